@@ -45,6 +45,7 @@ type chatModelAgentRunOptions struct {
 	// run
 	chatModelOptions []model.Option
 	toolOptions      []tool.Option
+	toolOptionsFor   map[ /*tool name*/ string][]tool.Option
 	agentToolOptions map[ /*tool name*/ string][]AgentRunOption // todo: map or list?
 
 	// resume
@@ -63,6 +64,16 @@ func WithToolOptions(opts []tool.Option) AgentRunOption {
 	})
 }
 
+// WithToolOptionsFor specifies tool.Option only for the tool of the name, in addition to the ones specified by WithToolOptions.
+func WithToolOptionsFor(toolName string, opts []tool.Option) AgentRunOption {
+	return WrapImplSpecificOptFn(func(t *chatModelAgentRunOptions) {
+		if t.toolOptionsFor == nil {
+			t.toolOptionsFor = make(map[string][]tool.Option)
+		}
+		t.toolOptionsFor[toolName] = append(t.toolOptionsFor[toolName], opts...)
+	})
+}
+
 func WithAgentToolRunOptions(opts map[string] /*tool name*/ []AgentRunOption) AgentRunOption {
 	return WrapImplSpecificOptFn(func(t *chatModelAgentRunOptions) {
 		t.agentToolOptions = opts
@@ -245,6 +256,11 @@ func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatMo
 			afterChatModels = append(afterChatModels, m.AfterChatModel)
 		}
 	}
+	if len(tc.Tools) > 0 {
+		if err := model.CheckToolCalling(config.Model); err != nil {
+			return nil, err
+		}
+	}
 
 	return &ChatModelAgent{
 		name:             config.Name,
@@ -854,8 +870,15 @@ func getComposeOptions(opts []AgentRunOption) []compose.Option {
 	for toolName, atos := range o.agentToolOptions {
 		to = append(to, withAgentToolOptions(toolName, atos))
 	}
+	var tno []compose.ToolsNodeOption
 	if len(to) > 0 {
-		co = append(co, compose.WithToolsNodeOption(compose.WithToolOption(to...)))
+		tno = append(tno, compose.WithToolOption(to...))
+	}
+	for toolName, tos := range o.toolOptionsFor {
+		tno = append(tno, compose.WithToolOptionFor(toolName, tos...))
+	}
+	if len(tno) > 0 {
+		co = append(co, compose.WithToolsNodeOption(tno...))
 	}
 	if o.historyModifier != nil {
 		co = append(co, compose.WithStateModifier(func(ctx context.Context, path compose.NodePath, state any) error {
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/safe"
 	"github.com/cloudwego/eino/schema"
 )
@@ -328,7 +329,7 @@ func (a *flowAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentR
 
 	subAgent := a.getAgent(ctx, nextAgentName)
 	if subAgent == nil {
-		return genErrorIter(fmt.Errorf("failed to resume agent: agent '%s' not found", nextAgentName))
+		return genErrorIter(fmt.Errorf("failed to resume agent: agent '%s' not found: %w", nextAgentName, einoerr.ErrNodeNotFound))
 	}
 
 	return subAgent.Resume(ctx, info, opts...)
@@ -396,8 +397,8 @@ func (a *flowAgent) run(
 	if destName != "" {
 		agentToRun := a.getAgent(ctx, destName)
 		if agentToRun == nil {
-			e := fmt.Errorf("transfer failed: agent '%s' not found when transferring from '%s'",
-				destName, a.Name(ctx))
+			e := fmt.Errorf("transfer failed: agent '%s' not found when transferring from '%s': %w",
+				destName, a.Name(ctx), einoerr.ErrNodeNotFound)
 			generator.Send(&AgentEvent{Err: e})
 			return
 		}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
 )
 
 // ClearToolResultConfig configures the tool result clearing middleware.
@@ -45,9 +46,14 @@ type ClearToolResultConfig struct {
 	ClearToolResultPlaceholder string
 
 	// TokenCounter is a custom function to estimate token count for a message.
-	// If nil, uses the default counter (character count / 4).
+	// If nil, uses Tokenizer, or the default counter (character count / 4) if Tokenizer is nil too.
 	TokenCounter func(msg *schema.Message) int
 
+	// Tokenizer counts the tokens of the content and tool call arguments of a message,
+	// e.g. tokenizer.Approximate, which also counts a CJK character as a token, or tokenizer.ForModel(model).
+	// Ignored if TokenCounter is set. The default counter is used for a message failing to be counted.
+	Tokenizer tokenizer.Tokenizer
+
 	// ExcludeTools is a list of tool names whose results should never be cleared.
 	ExcludeTools []string
 }
@@ -77,13 +83,17 @@ func NewClearToolResult(ctx context.Context, config *ClearToolResultConfig) (adk
 
 	// Set token estimator
 	counter := config.TokenCounter
-	if counter == nil {
+	if counter == nil && config.Tokenizer == nil {
 		counter = defaultTokenCounter
 	}
 
 	return adk.AgentMiddleware{
 		BeforeChatModel: func(ctx context.Context, state *adk.ChatModelAgentState) error {
-			return reduceByTokens(state, toolResultTokenThreshold, keepRecentTokens, placeholder, counter, config.ExcludeTools)
+			c := counter
+			if c == nil {
+				c = tokenizerCounter(ctx, config.Tokenizer)
+			}
+			return reduceByTokens(state, toolResultTokenThreshold, keepRecentTokens, placeholder, c, config.ExcludeTools)
 		},
 	}, nil
 }
@@ -102,6 +112,22 @@ func defaultTokenCounter(msg *schema.Message) int {
 	return (count + 3) / 4
 }
 
+// tokenizerCounter counts the tokens of a message by the tokenizer, falling back to defaultTokenCounter on failure.
+func tokenizerCounter(ctx context.Context, t tokenizer.Tokenizer) func(msg *schema.Message) int {
+	return func(msg *schema.Message) int {
+		text := msg.Content
+		for _, tc := range msg.ToolCalls {
+			text += tc.Function.Arguments
+		}
+
+		n, err := t.CountTokens(ctx, text)
+		if err != nil {
+			return defaultTokenCounter(msg)
+		}
+		return n
+	}
+}
+
 // reduceByTokens reduces context based on tool result token threshold and recent message protection.
 // It clears old tool results when:
 // 1. The total tokens of all tool results exceed toolResultTokenThreshold
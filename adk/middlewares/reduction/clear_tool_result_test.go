@@ -17,6 +17,8 @@
 package reduction
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -25,6 +27,7 @@ import (
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
 )
 
 func Test_reduceByTokens(t *testing.T) {
@@ -263,3 +266,17 @@ func Test_reduceByTokens(t *testing.T) {
 		})
 	}
 }
+
+func Test_tokenizerCounter(t *testing.T) {
+	ctx := context.Background()
+	msg := schema.AssistantMessage("你好世界", []schema.ToolCall{{Function: schema.FunctionCall{Arguments: "{}"}}})
+
+	// 14 bytes by the default counter, while each CJK character is a token by tokenizer.Approximate
+	assert.Equal(t, 4, defaultTokenCounter(msg))
+	assert.Equal(t, 5, tokenizerCounter(ctx, tokenizer.Approximate)(msg))
+
+	failing := tokenizer.Func(func(context.Context, string) (int, error) {
+		return 0, errors.New("unknown model")
+	})
+	assert.Equal(t, 4, tokenizerCounter(ctx, failing)(msg))
+}
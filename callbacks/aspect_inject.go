@@ -18,6 +18,7 @@ package callbacks
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/internal/callbacks"
@@ -103,6 +104,13 @@ func EnsureRunInfo(ctx context.Context, typ string, comp components.Component) c
 
 // ReuseHandlers initializes a new context with the provided RunInfo, while using the same handlers already exist.
 // Will initialize Global callback handlers if none exist in the ctx before.
+// It's also the recommended way for a component (e.g. a tool) that internally invokes other runnables
+// to make the nested runs appear under the parent trace, e.g.
+//
+//	func (t *myTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+//		// info can be nil if the nested runnable is a graph or a component which sets its own RunInfo
+//		return t.subGraph.Invoke(callbacks.ReuseHandlers(ctx, nil), args)
+//	}
 func ReuseHandlers(ctx context.Context, info *RunInfo) context.Context {
 	return callbacks.ReuseHandlers(ctx, info)
 }
@@ -112,3 +120,47 @@ func ReuseHandlers(ctx context.Context, info *RunInfo) context.Context {
 func InitCallbacks(ctx context.Context, info *RunInfo, handlers ...Handler) context.Context {
 	return callbacks.InitCallbacks(ctx, info, handlers...)
 }
+
+// PropagateContext returns a context which is canceled along with dst, but also carries the callback handlers,
+// RunInfo and any other values of src. Values of dst take precedence over those of src.
+// The internal values of the context package are not taken from src, so the cancellation of the returned context,
+// i.e. Done, Err and the cause, only follows dst, and src being canceled has no effect on it.
+// It's useful when starting a goroutine with a context that isn't derived from the one of the current run,
+// e.g. a detached background task that should outlive the run but still be reported under the same trace:
+//
+//	go func() {
+//		bgCtx := callbacks.PropagateContext(context.Background(), ctx)
+//		_, _ = runnable.Invoke(bgCtx, input)
+//	}()
+func PropagateContext(dst, src context.Context) context.Context {
+	return &propagatedCtx{Context: dst, src: src}
+}
+
+type propagatedCtx struct {
+	context.Context
+	src context.Context
+}
+
+func (p *propagatedCtx) Value(key any) any {
+	if v := p.Context.Value(key); v != nil {
+		return v
+	}
+	v := p.src.Value(key)
+	if isContextInternal(v) {
+		return nil
+	}
+	return v
+}
+
+// isContextInternal reports whether v is a value of the context package itself,
+// e.g. the cancelCtx looked up by context.Cause, which must come from dst rather than src.
+func isContextInternal(v any) bool {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() == "context"
+}
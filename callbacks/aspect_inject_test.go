@@ -283,6 +283,36 @@ func TestAppendHandlersTwiceOnSameCtx(t *testing.T) {
 	assert.Equal(t, 1, cb2.times)
 }
 
+func TestPropagateContext(t *testing.T) {
+	callbacks.GlobalHandlers = []Handler{}
+	cb := &myCallback{t: t}
+	type userKey struct{}
+	src := InitCallbacks(context.WithValue(context.Background(), userKey{}, "v"), &RunInfo{Name: "test"}, cb)
+	dst, cancel := context.WithCancel(context.Background())
+
+	ctx := PropagateContext(dst, src)
+	assert.Equal(t, "v", ctx.Value(userKey{}))
+	OnStart(ctx, 0)
+	assert.Equal(t, 1, cb.times)
+
+	cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	// the cancellation of src doesn't leak into the propagated context
+	src, cancelSrc := context.WithCancel(src)
+	cancelSrc()
+	ctx = PropagateContext(context.Background(), src)
+	assert.NoError(t, ctx.Err())
+	assert.Nil(t, ctx.Done())
+	assert.Equal(t, "v", ctx.Value(userKey{}))
+
+	// the values of the context package, e.g. the cancelCtx of src, are not propagated
+	type ctxKey struct{}
+	ctx = PropagateContext(context.Background(), context.WithValue(src, ctxKey{}, src))
+	assert.Nil(t, ctx.Value(ctxKey{}))
+}
+
 type myCallback struct {
 	t     *testing.T
 	times int
@@ -28,6 +28,7 @@ type HandlerBuilder struct {
 	onErrorFn                func(ctx context.Context, info *RunInfo, err error) context.Context
 	onStartWithStreamInputFn func(ctx context.Context, info *RunInfo, input *schema.StreamReader[CallbackInput]) context.Context
 	onEndWithStreamOutputFn  func(ctx context.Context, info *RunInfo, output *schema.StreamReader[CallbackOutput]) context.Context
+	onGraphEventFn           func(ctx context.Context, info *RunInfo, event *GraphEvent)
 }
 
 type handlerImpl struct {
@@ -58,6 +59,12 @@ func (hb *handlerImpl) OnEndWithStreamOutput(ctx context.Context, info *RunInfo,
 	return hb.onEndWithStreamOutputFn(ctx, info, output)
 }
 
+func (hb *handlerImpl) OnGraphEvent(ctx context.Context, info *RunInfo, event *GraphEvent) {
+	if hb.onGraphEventFn != nil {
+		hb.onGraphEventFn(ctx, info, event)
+	}
+}
+
 func (hb *handlerImpl) Needed(_ context.Context, _ *RunInfo, timing CallbackTiming) bool {
 	switch timing {
 	case TimingOnStart:
@@ -118,6 +125,14 @@ func (hb *HandlerBuilder) OnEndWithStreamOutputFn(
 	return hb
 }
 
+// OnGraphEventFn sets the callback function to be called on graph-level lifecycle events.
+func (hb *HandlerBuilder) OnGraphEventFn(
+	fn func(ctx context.Context, info *RunInfo, event *GraphEvent)) *HandlerBuilder {
+
+	hb.onGraphEventFn = fn
+	return hb
+}
+
 // Build returns a Handler with the functions set in the builder.
 func (hb *HandlerBuilder) Build() Handler {
 	return &handlerImpl{*hb}
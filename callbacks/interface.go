@@ -65,6 +65,37 @@ func AppendGlobalHandlers(handlers ...Handler) {
 	callbacks.GlobalHandlers = append(callbacks.GlobalHandlers, handlers...)
 }
 
+// GraphEventType enumerates the graph-level lifecycle events.
+type GraphEventType = callbacks.GraphEventType
+
+const (
+	// GraphEventRunStart is emitted when a graph run starts, after the graph's OnStart callback.
+	GraphEventRunStart GraphEventType = "RunStart"
+	// GraphEventRunEnd is emitted when a graph run finishes, with GraphEvent.Err set if the run failed or was interrupted.
+	GraphEventRunEnd GraphEventType = "RunEnd"
+	// GraphEventBranchEvaluated is emitted after a branch condition is evaluated,
+	// GraphEvent.NodeKey is the start node of the branch and GraphEvent.Targets are the chosen end nodes.
+	GraphEventBranchEvaluated GraphEventType = "BranchEvaluated"
+	// GraphEventStateMutated is emitted after a state handler or compose.ProcessState returns without error,
+	// as the local state may have been modified by it. GraphEvent.NodeKey is the node modifying the state, if any.
+	// It's emitted after the lock of the state is released, so the handler may read the state by compose.ProcessStateRead,
+	// while modifying the state by compose.ProcessState in the handler emits the event again.
+	GraphEventStateMutated GraphEventType = "StateMutated"
+	// GraphEventMaxStepsExceeded is emitted when the graph run is terminated because the max run steps is exceeded.
+	GraphEventMaxStepsExceeded GraphEventType = "MaxStepsExceeded"
+	// GraphEventCheckPointSaved is emitted after a checkpoint has been written to the checkpoint store.
+	GraphEventCheckPointSaved GraphEventType = "CheckPointSaved"
+)
+
+// GraphEvent describes a graph-level lifecycle event.
+// Only the fields related to the event type are set.
+type GraphEvent = callbacks.GraphEvent
+
+// GraphEventHandler receives graph-level lifecycle events, in addition to the per-node OnStart/OnEnd callbacks.
+// It's an optional interface for callback handlers, events will be delivered to any Handler that implements it.
+// Handlers created by HandlerBuilder implement it, see HandlerBuilder.OnGraphEventFn.
+type GraphEventHandler = callbacks.GraphEventHandler
+
 // CallbackTiming enumerates all the timing of callback aspects.
 type CallbackTiming = callbacks.CallbackTiming
 
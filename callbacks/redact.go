@@ -0,0 +1,184 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/internal/callbacks"
+)
+
+// Redactor rewrites the data of a callback before it's handed to the handlers, e.g. to mask the sensitive data in prompts
+// and tool arguments, so that logging and tracing handlers can be enabled safely.
+// The data is the callback input, output, stream chunk or error of the component, and must not be modified in place,
+// as it's the same value used by the component. RedactStrings helps to build a redacted copy.
+type Redactor = callbacks.Redactor
+
+// WithRedactor registers the redactor, which is applied to the data of all callbacks before it's handed to any handler.
+// Redactors are applied in the order they're registered. The data used by the components is never affected.
+// Note: This function is not thread-safe and should only be called during process initialization.
+// e.g.
+//
+//	callbacks.WithRedactor(callbacks.PIIRedactor())
+//	callbacks.AppendGlobalHandlers(loggingHandler)
+func WithRedactor(redactor Redactor) {
+	if redactor == nil {
+		return
+	}
+	callbacks.GlobalRedactors = append(callbacks.GlobalRedactors, redactor)
+}
+
+// DefaultRedactedText is the text replacing the sensitive data by the built-in redactors.
+const DefaultRedactedText = "[REDACTED]"
+
+var (
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// CreditCardPattern matches credit card numbers, optionally separated by spaces or dashes.
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// PhonePattern matches phone numbers in the common formats, e.g. +1 (555) 123-4567 or 13812345678.
+	PhonePattern = regexp.MustCompile(`(?:\+\d{1,3}[-. ]?)?(?:\(\d{3}\)|\b\d{3})[-. ]?\d{3,4}[-. ]?\d{4}\b`)
+	// IPv4Pattern matches IPv4 addresses.
+	IPv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// SecretPattern matches bearer tokens and API keys, e.g. "Bearer xxx" or "sk-xxx".
+	SecretPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*|\b(?:sk|pk|ak|api|key|token)[-_][A-Za-z0-9_-]{16,}`)
+
+	// PIIPatterns are the patterns used by PIIRedactor.
+	PIIPatterns = []*regexp.Regexp{EmailPattern, CreditCardPattern, PhonePattern, IPv4Pattern, SecretPattern}
+)
+
+// RegexRedactor returns a redactor replacing the matches of the patterns with the replacement,
+// in all the strings of the data, e.g. the contents of the messages and the arguments of the tool calls.
+func RegexRedactor(replacement string, patterns ...*regexp.Regexp) Redactor {
+	return func(_ components.Component, data any) any {
+		return RedactStrings(data, func(s string) string {
+			for _, p := range patterns {
+				s = p.ReplaceAllString(s, replacement)
+			}
+			return s
+		})
+	}
+}
+
+// PIIRedactor returns a redactor masking the emails, credit card numbers, phone numbers, IP addresses and secrets
+// with DefaultRedactedText. It's best effort, and can be combined with RegexRedactor for the business specific data.
+func PIIRedactor() Redactor {
+	return RegexRedactor(DefaultRedactedText, PIIPatterns...)
+}
+
+// maxRedactDepth limits the depth RedactStrings goes into the data, in case of circular references.
+const maxRedactDepth = 32
+
+// RedactStrings returns a copy of the data with all the strings reachable by exported fields, elements and pointers
+// rewritten by fn, leaving the data itself untouched.
+// An error is replaced by an error of the rewritten message, which still unwraps to the original one.
+func RedactStrings(data any, fn func(string) string) any {
+	if data == nil {
+		return nil
+	}
+	if err, ok := data.(error); ok {
+		return &redactedError{msg: fn(err.Error()), err: err}
+	}
+	return redactValue(reflect.ValueOf(data), fn, 0).Interface()
+}
+
+func redactValue(v reflect.Value, fn func(string) string, depth int) reflect.Value {
+	if depth > maxRedactDepth {
+		return v
+	}
+
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.String:
+		nv := reflect.New(t).Elem()
+		nv.SetString(fn(v.String()))
+		return nv
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		nv := reflect.New(t.Elem())
+		nv.Elem().Set(redactValue(v.Elem(), fn, depth+1))
+		return nv
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		nv := reflect.New(t).Elem()
+		if err, ok := v.Interface().(error); ok {
+			re := reflect.ValueOf(RedactStrings(err, fn))
+			if !re.Type().AssignableTo(t) {
+				return v
+			}
+			nv.Set(re)
+		} else {
+			nv.Set(redactValue(v.Elem(), fn, depth+1))
+		}
+		return nv
+	case reflect.Struct:
+		nv := reflect.New(t).Elem()
+		nv.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				nv.Field(i).Set(redactValue(v.Field(i), fn, depth+1))
+			}
+		}
+		return nv
+	case reflect.Slice:
+		if v.IsNil() || t.Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		nv := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			nv.Index(i).Set(redactValue(v.Index(i), fn, depth+1))
+		}
+		return nv
+	case reflect.Array:
+		nv := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			nv.Index(i).Set(redactValue(v.Index(i), fn, depth+1))
+		}
+		return nv
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		nv := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			nv.SetMapIndex(iter.Key(), redactValue(iter.Value(), fn, depth+1))
+		}
+		return nv
+	default:
+		return v
+	}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+type redactInput struct {
+	Messages []*schema.Message
+	Extra    map[string]any
+	Count    int
+}
+
+func TestRedactor(t *testing.T) {
+	callbacks.GlobalHandlers = []Handler{}
+	defer func() { callbacks.GlobalRedactors = nil }()
+
+	WithRedactor(PIIRedactor())
+	var comps []components.Component
+	WithRedactor(func(component components.Component, data any) any {
+		comps = append(comps, component)
+		return data
+	})
+
+	var (
+		gotInput  *redactInput
+		gotErr    error
+		gotChunks []string
+	)
+	handler := NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+			gotInput = input.(*redactInput)
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *RunInfo, err error) context.Context {
+			gotErr = err
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *RunInfo, output *schema.StreamReader[CallbackOutput]) context.Context {
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err == io.EOF {
+					return ctx
+				}
+				gotChunks = append(gotChunks, chunk.(string))
+			}
+		}).Build()
+
+	ctx := InitCallbacks(context.Background(), &RunInfo{Component: components.ComponentOfChatModel}, handler)
+
+	input := &redactInput{
+		Messages: []*schema.Message{schema.UserMessage("mail me at alice@example.com or call +1 (555) 123-4567")},
+		Extra:    map[string]any{"auth": "Bearer abc.def", "ip": "10.0.0.1"},
+		Count:    3,
+	}
+	ctx = OnStart(ctx, input)
+	assert.Equal(t, "mail me at [REDACTED] or call [REDACTED]", gotInput.Messages[0].Content)
+	assert.Equal(t, map[string]any{"auth": "[REDACTED]", "ip": "[REDACTED]"}, gotInput.Extra)
+	assert.Equal(t, 3, gotInput.Count)
+	// the data of the component is untouched
+	assert.Equal(t, "mail me at alice@example.com or call +1 (555) 123-4567", input.Messages[0].Content)
+	assert.Equal(t, "10.0.0.1", input.Extra["ip"])
+
+	origErr := errors.New("invalid api key sk-0123456789abcdefghij")
+	OnError(ctx, origErr)
+	assert.Equal(t, "invalid api key [REDACTED]", gotErr.Error())
+	assert.ErrorIs(t, gotErr, origErr)
+
+	_, sr := OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray([]string{"card 4111 1111 1111 1111", "done"}))
+	var chunks []string
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	assert.Equal(t, []string{"card 4111 1111 1111 1111", "done"}, chunks)
+	assert.Equal(t, []string{"card [REDACTED]", "done"}, gotChunks)
+	assert.Equal(t, components.ComponentOfChatModel, comps[0])
+}
+
+func TestRegexRedactor(t *testing.T) {
+	r := RegexRedactor("***", regexp.MustCompile(`order-\d+`))
+	assert.Equal(t, "see ***", r("", "see order-42"))
+	assert.Equal(t, []string{"***", "x"}, r("", []string{"order-1", "x"}))
+	assert.Nil(t, r("", nil))
+	assert.Equal(t, 1, r("", 1))
+}
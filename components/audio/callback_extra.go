@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audio
+
+import (
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config is the config of the speech component.
+type Config struct {
+	// Model is the model name.
+	Model string
+	// Language is the language of the speech.
+	Language string
+	// Voice is the voice of the synthesized speech.
+	Voice string
+	// MIMEType is the encoding of the audio.
+	MIMEType string
+	// SampleRate is the sample rate of the audio.
+	SampleRate int
+}
+
+// SpeechToTextCallbackInput is the chunk of the input stream for the SpeechToText callback.
+type SpeechToTextCallbackInput struct {
+	// Audio is the audio chunk to be transcribed.
+	Audio *schema.AudioChunk
+	// Config is the config of the component.
+	Config *Config
+	// Extra is the extra information for the callback.
+	Extra map[string]any
+}
+
+// SpeechToTextCallbackOutput is the chunk of the output stream for the SpeechToText callback.
+type SpeechToTextCallbackOutput struct {
+	// Text is the increment of the transcript.
+	Text string
+	// Config is the config of the component.
+	Config *Config
+	// Extra is the extra information for the callback.
+	Extra map[string]any
+}
+
+// TextToSpeechCallbackInput is the chunk of the input stream for the TextToSpeech callback.
+type TextToSpeechCallbackInput struct {
+	// Text is the text chunk to be synthesized.
+	Text string
+	// Config is the config of the component.
+	Config *Config
+	// Extra is the extra information for the callback.
+	Extra map[string]any
+}
+
+// TextToSpeechCallbackOutput is the chunk of the output stream for the TextToSpeech callback.
+type TextToSpeechCallbackOutput struct {
+	// Audio is the synthesized audio chunk.
+	Audio *schema.AudioChunk
+	// Config is the config of the component.
+	Config *Config
+	// Extra is the extra information for the callback.
+	Extra map[string]any
+}
+
+// ConvSpeechToTextCallbackInput converts the callback input chunk to the SpeechToText callback input.
+func ConvSpeechToTextCallbackInput(src callbacks.CallbackInput) *SpeechToTextCallbackInput {
+	switch t := src.(type) {
+	case *SpeechToTextCallbackInput:
+		return t
+	case *schema.AudioChunk:
+		return &SpeechToTextCallbackInput{Audio: t}
+	default:
+		return nil
+	}
+}
+
+// ConvSpeechToTextCallbackOutput converts the callback output chunk to the SpeechToText callback output.
+func ConvSpeechToTextCallbackOutput(src callbacks.CallbackOutput) *SpeechToTextCallbackOutput {
+	switch t := src.(type) {
+	case *SpeechToTextCallbackOutput:
+		return t
+	case string:
+		return &SpeechToTextCallbackOutput{Text: t}
+	default:
+		return nil
+	}
+}
+
+// ConvTextToSpeechCallbackInput converts the callback input chunk to the TextToSpeech callback input.
+func ConvTextToSpeechCallbackInput(src callbacks.CallbackInput) *TextToSpeechCallbackInput {
+	switch t := src.(type) {
+	case *TextToSpeechCallbackInput:
+		return t
+	case string:
+		return &TextToSpeechCallbackInput{Text: t}
+	default:
+		return nil
+	}
+}
+
+// ConvTextToSpeechCallbackOutput converts the callback output chunk to the TextToSpeech callback output.
+func ConvTextToSpeechCallbackOutput(src callbacks.CallbackOutput) *TextToSpeechCallbackOutput {
+	switch t := src.(type) {
+	case *TextToSpeechCallbackOutput:
+		return t
+	case *schema.AudioChunk:
+		return &TextToSpeechCallbackOutput{Audio: t}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestConvCallback(t *testing.T) {
+	chunk := &schema.AudioChunk{Data: []byte{1}}
+
+	assert.NotNil(t, ConvSpeechToTextCallbackInput(&SpeechToTextCallbackInput{}))
+	assert.Equal(t, chunk, ConvSpeechToTextCallbackInput(chunk).Audio)
+	assert.Nil(t, ConvSpeechToTextCallbackInput("asd"))
+
+	assert.NotNil(t, ConvSpeechToTextCallbackOutput(&SpeechToTextCallbackOutput{}))
+	assert.Equal(t, "asd", ConvSpeechToTextCallbackOutput("asd").Text)
+	assert.Nil(t, ConvSpeechToTextCallbackOutput(chunk))
+
+	assert.NotNil(t, ConvTextToSpeechCallbackInput(&TextToSpeechCallbackInput{}))
+	assert.Equal(t, "asd", ConvTextToSpeechCallbackInput("asd").Text)
+	assert.Nil(t, ConvTextToSpeechCallbackInput(chunk))
+
+	assert.NotNil(t, ConvTextToSpeechCallbackOutput(&TextToSpeechCallbackOutput{}))
+	assert.Equal(t, chunk, ConvTextToSpeechCallbackOutput(chunk).Audio)
+	assert.Nil(t, ConvTextToSpeechCallbackOutput("asd"))
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audio defines the speech components, SpeechToText and TextToSpeech, to compose voice agents,
+// e.g. a graph transcribing the speech of the user, answering it with a ChatModel and speaking the answer.
+//
+// Both components are streaming in and out, so they are added to the graph as transformable nodes,
+// see compose.Graph.AddSpeechToTextNode and compose.Graph.AddTextToSpeechNode.
+// The implementations without their own callback aspect, see components.Checker, are reported by the graph via
+// callbacks.OnStartWithStreamInput and callbacks.OnEndWithStreamOutput with the raw chunks,
+// which ConvSpeechToTextCallbackInput, ConvSpeechToTextCallbackOutput, ConvTextToSpeechCallbackInput and
+// ConvTextToSpeechCallbackOutput convert to the callback input and output of this package.
+// The implementations with their own callback aspect should report the same way, with chunks of those types.
+package audio
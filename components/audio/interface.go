@@ -0,0 +1,35 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audio
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// SpeechToText transcribes the audio stream into the text stream, e.g. a streaming speech recognition service.
+// The output chunks are the increments of the transcript, which concatenate into the full transcript.
+type SpeechToText interface {
+	Transcribe(ctx context.Context, audio *schema.StreamReader[*schema.AudioChunk], opts ...Option) (*schema.StreamReader[string], error)
+}
+
+// TextToSpeech synthesizes the audio stream from the text stream, e.g. a streaming speech synthesis service.
+// The implementation may start synthesizing before the text stream ends, e.g. sentence by sentence.
+type TextToSpeech interface {
+	Synthesize(ctx context.Context, text *schema.StreamReader[string], opts ...Option) (*schema.StreamReader[*schema.AudioChunk], error)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audio
+
+// Options is the common options for the speech components.
+type Options struct {
+	// Model is the model name of the speech service.
+	Model *string
+	// Language is the language of the speech, e.g. "en-US", which is detected by the service if not set.
+	Language *string
+	// Voice is the voice to synthesize the speech with, only for TextToSpeech.
+	Voice *string
+	// MIMEType is the encoding of the synthesized audio, e.g. "audio/pcm", only for TextToSpeech.
+	MIMEType *string
+	// SampleRate is the sample rate of the synthesized audio in Hz, only for TextToSpeech.
+	SampleRate *int
+}
+
+// Option is the call option for the speech components.
+type Option struct {
+	apply func(opts *Options)
+
+	implSpecificOptFn any
+}
+
+// WithModel is the option to set the model name of the speech service.
+func WithModel(model string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Model = &model
+		},
+	}
+}
+
+// WithLanguage is the option to set the language of the speech.
+func WithLanguage(language string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Language = &language
+		},
+	}
+}
+
+// WithVoice is the option to set the voice to synthesize the speech with.
+func WithVoice(voice string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Voice = &voice
+		},
+	}
+}
+
+// WithMIMEType is the option to set the encoding of the synthesized audio.
+func WithMIMEType(mimeType string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.MIMEType = &mimeType
+		},
+	}
+}
+
+// WithSampleRate is the option to set the sample rate of the synthesized audio.
+func WithSampleRate(sampleRate int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.SampleRate = &sampleRate
+		},
+	}
+}
+
+// GetCommonOptions extract audio Options from Option list, optionally providing a base Options with default values.
+// e.g.
+//
+//	defaultVoice := "alloy"
+//	options := audio.GetCommonOptions(&audio.Options{Voice: &defaultVoice}, opts...)
+func GetCommonOptions(base *Options, opts ...Option) *Options {
+	if base == nil {
+		base = &Options{}
+	}
+
+	for i := range opts {
+		opt := opts[i]
+		if opt.apply != nil {
+			opt.apply(base)
+		}
+	}
+
+	return base
+}
+
+// WrapImplSpecificOptFn is the option to wrap the implementation specific option function.
+func WrapImplSpecificOptFn[T any](optFn func(*T)) Option {
+	return Option{
+		implSpecificOptFn: optFn,
+	}
+}
+
+// GetImplSpecificOptions extract the implementation specific options from Option list, optionally providing a base options with default values.
+// e.g.
+//
+//	myOption := &MyOption{
+//		Field1: "default_value",
+//	}
+//
+//	myOption := audio.GetImplSpecificOptions(myOption, opts...)
+func GetImplSpecificOptions[T any](base *T, opts ...Option) *T {
+	if base == nil {
+		base = new(T)
+	}
+
+	for i := range opts {
+		opt := opts[i]
+		if opt.implSpecificOptFn != nil {
+			optFn, ok := opt.implSpecificOptFn.(func(*T))
+			if ok {
+				optFn(base)
+			}
+		}
+	}
+
+	return base
+}
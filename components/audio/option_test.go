@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type implOptionForTest struct {
+	speed float64
+}
+
+func TestOptions(t *testing.T) {
+	defaultVoice := "default_voice"
+	opts := []Option{
+		WithModel("tts-1"),
+		WithLanguage("en-US"),
+		WithVoice("alloy"),
+		WithMIMEType("audio/pcm"),
+		WithSampleRate(16000),
+		WrapImplSpecificOptFn(func(o *implOptionForTest) { o.speed = 1.5 }),
+	}
+
+	o := GetCommonOptions(&Options{Voice: &defaultVoice}, opts...)
+	assert.Equal(t, "tts-1", *o.Model)
+	assert.Equal(t, "en-US", *o.Language)
+	assert.Equal(t, "alloy", *o.Voice)
+	assert.Equal(t, "audio/pcm", *o.MIMEType)
+	assert.Equal(t, 16000, *o.SampleRate)
+
+	assert.Nil(t, GetCommonOptions(nil).Voice)
+	assert.Equal(t, 1.5, GetImplSpecificOptions(&implOptionForTest{speed: 1}, opts...).speed)
+}
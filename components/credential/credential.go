@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package credential resolves the credentials of the components per run, e.g. the API keys of the models and tools
+// chosen per tenant or request, instead of the ones baked into the component constructors.
+// The Provider is carried by the context, set by WithProvider, or by compose.WithCredentialProvider for a graph run,
+// and the component implementations resolve the credential by Resolve, or model.ResolveCredential etc. when the call options are considered.
+package credential
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// Credential is the credential used by a component to call the service.
+type Credential struct {
+	// APIKey is the API key, the most common credential.
+	APIKey string
+	// Secrets are the other secrets keyed by name, e.g. "access_key", "secret_key" or "token".
+	Secrets map[string]string
+}
+
+// Request describes the component asking for the credential.
+type Request struct {
+	// Component is the kind of the component, e.g. components.ComponentOfChatModel.
+	Component components.Component
+	// Name identifies the component within its kind, e.g. the implementation type "OpenAI" of a model, or the tool name.
+	Name string
+}
+
+// Provider resolves the credentials, e.g. looking up the secrets of the tenant carried by the context in a vault.
+// It returns nil without error if it has no credential for the request, in which case the component uses its own.
+type Provider interface {
+	Resolve(ctx context.Context, req *Request) (*Credential, error)
+}
+
+// ProviderFunc is an adapter to use a function as Provider.
+type ProviderFunc func(ctx context.Context, req *Request) (*Credential, error)
+
+// Resolve calls f(ctx, req).
+func (f ProviderFunc) Resolve(ctx context.Context, req *Request) (*Credential, error) {
+	return f(ctx, req)
+}
+
+// NewStaticProvider creates a Provider of the fixed credentials, keyed by Request.Name,
+// or by Request.Component for the components without a credential of their name.
+func NewStaticProvider(creds map[string]*Credential) Provider {
+	return ProviderFunc(func(_ context.Context, req *Request) (*Credential, error) {
+		if c, ok := creds[req.Name]; ok {
+			return c, nil
+		}
+		return creds[string(req.Component)], nil
+	})
+}
+
+type providerKey struct{}
+
+// WithProvider returns a context carrying the provider, which overrides the one carried by ctx.
+func WithProvider(ctx context.Context, p Provider) context.Context {
+	return context.WithValue(ctx, providerKey{}, p)
+}
+
+// GetProvider returns the provider carried by the context.
+func GetProvider(ctx context.Context) (Provider, bool) {
+	p, ok := ctx.Value(providerKey{}).(Provider)
+	return p, ok && p != nil
+}
+
+// Resolve resolves the credential of the component by the provider carried by the context.
+// It returns nil without error if there is no provider, or the provider has no credential for the component.
+func Resolve(ctx context.Context, req *Request) (*Credential, error) {
+	p, ok := GetProvider(ctx)
+	if !ok {
+		return nil, nil
+	}
+	c, err := p.Resolve(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve credential of %s[%s] fail: %w", req.Component, req.Name, err)
+	}
+	return c, nil
+}
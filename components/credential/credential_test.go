@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credential
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components"
+)
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := Resolve(ctx, &Request{Component: components.ComponentOfChatModel, Name: "OpenAI"})
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	p := NewStaticProvider(map[string]*Credential{
+		"OpenAI":                           {APIKey: "sk-openai"},
+		string(components.ComponentOfTool): {APIKey: "sk-tool"},
+	})
+	ctx = WithProvider(ctx, p)
+
+	c, err = Resolve(ctx, &Request{Component: components.ComponentOfChatModel, Name: "OpenAI"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-openai", c.APIKey)
+
+	c, err = Resolve(ctx, &Request{Component: components.ComponentOfTool, Name: "search"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-tool", c.APIKey)
+
+	c, err = Resolve(ctx, &Request{Component: components.ComponentOfEmbedding, Name: "Ark"})
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	ctx = WithProvider(ctx, ProviderFunc(func(ctx context.Context, req *Request) (*Credential, error) {
+		return nil, errors.New("vault unavailable")
+	}))
+	_, err = Resolve(ctx, &Request{Component: components.ComponentOfChatModel, Name: "OpenAI"})
+	assert.EqualError(t, err, "resolve credential of ChatModel[OpenAI] fail: vault unavailable")
+}
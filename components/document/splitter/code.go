@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splitter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Language is the programming language of the code split by CodeSplitter.
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageJava       Language = "java"
+	LanguageJavaScript Language = "javascript"
+	LanguageTypeScript Language = "typescript"
+	LanguageMarkdown   Language = "markdown"
+)
+
+// languageSeparators splits the code by top level definitions first, then by blocks, lines, words and runes.
+var languageSeparators = map[Language][]string{
+	LanguageGo: {"\nfunc ", "\ntype ", "\nvar ", "\nconst ",
+		"\n\tif ", "\n\tfor ", "\n\tswitch ", "\n\tselect ", "\n\treturn ",
+		"\n\n", "\n", " ", ""},
+	LanguagePython: {"\nclass ", "\ndef ", "\n\tdef ", "\n    def ",
+		"\n\n", "\n", " ", ""},
+	LanguageJava: {"\nclass ", "\npublic ", "\nprotected ", "\nprivate ", "\nstatic ",
+		"\n\tif ", "\n\tfor ", "\n\twhile ", "\n\tswitch ", "\n\treturn ",
+		"\n\n", "\n", " ", ""},
+	LanguageJavaScript: {"\nfunction ", "\nclass ", "\nconst ", "\nlet ", "\nvar ", "\nexport ",
+		"\n\tif ", "\n\tfor ", "\n\twhile ", "\n\tswitch ", "\n\treturn ",
+		"\n\n", "\n", " ", ""},
+	LanguageTypeScript: {"\nenum ", "\ninterface ", "\ntype ", "\nfunction ", "\nclass ", "\nconst ", "\nlet ", "\nvar ", "\nexport ",
+		"\n\tif ", "\n\tfor ", "\n\twhile ", "\n\tswitch ", "\n\treturn ",
+		"\n\n", "\n", " ", ""},
+	LanguageMarkdown: {"\n# ", "\n## ", "\n### ", "\n#### ", "\n##### ", "\n###### ",
+		"\n```", "\n---", "\n\n", "\n", " ", ""},
+}
+
+// CodeConfig is the config of CodeSplitter.
+type CodeConfig struct {
+	// Language is the language of the code, required.
+	Language Language
+	// ChunkSize is the max length of a chunk, measured by LengthFunc, required.
+	ChunkSize int
+	// ChunkOverlap is the max length of the tail of a chunk repeated at the head of the next one, optional.
+	ChunkOverlap int
+	// LengthFunc measures the length of the text. Optional. By default, the number of runes.
+	LengthFunc func(text string) int
+}
+
+// CodeSplitter splits source code recursively by the syntax of the language,
+// keeping definitions, blocks and lines together as long as possible.
+// eg:
+//
+//	s, err := splitter.NewCodeSplitter(ctx, &splitter.CodeConfig{Language: splitter.LanguageGo, ChunkSize: 1000})
+//	chunks, err := s.Transform(ctx, docs)
+type CodeSplitter struct {
+	recursive *RecursiveSplitter
+}
+
+// NewCodeSplitter creates a CodeSplitter.
+func NewCodeSplitter(ctx context.Context, config *CodeConfig) (*CodeSplitter, error) {
+	if config == nil {
+		return nil, errNilConfig
+	}
+
+	separators, ok := languageSeparators[config.Language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported code splitter language: %s", config.Language)
+	}
+
+	r, err := NewRecursiveSplitter(ctx, &RecursiveConfig{
+		ChunkSize:    config.ChunkSize,
+		ChunkOverlap: config.ChunkOverlap,
+		Separators:   separators,
+		LengthFunc:   config.LengthFunc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CodeSplitter{recursive: r}, nil
+}
+
+// Transform splits the documents into chunks.
+func (c *CodeSplitter) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	return c.recursive.Transform(ctx, src, opts...)
+}
+
+// GetType returns the type of the splitter (CodeSplitter).
+func (c *CodeSplitter) GetType() string {
+	return "CodeSplitter"
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splitter
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultSeparators are the default separators of RecursiveSplitter, splitting by paragraphs, lines, words and runes in turn.
+var DefaultSeparators = []string{"\n\n", "\n", " ", ""}
+
+// RecursiveConfig is the config of RecursiveSplitter.
+type RecursiveConfig struct {
+	// ChunkSize is the max length of a chunk, measured by LengthFunc, required.
+	ChunkSize int
+	// ChunkOverlap is the max length of the tail of a chunk repeated at the head of the next one, optional.
+	ChunkOverlap int
+	// Separators are tried in order, the text is split by the first separator found in it,
+	// and the pieces still larger than ChunkSize are split by the rest separators.
+	// An empty separator splits the text into runes. Optional. Default is DefaultSeparators.
+	Separators []string
+	// LengthFunc measures the length of the text. Optional. By default, the number of runes.
+	LengthFunc func(text string) int
+}
+
+// RecursiveSplitter splits the documents recursively by a list of separators,
+// keeping paragraphs, lines and words together as long as possible.
+// eg:
+//
+//	s, err := splitter.NewRecursiveSplitter(ctx, &splitter.RecursiveConfig{ChunkSize: 500, ChunkOverlap: 50})
+//	chunks, err := s.Transform(ctx, docs)
+type RecursiveSplitter struct {
+	splitter   *textSplitter
+	separators []string
+}
+
+// NewRecursiveSplitter creates a RecursiveSplitter.
+func NewRecursiveSplitter(ctx context.Context, config *RecursiveConfig) (*RecursiveSplitter, error) {
+	if config == nil {
+		return nil, errNilConfig
+	}
+
+	s, err := newTextSplitter(config.ChunkSize, config.ChunkOverlap, config.LengthFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	separators := config.Separators
+	if len(separators) == 0 {
+		separators = DefaultSeparators
+	}
+
+	return &RecursiveSplitter{splitter: s, separators: separators}, nil
+}
+
+// Transform splits the documents into chunks.
+func (r *RecursiveSplitter) Transform(ctx context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	return transform(ctx, src, r.split)
+}
+
+func (r *RecursiveSplitter) split(_ context.Context, text string) ([]span, error) {
+	if len(text) == 0 {
+		return nil, nil
+	}
+	return trim(text, r.splitter.recursive(text, span{0, len(text)}, r.separators)), nil
+}
+
+// GetType returns the type of the splitter (RecursiveSplitter).
+func (r *RecursiveSplitter) GetType() string {
+	return "RecursiveSplitter"
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splitter
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// defaultSentenceTerminators are the runes ending a sentence, in both western and CJK punctuation.
+const defaultSentenceTerminators = ".!?。！？；;\n"
+
+// SentenceConfig is the config of SentenceSplitter.
+type SentenceConfig struct {
+	// ChunkSize is the max length of a chunk, measured by LengthFunc, required.
+	ChunkSize int
+	// ChunkOverlap is the max length of the tail sentences of a chunk repeated at the head of the next one, optional.
+	ChunkOverlap int
+	// Terminators are the runes ending a sentence, when followed by a white space or the end of the text.
+	// Optional. Default is ".!?。！？；;\n". CJK punctuation ends a sentence regardless of what follows.
+	Terminators string
+	// LengthFunc measures the length of the text. Optional. By default, the number of runes.
+	LengthFunc func(text string) int
+}
+
+// SentenceSplitter splits the documents into sentences, and merges the sentences into chunks,
+// so that a sentence is never broken unless it's larger than ChunkSize, in which case it's split by words.
+// eg:
+//
+//	s, err := splitter.NewSentenceSplitter(ctx, &splitter.SentenceConfig{ChunkSize: 300})
+//	chunks, err := s.Transform(ctx, docs)
+type SentenceSplitter struct {
+	splitter    *textSplitter
+	terminators string
+}
+
+// NewSentenceSplitter creates a SentenceSplitter.
+func NewSentenceSplitter(ctx context.Context, config *SentenceConfig) (*SentenceSplitter, error) {
+	if config == nil {
+		return nil, errNilConfig
+	}
+
+	s, err := newTextSplitter(config.ChunkSize, config.ChunkOverlap, config.LengthFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	terminators := config.Terminators
+	if len(terminators) == 0 {
+		terminators = defaultSentenceTerminators
+	}
+
+	return &SentenceSplitter{splitter: s, terminators: terminators}, nil
+}
+
+// Transform splits the documents into chunks.
+func (s *SentenceSplitter) Transform(ctx context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	return transform(ctx, src, s.split)
+}
+
+func (s *SentenceSplitter) split(_ context.Context, text string) ([]span, error) {
+	if len(text) == 0 {
+		return nil, nil
+	}
+
+	var pieces []span
+	for _, sentence := range s.sentences(text) {
+		if s.splitter.length(text[sentence.start:sentence.end]) <= s.splitter.chunkSize {
+			pieces = append(pieces, sentence)
+			continue
+		}
+		// the sentence is too long, split it by words
+		pieces = append(pieces, s.splitter.recursive(text, sentence, []string{" ", ""})...)
+	}
+
+	return trim(text, s.splitter.merge(text, pieces)), nil
+}
+
+// sentences cuts the text into contiguous sentences, each with its trailing white spaces.
+func (s *SentenceSplitter) sentences(text string) []span {
+	var (
+		ret   []span
+		start int
+	)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !strings.ContainsRune(s.terminators, r) {
+			continue
+		}
+
+		next, _ := utf8.DecodeRuneInString(text[i:])
+		if i < len(text) && r < utf8.RuneSelf && !unicode.IsSpace(r) && !unicode.IsSpace(next) {
+			// e.g. 3.14, e.g.
+			continue
+		}
+		for i < len(text) {
+			next, size = utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			i += size
+		}
+		ret = append(ret, span{start, i})
+		start = i
+	}
+	if start < len(text) {
+		ret = append(ret, span{start, len(text)})
+	}
+	return ret
+}
+
+// GetType returns the type of the splitter (SentenceSplitter).
+func (s *SentenceSplitter) GetType() string {
+	return "SentenceSplitter"
+}
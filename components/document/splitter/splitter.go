@@ -0,0 +1,232 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package splitter provides built-in document.Transformer implementations splitting documents into chunks:
+// RecursiveSplitter, TokenSplitter, SentenceSplitter and CodeSplitter.
+// Each chunk copies the metadata of its parent document, and records its position by the MetaKey* keys.
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Metadata keys of the chunks.
+const (
+	// MetaKeyParentID is the ID of the document the chunk is split from.
+	MetaKeyParentID = "_parent_id"
+	// MetaKeyChunkIndex is the index of the chunk in its parent document, starting from 0.
+	MetaKeyChunkIndex = "_chunk_index"
+	// MetaKeyStartOffset and MetaKeyEndOffset are the byte offsets of the chunk in the content of its parent document,
+	// i.e. chunk.Content == parent.Content[start:end].
+	MetaKeyStartOffset = "_start_offset"
+	MetaKeyEndOffset   = "_end_offset"
+)
+
+// span is the byte range [start, end) of a piece of text.
+type span struct {
+	start, end int
+}
+
+// splitFunc splits the text into chunks, returned by their spans in ascending order.
+type splitFunc func(ctx context.Context, text string) ([]span, error)
+
+// transform splits each document by split, the ID of a chunk is "{parent ID}_{index}" if the parent has an ID.
+func transform(ctx context.Context, src []*schema.Document, split splitFunc) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range src {
+		if doc == nil {
+			continue
+		}
+
+		spans, err := split(ctx, doc.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, sp := range spans {
+			meta := make(map[string]any, len(doc.MetaData)+4)
+			for k, v := range doc.MetaData {
+				meta[k] = v
+			}
+			meta[MetaKeyParentID] = doc.ID
+			meta[MetaKeyChunkIndex] = i
+			meta[MetaKeyStartOffset] = sp.start
+			meta[MetaKeyEndOffset] = sp.end
+
+			chunk := &schema.Document{
+				Content:  doc.Content[sp.start:sp.end],
+				MetaData: meta,
+			}
+			if len(doc.ID) > 0 {
+				chunk.ID = fmt.Sprintf("%s_%d", doc.ID, i)
+			}
+			ret = append(ret, chunk)
+		}
+	}
+
+	return ret, nil
+}
+
+// textSplitter merges the pieces of text into chunks of at most chunkSize, measured by length,
+// with the tail of a chunk of at most chunkOverlap repeated at the head of the next one.
+type textSplitter struct {
+	chunkSize    int
+	chunkOverlap int
+	length       func(text string) int
+}
+
+func newTextSplitter(chunkSize, chunkOverlap int, length func(text string) int) (*textSplitter, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	if chunkOverlap < 0 || chunkOverlap >= chunkSize {
+		return nil, fmt.Errorf("chunk overlap must be in [0, chunk size), got %d", chunkOverlap)
+	}
+	if length == nil {
+		length = utf8.RuneCountInString
+	}
+	return &textSplitter{chunkSize: chunkSize, chunkOverlap: chunkOverlap, length: length}, nil
+}
+
+// merge merges the contiguous pieces into chunks. A piece larger than chunkSize becomes a chunk on its own.
+func (s *textSplitter) merge(text string, pieces []span) []span {
+	var (
+		ret    []span
+		window []span
+	)
+	size := func(w []span) int {
+		if len(w) == 0 {
+			return 0
+		}
+		return s.length(text[w[0].start:w[len(w)-1].end])
+	}
+
+	for _, p := range pieces {
+		if len(window) > 0 && s.length(text[window[0].start:p.end]) > s.chunkSize {
+			ret = append(ret, span{window[0].start, window[len(window)-1].end})
+			// keep the tail within chunkOverlap, and leave room for the next piece
+			for len(window) > 0 && (size(window) > s.chunkOverlap ||
+				s.length(text[window[0].start:p.end]) > s.chunkSize) {
+				window = window[1:]
+			}
+		}
+		window = append(window, p)
+	}
+	if len(window) > 0 {
+		ret = append(ret, span{window[0].start, window[len(window)-1].end})
+	}
+
+	return ret
+}
+
+// recursive splits the text[start:end] by the first separator found in it, merges the pieces,
+// and splits the pieces still larger than chunkSize by the rest separators.
+// An empty separator splits the text into runes.
+func (s *textSplitter) recursive(text string, sp span, separators []string) []span {
+	if s.length(text[sp.start:sp.end]) <= s.chunkSize {
+		return []span{sp}
+	}
+
+	idx := len(separators)
+	for i, sep := range separators {
+		if len(sep) == 0 || strings.Contains(text[sp.start:sp.end], sep) {
+			idx = i
+			break
+		}
+	}
+	if idx == len(separators) {
+		return []span{sp}
+	}
+	rest := separators[idx+1:]
+
+	var (
+		ret  []span
+		good []span
+	)
+	for _, p := range cut(text, sp, separators[idx]) {
+		if s.length(text[p.start:p.end]) <= s.chunkSize {
+			good = append(good, p)
+			continue
+		}
+		if len(good) > 0 {
+			ret = append(ret, s.merge(text, good)...)
+			good = nil
+		}
+		ret = append(ret, s.recursive(text, p, rest)...)
+	}
+	if len(good) > 0 {
+		ret = append(ret, s.merge(text, good)...)
+	}
+
+	return ret
+}
+
+// cut cuts the text[start:end] into contiguous pieces before each occurrence of sep,
+// i.e. the separator is kept at the head of the following piece.
+func cut(text string, sp span, sep string) []span {
+	var ret []span
+	if len(sep) == 0 {
+		for i := sp.start; i < sp.end; {
+			_, size := utf8.DecodeRuneInString(text[i:sp.end])
+			ret = append(ret, span{i, i + size})
+			i += size
+		}
+		return ret
+	}
+
+	start := sp.start
+	for start+1 < sp.end {
+		i := strings.Index(text[start+1:sp.end], sep)
+		if i < 0 {
+			break
+		}
+		ret = append(ret, span{start, start + 1 + i})
+		start = start + 1 + i
+	}
+	return append(ret, span{start, sp.end})
+}
+
+// trim trims the white spaces of the chunks, and removes the empty ones.
+func trim(text string, spans []span) []span {
+	ret := make([]span, 0, len(spans))
+	for _, sp := range spans {
+		chunk := text[sp.start:sp.end]
+		left := len(chunk) - len(strings.TrimLeftFunc(chunk, unicode.IsSpace))
+		right := len(strings.TrimRightFunc(chunk, unicode.IsSpace))
+		if left >= right {
+			continue
+		}
+		ret = append(ret, span{sp.start + left, sp.start + right})
+	}
+	return ret
+}
+
+var errNilConfig = errors.New("splitter config is nil")
+
+var (
+	_ document.Transformer = (*RecursiveSplitter)(nil)
+	_ document.Transformer = (*TokenSplitter)(nil)
+	_ document.Transformer = (*SentenceSplitter)(nil)
+	_ document.Transformer = (*CodeSplitter)(nil)
+)
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func contents(docs []*schema.Document) []string {
+	ret := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ret = append(ret, doc.Content)
+	}
+	return ret
+}
+
+func checkChunks(t *testing.T, parent *schema.Document, chunks []*schema.Document) {
+	for i, chunk := range chunks {
+		start := chunk.MetaData[MetaKeyStartOffset].(int)
+		end := chunk.MetaData[MetaKeyEndOffset].(int)
+		assert.Equal(t, parent.Content[start:end], chunk.Content)
+		assert.Equal(t, i, chunk.MetaData[MetaKeyChunkIndex])
+		assert.Equal(t, parent.ID, chunk.MetaData[MetaKeyParentID])
+		for k, v := range parent.MetaData {
+			assert.Equal(t, v, chunk.MetaData[k])
+		}
+	}
+}
+
+func TestRecursiveSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRecursiveSplitter(ctx, nil)
+		assert.Error(t, err)
+		_, err = NewRecursiveSplitter(ctx, &RecursiveConfig{})
+		assert.Error(t, err)
+		_, err = NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 10, ChunkOverlap: 10})
+		assert.Error(t, err)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 20})
+		assert.NoError(t, err)
+
+		doc := &schema.Document{
+			ID:       "doc",
+			Content:  "first paragraph.\n\nsecond paragraph is longer than the chunk size.\n\nthird",
+			MetaData: map[string]any{"source": "test"},
+		}
+		chunks, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		checkChunks(t, doc, chunks)
+		assert.Equal(t, []string{"first paragraph.", "second paragraph is", "longer than the", "chunk size.", "third"}, contents(chunks))
+		assert.Equal(t, "doc_1", chunks[1].ID)
+		for _, c := range chunks {
+			assert.LessOrEqual(t, len(c.Content), 20)
+		}
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 11, ChunkOverlap: 5})
+		assert.NoError(t, err)
+
+		doc := &schema.Document{Content: "aaa bbb ccc ddd eee"}
+		chunks, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		checkChunks(t, doc, chunks)
+		assert.Equal(t, []string{"aaa bbb ccc", "ccc ddd", "ddd eee"}, contents(chunks))
+		assert.Empty(t, chunks[0].ID)
+	})
+
+	t.Run("runes", func(t *testing.T) {
+		s, err := NewRecursiveSplitter(ctx, &RecursiveConfig{ChunkSize: 4})
+		assert.NoError(t, err)
+
+		doc := &schema.Document{Content: "你好世界你好"}
+		chunks, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		checkChunks(t, doc, chunks)
+		assert.Equal(t, []string{"你好世界", "你好"}, contents(chunks))
+	})
+}
+
+func TestTokenSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := NewTokenSplitter(ctx, &TokenConfig{ChunkSize: 3, ChunkOverlap: 1})
+	assert.NoError(t, err)
+
+	doc := &schema.Document{ID: "doc", Content: "a b  c d\ne f g"}
+	chunks, err := s.Transform(ctx, []*schema.Document{doc})
+	assert.NoError(t, err)
+	checkChunks(t, doc, chunks)
+	assert.Equal(t, []string{"a b  c", "c d\ne", "e f g"}, contents(chunks))
+
+	s, err = NewTokenSplitter(ctx, &TokenConfig{
+		ChunkSize: 2,
+		Tokenizer: TokenizerFunc(func(ctx context.Context, text string) ([]Token, error) {
+			// one token per byte
+			ret := make([]Token, 0, len(text))
+			for i := range text {
+				ret = append(ret, Token{Start: i, End: i + 1})
+			}
+			return ret, nil
+		}),
+	})
+	assert.NoError(t, err)
+	chunks, err = s.Transform(ctx, []*schema.Document{{Content: "abcde"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ab", "cd", "e"}, contents(chunks))
+}
+
+func TestSentenceSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := NewSentenceSplitter(ctx, &SentenceConfig{ChunkSize: 32})
+	assert.NoError(t, err)
+
+	doc := &schema.Document{ID: "doc", Content: "Pi is 3.14. Is it? Yes! 你好。世界。\nThis last sentence is much longer than thirty-two runes."}
+	chunks, err := s.Transform(ctx, []*schema.Document{doc})
+	assert.NoError(t, err)
+	checkChunks(t, doc, chunks)
+	assert.Equal(t, []string{"Pi is 3.14. Is it? Yes! 你好。世界。", "This last sentence is much", "longer than thirty-two runes."}, contents(chunks))
+}
+
+func TestCodeSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewCodeSplitter(ctx, &CodeConfig{Language: "cobol", ChunkSize: 10})
+	assert.Error(t, err)
+
+	s, err := NewCodeSplitter(ctx, &CodeConfig{Language: LanguageGo, ChunkSize: 60})
+	assert.NoError(t, err)
+
+	code := strings.Join([]string{
+		"package main",
+		"",
+		"func a() int {",
+		"\treturn 1",
+		"}",
+		"",
+		"func b() int {",
+		"\treturn 2",
+		"}",
+	}, "\n")
+	doc := &schema.Document{ID: "main.go", Content: code}
+	chunks, err := s.Transform(ctx, []*schema.Document{doc})
+	assert.NoError(t, err)
+	checkChunks(t, doc, chunks)
+	assert.Equal(t, []string{"package main\n\nfunc a() int {\n\treturn 1\n}", "func b() int {\n\treturn 2\n}"}, contents(chunks))
+}
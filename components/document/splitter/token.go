@@ -0,0 +1,144 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package splitter
+
+import (
+	"context"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Token is a token of the text, located by its byte offsets [Start, End) in the text.
+type Token struct {
+	Start, End int
+}
+
+// Tokenizer tokenizes the text for TokenSplitter, e.g. by the tokenizer of the embedding model.
+type Tokenizer interface {
+	Tokenize(ctx context.Context, text string) ([]Token, error)
+}
+
+// TokenizerFunc is an adapter to use a function as Tokenizer.
+type TokenizerFunc func(ctx context.Context, text string) ([]Token, error)
+
+// Tokenize calls f(ctx, text).
+func (f TokenizerFunc) Tokenize(ctx context.Context, text string) ([]Token, error) {
+	return f(ctx, text)
+}
+
+// WhitespaceTokenizer tokenizes the text into the words separated by white spaces.
+var WhitespaceTokenizer Tokenizer = TokenizerFunc(func(_ context.Context, text string) ([]Token, error) {
+	var (
+		ret   []Token
+		start = -1
+	)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				ret = append(ret, Token{Start: start, End: i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i += size
+	}
+	if start >= 0 {
+		ret = append(ret, Token{Start: start, End: len(text)})
+	}
+	return ret, nil
+})
+
+// TokenConfig is the config of TokenSplitter.
+type TokenConfig struct {
+	// ChunkSize is the max number of tokens of a chunk, required.
+	ChunkSize int
+	// ChunkOverlap is the number of tokens of a chunk repeated at the head of the next one, optional.
+	ChunkOverlap int
+	// Tokenizer tokenizes the text. Optional. Default is WhitespaceTokenizer.
+	Tokenizer Tokenizer
+}
+
+// TokenSplitter splits the documents into chunks of ChunkSize tokens, counted by the Tokenizer.
+// eg:
+//
+//	s, err := splitter.NewTokenSplitter(ctx, &splitter.TokenConfig{ChunkSize: 512, ChunkOverlap: 64, Tokenizer: bpeTokenizer})
+//	chunks, err := s.Transform(ctx, docs)
+type TokenSplitter struct {
+	chunkSize    int
+	chunkOverlap int
+	tokenizer    Tokenizer
+}
+
+// NewTokenSplitter creates a TokenSplitter.
+func NewTokenSplitter(ctx context.Context, config *TokenConfig) (*TokenSplitter, error) {
+	if config == nil {
+		return nil, errNilConfig
+	}
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", config.ChunkSize)
+	}
+	if config.ChunkOverlap < 0 || config.ChunkOverlap >= config.ChunkSize {
+		return nil, fmt.Errorf("chunk overlap must be in [0, chunk size), got %d", config.ChunkOverlap)
+	}
+
+	tokenizer := config.Tokenizer
+	if tokenizer == nil {
+		tokenizer = WhitespaceTokenizer
+	}
+
+	return &TokenSplitter{chunkSize: config.ChunkSize, chunkOverlap: config.ChunkOverlap, tokenizer: tokenizer}, nil
+}
+
+// Transform splits the documents into chunks.
+func (t *TokenSplitter) Transform(ctx context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	return transform(ctx, src, t.split)
+}
+
+func (t *TokenSplitter) split(ctx context.Context, text string) ([]span, error) {
+	tokens, err := t.tokenizer.Tokenize(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("tokenize fail: %w", err)
+	}
+
+	var ret []span
+	for i := 0; i < len(tokens); i += t.chunkSize - t.chunkOverlap {
+		end := i + t.chunkSize
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		sp := span{tokens[i].Start, tokens[end-1].End}
+		if sp.start < 0 || sp.end > len(text) || sp.start > sp.end {
+			return nil, fmt.Errorf("invalid token offsets: [%d, %d)", sp.start, sp.end)
+		}
+		ret = append(ret, sp)
+		if end == len(tokens) {
+			break
+		}
+	}
+	return ret, nil
+}
+
+// GetType returns the type of the splitter (TokenSplitter).
+func (t *TokenSplitter) GetType() string {
+	return "TokenSplitter"
+}
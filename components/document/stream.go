@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package document
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// StreamLoader is a Loader which can load the documents as a stream, each chunk being a batch of documents,
+// so that a large source is not materialized in memory at once.
+// When running in stream mode, a graph node of StreamLoader outputs the stream, and the downstream nodes process the documents chunk by chunk,
+// e.g. through StreamTransformer, until a node requiring the whole []*schema.Document, where the chunks are concatenated.
+type StreamLoader interface {
+	Loader
+	LoadStream(ctx context.Context, src Source, opts ...LoaderOption) (*schema.StreamReader[[]*schema.Document], error)
+}
+
+// StreamTransformer is a Transformer which can transform a stream of documents chunk by chunk,
+// used by the graph node of the transformer when its input is a stream.
+type StreamTransformer interface {
+	Transformer
+	TransformStream(ctx context.Context, src *schema.StreamReader[[]*schema.Document], opts ...TransformerOption) (*schema.StreamReader[[]*schema.Document], error)
+}
+
+// NewChunkwiseTransformer wraps the transformer as a StreamTransformer, which transforms each chunk of the stream independently.
+// It's only suitable for the transformers treating each document independently, e.g. splitters,
+// but not those looking at all the documents together, e.g. rerankers or deduplicators.
+// e.g.
+//
+//	s, err := splitter.NewSentenceSplitter(ctx, &splitter.SentenceConfig{ChunkSize: 300})
+//	graph.AddDocumentTransformerNode("splitter", document.NewChunkwiseTransformer(s))
+func NewChunkwiseTransformer(t Transformer) StreamTransformer {
+	return &chunkwiseTransformer{t: t}
+}
+
+type chunkwiseTransformer struct {
+	t Transformer
+}
+
+func (c *chunkwiseTransformer) Transform(ctx context.Context, src []*schema.Document, opts ...TransformerOption) ([]*schema.Document, error) {
+	return c.t.Transform(ctx, src, opts...)
+}
+
+func (c *chunkwiseTransformer) TransformStream(ctx context.Context, src *schema.StreamReader[[]*schema.Document],
+	opts ...TransformerOption) (*schema.StreamReader[[]*schema.Document], error) {
+
+	sr, sw := schema.Pipe[[]*schema.Document](0)
+	go func() {
+		defer func() {
+			src.Close()
+			sw.Close()
+		}()
+
+		for {
+			chunk, err := src.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err == nil {
+				chunk, err = c.t.Transform(ctx, chunk, opts...)
+			}
+			if closed := sw.Send(chunk, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+
+	return sr, nil
+}
+
+// GetType returns the type of the wrapped transformer.
+func (c *chunkwiseTransformer) GetType() string {
+	if typ, ok := components.GetType(c.t); ok {
+		return typ
+	}
+	return "ChunkwiseTransformer"
+}
+
+// IsCallbacksEnabled reports whether the wrapped transformer triggers the callbacks by itself, for each chunk when transforming a stream.
+func (c *chunkwiseTransformer) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.t)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package document
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type upperTransformer struct{}
+
+func (upperTransformer) Transform(_ context.Context, src []*schema.Document, _ ...TransformerOption) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, 0, len(src))
+	for _, doc := range src {
+		if doc.Content == "bad" {
+			return nil, errors.New("bad document")
+		}
+		ret = append(ret, &schema.Document{ID: doc.ID, Content: strings.ToUpper(doc.Content)})
+	}
+	return ret, nil
+}
+
+func TestChunkwiseTransformer(t *testing.T) {
+	ctx := context.Background()
+	tf := NewChunkwiseTransformer(upperTransformer{})
+	assert.Equal(t, "ChunkwiseTransformer", tf.(interface{ GetType() string }).GetType())
+
+	out, err := tf.Transform(ctx, []*schema.Document{{Content: "a"}})
+	require.NoError(t, err)
+	assert.Equal(t, "A", out[0].Content)
+
+	sr, err := tf.TransformStream(ctx, schema.StreamReaderFromArray([][]*schema.Document{
+		{{Content: "a"}, {Content: "b"}},
+		{{Content: "c"}},
+	}))
+	require.NoError(t, err)
+	var chunks [][]string
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		var contents []string
+		for _, doc := range chunk {
+			contents = append(contents, doc.Content)
+		}
+		chunks = append(chunks, contents)
+	}
+	assert.Equal(t, [][]string{{"A", "B"}, {"C"}}, chunks)
+
+	sr, err = tf.TransformStream(ctx, schema.StreamReaderFromArray([][]*schema.Document{
+		{{Content: "a"}},
+		{{Content: "bad"}},
+		{{Content: "c"}},
+	}))
+	require.NoError(t, err)
+	defer sr.Close()
+	_, err = sr.Recv()
+	assert.NoError(t, err)
+	_, err = sr.Recv()
+	assert.ErrorContains(t, err, "bad document")
+}
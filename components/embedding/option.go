@@ -16,10 +16,19 @@
 
 package embedding
 
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/credential"
+)
+
 // Options is the options for the embedding.
 type Options struct {
 	// Model is the model name for the embedding.
 	Model *string
+	// Credential overrides the credential of the embedder for the call, see ResolveCredential.
+	Credential *credential.Credential
 }
 
 // Option is the call option for Embedder component.
@@ -38,6 +47,26 @@ func WithModel(model string) Option {
 	}
 }
 
+// WithCredential is the option to set the credential of the embedder for the call, e.g. the API key of the tenant.
+func WithCredential(c *credential.Credential) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Credential = c
+		},
+	}
+}
+
+// ResolveCredential returns the credential the embedder should use for the call:
+// the one set by WithCredential if any, otherwise the one resolved by the credential.Provider carried by ctx,
+// with name identifying the embedder, e.g. its implementation type.
+// It returns nil without error if neither is available, in which case the embedder uses its own credential.
+func ResolveCredential(ctx context.Context, options *Options, name string) (*credential.Credential, error) {
+	if options != nil && options.Credential != nil {
+		return options.Credential, nil
+	}
+	return credential.Resolve(ctx, &credential.Request{Component: components.ComponentOfEmbedding, Name: name})
+}
+
 // GetCommonOptions extract embedding Options from Option list, optionally providing a base Options with default values.
 // eg.
 //
@@ -16,7 +16,10 @@
 
 package indexer
 
-import "github.com/cloudwego/eino/components/embedding"
+import (
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
 
 // Options is the options for the indexer.
 type Options struct {
@@ -24,6 +27,16 @@ type Options struct {
 	SubIndexes []string
 	// Embedding is the embedding component.
 	Embedding embedding.Embedder
+	// Filter is the filter on the document metadata, selecting the stored documents the operation applies to,
+	// e.g. the stale documents to be replaced. See WithFilter.
+	Filter *schema.Filter
+
+	// Upsert replaces the stored documents with the same IDs instead of adding duplicates or failing. See WithUpsert.
+	Upsert bool
+	// DeleteIDs are the IDs of the stored documents to be deleted before storing the documents. See WithDeleteIDs.
+	DeleteIDs []string
+	// DeleteByFilter deletes the stored documents matching Filter before storing the documents. See WithDeleteByFilter.
+	DeleteByFilter bool
 }
 
 // WithSubIndexes is the option to set the sub indexes for the indexer.
@@ -44,6 +57,46 @@ func WithEmbedding(emb embedding.Embedder) Option {
 	}
 }
 
+// WithFilter is the option to set the metadata filter for the indexer, which indexer implementations translate to the syntax of their stores.
+func WithFilter(filter *schema.Filter) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Filter = filter
+		},
+	}
+}
+
+// WithUpsert is the option to upsert the documents by ID,
+// i.e. the stored documents with the same IDs are replaced, the others are added.
+func WithUpsert() Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Upsert = true
+		},
+	}
+}
+
+// WithDeleteIDs is the option to delete the stored documents by IDs before storing the documents.
+// Deletion only is done by storing no documents, e.g. Store(ctx, nil, indexer.WithDeleteIDs("doc_1")).
+func WithDeleteIDs(ids ...string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.DeleteIDs = append(opts.DeleteIDs, ids...)
+		},
+	}
+}
+
+// WithDeleteByFilter is the option to delete the stored documents matching the filter before storing the documents.
+// e.g. Store(ctx, nil, indexer.WithDeleteByFilter(schema.Eq("source", "wiki")))
+func WithDeleteByFilter(filter *schema.Filter) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Filter = filter
+			opts.DeleteByFilter = true
+		},
+	}
+}
+
 // Option is the call option for Indexer component.
 type Option struct {
 	apply func(opts *Options)
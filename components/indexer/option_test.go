@@ -22,6 +22,7 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 
 	"github.com/cloudwego/eino/internal/mock/components/embedding"
+	"github.com/cloudwego/eino/schema"
 )
 
 func TestOptions(t *testing.T) {
@@ -29,17 +30,28 @@ func TestOptions(t *testing.T) {
 		var (
 			subIndexes = []string{"index_1", "index_2"}
 			e          = &embedding.MockEmbedder{}
+			filter     = schema.Eq("source", "wiki")
 		)
 
 		opts := GetCommonOptions(
 			&Options{},
 			WithSubIndexes(subIndexes),
 			WithEmbedding(e),
+			WithFilter(filter),
 		)
 
 		convey.So(opts, convey.ShouldResemble, &Options{
 			SubIndexes: subIndexes,
 			Embedding:  e,
+			Filter:     filter,
+		})
+
+		opts = GetCommonOptions(nil, WithUpsert(), WithDeleteIDs("1"), WithDeleteIDs("2", "3"), WithDeleteByFilter(filter))
+		convey.So(opts, convey.ShouldResemble, &Options{
+			Filter:         filter,
+			Upsert:         true,
+			DeleteIDs:      []string{"1", "2", "3"},
+			DeleteByFilter: true,
 		})
 	})
 }
@@ -74,6 +74,8 @@ type CallbackOutput struct {
 	Config *Config
 	// TokenUsage is the token usage of this request.
 	TokenUsage *TokenUsage
+	// ResponseMeta is the standardized meta information of the response, e.g. finish reason, usage, model name, request id and latency.
+	ResponseMeta *schema.ResponseMeta
 	// Extra is the extra information for the callback.
 	Extra map[string]any
 }
@@ -98,10 +100,32 @@ func ConvCallbackOutput(src callbacks.CallbackOutput) *CallbackOutput {
 	case *CallbackOutput: // when callback is triggered within component implementation, the output is usually already a typed *model.CallbackOutput
 		return t
 	case *schema.Message: // when callback is injected by graph node, not the component implementation itself, the output is the output of Chat Model interface, which is *schema.Message
-		return &CallbackOutput{
+		out := &CallbackOutput{
 			Message: t,
 		}
+		if t != nil && t.ResponseMeta != nil {
+			out.ResponseMeta = t.ResponseMeta
+			out.TokenUsage = toTokenUsage(t.ResponseMeta.Usage)
+			if t.ResponseMeta.Model != "" {
+				out.Config = &Config{Model: t.ResponseMeta.Model}
+			}
+		}
+		return out
 	default:
 		return nil
 	}
 }
+
+func toTokenUsage(u *schema.TokenUsage) *TokenUsage {
+	if u == nil {
+		return nil
+	}
+	return &TokenUsage{
+		PromptTokens: u.PromptTokens,
+		PromptTokenDetails: PromptTokenDetails{
+			CachedTokens: u.PromptTokenDetails.CachedTokens,
+		},
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
@@ -32,4 +32,12 @@ func TestConvModel(t *testing.T) {
 	assert.NotNil(t, ConvCallbackOutput(&CallbackOutput{}))
 	assert.NotNil(t, ConvCallbackOutput(&schema.Message{}))
 	assert.Nil(t, ConvCallbackOutput("asd"))
+
+	out := ConvCallbackOutput(&schema.Message{ResponseMeta: &schema.ResponseMeta{
+		Model: "m",
+		Usage: &schema.TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+	}})
+	assert.Equal(t, "m", out.ResponseMeta.Model)
+	assert.Equal(t, "m", out.Config.Model)
+	assert.Equal(t, &TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3}, out.TokenUsage)
 }
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// Capabilities describes the features supported by a chat model.
+type Capabilities struct {
+	// ToolCalling reports whether the model can call tools.
+	ToolCalling bool
+	// ParallelToolCalls reports whether the model can call several tools in one message.
+	ParallelToolCalls bool
+	// JSONMode reports whether the model can be constrained to output JSON.
+	JSONMode bool
+	// Vision reports whether the model accepts images as input.
+	Vision bool
+	// MaxContextTokens is the context window of the model, 0 means unknown.
+	MaxContextTokens int
+}
+
+// CapabilityReporter is implemented by chat models reporting their capabilities,
+// so that graphs and flows can fail fast at construction instead of deep inside a run.
+type CapabilityReporter interface {
+	// Capabilities returns the capabilities of the model, nil if unknown.
+	Capabilities() *Capabilities
+}
+
+// GetCapabilities returns the capabilities reported by the model, false if the model doesn't report them.
+func GetCapabilities(m any) (*Capabilities, bool) {
+	reporter, ok := m.(CapabilityReporter)
+	if !ok {
+		return nil, false
+	}
+	c := reporter.Capabilities()
+	return c, c != nil
+}
+
+// CheckToolCalling returns an error if the model reports it doesn't support tool calling.
+// Models not reporting their capabilities are assumed to support it.
+func CheckToolCalling(m any) error {
+	c, ok := GetCapabilities(m)
+	if !ok || c.ToolCalling {
+		return nil
+	}
+	typ, _ := components.GetType(m)
+	return fmt.Errorf("chat model[%s] doesn't support tool calling", typ)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reportingModel struct {
+	echoModel
+	capabilities *Capabilities
+}
+
+func (r *reportingModel) Capabilities() *Capabilities {
+	return r.capabilities
+}
+
+func TestCapabilities(t *testing.T) {
+	_, ok := GetCapabilities(&echoModel{})
+	assert.False(t, ok)
+	assert.NoError(t, CheckToolCalling(&echoModel{}))
+
+	_, ok = GetCapabilities(&reportingModel{})
+	assert.False(t, ok)
+
+	m := &reportingModel{capabilities: &Capabilities{Vision: true, MaxContextTokens: 8192}}
+	c, ok := GetCapabilities(m)
+	assert.True(t, ok)
+	assert.Equal(t, 8192, c.MaxContextTokens)
+	assert.EqualError(t, CheckToolCalling(m), "chat model[Echo] doesn't support tool calling")
+
+	// the capabilities are kept by the middlewares
+	wrapped := WrapChatModel(m)
+	c, ok = GetCapabilities(wrapped)
+	assert.True(t, ok)
+	assert.True(t, c.Vision)
+	_, ok = GetCapabilities(WrapChatModel(&echoModel{}))
+	assert.False(t, ok)
+
+	m.capabilities.ToolCalling = true
+	assert.NoError(t, CheckToolCalling(wrapped))
+}
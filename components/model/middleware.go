@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GenerateEndpoint is the signature of BaseChatModel.Generate.
+type GenerateEndpoint func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error)
+
+// StreamEndpoint is the signature of BaseChatModel.Stream.
+type StreamEndpoint func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error)
+
+// Middleware intercepts the calls to a chat model, e.g. to modify the messages, append options,
+// record the token usage, limit the rate or retry on errors.
+// Either function can be nil, leaving the corresponding calls untouched.
+type Middleware struct {
+	// Generate wraps the Generate calls.
+	Generate func(next GenerateEndpoint) GenerateEndpoint
+	// Stream wraps the Stream calls.
+	Stream func(next StreamEndpoint) StreamEndpoint
+}
+
+// WrapChatModel wraps the chat model with the middlewares, the first middleware is the outermost one.
+// The returned model implements ToolCallingChatModel if m does, and the models returned by its WithTools are wrapped as well.
+// The type and callback aspect of m are kept, so the wrapped model reports the same callbacks as m.
+// e.g.
+//
+//	logging := model.Middleware{
+//		Generate: func(next model.GenerateEndpoint) model.GenerateEndpoint {
+//			return func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+//				start := time.Now()
+//				out, err := next(ctx, input, opts...)
+//				log.Printf("generate costs %v, err: %v", time.Since(start), err)
+//				return out, err
+//			}
+//		},
+//	}
+//	cm := model.WrapChatModel(arkModel, logging, retry)
+func WrapChatModel(m BaseChatModel, middlewares ...Middleware) BaseChatModel {
+	w := &wrappedChatModel{
+		model:       m,
+		middlewares: middlewares,
+		generate:    m.Generate,
+		stream:      m.Stream,
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i].Generate != nil {
+			w.generate = middlewares[i].Generate(w.generate)
+		}
+		if middlewares[i].Stream != nil {
+			w.stream = middlewares[i].Stream(w.stream)
+		}
+	}
+
+	if _, ok := m.(ToolCallingChatModel); ok {
+		return &wrappedToolCallingChatModel{wrappedChatModel: w}
+	}
+	return w
+}
+
+type wrappedChatModel struct {
+	model       BaseChatModel
+	middlewares []Middleware
+	generate    GenerateEndpoint
+	stream      StreamEndpoint
+}
+
+func (w *wrappedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	return w.generate(ctx, input, opts...)
+}
+
+func (w *wrappedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	return w.stream(ctx, input, opts...)
+}
+
+// GetType returns the type of the wrapped model.
+func (w *wrappedChatModel) GetType() string {
+	typ, _ := components.GetType(w.model)
+	return typ
+}
+
+// IsCallbacksEnabled returns whether the wrapped model reports the callbacks itself.
+func (w *wrappedChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(w.model)
+}
+
+// Capabilities returns the capabilities reported by the wrapped model.
+func (w *wrappedChatModel) Capabilities() *Capabilities {
+	c, _ := GetCapabilities(w.model)
+	return c
+}
+
+type wrappedToolCallingChatModel struct {
+	*wrappedChatModel
+}
+
+func (w *wrappedToolCallingChatModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	m, err := w.model.(ToolCallingChatModel).WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return WrapChatModel(m, w.middlewares...).(ToolCallingChatModel), nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+type echoModel struct {
+	tools []*schema.ToolInfo
+	calls int
+}
+
+func (e *echoModel) Generate(_ context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	e.calls++
+	o := GetCommonOptions(nil, opts...)
+	if o.Model != nil && *o.Model == "fail" && e.calls == 1 {
+		return nil, errors.New("transient error")
+	}
+	return schema.AssistantMessage(input[len(input)-1].Content, nil), nil
+}
+
+func (e *echoModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := e.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (e *echoModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	return &echoModel{tools: tools}, nil
+}
+
+func (e *echoModel) GetType() string { return "Echo" }
+
+func (e *echoModel) IsCallbacksEnabled() bool { return true }
+
+func TestWrapChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	var order []string
+	prefix := func(name string) Middleware {
+		return Middleware{
+			Generate: func(next GenerateEndpoint) GenerateEndpoint {
+				return func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+					order = append(order, name)
+					input = append(input[:len(input):len(input)], schema.UserMessage(name+input[len(input)-1].Content))
+					return next(ctx, input, opts...)
+				}
+			},
+			Stream: func(next StreamEndpoint) StreamEndpoint {
+				return func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+					order = append(order, name)
+					return next(ctx, input, opts...)
+				}
+			},
+		}
+	}
+	retry := Middleware{
+		Generate: func(next GenerateEndpoint) GenerateEndpoint {
+			return func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+				out, err := next(ctx, input, opts...)
+				if err != nil {
+					return next(ctx, input, opts...)
+				}
+				return out, nil
+			}
+		},
+	}
+
+	inner := &echoModel{}
+	m := WrapChatModel(inner, prefix("a"), prefix("b"), retry)
+
+	out, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("x")})
+	assert.NoError(t, err)
+	assert.Equal(t, "bax", out.Content)
+	assert.Equal(t, []string{"a", "b"}, order)
+
+	// only Generate is retried
+	out, err = m.Generate(ctx, []*schema.Message{schema.UserMessage("x")}, WithModel("fail"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bax", out.Content)
+
+	order = nil
+	sr, err := m.Stream(ctx, []*schema.Message{schema.UserMessage("x")})
+	assert.NoError(t, err)
+	chunk, err := sr.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "x", chunk.Content)
+	_, err = sr.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"a", "b"}, order)
+
+	typ, ok := components.GetType(m)
+	assert.True(t, ok)
+	assert.Equal(t, "Echo", typ)
+	assert.True(t, components.IsCallbacksEnabled(m))
+
+	tcm, ok := m.(ToolCallingChatModel)
+	assert.True(t, ok)
+	withTools, err := tcm.WithTools([]*schema.ToolInfo{{Name: "tool"}})
+	assert.NoError(t, err)
+	out, err = withTools.Generate(ctx, []*schema.Message{schema.UserMessage("y")})
+	assert.NoError(t, err)
+	assert.Equal(t, "bay", out.Content)
+
+	_, ok = WrapChatModel(struct{ BaseChatModel }{inner}).(ToolCallingChatModel)
+	assert.False(t, ok)
+}
@@ -16,7 +16,15 @@
 
 package model
 
-import "github.com/cloudwego/eino/schema"
+import (
+	"context"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/credential"
+	"github.com/cloudwego/eino/schema"
+)
 
 // Options is the common options for the model.
 type Options struct {
@@ -34,6 +42,39 @@ type Options struct {
 	Tools []*schema.ToolInfo
 	// ToolChoice controls which tool is called by the model.
 	ToolChoice *schema.ToolChoice
+	// ResponseFormat constrains the format of the model output, e.g. to a JSON object.
+	ResponseFormat *ResponseFormat
+	// Credential overrides the credential of the model for the call, see ResolveCredential.
+	Credential *credential.Credential
+}
+
+// ResponseFormatType is the type of the model output format.
+type ResponseFormatType string
+
+const (
+	// ResponseFormatText means the model outputs free text, which is the default.
+	ResponseFormatText ResponseFormatType = "text"
+	// ResponseFormatJSONObject means the model outputs a JSON object.
+	ResponseFormatJSONObject ResponseFormatType = "json_object"
+	// ResponseFormatJSONSchema means the model outputs a JSON object conforming to JSONSchema.
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema"
+)
+
+// ResponseFormat describes the format the model output should conform to.
+// Implementations with native support, e.g. the response_format param of OpenAI, should map it to the request.
+// Implementations lacking native support should leave it to ResponseFormatFallback,
+// which asks the model for the format with a system instruction instead.
+type ResponseFormat struct {
+	// Type is the type of the output format.
+	Type ResponseFormatType
+	// Name is the name of the schema, required by some providers when Type is ResponseFormatJSONSchema.
+	Name string
+	// Description describes the expected output.
+	Description string
+	// JSONSchema is the schema of the output, used when Type is ResponseFormatJSONSchema.
+	JSONSchema *jsonschema.Schema
+	// Strict requires the output to conform to the schema exactly, if supported by the provider.
+	Strict bool
 }
 
 // Option is the call option for ChatModel component.
@@ -109,6 +150,42 @@ func WithToolChoice(toolChoice schema.ToolChoice) Option {
 	}
 }
 
+// WithResponseFormat is the option to set the output format of the model.
+func WithResponseFormat(format *ResponseFormat) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.ResponseFormat = format
+		},
+	}
+}
+
+// WithCredential is the option to set the credential of the model for the call, e.g. the API key of the tenant.
+func WithCredential(c *credential.Credential) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Credential = c
+		},
+	}
+}
+
+// ResolveCredential returns the credential the model should use for the call:
+// the one set by WithCredential if any, otherwise the one resolved by the credential.Provider carried by ctx,
+// with name identifying the model, e.g. its implementation type.
+// It returns nil without error if neither is available, in which case the model uses its own credential.
+// e.g.
+//
+//	options := model.GetCommonOptions(&model.Options{}, opts...)
+//	cred, err := model.ResolveCredential(ctx, options, cm.GetType())
+//	if cred != nil {
+//		apiKey = cred.APIKey
+//	}
+func ResolveCredential(ctx context.Context, options *Options, name string) (*credential.Credential, error) {
+	if options != nil && options.Credential != nil {
+		return options.Credential, nil
+	}
+	return credential.Resolve(ctx, &credential.Request{Component: components.ComponentOfChatModel, Name: name})
+}
+
 // WrapImplSpecificOptFn is the option to wrap the implementation specific option function.
 func WrapImplSpecificOptFn[T any](optFn func(*T)) Option {
 	return Option{
@@ -17,8 +17,10 @@
 package model
 
 import (
+	"context"
 	"testing"
 
+	"github.com/cloudwego/eino/components/credential"
 	"github.com/cloudwego/eino/schema"
 	"github.com/smartystreets/goconvey/convey"
 )
@@ -108,3 +110,23 @@ func TestImplSpecificOption(t *testing.T) {
 		})
 	})
 }
+
+func TestResolveCredential(t *testing.T) {
+	convey.Convey("resolve_credential", t, func() {
+		ctx := credential.WithProvider(context.Background(), credential.NewStaticProvider(map[string]*credential.Credential{
+			"OpenAI": {APIKey: "from-provider"},
+		}))
+
+		c, err := ResolveCredential(ctx, GetCommonOptions(nil), "OpenAI")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(c.APIKey, convey.ShouldEqual, "from-provider")
+
+		c, err = ResolveCredential(ctx, GetCommonOptions(nil, WithCredential(&credential.Credential{APIKey: "from-option"})), "OpenAI")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(c.APIKey, convey.ShouldEqual, "from-option")
+
+		c, err = ResolveCredential(context.Background(), GetCommonOptions(nil), "OpenAI")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(c, convey.ShouldBeNil)
+	})
+}
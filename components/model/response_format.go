@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ResponseFormatFallback is the middleware emulating WithResponseFormat for models lacking native support.
+// When a JSON response format is requested, it appends an instruction describing the format to the system message,
+// and for Generate, strips the markdown code fence the model may wrap the JSON with.
+// Stream output is passed through as is.
+var ResponseFormatFallback = Middleware{
+	Generate: func(next GenerateEndpoint) GenerateEndpoint {
+		return func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+			input, ok, err := withFormatInstruction(input, opts)
+			if err != nil {
+				return nil, err
+			}
+			out, err := next(ctx, input, opts...)
+			if err != nil || !ok || out == nil {
+				return out, err
+			}
+			cp := *out
+			cp.Content = TrimCodeFence(cp.Content)
+			return &cp, nil
+		}
+	},
+	Stream: func(next StreamEndpoint) StreamEndpoint {
+		return func(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+			input, _, err := withFormatInstruction(input, opts)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, input, opts...)
+		}
+	},
+}
+
+// WithResponseFormatFallback wraps the model with ResponseFormatFallback, unless it reports native JSON mode support.
+func WithResponseFormatFallback(m BaseChatModel) BaseChatModel {
+	if c, ok := GetCapabilities(m); ok && c.JSONMode {
+		return m
+	}
+	return WrapChatModel(m, ResponseFormatFallback)
+}
+
+// TrimCodeFence removes the markdown code fence around the content, e.g. "```json\n{...}\n```", if any.
+func TrimCodeFence(content string) string {
+	s := strings.TrimSpace(content)
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") || len(s) < 6 {
+		return content
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "```"), "```")
+	// drop the language tag
+	if i := strings.IndexByte(s, '\n'); i >= 0 && !strings.ContainsAny(s[:i], "{[") {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(s)
+}
+
+func withFormatInstruction(input []*schema.Message, opts []Option) ([]*schema.Message, bool, error) {
+	format := GetCommonOptions(nil, opts...).ResponseFormat
+	if format == nil || format.Type == "" || format.Type == ResponseFormatText {
+		return input, false, nil
+	}
+
+	instruction, err := formatInstruction(format)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ret := make([]*schema.Message, 0, len(input)+1)
+	if len(input) > 0 && input[0] != nil && input[0].Role == schema.System {
+		sys := *input[0]
+		sys.Content = strings.TrimRight(sys.Content, "\n") + "\n\n" + instruction
+		ret = append(ret, &sys)
+		ret = append(ret, input[1:]...)
+	} else {
+		ret = append(ret, schema.SystemMessage(instruction))
+		ret = append(ret, input...)
+	}
+	return ret, true, nil
+}
+
+func formatInstruction(format *ResponseFormat) (string, error) {
+	sb := strings.Builder{}
+	sb.WriteString("Respond with a single valid JSON object only, without any explanation or markdown code fence.")
+	if format.Description != "" {
+		sb.WriteString("\nThe JSON object is ")
+		sb.WriteString(format.Description)
+		if !strings.HasSuffix(format.Description, ".") {
+			sb.WriteString(".")
+		}
+	}
+	if format.Type == ResponseFormatJSONSchema && format.JSONSchema != nil {
+		b, err := sonic.Marshal(format.JSONSchema)
+		if err != nil {
+			return "", fmt.Errorf("marshal response format schema fail: %w", err)
+		}
+		sb.WriteString("\nThe JSON object must conform to the following JSON schema:\n")
+		sb.Write(b)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type fencedJSONModel struct {
+	echoModel
+	input []*schema.Message
+}
+
+func (f *fencedJSONModel) Generate(_ context.Context, input []*schema.Message, _ ...Option) (*schema.Message, error) {
+	f.input = input
+	return schema.AssistantMessage("```json\n{\"city\":\"Paris\"}\n```", nil), nil
+}
+
+func TestResponseFormatFallback(t *testing.T) {
+	ctx := context.Background()
+
+	format := &ResponseFormat{
+		Type:        ResponseFormatJSONSchema,
+		Description: "the weather of a city",
+		JSONSchema:  &jsonschema.Schema{Type: "object"},
+	}
+	assert.Equal(t, format, GetCommonOptions(nil, WithResponseFormat(format)).ResponseFormat)
+
+	t.Run("without system message", func(t *testing.T) {
+		m := &fencedJSONModel{}
+		out, err := WithResponseFormatFallback(m).Generate(ctx, []*schema.Message{schema.UserMessage("weather")}, WithResponseFormat(format))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"city":"Paris"}`, out.Content)
+		assert.Len(t, m.input, 2)
+		assert.Equal(t, schema.System, m.input[0].Role)
+		assert.Contains(t, m.input[0].Content, "the weather of a city.")
+		assert.Contains(t, m.input[0].Content, `{"type":"object"}`)
+	})
+
+	t.Run("with system message", func(t *testing.T) {
+		m := &fencedJSONModel{}
+		input := []*schema.Message{schema.SystemMessage("you are a forecaster"), schema.UserMessage("weather")}
+		_, err := WithResponseFormatFallback(m).Generate(ctx, input, WithResponseFormat(format))
+		assert.NoError(t, err)
+		assert.Len(t, m.input, 2)
+		assert.Contains(t, m.input[0].Content, "you are a forecaster\n\nRespond with a single valid JSON object")
+		assert.Equal(t, "you are a forecaster", input[0].Content)
+	})
+
+	t.Run("text format", func(t *testing.T) {
+		m := &fencedJSONModel{}
+		out, err := WithResponseFormatFallback(m).Generate(ctx, []*schema.Message{schema.UserMessage("weather")})
+		assert.NoError(t, err)
+		assert.Len(t, m.input, 1)
+		assert.Contains(t, out.Content, "```json")
+	})
+
+	t.Run("native support", func(t *testing.T) {
+		m := &reportingModel{capabilities: &Capabilities{JSONMode: true}}
+		assert.Equal(t, BaseChatModel(m), WithResponseFormatFallback(m))
+	})
+}
+
+func TestTrimCodeFence(t *testing.T) {
+	assert.Equal(t, `{"a":1}`, TrimCodeFence("```json\n{\"a\":1}\n```"))
+	assert.Equal(t, `{"a":1}`, TrimCodeFence(" ```{\"a\":1}``` "))
+	assert.Equal(t, `[1]`, TrimCodeFence("```\n[1]\n```"))
+	assert.Equal(t, `{"a":1}`, TrimCodeFence(`{"a":1}`))
+}
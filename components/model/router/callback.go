@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ComponentOfModelRouter is the component of the routing callbacks, whose input is the []*schema.Message and output is the *Decision.
+const ComponentOfModelRouter components.Component = "ModelRouter"
+
+type options struct {
+	backend      string
+	capabilities []Capability
+}
+
+// WithBackend chooses the backend by name, bypassing the policy.
+func WithBackend(name string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.backend = name
+	})
+}
+
+// WithCapabilities requires the capabilities in addition to those detected from the request.
+func WithCapabilities(capabilities ...Capability) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.capabilities = append(o.capabilities, capabilities...)
+	})
+}
+
+// ConvDecision converts the callback output of the routing to *Decision, nil if it's not.
+func ConvDecision(output callbacks.CallbackOutput) *Decision {
+	d, _ := output.(*Decision)
+	return d
+}
+
+func backendCtx(ctx context.Context, b *Backend) context.Context {
+	typ, _ := components.GetType(b.Model)
+	return callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{Name: b.Name, Type: typ, Component: components.ComponentOfChatModel})
+}
+
+// generateWithCallbacks reports the chat model callbacks for the backends which don't report them themselves.
+func generateWithCallbacks(ctx context.Context, b *Backend, tools []*schema.ToolInfo, input []*schema.Message,
+	opts ...model.Option) (out *schema.Message, err error) {
+
+	ctx = backendCtx(ctx, b)
+	if components.IsCallbacksEnabled(b.Model) {
+		return b.Model.Generate(ctx, input, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input, Tools: tools})
+	out, err = b.Model.Generate(ctx, input, opts...)
+	if err != nil {
+		_ = callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	_ = callbacks.OnEnd(ctx, &model.CallbackOutput{Message: out})
+	return out, nil
+}
+
+func streamWithCallbacks(ctx context.Context, b *Backend, tools []*schema.ToolInfo, input []*schema.Message,
+	opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+
+	ctx = backendCtx(ctx, b)
+	if components.IsCallbacksEnabled(b.Model) {
+		return b.Model.Stream(ctx, input, opts...)
+	}
+
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input, Tools: tools})
+	sr, err := b.Model.Stream(ctx, input, opts...)
+	if err != nil {
+		_ = callbacks.OnError(ctx, err)
+		return nil, err
+	}
+
+	cbSR := schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (*model.CallbackOutput, error) {
+		return &model.CallbackOutput{Message: msg}, nil
+	})
+	_, cbSR = callbacks.OnEndWithStreamOutput(ctx, cbSR)
+	return schema.StreamReaderWithConvert(cbSR, func(out *model.CallbackOutput) (*schema.Message, error) {
+		return out.Message, nil
+	}), nil
+}
@@ -0,0 +1,436 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package router provides a chat model choosing among several backend models per request by policy,
+// e.g. by the length of the input, the cost budget, the required capabilities and A/B weights,
+// so that graphs stay provider-agnostic while controlling the cost.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+// Capability is a capability required by a request, which the chosen backend must support.
+type Capability string
+
+const (
+	// CapabilityTools is required if tools are bound, by WithTools or model.WithTools.
+	CapabilityTools Capability = "tools"
+	// CapabilityVision is required if any input message contains an image.
+	CapabilityVision Capability = "vision"
+	// CapabilityAudio is required if any input message contains an audio.
+	CapabilityAudio Capability = "audio"
+	// CapabilityVideo is required if any input message contains a video.
+	CapabilityVideo Capability = "video"
+)
+
+// Backend is a model the router can choose.
+type Backend struct {
+	// Name identifies the backend, e.g. in the callbacks and WithBackend, required and unique.
+	Name string
+	// Model is the backend model, required.
+	Model model.ToolCallingChatModel
+	// Capabilities are the capabilities supported by the backend.
+	// Optional. By default, derived from the model.Capabilities reported by the model.
+	Capabilities []Capability
+	// MaxInputTokens is the max input tokens the backend accepts, e.g. its context window.
+	// Optional. By default, the MaxContextTokens reported by the model. 0 means unlimited.
+	MaxInputTokens int
+	// InputCostPer1KTokens is the cost of 1k input tokens, used with Config.MaxCostPerRequest.
+	InputCostPer1KTokens float64
+	// Weight is the weight of the backend when several backends are eligible, e.g. for A/B testing.
+	// 0 means 1. Negative means the backend is only chosen explicitly, by WithBackend or as the fallback.
+	Weight int
+}
+
+// Request is the request to route.
+type Request struct {
+	Input []*schema.Message
+	// Tokens is the estimated input tokens.
+	Tokens int
+	// Capabilities are the required capabilities.
+	Capabilities []Capability
+}
+
+// Decision is the routing decision, reported as the output of the routing callbacks.
+type Decision struct {
+	Backend       string
+	Tokens        int
+	EstimatedCost float64
+	// Candidates are the names of the eligible backends the decision is made among.
+	Candidates []string
+}
+
+// Config is the config of the router.
+type Config struct {
+	// Backends are the models to choose among, required.
+	Backends []*Backend
+	// TokenCounter estimates the input tokens of a request. Optional. By default, estimated by tokenizer.Approximate.
+	TokenCounter func(ctx context.Context, input []*schema.Message) (int, error)
+	// MaxCostPerRequest is the budget of the estimated input cost of a request, backends over budget are ineligible.
+	// Optional. 0 means unlimited.
+	MaxCostPerRequest float64
+	// Select chooses the backend among the eligible candidates, sorted by cost ascending.
+	// Optional. By default, randomly by the weights.
+	Select func(ctx context.Context, req *Request, candidates []*Backend) (*Backend, error)
+	// Fallback is the name of the backend chosen when no backend is eligible. Optional. By default, an error is returned.
+	Fallback string
+}
+
+// Router is a model.ToolCallingChatModel choosing a backend per request.
+type Router struct {
+	backends []*Backend
+	byName   map[string]*Backend
+	counter  func(ctx context.Context, input []*schema.Message) (int, error)
+	maxCost  float64
+	selector func(ctx context.Context, req *Request, candidates []*Backend) (*Backend, error)
+	fallback string
+	tools    []*schema.ToolInfo
+}
+
+var _ model.ToolCallingChatModel = (*Router)(nil)
+
+// NewRouter creates a router.
+// e.g.
+//
+//	r, err := router.NewRouter(ctx, &router.Config{
+//		Backends: []*router.Backend{
+//			{Name: "small", Model: smallModel, Capabilities: []router.Capability{router.CapabilityTools},
+//				MaxInputTokens: 8000, InputCostPer1KTokens: 0.1},
+//			{Name: "large", Model: largeModel, Capabilities: []router.Capability{router.CapabilityTools, router.CapabilityVision},
+//				InputCostPer1KTokens: 2},
+//		},
+//		MaxCostPerRequest: 0.5,
+//	})
+//	agent, err := react.NewAgent(ctx, &react.AgentConfig{ToolCallingModel: r})
+func NewRouter(ctx context.Context, config *Config) (*Router, error) {
+	if config == nil || len(config.Backends) == 0 {
+		return nil, errors.New("model router has no backends")
+	}
+
+	backends := make([]*Backend, 0, len(config.Backends))
+	byName := make(map[string]*Backend, len(config.Backends))
+	for i, b := range config.Backends {
+		if b == nil || b.Model == nil {
+			return nil, fmt.Errorf("model router backend[%d] has no model", i)
+		}
+		b = withReportedCapabilities(b)
+		if len(b.Name) == 0 {
+			return nil, fmt.Errorf("model router backend[%d] has no name", i)
+		}
+		if _, ok := byName[b.Name]; ok {
+			return nil, fmt.Errorf("model router backend name is duplicated: %s", b.Name)
+		}
+		byName[b.Name] = b
+		backends = append(backends, b)
+	}
+	if len(config.Fallback) > 0 {
+		if _, ok := byName[config.Fallback]; !ok {
+			return nil, fmt.Errorf("model router fallback backend not found: %s", config.Fallback)
+		}
+	}
+
+	counter := config.TokenCounter
+	if counter == nil {
+		counter = countTokens
+	}
+	selector := config.Select
+	if selector == nil {
+		selector = selectByWeight
+	}
+
+	return &Router{
+		backends: backends,
+		byName:   byName,
+		counter:  counter,
+		maxCost:  config.MaxCostPerRequest,
+		selector: selector,
+		fallback: config.Fallback,
+	}, nil
+}
+
+// Generate routes the request to a backend and generates by it.
+func (r *Router) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	b, err := r.route(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return generateWithCallbacks(ctx, b, r.tools, input, opts...)
+}
+
+// Stream routes the request to a backend and streams by it.
+func (r *Router) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	b, err := r.route(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return streamWithCallbacks(ctx, b, r.tools, input, opts...)
+}
+
+// WithTools returns a router whose backends are bound with the tools.
+func (r *Router) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	backends := make([]*Backend, len(r.backends))
+	byName := make(map[string]*Backend, len(r.backends))
+	for i, b := range r.backends {
+		m, err := b.Model.WithTools(tools)
+		if err != nil {
+			return nil, fmt.Errorf("bind tools to model router backend[%s] fail: %w", b.Name, err)
+		}
+		nb := *b
+		nb.Model = m
+		backends[i] = &nb
+		byName[nb.Name] = &nb
+	}
+
+	nr := *r
+	nr.backends, nr.byName, nr.tools = backends, byName, tools
+	return &nr, nil
+}
+
+// Capabilities reports the union of the capabilities of the backends, with the largest context window,
+// i.e. what the router can serve by choosing the right backend.
+func (r *Router) Capabilities() *model.Capabilities {
+	c := &model.Capabilities{}
+	unlimited := false
+	for _, b := range r.backends {
+		for _, bc := range b.Capabilities {
+			switch bc {
+			case CapabilityTools:
+				c.ToolCalling = true
+			case CapabilityVision:
+				c.Vision = true
+			}
+		}
+		if reported, ok := model.GetCapabilities(b.Model); ok {
+			c.ParallelToolCalls = c.ParallelToolCalls || reported.ParallelToolCalls
+			c.JSONMode = c.JSONMode || reported.JSONMode
+		}
+		if b.MaxInputTokens == 0 {
+			unlimited = true
+		} else if b.MaxInputTokens > c.MaxContextTokens {
+			c.MaxContextTokens = b.MaxInputTokens
+		}
+	}
+	if unlimited {
+		c.MaxContextTokens = 0
+	}
+	return c
+}
+
+// GetType returns the type of the model (Router).
+func (r *Router) GetType() string {
+	return "Router"
+}
+
+// IsCallbacksEnabled reports the router reports the callbacks itself, i.e. the routing decision and the callbacks of the chosen backend.
+func (r *Router) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (r *Router) route(ctx context.Context, input []*schema.Message, opts ...model.Option) (b *Backend, err error) {
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{Name: "ModelRouter", Type: r.GetType(), Component: ComponentOfModelRouter})
+	ctx = callbacks.OnStart(ctx, input)
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	o := model.GetImplSpecificOptions(&options{}, opts...)
+	tokens, err := r.counter(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("model router count tokens fail: %w", err)
+	}
+	req := &Request{
+		Input:        input,
+		Tokens:       tokens,
+		Capabilities: requiredCapabilities(input, len(r.tools) > 0 || len(model.GetCommonOptions(nil, opts...).Tools) > 0, o.capabilities),
+	}
+
+	var candidates []*Backend
+	if len(o.backend) > 0 {
+		forced, ok := r.byName[o.backend]
+		if !ok {
+			return nil, fmt.Errorf("model router backend not found: %s", o.backend)
+		}
+		b = forced
+	} else {
+		candidates = r.eligible(req)
+		switch {
+		case len(candidates) > 0:
+			b, err = r.selector(ctx, req, candidates)
+			if err != nil {
+				return nil, err
+			}
+			if b == nil {
+				return nil, errors.New("model router selects no backend")
+			}
+		case len(r.fallback) > 0:
+			b = r.byName[r.fallback]
+		default:
+			return nil, fmt.Errorf("model router has no eligible backend for %d tokens with capabilities %v", tokens, req.Capabilities)
+		}
+	}
+
+	decision := &Decision{Backend: b.Name, Tokens: tokens, EstimatedCost: estimateCost(b, tokens)}
+	for _, c := range candidates {
+		decision.Candidates = append(decision.Candidates, c.Name)
+	}
+	_ = callbacks.OnEnd(ctx, decision)
+
+	return b, nil
+}
+
+// eligible returns the backends supporting the request, sorted by the estimated cost ascending.
+func (r *Router) eligible(req *Request) []*Backend {
+	var ret []*Backend
+	for _, b := range r.backends {
+		if b.Weight < 0 {
+			continue
+		}
+		if b.MaxInputTokens > 0 && req.Tokens > b.MaxInputTokens {
+			continue
+		}
+		if r.maxCost > 0 && estimateCost(b, req.Tokens) > r.maxCost {
+			continue
+		}
+		if !supports(b, req.Capabilities) {
+			continue
+		}
+		ret = append(ret, b)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].InputCostPer1KTokens < ret[j].InputCostPer1KTokens
+	})
+	return ret
+}
+
+// withReportedCapabilities fills the capabilities and max input tokens of the backend by those reported by its model.
+func withReportedCapabilities(b *Backend) *Backend {
+	reported, ok := model.GetCapabilities(b.Model)
+	if !ok || (len(b.Capabilities) > 0 && b.MaxInputTokens > 0) {
+		return b
+	}
+
+	nb := *b
+	if len(nb.Capabilities) == 0 {
+		if reported.ToolCalling {
+			nb.Capabilities = append(nb.Capabilities, CapabilityTools)
+		}
+		if reported.Vision {
+			nb.Capabilities = append(nb.Capabilities, CapabilityVision)
+		}
+	}
+	if nb.MaxInputTokens == 0 {
+		nb.MaxInputTokens = reported.MaxContextTokens
+	}
+	return &nb
+}
+
+func estimateCost(b *Backend, tokens int) float64 {
+	return float64(tokens) / 1000 * b.InputCostPer1KTokens
+}
+
+func supports(b *Backend, required []Capability) bool {
+	for _, c := range required {
+		found := false
+		for _, bc := range b.Capabilities {
+			if bc == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func requiredCapabilities(input []*schema.Message, withTools bool, extra []Capability) []Capability {
+	set := make(map[Capability]bool)
+	if withTools {
+		set[CapabilityTools] = true
+	}
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		for _, part := range msg.UserInputMultiContent {
+			addPartCapability(set, part.Type)
+		}
+		for _, part := range msg.MultiContent {
+			addPartCapability(set, part.Type)
+		}
+	}
+	for _, c := range extra {
+		set[c] = true
+	}
+
+	ret := make([]Capability, 0, len(set))
+	for c := range set {
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+func addPartCapability(set map[Capability]bool, typ schema.ChatMessagePartType) {
+	switch typ {
+	case schema.ChatMessagePartTypeImageURL:
+		set[CapabilityVision] = true
+	case schema.ChatMessagePartTypeAudioURL:
+		set[CapabilityAudio] = true
+	case schema.ChatMessagePartTypeVideoURL:
+		set[CapabilityVideo] = true
+	}
+}
+
+func countTokens(ctx context.Context, input []*schema.Message) (int, error) {
+	return tokenizer.CountMessageTokens(ctx, tokenizer.Approximate, input)
+}
+
+func selectByWeight(_ context.Context, _ *Request, candidates []*Backend) (*Backend, error) {
+	var total int
+	for _, c := range candidates {
+		total += weightOf(c)
+	}
+
+	n := rand.Intn(total)
+	for _, c := range candidates {
+		if n < weightOf(c) {
+			return c, nil
+		}
+		n -= weightOf(c)
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+func weightOf(b *Backend) int {
+	if b.Weight == 0 {
+		return 1
+	}
+	return b.Weight
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type namedModel struct {
+	name  string
+	tools []*schema.ToolInfo
+}
+
+func (n *namedModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	return schema.AssistantMessage(n.name, nil), nil
+}
+
+func (n *namedModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage(n.name, nil)}), nil
+}
+
+func (n *namedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &namedModel{name: n.name + "+tools", tools: tools}, nil
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+
+	r, err := NewRouter(ctx, &Config{
+		Backends: []*Backend{
+			{Name: "small", Model: &namedModel{name: "small"}, MaxInputTokens: 10, InputCostPer1KTokens: 1,
+				Capabilities: []Capability{CapabilityTools}},
+			{Name: "large", Model: &namedModel{name: "large"}, InputCostPer1KTokens: 100,
+				Capabilities: []Capability{CapabilityTools, CapabilityVision}},
+			{Name: "backup", Model: &namedModel{name: "backup"}, Weight: -1},
+		},
+		MaxCostPerRequest: 1,
+		Select: func(ctx context.Context, req *Request, candidates []*Backend) (*Backend, error) {
+			return candidates[0], nil // the cheapest
+		},
+		Fallback: "backup",
+	})
+	assert.NoError(t, err)
+
+	var decisions []*Decision
+	handler := callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info.Component == ComponentOfModelRouter {
+				decisions = append(decisions, ConvDecision(output))
+			}
+			return ctx
+		}).Build()
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+	generate := func(m model.BaseChatModel, input []*schema.Message, opts ...model.Option) string {
+		out, err_ := m.Generate(ctx, input, opts...)
+		assert.NoError(t, err_)
+		return out.Content
+	}
+
+	short := []*schema.Message{schema.UserMessage("hi")}
+	assert.Equal(t, "small", generate(r, short))
+
+	// too long for small, and too expensive for large
+	long := []*schema.Message{schema.UserMessage(strings.Repeat("x", 40))}
+	assert.Equal(t, "backup", generate(r, long))
+
+	// vision is only supported by large
+	image := []*schema.Message{{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+		{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{}},
+	}}}
+	assert.Equal(t, "large", generate(r, image))
+	assert.Equal(t, "large", generate(r, short, WithCapabilities(CapabilityVision)))
+	assert.Equal(t, "backup", generate(r, short, WithBackend("backup")))
+
+	_, err = r.Generate(ctx, short, WithBackend("unknown"))
+	assert.Error(t, err)
+
+	withTools, err := r.WithTools([]*schema.ToolInfo{{Name: "search"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "small+tools", generate(withTools, short))
+
+	sr, err := withTools.Stream(ctx, long)
+	assert.NoError(t, err)
+	chunk, err := sr.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "backup+tools", chunk.Content)
+	_, err = sr.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	assert.Len(t, decisions, 7)
+	assert.Equal(t, &Decision{Backend: "small", Tokens: 5, EstimatedCost: 0.005, Candidates: []string{"small", "large"}}, decisions[0])
+	assert.Equal(t, "backup", decisions[1].Backend)
+	assert.Empty(t, decisions[1].Candidates)
+}
+
+func TestRouterWeights(t *testing.T) {
+	ctx := context.Background()
+
+	r, err := NewRouter(ctx, &Config{
+		Backends: []*Backend{
+			{Name: "a", Model: &namedModel{name: "a"}, Weight: 3},
+			{Name: "b", Model: &namedModel{name: "b"}, Weight: 1},
+		},
+	})
+	assert.NoError(t, err)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		out, err_ := r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err_)
+		counts[out.Content]++
+	}
+	assert.Greater(t, counts["a"], counts["b"])
+	assert.Greater(t, counts["b"], 0)
+
+	// no eligible backend without fallback
+	_, err = r.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}, WithCapabilities(CapabilityVision))
+	assert.Error(t, err)
+}
+
+type reportingModel struct {
+	namedModel
+}
+
+func (r *reportingModel) Capabilities() *model.Capabilities {
+	return &model.Capabilities{ToolCalling: true, JSONMode: true, MaxContextTokens: 5}
+}
+
+func TestRouterCapabilities(t *testing.T) {
+	ctx := context.Background()
+
+	r, err := NewRouter(ctx, &Config{
+		Backends: []*Backend{
+			{Name: "reporting", Model: &reportingModel{namedModel{name: "reporting"}}},
+			{Name: "vision", Model: &namedModel{name: "vision"}, Capabilities: []Capability{CapabilityVision}, MaxInputTokens: 100},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Capabilities{ToolCalling: true, JSONMode: true, Vision: true, MaxContextTokens: 100}, r.Capabilities())
+
+	// the reported max context tokens limits the backend
+	out, err := r.Generate(ctx, []*schema.Message{schema.UserMessage("too long")}, WithCapabilities())
+	assert.NoError(t, err)
+	assert.Equal(t, "vision", out.Content)
+
+	withTools, err := r.WithTools([]*schema.ToolInfo{{Name: "search"}})
+	assert.NoError(t, err)
+	out, err = withTools.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	assert.NoError(t, err)
+	assert.Equal(t, "reporting+tools", out.Content)
+}
+
+func TestNewRouterValidation(t *testing.T) {
+	ctx := context.Background()
+	m := &namedModel{name: "m"}
+
+	_, err := NewRouter(ctx, &Config{})
+	assert.Error(t, err)
+	_, err = NewRouter(ctx, &Config{Backends: []*Backend{{Model: m}}})
+	assert.Error(t, err)
+	_, err = NewRouter(ctx, &Config{Backends: []*Backend{{Name: "a", Model: m}, {Name: "a", Model: m}}})
+	assert.Error(t, err)
+	_, err = NewRouter(ctx, &Config{Backends: []*Backend{{Name: "a", Model: m}}, Fallback: "b"})
+	assert.Error(t, err)
+}
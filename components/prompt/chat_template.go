@@ -18,6 +18,8 @@ package prompt
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
@@ -78,6 +80,33 @@ func (t *DefaultChatTemplate) Format(ctx context.Context,
 	return result, nil
 }
 
+// TemplateVariables returns the sorted top-level variables required to format the chat template.
+// It returns an error matching schema.ErrTemplateVariablesUnsupported if the variables of any template can't be analyzed,
+// e.g. a Jinja2 template, or a custom schema.MessagesTemplate not implementing schema.TemplateVariablesLister.
+func (t *DefaultChatTemplate) TemplateVariables() ([]string, error) {
+	vars := make(map[string]bool)
+	for _, template := range t.templates {
+		lister, ok := template.(schema.TemplateVariablesLister)
+		if !ok {
+			return nil, fmt.Errorf("%w: template of type %T", schema.ErrTemplateVariablesUnsupported, template)
+		}
+		vs, err := lister.TemplateVariables(t.formatType)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vs {
+			vars[v] = true
+		}
+	}
+
+	ret := make([]string, 0, len(vars))
+	for v := range vars {
+		ret = append(ret, v)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
 // GetType returns the type of the chat template (Default).
 func (t *DefaultChatTemplate) GetType() string {
 	return "Default"
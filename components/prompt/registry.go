@@ -0,0 +1,265 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrTemplateNotFound is returned by Registry.Get when no template of the name and version is registered.
+var ErrTemplateNotFound = errors.New("chat template not found")
+
+// Registry resolves chat templates by name and version, e.g. backed by a prompt management service,
+// so that the prompts used by a graph can be swapped without recompiling it, see FromRegistry.
+type Registry interface {
+	// Get returns the template of the name and version, the current version of the name if version is empty.
+	Get(ctx context.Context, name, version string) (ChatTemplate, error)
+}
+
+// MemoryRegistry is an in-memory Registry, safe for concurrent use.
+type MemoryRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]*versionedTemplates
+}
+
+type versionedTemplates struct {
+	versions map[string]ChatTemplate
+	order    []string // in registration order
+	current  string
+}
+
+// NewMemoryRegistry creates an empty MemoryRegistry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{templates: make(map[string]*versionedTemplates)}
+}
+
+// Register adds the template of the name and version, which replaces the registered one of the same version,
+// and becomes the current version of the name.
+func (r *MemoryRegistry) Register(name, version string, tpl ChatTemplate) error {
+	if name == "" || version == "" {
+		return errors.New("name and version of chat template are required")
+	}
+	if tpl == nil {
+		return fmt.Errorf("chat template %s@%s is nil", name, version)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vt, ok := r.templates[name]
+	if !ok {
+		vt = &versionedTemplates{versions: make(map[string]ChatTemplate)}
+		r.templates[name] = vt
+	}
+	if _, ok = vt.versions[version]; !ok {
+		vt.order = append(vt.order, version)
+	}
+	vt.versions[version] = tpl
+	vt.current = version
+
+	return nil
+}
+
+// SetCurrent makes the registered version the current version of the name, e.g. to roll back a prompt.
+func (r *MemoryRegistry) SetCurrent(name, version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vt, ok := r.templates[name]
+	if !ok || vt.versions[version] == nil {
+		return fmt.Errorf("%w: %s@%s", ErrTemplateNotFound, name, version)
+	}
+	vt.current = version
+	return nil
+}
+
+// Remove removes the version of the name, and the latest registered version left becomes the current one
+// if the removed version was.
+func (r *MemoryRegistry) Remove(name, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vt, ok := r.templates[name]
+	if !ok || vt.versions[version] == nil {
+		return
+	}
+	delete(vt.versions, version)
+	for i, v := range vt.order {
+		if v == version {
+			vt.order = append(vt.order[:i:i], vt.order[i+1:]...)
+			break
+		}
+	}
+	if len(vt.order) == 0 {
+		delete(r.templates, name)
+		return
+	}
+	if vt.current == version {
+		vt.current = vt.order[len(vt.order)-1]
+	}
+}
+
+// Versions returns the registered versions of the name in registration order, and the current version.
+func (r *MemoryRegistry) Versions(name string) (versions []string, current string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vt, ok := r.templates[name]
+	if !ok {
+		return nil, ""
+	}
+	return append([]string(nil), vt.order...), vt.current
+}
+
+// Get implements Registry.
+func (r *MemoryRegistry) Get(_ context.Context, name, version string) (ChatTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vt, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if version == "" {
+		version = vt.current
+	}
+	tpl, ok := vt.versions[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s", ErrTemplateNotFound, name, version)
+	}
+	return tpl, nil
+}
+
+// RegistryTemplateOption is the option of FromRegistry.
+type RegistryTemplateOption func(*RegistryChatTemplate)
+
+// WithRegistryVersion pins the version of the template, instead of the current version of the registry.
+func WithRegistryVersion(version string) RegistryTemplateOption {
+	return func(t *RegistryChatTemplate) {
+		t.version = version
+	}
+}
+
+// WithRegistryVersionSelector selects the version of the template for each run, e.g. by the user id in the variables for A/B testing.
+// Returning empty means the version pinned by WithRegistryVersion, or the current version of the registry.
+func WithRegistryVersionSelector(selector func(ctx context.Context, vs map[string]any) string) RegistryTemplateOption {
+	return func(t *RegistryChatTemplate) {
+		t.selector = selector
+	}
+}
+
+type registryOptions struct {
+	version string
+}
+
+// WithTemplateVersion is the call option of RegistryChatTemplate choosing the version of the template for the run,
+// which takes precedence over the version selector and the pinned version.
+func WithTemplateVersion(version string) Option {
+	return WrapImplSpecificOptFn(func(o *registryOptions) {
+		o.version = version
+	})
+}
+
+// RegistryChatTemplate is a ChatTemplate resolving the template by name from a Registry at each Format,
+// so that it picks up the templates registered after the graph is compiled.
+type RegistryChatTemplate struct {
+	registry Registry
+	name     string
+	version  string
+	selector func(ctx context.Context, vs map[string]any) string
+}
+
+// FromRegistry creates a RegistryChatTemplate resolving the template of the name from the registry at run time.
+// e.g.
+//
+//	registry := prompt.NewMemoryRegistry()
+//	_ = registry.Register("qa", "v1", prompt.FromMessages(schema.FString, schema.UserMessage("{question}")))
+//	// in chain, or graph
+//	chain.AppendChatTemplate(prompt.FromRegistry(registry, "qa"))
+//	// later, takes effect on the next run
+//	_ = registry.Register("qa", "v2", prompt.FromMessages(schema.FString, schema.SystemMessage("be brief"), schema.UserMessage("{question}")))
+func FromRegistry(registry Registry, name string, opts ...RegistryTemplateOption) *RegistryChatTemplate {
+	t := &RegistryChatTemplate{
+		registry: registry,
+		name:     name,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Format resolves the template from the registry and formats it with the variables.
+func (t *RegistryChatTemplate) Format(ctx context.Context, vs map[string]any, opts ...Option) (result []*schema.Message, err error) {
+	version := GetImplSpecificOptions(&registryOptions{}, opts...).version
+	if version == "" && t.selector != nil {
+		version = t.selector(ctx, vs)
+	}
+	if version == "" {
+		version = t.version
+	}
+
+	tpl, err := t.registry.Get(ctx, t.name, version)
+	if err != nil {
+		return nil, fmt.Errorf("get chat template %s from registry fail: %w", t.name, err)
+	}
+
+	if components.IsCallbacksEnabled(tpl) {
+		return tpl.Format(ctx, vs, opts...)
+	}
+
+	extra := map[string]any{"template_name": t.name, "template_version": version}
+	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfPrompt)
+	ctx = callbacks.OnStart(ctx, &CallbackInput{
+		Variables: vs,
+		Extra:     extra,
+	})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	result, err = tpl.Format(ctx, vs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = callbacks.OnEnd(ctx, &CallbackOutput{
+		Result: result,
+		Extra:  extra,
+	})
+
+	return result, nil
+}
+
+// GetType returns the type of the chat template (Registry).
+func (t *RegistryChatTemplate) GetType() string {
+	return "Registry"
+}
+
+// IsCallbacksEnabled checks if the callbacks are enabled for the chat template.
+func (t *RegistryChatTemplate) IsCallbacksEnabled() bool {
+	return true
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+type plainTemplate struct {
+	content string
+}
+
+func (p *plainTemplate) Format(_ context.Context, vs map[string]any, _ ...Option) ([]*schema.Message, error) {
+	return []*schema.Message{schema.UserMessage(p.content + vs["q"].(string))}, nil
+}
+
+func TestRegistryChatTemplate(t *testing.T) {
+	ctx := context.Background()
+	vs := map[string]any{"q": "hi"}
+
+	registry := NewMemoryRegistry()
+	assert.Error(t, registry.Register("qa", "", &plainTemplate{}))
+	assert.NoError(t, registry.Register("qa", "v1", FromMessages(schema.FString, schema.UserMessage("v1 {q}"))))
+
+	tpl := FromRegistry(registry, "qa")
+	msgs, err := tpl.Format(ctx, vs)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 hi", msgs[0].Content)
+
+	// hot swap
+	assert.NoError(t, registry.Register("qa", "v2", &plainTemplate{content: "v2 "}))
+	msgs, err = tpl.Format(ctx, vs)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2 hi", msgs[0].Content)
+	versions, current := registry.Versions("qa")
+	assert.Equal(t, []string{"v1", "v2"}, versions)
+	assert.Equal(t, "v2", current)
+
+	// call option over selector over pinned version
+	pinned := FromRegistry(registry, "qa", WithRegistryVersion("v1"), WithRegistryVersionSelector(func(ctx context.Context, vs map[string]any) string {
+		if vs["user"] == "b" {
+			return "v2"
+		}
+		return ""
+	}))
+	msgs, err = pinned.Format(ctx, vs)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 hi", msgs[0].Content)
+	msgs, err = pinned.Format(ctx, map[string]any{"q": "hi", "user": "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v2 hi", msgs[0].Content)
+	msgs, err = pinned.Format(ctx, map[string]any{"q": "hi", "user": "b"}, WithTemplateVersion("v1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 hi", msgs[0].Content)
+
+	// the callbacks of the template not enabling them are triggered by the registry template
+	var extra map[string]any
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		extra = ConvCallbackInput(input).Extra
+		return ctx
+	}).Build()
+	cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+	_, err = tpl.Format(cbCtx, vs)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"template_name": "qa", "template_version": ""}, extra)
+
+	// roll back
+	assert.NoError(t, registry.SetCurrent("qa", "v1"))
+	msgs, err = tpl.Format(ctx, vs)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1 hi", msgs[0].Content)
+	assert.Error(t, registry.SetCurrent("qa", "v3"))
+
+	registry.Remove("qa", "v1")
+	_, current = registry.Versions("qa")
+	assert.Equal(t, "v2", current)
+	registry.Remove("qa", "v2")
+	_, err = tpl.Format(ctx, vs)
+	assert.True(t, errors.Is(err, ErrTemplateNotFound))
+	_, err = pinned.Format(ctx, vs)
+	assert.True(t, errors.Is(err, ErrTemplateNotFound))
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retriever
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+// Fields to deduplicate the documents by, see WithDedupeBy.
+const (
+	DedupeByID      = "id"
+	DedupeByContent = "content"
+)
+
+// FilterDocuments enforces the Filter, ScoreThreshold, DedupeBy and MaxTokens options on the retrieved documents,
+// so that retrievers ignoring these options behave the same as those supporting them.
+// It's applied to the output of the retriever node in graph, and can be called by implementations as well.
+// The filters are applied in order:
+//   - Filter: documents whose metadata doesn't match the filter are removed.
+//   - ScoreThreshold: documents whose Score() is less than the threshold are removed, documents without a score are kept.
+//   - DedupeBy: documents with the same value of the field are removed except the first one, documents without the field are kept.
+//   - MaxTokens: documents are kept in order until the total tokens exceed the limit,
+//     tokens are counted by tokenizer.Approximate unless WithTokenCounter is given.
+func FilterDocuments(docs []*schema.Document, opts ...Option) []*schema.Document {
+	o := GetCommonOptions(nil, opts...)
+	if o.Filter == nil && o.ScoreThreshold == nil && o.DedupeBy == nil && o.MaxTokens == nil {
+		return docs
+	}
+
+	counter := o.TokenCounter
+	if counter == nil {
+		counter = approximateTokens
+	}
+
+	var (
+		ret    = make([]*schema.Document, 0, len(docs))
+		seen   = make(map[string]bool)
+		tokens int
+	)
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if o.Filter != nil && !o.Filter.Match(doc.MetaData) {
+			continue
+		}
+		if o.ScoreThreshold != nil && doc.HasScore() && doc.Score() < *o.ScoreThreshold {
+			continue
+		}
+		if o.DedupeBy != nil {
+			if key, ok := dedupeKey(doc, *o.DedupeBy); ok {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+		}
+		if o.MaxTokens != nil {
+			tokens += counter(doc)
+			if tokens > *o.MaxTokens {
+				break
+			}
+		}
+		ret = append(ret, doc)
+	}
+
+	return ret
+}
+
+func approximateTokens(doc *schema.Document) int {
+	// never fails
+	n, _ := tokenizer.Approximate.CountTokens(context.Background(), doc.Content)
+	return n
+}
+
+func dedupeKey(doc *schema.Document, field string) (string, bool) {
+	switch field {
+	case DedupeByID:
+		return doc.ID, true
+	case DedupeByContent:
+		return doc.Content, true
+	default:
+		v, ok := doc.MetaData[field]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retriever
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestFilterDocuments(t *testing.T) {
+	docs := []*schema.Document{
+		(&schema.Document{ID: "1", Content: "hello", MetaData: map[string]any{"source": "a"}}).WithScore(0.9),
+		(&schema.Document{ID: "2", Content: "hello", MetaData: map[string]any{"source": "b"}}).WithScore(0.3),
+		(&schema.Document{ID: "3", Content: "world", MetaData: map[string]any{"source": "a"}}).WithScore(0.8),
+		(&schema.Document{ID: "4", Content: "eino"}).WithScore(0.7),
+	}
+	ids := func(docs []*schema.Document) []string {
+		ret := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			ret = append(ret, doc.ID)
+		}
+		return ret
+	}
+
+	t.Run("no filter", func(t *testing.T) {
+		assert.Equal(t, docs, FilterDocuments(docs, WithTopK(1)))
+	})
+
+	t.Run("score threshold", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "3", "4"}, ids(FilterDocuments(docs, WithScoreThreshold(0.5))))
+		// documents without a score are kept
+		unscored := append([]*schema.Document{{ID: "0", Content: "none"}}, docs...)
+		assert.Equal(t, []string{"0", "1", "3", "4"}, ids(FilterDocuments(unscored, WithScoreThreshold(0.5))))
+	})
+
+	t.Run("dedupe", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "3", "4"}, ids(FilterDocuments(docs, WithDedupeBy(DedupeByContent))))
+		// documents without the metadata are kept
+		assert.Equal(t, []string{"1", "2", "4"}, ids(FilterDocuments(docs, WithDedupeBy("source"))))
+	})
+
+	t.Run("max tokens", func(t *testing.T) {
+		// "hello" and "world" are approximated as 2 tokens each
+		assert.Equal(t, []string{"1", "2"}, ids(FilterDocuments(docs, WithMaxTokens(4))))
+		assert.Equal(t, []string{"1"}, ids(FilterDocuments(docs, WithMaxTokens(12),
+			WithTokenCounter(func(doc *schema.Document) int { return 10 }))))
+	})
+
+	t.Run("metadata filter", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "3"}, ids(FilterDocuments(docs, WithFilter(schema.Eq("source", "a")))))
+		assert.Equal(t, []string{"2", "4"}, ids(FilterDocuments(docs, WithFilter(schema.Ne("source", "a")))))
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "3"}, ids(FilterDocuments(docs,
+			WithScoreThreshold(0.5), WithDedupeBy(DedupeByContent), WithMaxTokens(4))))
+	})
+}
@@ -16,7 +16,10 @@
 
 package retriever
 
-import "github.com/cloudwego/eino/components/embedding"
+import (
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
 
 // Options is the options for the retriever.
 type Options struct {
@@ -27,6 +30,7 @@ type Options struct {
 	// TopK is the top k for the retriever, which means the top number of documents to retrieve.
 	TopK *int
 	// ScoreThreshold is the score threshold for the retriever, eg 0.5 means the score of the document must be greater than 0.5.
+	// FilterDocuments only applies it to the documents carrying a score, see schema.Document.HasScore.
 	ScoreThreshold *float64
 	// Embedding is the embedder for the retriever, which is used to embed the query for retrieval	.
 	Embedding embedding.Embedder
@@ -34,6 +38,16 @@ type Options struct {
 	// DSLInfo is the dsl info for the retriever, which is used to retrieve the documents from the retriever.
 	// viking only
 	DSLInfo map[string]interface{}
+
+	// DedupeBy is the field to deduplicate the documents by, see WithDedupeBy.
+	DedupeBy *string
+	// MaxTokens is the max total tokens of the documents, see WithMaxTokens.
+	MaxTokens *int
+	// TokenCounter counts the tokens of a document for MaxTokens, see WithTokenCounter.
+	TokenCounter func(doc *schema.Document) int
+
+	// Filter is the filter on the document metadata, see WithFilter.
+	Filter *schema.Filter
 }
 
 // WithIndex wraps the index option.
@@ -90,6 +104,48 @@ func WithDSLInfo(dsl map[string]any) Option {
 	}
 }
 
+// WithDedupeBy wraps the dedupe by option, the documents with the same value of the field are deduplicated, keeping the first one.
+// field is DedupeByID, DedupeByContent, or any other key of the document metadata.
+func WithDedupeBy(field string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.DedupeBy = &field
+		},
+	}
+}
+
+// WithMaxTokens wraps the max tokens option, documents are kept in order until the total tokens exceed maxTokens.
+// Tokens are counted by the TokenCounter, see WithTokenCounter.
+func WithMaxTokens(maxTokens int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.MaxTokens = &maxTokens
+		},
+	}
+}
+
+// WithTokenCounter wraps the token counter option, which counts the tokens of a document for WithMaxTokens.
+// By default, the tokens of the document content estimated by tokenizer.Approximate.
+func WithTokenCounter(counter func(doc *schema.Document) int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.TokenCounter = counter
+		},
+	}
+}
+
+// WithFilter wraps the metadata filter option, which retriever implementations translate to the syntax of their stores.
+// e.g.
+//
+//	docs, err := r.Retrieve(ctx, query, retriever.WithFilter(schema.And(schema.Eq("lang", "go"), schema.Gte("year", 2024))))
+func WithFilter(filter *schema.Filter) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Filter = filter
+		},
+	}
+}
+
 // Option is the call option for Retriever component.
 type Option struct {
 	apply func(opts *Options)
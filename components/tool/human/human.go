@@ -0,0 +1,170 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package human provides a tool asking the human a question, e.g. to let a ReAct agent ask clarifying questions.
+// Calling the tool interrupts the graph, which is resumed with the answer supplied by Answer.
+// Configure the graph with a compose.CheckPointStore so that the wait can span processes.
+package human
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolName is the default name of the tool.
+const ToolName = "ask_human"
+
+const defaultDesc = "Ask the human a question when more information or a confirmation is required to proceed, " +
+	"and get the answer. Only ask when the information can not be obtained otherwise."
+
+func init() {
+	schema.RegisterName[*Question]("_eino_human_tool_question")
+}
+
+// Config is the config of the human tool.
+type Config struct {
+	// Name is the name of the tool, default to ToolName.
+	Name string
+	// Desc is the description of the tool for the model, optional.
+	Desc string
+	// FormatAnswer formats the answer into the result of the tool, optional, default to the answer as is.
+	FormatAnswer func(ctx context.Context, q *Question, answer string) (string, error)
+}
+
+// Question is the arguments of the tool generated by the model,
+// which is also the info of the interrupt, so that the application can present it to the human.
+type Question struct {
+	// Question is the question to ask.
+	Question string `json:"question"`
+	// Options are the suggested answers, optional.
+	Options []string `json:"options,omitempty"`
+}
+
+// PendingQuestion is a question waiting for the answer.
+type PendingQuestion struct {
+	// ID is the interrupt id to supply the answer to, by Answer.
+	ID string
+	*Question
+}
+
+// NewTool creates the human tool.
+// e.g.
+//
+//	askHuman, _ := human.NewTool(ctx, nil)
+//	agent, _ := react.NewAgent(ctx, &react.AgentConfig{
+//		ToolCallingModel: cm,
+//		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{askHuman}},
+//	})
+//
+//	_, err := agent.Generate(ctx, msgs, agent.WithComposeOptions(compose.WithCheckPointID(sessionID)))
+//	for _, q := range human.ExtractQuestions(err) {
+//		ctx = human.Answer(ctx, q.ID, askTheUser(q.Question))
+//	}
+//	out, err := agent.Generate(ctx, msgs, agent.WithComposeOptions(compose.WithCheckPointID(sessionID)))
+func NewTool(_ context.Context, config *Config) (tool.InvokableTool, error) {
+	t := &humanTool{name: ToolName, desc: defaultDesc}
+	if config != nil {
+		if config.Name != "" {
+			t.name = config.Name
+		}
+		if config.Desc != "" {
+			t.desc = config.Desc
+		}
+		t.formatAnswer = config.FormatAnswer
+	}
+	return t, nil
+}
+
+type humanTool struct {
+	name         string
+	desc         string
+	formatAnswer func(ctx context.Context, q *Question, answer string) (string, error)
+}
+
+func (h *humanTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: h.name,
+		Desc: h.desc,
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"question": {
+				Type:     schema.String,
+				Desc:     "the question to ask the human",
+				Required: true,
+			},
+			"options": {
+				Type:     schema.Array,
+				ElemInfo: &schema.ParameterInfo{Type: schema.String},
+				Desc:     "the suggested answers for the human to choose from, if any",
+			},
+		}),
+	}, nil
+}
+
+func (h *humanTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	wasInterrupted, hasState, q := compose.GetInterruptState[*Question](ctx)
+	if !wasInterrupted || !hasState {
+		q = &Question{}
+		if err := sonic.UnmarshalString(argumentsInJSON, q); err != nil {
+			return "", fmt.Errorf("unmarshal question fail: %w", err)
+		}
+		if q.Question == "" {
+			return "", errors.New("question is empty")
+		}
+		return "", compose.StatefulInterrupt(ctx, q, q)
+	}
+
+	isResume, hasData, answer := compose.GetResumeContext[string](ctx)
+	if !isResume {
+		// another interrupt is resumed, keep waiting
+		return "", compose.StatefulInterrupt(ctx, q, q)
+	}
+	if !hasData {
+		return "", fmt.Errorf("no answer supplied to question: %s", q.Question)
+	}
+
+	if h.formatAnswer != nil {
+		return h.formatAnswer(ctx, q, answer)
+	}
+	return answer, nil
+}
+
+// ExtractQuestions returns the questions waiting for the answers, if err is an interrupt caused by the human tool.
+func ExtractQuestions(err error) []*PendingQuestion {
+	info, ok := compose.ExtractInterruptInfo(err)
+	if !ok {
+		return nil
+	}
+	var ret []*PendingQuestion
+	for _, ic := range info.InterruptContexts {
+		if q, ok_ := ic.Info.(*Question); ok_ && ic.IsRootCause {
+			ret = append(ret, &PendingQuestion{ID: ic.ID, Question: q})
+		}
+	}
+	return ret
+}
+
+// Answer prepares the context to resume the graph with the answer to the question of the id.
+// Call it for each of the questions to be answered in this run.
+func Answer(ctx context.Context, id string, answer string) context.Context {
+	return compose.ResumeWithData(ctx, id, answer)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package human
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type memStore struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func (s *memStore) Get(_ context.Context, id string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[id]
+	return v, ok, nil
+}
+
+func (s *memStore) Set(_ context.Context, id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[id] = data
+	return nil
+}
+
+type echoTool struct{}
+
+func (echoTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo"}, nil
+}
+
+func (echoTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	return argumentsInJSON, nil
+}
+
+// newRunnable builds the graph from scratch, as if in a new process.
+func newRunnable(t *testing.T, store compose.CheckPointStore) compose.Runnable[*schema.Message, []*schema.Message] {
+	ctx := context.Background()
+	askHuman, err := NewTool(ctx, &Config{
+		FormatAnswer: func(ctx context.Context, q *Question, answer string) (string, error) {
+			return q.Question + " " + answer, nil
+		},
+	})
+	require.NoError(t, err)
+	tn, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: []tool.BaseTool{askHuman, echoTool{}}})
+	require.NoError(t, err)
+
+	g := compose.NewGraph[*schema.Message, []*schema.Message]()
+	require.NoError(t, g.AddToolsNode("tools", tn))
+	require.NoError(t, g.AddEdge(compose.START, "tools"))
+	require.NoError(t, g.AddEdge("tools", compose.END))
+	r, err := g.Compile(ctx, compose.WithCheckPointStore(store))
+	require.NoError(t, err)
+	return r
+}
+
+func TestHumanTool(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{m: map[string][]byte{}}
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: ToolName, Arguments: `{"question":"which color?","options":["red","blue"]}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "echo", Arguments: `"hi"`}},
+	})
+
+	askHuman, err := NewTool(ctx, nil)
+	require.NoError(t, err)
+	info, err := askHuman.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ToolName, info.Name)
+
+	_, err = newRunnable(t, store).Invoke(ctx, input, compose.WithCheckPointID("session"))
+	questions := ExtractQuestions(err)
+	require.Len(t, questions, 1)
+	assert.Equal(t, &Question{Question: "which color?", Options: []string{"red", "blue"}}, questions[0].Question)
+
+	// keeps waiting if not answered
+	_, err = newRunnable(t, store).Invoke(ctx, input, compose.WithCheckPointID("session"))
+	questions = ExtractQuestions(err)
+	require.Len(t, questions, 1)
+
+	out, err := newRunnable(t, store).Invoke(Answer(ctx, questions[0].ID, "blue"), input, compose.WithCheckPointID("session"))
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, "which color? blue", out[0].Content)
+	assert.Equal(t, `"hi"`, out[1].Content)
+
+	assert.Nil(t, ExtractQuestions(nil))
+	_, err = askHuman.InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "question is empty")
+}
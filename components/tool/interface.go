@@ -41,3 +41,24 @@ type StreamableTool interface {
 
 	StreamableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (*schema.StreamReader[string], error)
 }
+
+// AttachmentTool the tool exchanging file attachments with ToolsNode besides the arguments in JSON format,
+// so binary files need not be base64-encoded into the arguments or the result.
+// Implementing it declares that the tool accepts and produces attachments, which takes precedence over
+// InvokableTool and StreamableTool in ToolsNode.
+type AttachmentTool interface {
+	BaseTool
+
+	// InvokableRunWithAttachments call function with arguments in JSON format and the attachments passed to ToolsNode,
+	// the attachments of the result are put on the tool message.
+	InvokableRunWithAttachments(ctx context.Context, argumentsInJSON string, attachments []schema.Attachment,
+		opts ...Option) (*AttachmentResult, error)
+}
+
+// AttachmentResult is the result of AttachmentTool.
+type AttachmentResult struct {
+	// Content is the text result of the tool.
+	Content string
+	// Attachments are the files produced by the tool.
+	Attachments []schema.Attachment
+}
@@ -16,6 +16,13 @@
 
 package tool
 
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/credential"
+)
+
 // Option defines call option for InvokableTool or StreamableTool component, which is part of component interface signature.
 // Each tool implementation could define its own options struct and option funcs within its own package,
 // then wrap the impl specific option funcs into this type, before passing to InvokableRun or StreamableRun.
@@ -76,3 +83,16 @@ func GetImplSpecificOptions[T any](base *T, opts ...Option) *T {
 
 	return base
 }
+
+// ResolveCredential returns the credential resolved by the credential.Provider carried by ctx for the tool of the name,
+// or nil without error if there is no provider, or the provider has no credential for the tool,
+// in which case the tool uses its own credential.
+// e.g.
+//
+//	func (t *searchTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+//		cred, err := tool.ResolveCredential(ctx, "search")
+//		...
+//	}
+func ResolveCredential(ctx context.Context, name string) (*credential.Credential, error) {
+	return credential.Resolve(ctx, &credential.Request{Component: components.ComponentOfTool, Name: name})
+}
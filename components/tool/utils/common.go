@@ -17,7 +17,12 @@
 package utils
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
 )
 
 func marshalString(resp any) (string, error) {
@@ -26,3 +31,91 @@ func marshalString(resp any) (string, error) {
 	}
 	return sonic.MarshalString(resp)
 }
+
+func hideParams(js *jsonschema.Schema, names []string) {
+	if len(names) == 0 || js == nil {
+		return
+	}
+	for _, name := range names {
+		if js.Properties != nil {
+			js.Properties.Delete(name)
+		}
+		for i := 0; i < len(js.Required); i++ {
+			if js.Required[i] == name {
+				js.Required = append(js.Required[:i], js.Required[i+1:]...)
+				i--
+			}
+		}
+	}
+}
+
+const (
+	// rawSchemaTagName is the struct tag replacing the inferred json schema of a field with the raw one.
+	rawSchemaTagName = "jsonschema_raw"
+	// optionalTagValue in the jsonschema tag marks a field as not required, even without omitempty in the json tag.
+	optionalTagValue = "optional"
+)
+
+// schemaModifier applies the `jsonschema_raw` and `jsonschema:"optional"` tags before the user-defined modifier,
+// and keeps the first error, as the modifier of the reflector can't return one.
+type schemaModifier struct {
+	custom SchemaModifierFn
+	err    error
+}
+
+func (m *schemaModifier) modify(jsonTagName string, t reflect.Type, tag reflect.StructTag, js *jsonschema.Schema) {
+	if raw, ok := tag.Lookup(rawSchemaTagName); ok && len(raw) > 0 && jsonTagName != rootSchemaName {
+		rs := &jsonschema.Schema{}
+		if err := sonic.UnmarshalString(raw, rs); err != nil {
+			if m.err == nil {
+				m.err = fmt.Errorf("unmarshal raw json schema of field[%s] fail: %w", jsonTagName, err)
+			}
+		} else {
+			if len(rs.Description) == 0 {
+				rs.Description = js.Description
+			}
+			*js = *rs
+		}
+	}
+
+	if st := derefType(t); st.Kind() == reflect.Struct && len(js.Required) > 0 {
+		for _, f := range reflect.VisibleFields(st) {
+			if !hasTagValue(f.Tag.Get("jsonschema"), optionalTagValue) {
+				continue
+			}
+			name := f.Name
+			if jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ","); len(jsonName) > 0 {
+				name = jsonName
+			}
+			for i := 0; i < len(js.Required); i++ {
+				if js.Required[i] == name {
+					js.Required = append(js.Required[:i], js.Required[i+1:]...)
+					i--
+				}
+			}
+		}
+	}
+
+	if m.custom != nil {
+		m.custom(jsonTagName, t, tag, js)
+	}
+}
+
+// rootSchemaName is the jsonTagName passed to the modifier for the root struct.
+const rootSchemaName = "_root"
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func hasTagValue(tag, value string) bool {
+	for _, v := range strings.Split(tag, ",") {
+		if strings.TrimSpace(v) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -33,6 +33,8 @@ type toolOptions struct {
 	um         UnmarshalArguments
 	m          MarshalOutput
 	scModifier SchemaModifierFn
+	hidden     []string
+	rawSchema  string
 }
 
 // Option is the option func for the tool.
@@ -70,6 +72,29 @@ func WithSchemaModifier(modifier SchemaModifierFn) Option {
 	}
 }
 
+// WithHiddenParams removes the given top-level parameters from the inferred tool schema, so the model never sees them.
+// The go struct keeps the fields, and their values are expected to be injected at execution time,
+// e.g. by compose.ToolsNodeConfig.HiddenParams. Names are the json tag names of the fields.
+func WithHiddenParams(names ...string) Option {
+	return func(o *toolOptions) {
+		o.hidden = append(o.hidden, names...)
+	}
+}
+
+// WithRawJSONSchema uses the raw JSON schema as the parameters of the inferred tool, instead of inferring it from the go struct,
+// as an escape hatch for the schemas which can't be expressed by struct tags.
+// The go struct is still used to unmarshal the arguments, so it should match the schema.
+// To replace the schema of a single field, use the `jsonschema_raw` tag on the field instead, e.g.
+//
+//	type Input struct {
+//		Filter map[string]any `json:"filter" jsonschema_raw:"{\"type\":\"object\",\"properties\":{\"op\":{\"type\":\"string\"}}}"`
+//	}
+func WithRawJSONSchema(raw string) Option {
+	return func(o *toolOptions) {
+		o.rawSchema = raw
+	}
+}
+
 func getToolOptions(opt ...Option) *toolOptions {
 	opts := &toolOptions{
 		um: nil,
@@ -36,6 +36,9 @@ type InvokeFunc[T, D any] func(ctx context.Context, input T) (output D, err erro
 type OptionableInvokeFunc[T, D any] func(ctx context.Context, input T, opts ...tool.Option) (output D, err error)
 
 // InferTool creates an InvokableTool from a given function by inferring the ToolInfo from the function's request parameters.
+// Nested structs, slices and maps are inferred recursively. Fields are described by the struct tags, e.g.
+// `jsonschema:"description=...,enum=a,enum=b,required"`, and marked optional by omitempty in the json tag or `jsonschema:"optional"`.
+// The schema of a field can be replaced by the `jsonschema_raw` tag, and the whole schema by WithRawJSONSchema.
 // End-user can pass a SchemaCustomizerFn in opts to customize the go struct tag parsing process, overriding default behavior.
 func InferTool[T, D any](toolName, toolDesc string, i InvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
 	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
@@ -46,6 +49,38 @@ func InferTool[T, D any](toolName, toolDesc string, i InvokeFunc[T, D], opts ...
 	return NewTool(ti, i, opts...), nil
 }
 
+// InferTypedTool creates an InvokableTool like InferTool, and declares the result schema inferred from O in ToolInfo.ReturnsOneOf,
+// so that planners and validators know what the tool returns. O is marshaled to the content of the tool message as JSON,
+// and downstream nodes can parse it back by ParseToolResult.
+// The options applying to the parameters, e.g. WithRawJSONSchema and WithHiddenParams, don't apply to the result schema.
+func InferTypedTool[I, O any](toolName, toolDesc string, i InvokeFunc[I, O], opts ...Option) (tool.InvokableTool, error) {
+	ti, err := goStruct2ToolInfo[I](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ti.ReturnsOneOf, err = goStruct2ParamsOneOf[O](WithSchemaModifier(getToolOptions(opts...).scModifier))
+	if err != nil {
+		return nil, fmt.Errorf("infer result schema of tool[%s] fail: %w", toolName, err)
+	}
+
+	return NewTool(ti, i, opts...), nil
+}
+
+// ParseToolResult parses the content of the tool message returned by a tool created by InferTypedTool or NewTool with result type O.
+// A string result is returned as is, as it isn't marshaled as JSON by the tool.
+func ParseToolResult[O any](content string) (O, error) {
+	result := generic.NewInstance[O]()
+	if s, ok := any(&result).(*string); ok {
+		*s = content
+		return result, nil
+	}
+	if err := sonic.UnmarshalString(content, &result); err != nil {
+		return result, fmt.Errorf("unmarshal tool result fail: %w", err)
+	}
+	return result, nil
+}
+
 // InferOptionableTool creates an InvokableTool from a given function by inferring the ToolInfo from the function's request parameters, with tool option.
 func InferOptionableTool[T, D any](toolName, toolDesc string, i OptionableInvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
 	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
@@ -83,14 +118,27 @@ func goStruct2ToolInfo[T any](toolName, toolDesc string, opts ...Option) (*schem
 func goStruct2ParamsOneOf[T any](opts ...Option) (*schema.ParamsOneOf, error) {
 	options := getToolOptions(opts...)
 
-	r := &jsonschema.Reflector{
-		Anonymous:      true,
-		DoNotReference: true,
-		SchemaModifier: jsonschema.SchemaModifierFn(options.scModifier),
-	}
+	var js *jsonschema.Schema
+	if len(options.rawSchema) > 0 {
+		js = &jsonschema.Schema{}
+		if err := sonic.UnmarshalString(options.rawSchema, js); err != nil {
+			return nil, fmt.Errorf("unmarshal raw json schema fail: %w", err)
+		}
+	} else {
+		m := &schemaModifier{custom: options.scModifier}
+		r := &jsonschema.Reflector{
+			Anonymous:      true,
+			DoNotReference: true,
+			SchemaModifier: m.modify,
+		}
 
-	js := r.Reflect(generic.NewInstance[T]())
-	js.Version = ""
+		js = r.Reflect(generic.NewInstance[T]())
+		if m.err != nil {
+			return nil, m.err
+		}
+		js.Version = ""
+	}
+	hideParams(js, options.hidden)
 
 	paramsOneOf := schema.NewParamsOneOfByJSONSchema(js)
 
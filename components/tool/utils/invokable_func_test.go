@@ -24,6 +24,7 @@ import (
 
 	"github.com/eino-contrib/jsonschema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
 	"github.com/cloudwego/eino/components/tool"
@@ -403,3 +404,168 @@ func TestEnumTag(t *testing.T) {
 	_, err = goStruct2ParamsOneOf[testEnumStruct3]()
 	assert.NoError(t, err)
 }
+
+func TestWithHiddenParams(t *testing.T) {
+	type req struct {
+		Query  string `json:"query" jsonschema:"required"`
+		UserID string `json:"user_id" jsonschema:"required"`
+	}
+
+	tl, err := InferTool("query", "query something", func(ctx context.Context, in *req) (string, error) {
+		return in.Query + ":" + in.UserID, nil
+	}, WithHiddenParams("user_id"))
+	assert.NoError(t, err)
+
+	info, err := tl.Info(context.Background())
+	assert.NoError(t, err)
+	js, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+	_, ok := js.Properties.Get("user_id")
+	assert.False(t, ok)
+	_, ok = js.Properties.Get("query")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"query"}, js.Required)
+
+	// hidden params injected at execution time are still unmarshalled
+	content, err := tl.InvokableRun(context.Background(), `{"query":"q","user_id":"u1"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "q:u1", content)
+}
+
+type schemaInferAddress struct {
+	City    string `json:"city" jsonschema:"description=the city name"`
+	ZipCode string `json:"zip_code,omitempty"`
+}
+
+type schemaInferInput struct {
+	Name      string                        `json:"name" jsonschema_description:"the user name"`
+	Level     string                        `json:"level" jsonschema:"enum=low,enum=high"`
+	Note      string                        `json:"note" jsonschema:"optional"`
+	Address   schemaInferAddress            `json:"address"`
+	Contacts  []*schemaInferAddress         `json:"contacts"`
+	Labels    map[string]string             `json:"labels,omitempty"`
+	Filter    map[string]any                `json:"filter" jsonschema_raw:"{\"type\":\"object\",\"properties\":{\"op\":{\"type\":\"string\"}}}"`
+	Nested    map[string]schemaInferAddress `json:"nested,omitempty"`
+	Internal  string                        `json:"-"`
+	unexposed string
+}
+
+func TestInferNestedSchema(t *testing.T) {
+	ctx := context.Background()
+
+	tl, err := InferTool("search", "search users", func(ctx context.Context, in *schemaInferInput) (string, error) {
+		return in.Address.City, nil
+	})
+	require.NoError(t, err)
+	info, err := tl.Info(ctx)
+	require.NoError(t, err)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"name", "level", "address", "contacts", "filter"}, js.Required)
+
+	name, _ := js.Properties.Get("name")
+	assert.Equal(t, "the user name", name.Description)
+	level, _ := js.Properties.Get("level")
+	assert.Equal(t, []any{"low", "high"}, level.Enum)
+
+	address, _ := js.Properties.Get("address")
+	assert.Equal(t, "object", address.Type)
+	assert.Equal(t, []string{"city"}, address.Required)
+	city, _ := address.Properties.Get("city")
+	assert.Equal(t, "the city name", city.Description)
+
+	contacts, _ := js.Properties.Get("contacts")
+	assert.Equal(t, "array", contacts.Type)
+	assert.Equal(t, "object", contacts.Items.Type)
+	_, ok := contacts.Items.Properties.Get("zip_code")
+	assert.True(t, ok)
+
+	labels, _ := js.Properties.Get("labels")
+	assert.Equal(t, "object", labels.Type)
+	assert.Equal(t, "string", labels.AdditionalProperties.Type)
+
+	filter, _ := js.Properties.Get("filter")
+	assert.Equal(t, "object", filter.Type)
+	op, ok := filter.Properties.Get("op")
+	assert.True(t, ok)
+	assert.Equal(t, "string", op.Type)
+
+	_, ok = js.Properties.Get("Internal")
+	assert.False(t, ok)
+	_, ok = js.Properties.Get("unexposed")
+	assert.False(t, ok)
+
+	out, err := tl.InvokableRun(ctx, `{"name":"n","address":{"city":"c"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "c", out)
+
+	type badRaw struct {
+		Field string `json:"field" jsonschema_raw:"{bad"`
+	}
+	_, err = GoStruct2ParamsOneOf[badRaw]()
+	assert.Error(t, err)
+}
+
+func TestWithRawJSONSchema(t *testing.T) {
+	p, err := GoStruct2ParamsOneOf[schemaInferInput](
+		WithRawJSONSchema(`{"type":"object","properties":{"q":{"type":"string"},"name":{"type":"string"}},"required":["q","name"]}`),
+		WithHiddenParams("name"))
+	require.NoError(t, err)
+	js, err := p.ToJSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"q"}, js.Required)
+	_, ok := js.Properties.Get("q")
+	assert.True(t, ok)
+
+	_, err = GoStruct2ParamsOneOf[schemaInferInput](WithRawJSONSchema(`{`))
+	assert.Error(t, err)
+}
+
+func TestInferTypedTool(t *testing.T) {
+	ctx := context.Background()
+
+	type result struct {
+		Users []string `json:"users" jsonschema:"description=the matched user names"`
+		Total int      `json:"total"`
+	}
+
+	tl, err := InferTypedTool("search", "search users", func(ctx context.Context, in *schemaInferAddress) (*result, error) {
+		return &result{Users: []string{in.City}, Total: 1}, nil
+	})
+	require.NoError(t, err)
+
+	info, err := tl.Info(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, info.ReturnsOneOf)
+	js, err := info.ReturnsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users", "total"}, js.Required)
+	users, ok := js.Properties.Get("users")
+	assert.True(t, ok)
+	assert.Equal(t, "the matched user names", users.Description)
+
+	out, err := tl.InvokableRun(ctx, `{"city":"c"}`)
+	require.NoError(t, err)
+	parsed, err := ParseToolResult[*result](out)
+	require.NoError(t, err)
+	assert.Equal(t, &result{Users: []string{"c"}, Total: 1}, parsed)
+
+	st, err := InferTypedTool("echo", "echo", func(ctx context.Context, in *schemaInferAddress) (string, error) {
+		return in.City, nil
+	})
+	require.NoError(t, err)
+	info, err = st.Info(ctx)
+	require.NoError(t, err)
+	js, err = info.ReturnsOneOf.ToJSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, "string", js.Type)
+	out, err = st.InvokableRun(ctx, `{"city":"c"}`)
+	require.NoError(t, err)
+	s, err := ParseToolResult[string](out)
+	require.NoError(t, err)
+	assert.Equal(t, "c", s)
+
+	_, err = ParseToolResult[*result]("not json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// NewWebSearchTool wraps a WebSearcher into an InvokableTool with the standard web search ToolInfo,
+// so that different search providers are interchangeable for agents.
+func NewWebSearchTool(s tool.WebSearcher, opts ...Option) tool.InvokableTool {
+	return NewTool(tool.WebSearchToolInfo(), s.Search, opts...)
+}
+
+// NewWebFetchTool wraps a WebFetcher into an InvokableTool with the standard web fetch ToolInfo,
+// so that different fetcher implementations are interchangeable for agents.
+func NewWebFetchTool(f tool.WebFetcher, opts ...Option) tool.InvokableTool {
+	return NewTool(tool.WebFetchToolInfo(), f.Fetch, opts...)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+type mockWebSearcher struct{}
+
+func (m *mockWebSearcher) Search(_ context.Context, req *tool.WebSearchRequest) (*tool.WebSearchResponse, error) {
+	return &tool.WebSearchResponse{Results: []*tool.WebSearchResult{
+		{Title: req.Query, URL: "https://example.com"},
+		{Title: req.Query, URL: "https://example.com/docs", Snippet: "docs", Content: "the docs"},
+	}}, nil
+}
+
+type mockWebFetcher struct{}
+
+func (m *mockWebFetcher) Fetch(_ context.Context, req *tool.WebFetchRequest) (*tool.WebFetchResponse, error) {
+	if req.URL == "https://example.com/private" {
+		return nil, tool.ErrWebFetchDisallowed
+	}
+	return &tool.WebFetchResponse{URL: req.URL, StatusCode: 200, Title: "Example", Content: "hello"}, nil
+}
+
+func TestWebTools(t *testing.T) {
+	ctx := context.Background()
+
+	st := NewWebSearchTool(&mockWebSearcher{})
+	info, err := st.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, tool.WebSearchToolName, info.Name)
+
+	out, err := st.InvokableRun(ctx, `{"query":"eino","max_results":3}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"results":[{"title":"eino","url":"https://example.com"},`+
+		`{"title":"eino","url":"https://example.com/docs","snippet":"docs","content":"the docs"}]}`, out)
+
+	ft := NewWebFetchTool(&mockWebFetcher{})
+	info, err = ft.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, tool.WebFetchToolName, info.Name)
+	js, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tool.WebFetchParamURL}, js.Required)
+
+	out, err = ft.InvokableRun(ctx, `{"url":"https://example.com"}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"url":"https://example.com","status_code":200,"title":"Example","content":"hello"}`, out)
+
+	_, err = ft.InvokableRun(ctx, `{"url":"https://example.com/private"}`)
+	assert.ErrorIs(t, err, tool.ErrWebFetchDisallowed)
+}
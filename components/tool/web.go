@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Standard names of the built-in web tools.
+// Prompts can rely on these names regardless of which provider implements the tool.
+const (
+	WebSearchToolName = "web_search"
+	WebFetchToolName  = "web_fetch"
+)
+
+// Standard argument names of the built-in web tools, i.e. the json keys of WebSearchRequest and WebFetchRequest.
+const (
+	WebSearchParamQuery      = "query"
+	WebSearchParamMaxResults = "max_results"
+	WebFetchParamURL         = "url"
+	WebFetchParamMaxBytes    = "max_bytes"
+)
+
+var (
+	// ErrWebFetchDisallowed is returned by WebFetcher when the target url is disallowed by the site's robots.txt.
+	ErrWebFetchDisallowed = errors.New("web fetch disallowed by robots.txt")
+	// ErrWebFetchTooLarge is returned by WebFetcher when the content exceeds the size limit and can not be truncated.
+	ErrWebFetchTooLarge = errors.New("web fetch content exceeds size limit")
+)
+
+// WebSearchRequest is the arguments of the web search tool.
+type WebSearchRequest struct {
+	// Query is the search query.
+	Query string `json:"query"`
+	// MaxResults is the max number of results to return, 0 means the implementation's default.
+	MaxResults int `json:"max_results,omitempty"`
+}
+
+// WebSearchResult is a single result of the web search tool, normalized across the providers.
+type WebSearchResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+	// Content is the main content of the page as text, for the providers returning it along with the results,
+	// which saves the agent from fetching the page.
+	Content string `json:"content,omitempty"`
+}
+
+// WebSearchResponse is the result of the web search tool.
+type WebSearchResponse struct {
+	Results []*WebSearchResult `json:"results"`
+}
+
+// WebFetchRequest is the arguments of the web fetch tool.
+type WebFetchRequest struct {
+	// URL is the url of the page to fetch.
+	URL string `json:"url"`
+	// MaxBytes limits the size of the returned content, 0 means WebFetchConfig.MaxContentBytes.
+	// Implementations should never return more than WebFetchConfig.MaxContentBytes regardless of this value.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+}
+
+// WebFetchResponse is the result of the web fetch tool.
+type WebFetchResponse struct {
+	// URL is the final url after redirects.
+	URL         string `json:"url"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	// Title is the title of the page, if any.
+	Title string `json:"title,omitempty"`
+	// Content is the page content, usually converted to plain text or markdown.
+	Content string `json:"content"`
+	// Truncated reports whether Content has been cut to the size limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// WebFetchConfig is the common config every WebFetcher implementation is expected to honor.
+type WebFetchConfig struct {
+	// RespectRobots makes the fetcher check robots.txt and return ErrWebFetchDisallowed for disallowed urls.
+	RespectRobots bool
+	// Timeout bounds a single fetch, 0 means no timeout beyond the context's deadline.
+	Timeout time.Duration
+	// MaxContentBytes is the hard limit of the returned content size, 0 means unlimited.
+	MaxContentBytes int64
+	// UserAgent is the user agent sent by the fetcher, and the one checked against robots.txt.
+	UserAgent string
+}
+
+// WebSearcher is the contract of web search providers.
+type WebSearcher interface {
+	Search(ctx context.Context, req *WebSearchRequest) (*WebSearchResponse, error)
+}
+
+// WebFetcher is the contract of web page fetchers.
+type WebFetcher interface {
+	Fetch(ctx context.Context, req *WebFetchRequest) (*WebFetchResponse, error)
+}
+
+// WebSearchToolInfo returns the standard ToolInfo of the web search tool.
+func WebSearchToolInfo() *schema.ToolInfo {
+	return &schema.ToolInfo{
+		Name: WebSearchToolName,
+		Desc: "Search the web and return a list of results with title, url, snippet, and content if available.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			WebSearchParamQuery: {
+				Type:     schema.String,
+				Desc:     "the search query",
+				Required: true,
+			},
+			WebSearchParamMaxResults: {
+				Type: schema.Integer,
+				Desc: "the max number of results to return",
+			},
+		}),
+	}
+}
+
+// WebFetchToolInfo returns the standard ToolInfo of the web fetch tool.
+func WebFetchToolInfo() *schema.ToolInfo {
+	return &schema.ToolInfo{
+		Name: WebFetchToolName,
+		Desc: "Fetch a web page by url and return its title and content as text.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			WebFetchParamURL: {
+				Type:     schema.String,
+				Desc:     "the url of the page to fetch",
+				Required: true,
+			},
+			WebFetchParamMaxBytes: {
+				Type: schema.Integer,
+				Desc: "the max size of the returned content in bytes",
+			},
+		}),
+	}
+}
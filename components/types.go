@@ -51,12 +51,14 @@ func IsCallbacksEnabled(i any) bool {
 type Component string
 
 const (
-	ComponentOfPrompt      Component = "ChatTemplate"
-	ComponentOfChatModel   Component = "ChatModel"
-	ComponentOfEmbedding   Component = "Embedding"
-	ComponentOfIndexer     Component = "Indexer"
-	ComponentOfRetriever   Component = "Retriever"
-	ComponentOfLoader      Component = "Loader"
-	ComponentOfTransformer Component = "DocumentTransformer"
-	ComponentOfTool        Component = "Tool"
+	ComponentOfPrompt       Component = "ChatTemplate"
+	ComponentOfChatModel    Component = "ChatModel"
+	ComponentOfEmbedding    Component = "Embedding"
+	ComponentOfIndexer      Component = "Indexer"
+	ComponentOfRetriever    Component = "Retriever"
+	ComponentOfLoader       Component = "Loader"
+	ComponentOfTransformer  Component = "DocumentTransformer"
+	ComponentOfTool         Component = "Tool"
+	ComponentOfSpeechToText Component = "SpeechToText"
+	ComponentOfTextToSpeech Component = "TextToSpeech"
 )
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
@@ -47,6 +48,8 @@ type GraphBranch struct {
 	endNodes   map[string]bool
 	idx        int // used to distinguish branches in parallel
 	noDataFlow bool
+	fallback   bool
+	paradigms  runnableParadigm
 }
 
 // GetEndNode returns the all end nodes of the branch.
@@ -81,29 +84,36 @@ func newGraphBranch[T any](r *runnablePacker[T, []string, any], endNodes map[str
 		inputType:     generic.TypeOf[T](),
 		genericHelper: newGenericHelper[T, T](),
 		endNodes:      endNodes,
+		paradigms:     r.paradigms,
 	}
 }
 
+// NewGraphMultiBranch creates a branch which activates several end nodes at the same time, e.g. to fan a query out to multiple specialists.
+// The condition returns the set of end nodes to activate, every returned key is activated regardless of its value, in the order of the keys.
+// When the branch's input is a stream, it is copied for every activated end node automatically.
+// e.g.
+//
+//	condition := func(ctx context.Context, in string) (map[string]bool, error) {
+//		return map[string]bool{"weather_expert": true, "news_expert": true}, nil
+//	}
+//	endNodes := map[string]bool{"weather_expert": true, "news_expert": true, "sports_expert": true}
+//	branch := compose.NewGraphMultiBranch(condition, endNodes)
+//
+//	graph.AddBranch("key_of_node_before_branch", branch)
 func NewGraphMultiBranch[T any](condition GraphMultiBranchCondition[T], endNodes map[string]bool) *GraphBranch {
 	condRun := func(ctx context.Context, in T, opts ...any) ([]string, error) {
 		ends, err := condition(ctx, in)
 		if err != nil {
 			return nil, err
 		}
-		ret := make([]string, 0, len(ends))
-		for end := range ends {
-			if !endNodes[end] {
-				return nil, fmt.Errorf("branch invocation returns unintended end node: %s", end)
-			}
-			ret = append(ret, end)
-		}
-
-		return ret, nil
+		return selectedEndNodes(ends, endNodes)
 	}
 
 	return newGraphBranch(newRunnablePacker(condRun, nil, nil, nil, false), endNodes)
 }
 
+// NewStreamGraphMultiBranch creates a multi-target branch like NewGraphMultiBranch, with a condition reading the input stream.
+// The condition only needs to read enough of the stream to make the decision, and the activated end nodes still receive the complete stream.
 func NewStreamGraphMultiBranch[T any](condition StreamGraphMultiBranchCondition[T],
 	endNodes map[string]bool) *GraphBranch {
 
@@ -113,19 +123,26 @@ func NewStreamGraphMultiBranch[T any](condition StreamGraphMultiBranchCondition[
 			return nil, err
 		}
 
-		ret := make([]string, 0, len(ends))
-		for end := range ends {
-			if !endNodes[end] {
-				return nil, fmt.Errorf("branch invocation returns unintended end node: %s", end)
-			}
-			ret = append(ret, end)
-		}
-		return ret, nil
+		return selectedEndNodes(ends, endNodes)
 	}
 
 	return newGraphBranch(newRunnablePacker(nil, nil, condRun, nil, false), endNodes)
 }
 
+// selectedEndNodes returns the activated end nodes, i.e. every key of ends, in a deterministic order.
+// endNodes is nil for chain branches, whose end nodes are validated when the chain is compiled.
+func selectedEndNodes(ends map[string]bool, endNodes map[string]bool) ([]string, error) {
+	ret := make([]string, 0, len(ends))
+	for end := range ends {
+		if endNodes != nil && !endNodes[end] {
+			return nil, fmt.Errorf("branch invocation returns unintended end node: %s", end)
+		}
+		ret = append(ret, end)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
 // NewGraphBranch creates a new graph branch.
 // It is used to determine the next node based on the condition.
 // e.g.
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// ExperimentVariantKey is the key of the RunInfo.Metadata of the nodes run after an experiment branch,
+// whose value is the variant assigned to the run, i.e. the end node chosen by the branch.
+// The key is suffixed by "." and the experiment name if it's set by WithExperimentName, e.g. "experiment_variant.prompt_v2".
+const ExperimentVariantKey = "experiment_variant"
+
+// ExperimentOption is the option of NewExperimentBranch.
+type ExperimentOption func(*experimentOptions)
+
+type experimentOptions struct {
+	name string
+}
+
+// WithExperimentName sets the name of the experiment, which salts the hash so that the experiments in a graph are assigned independently,
+// and distinguishes their variants in RunInfo.Metadata.
+func WithExperimentName(name string) ExperimentOption {
+	return func(o *experimentOptions) {
+		o.name = name
+	}
+}
+
+// NewExperimentBranch creates a branch routing the traffic to the end nodes by the weights in splits, e.g. for A/B testing a new prompt
+// against the old one, each variant being a subgraph.
+// The weights are relative, e.g. {"old": 90, "new": 10} routes 10% of the runs to "new".
+// The assignment is deterministic by the key returned by hashBy, e.g. the user id, so that a user always sees the same variant,
+// and it's random if hashBy is nil or returns an empty key.
+// The variant is tagged in RunInfo.Metadata of the nodes run after the branch by ExperimentVariantKey for downstream analysis,
+// and can be read by GetExperimentVariant.
+// e.g.
+//
+//	hashBy := func(ctx context.Context, in map[string]any) string {
+//		return in["user_id"].(string)
+//	}
+//	branch := compose.NewExperimentBranch(map[string]float64{"old_prompt": 90, "new_prompt": 10}, hashBy,
+//		compose.WithExperimentName("prompt_v2"))
+//
+//	graph.AddBranch("key_of_node_before_branch", branch)
+func NewExperimentBranch[T any](splits map[string]float64, hashBy func(ctx context.Context, input T) string, opts ...ExperimentOption) *GraphBranch {
+	o := &experimentOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	variants, cumulative, splitsErr := normalizeSplits(splits)
+
+	endNodes := make(map[string]bool, len(splits))
+	for k := range splits {
+		endNodes[k] = true
+	}
+
+	return NewGraphBranch(func(ctx context.Context, in T) (string, error) {
+		if splitsErr != nil {
+			return "", splitsErr
+		}
+
+		var key string
+		if hashBy != nil {
+			key = hashBy(ctx, in)
+		}
+		point := experimentPoint(o.name, key)
+
+		variant := variants[len(variants)-1]
+		for i, c := range cumulative {
+			if point < c {
+				variant = variants[i]
+				break
+			}
+		}
+
+		if ev, ok := ctx.Value(experimentVariantsKey{}).(*experimentVariants); ok {
+			ev.set(o.name, variant)
+		}
+		return variant, nil
+	}, endNodes)
+}
+
+// GetExperimentVariant returns the variant assigned by the experiment branch of the name in the current run,
+// the name is empty for the experiment without WithExperimentName.
+func GetExperimentVariant(ctx context.Context, name string) (string, bool) {
+	ev, ok := ctx.Value(experimentVariantsKey{}).(*experimentVariants)
+	if !ok {
+		return "", false
+	}
+	ev.mu.RLock()
+	defer ev.mu.RUnlock()
+	v, ok := ev.variants[name]
+	return v, ok
+}
+
+func normalizeSplits(splits map[string]float64) ([]string, []float64, error) {
+	if len(splits) == 0 {
+		return nil, nil, errors.New("experiment branch has no splits")
+	}
+
+	variants := make([]string, 0, len(splits))
+	var total float64
+	for k, w := range splits {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, nil, fmt.Errorf("weight of experiment variant %s is invalid: %v", k, w)
+		}
+		total += w
+		variants = append(variants, k)
+	}
+	if total == 0 {
+		return nil, nil, errors.New("total weight of experiment splits is zero")
+	}
+	sort.Strings(variants) // the assignment must not depend on the map order
+
+	cumulative := make([]float64, len(variants))
+	var acc float64
+	for i, k := range variants {
+		acc += splits[k] / total
+		cumulative[i] = acc
+	}
+	return variants, cumulative, nil
+}
+
+// experimentPoint maps the key to a point in [0, 1).
+func experimentPoint(salt, key string) float64 {
+	if key == "" {
+		return rand.Float64()
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(salt))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	// fnv doesn't spread similar keys across the high bits, mix them by the finalizer of murmur3
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return float64(x>>11) / (1 << 53)
+}
+
+type experimentVariantsKey struct{}
+
+// experimentVariants holds the variants assigned in a run, shared by the nested graphs.
+type experimentVariants struct {
+	mu       sync.RWMutex
+	variants map[string]string
+}
+
+func withExperimentVariants(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(experimentVariantsKey{}).(*experimentVariants); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, experimentVariantsKey{}, &experimentVariants{})
+}
+
+func (ev *experimentVariants) set(name, variant string) {
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	if ev.variants == nil {
+		ev.variants = make(map[string]string)
+	}
+	ev.variants[name] = variant
+}
+
+// tag returns the metadata with the assigned variants added, without modifying the given one.
+func (ev *experimentVariants) tag(metadata map[string]string) map[string]string {
+	ev.mu.RLock()
+	defer ev.mu.RUnlock()
+	if len(ev.variants) == 0 {
+		return metadata
+	}
+
+	ret := make(map[string]string, len(metadata)+len(ev.variants))
+	for k, v := range metadata {
+		ret[k] = v
+	}
+	for name, variant := range ev.variants {
+		key := ExperimentVariantKey
+		if name != "" {
+			key += "." + name
+		}
+		ret[key] = variant
+	}
+	return ret
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/internal/core"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GraphFallbackBranchCondition is the condition type for the fallback branch,
+// which returns the candidate end nodes ranked by priority.
+type GraphFallbackBranchCondition[T any] func(ctx context.Context, in T) (candidates []string, err error)
+
+// StreamGraphFallbackBranchCondition is the condition type for the stream fallback branch,
+// which returns the candidate end nodes ranked by priority.
+type StreamGraphFallbackBranchCondition[T any] func(ctx context.Context, in *schema.StreamReader[T]) (candidates []string, err error)
+
+// NewGraphFallbackBranch creates a branch which runs the first of the ranked candidates,
+// and falls through to the next candidate with the same input when the node returns an error,
+// e.g. routing to a primary provider first and to a backup provider if the primary one fails.
+// The run fails with the error of the last candidate if all of them fail.
+// Only errors returned by the node itself trigger the fallback, errors read from its output stream don't,
+// and an interrupt is never treated as a failure.
+// The candidates must share the same input type, END can not be a candidate,
+// and the fallback branch is only supported in AnyPredecessor trigger mode.
+// e.g.
+//
+//	condition := func(ctx context.Context, in []*schema.Message) ([]string, error) {
+//		return []string{"primary_model", "backup_model"}, nil
+//	}
+//	endNodes := map[string]bool{"primary_model": true, "backup_model": true}
+//	branch := compose.NewGraphFallbackBranch(condition, endNodes)
+//
+//	graph.AddBranch("key_of_node_before_branch", branch)
+func NewGraphFallbackBranch[T any](condition GraphFallbackBranchCondition[T], endNodes map[string]bool) *GraphBranch {
+	condRun := func(ctx context.Context, in T, opts ...any) ([]string, error) {
+		candidates, err := condition(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return rankedCandidates(candidates, endNodes)
+	}
+
+	b := newGraphBranch(newRunnablePacker(condRun, nil, nil, nil, false), endNodes)
+	b.fallback = true
+	return b
+}
+
+// NewStreamGraphFallbackBranch creates a fallback branch like NewGraphFallbackBranch, with a condition reading the input stream.
+// The input stream is copied, so a fallback candidate still receives the complete stream.
+func NewStreamGraphFallbackBranch[T any](condition StreamGraphFallbackBranchCondition[T], endNodes map[string]bool) *GraphBranch {
+	condRun := func(ctx context.Context, in *schema.StreamReader[T], opts ...any) ([]string, error) {
+		candidates, err := condition(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return rankedCandidates(candidates, endNodes)
+	}
+
+	b := newGraphBranch(newRunnablePacker(nil, nil, condRun, nil, false), endNodes)
+	b.fallback = true
+	return b
+}
+
+func rankedCandidates(candidates []string, endNodes map[string]bool) ([]string, error) {
+	ret := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		if !endNodes[c] {
+			return nil, fmt.Errorf("branch invocation returns unintended end node: %s", c)
+		}
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		ret = append(ret, c)
+	}
+	if len(ret) == 0 {
+		return nil, errors.New("fallback branch returns no candidate")
+	}
+	return ret, nil
+}
+
+func (g *graph) validateFallbackBranch(startNode string, branch *GraphBranch, runType graphRunType) error {
+	if runType == runTypeDAG {
+		return fmt.Errorf("fallback branch of node[%s] is not supported in %s mode", startNode, runType)
+	}
+	var first string
+	for end := range branch.endNodes {
+		if end == END {
+			return fmt.Errorf("fallback branch of node[%s] can not fall back to END", startNode)
+		}
+		if first == "" {
+			first = end
+			continue
+		}
+		if g.getNodeInputType(end) != g.getNodeInputType(first) {
+			return fmt.Errorf("candidates of fallback branch of node[%s] have different input types: [%s]%s, [%s]%s",
+				startNode, first, g.getNodeInputType(first), end, g.getNodeInputType(end))
+		}
+	}
+	return nil
+}
+
+// setFallbacks records the candidates to fall back to if node fails in the current run.
+func (c *channelManager) setFallbacks(node string, candidates []string) {
+	if c.fallbacks == nil {
+		c.fallbacks = make(map[string][]string)
+	}
+	c.fallbacks[node] = candidates
+}
+
+// keepFallbackInputs keeps a copy of the inputs of the tasks which have fallback candidates.
+func keepFallbackInputs(tasks []*task, isStream bool, cm *channelManager) {
+	for _, t := range tasks {
+		if len(cm.fallbacks[t.nodeKey]) == 0 {
+			continue
+		}
+		if isStream {
+			if sr, ok := t.input.(streamReader); ok {
+				copies := sr.copy(2)
+				t.input, t.fallbackInput = copies[0], copies[1]
+				continue
+			}
+		}
+		t.fallbackInput = t.input
+	}
+}
+
+// resolveFallbackTasks replaces the failed tasks which have fallback candidates with tasks of their next candidates.
+func (r *runner) resolveFallbackTasks(ctx context.Context, completedTasks []*task, isStream bool,
+	cm *channelManager, optMap map[string][]any) ([]*task, []*task, error) {
+	if len(cm.fallbacks) == 0 {
+		return completedTasks, nil, nil
+	}
+
+	remaining := make([]*task, 0, len(completedTasks))
+	var fallbackTasks []*task
+	for _, t := range completedTasks {
+		candidates, ok := cm.fallbacks[t.nodeKey]
+		if !ok {
+			remaining = append(remaining, t)
+			continue
+		}
+		delete(cm.fallbacks, t.nodeKey)
+
+		if t.err == nil || isNodeInterrupt(t.err) {
+			if sr, ok_ := t.fallbackInput.(streamReader); ok_ {
+				sr.close()
+			}
+			remaining = append(remaining, t)
+			continue
+		}
+
+		next := candidates[0]
+		if len(candidates) > 1 {
+			cm.setFallbacks(next, candidates[1:])
+		}
+		nts, err := r.createTasks(ctx, map[string]any{next: t.fallbackInput}, optMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		keepFallbackInputs(nts, isStream, cm)
+		fallbackTasks = append(fallbackTasks, nts...)
+	}
+	return remaining, fallbackTasks, nil
+}
+
+// isNodeInterrupt reports whether err is an interrupt of the node, in the same way as resolveInterruptCompletedTasks.
+func isNodeInterrupt(err error) bool {
+	if isSubGraphInterrupt(err) != nil {
+		return true
+	}
+	ire := &core.InterruptSignal{}
+	return errors.As(err, &ire)
+}
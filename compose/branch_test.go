@@ -18,11 +18,14 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -130,3 +133,260 @@ func TestStreamMultiBranch(t *testing.T) {
 		"2": "start",
 	}, result)
 }
+
+func TestMultiBranchSelection(t *testing.T) {
+	ctx := context.Background()
+	emptyLambda := InvokableLambda(func(ctx context.Context, input string) (output string, err error) { return input, nil })
+	cond := func(ctx context.Context, in string) (map[string]bool, error) {
+		return map[string]bool{"1": true, "2": false}, nil
+	}
+
+	build := func(branch *GraphBranch) Runnable[string, map[string]any] {
+		g := NewGraph[string, map[string]any]()
+		for _, key := range []string{"1", "2", "3"} {
+			assert.NoError(t, g.AddLambdaNode(key, emptyLambda, WithOutputKey(key)))
+			assert.NoError(t, g.AddEdge(key, END))
+		}
+		assert.NoError(t, g.AddBranch(START, branch))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		return r
+	}
+	endNodes := map[string]bool{"1": true, "2": true, "3": true}
+
+	// every returned key is activated, even with a false value
+	result, err := build(NewGraphMultiBranch(cond, endNodes)).Invoke(ctx, "start")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"1": "start", "2": "start"}, result)
+
+	buildChain := func(branch *ChainBranch) Runnable[string, map[string]any] {
+		c := NewChain[string, map[string]any]()
+		for _, key := range []string{"1", "2", "3"} {
+			branch.AddLambda(key, emptyLambda, WithOutputKey(key))
+		}
+		c.AppendBranch(branch)
+		r, err := c.Compile(ctx)
+		assert.NoError(t, err)
+		return r
+	}
+
+	result, err = buildChain(NewChainMultiBranch(cond)).Invoke(ctx, "start")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"1": "start", "2": "start"}, result)
+}
+
+func TestMultiBranchBroadcast(t *testing.T) {
+	ctx := context.Background()
+
+	specialist := func(name string) *Lambda {
+		return TransformableLambda(func(ctx context.Context, input *schema.StreamReader[string]) (*schema.StreamReader[[]string], error) {
+			return schema.StreamReaderWithConvert(input, func(s string) ([]string, error) {
+				return []string{name + ":" + s}, nil
+			}), nil
+		})
+	}
+
+	g := NewGraph[string, []string]()
+	assert.NoError(t, g.AddLambdaNode("weather", specialist("weather")))
+	assert.NoError(t, g.AddLambdaNode("news", specialist("news")))
+	assert.NoError(t, g.AddLambdaNode("sports", specialist("sports")))
+	assert.NoError(t, g.AddJoinNode("join", NewSliceAppendMergeStrategy[string]()))
+	assert.NoError(t, g.AddBranch(START, NewStreamGraphMultiBranch(func(ctx context.Context, in *schema.StreamReader[string]) (map[string]bool, error) {
+		defer in.Close()
+		first, err := in.Recv()
+		if err != nil {
+			return nil, err
+		}
+		ends := map[string]bool{"weather": true, "news": true}
+		if first == "goal" {
+			ends["sports"] = true
+		}
+		return ends, nil
+	}, map[string]bool{"weather": true, "news": true, "sports": true})))
+	assert.NoError(t, g.AddEdge("weather", "join"))
+	assert.NoError(t, g.AddEdge("news", "join"))
+	assert.NoError(t, g.AddEdge("sports", "join"))
+	assert.NoError(t, g.AddEdge("join", END))
+
+	r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	assert.NoError(t, err)
+
+	// every activated specialist receives the complete input stream
+	out, err := r.Transform(ctx, schema.StreamReaderFromArray([]string{"rain", "fall"}))
+	assert.NoError(t, err)
+	var result []string
+	for {
+		chunk, err := out.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		result = append(result, chunk...)
+	}
+	assert.Equal(t, []string{"news:rain", "news:fall", "weather:rain", "weather:fall"}, result)
+}
+
+func TestFallbackBranch(t *testing.T) {
+	var calls []string
+	newProvider := func(name string, fail bool) *Lambda {
+		return InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			calls = append(calls, name)
+			if fail {
+				return "", errors.New(name + " unavailable")
+			}
+			return name + ":" + input, nil
+		})
+	}
+
+	build := func(failPrimary, failSecondary bool) Runnable[string, string] {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("primary", newProvider("primary", failPrimary)))
+		assert.NoError(t, g.AddLambdaNode("secondary", newProvider("secondary", failSecondary)))
+		assert.NoError(t, g.AddLambdaNode("last", newProvider("last", false)))
+		assert.NoError(t, g.AddBranch(START, NewGraphFallbackBranch(func(ctx context.Context, in string) ([]string, error) {
+			return []string{"primary", "secondary", "last"}, nil
+		}, map[string]bool{"primary": true, "secondary": true, "last": true})))
+		assert.NoError(t, g.AddEdge("primary", END))
+		assert.NoError(t, g.AddEdge("secondary", END))
+		assert.NoError(t, g.AddEdge("last", END))
+		r, err := g.Compile(context.Background())
+		assert.NoError(t, err)
+		return r
+	}
+
+	ctx := context.Background()
+
+	calls = nil
+	out, err := build(false, false).Invoke(ctx, "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "primary:hi", out)
+	assert.Equal(t, []string{"primary"}, calls)
+
+	calls = nil
+	out, err = build(true, true).Invoke(ctx, "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "last:hi", out)
+	assert.Equal(t, []string{"primary", "secondary", "last"}, calls)
+
+	calls = nil
+	sr, err := build(true, false).Stream(ctx, "hi")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "secondary:hi", out)
+	assert.Equal(t, []string{"primary", "secondary"}, calls)
+
+	// all candidates fail
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("primary", newProvider("primary", true)))
+	assert.NoError(t, g.AddLambdaNode("secondary", newProvider("secondary", true)))
+	assert.NoError(t, g.AddBranch(START, NewGraphFallbackBranch(func(ctx context.Context, in string) ([]string, error) {
+		return []string{"primary", "secondary"}, nil
+	}, map[string]bool{"primary": true, "secondary": true})))
+	assert.NoError(t, g.AddEdge("primary", END))
+	assert.NoError(t, g.AddEdge("secondary", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, "hi")
+	assert.ErrorContains(t, err, "secondary unavailable")
+
+	// not supported in AllPredecessor mode
+	g = NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("primary", newProvider("primary", false)))
+	assert.NoError(t, g.AddLambdaNode("secondary", newProvider("secondary", false)))
+	assert.NoError(t, g.AddBranch(START, NewGraphFallbackBranch(func(ctx context.Context, in string) ([]string, error) {
+		return []string{"primary", "secondary"}, nil
+	}, map[string]bool{"primary": true, "secondary": true})))
+	assert.NoError(t, g.AddEdge("primary", END))
+	assert.NoError(t, g.AddEdge("secondary", END))
+	_, err = g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestStreamFallbackBranch(t *testing.T) {
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("primary", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return "", errors.New("primary unavailable")
+	})))
+	assert.NoError(t, g.AddLambdaNode("backup", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return "backup:" + input, nil
+	})))
+	assert.NoError(t, g.AddBranch(START, NewStreamGraphFallbackBranch(func(ctx context.Context, in *schema.StreamReader[string]) ([]string, error) {
+		in.Close()
+		return []string{"primary", "backup"}, nil
+	}, map[string]bool{"primary": true, "backup": true})))
+	assert.NoError(t, g.AddEdge("primary", END))
+	assert.NoError(t, g.AddEdge("backup", END))
+	r, err := g.Compile(context.Background())
+	assert.NoError(t, err)
+
+	out, err := r.Transform(context.Background(), schema.StreamReaderFromArray([]string{"a", "b"}))
+	assert.NoError(t, err)
+	result, err := concatStreamReader(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "backup:ab", result)
+}
+
+func TestExperimentBranch(t *testing.T) {
+	ctx := context.Background()
+
+	newGraph := func(splits map[string]float64) Runnable[string, string] {
+		g := NewGraph[string, string]()
+		variant := func(name string) *Lambda {
+			return InvokableLambda(func(ctx context.Context, in string) (string, error) {
+				v, ok := GetExperimentVariant(ctx, "prompt")
+				assert.True(t, ok)
+				assert.Equal(t, name, v)
+				return name, nil
+			})
+		}
+		assert.NoError(t, g.AddLambdaNode("old", variant("old"), WithNodeName("old")))
+		assert.NoError(t, g.AddLambdaNode("new", variant("new"), WithNodeName("new")))
+		assert.NoError(t, g.AddBranch(START, NewExperimentBranch(splits, func(ctx context.Context, in string) string {
+			return in
+		}, WithExperimentName("prompt"))))
+		assert.NoError(t, g.AddEdge("old", END))
+		assert.NoError(t, g.AddEdge("new", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		return r
+	}
+
+	r := newGraph(map[string]float64{"old": 70, "new": 30})
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		out, err := r.Invoke(ctx, fmt.Sprintf("user_%d", i))
+		assert.NoError(t, err)
+		counts[out]++
+
+		// deterministic by the key
+		again, err := r.Invoke(ctx, fmt.Sprintf("user_%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, out, again)
+	}
+	assert.InDelta(t, 600, counts["new"], 100)
+	assert.Equal(t, 2000, counts["old"]+counts["new"])
+
+	var tagged string
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		if info.Name == "old" || info.Name == "new" {
+			tagged = info.Metadata[ExperimentVariantKey+".prompt"]
+			assert.Equal(t, "u", info.Metadata["user"])
+		}
+		return ctx
+	}).Build()
+	out, err := r.Invoke(ctx, "user_1", WithCallbacks(handler), WithRunMetadata(map[string]string{"user": "u"}))
+	assert.NoError(t, err)
+	assert.Equal(t, out, tagged)
+
+	r = newGraph(map[string]float64{"old": 0, "new": 1})
+	for i := 0; i < 10; i++ {
+		out, err = r.Invoke(ctx, fmt.Sprintf("user_%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", out)
+	}
+
+	r = newGraph(map[string]float64{"old": -1, "new": 1})
+	_, err = r.Invoke(ctx, "user")
+	assert.ErrorContains(t, err, "weight of experiment variant old is invalid")
+}
@@ -0,0 +1,307 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/schema"
+)
+
+// CancelReason describes why a graph run is canceled by CancelRun.
+type CancelReason string
+
+const (
+	// CancelReasonUserAborted means the run is aborted by the end user, e.g. clicking a stop button.
+	CancelReasonUserAborted CancelReason = "user_aborted"
+	// CancelReasonBudgetExceeded means the run exceeds its budget, e.g. tokens, cost or time.
+	CancelReasonBudgetExceeded CancelReason = "budget_exceeded"
+	// CancelReasonUpstreamDisconnect means the upstream caller has disconnected, e.g. the client closes the connection.
+	CancelReasonUpstreamDisconnect CancelReason = "upstream_disconnect"
+)
+
+// ErrRunCanceled is the sentinel of errors returned by a run which is canceled by CancelRun.
+// Use errors.Is(err, ErrRunCanceled) to check it, or errors.As with *RunCanceledError to get the reason.
+var ErrRunCanceled = errors.New("run canceled")
+
+// RunCanceledError is returned by a run which is canceled by CancelRun.
+// It's also the error received from the output stream after the run is canceled,
+// and the error passed to the OnError callbacks of the graph.
+type RunCanceledError struct {
+	Reason CancelReason
+	// Err is the original error caused by the cancellation, e.g. context.Canceled returned by a node.
+	Err error
+	// Partial is the concatenation of the chunks read from the output stream before the cancellation,
+	// only set for the error received from the output stream when there are such chunks.
+	// Use GetPartialOutput to get it typed.
+	Partial any
+}
+
+func (r *RunCanceledError) Error() string {
+	if r.Err == nil {
+		return fmt.Sprintf("run canceled, reason: %s", r.Reason)
+	}
+	return fmt.Sprintf("run canceled, reason: %s, error: %v", r.Reason, r.Err)
+}
+
+// Is reports whether the target is ErrRunCanceled.
+func (r *RunCanceledError) Is(target error) bool {
+	return target == ErrRunCanceled
+}
+
+func (r *RunCanceledError) Unwrap() error {
+	return r.Err
+}
+
+type runCancelKey struct{}
+
+type runCancel struct {
+	mu     sync.Mutex
+	reason *CancelReason
+	cancel context.CancelFunc
+	output partialOutput
+}
+
+// partialOutput tracks the chunks of the output stream received by the caller.
+type partialOutput interface {
+	get() (any, bool, error)
+}
+
+// streamOutput concatenates the chunks incrementally as they are received,
+// so only the partial output is kept instead of all the chunks of the stream.
+type streamOutput[T any] struct {
+	mu      sync.Mutex
+	partial T
+	has     bool
+	err     error
+}
+
+func (s *streamOutput[T]) add(chunk T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	if !s.has {
+		s.partial, s.has = chunk, true
+		return
+	}
+	partial, err := internal.ConcatItems([]T{s.partial, chunk})
+	if err != nil {
+		// the chunks can't be concatenated, there is no partial output
+		var zero T
+		s.partial, s.err = zero, err
+		return
+	}
+	s.partial = partial
+}
+
+func (s *streamOutput[T]) get() (any, bool, error) {
+	return s.concat()
+}
+
+func (s *streamOutput[T]) concat() (T, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero T
+	if s.err != nil {
+		return zero, false, s.err
+	}
+	return s.partial, s.has, nil
+}
+
+func (r *runCancel) getReason() (CancelReason, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reason == nil {
+		return "", false
+	}
+	return *r.reason, true
+}
+
+// WithRunCancel creates a context which can be canceled by CancelRun with a typed reason.
+// Use the returned context to invoke a graph, chain or workflow.
+// e.g.
+//
+//	ctx := compose.WithRunCancel(ctx)
+//	go func() {
+//		<-clientGone
+//		compose.CancelRun(ctx, compose.CancelReasonUpstreamDisconnect)
+//	}()
+//	_, err := runnable.Invoke(ctx, input)
+//	if reason, ok := compose.GetCancelReason(ctx); ok {
+//		// err matches ErrRunCanceled and carries the reason
+//	}
+func WithRunCancel(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	return context.WithValue(ctx, runCancelKey{}, &runCancel{cancel: cancel})
+}
+
+// CancelRun cancels the run using a context created by WithRunCancel, recording the reason.
+// The reason will be carried by the final error of the run, the error passed to callbacks and the error received from the output stream.
+// It returns false if ctx isn't created by WithRunCancel or the run has already been canceled.
+func CancelRun(ctx context.Context, reason CancelReason) bool {
+	rc, ok := ctx.Value(runCancelKey{}).(*runCancel)
+	if !ok {
+		return false
+	}
+
+	rc.mu.Lock()
+	if rc.reason != nil {
+		rc.mu.Unlock()
+		return false
+	}
+	rc.reason = &reason
+	rc.mu.Unlock()
+
+	rc.cancel()
+	return true
+}
+
+// GetCancelReason returns the reason passed to CancelRun, if the run of ctx has been canceled by it.
+func GetCancelReason(ctx context.Context) (CancelReason, bool) {
+	rc, ok := ctx.Value(runCancelKey{}).(*runCancel)
+	if !ok {
+		return "", false
+	}
+	return rc.getReason()
+}
+
+func wrapRunCanceledError(ctx context.Context, err error) error {
+	if err == nil || isInterruptError(err) || errors.Is(err, ErrRunCanceled) {
+		return err
+	}
+	reason, ok := GetCancelReason(ctx)
+	if !ok {
+		return err
+	}
+	return &RunCanceledError{Reason: reason, Err: err}
+}
+
+// streamWithCancelReason forwards the stream, and ends it with *RunCanceledError instead of the next chunk, error or EOF
+// once the run is canceled by CancelRun, as the stream may have been truncated by the cancellation.
+// The source stream is closed when the returned stream is closed or the run is canceled.
+// The chunks received by the caller are tracked, so that the partial output can be retrieved on cancellation.
+// Closing the returned stream with an error closes the source stream with the error as well.
+func streamWithCancelReason[T any](ctx context.Context, rc *runCancel, sr *schema.StreamReader[T]) *schema.StreamReader[T] {
+	nsr, sw := schema.Pipe[T](0)
+	out := &streamOutput[T]{}
+	rc.mu.Lock()
+	rc.output = out
+	rc.mu.Unlock()
+
+	go func() {
+		defer sw.Close()
+
+		for {
+			chunk, err := sr.Recv()
+			reason, canceled := rc.getReason()
+			if err == io.EOF && !canceled {
+				sr.Close()
+				return
+			}
+
+			if canceled {
+				var zero T
+				if err == nil || err == io.EOF {
+					err = ctx.Err()
+				}
+				rce := &RunCanceledError{Reason: reason, Err: err}
+				if partial, ok, cErr := out.concat(); cErr == nil && ok {
+					rce.Partial = partial
+				}
+				sr.CloseWithError(rce)
+				sw.Send(zero, rce)
+				return
+			}
+
+			if closed := sw.Send(chunk, err); closed {
+				if cErr := sw.ClosedError(); cErr != nil {
+					sr.CloseWithError(cErr)
+				} else {
+					sr.Close()
+				}
+				return
+			}
+		}
+	}()
+
+	// track the chunks in the goroutine of the caller, so they are visible once received
+	return schema.StreamReaderWithConvert(nsr, func(chunk T) (T, error) {
+		out.add(chunk)
+		return chunk, nil
+	})
+}
+
+// GetPartialOutput returns the partial output carried by the *RunCanceledError received from the output stream, see RunCanceledError.Partial.
+func GetPartialOutput[T any](err error) (T, bool) {
+	var rce *RunCanceledError
+	if errors.As(err, &rce) {
+		if p, ok := rce.Partial.(T); ok {
+			return p, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// AbortStream cancels the streaming run using ctx created by WithRunCancel, and closes its output stream sr.
+// It returns the concatenation of the chunks received from sr before the abort, and false if there is none,
+// e.g. for a "stop generating" button that still saves the partial message to the history:
+//
+//	ctx = compose.WithRunCancel(ctx)
+//	sr, err := runnable.Stream(ctx, input)
+//	// receive chunks from sr until the user clicks stop
+//	partial, ok, err := compose.AbortStream(ctx, sr, compose.CancelReasonUserAborted)
+//
+// The nodes still running receive the cancellation through ctx, and the senders of the streams being read by the run
+// can get the *RunCanceledError by schema.StreamWriter.ClosedError.
+func AbortStream[T any](ctx context.Context, sr *schema.StreamReader[T], reason CancelReason) (T, bool, error) {
+	var zero T
+	rc, ok := ctx.Value(runCancelKey{}).(*runCancel)
+	if !ok {
+		sr.Close()
+		return zero, false, errors.New("context isn't created by WithRunCancel")
+	}
+
+	CancelRun(ctx, reason)
+	reason, _ = rc.getReason()
+	sr.CloseWithError(&RunCanceledError{Reason: reason, Err: context.Canceled})
+
+	rc.mu.Lock()
+	out := rc.output
+	rc.mu.Unlock()
+	if out == nil {
+		return zero, false, nil
+	}
+
+	partial, ok, err := out.get()
+	if err != nil || !ok {
+		return zero, false, err
+	}
+	p, ok := partial.(T)
+	if !ok {
+		return zero, false, fmt.Errorf("unexpected partial output type, expected: %T, actual: %T", zero, partial)
+	}
+	return p, true, nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestCancelRun(t *testing.T) {
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	r, err := g.Compile(context.Background())
+	assert.NoError(t, err)
+
+	assert.False(t, CancelRun(context.Background(), CancelReasonUserAborted))
+
+	ctx := WithRunCancel(context.Background())
+	var cbErr error
+	handler := callbacks.NewHandlerBuilder().OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+		cbErr = err
+		return ctx
+	}).Build()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.True(t, CancelRun(ctx, CancelReasonBudgetExceeded))
+		assert.False(t, CancelRun(ctx, CancelReasonUserAborted))
+	}()
+	_, err = r.Invoke(ctx, "input", WithCallbacks(handler))
+	assert.ErrorIs(t, err, ErrRunCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	var rce *RunCanceledError
+	assert.True(t, errors.As(err, &rce))
+	assert.Equal(t, CancelReasonBudgetExceeded, rce.Reason)
+	assert.ErrorIs(t, cbErr, ErrRunCanceled)
+	reason, ok := GetCancelReason(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, CancelReasonBudgetExceeded, reason)
+}
+
+func TestCancelRunStream(t *testing.T) {
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+		sr, sw := schema.Pipe[string](0)
+		go func() {
+			defer sw.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Millisecond):
+					if sw.Send(input, nil) {
+						return
+					}
+				}
+			}
+		}()
+		return sr, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	r, err := g.Compile(context.Background())
+	assert.NoError(t, err)
+
+	ctx := WithRunCancel(context.Background())
+	sr, err := r.Stream(ctx, "input")
+	assert.NoError(t, err)
+	defer sr.Close()
+
+	chunk, err := sr.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "input", chunk)
+
+	CancelRun(ctx, CancelReasonUpstreamDisconnect)
+	for {
+		_, err = sr.Recv()
+		if err != nil {
+			break
+		}
+	}
+	assert.NotEqual(t, io.EOF, err)
+	var rce *RunCanceledError
+	assert.True(t, errors.As(err, &rce))
+	assert.Equal(t, CancelReasonUpstreamDisconnect, rce.Reason)
+	partial, ok := GetPartialOutput[string](err)
+	assert.True(t, ok)
+	assert.Equal(t, "input", partial[:5])
+}
+
+func TestAbortStream(t *testing.T) {
+	closedErr := make(chan error, 1)
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+		sr, sw := schema.Pipe[string](0)
+		go func() {
+			defer sw.Close()
+			for {
+				if sw.Send(input, nil) {
+					closedErr <- sw.ClosedError()
+					return
+				}
+			}
+		}()
+		return sr, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	r, err := g.Compile(context.Background())
+	assert.NoError(t, err)
+
+	_, _, err = AbortStream(context.Background(), schema.StreamReaderFromArray([]string{"a"}), CancelReasonUserAborted)
+	assert.Error(t, err)
+
+	ctx := WithRunCancel(context.Background())
+	sr, err := r.Stream(ctx, "ab")
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = sr.Recv()
+		assert.NoError(t, err)
+	}
+
+	partial, ok, err := AbortStream(ctx, sr, CancelReasonUserAborted)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ababab", partial)
+
+	select {
+	case err = <-closedErr:
+		assert.ErrorIs(t, err, ErrRunCanceled)
+	case <-time.After(time.Second):
+		t.Fatal("the node stream isn't closed")
+	}
+}
+
+func TestStreamOutputConcatIncrementally(t *testing.T) {
+	out := &streamOutput[map[string]any]{}
+	_, ok, err := out.concat()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	out.add(map[string]any{"a": "x"})
+	out.add(map[string]any{"a": "y", "b": 1})
+	out.add(map[string]any{"b": 2})
+	partial, ok, err := out.concat()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"a": "xy", "b": 2}, partial)
+
+	// the chunks which can't be concatenated leave no partial output
+	out.add(map[string]any{"a": 1})
+	_, ok, err = out.concat()
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
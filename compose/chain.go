@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/cloudwego/eino/components/audio"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
@@ -203,6 +204,42 @@ func (c *Chain[I, O]) AppendToolsNode(node *ToolsNode, opts ...GraphAddNodeOpt)
 	return c
 }
 
+// AppendGuardrail add a Guardrail node to the chain, see NewGuardrailNode.
+// e.g.
+//
+//	guardrail, err := compose.NewGuardrailNode[*schema.Message](&compose.GuardrailConfig{Guardrails: guardrails})
+//
+//	chain.AppendChatModel(chatModel).AppendGuardrail(guardrail)
+func (c *Chain[I, O]) AppendGuardrail(node *GuardrailNode, opts ...GraphAddNodeOpt) *Chain[I, O] {
+	gNode, options := toGuardrailNode(node, opts...)
+	c.addNode(gNode, options)
+	return c
+}
+
+// AppendSpeechToText add a SpeechToText node to the chain.
+// e.g.
+//
+//	stt, err := whisper.NewSpeechToText(ctx, &whisper.Config{})
+//
+//	chain.AppendSpeechToText(stt)
+func (c *Chain[I, O]) AppendSpeechToText(node audio.SpeechToText, opts ...GraphAddNodeOpt) *Chain[I, O] {
+	gNode, options := toSpeechToTextNode(node, opts...)
+	c.addNode(gNode, options)
+	return c
+}
+
+// AppendTextToSpeech add a TextToSpeech node to the chain.
+// e.g.
+//
+//	tts, err := openai.NewTextToSpeech(ctx, &openai.TextToSpeechConfig{Voice: "alloy"})
+//
+//	chain.AppendTextToSpeech(tts)
+func (c *Chain[I, O]) AppendTextToSpeech(node audio.TextToSpeech, opts ...GraphAddNodeOpt) *Chain[I, O] {
+	gNode, options := toTextToSpeechNode(node, opts...)
+	c.addNode(gNode, options)
+	return c
+}
+
 // AppendDocumentTransformer add a DocumentTransformer node to the chain.
 // e.g.
 //
@@ -498,6 +535,61 @@ func (c *Chain[I, O]) AppendPassthrough(opts ...GraphAddNodeOpt) *Chain[I, O] {
 	return c
 }
 
+// AppendNamedOutput returns the output of the previous node of the chain under the key in the final output,
+// alongside the output of the last node, instead of converting the chain to a Graph with explicit fan-in.
+// The output type of the chain must be map[string]any, and so must be that of the last node, e.g. by WithOutputKey.
+// The key must not conflict with the other keys of the final output.
+// It doesn't change the input of the next node, which is still the output of the previous node.
+// e.g.
+//
+//	chain := compose.NewChain[string, map[string]any]()
+//	chain.
+//		AppendChatTemplate(draftTemplate).
+//		AppendChatModel(cm).
+//		AppendNamedOutput("draft"). // the *schema.Message output by cm
+//		AppendLambda(toReviewInput).
+//		AppendChatModel(reviewer, compose.WithOutputKey("review"))
+//	out, err := r.Invoke(ctx, input) // map[string]any{"draft": ..., "review": ...}
+func (c *Chain[I, O]) AppendNamedOutput(key string) *Chain[I, O] {
+	if c.err != nil {
+		return c
+	}
+	if c.gg.compiled {
+		c.reportError(ErrChainCompiled)
+		return c
+	}
+	if key == "" {
+		c.reportError(errors.New("named output key is empty"))
+		return c
+	}
+
+	node, options := toPassthroughNode(WithOutputKey(key))
+	nodeKey := c.nextNodeKey()
+	if err := c.gg.addNode(nodeKey, node, options); err != nil {
+		c.reportError(err)
+		return c
+	}
+
+	preNodeKeys := c.preNodeKeys
+	if len(preNodeKeys) == 0 {
+		preNodeKeys = []string{START}
+	}
+	for _, preNodeKey := range preNodeKeys {
+		if err := c.gg.AddEdge(preNodeKey, nodeKey); err != nil {
+			c.reportError(err)
+			return c
+		}
+	}
+	if err := c.gg.AddEdge(nodeKey, END); err != nil {
+		c.reportError(err)
+		return c
+	}
+
+	// END waits for the named outputs as well as the output of the last node
+	c.gg.runAsDAG = true
+	return c
+}
+
 // nextIdx.
 // get the next idx for the chain.
 // chain key is: node_idx => eg: node_0 => represent the first node of the chain (idx start from 0)
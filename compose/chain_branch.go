@@ -41,17 +41,14 @@ type ChainBranch struct {
 	err            error
 }
 
+// NewChainMultiBranch creates a chain branch which activates several of its nodes at the same time, see NewGraphMultiBranch.
 func NewChainMultiBranch[T any](cond GraphMultiBranchCondition[T]) *ChainBranch {
 	invokeCond := func(ctx context.Context, in T, opts ...any) (endNodes []string, err error) {
 		ends, err := cond(ctx, in)
 		if err != nil {
 			return nil, err
 		}
-		endNodes = make([]string, 0, len(ends))
-		for end := range ends {
-			endNodes = append(endNodes, end)
-		}
-		return endNodes, nil
+		return selectedEndNodes(ends, nil)
 	}
 
 	return &ChainBranch{
@@ -60,17 +57,14 @@ func NewChainMultiBranch[T any](cond GraphMultiBranchCondition[T]) *ChainBranch
 	}
 }
 
+// NewStreamChainMultiBranch creates a multi-target chain branch with a condition reading the input stream, see NewStreamGraphMultiBranch.
 func NewStreamChainMultiBranch[T any](cond StreamGraphMultiBranchCondition[T]) *ChainBranch {
 	collectCon := func(ctx context.Context, in *schema.StreamReader[T], opts ...any) (endNodes []string, err error) {
 		ends, err := cond(ctx, in)
 		if err != nil {
 			return nil, err
 		}
-		endNodes = make([]string, 0, len(ends))
-		for end := range ends {
-			endNodes = append(endNodes, end)
-		}
-		return endNodes, nil
+		return selectedEndNodes(ends, nil)
 	}
 
 	return &ChainBranch{
@@ -176,6 +170,15 @@ func (cb *ChainBranch) AddToolsNode(key string, node *ToolsNode, opts ...GraphAd
 	return cb.addNode(key, gNode, options)
 }
 
+// AddGuardrail adds a Guardrail node to the branch, see NewGuardrailNode.
+// eg.
+//
+//	cb.AddGuardrail("guardrail_node_key", guardrail)
+func (cb *ChainBranch) AddGuardrail(key string, node *GuardrailNode, opts ...GraphAddNodeOpt) *ChainBranch {
+	gNode, options := toGuardrailNode(node, opts...)
+	return cb.addNode(key, gNode, options)
+}
+
 // AddLambda adds a Lambda node to the branch.
 // eg.
 //
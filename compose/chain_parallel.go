@@ -97,6 +97,15 @@ func (p *Parallel) AddToolsNode(outputKey string, node *ToolsNode, opts ...Graph
 	return p.addNode(outputKey, gNode, options)
 }
 
+// AddGuardrail adds a Guardrail node to the parallel, see NewGuardrailNode.
+// eg.
+//
+//	p.AddGuardrail("output_key01", guardrail)
+func (p *Parallel) AddGuardrail(outputKey string, node *GuardrailNode, opts ...GraphAddNodeOpt) *Parallel {
+	gNode, options := toGuardrailNode(node, append(opts, WithOutputKey(outputKey))...)
+	return p.addNode(outputKey, gNode, options)
+}
+
 // AddLambda adds a lambda node to the parallel.
 // eg.
 //
@@ -871,3 +871,45 @@ func TestChainWithNodeKey(t *testing.T) {
 	})
 
 }
+
+func TestChainNamedOutput(t *testing.T) {
+	ctx := context.Background()
+
+	upper := InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in + "_draft", nil
+	})
+	final := InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in + "_final", nil
+	})
+
+	r, err := NewChain[string, map[string]any]().
+		AppendLambda(upper).
+		AppendNamedOutput("draft").
+		AppendLambda(final, WithOutputKey("final")).
+		Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"draft": "x_draft", "final": "x_draft_final"}, out)
+
+	sr, err := r.Stream(ctx, "x")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"draft": "x_draft", "final": "x_draft_final"}, out)
+
+	_, err = NewChain[string, map[string]any]().
+		AppendNamedOutput("").
+		Compile(ctx)
+	assert.Error(t, err)
+
+	r, err = NewChain[string, map[string]any]().
+		AppendLambda(upper).
+		AppendNamedOutput("dup").
+		AppendLambda(final, WithOutputKey("dup")).
+		Compile(ctx)
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, "x")
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/callbacks"
 	"github.com/cloudwego/eino/schema"
 )
@@ -84,6 +85,7 @@ func TestSimpleCheckPoint(t *testing.T) {
 
 	_, err = r.Invoke(ctx, "start", WithCheckPointID("1"))
 	assert.NotNil(t, err)
+	assert.ErrorIs(t, err, einoerr.ErrInterrupted)
 	info, ok := ExtractInterruptInfo(err)
 	assert.True(t, ok)
 	assert.Equal(t, &testStruct{A: ""}, info.State)
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// compileCache holds the type metadata resolved while compiling graphs.
+// Type metadata only depends on the types themselves, so it's shared across all graphs of the process,
+// and compiling graphs with identical topology (e.g. one graph per tenant) resolves it only once.
+var compileCache = &typeMetaCache{}
+
+type typeMetaCache struct {
+	// genericHelperKey -> *genericHelper
+	helpers sync.Map
+	// assignableKey -> assignableType
+	assignable sync.Map
+	// fieldPathKey -> *fieldPathResult
+	fieldPaths sync.Map
+}
+
+type genericHelperKey struct {
+	input, output reflect.Type
+}
+
+type assignableKey struct {
+	input, arg reflect.Type
+}
+
+type fieldPathKey struct {
+	typ  reflect.Type
+	path string
+}
+
+type fieldPathResult struct {
+	extracted reflect.Type
+	remaining FieldPath
+	err       error
+}
+
+// maxPrecompileFieldDepth limits how deep Precompile descends into nested struct fields.
+const maxPrecompileFieldDepth = 4
+
+// Precompile front-loads the reflection work of compiling graphs whose nodes consume I and produce O,
+// such as building the generic helpers, checking type assignability and resolving the field paths of I and O
+// used by field mappings.
+// The results are kept in a package-level cache shared by all subsequent compilations,
+// so calling Precompile during initialization reduces the latency of the first Compile, e.g. on serverless cold starts.
+// e.g.
+//
+//	func init() {
+//		compose.Precompile[map[string]any, []*schema.Message]()
+//		compose.Precompile[[]*schema.Message, *schema.Message]()
+//	}
+func Precompile[I, O any]() {
+	newGenericHelper[I, O]()
+	newGenericHelper[I, I]()
+	newGenericHelper[O, O]()
+	newGenericHelper[I, O]().forMapInput()
+
+	input, output := generic.TypeOf[I](), generic.TypeOf[O]()
+	checkAssignable(input, output)
+	checkAssignable(output, input)
+
+	precompileFieldPaths(input, input, nil, map[reflect.Type]bool{})
+	precompileFieldPaths(output, output, nil, map[reflect.Type]bool{})
+}
+
+// precompileFieldPaths resolves the paths of all exported struct fields reachable from root through typ,
+// which is the type found at prefix.
+func precompileFieldPaths(root, typ reflect.Type, prefix []string, visiting map[reflect.Type]bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || len(prefix) >= maxPrecompileFieldDepth || visiting[typ] {
+		return
+	}
+
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		path := append(append(make([]string, 0, len(prefix)+1), prefix...), f.Name)
+		ft, _, err := checkAndExtractFieldType(path, root)
+		if err != nil {
+			continue
+		}
+		precompileFieldPaths(root, ft, path, visiting)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+type precompileInner struct {
+	Name string
+	Any  any
+}
+
+type precompileIn struct {
+	Inner *precompileInner
+	Next  *precompileIn
+	Tags  map[string]string
+	field int
+}
+
+type precompileOut struct {
+	Count int
+}
+
+func TestPrecompile(t *testing.T) {
+	Precompile[*precompileIn, precompileOut]()
+
+	inType, outType := generic.TypeOf[*precompileIn](), generic.TypeOf[precompileOut]()
+
+	gh, ok := compileCache.helpers.Load(genericHelperKey{input: inType, output: outType})
+	require.True(t, ok)
+	assert.Same(t, gh, newGenericHelper[*precompileIn, precompileOut]())
+
+	_, ok = compileCache.assignable.Load(assignableKey{input: inType, arg: outType})
+	assert.True(t, ok)
+
+	for _, path := range [][]string{
+		{"Inner"},
+		{"Inner", "Name"},
+		{"Inner", "Any"},
+		{"Next"},
+		{"Tags"},
+		{"Count"},
+	} {
+		typ := inType
+		if path[0] == "Count" {
+			typ = outType
+		}
+		_, ok = compileCache.fieldPaths.Load(fieldPathKey{typ: typ, path: strings.Join(path, pathSeparator)})
+		assert.True(t, ok, path)
+	}
+
+	// unexported fields and recursive types are skipped
+	_, ok = compileCache.fieldPaths.Load(fieldPathKey{typ: inType, path: "field"})
+	assert.False(t, ok)
+	_, ok = compileCache.fieldPaths.Load(fieldPathKey{typ: inType, path: strings.Join([]string{"Next", "Tags"}, pathSeparator)})
+	assert.False(t, ok)
+}
+
+func TestCompileCacheFieldPaths(t *testing.T) {
+	typ := reflect.TypeOf(&precompileIn{})
+
+	ft, remaining, err := checkAndExtractFieldType([]string{"Inner", "Any", "x"}, typ)
+	require.NoError(t, err)
+	assert.Equal(t, generic.TypeOf[any](), ft)
+	assert.Equal(t, FieldPath{"x"}, remaining)
+
+	// cached results are copied, so mutating them doesn't affect later compilations
+	remaining[0] = "y"
+	_, remaining, err = checkAndExtractFieldType([]string{"Inner", "Any", "x"}, typ)
+	require.NoError(t, err)
+	assert.Equal(t, FieldPath{"x"}, remaining)
+
+	// errors are cached as well
+	_, _, err = checkAndExtractFieldType([]string{"Missing"}, typ)
+	assert.ErrorContains(t, err, "has no field[Missing]")
+	_, _, err = checkAndExtractFieldType([]string{"Missing"}, typ)
+	assert.ErrorContains(t, err, "has no field[Missing]")
+}
+
+func TestCompileIdenticalGraphs(t *testing.T) {
+	build := func() (Runnable[*precompileIn, map[string]any], error) {
+		wf := NewWorkflow[*precompileIn, map[string]any]()
+		wf.AddLambdaNode("name", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return strings.ToUpper(in), nil
+		})).AddInput(START, FromFieldPath(FieldPath{"Inner", "Name"}))
+		wf.End().AddInput("name", ToField("name"))
+		return wf.Compile(context.Background())
+	}
+
+	for i := 0; i < 2; i++ {
+		r, err := build()
+		require.NoError(t, err)
+		out, err := r.Invoke(context.Background(), &precompileIn{Inner: &precompileInner{Name: "a"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "A"}, out)
+	}
+}
@@ -0,0 +1,393 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// maxReportedCycles bounds the number of cycles enumerated by the compile report,
+// as the number of elementary cycles may grow exponentially with the edges.
+const maxReportedCycles = 100
+
+// CompileReport is the result of the compile-time analysis of a graph, filled by WithCompileReport.
+// It lists all the cycles of the graph, the branches controlling the exit of each cycle,
+// and the max run steps bounding the execution, so that the runaway loops can be found before running.
+type CompileReport struct {
+	GraphName string
+	// DAG reports whether the graph runs in DAG mode, in which cycles are not allowed.
+	DAG bool
+	// MaxRunSteps is the max steps the graph runs with, 0 in DAG mode.
+	MaxRunSteps int
+	// MaxRunStepsConfigured reports whether MaxRunSteps is set by WithMaxRunSteps rather than defaulted.
+	MaxRunStepsConfigured bool
+	// Cycles are the elementary cycles of the graph, sorted by their nodes.
+	Cycles []CycleReport
+	// CyclesTruncated reports whether there are more cycles than the reported ones.
+	CyclesTruncated bool
+	// Edges is the type compatibility matrix of the data edges, sorted by the start and end node.
+	Edges []EdgeTypeReport
+	// Diagnostics are the human-readable findings with suggestions, empty if nothing to concern.
+	Diagnostics []string
+}
+
+// CycleReport describes an elementary cycle of the graph.
+type CycleReport struct {
+	// Nodes are the nodes on the cycle in edge order, starting from the smallest key, without repeating the first one.
+	Nodes []string
+	// Exits are the branches on the cycle which are able to route out of it.
+	// Empty Exits means the cycle can only be stopped by the max run steps or an error.
+	Exits []CycleExit
+}
+
+// CycleExit is a branch on a cycle that can route out of the cycle.
+type CycleExit struct {
+	// Node is the key of the node on the cycle where the branch starts.
+	Node string
+	// Targets are the end nodes of the branch outside the cycle.
+	Targets []string
+}
+
+// WithCompileReport fills report with the compile-time cycle analysis of the graph when it's compiled,
+// even if the compilation fails because of the cycles in DAG mode.
+// It doesn't apply to the subgraphs, which can set it by WithGraphCompileOptions.
+// e.g.
+//
+//	report := &compose.CompileReport{}
+//	r, err := g.Compile(ctx, compose.WithCompileReport(report))
+//	for _, d := range report.Diagnostics {
+//		log.Println(d)
+//	}
+func WithCompileReport(report *CompileReport) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.report = report
+	}
+}
+
+func defaultMaxRunSteps(nodeNum int) int {
+	return nodeNum + 10
+}
+
+func newCompileReport(graphName string, chanSubscribeTo map[string]*chanCall, dag bool, maxRunSteps int) *CompileReport {
+	report := &CompileReport{
+		GraphName:             graphName,
+		DAG:                   dag,
+		MaxRunSteps:           maxRunSteps,
+		MaxRunStepsConfigured: maxRunSteps > 0,
+	}
+	if !dag && maxRunSteps == 0 {
+		report.MaxRunSteps = defaultMaxRunSteps(len(chanSubscribeTo))
+	}
+
+	var cycles [][]string
+	cycles, report.CyclesTruncated = findCycles(chanSubscribeTo)
+	for _, nodes := range cycles {
+		report.Cycles = append(report.Cycles, CycleReport{
+			Nodes: nodes,
+			Exits: findCycleExits(nodes, chanSubscribeTo),
+		})
+	}
+
+	for _, c := range report.Cycles {
+		switch {
+		case dag:
+			report.Diagnostics = append(report.Diagnostics, fmt.Sprintf(
+				"cycle %s is not allowed in DAG mode, remove one of its edges or use AnyPredecessor trigger mode",
+				formatLoops([][]string{append(c.Nodes, c.Nodes[0])})))
+		case len(c.Exits) == 0:
+			report.Diagnostics = append(report.Diagnostics, fmt.Sprintf(
+				"cycle %s has no branch routing out of it, it only stops when exceeding max run steps(%d), add a branch leading out of the cycle",
+				formatLoops([][]string{append(c.Nodes, c.Nodes[0])}), report.MaxRunSteps))
+		}
+	}
+	if !dag && len(report.Cycles) > 0 && !report.MaxRunStepsConfigured {
+		report.Diagnostics = append(report.Diagnostics, fmt.Sprintf(
+			"graph has %d cycle(s) but max run steps isn't configured, the default %d is used, set it by WithMaxRunSteps",
+			len(report.Cycles), report.MaxRunSteps))
+	}
+	if report.CyclesTruncated {
+		report.Diagnostics = append(report.Diagnostics, fmt.Sprintf(
+			"graph has more than %d cycles, only the first %d are reported", maxReportedCycles, maxReportedCycles))
+	}
+
+	return report
+}
+
+func controlSuccessors(c *chanCall) []string {
+	seen := make(map[string]bool)
+	var ret []string
+	add := func(node string) {
+		if node == END || seen[node] {
+			return
+		}
+		seen[node] = true
+		ret = append(ret, node)
+	}
+	for _, node := range c.controls {
+		add(node)
+	}
+	for _, b := range c.writeToBranches {
+		for node := range b.endNodes {
+			add(node)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// findCycles enumerates the elementary cycles, each of which starts from its smallest node
+// and only goes through the nodes greater than the start, so that every cycle is found exactly once.
+func findCycles(chanSubscribeTo map[string]*chanCall) ([][]string, bool) {
+	nodes := make([]string, 0, len(chanSubscribeTo))
+	successors := make(map[string][]string, len(chanSubscribeTo))
+	for node, c := range chanSubscribeTo {
+		nodes = append(nodes, node)
+		successors[node] = controlSuccessors(c)
+	}
+	sort.Strings(nodes)
+
+	var (
+		cycles    [][]string
+		truncated bool
+	)
+	for _, start := range nodes {
+		onPath := map[string]bool{start: true}
+		var dfs func(path []string)
+		dfs = func(path []string) {
+			for _, next := range successors[path[len(path)-1]] {
+				if truncated {
+					return
+				}
+				if next == start {
+					if len(cycles) == maxReportedCycles {
+						truncated = true
+						return
+					}
+					cycles = append(cycles, append([]string{}, path...))
+					continue
+				}
+				if next < start || onPath[next] {
+					continue
+				}
+				onPath[next] = true
+				dfs(append(path, next))
+				onPath[next] = false
+			}
+		}
+		dfs([]string{start})
+		if truncated {
+			break
+		}
+	}
+
+	return cycles, truncated
+}
+
+func findCycleExits(cycle []string, chanSubscribeTo map[string]*chanCall) []CycleExit {
+	inCycle := make(map[string]bool, len(cycle))
+	for _, node := range cycle {
+		inCycle[node] = true
+	}
+
+	var exits []CycleExit
+	for _, node := range cycle {
+		for _, b := range chanSubscribeTo[node].writeToBranches {
+			var targets []string
+			for end := range b.endNodes {
+				if !inCycle[end] {
+					targets = append(targets, end)
+				}
+			}
+			if len(targets) == 0 {
+				continue
+			}
+			sort.Strings(targets)
+			exits = append(exits, CycleExit{Node: node, Targets: targets})
+		}
+	}
+	return exits
+}
+
+// EdgeTypeCompatibility describes how the output of the start node of an edge is passed to the end node.
+type EdgeTypeCompatibility string
+
+const (
+	// EdgeTypeExact means the output type is the same as the input type.
+	EdgeTypeExact EdgeTypeCompatibility = "exact"
+	// EdgeTypeAssignable means the output type implements the input interface type.
+	EdgeTypeAssignable EdgeTypeCompatibility = "assignable"
+	// EdgeTypeRuntimeChecked means the output is an interface, whose concrete type is only checked at runtime.
+	EdgeTypeRuntimeChecked EdgeTypeCompatibility = "runtime_checked"
+	// EdgeTypeConverted means the output is converted by field mappings or an edge transform.
+	EdgeTypeConverted EdgeTypeCompatibility = "converted"
+)
+
+// EdgeTypeReport is an entry of the type compatibility matrix in CompileReport.
+type EdgeTypeReport struct {
+	From     string
+	To       string
+	FromType reflect.Type
+	ToType   reflect.Type
+
+	Compatibility EdgeTypeCompatibility
+	// Keyed reports whether the edge is declared by WithOutputKey of the start node or WithInputKey of the end node.
+	Keyed bool
+	// Loose reports whether the edge passes an interface or map[string]any without explicit declarations,
+	// i.e. the type errors on it can only be found at runtime.
+	Loose bool
+}
+
+// StrictEdgeTypesMode decides how the loose edges are handled when compiling, see EdgeTypeReport.Loose.
+type StrictEdgeTypesMode uint8
+
+const (
+	// StrictEdgeTypesOff doesn't check the loose edges, which is the default.
+	StrictEdgeTypesOff StrictEdgeTypesMode = iota
+	// StrictEdgeTypesWarn reports the loose edges as the diagnostics of CompileReport.
+	StrictEdgeTypesWarn
+	// StrictEdgeTypesError fails the compilation with ErrLooseEdgeType if there's any loose edge.
+	StrictEdgeTypesError
+)
+
+// ErrLooseEdgeType is returned by compiling in StrictEdgeTypesError mode when an edge passes
+// an interface or map[string]any without WithOutputKey, WithInputKey, field mappings or an edge transform.
+var ErrLooseEdgeType = errors.New("loose edge type")
+
+// WithStrictEdgeTypes requires the edges passing an interface or map[string]any to be declared explicitly,
+// by WithOutputKey of the start node, WithInputKey of the end node, field mappings or an edge transform,
+// so that the type errors on them are found at compile time rather than at runtime.
+// Use StrictEdgeTypesWarn together with WithCompileReport to get the loose edges without failing the compilation.
+func WithStrictEdgeTypes(mode StrictEdgeTypesMode) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.strictEdgeTypes = mode
+	}
+}
+
+func (g *graph) edgeTypeReports() []EdgeTypeReport {
+	edges := make(map[string]map[string]bool)
+	addEdge := func(from, to string) {
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		edges[from][to] = true
+	}
+	for from, ends := range g.dataEdges {
+		for _, to := range ends {
+			addEdge(from, to)
+		}
+	}
+	for from, branches := range g.branches {
+		for _, b := range branches {
+			if b.noDataFlow {
+				continue
+			}
+			for to := range b.endNodes {
+				addEdge(from, to)
+			}
+		}
+	}
+
+	var ret []EdgeTypeReport
+	for from, ends := range edges {
+		for to := range ends {
+			ret = append(ret, g.edgeTypeReport(from, to))
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].From != ret[j].From {
+			return ret[i].From < ret[j].From
+		}
+		return ret[i].To < ret[j].To
+	})
+	return ret
+}
+
+func (g *graph) edgeTypeReport(from, to string) EdgeTypeReport {
+	e := EdgeTypeReport{
+		From:     from,
+		To:       to,
+		FromType: g.getNodeOutputType(from),
+		ToType:   g.getNodeInputType(to),
+	}
+	if n, ok := g.nodes[from]; ok && n.nodeInfo != nil && (n.nodeInfo.outputKey != "" || len(n.nodeInfo.outputKeyRenames) > 0) {
+		e.Keyed = true
+	}
+	if n, ok := g.nodes[to]; ok && n.nodeInfo != nil && (n.nodeInfo.inputKey != "" || len(n.nodeInfo.inputKeys) > 0) {
+		e.Keyed = true
+	}
+
+	switch {
+	case g.convertedEdges[from][to]:
+		e.Compatibility = EdgeTypeConverted
+	case e.FromType == e.ToType:
+		e.Compatibility = EdgeTypeExact
+	case checkAssignable(e.FromType, e.ToType) == assignableTypeMust:
+		e.Compatibility = EdgeTypeAssignable
+	default:
+		e.Compatibility = EdgeTypeRuntimeChecked
+	}
+
+	if e.Compatibility != EdgeTypeConverted && !e.Keyed {
+		mapType := generic.TypeOf[map[string]any]()
+		e.Loose = e.Compatibility == EdgeTypeRuntimeChecked || e.FromType == mapType || e.ToType == mapType
+	}
+	return e
+}
+
+func (e *EdgeTypeReport) looseDiagnostic() string {
+	var fixes []string
+	if e.From != START {
+		fixes = append(fixes, fmt.Sprintf("WithOutputKey of node[%s]", e.From))
+	}
+	if e.To != END {
+		fixes = append(fixes, fmt.Sprintf("WithInputKey of node[%s]", e.To))
+	}
+	fixes = append(fixes, "field mappings or an edge transform")
+	return fmt.Sprintf("edge[%s]-[%s] passes %s to %s, which is only checked at runtime, declare it by %s",
+		e.From, e.To, e.FromType, e.ToType, strings.Join(fixes, ", "))
+}
+
+func checkLooseEdgeTypes(edges []EdgeTypeReport) error {
+	var diagnostics []string
+	for i := range edges {
+		if edges[i].Loose {
+			diagnostics = append(diagnostics, edges[i].looseDiagnostic())
+		}
+	}
+	if len(diagnostics) > 0 {
+		return fmt.Errorf("%w: %s", ErrLooseEdgeType, strings.Join(diagnostics, "; "))
+	}
+	return nil
+}
+
+func (r *CompileReport) addEdgeTypes(edges []EdgeTypeReport, warnLoose bool) {
+	r.Edges = edges
+	if !warnLoose {
+		return
+	}
+	for i := range edges {
+		if edges[i].Loose {
+			r.Diagnostics = append(r.Diagnostics, edges[i].looseDiagnostic())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileReport(t *testing.T) {
+	ctx := context.Background()
+	pass := InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })
+
+	newGraph := func(opts ...NewGraphOption) *Graph[string, string] {
+		g := NewGraph[string, string](opts...)
+		for _, key := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, g.AddLambdaNode(key, pass))
+		}
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", "b"))
+		require.NoError(t, g.AddBranch("b", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			return "a", nil
+		}, map[string]bool{"a": true, "c": true})))
+		require.NoError(t, g.AddEdge("c", "d"))
+		require.NoError(t, g.AddEdge("d", "c"))
+		require.NoError(t, g.AddEdge("d", END))
+		return g
+	}
+
+	t.Run("pregel", func(t *testing.T) {
+		report := &CompileReport{}
+		_, err := newGraph().Compile(ctx, WithCompileReport(report), WithGraphName("g"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "g", report.GraphName)
+		assert.False(t, report.DAG)
+		assert.False(t, report.MaxRunStepsConfigured)
+		assert.Equal(t, 14, report.MaxRunSteps)
+		assert.Equal(t, []CycleReport{
+			{Nodes: []string{"a", "b"}, Exits: []CycleExit{{Node: "b", Targets: []string{"c"}}}},
+			{Nodes: []string{"c", "d"}},
+		}, report.Cycles)
+		assert.Len(t, report.Diagnostics, 2)
+		assert.Contains(t, report.Diagnostics[0], "[c->d->c] has no branch")
+		assert.Contains(t, report.Diagnostics[1], "WithMaxRunSteps")
+
+		report = &CompileReport{}
+		_, err = newGraph().Compile(ctx, WithCompileReport(report), WithMaxRunSteps(5))
+		require.NoError(t, err)
+		assert.True(t, report.MaxRunStepsConfigured)
+		assert.Equal(t, 5, report.MaxRunSteps)
+		assert.Len(t, report.Diagnostics, 1)
+	})
+
+	t.Run("dag", func(t *testing.T) {
+		report := &CompileReport{}
+		_, err := newGraph().Compile(ctx, WithCompileReport(report), WithNodeTriggerMode(AllPredecessor))
+		assert.ErrorIs(t, err, DAGInvalidLoopErr)
+		assert.True(t, report.DAG)
+		assert.Len(t, report.Cycles, 2)
+		assert.Len(t, report.Diagnostics, 2)
+		assert.Contains(t, report.Diagnostics[0], "not allowed in DAG mode")
+	})
+}
+
+func TestStrictEdgeTypes(t *testing.T) {
+	ctx := context.Background()
+
+	newGraph := func() *Graph[map[string]any, map[string]any] {
+		g := NewGraph[map[string]any, map[string]any]()
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		}), WithInputKey("q"), WithOutputKey("x")))
+		require.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, in map[string]any) (map[string]any, error) {
+			return in, nil
+		})))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", "b"))
+		require.NoError(t, g.AddEdge("b", END))
+		return g
+	}
+
+	report := &CompileReport{}
+	_, err := newGraph().Compile(ctx, WithCompileReport(report))
+	require.NoError(t, err)
+	mapType := reflect.TypeOf(map[string]any{})
+	assert.Equal(t, []EdgeTypeReport{
+		{From: "a", To: "b", FromType: mapType, ToType: mapType, Compatibility: EdgeTypeExact, Keyed: true},
+		{From: "b", To: END, FromType: mapType, ToType: mapType, Compatibility: EdgeTypeExact, Loose: true},
+		{From: START, To: "a", FromType: mapType, ToType: mapType, Compatibility: EdgeTypeExact, Keyed: true},
+	}, report.Edges)
+	assert.Empty(t, report.Diagnostics)
+
+	report = &CompileReport{}
+	_, err = newGraph().Compile(ctx, WithCompileReport(report), WithStrictEdgeTypes(StrictEdgeTypesWarn))
+	require.NoError(t, err)
+	require.Len(t, report.Diagnostics, 1)
+	assert.Contains(t, report.Diagnostics[0], "edge[b]-[end]")
+	assert.Contains(t, report.Diagnostics[0], "WithOutputKey of node[b]")
+
+	_, err = newGraph().Compile(ctx, WithStrictEdgeTypes(StrictEdgeTypesError))
+	assert.ErrorIs(t, err, ErrLooseEdgeType)
+}
@@ -17,13 +17,17 @@
 package compose
 
 import (
+	"context"
+
 	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/audio"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
 )
 
 func toComponentNode[I, O, TOption any](
@@ -58,10 +62,19 @@ func toEmbeddingNode(node embedding.Embedder, opts ...GraphAddNodeOpt) (*graphNo
 }
 
 func toRetrieverNode(node retriever.Retriever, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	// enforce the common filtering options, even if the implementation ignores them
+	retrieve := func(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+		docs, err := node.Retrieve(ctx, query, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return retriever.FilterDocuments(docs, opts...), nil
+	}
+
 	return toComponentNode(
 		node,
 		components.ComponentOfRetriever,
-		node.Retrieve,
+		retrieve,
 		nil,
 		nil,
 		nil,
@@ -69,11 +82,15 @@ func toRetrieverNode(node retriever.Retriever, opts ...GraphAddNodeOpt) (*graphN
 }
 
 func toLoaderNode(node document.Loader, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	var stream Stream[document.Source, []*schema.Document, document.LoaderOption]
+	if sl, ok := node.(document.StreamLoader); ok {
+		stream = sl.LoadStream
+	}
 	return toComponentNode(
 		node,
 		components.ComponentOfLoader,
 		node.Load,
-		nil,
+		stream,
 		nil,
 		nil,
 		opts...)
@@ -113,18 +130,52 @@ func toChatTemplateNode(node prompt.ChatTemplate, opts ...GraphAddNodeOpt) (*gra
 }
 
 func toDocumentTransformerNode(node document.Transformer, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	var transform Transform[[]*schema.Document, []*schema.Document, document.TransformerOption]
+	if st, ok := node.(document.StreamTransformer); ok {
+		transform = st.TransformStream
+	}
 	return toComponentNode(
 		node,
 		components.ComponentOfTransformer,
 		node.Transform,
 		nil,
 		nil,
+		transform,
+		opts...)
+}
+
+func toSpeechToTextNode(node audio.SpeechToText, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	return toComponentNode(
+		node,
+		components.ComponentOfSpeechToText,
+		nil,
+		nil,
 		nil,
+		node.Transcribe,
 		opts...)
 }
 
-func toToolsNode(node *ToolsNode, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+func toTextToSpeechNode(node audio.TextToSpeech, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
 	return toComponentNode(
+		node,
+		components.ComponentOfTextToSpeech,
+		nil,
+		nil,
+		nil,
+		node.Synthesize,
+		opts...)
+}
+
+func toToolsNode(node *ToolsNode, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	limiter := getGraphAddNodeOpts(opts...).nodeOptions.rateLimiter
+	if limiter != nil {
+		// the limiter of a ToolsNode is waited for before each tool call, so the ToolsNode is copied in case it's shared
+		cp := *node
+		cp.rateLimiter = limiter
+		node = &cp
+	}
+
+	gn, options := toComponentNode(
 		node,
 		ComponentOfToolsNode,
 		node.Invoke,
@@ -132,6 +183,8 @@ func toToolsNode(node *ToolsNode, opts ...GraphAddNodeOpt) (*graphNode, *graphAd
 		nil,
 		nil,
 		opts...)
+	gn.nodeInfo.rateLimiter = nil
+	return gn, options
 }
 
 func toLambdaNode(node *Lambda, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
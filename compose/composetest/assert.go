@@ -0,0 +1,179 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package composetest
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT = assert.TestingT
+
+type tHelper interface {
+	Helper()
+}
+
+// AssertNodeCalled asserts that the node has been executed exactly times times within the recorded runs.
+func AssertNodeCalled(t TestingT, run *Recorder, nodeKey string, times int) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	calls := run.Calls(nodeKey)
+	return assert.Equal(t, times, len(calls), "node[%s] is expected to be called %d times, actually %d times", nodeKey, times, len(calls))
+}
+
+// AssertNodeNotCalled asserts that the node has never been executed within the recorded runs.
+func AssertNodeNotCalled(t TestingT, run *Recorder, nodeKey string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return AssertNodeCalled(t, run, nodeKey, 0)
+}
+
+// AssertNodeInput asserts that the input of the last execution of the node equals expected.
+// streamed inputs are concatenated before compared.
+func AssertNodeInput[T any](t TestingT, run *Recorder, nodeKey string, expected T) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	inputs, err := NodeInputs[T](run, nodeKey)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	if len(inputs) == 0 {
+		return assert.Fail(t, fmt.Sprintf("node[%s] has not been called", nodeKey))
+	}
+
+	return assert.Equal(t, expected, inputs[len(inputs)-1], "unexpected input of node[%s]", nodeKey)
+}
+
+// AssertNodeOutput asserts that the output of the last execution of the node equals expected.
+// streamed outputs are concatenated before compared.
+func AssertNodeOutput[T any](t TestingT, run *Recorder, nodeKey string, expected T) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	outputs, err := NodeOutputs[T](run, nodeKey)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	if len(outputs) == 0 {
+		return assert.Fail(t, fmt.Sprintf("node[%s] has not been called", nodeKey))
+	}
+
+	return assert.Equal(t, expected, outputs[len(outputs)-1], "unexpected output of node[%s]", nodeKey)
+}
+
+// AssertNodeError asserts that the last execution of the node has failed with an error.
+// the error is also checked by errors.Is if target isn't nil.
+func AssertNodeError(t TestingT, run *Recorder, nodeKey string, target error) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	calls := run.Calls(nodeKey)
+	if len(calls) == 0 {
+		return assert.Fail(t, fmt.Sprintf("node[%s] has not been called", nodeKey))
+	}
+
+	err := calls[len(calls)-1].Err
+	if !assert.Error(t, err, "node[%s] is expected to fail", nodeKey) {
+		return false
+	}
+	if target != nil {
+		return assert.ErrorIs(t, err, target)
+	}
+	return true
+}
+
+// NodeInputs returns the inputs of the executions of the node in order, the streamed inputs are concatenated.
+// an error is returned if an input isn't of type T.
+func NodeInputs[T any](run *Recorder, nodeKey string) ([]T, error) {
+	calls := run.Calls(nodeKey)
+	ret := make([]T, 0, len(calls))
+	for _, c := range calls {
+		v, err := valueOf[T](c.Input, c.InputChunks, c.StreamInput)
+		if err != nil {
+			return nil, fmt.Errorf("input of node[%s]: %w", nodeKey, err)
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// NodeOutputs returns the outputs of the executions of the node in order, the streamed outputs are concatenated.
+// the failed executions are skipped, and an error is returned if an output isn't of type T.
+func NodeOutputs[T any](run *Recorder, nodeKey string) ([]T, error) {
+	calls := run.Calls(nodeKey)
+	ret := make([]T, 0, len(calls))
+	for _, c := range calls {
+		if c.Err != nil {
+			continue
+		}
+		v, err := valueOf[T](c.Output, c.OutputChunks, c.StreamOutput)
+		if err != nil {
+			return nil, fmt.Errorf("output of node[%s]: %w", nodeKey, err)
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+func valueOf[T any](v any, chunks []any, stream bool) (T, error) {
+	var zero T
+	if !stream {
+		if v == nil {
+			return zero, nil
+		}
+		t, ok := v.(T)
+		if !ok {
+			return zero, fmt.Errorf("unexpected type, expected: %v, got: %T", generic.TypeOf[T](), v)
+		}
+		return t, nil
+	}
+
+	items := make([]T, 0, len(chunks))
+	for _, c := range chunks {
+		t, ok := c.(T)
+		if !ok {
+			return zero, fmt.Errorf("unexpected chunk type, expected: %v, got: %T", generic.TypeOf[T](), c)
+		}
+		items = append(items, t)
+	}
+
+	switch len(items) {
+	case 0:
+		return zero, nil
+	case 1:
+		return items[0], nil
+	default:
+		concatenated, err := internal.ConcatItems(items)
+		if err != nil {
+			return zero, fmt.Errorf("concat chunks fail: %w", err)
+		}
+		return concatenated, nil
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package composetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockT struct {
+	errs []string
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errs = append(m.errs, fmt.Sprintf(format, args...))
+}
+
+func buildGraph(t *testing.T) compose.Runnable[string, string] {
+	sub := compose.NewGraph[string, string]()
+	require.NoError(t, sub.AddLambdaNode("upper", compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return strings.ToUpper(in), nil
+	})))
+	require.NoError(t, sub.AddEdge(compose.START, "upper"))
+	require.NoError(t, sub.AddEdge("upper", compose.END))
+
+	g := compose.NewGraph[string, string]()
+	require.NoError(t, g.AddLambdaNode("split", compose.StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray(strings.Split(in, " ")), nil
+	})))
+	require.NoError(t, g.AddGraphNode("sub", sub))
+	require.NoError(t, g.AddEdge(compose.START, "split"))
+	require.NoError(t, g.AddEdge("split", "sub"))
+	require.NoError(t, g.AddEdge("sub", compose.END))
+
+	r, err := g.Compile(context.Background())
+	require.NoError(t, err)
+	return r
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	r := buildGraph(t)
+
+	rec := NewRecorder()
+	out, err := r.Invoke(ctx, "a b", rec.Option())
+	require.NoError(t, err)
+	assert.Equal(t, "AB", out)
+
+	AssertNodeCalled(t, rec, "split", 1)
+	AssertNodeCalled(t, rec, "sub", 1)
+	AssertNodeCalled(t, rec, "upper", 1)
+	AssertNodeCalled(t, rec, "sub/upper", 1)
+	AssertNodeNotCalled(t, rec, "missing")
+	assert.Len(t, rec.Calls(""), 3)
+
+	AssertNodeInput(t, rec, "split", "a b")
+	AssertNodeOutput(t, rec, "split", "ab")
+	AssertNodeInput(t, rec, "upper", "ab")
+	AssertNodeOutput(t, rec, "sub", "AB")
+	assert.Equal(t, []string{"sub", "upper"}, rec.Calls("upper")[0].Path)
+
+	rec.Reset()
+	sr, err := r.Stream(ctx, "c d", rec.Option())
+	require.NoError(t, err)
+	for {
+		_, err = sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	sr.Close()
+
+	AssertNodeCalled(t, rec, "split", 1)
+	AssertNodeOutput(t, rec, "split", "cd")
+	AssertNodeOutput(t, rec, "sub", "CD")
+	assert.True(t, rec.Calls("split")[0].StreamOutput)
+}
+
+func TestAssertions(t *testing.T) {
+	ctx := context.Background()
+	errFail := errors.New("fail")
+
+	g := compose.NewGraph[string, string]()
+	require.NoError(t, g.AddLambdaNode("fail", compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return "", errFail
+	})))
+	require.NoError(t, g.AddEdge(compose.START, "fail"))
+	require.NoError(t, g.AddEdge("fail", compose.END))
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	rec := NewRecorder()
+	_, err = r.Invoke(ctx, "x", rec.Option())
+	require.Error(t, err)
+
+	AssertNodeError(t, rec, "fail", errFail)
+	outputs, err := NodeOutputs[string](rec, "fail")
+	require.NoError(t, err)
+	assert.Empty(t, outputs)
+
+	m := &mockT{}
+	assert.False(t, AssertNodeCalled(m, rec, "fail", 2))
+	assert.False(t, AssertNodeInput(m, rec, "fail", "y"))
+	assert.False(t, AssertNodeInput(m, rec, "fail", 1))
+	assert.False(t, AssertNodeOutput(m, rec, "missing", "x"))
+	assert.Len(t, m.errs, 4)
+	assert.Contains(t, m.errs[2], "unexpected type, expected: int, got: string")
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package composetest provides helpers to observe and assert the behavior of the nodes within graph runs in unit tests,
+// without hand-writing callback handlers.
+//
+//	rec := composetest.NewRecorder()
+//	out, err := runnable.Invoke(ctx, input, rec.Option())
+//
+//	composetest.AssertNodeCalled(t, rec, "retriever", 1)
+//	composetest.AssertNodeInput(t, rec, "retriever", "what is eino")
+package composetest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// NodeCall is a recorded execution of a graph node.
+type NodeCall struct {
+	// NodeKey is the key of the node in its graph.
+	NodeKey string
+	// Path is the keys of the node and its ancestor nodes, from the root graph, e.g. ["sub_graph", "node"].
+	Path      []string
+	Name      string
+	Component components.Component
+
+	// Input and Output are the callback input and output of the node,
+	// whose types are the input and output types of the node, or the callback input and output types of the component,
+	// e.g. *model.CallbackInput for chat models.
+	Input  any
+	Output any
+	// InputChunks and OutputChunks are the chunks of the streamed input and output.
+	InputChunks, OutputChunks []any
+	StreamInput, StreamOutput bool
+
+	Err error
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Recorder is a callback handler recording the executions of the graph nodes, including the nodes of the sub graphs.
+// Pass Option() to the runs to be recorded.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []*NodeCall
+
+	// streams are the goroutines receiving the streamed inputs and outputs
+	streams sync.WaitGroup
+}
+
+// Option returns the option of the runs to be recorded.
+func (r *Recorder) Option() compose.Option {
+	return compose.WithCallbacks(r.Handler())
+}
+
+// Handler returns the callback handler recording the node executions.
+func (r *Recorder) Handler() callbacks.Handler {
+	return &recordHandler{r: r}
+}
+
+// Calls returns the recorded executions of the node in order, after all the streams of them are received.
+// nodeKey is either the key of the node in any graph, or its path joined by '/', e.g. "sub_graph/node".
+// All the executions are returned if nodeKey is empty.
+func (r *Recorder) Calls(nodeKey string) []*NodeCall {
+	r.streams.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []*NodeCall
+	for _, c := range r.calls {
+		if nodeKey == "" || c.NodeKey == nodeKey || strings.Join(c.Path, "/") == nodeKey {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded executions.
+func (r *Recorder) Reset() {
+	r.streams.Wait()
+
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
+
+func (r *Recorder) add(c *NodeCall) {
+	r.mu.Lock()
+	r.calls = append(r.calls, c)
+	r.mu.Unlock()
+}
+
+type recordCtxKey struct{}
+
+// recording is stored in the context returned by OnStart, so that the callbacks of the components nested in the node,
+// which share the address of the node, are ignored.
+type recording struct {
+	call *NodeCall
+	addr string
+}
+
+type recordHandler struct {
+	r *Recorder
+}
+
+func (h *recordHandler) start(ctx context.Context, info *callbacks.RunInfo) (context.Context, *NodeCall) {
+	addr := compose.GetCurrentAddress(ctx)
+	if len(addr) == 0 || addr[len(addr)-1].Type != compose.AddressSegmentNode {
+		// graph runs and tool calls aren't nodes
+		return ctx, nil
+	}
+
+	addrStr := addr.String()
+	if rec, ok := ctx.Value(recordCtxKey{}).(*recording); ok && rec.addr == addrStr {
+		return context.WithValue(ctx, recordCtxKey{}, &recording{addr: addrStr}), nil
+	}
+
+	call := &NodeCall{NodeKey: addr[len(addr)-1].ID}
+	for _, seg := range addr {
+		if seg.Type == compose.AddressSegmentNode {
+			call.Path = append(call.Path, seg.ID)
+		}
+	}
+	if info != nil {
+		call.Name = info.Name
+		call.Component = info.Component
+	}
+
+	h.r.add(call)
+	return context.WithValue(ctx, recordCtxKey{}, &recording{call: call, addr: addrStr}), call
+}
+
+func (h *recordHandler) end(ctx context.Context, fn func(c *NodeCall)) {
+	rec, ok := ctx.Value(recordCtxKey{}).(*recording)
+	if !ok || rec.call == nil {
+		return
+	}
+
+	h.r.mu.Lock()
+	fn(rec.call)
+	h.r.mu.Unlock()
+}
+
+func receive[T any](h *recordHandler, sr *schema.StreamReader[T], add func(c *NodeCall, chunk any), c *NodeCall) {
+	if c == nil {
+		sr.Close()
+		return
+	}
+
+	h.r.streams.Add(1)
+	go func() {
+		defer h.r.streams.Done()
+		defer sr.Close()
+
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				h.r.mu.Lock()
+				if c.Err == nil {
+					c.Err = err
+				}
+				h.r.mu.Unlock()
+				return
+			}
+
+			h.r.mu.Lock()
+			add(c, chunk)
+			h.r.mu.Unlock()
+		}
+	}()
+}
+
+func (h *recordHandler) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	ctx, c := h.start(ctx, info)
+	if c != nil {
+		h.r.mu.Lock()
+		c.Input = input
+		h.r.mu.Unlock()
+	}
+	return ctx
+}
+
+func (h *recordHandler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	h.end(ctx, func(c *NodeCall) {
+		c.Output = output
+	})
+	return ctx
+}
+
+func (h *recordHandler) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	h.end(ctx, func(c *NodeCall) {
+		c.Err = err
+	})
+	return ctx
+}
+
+func (h *recordHandler) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+
+	ctx, c := h.start(ctx, info)
+	if c != nil {
+		h.r.mu.Lock()
+		c.StreamInput = true
+		h.r.mu.Unlock()
+	}
+	receive(h, input, func(c *NodeCall, chunk any) {
+		c.InputChunks = append(c.InputChunks, chunk)
+	}, c)
+	return ctx
+}
+
+func (h *recordHandler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+
+	var c *NodeCall
+	h.end(ctx, func(call *NodeCall) {
+		call.StreamOutput = true
+		c = call
+	})
+	receive(h, output, func(c *NodeCall, chunk any) {
+		c.OutputChunks = append(c.OutputChunks, chunk)
+	}, c)
+	return ctx
+}
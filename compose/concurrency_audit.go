@@ -0,0 +1,255 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SharedMutationTarget is the kind of the structure shared by the concurrent runs which is mutated.
+type SharedMutationTarget string
+
+const (
+	// SharedMutationComponent means the component instance of the node is mutated,
+	// e.g. a ChatModel whose BindTools is called during the run.
+	SharedMutationComponent SharedMutationTarget = "component"
+	// SharedMutationCallOptions means the call options passed to the node are mutated,
+	// which are usually shared by the runs calling with the same options.
+	SharedMutationCallOptions SharedMutationTarget = "call options"
+)
+
+// SharedMutation is a mutation of the structures shared by the concurrent runs of a compiled graph, found by WithConcurrencyAudit.
+// It's also the value of the panic if WithConcurrencyAudit is called with a nil handler.
+type SharedMutation struct {
+	NodeKey string
+	Target  SharedMutationTarget
+}
+
+func (s *SharedMutation) Error() string {
+	return fmt.Sprintf("node[%s] mutated its shared %s, which is unsafe for the concurrent runs of the compiled graph", s.NodeKey, s.Target)
+}
+
+// WithConcurrencyAudit enables the audit mode of the compiled graph, helping to find what makes sharing it across goroutines unsafe.
+// The component instance of each node is fingerprinted at compile time, and the call options of each node before it runs,
+// and both are checked again after the node runs. Upon a mutation, onMutation is called with the key of the offending node,
+// or the run panics with the *SharedMutation if onMutation is nil, e.g. in tests.
+// A mutation is reported once until the structure is mutated again.
+// The fingerprints cover the values reachable from the component and the options through the exported fields only,
+// except the types of the standard library, e.g. sync.Mutex or http.Client, which are safe for concurrent use by design.
+// The unexported fields are left out, as they are read without the locks guarding them, and usually hold the internal states,
+// e.g. caches or counters, which the component mutates safely by itself. So a mutation of an unexported field is not reported,
+// and a mutation of an exported field is a data race with the audit itself, which is what it reports.
+// The audit has considerable overhead, so it's meant for tests rather than production.
+// It doesn't apply to the subgraphs, which can set it by WithGraphCompileOptions.
+// e.g.
+//
+//	r, err := g.Compile(ctx, compose.WithConcurrencyAudit(nil))
+func WithConcurrencyAudit(onMutation func(ctx context.Context, m *SharedMutation)) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.concurrencyAudit = &concurrencyAuditOptions{onMutation: onMutation}
+	}
+}
+
+type concurrencyAuditOptions struct {
+	onMutation func(ctx context.Context, m *SharedMutation)
+}
+
+type concurrencyAudit struct {
+	onMutation func(ctx context.Context, m *SharedMutation)
+
+	mu           sync.Mutex
+	instances    map[string]any
+	fingerprints map[string]uint64
+}
+
+func newConcurrencyAudit(nodes map[string]*graphNode, o *concurrencyAuditOptions) *concurrencyAudit {
+	a := &concurrencyAudit{
+		onMutation:   o.onMutation,
+		instances:    make(map[string]any),
+		fingerprints: make(map[string]uint64),
+	}
+	for key, node := range nodes {
+		if node.g != nil || node.instance == nil {
+			// subgraphs are compiled and audited on their own
+			continue
+		}
+		a.instances[key] = node.instance
+		a.fingerprints[key] = fingerprint(node.instance)
+	}
+	return a
+}
+
+// checkComponent checks whether the component of the node has changed since the last check.
+func (a *concurrencyAudit) checkComponent(nodeKey string) *SharedMutation {
+	instance, ok := a.instances[nodeKey]
+	if !ok {
+		return nil
+	}
+	fp := fingerprint(instance)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.fingerprints[nodeKey] == fp {
+		return nil
+	}
+	a.fingerprints[nodeKey] = fp
+	return &SharedMutation{NodeKey: nodeKey, Target: SharedMutationComponent}
+}
+
+// report is called in the goroutine of the graph run, so that the panic is not recovered as a node panic.
+func (a *concurrencyAudit) report(ctx context.Context, mutations []*SharedMutation) {
+	for _, m := range mutations {
+		if a.onMutation == nil {
+			panic(m)
+		}
+		a.onMutation(ctx, m)
+	}
+}
+
+// auditTask runs the task, checking the mutations of the shared structures caused by it.
+func (a *concurrencyAudit) auditTask(ta *task, run func()) {
+	before := fingerprint(ta.option)
+	run()
+	if fingerprint(ta.option) != before {
+		ta.mutations = append(ta.mutations, &SharedMutation{NodeKey: ta.nodeKey, Target: SharedMutationCallOptions})
+	}
+	if m := a.checkComponent(ta.nodeKey); m != nil {
+		ta.mutations = append(ta.mutations, m)
+	}
+}
+
+const maxFingerprintDepth = 16
+
+type fingerprinter struct {
+	buf     [8]byte
+	sum     func(b []byte)
+	visited map[uintptr]struct{}
+}
+
+// fingerprint hashes the value deeply, following the pointers and the exported fields.
+func fingerprint(v any) uint64 {
+	h := fnv.New64a()
+	f := &fingerprinter{
+		sum:     func(b []byte) { _, _ = h.Write(b) },
+		visited: make(map[uintptr]struct{}),
+	}
+	f.walk(reflect.ValueOf(v), 0)
+	return h.Sum64()
+}
+
+func (f *fingerprinter) writeUint(u uint64) {
+	binary.LittleEndian.PutUint64(f.buf[:], u)
+	f.sum(f.buf[:])
+}
+
+func isStdType(t reflect.Type) bool {
+	pkg := t.PkgPath()
+	if pkg == "" {
+		return false
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
+
+func (f *fingerprinter) walk(v reflect.Value, depth int) {
+	if !v.IsValid() || depth > maxFingerprintDepth {
+		f.writeUint(0)
+		return
+	}
+	f.writeUint(uint64(v.Kind()))
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			f.writeUint(1)
+		} else {
+			f.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f.writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		f.writeUint(math.Float64bits(real(c)))
+		f.writeUint(math.Float64bits(imag(c)))
+	case reflect.String:
+		f.writeUint(uint64(v.Len()))
+		f.sum([]byte(v.String()))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		f.writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			f.writeUint(0)
+			return
+		}
+		f.walk(v.Elem(), depth+1)
+	case reflect.Ptr:
+		if v.IsNil() {
+			f.writeUint(0)
+			return
+		}
+		if _, ok := f.visited[v.Pointer()]; ok {
+			f.writeUint(uint64(v.Pointer()))
+			return
+		}
+		f.visited[v.Pointer()] = struct{}{}
+		f.walk(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		f.writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			f.walk(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			f.writeUint(0)
+			return
+		}
+		f.writeUint(uint64(v.Len()))
+		// the entries are hashed separately and summed, to be independent of the iteration order
+		var total uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			h := fnv.New64a()
+			ef := &fingerprinter{sum: func(b []byte) { _, _ = h.Write(b) }, visited: f.visited}
+			ef.walk(iter.Key(), depth+1)
+			ef.walk(iter.Value(), depth+1)
+			total += h.Sum64()
+		}
+		f.writeUint(total)
+	case reflect.Struct:
+		if isStdType(v.Type()) {
+			return
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			f.walk(v.Field(i), depth+1)
+		}
+	}
+}
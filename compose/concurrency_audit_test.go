@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type auditModel struct {
+	Tools   []*schema.ToolInfo
+	Options map[string]any
+
+	mu     sync.Mutex
+	mutate bool
+	calls  int
+}
+
+func (a *auditModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls++
+	if a.mutate {
+		a.Tools = append(a.Tools, &schema.ToolInfo{Name: "t"})
+	}
+	return schema.AssistantMessage("ok", nil), nil
+}
+
+func (a *auditModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := a.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+type auditOption struct {
+	N int
+}
+
+func TestConcurrencyAudit(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	build := func(cm *auditModel, opts ...GraphCompileOption) Runnable[[]*schema.Message, *schema.Message] {
+		g := NewGraph[[]*schema.Message, *schema.Message]()
+		require.NoError(t, g.AddChatModelNode("model", cm))
+		require.NoError(t, g.AddLambdaNode("lambda", InvokableLambdaWithOption(
+			func(ctx context.Context, in *schema.Message, opts ...*auditOption) (*schema.Message, error) {
+				for _, o := range opts {
+					o.N++
+				}
+				return in, nil
+			})))
+		require.NoError(t, g.AddEdge(START, "model"))
+		require.NoError(t, g.AddEdge("model", "lambda"))
+		require.NoError(t, g.AddEdge("lambda", END))
+		r, err := g.Compile(ctx, opts...)
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("safe", func(t *testing.T) {
+		// the unexported states guarded by the component itself are neither reported nor raced with
+		r := build(&auditModel{Options: map[string]any{"a": 1, "b": []int{1}}}, WithConcurrencyAudit(nil))
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := r.Invoke(ctx, input)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		r := build(&auditModel{mutate: true}, WithConcurrencyAudit(nil))
+		defer func() {
+			p := recover()
+			m, ok := p.(*SharedMutation)
+			require.True(t, ok, "unexpected panic: %v", p)
+			assert.Equal(t, "model", m.NodeKey)
+			assert.Equal(t, SharedMutationComponent, m.Target)
+		}()
+		_, _ = r.Invoke(ctx, input)
+		t.Fatal("should panic")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		var mutations []*SharedMutation
+		r := build(&auditModel{mutate: true}, WithConcurrencyAudit(func(ctx context.Context, m *SharedMutation) {
+			mutations = append(mutations, m)
+		}))
+		opt := WithLambdaOption(&auditOption{}).DesignateNode("lambda")
+		_, err := r.Invoke(ctx, input, opt)
+		assert.NoError(t, err)
+		assert.Equal(t, []*SharedMutation{
+			{NodeKey: "model", Target: SharedMutationComponent},
+			{NodeKey: "lambda", Target: SharedMutationCallOptions},
+		}, mutations)
+		assert.Contains(t, mutations[1].Error(), "node[lambda] mutated its shared call options")
+	})
+}
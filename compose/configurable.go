@@ -0,0 +1,193 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// ConfigurableField declares a parameter of a node, which is bound per run by WithConfigurable,
+// e.g. the temperature of a chat model or the filter of a retriever, so that a compiled graph can be parameterized
+// e.g. per tenant without recompiling.
+type ConfigurableField struct {
+	// Key is the key of the value in WithConfigurable, which can be shared by the nodes, e.g. "temperature".
+	Key string
+	// Description describes the field, e.g. for the consoles listing the fields.
+	Description string
+	// Bind converts the value to the call option of the node, which is designated to the node automatically.
+	Bind func(value any) (Option, error)
+}
+
+// NewConfigurableField declares a configurable field of type T, whose value is converted to T and then to the option by bind.
+// The numeric values are converted to the numeric T, e.g. float64 decoded from JSON to float32.
+// e.g.
+//
+//	temperature := compose.NewConfigurableField("temperature", "sampling temperature", func(v float32) compose.Option {
+//		return compose.WithChatModelOption(model.WithTemperature(v))
+//	})
+//	err = graph.AddChatModelNode("chat_model", chatModel, compose.WithConfigurableFields(temperature))
+//	out, err := runnable.Invoke(ctx, input, compose.WithConfigurable(map[string]any{"temperature": 0.2}))
+func NewConfigurableField[T any](key, description string, bind func(value T) Option) *ConfigurableField {
+	return &ConfigurableField{
+		Key:         key,
+		Description: description,
+		Bind: func(value any) (Option, error) {
+			v, err := convertConfigurableValue[T](value)
+			if err != nil {
+				return Option{}, err
+			}
+			return bind(v), nil
+		},
+	}
+}
+
+func convertConfigurableValue[T any](value any) (T, error) {
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+
+	var zero T
+	typ := generic.TypeOf[T]()
+	rv := reflect.ValueOf(value)
+	if value != nil && isNumericKind(rv.Kind()) && isNumericKind(typ.Kind()) {
+		return rv.Convert(typ).Interface().(T), nil
+	}
+	return zero, fmt.Errorf("expect a value of type[%s], got %T", typ, value)
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithConfigurableFields declares the configurable fields of the node, see ConfigurableField.
+func WithConfigurableFields(fields ...*ConfigurableField) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.configurable = append(o.nodeOptions.configurable, fields...)
+	}
+}
+
+// WithConfigurable binds the values of the configurable fields for the run, key -> value.
+// Each value is bound to all the nodes declaring the key, including the nodes of the subgraphs,
+// and binding a key not declared by any of them fails the run.
+// If it's passed more than once, the values are merged, the latter taking precedence.
+func WithConfigurable(values map[string]any) Option {
+	return Option{configurable: values}
+}
+
+// configurableTarget is a node declaring a configurable key, field is nil if the node is a subgraph.
+type configurableTarget struct {
+	nodeKey string
+	field   *ConfigurableField
+}
+
+func (g *graph) collectConfigurable() (map[string][]configurableTarget, error) {
+	ret := make(map[string][]configurableTarget)
+	for key, node := range g.nodes {
+		seen := make(map[string]bool)
+		if node.nodeInfo != nil {
+			for _, f := range node.nodeInfo.configurable {
+				if f == nil || f.Key == "" || f.Bind == nil {
+					return nil, fmt.Errorf("configurable field of node[%s] must have a key and a bind function", key)
+				}
+				if seen[f.Key] {
+					return nil, fmt.Errorf("configurable field[%s] is declared more than once by node[%s]", f.Key, key)
+				}
+				seen[f.Key] = true
+				ret[f.Key] = append(ret[f.Key], configurableTarget{nodeKey: key, field: f})
+			}
+		}
+		if node.g != nil && node.cr != nil {
+			for k := range node.cr.configurable {
+				if !seen[k] {
+					seen[k] = true
+					ret[k] = append(ret[k], configurableTarget{nodeKey: key})
+				}
+			}
+		}
+	}
+	return ret, nil
+}
+
+// bindConfigurable converts the configurable values in the options to the options designated to the nodes.
+func (r *runner) bindConfigurable(opts []Option) ([]Option, error) {
+	var values map[string]any
+	for _, opt := range opts {
+		if len(opt.paths) > 0 || opt.configurable == nil {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]any, len(opt.configurable))
+		}
+		for k, v := range opt.configurable {
+			values[k] = v
+		}
+	}
+	if values == nil {
+		return opts, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var unknown []string
+	subGraphValues := make(map[string]map[string]any)
+	ret := make([]Option, 0, len(opts)+len(values))
+	ret = append(ret, opts...)
+	for _, k := range keys {
+		targets, ok := r.configurable[k]
+		if !ok {
+			unknown = append(unknown, k)
+			continue
+		}
+		for _, t := range targets {
+			if t.field == nil {
+				if subGraphValues[t.nodeKey] == nil {
+					subGraphValues[t.nodeKey] = make(map[string]any)
+				}
+				subGraphValues[t.nodeKey][k] = values[k]
+				continue
+			}
+			o, err := t.field.Bind(values[k])
+			if err != nil {
+				return nil, fmt.Errorf("bind configurable field[%s] of node[%s] fail: %w", k, t.nodeKey, err)
+			}
+			ret = append(ret, o.DesignateNode(t.nodeKey))
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown configurable keys: %v", unknown)
+	}
+
+	for nodeKey, vs := range subGraphValues {
+		ret = append(ret, WithConfigurable(vs).DesignateNode(nodeKey))
+	}
+	return ret, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type configurableTestOption struct {
+	temperature float32
+	tenant      string
+}
+
+func TestConfigurable(t *testing.T) {
+	ctx := context.Background()
+
+	temperature := NewConfigurableField("temperature", "sampling temperature", func(v float32) Option {
+		return WithLambdaOption(func(o *configurableTestOption) { o.temperature = v })
+	})
+	tenant := NewConfigurableField("tenant", "", func(v string) Option {
+		return WithLambdaOption(func(o *configurableTestOption) { o.tenant = v })
+	})
+	newNode := func(name string) *Lambda {
+		return InvokableLambdaWithOption(func(ctx context.Context, in string, opts ...func(*configurableTestOption)) (string, error) {
+			o := &configurableTestOption{temperature: 1, tenant: "default"}
+			for _, opt := range opts {
+				opt(o)
+			}
+			return fmt.Sprintf("%s%s(%.1f,%s)", in, name, o.temperature, o.tenant), nil
+		})
+	}
+
+	sub := NewGraph[string, string]()
+	require.NoError(t, sub.AddLambdaNode("c", newNode("c"), WithConfigurableFields(tenant)))
+	require.NoError(t, sub.AddEdge(START, "c"))
+	require.NoError(t, sub.AddEdge("c", END))
+
+	g := NewGraph[string, string]()
+	require.NoError(t, g.AddLambdaNode("a", newNode("a"), WithConfigurableFields(temperature, tenant)))
+	require.NoError(t, g.AddLambdaNode("b", newNode("b"), WithConfigurableFields(temperature)))
+	require.NoError(t, g.AddGraphNode("sub", sub))
+	require.NoError(t, g.AddEdge(START, "a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.AddEdge("b", "sub"))
+	require.NoError(t, g.AddEdge("sub", END))
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "a(1.0,default)b(1.0,default)c(1.0,default)", out)
+
+	// float64 as decoded from JSON is converted to float32, and the values bound later take precedence
+	out, err = r.Invoke(ctx, "",
+		WithConfigurable(map[string]any{"temperature": 0.2, "tenant": "t0"}),
+		WithConfigurable(map[string]any{"tenant": "t1"}))
+	require.NoError(t, err)
+	assert.Equal(t, "a(0.2,t1)b(0.2,default)c(1.0,t1)", out)
+
+	_, err = r.Invoke(ctx, "", WithConfigurable(map[string]any{"top_k": 3}))
+	assert.ErrorContains(t, err, "unknown configurable keys: [top_k]")
+
+	_, err = r.Invoke(ctx, "", WithConfigurable(map[string]any{"tenant": 1}))
+	assert.ErrorContains(t, err, "expect a value of type[string], got int")
+
+	// stream
+	sr, err := r.Stream(ctx, "", WithConfigurable(map[string]any{"temperature": 0}))
+	require.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	require.NoError(t, err)
+	assert.Equal(t, "a(0.0,default)b(0.0,default)c(1.0,default)", out)
+
+	t.Run("validate", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("a", newNode("a"), WithConfigurableFields(temperature, temperature)))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", END))
+		_, err := g.Compile(ctx)
+		assert.ErrorContains(t, err, "declared more than once")
+
+		g = NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("a", newNode("a"), WithConfigurableFields(&ConfigurableField{Key: "k"})))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", END))
+		_, err = g.Compile(ctx)
+		assert.ErrorContains(t, err, "must have a key and a bind function")
+	})
+}
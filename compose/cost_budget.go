@@ -0,0 +1,324 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ModelPricing is the price of a model in USD per million tokens.
+type ModelPricing struct {
+	// PromptPerMTokens is the price of the prompt tokens.
+	PromptPerMTokens float64
+	// CachedPromptPerMTokens is the price of the cached prompt tokens, which are part of the prompt tokens.
+	// Zero means the cached prompt tokens are charged as the other prompt tokens.
+	CachedPromptPerMTokens float64
+	// CompletionPerMTokens is the price of the completion tokens.
+	CompletionPerMTokens float64
+}
+
+// Cost returns the cost in USD of the token usage.
+func (p *ModelPricing) Cost(usage *model.TokenUsage) float64 {
+	if p == nil || usage == nil {
+		return 0
+	}
+	prompt := float64(usage.PromptTokens) * p.PromptPerMTokens
+	if cached := usage.PromptTokenDetails.CachedTokens; cached > 0 && p.CachedPromptPerMTokens > 0 {
+		prompt -= float64(cached) * (p.PromptPerMTokens - p.CachedPromptPerMTokens)
+	}
+	return (prompt + float64(usage.CompletionTokens)*p.CompletionPerMTokens) / 1e6
+}
+
+// PricingRegistry maps the model names to their pricing, safe for concurrent use.
+type PricingRegistry struct {
+	mu      sync.RWMutex
+	pricing map[string]*ModelPricing
+}
+
+// NewPricingRegistry creates an empty PricingRegistry.
+func NewPricingRegistry() *PricingRegistry {
+	return &PricingRegistry{pricing: make(map[string]*ModelPricing)}
+}
+
+var defaultPricingRegistry = NewPricingRegistry()
+
+// RegisterModelPricing registers the pricing of the model to the default PricingRegistry used by WithCostBudget.
+// e.g.
+//
+//	compose.RegisterModelPricing("gpt-4o", &compose.ModelPricing{PromptPerMTokens: 2.5, CachedPromptPerMTokens: 1.25, CompletionPerMTokens: 10})
+func RegisterModelPricing(modelName string, pricing *ModelPricing) {
+	defaultPricingRegistry.Register(modelName, pricing)
+}
+
+// Register sets the pricing of the model, overriding the previous one.
+func (r *PricingRegistry) Register(modelName string, pricing *ModelPricing) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pricing[modelName] = pricing
+}
+
+// Lookup returns the pricing of the model.
+// If the model name is not registered, the pricing of its longest registered prefix is returned,
+// so that e.g. "gpt-4o-2024-08-06" is priced as "gpt-4o".
+func (r *PricingRegistry) Lookup(modelName string) (*ModelPricing, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.pricing[modelName]; ok {
+		return p, true
+	}
+	var (
+		found  *ModelPricing
+		prefix string
+	)
+	for name, p := range r.pricing {
+		if len(name) > len(prefix) && strings.HasPrefix(modelName, name) {
+			found, prefix = p, name
+		}
+	}
+	return found, found != nil
+}
+
+// EstimateCost returns the cost in USD of the token usage of the model, and false if the model has no pricing registered.
+func (r *PricingRegistry) EstimateCost(modelName string, usage *model.TokenUsage) (float64, bool) {
+	p, ok := r.Lookup(modelName)
+	if !ok {
+		return 0, false
+	}
+	return p.Cost(usage), true
+}
+
+// CostBudgetOption is the option of WithCostBudget.
+type CostBudgetOption func(o *costBudgetOptions)
+
+type costBudgetOptions struct {
+	maxUSD   float64
+	registry *PricingRegistry
+	onExceed func(ctx context.Context, err *CostBudgetExceededError) error
+}
+
+// WithPricingRegistry sets the PricingRegistry to estimate the cost, instead of the default one of RegisterModelPricing.
+func WithPricingRegistry(r *PricingRegistry) CostBudgetOption {
+	return func(o *costBudgetOptions) {
+		o.registry = r
+	}
+}
+
+// WithBudgetExceededHandler sets the handler called once the cost budget is exceeded, instead of aborting the run.
+// If the handler returns an error, the run is aborted with it, otherwise the run continues and the budget isn't enforced any more,
+// e.g. to alert and let the run finish.
+func WithBudgetExceededHandler(h func(ctx context.Context, err *CostBudgetExceededError) error) CostBudgetOption {
+	return func(o *costBudgetOptions) {
+		o.onExceed = h
+	}
+}
+
+// WithCostBudget sets the maximum cost in USD of the run, including the nested graphs.
+// The cost is estimated from the token usage reported by the chat models through callbacks, priced by the PricingRegistry,
+// and the usage of the models without pricing registered is ignored.
+// The budget is checked before each super step, so the run is aborted with *CostBudgetExceededError
+// before executing any more node once the cost exceeds the budget, unless WithBudgetExceededHandler is set.
+// The cost of a streaming output is counted once the stream is fully received, so it may be counted a few steps later.
+// It applies to the whole run, DesignateNode doesn't take effect for it.
+// e.g.
+//
+//	runnable.Invoke(ctx, input, compose.WithCostBudget(0.5))
+func WithCostBudget(maxUSD float64, opts ...CostBudgetOption) Option {
+	o := &costBudgetOptions{maxUSD: maxUSD}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return Option{
+		costBudget: o,
+	}
+}
+
+// GetRunCost returns the estimated cost in USD of the run so far, if the run is called with WithCostBudget.
+func GetRunCost(ctx context.Context) (float64, bool) {
+	t, ok := ctx.Value(costTrackerKey{}).(*costTracker)
+	if !ok {
+		return 0, false
+	}
+	return t.getSpent(), true
+}
+
+type costTrackerKey struct{}
+
+type costTracker struct {
+	opts *costBudgetOptions
+
+	mu      sync.Mutex
+	spent   float64
+	handled bool
+}
+
+// withCostBudget starts tracking the cost of the run if WithCostBudget is set,
+// returning the callback handler accumulating the cost.
+func withCostBudget(ctx context.Context, opts ...Option) (context.Context, callbacks.Handler) {
+	var o *costBudgetOptions
+	for i := range opts {
+		if opts[i].costBudget != nil {
+			o = opts[i].costBudget
+		}
+	}
+	if o == nil {
+		return ctx, nil
+	}
+	t := &costTracker{opts: o}
+	return context.WithValue(ctx, costTrackerKey{}, t), t.handler()
+}
+
+func (t *costTracker) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info == nil || info.Component != components.ComponentOfChatModel {
+				return ctx
+			}
+			if out := model.ConvCallbackOutput(output); out != nil {
+				t.add(modelNameOf(out), out.TokenUsage)
+			}
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			if info == nil || info.Component != components.ComponentOfChatModel {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				defer func() {
+					_ = recover()
+					output.Close()
+				}()
+				var (
+					name  string
+					usage *model.TokenUsage
+				)
+				for {
+					chunk, err := output.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						return
+					}
+					out := model.ConvCallbackOutput(chunk)
+					if out == nil {
+						continue
+					}
+					if n := modelNameOf(out); n != "" {
+						name = n
+					}
+					if out.TokenUsage != nil {
+						// the usage is usually reported by the last chunk, or accumulated across the chunks
+						usage = out.TokenUsage
+					}
+				}
+				t.add(name, usage)
+			}()
+			return ctx
+		}).
+		Build()
+}
+
+func modelNameOf(out *model.CallbackOutput) string {
+	if out.Config != nil && out.Config.Model != "" {
+		return out.Config.Model
+	}
+	if out.ResponseMeta != nil {
+		return out.ResponseMeta.Model
+	}
+	return ""
+}
+
+func (t *costTracker) add(modelName string, usage *model.TokenUsage) {
+	if usage == nil {
+		return
+	}
+	registry := t.opts.registry
+	if registry == nil {
+		registry = defaultPricingRegistry
+	}
+	cost, ok := registry.EstimateCost(modelName, usage)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	t.spent += cost
+	t.mu.Unlock()
+}
+
+func (t *costTracker) getSpent() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spent
+}
+
+// check returns an error if the run should be aborted because of the budget.
+func (t *costTracker) check(ctx context.Context) error {
+	t.mu.Lock()
+	if t.handled || t.spent <= t.opts.maxUSD {
+		t.mu.Unlock()
+		return nil
+	}
+	exceeded := &CostBudgetExceededError{Budget: t.opts.maxUSD, Spent: t.spent}
+	if t.opts.onExceed == nil {
+		t.mu.Unlock()
+		return exceeded
+	}
+	t.handled = true
+	t.mu.Unlock()
+
+	if err := t.opts.onExceed(ctx, exceeded); err != nil {
+		return fmt.Errorf("budget exceeded handler fail: %w", err)
+	}
+	return nil
+}
+
+func checkCostBudget(ctx context.Context) error {
+	t, ok := ctx.Value(costTrackerKey{}).(*costTracker)
+	if !ok {
+		return nil
+	}
+	return t.check(ctx)
+}
+
+// ErrCostBudgetExceeded is the sentinel of *CostBudgetExceededError.
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// CostBudgetExceededError is returned when the estimated cost of a run exceeds the budget set by WithCostBudget.
+type CostBudgetExceededError struct {
+	Budget float64
+	Spent  float64
+}
+
+func (c *CostBudgetExceededError) Error() string {
+	return fmt.Sprintf("estimated cost[$%.6f] exceeds the budget[$%.6f]", c.Spent, c.Budget)
+}
+
+// Is reports whether the target is ErrCostBudgetExceeded.
+func (c *CostBudgetExceededError) Is(target error) bool {
+	return target == ErrCostBudgetExceeded
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type costModel struct {
+	calls int
+}
+
+func (c *costModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	c.calls++
+	msg := schema.AssistantMessage("ok", nil)
+	msg.ResponseMeta = &schema.ResponseMeta{
+		Model: "test-model-v1",
+		Usage: &schema.TokenUsage{PromptTokens: 1000000, CompletionTokens: 100000},
+	}
+	return msg, nil
+}
+
+func (c *costModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := c.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func TestModelPricing(t *testing.T) {
+	p := &ModelPricing{PromptPerMTokens: 2, CachedPromptPerMTokens: 1, CompletionPerMTokens: 8}
+	usage := &model.TokenUsage{
+		PromptTokens:       1000000,
+		PromptTokenDetails: model.PromptTokenDetails{CachedTokens: 500000},
+		CompletionTokens:   250000,
+	}
+	assert.InDelta(t, 1.5+2, p.Cost(usage), 1e-9)
+
+	r := NewPricingRegistry()
+	r.Register("gpt", &ModelPricing{PromptPerMTokens: 1})
+	r.Register("gpt-4o", p)
+	got, ok := r.Lookup("gpt-4o-2024-08-06")
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+	_, ok = r.EstimateCost("claude", usage)
+	assert.False(t, ok)
+}
+
+func TestWithCostBudget(t *testing.T) {
+	ctx := context.Background()
+	registry := NewPricingRegistry()
+	// $1.1 per call
+	registry.Register("test-model", &ModelPricing{PromptPerMTokens: 1, CompletionPerMTokens: 1})
+
+	build := func(cm *costModel) Runnable[[]*schema.Message, []*schema.Message] {
+		g := NewGraph[[]*schema.Message, []*schema.Message]()
+		require.NoError(t, g.AddChatModelNode("model", cm))
+		require.NoError(t, g.AddLambdaNode("next", InvokableLambda(func(ctx context.Context, in *schema.Message) ([]*schema.Message, error) {
+			return []*schema.Message{in}, nil
+		})))
+		require.NoError(t, g.AddEdge(START, "model"))
+		require.NoError(t, g.AddEdge("model", "next"))
+		require.NoError(t, g.AddBranch("next", NewGraphBranch(func(ctx context.Context, in []*schema.Message) (string, error) {
+			if cm.calls < 5 {
+				return "model", nil
+			}
+			return END, nil
+		}, map[string]bool{"model": true, END: true})))
+		r, err := g.Compile(ctx)
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		cm := &costModel{}
+		_, err := build(cm).Invoke(ctx, []*schema.Message{schema.UserMessage("hi")},
+			WithCostBudget(3, WithPricingRegistry(registry)))
+		assert.ErrorIs(t, err, ErrCostBudgetExceeded)
+		var e *CostBudgetExceededError
+		require.True(t, errors.As(err, &e))
+		assert.InDelta(t, 3.3, e.Spent, 1e-9)
+		assert.Equal(t, 3.0, e.Budget)
+		assert.Equal(t, 3, cm.calls)
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		cm := &costModel{}
+		var handled []float64
+		_, err := build(cm).Invoke(ctx, []*schema.Message{schema.UserMessage("hi")},
+			WithCostBudget(3, WithPricingRegistry(registry), WithBudgetExceededHandler(func(ctx context.Context, err *CostBudgetExceededError) error {
+				handled = append(handled, err.Spent)
+				spent, ok := GetRunCost(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, err.Spent, spent)
+				return nil
+			})))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, cm.calls)
+		require.Len(t, handled, 1)
+		assert.InDelta(t, 3.3, handled[0], 1e-9)
+	})
+}
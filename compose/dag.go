@@ -61,6 +61,8 @@ type dagChannel struct {
 
 func (ch *dagChannel) setMergeConfig(cfg FanInMergeConfig) {
 	ch.mergeConfig.StreamMergeWithSourceEOF = cfg.StreamMergeWithSourceEOF
+	ch.mergeConfig.strategy = cfg.strategy
+	ch.mergeConfig.mergeFuncs = cfg.mergeFuncs
 }
 
 func (ch *dagChannel) load(c channel) error {
@@ -175,6 +177,13 @@ func (ch *dagChannel) get(isStream bool, name string, edgeHandler *edgeHandlerMa
 		}
 		return ch.zeroValue(), true, nil
 	}
+	if ch.mergeConfig.strategy != nil {
+		v, err := ch.mergeConfig.strategy.apply(names, valueList, isStream)
+		if err != nil {
+			return nil, false, err
+		}
+		return v, true, nil
+	}
 	if len(valueList) == 1 {
 		return valueList[0], true, nil
 	}
@@ -182,6 +191,7 @@ func (ch *dagChannel) get(isStream bool, name string, edgeHandler *edgeHandlerMa
 	mergeOpts := &mergeOptions{
 		streamMergeWithSourceEOF: ch.mergeConfig.StreamMergeWithSourceEOF,
 		names:                    names,
+		mergeFuncs:               ch.mergeConfig.mergeFuncs,
 	}
 	v, err := mergeValues(valueList, mergeOpts)
 	if err != nil {
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDAG(t *testing.T) {
@@ -221,3 +223,65 @@ func TestDAG(t *testing.T) {
 		t.Fatal("cannot validate loop")
 	}
 }
+
+func TestDAGBranchJoin(t *testing.T) {
+	ctx := context.Background()
+	suffix := func(s string) *Lambda {
+		return InvokableLambda(func(ctx context.Context, in string) (string, error) { return in + s, nil })
+	}
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("A", suffix("A")))
+	assert.NoError(t, g.AddLambdaNode("B", suffix("B")))
+	assert.NoError(t, g.AddLambdaNode("B2", suffix("2"), WithOutputKey("B2")))
+	assert.NoError(t, g.AddLambdaNode("C", suffix("C"), WithOutputKey("C")))
+	assert.NoError(t, g.AddLambdaNode("E", suffix("E"), WithOutputKey("E")))
+	assert.NoError(t, g.AddLambdaNode("D", InvokableLambda(func(ctx context.Context, in map[string]any) (string, error) {
+		ret := ""
+		for _, k := range []string{"B2", "C", "E"} {
+			if v, ok := in[k]; ok {
+				ret += k + "=" + v.(string) + ";"
+			}
+		}
+		return ret, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "A"))
+	assert.NoError(t, g.AddEdge(START, "E"))
+	assert.NoError(t, g.AddBranch("A", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		if in == "bA" {
+			return "B", nil
+		}
+		return "C", nil
+	}, map[string]bool{"B": true, "C": true})))
+	assert.NoError(t, g.AddEdge("B", "B2"))
+	assert.NoError(t, g.AddEdge("B2", "D"))
+	assert.NoError(t, g.AddEdge("C", "D"))
+	assert.NoError(t, g.AddEdge("E", "D"))
+	assert.NoError(t, g.AddEdge("D", END))
+
+	r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	assert.NoError(t, err)
+
+	// the skipping of C lets D fire once B2 and E complete, and vice versa
+	out, err := r.Invoke(ctx, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2=bAB2;E=bE;", out)
+	out, err = r.Invoke(ctx, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, "C=cAC;E=cE;", out)
+
+	// END can not be reached when all of its predecessors are skipped
+	g2 := NewGraph[string, string]()
+	assert.NoError(t, g2.AddLambdaNode("A", suffix("A")))
+	assert.NoError(t, g2.AddLambdaNode("B", suffix("B")))
+	assert.NoError(t, g2.AddLambdaNode("C", suffix("C")))
+	assert.NoError(t, g2.AddEdge(START, "A"))
+	assert.NoError(t, g2.AddBranch("A", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		return "C", nil
+	}, map[string]bool{"B": true, "C": true})))
+	assert.NoError(t, g2.AddEdge("B", END))
+	r2, err := g2.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	assert.NoError(t, err)
+	_, err = r2.Invoke(ctx, "x")
+	assert.ErrorIs(t, err, ErrEndNodeSkipped)
+}
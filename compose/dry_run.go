@@ -0,0 +1,290 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// DryRunReport describes how the data flows through a graph, without executing any component.
+type DryRunReport struct {
+	Name                  string
+	InputType, OutputType reflect.Type
+	// Nodes are the nodes of the graph, sorted by key.
+	Nodes []*DryRunNode
+	// Branches are the branches of the graph, sorted by start node.
+	Branches []*DryRunBranch
+}
+
+// DryRunNode describes a node of the graph.
+type DryRunNode struct {
+	Key  string
+	Name string
+	// Group is the logical group of the node, passed from WithNodeGroup.
+	Group     string
+	Component components.Component
+	// InputType and OutputType are the resolved types of the node, before InputKey and after OutputKey are applied.
+	InputType, OutputType reflect.Type
+	InputKey, OutputKey   string
+	// Implemented lists the paradigms implemented by the node, i.e. Invoke, Stream, Collect and Transform,
+	// the others are converted from them when needed.
+	Implemented []string
+	// Predecessors are the nodes sending data to the node, including by branches.
+	Predecessors []string
+	// Successors are the nodes the node sends data to, including by branches.
+	Successors []string
+	// MergeInputs reports the node has several predecessors, whose outputs are merged as its input.
+	// MergeBy tells how they are merged.
+	MergeInputs bool
+	MergeBy     string
+	// ConcatInputWhenStreaming reports that when the graph is streamed, the input stream is concatenated
+	// before the node runs, as it doesn't implement Collect nor Transform.
+	ConcatInputWhenStreaming bool
+	// ConcatOutputWhenInvoking reports that when the graph is invoked, the output stream of the node is concatenated,
+	// as it doesn't implement Invoke.
+	ConcatOutputWhenInvoking bool
+	// StreamCopies is the number of copies of the output stream made when the graph is streamed,
+	// one for each successor and branch. A copy is made only if there are more than one readers.
+	StreamCopies int
+	// SubGraph is the report of the sub graph, if the node is a graph.
+	SubGraph *DryRunReport
+}
+
+// DryRunBranch describes a branch of the graph.
+type DryRunBranch struct {
+	StartNode string
+	EndNodes  []string
+	// ConcatInputWhenStreaming reports that when the graph is streamed, the stream is concatenated for the condition,
+	// as the branch is not created by a stream condition, e.g. NewStreamGraphBranch.
+	ConcatInputWhenStreaming bool
+}
+
+// DryRun compiles the graph and reports the resolved types of the nodes, the merge points,
+// and where the streams are concatenated or copied, without executing any component.
+// Compiling fails as usual if the types mismatch. Like Compile, the graph can't be modified afterward.
+// e.g.
+//
+//	report, err := compose.DryRun(ctx, graph)
+//	if err != nil {...}
+//	fmt.Println(report)
+func DryRun(ctx context.Context, g AnyGraph, opts ...GraphCompileOption) (*DryRunReport, error) {
+	var info *GraphInfo
+	opts = append(opts, WithGraphCompileCallbacks(&subGraphCompileCallback{
+		closure: func(_ context.Context, gi *GraphInfo) {
+			info = gi
+		},
+	}))
+	if _, err := g.compile(ctx, newGraphCompileOptions(opts...)); err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("graph info not available after compiling")
+	}
+	return newDryRunReport(info), nil
+}
+
+func newDryRunReport(info *GraphInfo) *DryRunReport {
+	report := &DryRunReport{
+		Name:       info.Name,
+		InputType:  info.InputType,
+		OutputType: info.OutputType,
+	}
+
+	predecessors := make(map[string][]string)
+	successors := make(map[string][]string)
+	readers := make(map[string]int)
+	for start, ends := range info.DataEdges {
+		for _, end := range ends {
+			predecessors[end] = append(predecessors[end], start)
+			successors[start] = append(successors[start], end)
+		}
+		readers[start] += len(ends)
+	}
+	for start, branches := range info.Branches {
+		for i := range branches {
+			b := &branches[i]
+			ends := make([]string, 0, len(b.endNodes))
+			for end := range b.endNodes {
+				ends = append(ends, end)
+				if !b.noDataFlow {
+					predecessors[end] = append(predecessors[end], start)
+					successors[start] = append(successors[start], end)
+				}
+			}
+			sort.Strings(ends)
+			readers[start]++
+			report.Branches = append(report.Branches, &DryRunBranch{
+				StartNode:                start,
+				EndNodes:                 ends,
+				ConcatInputWhenStreaming: b.paradigms&paradigmCollect == 0,
+			})
+		}
+	}
+	sort.SliceStable(report.Branches, func(i, j int) bool {
+		return report.Branches[i].StartNode < report.Branches[j].StartNode
+	})
+
+	mergeConfigs := newGraphCompileOptions(info.CompileOptions...).mergeConfigs
+	for key, n := range info.Nodes {
+		node := &DryRunNode{
+			Key:          key,
+			Name:         n.Name,
+			Group:        n.Group,
+			Component:    n.Component,
+			InputType:    n.InputType,
+			OutputType:   n.OutputType,
+			InputKey:     n.InputKey,
+			OutputKey:    n.OutputKey,
+			Implemented:  paradigmNames(n.paradigms),
+			Predecessors: dedupSorted(predecessors[key]),
+			Successors:   dedupSorted(successors[key]),
+		}
+		if n.GraphInfo != nil {
+			node.SubGraph = newDryRunReport(n.GraphInfo)
+		}
+
+		node.ConcatInputWhenStreaming = n.paradigms.concatsInputWhenStreaming()
+		node.ConcatOutputWhenInvoking = n.paradigms.concatsOutputWhenInvoking()
+		if readers[key] > 1 {
+			node.StreamCopies = readers[key]
+		}
+
+		if len(node.Predecessors) > 1 {
+			node.MergeInputs = true
+			nodeInfo, _ := getNodeInfo(n.GraphAddNodeOpts...)
+			inputType := node.InputType
+			if node.InputKey != "" {
+				inputType = reflect.TypeOf(map[string]any{})
+			}
+			switch {
+			case nodeInfo.mergeStrategy != nil || mergeConfigs[key].strategy != nil:
+				node.MergeBy = "MergeStrategy"
+			case inputType != nil && inputType.Kind() == reflect.Map:
+				node.MergeBy = "map keys"
+			default:
+				node.MergeBy = "merge function registered for " + typeName(inputType)
+			}
+		}
+
+		report.Nodes = append(report.Nodes, node)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Key < report.Nodes[j].Key
+	})
+
+	return report
+}
+
+func paradigmNames(p runnableParadigm) []string {
+	var ret []string
+	for _, v := range []struct {
+		p    runnableParadigm
+		name string
+	}{
+		{paradigmInvoke, "Invoke"},
+		{paradigmStream, "Stream"},
+		{paradigmCollect, "Collect"},
+		{paradigmTransform, "Transform"},
+	} {
+		if p&v.p != 0 {
+			ret = append(ret, v.name)
+		}
+	}
+	return ret
+}
+
+func dedupSorted(keys []string) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(keys))
+	ret := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !set[k] {
+			set[k] = true
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.String()
+}
+
+// String formats the report for humans.
+func (r *DryRunReport) String() string {
+	sb := &strings.Builder{}
+	r.format(sb, "")
+	return sb.String()
+}
+
+func (r *DryRunReport) format(sb *strings.Builder, indent string) {
+	name := r.Name
+	if name == "" {
+		name = "graph"
+	}
+	fmt.Fprintf(sb, "%s%s: %s -> %s\n", indent, name, typeName(r.InputType), typeName(r.OutputType))
+	for _, n := range r.Nodes {
+		fmt.Fprintf(sb, "%s  node[%s] (%s): %s -> %s", indent, n.Key, n.Component, typeName(n.InputType), typeName(n.OutputType))
+		if n.Group != "" {
+			fmt.Fprintf(sb, ", group: %s", n.Group)
+		}
+		if n.InputKey != "" {
+			fmt.Fprintf(sb, ", input key: %s", n.InputKey)
+		}
+		if n.OutputKey != "" {
+			fmt.Fprintf(sb, ", output key: %s", n.OutputKey)
+		}
+		if len(n.Implemented) > 0 {
+			fmt.Fprintf(sb, ", implements: %s", strings.Join(n.Implemented, "/"))
+		}
+		sb.WriteString("\n")
+		if n.MergeInputs {
+			fmt.Fprintf(sb, "%s    merges inputs of [%s] by %s\n", indent, strings.Join(n.Predecessors, ", "), n.MergeBy)
+		}
+		if n.ConcatInputWhenStreaming {
+			fmt.Fprintf(sb, "%s    input stream is concatenated when streaming\n", indent)
+		}
+		if n.ConcatOutputWhenInvoking {
+			fmt.Fprintf(sb, "%s    output stream is concatenated when invoking\n", indent)
+		}
+		if n.StreamCopies > 1 {
+			fmt.Fprintf(sb, "%s    output stream is copied %d times when streaming\n", indent, n.StreamCopies)
+		}
+		if n.SubGraph != nil {
+			n.SubGraph.format(sb, indent+"    ")
+		}
+	}
+	for _, b := range r.Branches {
+		fmt.Fprintf(sb, "%s  branch from [%s] to [%s]", indent, b.StartNode, strings.Join(b.EndNodes, ", "))
+		if b.ConcatInputWhenStreaming {
+			sb.WriteString(", input stream is concatenated when streaming")
+		}
+		sb.WriteString("\n")
+	}
+}
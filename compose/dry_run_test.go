@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	sub := NewChain[string, string]().AppendLambda(InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in + "!", nil
+	}))
+
+	g := NewGraph[string, map[string]any]()
+	assert.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in, nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("b", StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{in, "b"}), nil
+	}), WithOutputKey("b"), WithNodeGroup("streaming")))
+	assert.NoError(t, g.AddLambdaNode("c", TransformableLambda(func(ctx context.Context, in *schema.StreamReader[string]) (*schema.StreamReader[string], error) {
+		return in, nil
+	}), WithOutputKey("c")))
+	assert.NoError(t, g.AddGraphNode("sub", sub, WithOutputKey("sub")))
+	assert.NoError(t, g.AddPassthroughNode("join"))
+	assert.NoError(t, g.AddEdge(START, "a"))
+	assert.NoError(t, g.AddEdge("a", "b"))
+	assert.NoError(t, g.AddEdge("a", "c"))
+	assert.NoError(t, g.AddEdge("a", "sub"))
+	assert.NoError(t, g.AddEdge("b", "join"))
+	assert.NoError(t, g.AddEdge("c", "join"))
+	assert.NoError(t, g.AddEdge("sub", "join"))
+	assert.NoError(t, g.AddBranch("join", NewGraphBranch(func(ctx context.Context, in map[string]any) (string, error) {
+		return END, nil
+	}, map[string]bool{END: true})))
+
+	report, err := DryRun(ctx, g, WithGraphName("test"))
+	assert.NoError(t, err)
+	assert.Equal(t, "test", report.Name)
+	assert.Len(t, report.Nodes, 5)
+
+	nodes := make(map[string]*DryRunNode)
+	for _, n := range report.Nodes {
+		nodes[n.Key] = n
+	}
+
+	a := nodes["a"]
+	assert.Equal(t, []string{"Invoke"}, a.Implemented)
+	assert.True(t, a.ConcatInputWhenStreaming)
+	assert.False(t, a.ConcatOutputWhenInvoking)
+	assert.Equal(t, 3, a.StreamCopies)
+	assert.Equal(t, []string{"b", "c", "sub"}, a.Successors)
+
+	b := nodes["b"]
+	assert.True(t, b.ConcatInputWhenStreaming)
+	assert.True(t, b.ConcatOutputWhenInvoking)
+	assert.Equal(t, "b", b.OutputKey)
+	assert.Equal(t, "streaming", b.Group)
+
+	c := nodes["c"]
+	assert.False(t, c.ConcatInputWhenStreaming)
+	assert.True(t, c.ConcatOutputWhenInvoking)
+	assert.Equal(t, reflect.TypeOf(""), c.InputType)
+
+	join := nodes["join"]
+	assert.True(t, join.MergeInputs)
+	assert.Equal(t, "map keys", join.MergeBy)
+	assert.Equal(t, []string{"b", "c", "sub"}, join.Predecessors)
+	assert.Equal(t, reflect.TypeOf(map[string]any{}), join.InputType)
+	assert.False(t, join.ConcatInputWhenStreaming)
+
+	assert.NotNil(t, nodes["sub"].SubGraph)
+	assert.Len(t, nodes["sub"].SubGraph.Nodes, 1)
+	assert.False(t, nodes["sub"].ConcatInputWhenStreaming)
+
+	assert.Len(t, report.Branches, 1)
+	assert.Equal(t, []string{END}, report.Branches[0].EndNodes)
+	assert.True(t, report.Branches[0].ConcatInputWhenStreaming)
+
+	s := report.String()
+	assert.Contains(t, s, "test: string -> map[string]interface {}")
+	assert.Contains(t, s, "node[join] (Passthrough)")
+	assert.Contains(t, s, "group: streaming, output key: b")
+	assert.Contains(t, s, "output stream is copied 3 times when streaming")
+
+	// the graph can still be compiled and run
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	out, err := r.Invoke(ctx, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"b": "xb", "c": "x", "sub": "x!"}, out)
+
+	// type mismatches are reported
+	bad := NewGraph[string, string]()
+	assert.NoError(t, bad.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (int, error) {
+		return 0, nil
+	})))
+	assert.NoError(t, bad.AddEdge(START, "a"))
+	assert.Error(t, bad.AddEdge("a", END))
+	_, err = DryRun(ctx, bad)
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+// EdgeTransform converts the output of an edge's start node to the input of its end node,
+// created by NewEdgeTransform and attached to an edge by Graph.AddEdgeWithTransform.
+type EdgeTransform struct {
+	inputType, outputType     reflect.Type
+	inputHelper, outputHelper *genericHelper
+	handler                   handlerPair
+}
+
+// NewEdgeTransform creates an EdgeTransform from fn.
+// In streaming mode, fn is applied to every chunk of the upstream stream,
+// so it must be able to convert a chunk of I into a chunk of O independently.
+// e.g.
+//
+//	// adapt a *schema.Message output to a string input without a passthrough lambda node
+//	toContent := compose.NewEdgeTransform(func(m *schema.Message) (string, error) {
+//		return m.Content, nil
+//	})
+//	err := graph.AddEdgeWithTransform("chat_model", "post_process", toContent)
+func NewEdgeTransform[I, O any](fn func(I) (O, error)) *EdgeTransform {
+	return &EdgeTransform{
+		inputType:    generic.TypeOf[I](),
+		outputType:   generic.TypeOf[O](),
+		inputHelper:  newGenericHelper[I, I](),
+		outputHelper: newGenericHelper[O, O](),
+		handler: handlerPair{
+			invoke: func(value any) (any, error) {
+				in, ok := value.(I)
+				if !ok && value != nil {
+					var i I
+					return nil, fmt.Errorf("edge transform expects input type: %T, actual type: %T", i, value)
+				}
+				return fn(in)
+			},
+			transform: func(sr streamReader) streamReader {
+				return packStreamReader(schema.StreamReaderWithConvert(sr.toAnyStreamReader(), func(v any) (O, error) {
+					in, ok := v.(I)
+					if !ok && v != nil {
+						var i I
+						var o O
+						return o, fmt.Errorf("edge transform expects input type: %T, actual type: %T", i, v)
+					}
+					return fn(in)
+				}))
+			},
+		},
+	}
+}
+
+// validateEdgeTransform checks the types on both sides of the transform, infers the types of passthrough nodes,
+// and registers the runtime handlers of the edge.
+func (g *graph) validateEdgeTransform(startNode, endNode string, t *EdgeTransform) error {
+	if _, ok := g.handlerOnEdges[startNode]; !ok {
+		g.handlerOnEdges[startNode] = make(map[string][]handlerPair)
+	}
+
+	startNodeOutputType := g.getNodeOutputType(startNode)
+	if startNodeOutputType == nil {
+		g.nodes[startNode].cr.inputType = t.inputType
+		g.nodes[startNode].cr.outputType = t.inputType
+		g.nodes[startNode].cr.genericHelper = t.inputHelper
+	} else {
+		switch checkAssignable(startNodeOutputType, t.inputType) {
+		case assignableTypeMustNot:
+			return newTypeMismatchErr(t.inputType, startNodeOutputType,
+				"graph edge[%s]-[%s]: start node's output type[%s] and edge transform's input type[%s] mismatch", startNode, endNode, startNodeOutputType.String(), t.inputType.String())
+		case assignableTypeMay:
+			g.handlerOnEdges[startNode][endNode] = append(g.handlerOnEdges[startNode][endNode], t.inputHelper.inputConverter)
+		}
+	}
+
+	g.handlerOnEdges[startNode][endNode] = append(g.handlerOnEdges[startNode][endNode], t.handler)
+
+	endNodeInputType := g.getNodeInputType(endNode)
+	if endNodeInputType == nil {
+		g.nodes[endNode].cr.inputType = t.outputType
+		g.nodes[endNode].cr.outputType = t.outputType
+		g.nodes[endNode].cr.genericHelper = t.outputHelper
+	} else {
+		switch checkAssignable(t.outputType, endNodeInputType) {
+		case assignableTypeMustNot:
+			return newTypeMismatchErr(endNodeInputType, t.outputType,
+				"graph edge[%s]-[%s]: edge transform's output type[%s] and end node's input type[%s] mismatch", startNode, endNode, t.outputType.String(), endNodeInputType.String())
+		case assignableTypeMay:
+			g.handlerOnEdges[startNode][endNode] = append(g.handlerOnEdges[startNode][endNode], g.getNodeGenericHelper(endNode).inputConverter)
+		}
+	}
+
+	return nil
+}
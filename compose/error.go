@@ -21,13 +21,104 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/einoerr"
 )
 
 // ErrExceedMaxSteps graph will throw this error when the number of steps exceeds the maximum number of steps.
-var ErrExceedMaxSteps = errors.New("exceeds max steps")
+// It's the same as einoerr.ErrMaxSteps, use errors.As with *einoerr.MaxStepsError to get the limit.
+var ErrExceedMaxSteps = einoerr.ErrMaxSteps
+
+// ErrEndNodeSkipped is returned when a graph in AllPredecessor trigger mode can never reach END,
+// because every predecessor of END has been skipped by branches.
+var ErrEndNodeSkipped = errors.New("end node has been skipped by branches")
+
+// ErrNodePanic is the sentinel of errors converted from a panic inside node execution.
+// Use errors.Is(err, ErrNodePanic) to check it, or errors.As with *NodePanicError to get the details.
+var ErrNodePanic = errors.New("node panic")
+
+// NodePanicError is returned when a node panics during execution,
+// including its state pre-handler and post-handler.
+// It carries the key and component type of the node, where the panic happened, the recovered value and the captured stack.
+// Panic recovery can be disabled by WithNodePanicRecoveryDisabled at compile time.
+type NodePanicError struct {
+	NodeKey   string
+	Component components.Component
+	Phase     NodePanicPhase
+	// Info is the value recovered from the panic as it is.
+	Info  any
+	Stack []byte
+}
+
+// NodePanicPhase tells where a node panics.
+type NodePanicPhase string
+
+const (
+	// NodePanicPhaseRun means the node itself panics.
+	NodePanicPhaseRun NodePanicPhase = "run"
+	// NodePanicPhasePreHandler means the state pre-handler of the node panics.
+	NodePanicPhasePreHandler NodePanicPhase = "pre_handler"
+	// NodePanicPhasePostHandler means the state post-handler of the node panics.
+	NodePanicPhasePostHandler NodePanicPhase = "post_handler"
+)
+
+func (n *NodePanicError) Error() string {
+	return fmt.Sprintf("node[%s] of component[%s] panic in %s: %v, \nstack: %s", n.NodeKey, n.Component, n.Phase, n.Info, string(n.Stack))
+}
+
+// Is reports whether the target is ErrNodePanic.
+func (n *NodePanicError) Is(target error) bool {
+	return target == ErrNodePanic
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (n *NodePanicError) Unwrap() error {
+	if err, ok := n.Info.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ErrNodeVisitBudgetExceeded is the sentinel of *NodeVisitBudgetExceededError.
+var ErrNodeVisitBudgetExceeded = errors.New("node visit budget exceeded")
+
+// NodeVisitBudgetExceededError is returned when a node is about to run more times in a single run
+// than its budget set by WithNodeVisitBudgets.
+type NodeVisitBudgetExceededError struct {
+	NodeKey string
+	Budget  int
+	Step    int
+}
+
+func (n *NodeVisitBudgetExceededError) Error() string {
+	return fmt.Sprintf("node[%s] exceeds its visit budget[%d] at step[%d]", n.NodeKey, n.Budget, n.Step)
+}
+
+// Is reports whether the target is ErrNodeVisitBudgetExceeded.
+func (n *NodeVisitBudgetExceededError) Is(target error) bool {
+	return target == ErrNodeVisitBudgetExceeded
+}
+
+func newNodePanicError(ta *task, phase NodePanicPhase, info any, stack []byte) error {
+	e := &NodePanicError{
+		NodeKey: ta.nodeKey,
+		Phase:   phase,
+		Info:    info,
+		Stack:   stack,
+	}
+	if ta.call != nil && ta.call.action != nil && ta.call.action.meta != nil {
+		e.Component = ta.call.action.meta.component
+	}
+	return e
+}
 
 func newUnexpectedInputTypeErr(expected reflect.Type, got reflect.Type) error {
-	return fmt.Errorf("unexpected input type. expected: %v, got: %v", expected, got)
+	return &einoerr.TypeMismatchError{Expected: expected, Actual: got}
+}
+
+func newTypeMismatchErr(expected, got reflect.Type, format string, args ...any) error {
+	return &einoerr.TypeMismatchError{Expected: expected, Actual: got, Detail: fmt.Sprintf(format, args...)}
 }
 
 type defaultImplAction string
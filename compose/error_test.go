@@ -135,3 +135,57 @@ func TestContextCancelDuringRun(t *testing.T) {
 	unwrappedErr := ie.Unwrap()
 	assert.ErrorIs(t, unwrappedErr, context.Canceled)
 }
+
+func TestNodePanicError(t *testing.T) {
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		panic("boom")
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+
+	ctx := context.Background()
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "input")
+	assert.ErrorIs(t, err, ErrNodePanic)
+	var pe *NodePanicError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "1", pe.NodeKey)
+	assert.Equal(t, ComponentOfLambda, pe.Component)
+	assert.Equal(t, NodePanicPhaseRun, pe.Phase)
+	assert.Equal(t, "boom", pe.Info)
+	assert.NotEmpty(t, pe.Stack)
+
+	// the value panicked in the state handler is kept as it is
+	boom := errors.New("boom")
+	g = NewGraph[string, string](WithGenLocalState(func(ctx context.Context) *struct{} { return &struct{}{} }))
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input, nil
+	}), WithStatePreHandler(func(ctx context.Context, in string, state *struct{}) (string, error) {
+		panic(boom)
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	r, err = g.Compile(ctx)
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, "input")
+	assert.ErrorIs(t, err, ErrNodePanic)
+	assert.ErrorIs(t, err, boom)
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, NodePanicPhasePreHandler, pe.Phase)
+	assert.Equal(t, boom, pe.Info)
+
+	g = NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		panic("boom")
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	r, err = g.Compile(ctx, WithNodePanicRecoveryDisabled())
+	assert.NoError(t, err)
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = r.Invoke(ctx, "input")
+	})
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ExecutionMetrics is the resource accounting of a graph run, reported by WithExecutionMetrics.
+type ExecutionMetrics struct {
+	// GraphName is the name of the graph, set by WithGraphName.
+	GraphName string
+	// Duration is the time of the run. In stream mode, it's until the output stream is fully received or closed.
+	Duration time.Duration
+	// Err is the error of the run, if any.
+	Err error
+	// Nodes is the accounting of the nodes which have run, by node key.
+	Nodes map[string]*NodeMetrics
+}
+
+// NodeMetrics is the resource accounting of a node in a graph run, accumulated over its executions.
+type NodeMetrics struct {
+	// Runs is the number of times the node is executed.
+	Runs int
+	// Duration is the total time of the executions.
+	// In stream mode, an execution ends once the node returns its output stream.
+	Duration time.Duration
+	// AllocBytes and AllocObjects are the heap allocations of the process during the executions,
+	// which include the allocations of the nodes running in parallel and other goroutines of the process, so they're approximate.
+	AllocBytes   uint64
+	AllocObjects uint64
+	// Goroutines is the number of goroutines spawned by the graph for the node,
+	// i.e. to execute it in parallel with the other nodes of the same step.
+	Goroutines int
+	// StreamCopies is the number of readers the output streams of the node are copied to, e.g. for multiple successors and branches.
+	StreamCopies int
+	// PeakBufferedChunks is the max number of the chunks of a copied output stream buffered for the slowest reader,
+	// i.e. the chunks received by the fastest reader but not by the slowest one yet.
+	PeakBufferedChunks int
+}
+
+// WithExecutionMetrics enables the instrumentation mode of the graph, which accounts the allocations, the goroutines
+// and the buffered stream chunks of each node in a run, and calls report with the metrics once the run is done.
+// The instrumentation has overhead, e.g. reading the runtime metrics around each node execution, so it's meant for profiling and tuning.
+// It doesn't apply to the subgraphs, which can set it by WithGraphCompileOptions, the subgraph nodes are accounted as a whole.
+// e.g.
+//
+//	r, err := g.Compile(ctx, compose.WithExecutionMetrics(func(ctx context.Context, m *compose.ExecutionMetrics) {
+//		for key, n := range m.Nodes {
+//			log.Printf("node %s: runs=%d alloc=%dB goroutines=%d peak_buffered=%d", key, n.Runs, n.AllocBytes, n.Goroutines, n.PeakBufferedChunks)
+//		}
+//	}))
+func WithExecutionMetrics(report func(ctx context.Context, m *ExecutionMetrics)) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.executionMetrics = report
+	}
+}
+
+type executionMetricsKey struct{}
+
+// metricsCollector collects the ExecutionMetrics of a graph run, safe for concurrent use.
+type metricsCollector struct {
+	mu      sync.Mutex
+	start   time.Time
+	metrics *ExecutionMetrics
+	report  func(ctx context.Context, m *ExecutionMetrics)
+}
+
+// withExecutionMetrics sets the collector of the run into ctx if the instrumentation mode is enabled.
+// The collector of the parent graph is overridden with nil, so that it doesn't collect the nodes of the subgraphs.
+func (r *runner) withExecutionMetrics(ctx context.Context) (context.Context, *metricsCollector) {
+	if r.options.executionMetrics == nil {
+		if ctx.Value(executionMetricsKey{}) != nil {
+			ctx = context.WithValue(ctx, executionMetricsKey{}, (*metricsCollector)(nil))
+		}
+		return ctx, nil
+	}
+	c := &metricsCollector{
+		start:   time.Now(),
+		metrics: &ExecutionMetrics{GraphName: r.options.graphName, Nodes: make(map[string]*NodeMetrics)},
+		report:  r.options.executionMetrics,
+	}
+	return context.WithValue(ctx, executionMetricsKey{}, c), c
+}
+
+func getMetricsCollector(ctx context.Context) *metricsCollector {
+	c, _ := ctx.Value(executionMetricsKey{}).(*metricsCollector)
+	return c
+}
+
+// node returns the metrics of the node, the caller should hold the lock.
+func (c *metricsCollector) node(key string) *NodeMetrics {
+	n, ok := c.metrics.Nodes[key]
+	if !ok {
+		n = &NodeMetrics{}
+		c.metrics.Nodes[key] = n
+	}
+	return n
+}
+
+var allocSamples = []string{"/gc/heap/allocs:bytes", "/gc/heap/allocs:objects"}
+
+func readAllocs() (bytes, objects uint64) {
+	samples := []metrics.Sample{{Name: allocSamples[0]}, {Name: allocSamples[1]}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		bytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		objects = samples[1].Value.Uint64()
+	}
+	return bytes, objects
+}
+
+// startNode starts accounting an execution of the node, the returned function ends it.
+func (c *metricsCollector) startNode(key string) func() {
+	start := time.Now()
+	bytes, objects := readAllocs()
+	return func() {
+		endBytes, endObjects := readAllocs()
+		d := time.Since(start)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		n := c.node(key)
+		n.Runs++
+		n.Duration += d
+		n.AllocBytes += endBytes - bytes
+		n.AllocObjects += endObjects - objects
+	}
+}
+
+func (c *metricsCollector) addGoroutine(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.node(key).Goroutines++
+}
+
+// trackCopies counts the chunks received by each copy of the output stream of the node,
+// to find the peak number of chunks buffered for the slowest copy.
+func (c *metricsCollector) trackCopies(key string, copies []any) {
+	var received []int
+	for i := range copies {
+		sr, ok := copies[i].(streamReader)
+		if !ok {
+			return
+		}
+		idx := len(received)
+		received = append(received, 0)
+		copies[i] = sr.withRecvHook(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			received[idx]++
+			lo, hi := received[0], received[0]
+			for _, r := range received[1:] {
+				if r < lo {
+					lo = r
+				}
+				if r > hi {
+					hi = r
+				}
+			}
+			if n := c.node(key); hi-lo > n.PeakBufferedChunks {
+				n.PeakBufferedChunks = hi - lo
+			}
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.node(key).StreamCopies += len(copies)
+}
+
+// finish reports the metrics of the run. In stream mode, it's reported once the output stream is fully received or closed.
+func (c *metricsCollector) finish(ctx context.Context, result any, err error) any {
+	if sr, ok := result.(streamReader); ok && err == nil {
+		return sr.withDoneHook(func(err error) {
+			c.doReport(ctx, err)
+		})
+	}
+	c.doReport(ctx, err)
+	return result
+}
+
+// streamWithRecvHook calls hook for each chunk received from the stream.
+func streamWithRecvHook[T any](sr *schema.StreamReader[T], hook func()) *schema.StreamReader[T] {
+	return schema.StreamReaderWithConvert(sr, func(t T) (T, error) {
+		hook()
+		return t, nil
+	})
+}
+
+// streamWithDoneHook calls hook once the stream is fully received, fails, or is closed by the reader.
+func streamWithDoneHook[T any](sr *schema.StreamReader[T], hook func(err error)) *schema.StreamReader[T] {
+	nsr, sw := schema.Pipe[T](0)
+	go func() {
+		var err error
+		defer func() {
+			sr.Close()
+			sw.Close()
+			hook(err)
+		}()
+
+		for {
+			chunk, e := sr.Recv()
+			if errors.Is(e, io.EOF) {
+				return
+			}
+			if closed := sw.Send(chunk, e); closed {
+				return
+			}
+			if e != nil {
+				err = e
+				return
+			}
+		}
+	}()
+	return nsr
+}
+
+func (c *metricsCollector) doReport(ctx context.Context, err error) {
+	c.mu.Lock()
+	m := c.metrics
+	m.Duration = time.Since(c.start)
+	m.Err = err
+	c.mu.Unlock()
+
+	defer func() {
+		_ = recover() // a misbehaving report must not affect the graph run
+	}()
+	c.report(ctx, m)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+var metricsSink []byte
+
+func TestExecutionMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		reports []*ExecutionMetrics
+	)
+	report := func(_ context.Context, m *ExecutionMetrics) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, m)
+	}
+
+	t.Run("invoke", func(t *testing.T) {
+		reports = nil
+		g := NewGraph[string, map[string]any]()
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			metricsSink = make([]byte, 1<<20)
+			return in + "a", nil
+		}), WithOutputKey("a")))
+		require.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in + "b", nil
+		}), WithOutputKey("b")))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge(START, "b"))
+		require.NoError(t, g.AddEdge("a", END))
+		require.NoError(t, g.AddEdge("b", END))
+		r, err := g.Compile(ctx, WithGraphName("g"), WithExecutionMetrics(report))
+		require.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "x")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "xa", "b": "xb"}, out)
+
+		require.Len(t, reports, 1)
+		m := reports[0]
+		assert.Equal(t, "g", m.GraphName)
+		assert.NoError(t, m.Err)
+		require.Contains(t, m.Nodes, "a")
+		require.Contains(t, m.Nodes, "b")
+		assert.Equal(t, 1, m.Nodes["a"].Runs)
+		assert.Equal(t, 1, m.Nodes["b"].Runs)
+		assert.GreaterOrEqual(t, m.Nodes["a"].AllocBytes, uint64(1<<20))
+		// one of the nodes of the step runs synchronously, the other in a new goroutine
+		assert.Equal(t, 1, m.Nodes["a"].Goroutines+m.Nodes["b"].Goroutines)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		reports = nil
+		g := NewGraph[string, map[string]any]()
+		require.NoError(t, g.AddLambdaNode("src", StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+			return schema.StreamReaderFromArray([]string{"1", "2", "3"}), nil
+		})))
+		passthrough := TransformableLambda(func(ctx context.Context, in *schema.StreamReader[string]) (*schema.StreamReader[string], error) {
+			return in, nil
+		})
+		require.NoError(t, g.AddLambdaNode("x", passthrough, WithOutputKey("x")))
+		require.NoError(t, g.AddLambdaNode("y", passthrough, WithOutputKey("y")))
+		require.NoError(t, g.AddEdge(START, "src"))
+		require.NoError(t, g.AddEdge("src", "x"))
+		require.NoError(t, g.AddEdge("src", "y"))
+		require.NoError(t, g.AddEdge("x", END))
+		require.NoError(t, g.AddEdge("y", END))
+		r, err := g.Compile(ctx, WithExecutionMetrics(report))
+		require.NoError(t, err)
+
+		sr, err := r.Stream(ctx, "x")
+		require.NoError(t, err)
+		mu.Lock()
+		// reported once the output stream is received
+		assert.Empty(t, reports)
+		mu.Unlock()
+		for {
+			_, err = sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+		sr.Close()
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(reports) == 1
+		}, time.Second, time.Millisecond)
+		m := reports[0]
+		require.Contains(t, m.Nodes, "src")
+		assert.Equal(t, 2, m.Nodes["src"].StreamCopies)
+		assert.LessOrEqual(t, m.Nodes["src"].PeakBufferedChunks, 3)
+	})
+}
@@ -440,6 +440,22 @@ func checkAndExtractFromMapKey(fromMapKey string, input reflect.Value) (reflect.
 }
 
 func checkAndExtractFieldType(paths []string, typ reflect.Type) (extracted reflect.Type, remainingPaths FieldPath, err error) {
+	key := fieldPathKey{typ: typ, path: strings.Join(paths, pathSeparator)}
+	if r, ok := compileCache.fieldPaths.Load(key); ok {
+		res := r.(*fieldPathResult)
+		return res.extracted, append(FieldPath(nil), res.remaining...), res.err
+	}
+
+	extracted, remainingPaths, err = resolveFieldType(paths, typ)
+	compileCache.fieldPaths.Store(key, &fieldPathResult{
+		extracted: extracted,
+		remaining: append(FieldPath(nil), remainingPaths...),
+		err:       err,
+	})
+	return extracted, remainingPaths, err
+}
+
+func resolveFieldType(paths []string, typ reflect.Type) (extracted reflect.Type, remainingPaths FieldPath, err error) {
 	extracted = typ
 	for i, field := range paths {
 		for extracted.Kind() == reflect.Ptr {
@@ -103,6 +103,21 @@ func (g *Graph[I, O]) AddEdge(startNode, endNode string) (err error) {
 	return g.graph.addEdgeWithMappings(startNode, endNode, false, false)
 }
 
+// AddEdgeWithTransform adds an edge to the graph like AddEdge, converting the output of startNode by transform
+// before it's passed to endNode, so the types of both nodes only need to match the transform rather than each other.
+// e.g.
+//
+//	toContent := compose.NewEdgeTransform(func(m *schema.Message) (string, error) {
+//		return m.Content, nil
+//	})
+//	err := graph.AddEdgeWithTransform("chat_model", "post_process", toContent)
+func (g *Graph[I, O]) AddEdgeWithTransform(startNode, endNode string, transform *EdgeTransform) (err error) {
+	if transform == nil {
+		return g.graph.addEdgeWithMappings(startNode, endNode, false, false)
+	}
+	return g.graph.addEdge(startNode, endNode, false, false, transform)
+}
+
 // Compile take the raw graph and compile it into a form ready to be run.
 // e.g.
 //
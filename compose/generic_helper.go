@@ -26,6 +26,16 @@ import (
 )
 
 func newGenericHelper[I, O any]() *genericHelper {
+	key := genericHelperKey{input: generic.TypeOf[I](), output: generic.TypeOf[O]()}
+	if gh, ok := compileCache.helpers.Load(key); ok {
+		return gh.(*genericHelper)
+	}
+
+	gh, _ := compileCache.helpers.LoadOrStore(key, buildGenericHelper[I, O]())
+	return gh.(*genericHelper)
+}
+
+func buildGenericHelper[I, O any]() *genericHelper {
 	return &genericHelper{
 		inputStreamFilter:  defaultStreamMapFilter[I],
 		outputStreamFilter: defaultStreamMapFilter[O],
@@ -23,12 +23,14 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/cloudwego/eino/components/audio"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/internal/gmap"
 )
@@ -63,8 +65,9 @@ type graph struct {
 	endNodes     []string
 
 	toValidateMap map[string][]struct {
-		endNode  string
-		mappings []*FieldMapping
+		endNode   string
+		mappings  []*FieldMapping
+		transform *EdgeTransform
 	}
 
 	stateType      reflect.Type
@@ -76,6 +79,8 @@ type graph struct {
 	*genericHelper
 
 	fieldMappingRecords map[string][]*FieldMapping
+	// convertedEdges records the data edges with field mappings or an edge transform, start node -> end nodes
+	convertedEdges map[string]map[string]bool
 
 	buildError error
 
@@ -83,6 +88,10 @@ type graph struct {
 
 	compiled bool
 
+	// runAsDAG runs the chain in DAG mode, i.e. nodes are triggered when all predecessors are done,
+	// set by AppendNamedOutput so that END waits for both the named outputs and the final output.
+	runAsDAG bool
+
 	handlerOnEdges   map[string]map[string][]handlerPair
 	handlerPreNode   map[string][]handlerPair
 	handlerPreBranch map[string][][]handlerPair
@@ -122,8 +131,9 @@ func newGraph(cfg *newGraphConfig) *graph {
 		branches:     make(map[string][]*GraphBranch),
 
 		toValidateMap: make(map[string][]struct {
-			endNode  string
-			mappings []*FieldMapping
+			endNode   string
+			mappings  []*FieldMapping
+			transform *EdgeTransform
 		}),
 
 		expectedInputType:  cfg.inputType,
@@ -131,6 +141,7 @@ func newGraph(cfg *newGraphConfig) *graph {
 		genericHelper:      cfg.gh,
 
 		fieldMappingRecords: make(map[string][]*FieldMapping),
+		convertedEdges:      make(map[string]map[string]bool),
 
 		cmp: cfg.cmp,
 
@@ -194,6 +205,9 @@ func (g *graph) addNode(key string, node *graphNode, options *graphAddNodeOpts)
 			return errors.New("only chain support node key option")
 		}
 	}
+	if err = validateNodeKeys(key, node, options.nodeOptions); err != nil {
+		return err
+	}
 	// end: check options
 
 	// check pre- / post-handler type
@@ -230,6 +244,10 @@ func (g *graph) addNode(key string, node *graphNode, options *graphAddNodeOpts)
 }
 
 func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, noData bool, mappings ...*FieldMapping) (err error) {
+	return g.addEdge(startNode, endNode, noControl, noData, nil, mappings...)
+}
+
+func (g *graph) addEdge(startNode, endNode string, noControl bool, noData bool, transform *EdgeTransform, mappings ...*FieldMapping) (err error) {
 	if g.buildError != nil {
 		return g.buildError
 	}
@@ -254,10 +272,10 @@ func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, n
 	}
 
 	if _, ok := g.nodes[startNode]; !ok && startNode != START {
-		return fmt.Errorf("edge start node '%s' needs to be added to graph first", startNode)
+		return &einoerr.NodeNotFoundError{NodeKey: startNode, Context: "edge start node"}
 	}
 	if _, ok := g.nodes[endNode]; !ok && endNode != END {
-		return fmt.Errorf("edge end node '%s' needs to be added to graph first", endNode)
+		return &einoerr.NodeNotFoundError{NodeKey: endNode, Context: "edge end node"}
 	}
 
 	if !noControl {
@@ -282,12 +300,18 @@ func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, n
 			}
 		}
 
-		g.addToValidateMap(startNode, endNode, mappings)
+		g.addToValidateMap(startNode, endNode, mappings, transform)
 		err = g.updateToValidateMap()
 		if err != nil {
 			return err
 		}
 		g.dataEdges[startNode] = append(g.dataEdges[startNode], endNode)
+		if len(mappings) > 0 || transform != nil {
+			if g.convertedEdges[startNode] == nil {
+				g.convertedEdges[startNode] = make(map[string]bool)
+			}
+			g.convertedEdges[startNode][endNode] = true
+		}
 	}
 
 	return nil
@@ -388,6 +412,30 @@ func (g *graph) AddDocumentTransformerNode(key string, node document.Transformer
 	return g.addNode(key, gNode, options)
 }
 
+// AddSpeechToTextNode adds a node that implements audio.SpeechToText.
+// The node transforms the stream of *schema.AudioChunk into the stream of the transcript in string.
+// e.g.
+//
+//	stt, err := whisper.NewSpeechToText(ctx, &whisper.Config{})
+//
+//	graph.AddSpeechToTextNode("stt_node_key", stt)
+func (g *graph) AddSpeechToTextNode(key string, node audio.SpeechToText, opts ...GraphAddNodeOpt) error {
+	gNode, options := toSpeechToTextNode(node, opts...)
+	return g.addNode(key, gNode, options)
+}
+
+// AddTextToSpeechNode adds a node that implements audio.TextToSpeech.
+// The node transforms the stream of text in string into the stream of *schema.AudioChunk.
+// e.g.
+//
+//	tts, err := openai.NewTextToSpeech(ctx, &openai.TextToSpeechConfig{Voice: "alloy"})
+//
+//	graph.AddTextToSpeechNode("tts_node_key", tts)
+func (g *graph) AddTextToSpeechNode(key string, node audio.TextToSpeech, opts ...GraphAddNodeOpt) error {
+	gNode, options := toTextToSpeechNode(node, opts...)
+	return g.addNode(key, gNode, options)
+}
+
 // AddLambdaNode add node that implements at least one of Invoke[I, O], Stream[I, O], Collect[I, O], Transform[I, O].
 // due to the lack of supporting method generics, we need to use function generics to generate Lambda run as Runnable[I, O].
 // for Invoke[I, O], use compose.InvokableLambda()
@@ -418,6 +466,20 @@ func (g *graph) AddPassthroughNode(key string, opts ...GraphAddNodeOpt) error {
 	return g.addNode(key, gNode, options)
 }
 
+// AddJoinNode adds a passthrough node merging the inputs from its predecessors by the given strategy,
+// making the fan-in merge of the graph explicit.
+// e.g.
+//
+//	graph.AddJoinNode("join", compose.NewMessagesMergeStrategy())
+//	graph.AddEdge("search_a", "join")
+//	graph.AddEdge("search_b", "join")
+func (g *graph) AddJoinNode(key string, strategy *MergeStrategy, opts ...GraphAddNodeOpt) error {
+	if strategy == nil {
+		return errors.New("merge strategy of join node is nil")
+	}
+	return g.AddPassthroughNode(key, append(opts, WithMergeStrategy(strategy))...)
+}
+
 // AddBranch adds a branch to the graph.
 // e.g.
 //
@@ -452,7 +514,7 @@ func (g *graph) addBranch(startNode string, branch *GraphBranch, skipData bool)
 	}
 
 	if _, ok := g.nodes[startNode]; !ok && startNode != START {
-		return fmt.Errorf("branch start node '%s' needs to be added to graph first", startNode)
+		return &einoerr.NodeNotFoundError{NodeKey: startNode, Context: "branch start node"}
 	}
 
 	if _, ok := g.handlerPreBranch[startNode]; !ok {
@@ -469,7 +531,8 @@ func (g *graph) addBranch(startNode string, branch *GraphBranch, skipData bool)
 	// check branch condition type
 	result := checkAssignable(g.getNodeOutputType(startNode), branch.inputType)
 	if result == assignableTypeMustNot {
-		return fmt.Errorf("condition's input type[%s] and start node[%s]'s output type[%s] are mismatched", branch.inputType.String(), startNode, g.getNodeOutputType(startNode).String())
+		return newTypeMismatchErr(branch.inputType, g.getNodeOutputType(startNode),
+			"condition's input type[%s] and start node[%s]'s output type[%s] are mismatched", branch.inputType.String(), startNode, g.getNodeOutputType(startNode).String())
 	} else if result == assignableTypeMay {
 		g.handlerPreBranch[startNode] = append(g.handlerPreBranch[startNode], []handlerPair{branch.inputConverter})
 	} else {
@@ -480,11 +543,11 @@ func (g *graph) addBranch(startNode string, branch *GraphBranch, skipData bool)
 		for endNode := range branch.endNodes {
 			if _, ok := g.nodes[endNode]; !ok {
 				if endNode != END {
-					return fmt.Errorf("branch end node '%s' needs to be added to graph first", endNode)
+					return &einoerr.NodeNotFoundError{NodeKey: endNode, Context: "branch end node"}
 				}
 			}
 
-			g.addToValidateMap(startNode, endNode, nil)
+			g.addToValidateMap(startNode, endNode, nil, nil)
 			e := g.updateToValidateMap()
 			if e != nil {
 				return e
@@ -514,11 +577,12 @@ func (g *graph) addBranch(startNode string, branch *GraphBranch, skipData bool)
 	return nil
 }
 
-func (g *graph) addToValidateMap(startNode, endNode string, mapping []*FieldMapping) {
+func (g *graph) addToValidateMap(startNode, endNode string, mapping []*FieldMapping, transform *EdgeTransform) {
 	g.toValidateMap[startNode] = append(g.toValidateMap[startNode], struct {
-		endNode  string
-		mappings []*FieldMapping
-	}{endNode: endNode, mappings: mapping})
+		endNode   string
+		mappings  []*FieldMapping
+		transform *EdgeTransform
+	}{endNode: endNode, mappings: mapping, transform: transform})
 }
 
 // updateToValidateMap after update node, check validate map
@@ -533,6 +597,18 @@ func (g *graph) updateToValidateMap() error {
 			for i := 0; i < len(g.toValidateMap[startNode]); i++ {
 				endNode := g.toValidateMap[startNode][i]
 
+				if endNode.transform != nil {
+					// the types on both sides of an edge transform are checked independently
+					g.toValidateMap[startNode] = append(g.toValidateMap[startNode][:i], g.toValidateMap[startNode][i+1:]...)
+					i--
+
+					hasChanged = true
+					if err := g.validateEdgeTransform(startNode, endNode.endNode, endNode.transform); err != nil {
+						return err
+					}
+					continue
+				}
+
 				endNodeInputType = g.getNodeInputType(endNode.endNode)
 				if startNodeOutputType == nil && endNodeInputType == nil {
 					continue
@@ -556,8 +632,8 @@ func (g *graph) updateToValidateMap() error {
 					// common node check
 					result := checkAssignable(startNodeOutputType, endNodeInputType)
 					if result == assignableTypeMustNot {
-						return fmt.Errorf("graph edge[%s]-[%s]: start node's output type[%s] and end node's input type[%s] mismatch",
-							startNode, endNode.endNode, startNodeOutputType.String(), endNodeInputType.String())
+						return newTypeMismatchErr(endNodeInputType, startNodeOutputType,
+							"graph edge[%s]-[%s]: start node's output type[%s] and end node's input type[%s] mismatch", startNode, endNode.endNode, startNodeOutputType.String(), endNodeInputType.String())
 					} else if result == assignableTypeMay {
 						// add runtime check edges
 						if _, ok := g.handlerOnEdges[startNode]; !ok {
@@ -649,11 +725,22 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 			return nil, errors.New(fmt.Sprintf("%s doesn't support node trigger mode option", g.cmp))
 		}
 	}
-	if (opt != nil && opt.nodeTriggerMode == AllPredecessor) || isWorkflow(g.cmp) {
+	if (opt != nil && opt.nodeTriggerMode == AllPredecessor) || isWorkflow(g.cmp) || g.runAsDAG {
 		runType = runTypeDAG
 		cb = dagChannelBuilder
 	}
 
+	for startNode, branches := range g.branches {
+		for _, branch := range branches {
+			if !branch.fallback {
+				continue
+			}
+			if err := g.validateFallbackBranch(startNode, branch, runType); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// get eager type
 	eager := false
 	if isWorkflow(g.cmp) || runType == runTypeDAG {
@@ -677,6 +764,16 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		}
 	}
 
+	var edgeTypes []EdgeTypeReport
+	if opt != nil && (opt.report != nil || opt.strictEdgeTypes != StrictEdgeTypesOff) {
+		edgeTypes = g.edgeTypeReports()
+		if opt.strictEdgeTypes == StrictEdgeTypesError {
+			if err := checkLooseEdgeTypes(edgeTypes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	for key := range g.fieldMappingRecords {
 		// not allowed to map multiple fields to the same field
 		toMap := make(map[string]bool)
@@ -696,7 +793,11 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 	for name, node := range g.nodes {
 		node.beforeChildGraphCompile(name, key2SubGraphs)
 
-		r, err := node.compileIfNeeded(ctx)
+		var concatFuncs map[reflect.Type]func(streamReader) (streamReader, error)
+		if opt != nil {
+			concatFuncs = opt.concatFuncs
+		}
+		r, err := node.compileIfNeeded(ctx, concatFuncs)
 		if err != nil {
 			return nil, err
 		}
@@ -761,6 +862,10 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		}
 	}
 
+	if err := g.validateInputKeys(dataPredecessors); err != nil {
+		return nil, err
+	}
+
 	inputChannels := &chanCall{
 		writeTo:         g.dataEdges[START],
 		controls:        g.controlEdges[START],
@@ -768,12 +873,34 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 	}
 	copy(inputChannels.writeToBranches, g.branches[START])
 
-	var mergeConfigs map[string]FanInMergeConfig
+	mergeConfigs := make(map[string]FanInMergeConfig)
 	if opt != nil {
-		mergeConfigs = opt.mergeConfigs
+		for k, v := range opt.mergeConfigs {
+			mergeConfigs[k] = v
+		}
 	}
-	if mergeConfigs == nil {
-		mergeConfigs = make(map[string]FanInMergeConfig)
+	for key, node := range g.nodes {
+		s := node.nodeInfo.mergeStrategy
+		if s == nil {
+			continue
+		}
+		if node.nodeInfo.inputKey == "" && len(node.nodeInfo.inputKeys) == 0 && checkAssignable(s.typ, node.inputType()) == assignableTypeMustNot {
+			return nil, fmt.Errorf("merge strategy type[%s] of node[%s] mismatches its input type[%s]",
+				s.typ, key, node.inputType())
+		}
+		cfg := mergeConfigs[key]
+		cfg.strategy = s
+		mergeConfigs[key] = cfg
+	}
+	if opt != nil && len(opt.mergeFuncs) > 0 {
+		for key := range g.nodes {
+			cfg := mergeConfigs[key]
+			cfg.mergeFuncs = opt.mergeFuncs
+			mergeConfigs[key] = cfg
+		}
+		cfg := mergeConfigs[END]
+		cfg.mergeFuncs = opt.mergeFuncs
+		mergeConfigs[END] = cfg
 	}
 
 	r := &runner{
@@ -798,6 +925,12 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		mergeConfigs: mergeConfigs,
 	}
 
+	configurable, err := g.collectConfigurable()
+	if err != nil {
+		return nil, err
+	}
+	r.configurable = configurable
+
 	successors := make(map[string][]string)
 	for ch := range r.chanSubscribeTo {
 		successors[ch] = getSuccessors(r.chanSubscribeTo[ch])
@@ -818,6 +951,11 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		}
 	}
 
+	if opt != nil && opt.report != nil {
+		*opt.report = *newCompileReport(opt.graphName, r.chanSubscribeTo, runType == runTypeDAG, opt.maxRunSteps)
+		opt.report.addEdgeTypes(edgeTypes, opt.strictEdgeTypes == StrictEdgeTypesWarn)
+	}
+
 	if runType == runTypeDAG {
 		err := validateDAG(r.chanSubscribeTo, controlPredecessors)
 		if err != nil {
@@ -837,16 +975,33 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		outputPairs[START] = r.inputConvertStreamPair
 		r.checkPointer = newCheckPointer(inputPairs, outputPairs, opt.checkPointStore, opt.serializer)
 
+		for key, budget := range opt.nodeVisitBudgets {
+			if _, ok := r.chanSubscribeTo[key]; !ok {
+				return nil, &einoerr.NodeNotFoundError{NodeKey: key, Context: "node of visit budget"}
+			}
+			if budget < 1 {
+				return nil, fmt.Errorf("node visit budget of node[%s] must be at least 1, got %d", key, budget)
+			}
+		}
+
+		if err := g.validatePrefetchNodes(opt.prefetchNodes, opt.interruptBeforeNodes, dataPredecessors, r.dag); err != nil {
+			return nil, err
+		}
+
 		r.interruptBeforeNodes = opt.interruptBeforeNodes
 		r.interruptAfterNodes = opt.interruptAfterNodes
 		r.options = *opt
+
+		if opt.concurrencyAudit != nil {
+			r.audit = newConcurrencyAudit(g.nodes, opt.concurrencyAudit)
+		}
 	}
 
 	// default options
 	if r.dag && r.options.maxRunSteps > 0 {
 		return nil, fmt.Errorf("cannot set max run steps in dag mode")
 	} else if !r.dag && r.options.maxRunSteps == 0 {
-		r.options.maxRunSteps = len(r.chanSubscribeTo) + 10
+		r.options.maxRunSteps = defaultMaxRunSteps(len(r.chanSubscribeTo))
 	}
 
 	g.compiled = true
@@ -912,6 +1067,8 @@ func (g *graph) toGraphInfo(opt *graphCompileOptions, key2SubGraphs map[string]*
 					inputType:     b.inputType,
 					genericHelper: b.genericHelper,
 					endNodes:      gmap.Clone(b.endNodes),
+					noDataFlow:    b.noDataFlow,
+					paradigms:     b.paradigms,
 				})
 			}
 			return startNode, branchInfo
@@ -932,8 +1089,10 @@ func (g *graph) toGraphInfo(opt *graphCompileOptions, key2SubGraphs map[string]*
 				InputType:        gNode.cr.inputType,
 				OutputType:       gNode.cr.outputType,
 				Name:             gNode.nodeInfo.name,
+				Group:            gNode.nodeInfo.group,
 				InputKey:         gNode.cr.nodeInfo.inputKey,
 				OutputKey:        gNode.cr.nodeInfo.outputKey,
+				paradigms:        gNode.cr.paradigms,
 			}
 			continue
 		}
@@ -945,9 +1104,11 @@ func (g *graph) toGraphInfo(opt *graphCompileOptions, key2SubGraphs map[string]*
 			InputType:        gNode.cr.inputType,
 			OutputType:       gNode.cr.outputType,
 			Name:             gNode.nodeInfo.name,
+			Group:            gNode.nodeInfo.group,
 			InputKey:         gNode.cr.nodeInfo.inputKey,
 			OutputKey:        gNode.cr.nodeInfo.outputKey,
 			Mappings:         g.fieldMappingRecords[key],
+			paradigms:        gNode.cr.paradigms,
 		}
 
 		if gi, ok := key2SubGraphs[key]; ok {
@@ -1159,10 +1320,25 @@ func NewNodePath(nodeKeyPath ...string) *NodePath {
 	return &NodePath{path: nodeKeyPath}
 }
 
+// NewNodeGroupPath specifies the nodes of a group, set by WithNodeGroup, in the graph at graphPath,
+// which is composed of the node keys leading to a subgraph, empty for the top graph.
+//
+// e.g.
+// NewNodeGroupPath("retrieval", "sub_graph_node_key")
+func NewNodeGroupPath(group string, graphPath ...string) *NodePath {
+	return &NodePath{path: graphPath, group: group}
+}
+
 type NodePath struct {
-	path []string
+	path  []string
+	group string
 }
 
 func (p *NodePath) GetPath() []string {
 	return p.path
 }
+
+// GetGroup returns the node group designated by the path, empty if the path leads to a single node.
+func (p *NodePath) GetGroup() string {
+	return p.group
+}
@@ -36,14 +36,28 @@ type graphAddNodeOpts struct {
 type GraphAddNodeOpt func(o *graphAddNodeOpts)
 
 type nodeOptions struct {
-	nodeName string
+	nodeName  string
+	nodeGroup string
 
 	nodeKey string
 
 	inputKey  string
 	outputKey string
 
+	inputKeys        []keyRenaming
+	outputKeyRenames []keyRenaming
+
 	graphCompileOption []GraphCompileOption // when this node is itself an AnyGraph, this option will be used to compile the node as a nested graph
+
+	outputSampling *outputSampling
+
+	mergeStrategy *MergeStrategy
+
+	streamConcat *streamConcatOptions
+
+	rateLimiter RateLimiter
+
+	configurable []*ConfigurableField
 }
 
 // WithNodeName sets the name of the node.
@@ -53,6 +67,15 @@ func WithNodeName(n string) GraphAddNodeOpt {
 	}
 }
 
+// WithNodeGroup sets the logical group of the node, e.g. "retrieval", to organize large graphs.
+// The group is surfaced in callbacks.RunInfo and GraphNodeInfo,
+// and options can be designated to all the nodes of a group by Option.DesignateNodeGroup.
+func WithNodeGroup(group string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.nodeGroup = group
+	}
+}
+
 // WithNodeKey set the node key, which is used to identify the node in the chain.
 // only for use in Chain/StateChain.
 func WithNodeKey(key string) GraphAddNodeOpt {
@@ -89,6 +112,41 @@ func WithGraphCompileOptions(opts ...GraphCompileOption) GraphAddNodeOpt {
 	}
 }
 
+// WithOutputSampling asynchronously copies a sampled fraction of the node's outputs to the sink,
+// e.g. to collect data for prompt improvement without logging the full payload of every request.
+// rate is the probability of an output being sampled, in the range of [0, 1].
+// e.g.
+//
+//	graph.AddChatModelNode("chat_model", chatModel, compose.WithOutputSampling(0.01, mySink))
+func WithOutputSampling(rate float64, sink OutputSampleSink) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.outputSampling = &outputSampling{rate: rate, sink: sink}
+	}
+}
+
+// WithMergeStrategy sets how the inputs from multiple predecessors of the node are merged when fan-in.
+// The type of the strategy must be the input type of the node.
+// e.g.
+//
+//	graph.AddChatModelNode("chat_model", chatModel, compose.WithMergeStrategy(compose.NewMessagesMergeStrategy()))
+func WithMergeStrategy(s *MergeStrategy) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.mergeStrategy = s
+	}
+}
+
+// WithRateLimiter waits for the limiter before each execution of the node, e.g. NewTokenBucketLimiter,
+// so that the nodes sharing the same limiter, even across graphs, are bound by the same quota.
+// For a ToolsNode, the limiter is waited for before each tool call instead of each execution of the node.
+// e.g.
+//
+//	graph.AddChatModelNode("chat_model", chatModel, compose.WithRateLimiter(limiter))
+func WithRateLimiter(limiter RateLimiter) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.rateLimiter = limiter
+	}
+}
+
 // WithStatePreHandler modify node's input of I according to state S and input or store input information into state, and it's thread-safe.
 // notice: this option requires Graph to be created with WithGenLocalState option.
 // I: input type of the Node like ChatModel, Lambda, Retriever etc.
@@ -166,3 +224,36 @@ func getGraphAddNodeOpts(opts ...GraphAddNodeOpt) *graphAddNodeOpts {
 
 	return opt
 }
+
+// WithMaxStreamChunks limits the number of the chunks buffered when the input stream of the node is concatenated automatically,
+// i.e. running in stream mode while the node implements neither Transform nor Collect,
+// the node fails fast with ErrStreamChunksExceeded once the limit is exceeded.
+// e.g.
+//
+//	graph.AddLambdaNode("summarize", compose.InvokableLambda(summarize), compose.WithMaxStreamChunks(1000))
+func WithMaxStreamChunks(n int) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		if o.nodeOptions.streamConcat == nil {
+			o.nodeOptions.streamConcat = &streamConcatOptions{}
+		}
+		o.nodeOptions.streamConcat.maxChunks = n
+	}
+}
+
+// WithStreamConcatFunc concatenates the input stream of the node with fn when it's concatenated automatically in stream mode,
+// instead of the function registered by RegisterStreamConcatFunc, WithGraphStreamConcatFunc or the default one, e.g. for a type without a registered one,
+// or to concatenate differently only for this node. T must be the input type of the node, which is checked when compiling.
+// e.g.
+//
+//	graph.AddLambdaNode("latest", compose.InvokableLambda(handle), compose.WithStreamConcatFunc(func(items []*Event) (*Event, error) {
+//		return items[len(items)-1], nil
+//	}))
+func WithStreamConcatFunc[T any](fn func([]T) (T, error)) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		if o.nodeOptions.streamConcat == nil {
+			o.nodeOptions.streamConcat = &streamConcatOptions{}
+		}
+		o.nodeOptions.streamConcat.concatType = generic.TypeOf[T]()
+		o.nodeOptions.streamConcat.concat = newStreamConcatFunc(fn)
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/audio"
+	"github.com/cloudwego/eino/components/credential"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
@@ -88,6 +90,18 @@ type Option struct {
 	writeToCheckPointID *string
 	forceNewRun         bool
 	stateModifier       StateModifier
+
+	stateSnapshotPerStep bool
+	streamChunkMeta      bool
+
+	runID       string
+	runMetadata map[string]string
+
+	costBudget *costBudgetOptions
+
+	configurable map[string]any
+
+	credentialProvider credential.Provider
 }
 
 func (o Option) deepCopy() Option {
@@ -101,10 +115,11 @@ func (o Option) deepCopy() Option {
 		nPaths[i] = &nPath
 	}
 	return Option{
-		options:     nOptions,
-		handler:     nHandler,
-		paths:       nPaths,
-		maxRunSteps: o.maxRunSteps,
+		options:      nOptions,
+		handler:      nHandler,
+		paths:        nPaths,
+		maxRunSteps:  o.maxRunSteps,
+		configurable: o.configurable,
 	}
 }
 
@@ -122,6 +137,20 @@ func (o Option) DesignateNode(nodeKey ...string) Option {
 	return o.DesignateNodeWithPath(nKeys...)
 }
 
+// DesignateNodeGroup sets the groups of the nodes to which the option will be applied, see WithNodeGroup.
+// notice: only effective at the top graph, use NewNodeGroupPath for the groups in subgraphs.
+// e.g.
+//
+// retrieverOption := compose.WithRetrieverOption(retriever.WithTopK(3))
+// runnable.Invoke(ctx, "input", retrieverOption.DesignateNodeGroup("retrieval"))
+func (o Option) DesignateNodeGroup(group ...string) Option {
+	paths := make([]*NodePath, len(group))
+	for i, g := range group {
+		paths[i] = NewNodeGroupPath(g)
+	}
+	return o.DesignateNodeWithPath(paths...)
+}
+
 // DesignateNodeWithPath sets the path of the node(s) to which the option will be applied.
 // You can specify a node in the subgraph through `NodePath` to make the option only take effect at this node.
 //
@@ -133,6 +162,38 @@ func (o Option) DesignateNodeWithPath(path ...*NodePath) Option {
 	return o
 }
 
+// DesignateSubGraph scopes options to the subgraph at path, so that options built for a graph can be reused
+// when the graph is nested into another graph.
+// Options without designated nodes apply to the whole subgraph, and designated paths become relative to the subgraph.
+// e.g.
+//
+//	// options built against the agent graph itself
+//	agentOpts := []compose.Option{
+//		compose.WithChatModelOption(model.WithTemperature(0)).DesignateNode("chat_model"),
+//		compose.WithRuntimeMaxSteps(5),
+//	}
+//	// the agent graph is added to the parent graph as node "agent"
+//	parent.Invoke(ctx, input, compose.DesignateSubGraph(compose.NewNodePath("agent"), agentOpts...)...)
+func DesignateSubGraph(path *NodePath, opts ...Option) []Option {
+	ret := make([]Option, 0, len(opts))
+	for _, opt := range opts {
+		if len(opt.paths) == 0 {
+			ret = append(ret, opt.DesignateNodeWithPath(NewNodePath(path.path...)))
+			continue
+		}
+		nPaths := make([]*NodePath, 0, len(opt.paths))
+		for _, p := range opt.paths {
+			nPath := make([]string, 0, len(path.path)+len(p.path))
+			nPath = append(nPath, path.path...)
+			nPath = append(nPath, p.path...)
+			nPaths = append(nPaths, &NodePath{path: nPath, group: p.group})
+		}
+		opt.paths = nPaths
+		ret = append(ret, opt)
+	}
+	return ret
+}
+
 // WithEmbeddingOption is a functional option type for embedding component.
 // e.g.
 //
@@ -188,6 +249,15 @@ func WithChatTemplateOption(opts ...prompt.Option) Option {
 	return withComponentOption(opts...)
 }
 
+// WithAudioOption is a functional option type for the speech components, i.e. SpeechToText and TextToSpeech.
+// e.g.
+//
+//	audioOption := compose.WithAudioOption(audio.WithVoice("alloy"))
+//	runnable.Transform(ctx, audioStream, audioOption)
+func WithAudioOption(opts ...audio.Option) Option {
+	return withComponentOption(opts...)
+}
+
 // WithToolsNodeOption is a functional option type for tools node component.
 func WithToolsNodeOption(opts ...ToolsNodeOption) Option {
 	return withComponentOption(opts...)
@@ -211,10 +281,57 @@ func WithCallbacks(cbs ...callbacks.Handler) Option {
 	}
 }
 
+// WithStreamChunkMeta attaches the schema.ChunkMeta to the chunks of the output streams of the nodes,
+// with the node key as the origin, which can be retrieved by schema.StreamReader.ChunkMeta downstream,
+// e.g. from the output stream of the graph. It only takes effect for the graph run directly.
+func WithStreamChunkMeta() Option {
+	return Option{
+		streamChunkMeta: true,
+	}
+}
+
+// WithRunID sets the ID of the run, which is carried by callbacks.RunInfo.RunID
+// for the graph and every node in the run, including the nodes of the nested graphs,
+// so that the traces can be correlated. It applies to the whole run, DesignateNode doesn't take effect for it.
+// A nested graph run inherits the ID of its parent unless it's run with its own.
+func WithRunID(id string) Option {
+	return Option{
+		runID: id,
+	}
+}
+
+// WithRunMetadata sets the metadata of the run, e.g. the user ID, session ID or experiment name,
+// which is carried by callbacks.RunInfo.Metadata for the graph and every node in the run, including the nodes of the nested graphs.
+// It applies to the whole run, DesignateNode doesn't take effect for it.
+// Multiple calls are merged, the latter one wins for the same key, and they're merged with the metadata of the parent run as well.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithRunMetadata(map[string]string{"user_id": uid, "session_id": sid}))
+func WithRunMetadata(metadata map[string]string) Option {
+	return Option{
+		runMetadata: metadata,
+	}
+}
+
+// WithCredentialProvider sets the provider resolving the credentials of the components in the run, e.g. the API keys of the tenant,
+// which is carried by the context of the graph and every node in the run, including the nodes of the nested graphs,
+// for the components to resolve by credential.Resolve, model.ResolveCredential, etc.
+// It applies to the whole run, DesignateNode doesn't take effect for it. If set more than once, the last one wins.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithCredentialProvider(vault.ForTenant(tenantID)))
+func WithCredentialProvider(p credential.Provider) Option {
+	return Option{
+		credentialProvider: p,
+	}
+}
+
 // WithRuntimeMaxSteps sets the maximum number of steps for the graph runtime.
+// Designate it to a subgraph node to limit the steps of that subgraph only.
 // e.g.
 //
 //	runnable.Invoke(ctx, "input", compose.WithRuntimeMaxSteps(20))
+//	runnable.Invoke(ctx, "input", compose.WithRuntimeMaxSteps(5).DesignateNode("sub_graph_node_key"))
 func WithRuntimeMaxSteps(maxSteps int) Option {
 	return Option{
 		maxRunSteps: maxSteps,
@@ -18,12 +18,17 @@ package compose
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/credential"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/model"
@@ -263,6 +268,27 @@ func TestCallOptionsOneByOne(t *testing.T) {
 		assert.Equal(t, "123", *opt.Index)
 	})
 
+	t.Run("retriever_filter_option", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inst := mockRetriever.NewMockRetriever(ctrl)
+		inst.EXPECT().Retrieve(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*schema.Document{
+				(&schema.Document{ID: "1", Content: "a"}).WithScore(0.9),
+				(&schema.Document{ID: "2", Content: "b"}).WithScore(0.1),
+				(&schema.Document{ID: "1", Content: "a"}).WithScore(0.8),
+				(&schema.Document{ID: "3", Content: "c"}).WithScore(0.7),
+			}, nil).
+			Times(1)
+		r, err := NewChain[string, []*schema.Document]().AppendRetriever(inst).Compile(ctx)
+		assert.NoError(t, err)
+		docs, err := r.Invoke(ctx, "hi",
+			WithRetrieverOption(retriever.WithScoreThreshold(0.5), retriever.WithDedupeBy(retriever.DedupeByID)))
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "1", docs[0].ID)
+		assert.Equal(t, "3", docs[1].ID)
+	})
+
 	t.Run("loader_option", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		inst := mockDocument.NewMockLoader(ctrl)
@@ -494,3 +520,220 @@ func TestCallOptionInSubGraph(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, result, "input grandparent-1 parent-1 child1-1 child2-1")
 }
+
+func TestSubGraphScopedOptions(t *testing.T) {
+	ctx := context.Background()
+
+	type innerOption string
+
+	var got []innerOption
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("a", InvokableLambdaWithOption(func(ctx context.Context, input string, opts ...innerOption) (string, error) {
+		got = append(got, opts...)
+		return input + "a", nil
+	})))
+	assert.NoError(t, sub.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "b", nil
+	})))
+	assert.NoError(t, sub.AddLambdaNode("c", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "c", nil
+	})))
+	assert.NoError(t, sub.AddEdge(START, "a"))
+	assert.NoError(t, sub.AddEdge("a", "b"))
+	assert.NoError(t, sub.AddEdge("b", "c"))
+	assert.NoError(t, sub.AddEdge("c", END))
+
+	parent := NewGraph[string, string]()
+	assert.NoError(t, parent.AddGraphNode("sub", sub))
+	assert.NoError(t, parent.AddEdge(START, "sub"))
+	assert.NoError(t, parent.AddEdge("sub", END))
+	r, err := parent.Compile(ctx)
+	assert.NoError(t, err)
+
+	// max steps designated to the subgraph only limits the subgraph
+	_, err = r.Invoke(ctx, "", WithRuntimeMaxSteps(2).DesignateNode("sub"))
+	assert.ErrorIs(t, err, ErrExceedMaxSteps)
+	out, err := r.Invoke(ctx, "", WithRuntimeMaxSteps(4).DesignateNode("sub"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", out)
+
+	// options built against the subgraph are lifted to the parent
+	got = nil
+	subOpts := []Option{
+		WithLambdaOption(innerOption("x")).DesignateNode("a"),
+		WithRuntimeMaxSteps(2),
+	}
+	_, err = r.Invoke(ctx, "", DesignateSubGraph(NewNodePath("sub"), subOpts...)...)
+	assert.ErrorIs(t, err, ErrExceedMaxSteps)
+	assert.Equal(t, []innerOption{"x"}, got)
+
+	got = nil
+	_, err = r.Invoke(ctx, "", DesignateSubGraph(NewNodePath("sub"), subOpts[0])...)
+	assert.NoError(t, err)
+	assert.Equal(t, []innerOption{"x"}, got)
+}
+
+func TestRunMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("inner", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in, nil
+	})))
+	assert.NoError(t, sub.AddEdge(START, "inner"))
+	assert.NoError(t, sub.AddEdge("inner", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("outer", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in, nil
+	})))
+	assert.NoError(t, g.AddGraphNode("sub", sub))
+	assert.NoError(t, g.AddEdge(START, "outer"))
+	assert.NoError(t, g.AddEdge("outer", "sub"))
+	assert.NoError(t, g.AddEdge("sub", END))
+	r, err := g.Compile(ctx, WithGraphName("g"))
+	assert.NoError(t, err)
+
+	var mu sync.Mutex
+	var infos []*callbacks.RunInfo
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		mu.Lock()
+		infos = append(infos, info)
+		mu.Unlock()
+		return ctx
+	}).Build()
+
+	_, err = r.Invoke(ctx, "x", WithCallbacks(handler), WithRunID("run-1"),
+		WithRunMetadata(map[string]string{"user": "u1"}), WithRunMetadata(map[string]string{"session": "s1"}))
+	assert.NoError(t, err)
+
+	assert.Len(t, infos, 4)
+	for _, info := range infos {
+		assert.Equal(t, "run-1", info.RunID)
+		assert.Equal(t, map[string]string{"user": "u1", "session": "s1"}, info.Metadata)
+	}
+
+	infos = nil
+	_, err = r.Invoke(ctx, "x", WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Len(t, infos, 4)
+	for _, info := range infos {
+		assert.Empty(t, info.RunID)
+		assert.Nil(t, info.Metadata)
+	}
+}
+
+func TestCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+
+	resolve := func(name string) *Lambda {
+		return InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			c, err := credential.Resolve(ctx, &credential.Request{Component: components.ComponentOfTool, Name: name})
+			if err != nil || c == nil {
+				return in + name + ":none,", err
+			}
+			return in + name + ":" + c.APIKey + ",", nil
+		})
+	}
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("inner", resolve("inner")))
+	assert.NoError(t, sub.AddEdge(START, "inner"))
+	assert.NoError(t, sub.AddEdge("inner", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("outer", resolve("outer")))
+	assert.NoError(t, g.AddGraphNode("sub", sub))
+	assert.NoError(t, g.AddEdge(START, "outer"))
+	assert.NoError(t, g.AddEdge("outer", "sub"))
+	assert.NoError(t, g.AddEdge("sub", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "outer:none,inner:none,", out)
+
+	tenant := func(id string) credential.Provider {
+		return credential.ProviderFunc(func(ctx context.Context, req *credential.Request) (*credential.Credential, error) {
+			return &credential.Credential{APIKey: id + "-" + req.Name}, nil
+		})
+	}
+	out, err = r.Invoke(ctx, "", WithCredentialProvider(tenant("t1")))
+	assert.NoError(t, err)
+	assert.Equal(t, "outer:t1-outer,inner:t1-inner,", out)
+
+	// the provider of the run overrides the one carried by ctx
+	out, err = r.Invoke(credential.WithProvider(ctx, tenant("t0")), "", WithCredentialProvider(tenant("t2")))
+	assert.NoError(t, err)
+	assert.Equal(t, "outer:t2-outer,inner:t2-inner,", out)
+}
+
+type nodeGroupCompileCallback struct {
+	info *GraphInfo
+}
+
+func (c *nodeGroupCompileCallback) OnFinish(_ context.Context, info *GraphInfo) {
+	c.info = info
+}
+
+func TestNodeGroup(t *testing.T) {
+	ctx := context.Background()
+
+	suffix := func(ctx context.Context, in string, opts ...string) (string, error) {
+		return in + strings.Join(opts, ""), nil
+	}
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("s1", InvokableLambdaWithOption(suffix), WithNodeGroup("retrieval")))
+	assert.NoError(t, sub.AddEdge(START, "s1"))
+	assert.NoError(t, sub.AddEdge("s1", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("n1", InvokableLambdaWithOption(suffix), WithNodeGroup("retrieval"), WithNodeName("n1")))
+	assert.NoError(t, g.AddLambdaNode("n2", InvokableLambdaWithOption(suffix), WithNodeGroup("retrieval"), WithNodeName("n2")))
+	assert.NoError(t, g.AddLambdaNode("n3", InvokableLambdaWithOption(suffix)))
+	assert.NoError(t, g.AddGraphNode("sub", sub, WithNodeGroup("nested")))
+	assert.NoError(t, g.AddEdge(START, "n1"))
+	assert.NoError(t, g.AddEdge("n1", "n2"))
+	assert.NoError(t, g.AddEdge("n2", "n3"))
+	assert.NoError(t, g.AddEdge("n3", "sub"))
+	assert.NoError(t, g.AddEdge("sub", END))
+
+	cc := &nodeGroupCompileCallback{}
+	r, err := g.Compile(ctx, WithGraphCompileCallbacks(cc))
+	assert.NoError(t, err)
+	assert.Equal(t, "retrieval", cc.info.Nodes["n1"].Group)
+	assert.Equal(t, "", cc.info.Nodes["n3"].Group)
+	assert.Equal(t, "nested", cc.info.Nodes["sub"].Group)
+	assert.Equal(t, "retrieval", cc.info.Nodes["sub"].GraphInfo.Nodes["s1"].Group)
+
+	var (
+		mu     sync.Mutex
+		called []string
+	)
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		mu.Lock()
+		defer mu.Unlock()
+		called = append(called, info.Group+":"+info.Name)
+		return ctx
+	}).Build()
+
+	out, err := r.Invoke(ctx, "x",
+		WithLambdaOption("r").DesignateNodeGroup("retrieval"),
+		WithLambdaOption("s").DesignateNodeWithPath(NewNodeGroupPath("retrieval", "sub")),
+		WithCallbacks(handler).DesignateNodeGroup("retrieval"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "xrrs", out)
+	sort.Strings(called)
+	assert.Equal(t, []string{"retrieval:n1", "retrieval:n2"}, called)
+
+	// the group path is kept relative to the subgraph
+	out, err = r.Invoke(ctx, "x", DesignateSubGraph(NewNodePath("sub"), WithLambdaOption("s").DesignateNodeGroup("retrieval"))...)
+	assert.NoError(t, err)
+	assert.Equal(t, "xs", out)
+
+	_, err = r.Invoke(ctx, "x", WithLambdaOption("r").DesignateNodeGroup("unknown"))
+	assert.ErrorContains(t, err, "unknown node group: unknown")
+}
@@ -16,6 +16,13 @@
 
 package compose
 
+import (
+	"context"
+	"reflect"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
 type graphCompileOptions struct {
 	maxRunSteps     int
 	graphName       string
@@ -32,7 +39,23 @@ type graphCompileOptions struct {
 
 	eagerDisabled bool
 
+	panicRecoveryDisabled bool
+
 	mergeConfigs map[string]FanInMergeConfig
+
+	mergeFuncs  map[reflect.Type]func([]any) (any, error)
+	concatFuncs map[reflect.Type]func(streamReader) (streamReader, error)
+
+	report          *CompileReport
+	strictEdgeTypes StrictEdgeTypesMode
+
+	stepHooks        []StepHook
+	nodeVisitBudgets map[string]int
+
+	executionMetrics func(ctx context.Context, m *ExecutionMetrics)
+	concurrencyAudit *concurrencyAuditOptions
+
+	prefetchNodes []string
 }
 
 func newGraphCompileOptions(opts ...GraphCompileOption) *graphCompileOptions {
@@ -59,6 +82,31 @@ func WithMaxRunSteps(maxSteps int) GraphCompileOption {
 	}
 }
 
+// StepHook is called at the boundary of each super step, right before the tasks of the step are submitted,
+// with the index of the step starting from 0 and the keys of the nodes to run in it.
+// Returning an error stops the graph run with the error.
+// In eager mode, i.e. AllPredecessor trigger mode or Workflow, a step is a batch of the nodes that become ready together.
+type StepHook func(ctx context.Context, step int, activeNodes []string) error
+
+// WithStepHooks registers hooks called at the boundary of each super step of the graph, see StepHook.
+// It doesn't apply to the subgraphs, which can set it by WithGraphCompileOptions.
+func WithStepHooks(hooks ...StepHook) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.stepHooks = append(o.stepHooks, hooks...)
+	}
+}
+
+// WithNodeVisitBudgets limits the times each node may run in a single graph run, node key -> budget,
+// which gives finer control over the loops than WithMaxRunSteps.
+// When a node is about to exceed its budget, the run fails with *NodeVisitBudgetExceededError,
+// which matches ErrNodeVisitBudgetExceeded. The visits are counted from the start of each run, including resuming.
+// Nodes not in budgets are unlimited.
+func WithNodeVisitBudgets(budgets map[string]int) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.nodeVisitBudgets = budgets
+	}
+}
+
 // WithGraphName sets a name for the graph.
 // The name is used for debugging and logging purposes.
 // If not set, a default name will be used.
@@ -91,6 +139,16 @@ func WithEagerExecutionDisabled() GraphCompileOption {
 	}
 }
 
+// WithNodePanicRecoveryDisabled disables the conversion of panics inside node execution into *NodePanicError.
+// By default, a panic raised by a node (including its state handlers) is recovered and returned as an error
+// matching ErrNodePanic, so that one misbehaving node can't crash the whole process.
+// After using this option, the panic will be propagated as is.
+func WithNodePanicRecoveryDisabled() GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.panicRecoveryDisabled = true
+	}
+}
+
 // WithNodeTriggerMode sets the trigger mode for nodes in the graph.
 // The trigger mode determines when a node is triggered during graph execution, ref: https://www.cloudwego.io/docs/eino/core_modules/chain_and_graph_orchestration/orchestration_design_principles/#runtime-engine
 // AnyPredecessor by default.
@@ -114,6 +172,9 @@ func WithGraphCompileCallbacks(cbs ...GraphCompileCallback) GraphCompileOption {
 // tracking the completion of individual input streams in a named stream merge.
 type FanInMergeConfig struct {
 	StreamMergeWithSourceEOF bool //indicates whether to emit a SourceEOF error for each stream
+
+	strategy   *MergeStrategy                            // set by WithMergeStrategy or AddJoinNode
+	mergeFuncs map[reflect.Type]func([]any) (any, error) // set by WithGraphValuesMergeFunc
 }
 
 // WithFanInMergeConfig sets the fan-in merge configurations
@@ -124,6 +185,35 @@ func WithFanInMergeConfig(confs map[string]FanInMergeConfig) GraphCompileOption
 	}
 }
 
+// WithGraphValuesMergeFunc merges the values of type T with fn when fan-in in the graph,
+// instead of the function registered by RegisterValuesMergeFunc or the default one,
+// so that the merge logic of a graph doesn't affect, or isn't affected by, the others in the same process.
+// It doesn't apply to the nodes with a MergeStrategy, nor to the subgraphs, which can set it by WithGraphCompileOptions.
+// If set more than once for the same T, the last one takes effect.
+func WithGraphValuesMergeFunc[T any](fn func([]T) (T, error)) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		if o.mergeFuncs == nil {
+			o.mergeFuncs = make(map[reflect.Type]func([]any) (any, error))
+		}
+		o.mergeFuncs[generic.TypeOf[T]()] = newValuesMergeFunc(fn)
+	}
+}
+
+// WithGraphStreamConcatFunc concatenates the input streams of type T with fn,
+// when the input stream of a node in the graph is concatenated automatically in stream mode,
+// instead of the function registered by RegisterStreamConcatFunc or the default one.
+// WithStreamConcatFunc of the node takes precedence over it, and it doesn't apply to the subgraphs,
+// which can set it by WithGraphCompileOptions.
+// If set more than once for the same T, the last one takes effect.
+func WithGraphStreamConcatFunc[T any](fn func([]T) (T, error)) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		if o.concatFuncs == nil {
+			o.concatFuncs = make(map[reflect.Type]func(streamReader) (streamReader, error))
+		}
+		o.concatFuncs[generic.TypeOf[T]()] = newStreamConcatFunc(fn)
+	}
+}
+
 // InitGraphCompileCallbacks set global graph compile callbacks,
 // which ONLY will be added to top level graph compile options
 func InitGraphCompileCallbacks(cbs []GraphCompileCallback) {
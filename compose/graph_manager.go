@@ -23,7 +23,6 @@ import (
 	"time"
 
 	"github.com/cloudwego/eino/internal"
-	"github.com/cloudwego/eino/internal/safe"
 )
 
 type channel interface {
@@ -122,6 +121,9 @@ type channelManager struct {
 
 	edgeHandlerManager    *edgeHandlerManager
 	preNodeHandlerManager *preNodeHandlerManager
+
+	// fallbacks are the remaining candidates of fallback branches, keyed by the running candidate.
+	fallbacks map[string][]string
 }
 
 func (c *channelManager) loadChannels(channels map[string]channel) error {
@@ -239,12 +241,20 @@ func (c *channelManager) reportBranch(from string, skippedNodes []string) error
 			if skipped {
 				nKeys = append(nKeys, successor)
 			}
-			// todo: detect if end node has been skipped?
 		}
 	}
 	return nil
 }
 
+// endSkipped reports whether END can never be reached because all of its predecessors have been skipped.
+// Only dag channels track skipping, so it is always false in pregel mode.
+func (c *channelManager) endSkipped() bool {
+	if dc, ok := c.channels[END].(*dagChannel); ok {
+		return dc.Skipped
+	}
+	return false
+}
+
 type task struct {
 	ctx            context.Context
 	nodeKey        string
@@ -254,6 +264,12 @@ type task struct {
 	option         []any
 	err            error
 	skipPreHandler bool
+	// fallbackInput is a copy of input kept for the next candidate of a fallback branch.
+	fallbackInput any
+	// mutations are the shared structures mutated by the task, found by WithConcurrencyAudit.
+	mutations []*SharedMutation
+	// prefetch is the run of the node started ahead by WithPrefetch, whose result is taken over by the task.
+	prefetch *prefetchTask
 }
 
 type taskManager struct {
@@ -268,21 +284,59 @@ type taskManager struct {
 	cancelCh chan *time.Duration
 	canceled bool
 	deadline *time.Time
+
+	panicRecoveryDisabled bool
+	streamChunkMeta       bool
+
+	metrics *metricsCollector
+	audit   *concurrencyAudit
 }
 
 func (t *taskManager) execute(currentTask *task) {
+	defer t.done.Send(currentTask)
+
+	if currentTask.prefetch != nil {
+		currentTask.prefetch.wait(currentTask)
+		return
+	}
+	t.run(currentTask)
+}
+
+func (t *taskManager) run(currentTask *task) {
 	defer func() {
-		panicInfo := recover()
-		if panicInfo != nil {
-			currentTask.output = nil
-			currentTask.err = safe.NewPanicErr(panicInfo, debug.Stack())
+		if !t.panicRecoveryDisabled {
+			panicInfo := recover()
+			if panicInfo != nil {
+				currentTask.output = nil
+				currentTask.err = newNodePanicError(currentTask, NodePanicPhaseRun, panicInfo, debug.Stack())
+			}
 		}
-
-		t.done.Send(currentTask)
 	}()
 
+	if ni := currentTask.call.action.nodeInfo; ni != nil && ni.rateLimiter != nil {
+		if err := ni.rateLimiter.Wait(currentTask.ctx); err != nil {
+			currentTask.err = fmt.Errorf("wait for rate limiter of node[%s] fail: %w", currentTask.nodeKey, err)
+			return
+		}
+	}
+
+	if t.metrics != nil {
+		defer t.metrics.startNode(currentTask.nodeKey)()
+	}
+
 	ctx := initNodeCallbacks(currentTask.ctx, currentTask.nodeKey, currentTask.call.action.nodeInfo, currentTask.call.action.meta, t.opts...)
-	currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+	if t.audit != nil {
+		t.audit.auditTask(currentTask, func() {
+			currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+		})
+	} else {
+		currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+	}
+	if t.streamChunkMeta && currentTask.err == nil {
+		if sr, ok := currentTask.output.(streamReader); ok {
+			currentTask.output = sr.withChunkMeta(currentTask.nodeKey)
+		}
+	}
 }
 
 func (t *taskManager) submit(tasks []*task) error {
@@ -295,7 +349,7 @@ func (t *taskManager) submit(tasks []*task) error {
 	// 2. the task manager mode is set to needAll
 	for i := 0; i < len(tasks); i++ {
 		currentTask := tasks[i]
-		err := runPreHandler(currentTask, t.runWrapper)
+		err := runPreHandler(currentTask, t.runWrapper, t.panicRecoveryDisabled)
 		if err != nil {
 			// pre-handler error, regarded as a failure of the task itself
 			currentTask.err = err
@@ -319,6 +373,9 @@ func (t *taskManager) submit(tasks []*task) error {
 	}
 	for _, currentTask := range tasks {
 		t.num += 1
+		if t.metrics != nil {
+			t.metrics.addGoroutine(currentTask.nodeKey)
+		}
 		go t.execute(currentTask)
 	}
 	if syncTask != nil {
@@ -373,12 +430,19 @@ func (t *taskManager) waitOne() (ta *task, success bool, canceled bool) {
 		return nil, false, true
 	}
 
+	if t.audit != nil && len(ta.mutations) > 0 {
+		t.audit.report(ta.ctx, ta.mutations)
+	}
+
 	delete(t.runningTasks, ta.nodeKey)
 	if ta.err != nil {
 		// biz error, jump post processor
 		return ta, true, false
 	}
-	runPostHandler(ta, t.runWrapper)
+	runPostHandler(ta, t.runWrapper, t.panicRecoveryDisabled)
+	if ta.err == nil {
+		sampleOutput(ta)
+	}
 	return ta, true, false
 }
 
@@ -491,10 +555,13 @@ func receiveWithListening(recv func() (*task, bool), cancel chan *time.Duration)
 	return p.ta, p.closed, false, canceled, nil
 }
 
-func runPreHandler(ta *task, runWrapper runnableCallWrapper) (err error) {
+func runPreHandler(ta *task, runWrapper runnableCallWrapper, panicRecoveryDisabled bool) (err error) {
 	defer func() {
+		if panicRecoveryDisabled {
+			return
+		}
 		if e := recover(); e != nil {
-			err = safe.NewPanicErr(fmt.Errorf("panic in pre handler: %v", e), debug.Stack())
+			err = newNodePanicError(ta, NodePanicPhasePreHandler, e, debug.Stack())
 		}
 	}()
 	if ta.call.preProcessor != nil && !ta.skipPreHandler {
@@ -507,10 +574,13 @@ func runPreHandler(ta *task, runWrapper runnableCallWrapper) (err error) {
 	return nil
 }
 
-func runPostHandler(ta *task, runWrapper runnableCallWrapper) {
+func runPostHandler(ta *task, runWrapper runnableCallWrapper, panicRecoveryDisabled bool) {
 	defer func() {
+		if panicRecoveryDisabled {
+			return
+		}
 		if e := recover(); e != nil {
-			ta.err = safe.NewPanicErr(fmt.Errorf("panic in post handler: %v", e), debug.Stack())
+			ta.err = newNodePanicError(ta, NodePanicPhasePostHandler, e, debug.Stack())
 		}
 	}()
 	if ta.call.postProcessor != nil {
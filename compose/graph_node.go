@@ -48,12 +48,27 @@ type nodeInfo struct {
 	// passed from WithNodeName()
 	name string
 
+	// the logical group of graph node, passed from WithNodeGroup()
+	group string
+
 	inputKey  string
 	outputKey string
 
+	inputKeys        []keyRenaming
+	outputKeyRenames []keyRenaming
+
 	preProcessor, postProcessor *composableRunnable
 
 	compileOption *graphCompileOptions // if the node is an AnyGraph, it will need compile options of its own
+
+	outputSampling *outputSampling
+	streamConcat   *streamConcatOptions
+
+	mergeStrategy *MergeStrategy
+
+	rateLimiter RateLimiter
+
+	configurable []*ConfigurableField
 }
 
 // graphNode the complete information of the node in graph
@@ -80,7 +95,7 @@ func (gn *graphNode) getGenericHelper() *genericHelper {
 	}
 
 	if gn.nodeInfo != nil {
-		if len(gn.nodeInfo.inputKey) > 0 {
+		if len(gn.nodeInfo.inputKey) > 0 || len(gn.nodeInfo.inputKeys) > 0 {
 			ret = ret.forMapInput()
 		}
 		if len(gn.nodeInfo.outputKey) > 0 {
@@ -91,7 +106,7 @@ func (gn *graphNode) getGenericHelper() *genericHelper {
 }
 
 func (gn *graphNode) inputType() reflect.Type {
-	if gn.nodeInfo != nil && len(gn.nodeInfo.inputKey) != 0 {
+	if gn.nodeInfo != nil && (len(gn.nodeInfo.inputKey) != 0 || len(gn.nodeInfo.inputKeys) != 0) {
 		return generic.TypeOf[map[string]any]()
 	}
 	// priority follow compile
@@ -118,7 +133,8 @@ func (gn *graphNode) outputType() reflect.Type {
 	return nil
 }
 
-func (gn *graphNode) compileIfNeeded(ctx context.Context) (*composableRunnable, error) {
+// compileIfNeeded compiles the node, concatFuncs are the stream concat functions set to the graph by WithGraphStreamConcatFunc.
+func (gn *graphNode) compileIfNeeded(ctx context.Context, concatFuncs map[reflect.Type]func(streamReader) (streamReader, error)) (*composableRunnable, error) {
 	var r *composableRunnable
 	if gn.g != nil {
 		cr, err := gn.g.compile(ctx, gn.nodeInfo.compileOption)
@@ -137,6 +153,30 @@ func (gn *graphNode) compileIfNeeded(ctx context.Context) (*composableRunnable,
 	r.meta = gn.executorMeta
 	r.nodeInfo = gn.nodeInfo
 
+	sc := gn.nodeInfo.streamConcat
+	if concat, ok := concatFuncs[r.inputType]; ok && (sc == nil || sc.concat == nil) {
+		gsc := &streamConcatOptions{concatType: r.inputType, concat: concat}
+		if sc != nil {
+			gsc.maxChunks = sc.maxChunks
+		}
+		sc = gsc
+	}
+	if sc != nil {
+		var err error
+		r, err = streamConcatComposableRunnable(sc, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(gn.nodeInfo.outputKeyRenames) > 0 {
+		var err error
+		r, err = renamedOutputKeysComposableRunnable(gn.nodeInfo.outputKeyRenames, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if gn.nodeInfo.outputKey != "" {
 		r = outputKeyedComposableRunnable(gn.nodeInfo.outputKey, r)
 	}
@@ -145,6 +185,14 @@ func (gn *graphNode) compileIfNeeded(ctx context.Context) (*composableRunnable,
 		r = inputKeyedComposableRunnable(gn.nodeInfo.inputKey, r)
 	}
 
+	if len(gn.nodeInfo.inputKeys) > 0 {
+		var err error
+		r, err = inputKeysComposableRunnable(gn.nodeInfo.inputKeys, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
@@ -167,11 +215,20 @@ func getNodeInfo(opts ...GraphAddNodeOpt) (*nodeInfo, *graphAddNodeOpts) {
 	opt := getGraphAddNodeOpts(opts...)
 
 	return &nodeInfo{
-		name:          opt.nodeOptions.nodeName,
-		inputKey:      opt.nodeOptions.inputKey,
-		outputKey:     opt.nodeOptions.outputKey,
-		preProcessor:  opt.processor.statePreHandler,
-		postProcessor: opt.processor.statePostHandler,
-		compileOption: newGraphCompileOptions(opt.nodeOptions.graphCompileOption...),
+		name:             opt.nodeOptions.nodeName,
+		group:            opt.nodeOptions.nodeGroup,
+		inputKey:         opt.nodeOptions.inputKey,
+		outputKey:        opt.nodeOptions.outputKey,
+		inputKeys:        opt.nodeOptions.inputKeys,
+		outputKeyRenames: opt.nodeOptions.outputKeyRenames,
+		preProcessor:     opt.processor.statePreHandler,
+		postProcessor:    opt.processor.statePostHandler,
+		compileOption:    newGraphCompileOptions(opt.nodeOptions.graphCompileOption...),
+
+		outputSampling: opt.nodeOptions.outputSampling,
+		streamConcat:   opt.nodeOptions.streamConcat,
+		mergeStrategy:  opt.nodeOptions.mergeStrategy,
+		rateLimiter:    opt.nodeOptions.rateLimiter,
+		configurable:   opt.nodeOptions.configurable,
 	}, opt
 }
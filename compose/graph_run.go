@@ -21,8 +21,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal"
 	"github.com/cloudwego/eino/internal/core"
 	"github.com/cloudwego/eino/internal/serialization"
@@ -81,6 +84,10 @@ type runner struct {
 	interruptAfterNodes  []string
 
 	mergeConfigs map[string]FanInMergeConfig
+
+	audit *concurrencyAudit
+
+	configurable map[string][]configurableTarget
 }
 
 func (r *runner) invoke(ctx context.Context, input any, opts ...Option) (any, error) {
@@ -111,7 +118,18 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 	defer func() {
 		if !haveOnStart {
 			ctx, input = onGraphStart(ctx, input, isStream)
+			onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventRunStart})
 		}
+		if sc := getStateCapture(ctx); sc != nil && r.runCtx != nil {
+			sc.captureFinal(ctx)
+		}
+		err = wrapRunCanceledError(ctx, err)
+		if rc, ok := ctx.Value(runCancelKey{}).(*runCancel); ok && err == nil && isStream {
+			if _, isSubGraph := getNodePath(ctx); !isSubGraph {
+				result = result.(streamReader).withCancelReason(ctx, rc)
+			}
+		}
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventRunEnd, Err: err})
 		if err != nil {
 			ctx, err = onGraphError(ctx, err)
 		} else {
@@ -119,27 +137,39 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		}
 	}()
 
+	ctx = withExperimentVariants(ctx)
+
 	var runWrapper runnableCallWrapper
 	runWrapper = runnableInvoke
 	if isStream {
 		runWrapper = runnableTransform
 	}
 
+	var mc *metricsCollector
+	ctx, mc = r.withExecutionMetrics(ctx)
+	if mc != nil {
+		defer func() {
+			result = mc.finish(ctx, result, err)
+		}()
+	}
+
 	// Initialize channel and task managers.
 	cm := r.initChannelManager(isStream)
 	tm := r.initTaskManager(runWrapper, getGraphCancel(ctx), opts...)
+	tm.metrics = mc
+	tm.audit = r.audit
 	maxSteps := r.options.maxRunSteps
 
 	if r.dag {
 		for i := range opts {
-			if opts[i].maxRunSteps > 0 {
+			if opts[i].maxRunSteps > 0 && len(opts[i].paths) == 0 {
 				return nil, newGraphRunError(fmt.Errorf("cannot set max run steps in dag"))
 			}
 		}
 	} else {
 		// Update maxSteps if provided in options.
 		for i := range opts {
-			if opts[i].maxRunSteps > 0 {
+			if opts[i].maxRunSteps > 0 && len(opts[i].paths) == 0 {
 				maxSteps = opts[i].maxRunSteps
 			}
 		}
@@ -148,6 +178,11 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		}
 	}
 
+	opts, err = r.bindConfigurable(opts)
+	if err != nil {
+		return nil, newGraphRunError(err)
+	}
+
 	// Extract and validate options for each node.
 	optMap, extractErr := extractOption(r.chanSubscribeTo, opts...)
 	if extractErr != nil {
@@ -171,6 +206,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		initialized = true
 		ctx, input = onGraphStart(ctx, input, isStream)
 		haveOnStart = true
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventRunStart})
 
 		// restoreFromCheckPoint will 'fix' the ctx used by the 'nextTasks',
 		// so it should run after all operations on ctx are done, such as onGraphStart.
@@ -189,6 +225,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 			ctx, input = onGraphStart(ctx, input, isStream)
 			haveOnStart = true
+			onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventRunStart, CheckPointID: *checkPointID})
 
 			// restoreFromCheckPoint will 'fix' the ctx used by the 'nextTasks',
 			// so it should run after all operations on ctx are done, such as onGraphStart.
@@ -203,6 +240,11 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 		ctx, input = onGraphStart(ctx, input, isStream)
 		haveOnStart = true
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventRunStart})
+
+		var pf prefetches
+		input, pf = r.startPrefetches(ctx, input, isStream, tm, optMap)
+		defer pf.release()
 
 		var isEnd bool
 		nextTasks, result, isEnd, err = r.calculateNextTasks(ctx, []*task{{
@@ -232,11 +274,27 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 				writeToCheckPointID,
 			)
 		}
+		pf.bind(nextTasks)
 	}
 
 	// used to reporting NoTask error
 	var lastCompletedTask []*task
 
+	var stepCapture *stateCapture
+	if sc := getStateCapture(ctx); sc != nil && r.runCtx != nil {
+		for i := range opts {
+			if opts[i].stateSnapshotPerStep {
+				stepCapture = sc
+			}
+		}
+	}
+
+	// visits counts the runs of the nodes with a visit budget
+	var visits map[string]int
+	if len(r.options.nodeVisitBudgets) > 0 {
+		visits = make(map[string]int, len(r.options.nodeVisitBudgets))
+	}
+
 	// Main execution loop.
 	for step := 0; ; step++ {
 		// Check for context cancellation.
@@ -247,7 +305,12 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		default:
 		}
 		if !r.dag && step >= maxSteps {
-			return nil, newGraphRunError(ErrExceedMaxSteps)
+			onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventMaxStepsExceeded, Step: step})
+			return nil, newGraphRunError(&einoerr.MaxStepsError{MaxSteps: maxSteps})
+		}
+
+		if err = r.beforeStep(ctx, step, nextTasks, visits); err != nil {
+			return nil, newGraphRunError(err)
 		}
 
 		// 1. submit next tasks
@@ -263,6 +326,13 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 		completedTasks, canceled, canceledTasks := tm.wait()
 		totalCanceledTasks = append(totalCanceledTasks, canceledTasks...)
+		var fallbackTasks []*task
+		if !canceled {
+			completedTasks, fallbackTasks, err = r.resolveFallbackTasks(ctx, completedTasks, isStream, cm, optMap)
+			if err != nil {
+				return nil, newGraphRunError(fmt.Errorf("failed to create fallback tasks: %w", err))
+			}
+		}
 		tempInfo := newInterruptTempInfo()
 		if canceled {
 			if len(canceledTasks) > 0 {
@@ -312,10 +382,20 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 			)
 		}
 
+		if len(completedTasks) == 0 && len(fallbackTasks) > 0 {
+			nextTasks = fallbackTasks
+			continue
+		}
 		if len(completedTasks) == 0 {
+			if cm.endSkipped() {
+				return nil, newGraphRunError(fmt.Errorf("no tasks to execute, last completed nodes: %v: %w", printTask(lastCompletedTask), ErrEndNodeSkipped))
+			}
 			return nil, newGraphRunError(fmt.Errorf("no tasks to execute, last completed nodes: %v", printTask(lastCompletedTask)))
 		}
 		lastCompletedTask = completedTasks
+		if stepCapture != nil {
+			stepCapture.captureStep(ctx, step)
+		}
 
 		var isEnd bool
 		nextTasks, result, isEnd, err = r.calculateNextTasks(ctx, completedTasks, isStream, cm, optMap)
@@ -325,6 +405,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		if isEnd {
 			return result, nil
 		}
+		nextTasks = append(nextTasks, fallbackTasks...)
 
 		tempInfo.interruptBeforeNodes = getHitKey(nextTasks, r.interruptBeforeNodes)
 
@@ -544,6 +625,7 @@ func (r *runner) handleInterrupt(
 		if err != nil {
 			return fmt.Errorf("failed to set checkpoint: %w, checkPointID: %s", err, *checkPointID)
 		}
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventCheckPointSaved, CheckPointID: *checkPointID})
 	}
 
 	intInfo.InterruptContexts = core.ToInterruptContexts(is, nil)
@@ -679,6 +761,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 		if err != nil {
 			return fmt.Errorf("failed to set checkpoint: %w, checkPointID: %s", err, *checkPointID)
 		}
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventCheckPointSaved, CheckPointID: *checkPointID})
 	}
 	intInfo.InterruptContexts = core.ToInterruptContexts(is, nil)
 	return &interruptError{Info: intInfo}
@@ -705,6 +788,7 @@ func (r *runner) calculateNextTasks(ctx context.Context, completedTasks []*task,
 		if err != nil {
 			return nil, nil, false, fmt.Errorf("failed to create tasks: %w", err)
 		}
+		keepFallbackInputs(nextTasks, isStream, cm)
 	}
 	return nextTasks, nil, false, nil
 }
@@ -808,6 +892,9 @@ func (r *runner) resolveCompletedTasks(ctx context.Context, completedTasks []*ta
 
 		// update channel & new_next_tasks
 		vs := copyItem(t.output, len(t.call.writeTo)+len(t.call.writeToBranches)*2)
+		if mc := getMetricsCollector(ctx); mc != nil && len(vs) > 1 {
+			mc.trackCopies(t.nodeKey, vs)
+		}
 		nextNodeKeys, err := r.calculateBranch(ctx, t.nodeKey, t.call,
 			vs[len(t.call.writeTo)+len(t.call.writeToBranches):], isStream, cm)
 		if err != nil {
@@ -867,6 +954,14 @@ func (r *runner) calculateBranch(ctx context.Context, curNodeKey string, startCh
 			}
 		}
 
+		if branch.fallback && len(ws) > 1 {
+			// run the first candidate only, the others are tried in order if it fails
+			cm.setFallbacks(ws[0], ws[1:])
+			ws = ws[:1]
+		}
+
+		onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventBranchEvaluated, NodeKey: curNodeKey, Targets: ws})
+
 		for node := range branch.endNodes {
 			skipped := true
 			for _, w := range ws {
@@ -910,10 +1005,17 @@ func (r *runner) initTaskManager(runWrapper runnableCallWrapper, cancelVal *grap
 		needAll:      !r.eager,
 		done:         internal.NewUnboundedChan[*task](),
 		runningTasks: make(map[string]*task),
+
+		panicRecoveryDisabled: r.options.panicRecoveryDisabled,
 	}
 	if cancelVal != nil {
 		tm.cancelCh = cancelVal.ch
 	}
+	for i := range opts {
+		if opts[i].streamChunkMeta {
+			tm.streamChunkMeta = true
+		}
+	}
 	return tm
 }
 
@@ -984,6 +1086,8 @@ func (r *runner) toComposableRunnable() *composableRunnable {
 		outputType:    r.outputType,
 		genericHelper: r.genericHelper,
 		optionType:    nil, // if option type is nil, graph will transmit all options.
+		paradigms:     paradigmInvoke | paradigmTransform,
+		configurable:  r.configurable,
 	}
 
 	return cr
@@ -1025,3 +1129,35 @@ func printTask(ts []*task) string {
 	sb.WriteString("]")
 	return sb.String()
 }
+
+// beforeStep checks the cost budget and the node visit budgets, and calls the step hooks before the tasks of a super step are submitted.
+func (r *runner) beforeStep(ctx context.Context, step int, tasks []*task, visits map[string]int) error {
+	if err := checkCostBudget(ctx); err != nil {
+		return err
+	}
+
+	if len(r.options.stepHooks) == 0 && visits == nil {
+		return nil
+	}
+
+	activeNodes := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		activeNodes = append(activeNodes, t.nodeKey)
+		budget, ok := r.options.nodeVisitBudgets[t.nodeKey]
+		if !ok {
+			continue
+		}
+		visits[t.nodeKey]++
+		if visits[t.nodeKey] > budget {
+			return &NodeVisitBudgetExceededError{NodeKey: t.nodeKey, Budget: budget, Step: step}
+		}
+	}
+
+	sort.Strings(activeNodes)
+	for _, hook := range r.options.stepHooks {
+		if err := hook(ctx, step, activeNodes); err != nil {
+			return fmt.Errorf("step hook fail: %w", err)
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -29,8 +30,12 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/audio"
+	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -165,6 +170,10 @@ func TestGraphWithImplementableType(t *testing.T) {
 	_, err = r.Invoke(ctx, "how are you", WithRuntimeMaxSteps(1))
 	assert.Error(t, err)
 	assert.ErrorContains(t, err, "exceeds max steps")
+	var msErr *einoerr.MaxStepsError
+	assert.True(t, errors.As(err, &msErr))
+	assert.Equal(t, 1, msErr.MaxSteps)
+	assert.ErrorIs(t, err, einoerr.ErrMaxSteps)
 
 	out, err := r.Invoke(ctx, "how are you")
 	assert.NoError(t, err)
@@ -354,6 +363,10 @@ func TestValidate(t *testing.T) {
 
 	err = g.AddEdge("1", "2")
 	assert.ErrorContains(t, err, "graph edge[1]-[2]: start node's output type[string] and end node's input type[int] mismatch")
+	var tmErr *einoerr.TypeMismatchError
+	assert.True(t, errors.As(err, &tmErr))
+	assert.Equal(t, reflect.TypeOf(0), tmErr.Expected)
+	assert.Equal(t, reflect.TypeOf(""), tmErr.Actual)
 
 	// test unmatched passthrough node
 	g = NewGraph[string, string]()
@@ -1141,6 +1154,7 @@ func TestGraphCompileCallback(t *testing.T) {
 			Nodes: map[string]GraphNodeInfo{
 				"node1": {
 					Component:        ComponentOfLambda,
+					paradigms:        paradigmInvoke,
 					Instance:         lambda,
 					GraphAddNodeOpts: lambdaOpts,
 					InputType:        reflect.TypeOf(""),
@@ -1150,18 +1164,21 @@ func TestGraphCompileCallback(t *testing.T) {
 				},
 				"pass1": {
 					Component:  ComponentOfPassthrough,
+					paradigms:  paradigmInvoke | paradigmTransform,
 					InputType:  reflect.TypeOf(""),
 					OutputType: reflect.TypeOf(""),
 					Name:       "",
 				},
 				"pass2": {
 					Component:  ComponentOfPassthrough,
+					paradigms:  paradigmInvoke | paradigmTransform,
 					InputType:  reflect.TypeOf(""),
 					OutputType: reflect.TypeOf(""),
 					Name:       "",
 				},
 				"sub_graph": {
 					Component:        ComponentOfGraph,
+					paradigms:        paradigmInvoke | paradigmTransform,
 					Instance:         subGraph,
 					GraphAddNodeOpts: subGraphOpts,
 					InputType:        reflect.TypeOf(""),
@@ -1172,6 +1189,7 @@ func TestGraphCompileCallback(t *testing.T) {
 						Nodes: map[string]GraphNodeInfo{
 							"sub_sub_1": {
 								Component:        ComponentOfGraph,
+								paradigms:        paradigmInvoke | paradigmTransform,
 								Instance:         subSubGraph,
 								GraphAddNodeOpts: ssGraphOpts,
 								InputType:        reflect.TypeOf(""),
@@ -1182,6 +1200,7 @@ func TestGraphCompileCallback(t *testing.T) {
 									Nodes: map[string]GraphNodeInfo{
 										"sub1": {
 											Component:        ComponentOfLambda,
+											paradigms:        paradigmInvoke,
 											Instance:         lambda2,
 											GraphAddNodeOpts: lambdaOpts2,
 											InputType:        reflect.TypeOf(""),
@@ -1219,6 +1238,7 @@ func TestGraphCompileCallback(t *testing.T) {
 				},
 				"node3": {
 					Component:        ComponentOfLambda,
+					paradigms:        paradigmInvoke,
 					Instance:         lambda3,
 					GraphAddNodeOpts: lambdaOpts3,
 					InputType:        reflect.TypeOf(""),
@@ -1228,6 +1248,7 @@ func TestGraphCompileCallback(t *testing.T) {
 				},
 				"node4": {
 					Component:        ComponentOfLambda,
+					paradigms:        paradigmInvoke,
 					Instance:         lambda4,
 					GraphAddNodeOpts: lambdaOpts4,
 					InputType:        reflect.TypeOf(""),
@@ -2064,3 +2085,337 @@ func (t *testGraphStateCallbackHandler) OnStartWithStreamInput(ctx context.Conte
 func (t *testGraphStateCallbackHandler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
 	return ctx
 }
+
+func TestGraphEventCallbacks(t *testing.T) {
+	g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) (s *state) {
+		return &state{}
+	}))
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input, nil
+	}), WithStatePostHandler(func(ctx context.Context, out string, s *state) (string, error) {
+		s.A = out
+		return out, nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + "2", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("3", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + "3", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddBranch("1", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		return "2", nil
+	}, map[string]bool{"2": true, "3": true})))
+	assert.NoError(t, g.AddEdge("2", END))
+	assert.NoError(t, g.AddEdge("3", END))
+
+	ctx := context.Background()
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	var events []*callbacks.GraphEvent
+	handler := callbacks.NewHandlerBuilder().OnGraphEventFn(func(ctx context.Context, info *callbacks.RunInfo, event *callbacks.GraphEvent) {
+		events = append(events, event)
+	}).Build()
+
+	out, err := r.Invoke(ctx, "input", WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Equal(t, "input2", out)
+	assert.Equal(t, []*callbacks.GraphEvent{
+		{Type: callbacks.GraphEventRunStart},
+		{Type: callbacks.GraphEventStateMutated, NodeKey: "1"},
+		{Type: callbacks.GraphEventBranchEvaluated, NodeKey: "1", Targets: []string{"2"}},
+		{Type: callbacks.GraphEventRunEnd},
+	}, events)
+
+	loop := NewGraph[string, string]()
+	assert.NoError(t, loop.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input, nil
+	})))
+	assert.NoError(t, loop.AddEdge(START, "1"))
+	assert.NoError(t, loop.AddBranch("1", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		return "1", nil
+	}, map[string]bool{"1": true, END: true})))
+	lr, err := loop.Compile(ctx, WithMaxRunSteps(3))
+	assert.NoError(t, err)
+
+	events = nil
+	_, err = lr.Invoke(ctx, "input", WithCallbacks(handler))
+	assert.ErrorIs(t, err, ErrExceedMaxSteps)
+	assert.Equal(t, callbacks.GraphEventMaxStepsExceeded, events[len(events)-2].Type)
+	assert.Equal(t, 3, events[len(events)-2].Step)
+	assert.Equal(t, callbacks.GraphEventRunEnd, events[len(events)-1].Type)
+	assert.ErrorIs(t, events[len(events)-1].Err, ErrExceedMaxSteps)
+
+	// the state is unlocked when the event is emitted, so the handler can access it
+	var mutated string
+	stateHandler := callbacks.NewHandlerBuilder().OnGraphEventFn(func(ctx context.Context, info *callbacks.RunInfo, event *callbacks.GraphEvent) {
+		if event.Type != callbacks.GraphEventStateMutated {
+			return
+		}
+		_ = ProcessStateRead[*state](ctx, func(ctx context.Context, s *state) error {
+			mutated = event.NodeKey + ":" + s.A
+			return nil
+		})
+	}).Build()
+	_, err = r.Invoke(ctx, "input", WithCallbacks(stateHandler))
+	assert.NoError(t, err)
+	assert.Equal(t, "1:input", mutated)
+}
+
+func TestAddEdgeWithTransform(t *testing.T) {
+	ctx := context.Background()
+
+	toContent := NewEdgeTransform(func(m *schema.Message) (string, error) {
+		return m.Content, nil
+	})
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("gen", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[*schema.Message], error) {
+		return schema.StreamReaderFromArray([]*schema.Message{
+			schema.AssistantMessage(input, nil),
+			schema.AssistantMessage("!", nil),
+		}), nil
+	})))
+	assert.NoError(t, g.AddPassthroughNode("pass"))
+	assert.NoError(t, g.AddLambdaNode("upper", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return strings.ToUpper(input), nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "gen"))
+	assert.NoError(t, g.AddEdgeWithTransform("gen", "pass", toContent))
+	assert.NoError(t, g.AddEdge("pass", "upper"))
+	assert.NoError(t, g.AddEdge("upper", END))
+
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "HI!", out)
+
+	sr, err := r.Stream(ctx, "hi")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "HI!", out)
+
+	// the transform's input type must match the start node's output type
+	g = NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("upper", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return strings.ToUpper(input), nil
+	})))
+	assert.ErrorContains(t, g.AddEdgeWithTransform(START, "upper", toContent), "and edge transform's input type[*schema.Message] mismatch")
+}
+
+func TestStepHooksAndNodeVisitBudgets(t *testing.T) {
+	ctx := context.Background()
+
+	newGraph := func() *Graph[int, int] {
+		g := NewGraph[int, int]()
+		assert.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in int) (int, error) {
+			return in + 1, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, in int) (int, error) {
+			return in, nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "a"))
+		assert.NoError(t, g.AddEdge("a", "b"))
+		assert.NoError(t, g.AddBranch("b", NewGraphBranch(func(ctx context.Context, in int) (string, error) {
+			if in < 3 {
+				return "a", nil
+			}
+			return END, nil
+		}, map[string]bool{"a": true, END: true})))
+		return g
+	}
+
+	var steps [][]string
+	r, err := newGraph().Compile(ctx, WithStepHooks(func(ctx context.Context, step int, activeNodes []string) error {
+		assert.Equal(t, len(steps), step)
+		steps = append(steps, activeNodes)
+		return nil
+	}))
+	assert.NoError(t, err)
+	out, err := r.Invoke(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, out)
+	assert.Equal(t, [][]string{{"a"}, {"b"}, {"a"}, {"b"}, {"a"}, {"b"}}, steps)
+
+	r, err = newGraph().Compile(ctx, WithStepHooks(func(ctx context.Context, step int, activeNodes []string) error {
+		if step == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	}))
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, 0)
+	assert.ErrorContains(t, err, "stop")
+
+	r, err = newGraph().Compile(ctx, WithNodeVisitBudgets(map[string]int{"a": 3}))
+	assert.NoError(t, err)
+	out, err = r.Invoke(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, out)
+
+	r, err = newGraph().Compile(ctx, WithNodeVisitBudgets(map[string]int{"a": 2}))
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, 0)
+	assert.ErrorIs(t, err, ErrNodeVisitBudgetExceeded)
+	var budgetErr *NodeVisitBudgetExceededError
+	assert.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, "a", budgetErr.NodeKey)
+	assert.Equal(t, 4, budgetErr.Step)
+
+	_, err = newGraph().Compile(ctx, WithNodeVisitBudgets(map[string]int{"c": 1}))
+	assert.ErrorIs(t, err, einoerr.ErrNodeNotFound)
+}
+
+type streamLoader struct{}
+
+func (streamLoader) Load(ctx context.Context, src document.Source, opts ...document.LoaderOption) ([]*schema.Document, error) {
+	sr, _ := streamLoader{}.LoadStream(ctx, src, opts...)
+	return concatStreamReader(sr)
+}
+
+func (streamLoader) LoadStream(_ context.Context, src document.Source, _ ...document.LoaderOption) (*schema.StreamReader[[]*schema.Document], error) {
+	return schema.StreamReaderFromArray([][]*schema.Document{
+		{{ID: "1", Content: src.URI + " a"}, {ID: "2", Content: src.URI + " b"}},
+		{{ID: "3", Content: src.URI + " c"}},
+	}), nil
+}
+
+type upperTransformer struct{}
+
+func (upperTransformer) Transform(_ context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, 0, len(src))
+	for _, doc := range src {
+		ret = append(ret, &schema.Document{ID: doc.ID, Content: strings.ToUpper(doc.Content)})
+	}
+	return ret, nil
+}
+
+func TestDocumentStreamNodes(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[document.Source, []*schema.Document]()
+	assert.NoError(t, g.AddLoaderNode("loader", streamLoader{}))
+	assert.NoError(t, g.AddDocumentTransformerNode("transformer", document.NewChunkwiseTransformer(upperTransformer{})))
+	assert.NoError(t, g.AddEdge(START, "loader"))
+	assert.NoError(t, g.AddEdge("loader", "transformer"))
+	assert.NoError(t, g.AddEdge("transformer", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, document.Source{URI: "x"})
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+	assert.Equal(t, "X C", out[2].Content)
+
+	sr, err := r.Stream(ctx, document.Source{URI: "x"})
+	assert.NoError(t, err)
+	var chunks [][]*schema.Document
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	// the documents are passed through chunk by chunk
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], 2)
+	assert.Equal(t, "X A", chunks[0][0].Content)
+}
+
+type speechToTextForTest struct{}
+
+func (s *speechToTextForTest) Transcribe(ctx context.Context, in *schema.StreamReader[*schema.AudioChunk], opts ...audio.Option) (*schema.StreamReader[string], error) {
+	return schema.StreamReaderWithConvert(in, func(c *schema.AudioChunk) (string, error) {
+		return string(c.Data), nil
+	}), nil
+}
+
+type textToSpeechForTest struct{}
+
+func (s *textToSpeechForTest) Synthesize(ctx context.Context, in *schema.StreamReader[string], opts ...audio.Option) (*schema.StreamReader[*schema.AudioChunk], error) {
+	voice := "default"
+	o := audio.GetCommonOptions(&audio.Options{Voice: &voice}, opts...)
+	return schema.StreamReaderWithConvert(in, func(text string) (*schema.AudioChunk, error) {
+		return &schema.AudioChunk{Data: []byte(text), MIMEType: "audio/pcm", Extra: map[string]any{"voice": *o.Voice}}, nil
+	}), nil
+}
+
+func TestSpeechNodes(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[*schema.AudioChunk, *schema.AudioChunk]()
+	assert.NoError(t, g.AddSpeechToTextNode("stt", &speechToTextForTest{}))
+	assert.NoError(t, g.AddLambdaNode("upper", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return strings.ToUpper(in), nil
+	})))
+	assert.NoError(t, g.AddTextToSpeechNode("tts", &textToSpeechForTest{}))
+	assert.NoError(t, g.AddEdge(START, "stt"))
+	assert.NoError(t, g.AddEdge("stt", "upper"))
+	assert.NoError(t, g.AddEdge("upper", "tts"))
+	assert.NoError(t, g.AddEdge("tts", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, &schema.AudioChunk{Data: []byte("hello")}, WithAudioOption(audio.WithVoice("alloy")))
+	assert.NoError(t, err)
+	assert.Equal(t, &schema.AudioChunk{Data: []byte("HELLO"), MIMEType: "audio/pcm", Extra: map[string]any{"voice": "alloy"}}, out)
+
+	var (
+		heard  []string
+		spoken []string
+	)
+	handler := callbacks.NewHandlerBuilder().
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			defer input.Close()
+			if info.Component != components.ComponentOfSpeechToText {
+				return ctx
+			}
+			for {
+				chunk, err := input.Recv()
+				if err != nil {
+					return ctx
+				}
+				heard = append(heard, string(audio.ConvSpeechToTextCallbackInput(chunk).Audio.Data))
+			}
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer output.Close()
+			if info.Component != components.ComponentOfTextToSpeech {
+				return ctx
+			}
+			for {
+				chunk, err := output.Recv()
+				if err != nil {
+					return ctx
+				}
+				spoken = append(spoken, string(audio.ConvTextToSpeechCallbackOutput(chunk).Audio.Data))
+			}
+		}).Build()
+
+	sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]*schema.AudioChunk{{Data: []byte("he")}, {Data: []byte("llo")}}),
+		WithCallbacks(handler))
+	assert.NoError(t, err)
+	out, err = schema.ConcatAudioChunks(mustReadAll(t, sr))
+	assert.NoError(t, err)
+	assert.Equal(t, "HELLO", string(out.Data))
+	assert.Equal(t, "default", out.Extra["voice"])
+	assert.Equal(t, []string{"he", "llo"}, heard)
+	assert.Equal(t, []string{"HELLO"}, spoken)
+}
+
+func mustReadAll[T any](t *testing.T, sr *schema.StreamReader[T]) []T {
+	defer sr.Close()
+	var ret []T
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			return ret
+		}
+		assert.NoError(t, err)
+		ret = append(ret, chunk)
+	}
+}
@@ -0,0 +1,452 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// GuardrailAction is the action decided by a guardrail for the content.
+type GuardrailAction string
+
+const (
+	// GuardrailAllow lets the content pass as is.
+	GuardrailAllow GuardrailAction = "allow"
+	// GuardrailBlock stops the content, failing the node with a *GuardrailBlockedError,
+	// or replacing the content by GuardrailConfig.BlockedResponse if set.
+	GuardrailBlock GuardrailAction = "block"
+	// GuardrailTransform replaces the content with the transformed one, e.g. masking the sensitive words.
+	GuardrailTransform GuardrailAction = "transform"
+	// GuardrailAnnotate lets the content pass, recording the verdict in the Extra of the message, see GetGuardrailVerdicts.
+	GuardrailAnnotate GuardrailAction = "annotate"
+)
+
+// GuardrailVerdict is the decision of a guardrail.
+type GuardrailVerdict struct {
+	// Guardrail is the name of the guardrail, optional.
+	Guardrail string
+	// Action is the action for the content.
+	Action GuardrailAction
+	// Reason explains the decision, e.g. the category of the violation.
+	Reason string
+	// Labels are the extra annotations, e.g. the scores of a moderation model.
+	Labels map[string]any
+}
+
+// Guardrail checks the content flowing into or out of the models, e.g. by keywords, regex or a moderation model.
+// It returns the verdict, and the transformed content if the action is GuardrailTransform.
+// A nil verdict means GuardrailAllow.
+type Guardrail interface {
+	Check(ctx context.Context, content string) (verdict *GuardrailVerdict, transformed string, err error)
+}
+
+// GuardrailFunc is an adapter to use a function as Guardrail, e.g. to plug in a moderation model:
+//
+//	moderator := compose.GuardrailFunc(func(ctx context.Context, content string) (*compose.GuardrailVerdict, string, error) {
+//		resp, err := moderationModel.Generate(ctx, []*schema.Message{schema.SystemMessage(policy), schema.UserMessage(content)})
+//		if err != nil {
+//			return nil, "", err
+//		}
+//		if strings.HasPrefix(resp.Content, "unsafe") {
+//			return &compose.GuardrailVerdict{Action: compose.GuardrailBlock, Reason: resp.Content}, "", nil
+//		}
+//		return nil, "", nil
+//	})
+type GuardrailFunc func(ctx context.Context, content string) (*GuardrailVerdict, string, error)
+
+// Check calls f(ctx, content).
+func (f GuardrailFunc) Check(ctx context.Context, content string) (*GuardrailVerdict, string, error) {
+	return f(ctx, content)
+}
+
+// GuardrailBlockedError is returned by the guardrail node when the content is blocked.
+type GuardrailBlockedError struct {
+	Verdict *GuardrailVerdict
+}
+
+func (e *GuardrailBlockedError) Error() string {
+	if e.Verdict.Guardrail != "" {
+		return fmt.Sprintf("content blocked by guardrail[%s]: %s", e.Verdict.Guardrail, e.Verdict.Reason)
+	}
+	return fmt.Sprintf("content blocked by guardrail: %s", e.Verdict.Reason)
+}
+
+// GuardrailExtraKey is the key of the verdicts in the Extra of the messages passing the guardrail node.
+const GuardrailExtraKey = "_guardrail_verdicts"
+
+// GetGuardrailVerdicts returns the verdicts other than GuardrailAllow recorded in the message by the guardrail nodes.
+func GetGuardrailVerdicts(msg *schema.Message) []*GuardrailVerdict {
+	if msg == nil || msg.Extra == nil {
+		return nil
+	}
+	v, _ := msg.Extra[GuardrailExtraKey].([]*GuardrailVerdict)
+	return v
+}
+
+// DefaultGuardrailHoldBack is the default GuardrailConfig.HoldBack.
+const DefaultGuardrailHoldBack = 64
+
+// GuardrailConfig is the config of the guardrail node.
+type GuardrailConfig struct {
+	// Guardrails check the content in order, each one receiving the content transformed by the previous ones.
+	Guardrails []Guardrail
+	// BlockedResponse replaces the blocked content instead of failing the node, if set.
+	// When streaming, it's only used if nothing has been emitted yet.
+	BlockedResponse string
+	// Roles limits the messages checked to those of the roles, default is all the messages.
+	Roles []schema.RoleType
+	// HoldBack is the number of runes held back when streaming, default is DefaultGuardrailHoldBack.
+	// The guardrails check the text accumulated so far whenever twice the runes are pending,
+	// and the text is emitted except the last HoldBack runes, so that a violation spanning several chunks
+	// is still caught before it's emitted. It should be longer than the longest content the guardrails can match.
+	HoldBack int
+}
+
+// GuardrailContent is the type of the data a guardrail node checks.
+// For messages, the text contents are checked, while the tool calls are passed as is.
+type GuardrailContent interface {
+	string | *schema.Message | []*schema.Message
+}
+
+// GuardrailNode is a node blocking, transforming or annotating the data flowing through it by guardrails.
+// It outputs the same type as its input, so it can be put before a chat model to moderate the input,
+// or after a chat model to moderate the output. Streams are checked as they flow, see GuardrailConfig.HoldBack.
+type GuardrailNode struct {
+	executor *composableRunnable
+}
+
+// NewGuardrailNode creates a guardrail node checking T.
+// e.g.
+//
+//	keywords, _ := compose.NewKeywordGuardrail(&compose.KeywordGuardrailConfig{Keywords: []string{"password"}})
+//	node, err := compose.NewGuardrailNode[*schema.Message](&compose.GuardrailConfig{Guardrails: []compose.Guardrail{keywords}})
+//	chain.AppendChatModel(cm).AppendGuardrail(node)
+func NewGuardrailNode[T GuardrailContent](config *GuardrailConfig) (*GuardrailNode, error) {
+	if config == nil || len(config.Guardrails) == 0 {
+		return nil, errors.New("guardrails of guardrail node are required")
+	}
+	for _, g := range config.Guardrails {
+		if g == nil {
+			return nil, errors.New("guardrail is nil")
+		}
+	}
+
+	r := &guardrailRunner{
+		config:   config,
+		holdBack: config.HoldBack,
+	}
+	if r.holdBack <= 0 {
+		r.holdBack = DefaultGuardrailHoldBack
+	}
+	if len(config.Roles) > 0 {
+		r.roles = make(map[schema.RoleType]bool, len(config.Roles))
+		for _, role := range config.Roles {
+			r.roles[role] = true
+		}
+	}
+
+	var (
+		invoke    Invoke[T, T, any]
+		transform Transform[T, T, any]
+	)
+	switch any(*new(T)).(type) {
+	case string:
+		invoke = func(ctx context.Context, input T, _ ...any) (T, error) {
+			out, _, err := r.checkText(ctx, any(input).(string))
+			if err != nil {
+				return *new(T), err
+			}
+			return any(out).(T), nil
+		}
+		transform = func(ctx context.Context, input *schema.StreamReader[T], _ ...any) (*schema.StreamReader[T], error) {
+			msgs := schema.StreamReaderWithConvert(input, func(t T) (*schema.Message, error) {
+				return &schema.Message{Content: any(t).(string)}, nil
+			})
+			return schema.StreamReaderWithConvert(r.transformMessages(ctx, msgs), func(msg *schema.Message) (T, error) {
+				if msg.Content == "" {
+					return *new(T), schema.ErrNoValue
+				}
+				return any(msg.Content).(T), nil
+			}), nil
+		}
+	case *schema.Message:
+		invoke = func(ctx context.Context, input T, _ ...any) (T, error) {
+			out, err := r.checkMessage(ctx, any(input).(*schema.Message))
+			if err != nil {
+				return *new(T), err
+			}
+			return any(out).(T), nil
+		}
+		transform = func(ctx context.Context, input *schema.StreamReader[T], _ ...any) (*schema.StreamReader[T], error) {
+			msgs := schema.StreamReaderWithConvert(input, func(t T) (*schema.Message, error) {
+				return any(t).(*schema.Message), nil
+			})
+			return schema.StreamReaderWithConvert(r.transformMessages(ctx, msgs), func(msg *schema.Message) (T, error) {
+				return any(msg).(T), nil
+			}), nil
+		}
+	case []*schema.Message:
+		invoke = func(ctx context.Context, input T, _ ...any) (T, error) {
+			in := any(input).([]*schema.Message)
+			out := make([]*schema.Message, len(in))
+			for i, msg := range in {
+				var err error
+				if out[i], err = r.checkMessage(ctx, msg); err != nil {
+					return *new(T), err
+				}
+			}
+			return any(out).(T), nil
+		}
+	}
+
+	executor := runnableLambda(invoke, nil, nil, transform, true)
+	executor.meta = &executorMeta{
+		component:                  ComponentOfGuardrail,
+		isComponentCallbackEnabled: false,
+	}
+	return &GuardrailNode{executor: executor}, nil
+}
+
+type guardrailRunner struct {
+	config   *GuardrailConfig
+	holdBack int
+	roles    map[schema.RoleType]bool
+}
+
+// checkText runs the guardrails in order, returning the content to pass on and the verdicts other than GuardrailAllow.
+func (r *guardrailRunner) checkText(ctx context.Context, content string) (string, []*GuardrailVerdict, error) {
+	var verdicts []*GuardrailVerdict
+	for _, g := range r.config.Guardrails {
+		verdict, transformed, err := g.Check(ctx, content)
+		if err != nil {
+			return "", nil, fmt.Errorf("guardrail check fail: %w", err)
+		}
+		if verdict == nil || verdict.Action == GuardrailAllow || verdict.Action == "" {
+			continue
+		}
+
+		verdicts = append(verdicts, verdict)
+		switch verdict.Action {
+		case GuardrailBlock:
+			if r.config.BlockedResponse != "" {
+				return r.config.BlockedResponse, verdicts, nil
+			}
+			return "", verdicts, &GuardrailBlockedError{Verdict: verdict}
+		case GuardrailTransform:
+			content = transformed
+		}
+	}
+	return content, verdicts, nil
+}
+
+func (r *guardrailRunner) checkMessage(ctx context.Context, msg *schema.Message) (*schema.Message, error) {
+	if msg == nil || (r.roles != nil && !r.roles[msg.Role]) {
+		return msg, nil
+	}
+
+	var (
+		cp       = *msg
+		verdicts []*GuardrailVerdict
+	)
+	check := func(content string) (string, error) {
+		if content == "" {
+			return content, nil
+		}
+		out, vs, err := r.checkText(ctx, content)
+		verdicts = append(verdicts, vs...)
+		return out, err
+	}
+
+	var err error
+	if cp.Content, err = check(msg.Content); err != nil {
+		return nil, err
+	}
+	if len(msg.UserInputMultiContent) > 0 {
+		cp.UserInputMultiContent = make([]schema.MessageInputPart, len(msg.UserInputMultiContent))
+		copy(cp.UserInputMultiContent, msg.UserInputMultiContent)
+		for i := range cp.UserInputMultiContent {
+			if cp.UserInputMultiContent[i].Text, err = check(cp.UserInputMultiContent[i].Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(msg.AssistantGenMultiContent) > 0 {
+		cp.AssistantGenMultiContent = make([]schema.MessageOutputPart, len(msg.AssistantGenMultiContent))
+		copy(cp.AssistantGenMultiContent, msg.AssistantGenMultiContent)
+		for i := range cp.AssistantGenMultiContent {
+			if cp.AssistantGenMultiContent[i].Text, err = check(cp.AssistantGenMultiContent[i].Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	withVerdicts(&cp, verdicts)
+	return &cp, nil
+}
+
+func withVerdicts(msg *schema.Message, verdicts []*GuardrailVerdict) {
+	if len(verdicts) == 0 {
+		return
+	}
+	extra := make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		extra[k] = v
+	}
+	extra[GuardrailExtraKey] = append(GetGuardrailVerdicts(msg), verdicts...)
+	msg.Extra = extra
+}
+
+// transformMessages checks the contents of the message stream as they flow, holding back the tail of the checked text,
+// and passes the other fields of the chunks, e.g. the tool calls, as is.
+func (r *guardrailRunner) transformMessages(ctx context.Context, input *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	sr, sw := schema.Pipe[*schema.Message](0)
+
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				sw.Send(nil, fmt.Errorf("guardrail stream panic: %v", e))
+			}
+			input.Close()
+			sw.Close()
+		}()
+
+		var (
+			role     schema.RoleType
+			raw      strings.Builder
+			pending  int    // runes received but not checked yet
+			emitted  string // the checked text already emitted
+			verdicts []*GuardrailVerdict
+		)
+
+		// flush checks the accumulated text and emits it except the held back runes, or all of it if final
+		flush := func(final bool) bool {
+			checked, vs, err := r.checkText(ctx, raw.String())
+			if err != nil {
+				sw.Send(nil, err)
+				return false
+			}
+			verdicts = vs
+			if len(vs) > 0 && vs[len(vs)-1].Action == GuardrailBlock {
+				// checked is the BlockedResponse, which can't replace the content partially emitted
+				if emitted != "" {
+					sw.Send(nil, &GuardrailBlockedError{Verdict: vs[len(vs)-1]})
+					return false
+				}
+				chunk := &schema.Message{Role: role, Content: checked}
+				withVerdicts(chunk, verdicts)
+				sw.Send(chunk, nil)
+				return false
+			}
+			if !strings.HasPrefix(checked, emitted) {
+				sw.Send(nil, fmt.Errorf("guardrail transformed the content already streamed, try a larger hold back than %d", r.holdBack))
+				return false
+			}
+
+			end := len(checked)
+			if !final {
+				for i := 0; i < r.holdBack && end > len(emitted); i++ {
+					_, size := utf8.DecodeLastRuneInString(checked[:end])
+					end -= size
+				}
+			}
+			pending = 0
+			if end <= len(emitted) && !final {
+				return true
+			}
+
+			chunk := &schema.Message{Role: role, Content: checked[len(emitted):end]}
+			if final {
+				withVerdicts(chunk, verdicts)
+			}
+			emitted = checked[:end]
+			return !sw.Send(chunk, nil)
+		}
+
+		for {
+			chunk, err := input.Recv()
+			if err == io.EOF {
+				flush(true)
+				return
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if chunk == nil {
+				continue
+			}
+
+			if chunk.Role != "" {
+				role = chunk.Role
+			}
+			if r.roles != nil && role != "" && !r.roles[role] {
+				// not checked, pass as is
+				if sw.Send(chunk, nil) {
+					return
+				}
+				continue
+			}
+
+			if chunk.Content != "" {
+				raw.WriteString(chunk.Content)
+				pending += utf8.RuneCountInString(chunk.Content)
+			}
+			if hasNonContent(chunk) {
+				cp := *chunk
+				cp.Content = ""
+				if sw.Send(&cp, nil) {
+					return
+				}
+			}
+
+			if pending >= 2*r.holdBack && !flush(false) {
+				return
+			}
+		}
+	}()
+
+	return sr
+}
+
+func hasNonContent(msg *schema.Message) bool {
+	return len(msg.ToolCalls) > 0 || msg.ResponseMeta != nil || msg.ReasoningContent != "" ||
+		len(msg.Extra) > 0 || len(msg.AssistantGenMultiContent) > 0 || len(msg.UserInputMultiContent) > 0 ||
+		len(msg.MultiContent) > 0 || msg.ToolCallID != "" || msg.Name != ""
+}
+
+// AddGuardrailNode adds a guardrail node to the graph, see NewGuardrailNode.
+// e.g.
+//
+//	node, err := compose.NewGuardrailNode[[]*schema.Message](&compose.GuardrailConfig{Guardrails: guardrails})
+//	graph.AddGuardrailNode("input_guardrail", node)
+func (g *graph) AddGuardrailNode(key string, node *GuardrailNode, opts ...GraphAddNodeOpt) error {
+	gNode, options := toGuardrailNode(node, opts...)
+	return g.addNode(key, gNode, options)
+}
+
+func toGuardrailNode(node *GuardrailNode, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	info, options := getNodeInfo(opts...)
+	gn := toNode(info, node.executor, nil, node.executor.meta, node, opts...)
+	return gn, options
+}
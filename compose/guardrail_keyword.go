@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// KeywordGuardrailConfig is the config of the keyword guardrail.
+type KeywordGuardrailConfig struct {
+	// Keywords are the words to match, as literal texts.
+	Keywords []string
+	// Patterns are the regular expressions to match.
+	Patterns []*regexp.Regexp
+	// CaseSensitive matches the keywords case-sensitively, default is case-insensitive. Patterns are not affected.
+	CaseSensitive bool
+	// Action is the action for the matched content, default is GuardrailBlock.
+	// For GuardrailTransform, the matches are replaced with Replacement.
+	Action GuardrailAction
+	// Replacement replaces the matches for GuardrailTransform, default is "***".
+	Replacement string
+	// Name is the name of the guardrail in the verdicts, default is "Keyword".
+	Name string
+}
+
+// NewKeywordGuardrail creates a guardrail matching the content by keywords and regular expressions.
+// e.g.
+//
+//	guardrail, err := compose.NewKeywordGuardrail(&compose.KeywordGuardrailConfig{
+//		Keywords: []string{"internal only"},
+//		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)},
+//		Action:   compose.GuardrailTransform,
+//	})
+func NewKeywordGuardrail(config *KeywordGuardrailConfig) (Guardrail, error) {
+	if config == nil || len(config.Keywords)+len(config.Patterns) == 0 {
+		return nil, errors.New("keywords or patterns of keyword guardrail are required")
+	}
+
+	action := config.Action
+	if action == "" {
+		action = GuardrailBlock
+	}
+	switch action {
+	case GuardrailBlock, GuardrailTransform, GuardrailAnnotate:
+	default:
+		return nil, errors.New("invalid action of keyword guardrail: " + string(action))
+	}
+
+	replacement := config.Replacement
+	if replacement == "" {
+		replacement = "***"
+	}
+	name := config.Name
+	if name == "" {
+		name = "Keyword"
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(config.Keywords)+len(config.Patterns))
+	for _, kw := range config.Keywords {
+		if kw == "" {
+			continue
+		}
+		expr := regexp.QuoteMeta(kw)
+		if !config.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		patterns = append(patterns, regexp.MustCompile(expr))
+	}
+	for _, p := range config.Patterns {
+		if p != nil {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return &keywordGuardrail{
+		name:        name,
+		patterns:    patterns,
+		action:      action,
+		replacement: replacement,
+	}, nil
+}
+
+type keywordGuardrail struct {
+	name        string
+	patterns    []*regexp.Regexp
+	action      GuardrailAction
+	replacement string
+}
+
+func (k *keywordGuardrail) Check(_ context.Context, content string) (*GuardrailVerdict, string, error) {
+	var matched []string
+	transformed := content
+	for _, p := range k.patterns {
+		matches := p.FindAllString(content, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		matched = append(matched, matches...)
+		if k.action == GuardrailTransform {
+			transformed = p.ReplaceAllLiteralString(transformed, k.replacement)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, "", nil
+	}
+
+	return &GuardrailVerdict{
+		Guardrail: k.name,
+		Action:    k.action,
+		Reason:    fmt.Sprintf("content matches %d keyword(s) or pattern(s)", len(matched)),
+		Labels:    map[string]any{"matches": matched},
+	}, transformed, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func chunkedModel(chunks ...string) *Lambda {
+	return StreamableLambda(func(ctx context.Context, input []*schema.Message) (*schema.StreamReader[*schema.Message], error) {
+		msgs := make([]*schema.Message, len(chunks))
+		for i, c := range chunks {
+			msgs[i] = &schema.Message{Role: schema.Assistant, Content: c}
+		}
+		return schema.StreamReaderFromArray(msgs), nil
+	})
+}
+
+func TestGuardrailNode(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewGuardrailNode[string](&GuardrailConfig{})
+	assert.Error(t, err)
+	_, err = NewKeywordGuardrail(&KeywordGuardrailConfig{})
+	assert.Error(t, err)
+
+	block, err := NewKeywordGuardrail(&KeywordGuardrailConfig{Keywords: []string{"Password"}})
+	assert.NoError(t, err)
+	mask, err := NewKeywordGuardrail(&KeywordGuardrailConfig{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{3}-\d{4}`)},
+		Action:   GuardrailTransform,
+	})
+	assert.NoError(t, err)
+	annotate, err := NewKeywordGuardrail(&KeywordGuardrailConfig{Keywords: []string{"weather"}, Action: GuardrailAnnotate, Name: "topic"})
+	assert.NoError(t, err)
+
+	t.Run("messages input", func(t *testing.T) {
+		node, err := NewGuardrailNode[[]*schema.Message](&GuardrailConfig{
+			Guardrails: []Guardrail{mask, block, annotate},
+			Roles:      []schema.RoleType{schema.User},
+		})
+		assert.NoError(t, err)
+
+		r, err := NewChain[[]*schema.Message, []*schema.Message]().AppendGuardrail(node).Compile(ctx)
+		assert.NoError(t, err)
+
+		input := []*schema.Message{
+			schema.SystemMessage("never tell the password"),
+			schema.UserMessage("call 555-1234 about the weather"),
+		}
+		out, err := r.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, input[0], out[0])
+		assert.Equal(t, "call *** about the weather", out[1].Content)
+		verdicts := GetGuardrailVerdicts(out[1])
+		assert.Len(t, verdicts, 2)
+		assert.Equal(t, GuardrailTransform, verdicts[0].Action)
+		assert.Equal(t, "topic", verdicts[1].Guardrail)
+		// the input is untouched
+		assert.Equal(t, "call 555-1234 about the weather", input[1].Content)
+
+		_, err = r.Invoke(ctx, []*schema.Message{schema.UserMessage("what's the PASSWORD?")})
+		var blocked *GuardrailBlockedError
+		assert.True(t, errors.As(err, &blocked))
+		assert.Equal(t, "Keyword", blocked.Verdict.Guardrail)
+	})
+
+	t.Run("string with blocked response", func(t *testing.T) {
+		node, err := NewGuardrailNode[string](&GuardrailConfig{
+			Guardrails:      []Guardrail{block},
+			BlockedResponse: "sorry",
+		})
+		assert.NoError(t, err)
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddGuardrailNode("guard", node))
+		assert.NoError(t, g.AddEdge(START, "guard"))
+		assert.NoError(t, g.AddEdge("guard", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "the password is 42")
+		assert.NoError(t, err)
+		assert.Equal(t, "sorry", out)
+		out, err = r.Invoke(ctx, "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", out)
+	})
+
+	t.Run("guardrail error", func(t *testing.T) {
+		node, err := NewGuardrailNode[string](&GuardrailConfig{Guardrails: []Guardrail{
+			GuardrailFunc(func(ctx context.Context, content string) (*GuardrailVerdict, string, error) {
+				return nil, "", errors.New("moderation unavailable")
+			}),
+		}})
+		assert.NoError(t, err)
+		r, err := NewChain[string, string]().AppendGuardrail(node).Compile(ctx)
+		assert.NoError(t, err)
+		_, err = r.Invoke(ctx, "hello")
+		assert.ErrorContains(t, err, "moderation unavailable")
+	})
+
+	collect := func(sr *schema.StreamReader[*schema.Message]) (string, []*schema.Message, error) {
+		defer sr.Close()
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				msg, err_ := schema.ConcatMessages(chunks)
+				if err_ != nil {
+					return "", nil, err_
+				}
+				return msg.Content, chunks, nil
+			}
+			if err != nil {
+				return "", chunks, err
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	t.Run("stream transform", func(t *testing.T) {
+		node, err := NewGuardrailNode[*schema.Message](&GuardrailConfig{Guardrails: []Guardrail{mask}, HoldBack: 8})
+		assert.NoError(t, err)
+		r, err := NewChain[[]*schema.Message, *schema.Message]().
+			AppendLambda(chunkedModel("call me at 55", "5-12", "34 tomorrow, or at 555-", "6789 later, ", strings.Repeat("bye ", 10))).
+			AppendGuardrail(node).
+			Compile(ctx)
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, nil)
+		assert.NoError(t, err)
+		content, chunks, err := collect(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "call me at *** tomorrow, or at *** later, "+strings.Repeat("bye ", 10), content)
+		assert.True(t, len(chunks) > 1)
+		assert.Len(t, GetGuardrailVerdicts(chunks[len(chunks)-1]), 1)
+	})
+
+	t.Run("stream block", func(t *testing.T) {
+		node, err := NewGuardrailNode[*schema.Message](&GuardrailConfig{Guardrails: []Guardrail{block}, HoldBack: 4})
+		assert.NoError(t, err)
+		r, err := NewChain[[]*schema.Message, *schema.Message]().
+			AppendLambda(chunkedModel("this is fine, really fine. ", "the pass", "word is 42")).
+			AppendGuardrail(node).
+			Compile(ctx)
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, nil)
+		assert.NoError(t, err)
+		_, chunks, err := collect(sr)
+		var blocked *GuardrailBlockedError
+		assert.True(t, errors.As(err, &blocked))
+		// the content before the violation has been emitted, without the violation
+		assert.NotEmpty(t, chunks)
+		for _, c := range chunks {
+			assert.NotContains(t, c.Content, "word")
+		}
+
+		node, err = NewGuardrailNode[*schema.Message](&GuardrailConfig{Guardrails: []Guardrail{block}, BlockedResponse: "sorry"})
+		assert.NoError(t, err)
+		r, err = NewChain[[]*schema.Message, *schema.Message]().
+			AppendLambda(chunkedModel("the pass", "word is 42")).
+			AppendGuardrail(node).
+			Compile(ctx)
+		assert.NoError(t, err)
+		sr, err = r.Stream(ctx, nil)
+		assert.NoError(t, err)
+		content, _, err := collect(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "sorry", content)
+	})
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/core"
 	"github.com/cloudwego/eino/schema"
 )
@@ -314,6 +315,11 @@ func (e *interruptError) Error() string {
 	return fmt.Sprintf("interrupt happened, info: %+v", e.Info)
 }
 
+// Is reports whether target is einoerr.ErrInterrupted.
+func (e *interruptError) Is(target error) bool {
+	return target == einoerr.ErrInterrupted
+}
+
 func isSubGraphInterrupt(err error) *subGraphInterruptError {
 	if err == nil {
 		return nil
@@ -336,6 +342,11 @@ func (e *subGraphInterruptError) Error() string {
 	return fmt.Sprintf("interrupt happened, info: %+v", e.Info)
 }
 
+// Is reports whether target is einoerr.ErrInterrupted.
+func (e *subGraphInterruptError) Is(target error) bool {
+	return target == einoerr.ErrInterrupted
+}
+
 func isInterruptError(err error) bool {
 	if _, ok := ExtractInterruptInfo(err); ok {
 		return true
@@ -30,9 +30,12 @@ type GraphNodeInfo struct {
 	GraphAddNodeOpts      []GraphAddNodeOpt
 	InputType, OutputType reflect.Type // mainly for lambda, whose input and output types cannot be inferred by component type
 	Name                  string
+	Group                 string // passed from WithNodeGroup
 	InputKey, OutputKey   string
 	GraphInfo             *GraphInfo
 	Mappings              []*FieldMapping
+
+	paradigms runnableParadigm
 }
 
 // GraphInfo the info which end users pass in when they are compiling a graph.
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+// MergeStrategy defines how the inputs from multiple predecessors of a node are merged into the node's input,
+// replacing the default fan-in merge which only supports maps and types registered by RegisterValuesMergeFunc.
+// Set it by AddJoinNode or WithMergeStrategy.
+// In streaming mode, each input stream is concatenated first, then merged, and the result is passed on as a single chunk stream.
+type MergeStrategy struct {
+	typ    reflect.Type
+	merge  func(names []string, values []any) (any, error)
+	concat func(sr streamReader) (any, error)
+	pack   func(v any) streamReader
+}
+
+// NewMergeStrategy creates a MergeStrategy from a custom merge function,
+// which receives the inputs keyed by the predecessor node keys.
+func NewMergeStrategy[T any](fn func(values map[string]T) (T, error)) *MergeStrategy {
+	return &MergeStrategy{
+		typ: generic.TypeOf[T](),
+		merge: func(names []string, values []any) (any, error) {
+			m := make(map[string]T, len(values))
+			for i, v := range values {
+				tv, ok := v.(T)
+				if !ok && v != nil {
+					var t T
+					return nil, fmt.Errorf("merge strategy expects type: %T, actual type: %T", t, v)
+				}
+				m[names[i]] = tv
+			}
+			return fn(m)
+		},
+		concat: func(sr streamReader) (any, error) {
+			tsr, ok := unpackStreamReader[T](sr)
+			if !ok {
+				var t T
+				return nil, fmt.Errorf("cannot convert sr to streamReader[%T]", t)
+			}
+			v, err := concatStreamReader(tsr)
+			if err != nil && !errors.Is(err, emptyStreamConcatErr) {
+				return nil, err
+			}
+			return v, nil
+		},
+		pack: func(v any) streamReader {
+			tv, _ := v.(T)
+			return packStreamReader(schema.StreamReaderFromArray([]T{tv}))
+		},
+	}
+}
+
+// NewMapMergeStrategy creates a MergeStrategy merging the input maps into one, which is also the default fan-in behavior for maps.
+// An error is returned if the same key exists in more than one input.
+func NewMapMergeStrategy[K comparable, V any]() *MergeStrategy {
+	return NewMergeStrategy(func(values map[string]map[K]V) (map[K]V, error) {
+		ret := make(map[K]V)
+		for _, name := range sortedKeys(values) {
+			for k, v := range values[name] {
+				if _, ok := ret[k]; ok {
+					return nil, fmt.Errorf("duplicated key[%v] found when merging maps", k)
+				}
+				ret[k] = v
+			}
+		}
+		return ret, nil
+	})
+}
+
+// NewSliceAppendMergeStrategy creates a MergeStrategy appending the input slices into one,
+// ordered by the keys of the predecessor nodes so that the result is deterministic.
+// In streaming mode, the chunks of each input stream are appended as well.
+func NewSliceAppendMergeStrategy[T any]() *MergeStrategy {
+	s := NewMergeStrategy(func(values map[string][]T) ([]T, error) {
+		var ret []T
+		for _, name := range sortedKeys(values) {
+			ret = append(ret, values[name]...)
+		}
+		return ret, nil
+	})
+	s.concat = func(sr streamReader) (any, error) {
+		tsr, ok := unpackStreamReader[[]T](sr)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert sr to streamReader[%T]", []T{})
+		}
+		defer tsr.Close()
+		var ret []T
+		for {
+			chunk, err := tsr.Recv()
+			if err == io.EOF {
+				return ret, nil
+			}
+			if err != nil {
+				if _, ok_ := schema.GetSourceName(err); ok_ {
+					continue
+				}
+				return nil, newStreamReadError(err)
+			}
+			ret = append(ret, chunk...)
+		}
+	}
+	return s
+}
+
+// NewMessagesMergeStrategy creates a MergeStrategy concatenating the input message lists,
+// e.g. to join the histories produced by parallel branches before a ChatModel node.
+func NewMessagesMergeStrategy() *MergeStrategy {
+	return NewSliceAppendMergeStrategy[*schema.Message]()
+}
+
+func (s *MergeStrategy) apply(names []string, values []any, isStream bool) (any, error) {
+	if !isStream {
+		return s.merge(names, values)
+	}
+
+	concatenated := make([]any, len(values))
+	for i, v := range values {
+		sr, ok := v.(streamReader)
+		if !ok {
+			return nil, fmt.Errorf("merge strategy expects stream input, actual type: %T", v)
+		}
+		cv, err := s.concat(sr)
+		if err != nil {
+			return nil, err
+		}
+		concatenated[i] = cv
+	}
+	merged, err := s.merge(names, concatenated)
+	if err != nil {
+		return nil, err
+	}
+	return s.pack(merged), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestJoinNode(t *testing.T) {
+	ctx := context.Background()
+
+	msgs := func(content string) *Lambda {
+		return InvokableLambda(func(ctx context.Context, input string) ([]*schema.Message, error) {
+			return []*schema.Message{schema.UserMessage(input), schema.AssistantMessage(content, nil)}, nil
+		})
+	}
+
+	for _, mode := range []NodeTriggerMode{AnyPredecessor, AllPredecessor} {
+		g := NewGraph[string, []*schema.Message]()
+		assert.NoError(t, g.AddLambdaNode("a", msgs("from a")))
+		assert.NoError(t, g.AddLambdaNode("b", msgs("from b")))
+		assert.NoError(t, g.AddJoinNode("join", NewMessagesMergeStrategy()))
+		assert.NoError(t, g.AddEdge(START, "a"))
+		assert.NoError(t, g.AddEdge(START, "b"))
+		assert.NoError(t, g.AddEdge("a", "join"))
+		assert.NoError(t, g.AddEdge("b", "join"))
+		assert.NoError(t, g.AddEdge("join", END))
+		r, err := g.Compile(ctx, WithNodeTriggerMode(mode))
+		assert.NoError(t, err)
+
+		expected := []*schema.Message{
+			schema.UserMessage("q"), schema.AssistantMessage("from a", nil),
+			schema.UserMessage("q"), schema.AssistantMessage("from b", nil),
+		}
+		out, err := r.Invoke(ctx, "q")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out)
+
+		sr, err := r.Stream(ctx, "q")
+		assert.NoError(t, err)
+		var streamed []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err != nil {
+				break
+			}
+			streamed = append(streamed, chunk...)
+		}
+		assert.Equal(t, expected, streamed)
+	}
+
+	// custom strategy on a regular node
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "a", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "b", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("join", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input, nil
+	}), WithMergeStrategy(NewMergeStrategy(func(values map[string]string) (string, error) {
+		return values["a"] + "|" + values["b"], nil
+	}))))
+	assert.NoError(t, g.AddEdge(START, "a"))
+	assert.NoError(t, g.AddEdge(START, "b"))
+	assert.NoError(t, g.AddEdge("a", "join"))
+	assert.NoError(t, g.AddEdge("b", "join"))
+	assert.NoError(t, g.AddEdge("join", END))
+	r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	assert.NoError(t, err)
+	out, err := r.Invoke(ctx, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, "xa|xb", out)
+
+	// the type of the strategy must match the input type of the node
+	g = NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return strings.ToUpper(input), nil
+	}), WithMergeStrategy(NewMessagesMergeStrategy())))
+	assert.NoError(t, g.AddEdge(START, "a"))
+	assert.NoError(t, g.AddEdge("a", END))
+	_, err = g.Compile(ctx)
+	assert.ErrorContains(t, err, "mismatches its input type")
+}
@@ -0,0 +1,410 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"sort"
+
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// keyRenaming maps the key 'from' of a map[string]any to the key 'to'.
+type keyRenaming struct {
+	from, to string
+}
+
+// WithInputKeys sets multiple input keys of the node.
+// the node consumes these keys from its predecessors' output map[string]any, and its input is assembled from them:
+// a map for map input types, or a struct whose fields are named after the keys for struct input types.
+// for example, if the pre node's output is map[string]any{"a": 1, "b": 2, "c": 3},
+// and the current node's input keys are "a" and "b", then the current node's input value will be map[string]any{"a": 1, "b": 2}.
+// when the predecessors' output keys are known at compile time (set by WithOutputKey or WithRenamedOutputKey), they're verified to contain all the input keys.
+func WithInputKeys(keys ...string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		for _, k := range keys {
+			o.nodeOptions.inputKeys = append(o.nodeOptions.inputKeys, keyRenaming{from: k, to: k})
+		}
+	}
+}
+
+// WithRenamedInputKey consumes the key upstreamKey from the predecessors' output map[string]any as the key inputKey of the node's input.
+// it can be combined with WithInputKeys, for example:
+//
+//	graph.AddLambdaNode("node_name", node, compose.WithInputKeys("query"), compose.WithRenamedInputKey("docs", "Documents"))
+func WithRenamedInputKey(upstreamKey, inputKey string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.inputKeys = append(o.nodeOptions.inputKeys, keyRenaming{from: upstreamKey, to: inputKey})
+	}
+}
+
+// WithRenamedOutputKey emits the key outputKey of the node's output map[string]any under the key emittedKey.
+// the other keys of the output are emitted as is, and the node fails if emittedKey collides with one of them.
+// the downstream nodes consuming outputKey by input keys are rejected at compile time.
+// the output type of the node must be map[string]any.
+func WithRenamedOutputKey(outputKey, emittedKey string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.outputKeyRenames = append(o.nodeOptions.outputKeyRenames, keyRenaming{from: outputKey, to: emittedKey})
+	}
+}
+
+func validateNodeKeys(key string, node *graphNode, opts *nodeOptions) error {
+	if len(opts.inputKeys) > 0 && opts.inputKey != "" {
+		return fmt.Errorf("node '%s' cannot set both input key and input keys", key)
+	}
+
+	if err := validateKeyRenamings(opts.inputKeys); err != nil {
+		return fmt.Errorf("node '%s' has invalid input keys: %w", key, err)
+	}
+	if err := validateKeyRenamings(opts.outputKeyRenames); err != nil {
+		return fmt.Errorf("node '%s' has invalid renamed output keys: %w", key, err)
+	}
+
+	inputType, outputType := node.componentTypes()
+	if len(opts.inputKeys) > 0 && inputType != nil {
+		if _, err := inputKeysFieldTypes(opts.inputKeys, inputType); err != nil {
+			return fmt.Errorf("node '%s' has invalid input keys: %w", key, err)
+		}
+	}
+	if len(opts.outputKeyRenames) > 0 && outputType != nil && outputType != generic.TypeOf[map[string]any]() {
+		return fmt.Errorf("node '%s' with renamed output keys should output map[string]any, actual: %v", key, outputType)
+	}
+
+	return nil
+}
+
+// componentTypes returns the input and output types of the node itself, regardless of its input and output keys.
+func (gn *graphNode) componentTypes() (inputType, outputType reflect.Type) {
+	if gn.g != nil {
+		return gn.g.inputType(), gn.g.outputType()
+	} else if gn.cr != nil {
+		return gn.cr.inputType, gn.cr.outputType
+	}
+	return nil, nil
+}
+
+func validateKeyRenamings(renamings []keyRenaming) error {
+	from := make(map[string]bool, len(renamings))
+	to := make(map[string]bool, len(renamings))
+	for _, r := range renamings {
+		if r.from == "" || r.to == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+		if from[r.from] {
+			return fmt.Errorf("duplicate key: %s", r.from)
+		}
+		if to[r.to] {
+			return fmt.Errorf("duplicate renamed key: %s", r.to)
+		}
+		from[r.from], to[r.to] = true, true
+	}
+
+	return nil
+}
+
+// mayProduce reports whether the map[string]any output of the node may contain the key, as far as known at compile time:
+// the output of a node with WithOutputKey contains the output key only,
+// and that of a node with WithRenamedOutputKey doesn't contain the keys renamed away, unless another key is renamed to them.
+func (g *graph) mayProduce(node, key string) bool {
+	n, ok := g.nodes[node]
+	if !ok || n.nodeInfo == nil {
+		return true
+	}
+	if n.nodeInfo.outputKey != "" {
+		return n.nodeInfo.outputKey == key
+	}
+
+	renamedAway := false
+	for _, rn := range n.nodeInfo.outputKeyRenames {
+		if rn.to == key {
+			return true
+		}
+		if rn.from == key {
+			renamedAway = true
+		}
+	}
+	return !renamedAway
+}
+
+// validateInputKeys verifies that the predecessors of the nodes with input keys may produce those keys, see mayProduce.
+func (g *graph) validateInputKeys(dataPredecessors map[string][]string) error {
+	for key, node := range g.nodes {
+		if len(node.nodeInfo.inputKeys) == 0 || len(dataPredecessors[key]) == 0 {
+			continue
+		}
+
+		for _, r := range node.nodeInfo.inputKeys {
+			produced := false
+			for _, pre := range dataPredecessors[key] {
+				if g.mayProduce(pre, r.from) {
+					produced = true
+					break
+				}
+			}
+			if !produced {
+				predecessors := append([]string(nil), dataPredecessors[key]...)
+				sort.Strings(predecessors)
+				return fmt.Errorf("node[%s] consumes input key[%s], but none of its predecessors%v produces it", key, r.from, predecessors)
+			}
+		}
+	}
+
+	return nil
+}
+
+func inputKeysComposableRunnable(keys []keyRenaming, r *composableRunnable) (*composableRunnable, error) {
+	inputType := r.inputType
+	if inputType == nil { // passthrough
+		inputType = generic.TypeOf[map[string]any]()
+	}
+
+	fieldTypes, err := inputKeysFieldTypes(keys, inputType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input keys: %w", err)
+	}
+
+	convert := r.inputFieldMappingConverter
+	if r.inputType == nil {
+		convert = handlerPair{
+			invoke:    func(v any) (any, error) { return v, nil },
+			transform: func(input streamReader) streamReader { return input },
+		}
+	}
+
+	// chunks of a struct can't be concatenated, so the chunks of the keys are concatenated before being assembled into the struct
+	concatChunks := inputType.Kind() == reflect.Struct || inputType.Kind() == reflect.Ptr && inputType.Elem().Kind() == reflect.Struct
+
+	wrapper := *r
+	wrapper.genericHelper = wrapper.genericHelper.forMapInput()
+
+	i := r.i
+	wrapper.i = func(ctx context.Context, input any, opts ...any) (output any, err error) {
+		in := input.(map[string]any)
+		assembled := make(map[string]any, len(keys))
+		for _, k := range keys {
+			v, ok := in[k.from]
+			if !ok {
+				return nil, fmt.Errorf("cannot find input key: %s", k.from)
+			}
+			if err = checkInputKeyValue(k, v, fieldTypes[k.to]); err != nil {
+				return nil, err
+			}
+			assembled[k.to] = v
+		}
+
+		converted, err := convert.invoke(assembled)
+		if err != nil {
+			return nil, err
+		}
+		return i(ctx, converted, opts...)
+	}
+
+	t := r.t
+	wrapper.t = func(ctx context.Context, input streamReader, opts ...any) (output streamReader, err error) {
+		s, ok := unpackStreamReader[map[string]any](input)
+		if !ok {
+			return nil, fmt.Errorf("input keys of node[%s] expect stream of map[string]any, got: %v", r.nodeInfo.name, input.getChunkType())
+		}
+
+		assembled := schema.StreamReaderWithConvert(s, func(in map[string]any) (map[string]any, error) {
+			chunk := make(map[string]any, len(keys))
+			for _, k := range keys {
+				if v, ok := in[k.from]; ok {
+					if err := checkInputKeyValue(k, v, fieldTypes[k.to]); err != nil {
+						return nil, err
+					}
+					chunk[k.to] = v
+				}
+			}
+			if len(chunk) == 0 {
+				return nil, schema.ErrNoValue
+			}
+			return chunk, nil
+		})
+
+		if !concatChunks {
+			assembled = requireStreamKeys(assembled, keys)
+		} else {
+			merged, err := concatStreamReader(assembled)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				if _, ok := merged[k.to]; !ok {
+					return nil, fmt.Errorf("cannot find input key: %s", k.from)
+				}
+			}
+			assembled = schema.StreamReaderFromArray([]map[string]any{merged})
+		}
+
+		return t(ctx, convert.transform(packStreamReader(assembled)), opts...)
+	}
+
+	wrapper.inputType = generic.TypeOf[map[string]any]()
+	return &wrapper, nil
+}
+
+// requireStreamKeys passes the assembled stream through, failing it at the end if any of the keys has never arrived.
+func requireStreamKeys(s *schema.StreamReader[map[string]any], keys []keyRenaming) *schema.StreamReader[map[string]any] {
+	out, sw := schema.Pipe[map[string]any](0)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				sw.Send(nil, safe.NewPanicErr(p, debug.Stack()))
+			}
+			s.Close()
+			sw.Close()
+		}()
+
+		arrived := make(map[string]bool, len(keys))
+		for {
+			chunk, err := s.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			for k := range chunk {
+				arrived[k] = true
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+
+		for _, k := range keys {
+			if !arrived[k.to] {
+				sw.Send(nil, fmt.Errorf("cannot find input key: %s", k.from))
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// inputKeysFieldTypes resolves the types of the fields assembled from the input keys, nil for fields of any type.
+func inputKeysFieldTypes(keys []keyRenaming, inputType reflect.Type) (map[string]reflect.Type, error) {
+	if !validateStructOrMap(inputType) && inputType != generic.TypeOf[any]() {
+		return nil, fmt.Errorf("input type should be struct, map or any, actual: %v", inputType)
+	}
+
+	fieldTypes := make(map[string]reflect.Type, len(keys))
+	for _, k := range keys {
+		ft, remaining, err := checkAndExtractFieldType([]string{k.to}, inputType)
+		if err != nil {
+			return nil, err
+		}
+		if len(remaining) > 0 || ft == generic.TypeOf[any]() {
+			continue
+		}
+		fieldTypes[k.to] = ft
+	}
+
+	return fieldTypes, nil
+}
+
+func checkInputKeyValue(k keyRenaming, v any, fieldType reflect.Type) error {
+	if fieldType == nil {
+		return nil
+	}
+
+	vt := reflect.TypeOf(v)
+	if vt == nil {
+		switch fieldType.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+			return nil
+		}
+	} else if vt.AssignableTo(fieldType) {
+		return nil
+	}
+
+	return fmt.Errorf("value of input key[%s] is of type[%v], which is not assignable to [%v]", k.from, vt, fieldType)
+}
+
+func renamedOutputKeysComposableRunnable(renames []keyRenaming, r *composableRunnable) (*composableRunnable, error) {
+	if r.outputType != nil && r.outputType != generic.TypeOf[map[string]any]() {
+		return nil, fmt.Errorf("renamed output keys need output of map[string]any, actual: %v", r.outputType)
+	}
+
+	renameTo := make(map[string]string, len(renames))
+	for _, rn := range renames {
+		renameTo[rn.from] = rn.to
+	}
+	// sources records the output key each emitted key comes from, across the chunks of a stream,
+	// so that a key renamed to another key of the output fails the node, instead of one of them winning by the map order.
+	rename := func(out map[string]any, sources map[string]string) (map[string]any, error) {
+		renamed := make(map[string]any, len(out))
+		for k, v := range out {
+			to := k
+			if rn, ok := renameTo[k]; ok {
+				to = rn
+			}
+			if from, ok := sources[to]; ok && from != k {
+				if from > k {
+					from, k = k, from
+				}
+				return nil, fmt.Errorf("renamed output key[%s] collides, emitted from both key[%s] and key[%s]", to, from, k)
+			}
+			sources[to] = k
+			renamed[to] = v
+		}
+		return renamed, nil
+	}
+
+	wrapper := *r
+
+	i := r.i
+	wrapper.i = func(ctx context.Context, input any, opts ...any) (output any, err error) {
+		out, err := i(ctx, input, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := out.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("node[%s] with renamed output keys should output map[string]any, actual: %T", r.nodeInfo.name, out)
+		}
+		return rename(m, make(map[string]string, len(m)))
+	}
+
+	t := r.t
+	wrapper.t = func(ctx context.Context, input streamReader, opts ...any) (output streamReader, err error) {
+		out, err := t(ctx, input, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := unpackStreamReader[map[string]any](out)
+		if !ok {
+			return nil, fmt.Errorf("node[%s] with renamed output keys should output stream of map[string]any, actual: %v", r.nodeInfo.name, out.getChunkType())
+		}
+		sources := make(map[string]string)
+		return packStreamReader(schema.StreamReaderWithConvert(s, func(m map[string]any) (map[string]any, error) {
+			return rename(m, sources)
+		})), nil
+	}
+
+	return &wrapper, nil
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type nodeKeysInput struct {
+	Query string
+	Count int
+}
+
+func TestInputKeys(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[string, map[string]any]()
+	require.NoError(t, g.AddLambdaNode("query", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in + "?", nil
+	}), WithOutputKey("query")))
+	require.NoError(t, g.AddLambdaNode("count", InvokableLambda(func(ctx context.Context, in string) (int, error) {
+		return len(in), nil
+	}), WithOutputKey("n")))
+	require.NoError(t, g.AddLambdaNode("answer", InvokableLambda(func(ctx context.Context, in *nodeKeysInput) (map[string]any, error) {
+		return map[string]any{"result": fmt.Sprintf("%s%d", in.Query, in.Count), "extra": true}, nil
+	}), WithRenamedInputKey("query", "Query"), WithRenamedInputKey("n", "Count"), WithRenamedOutputKey("result", "answer")))
+	require.NoError(t, g.AddEdge(START, "query"))
+	require.NoError(t, g.AddEdge(START, "count"))
+	require.NoError(t, g.AddEdge("query", "answer"))
+	require.NoError(t, g.AddEdge("count", "answer"))
+	require.NoError(t, g.AddEdge("answer", END))
+
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"answer": "abc?3", "extra": true}, out)
+
+	sr, err := r.Stream(ctx, "abc")
+	require.NoError(t, err)
+	defer sr.Close()
+	merged := map[string]any{}
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		for k, v := range chunk {
+			merged[k] = v
+		}
+	}
+	assert.Equal(t, map[string]any{"answer": "abc?3", "extra": true}, merged)
+}
+
+func TestInputKeysMapInput(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[map[string]any, map[string]any]()
+	require.NoError(t, g.AddLambdaNode("node", InvokableLambda(func(ctx context.Context, in map[string]any) (map[string]any, error) {
+		return in, nil
+	}), WithInputKeys("a"), WithRenamedInputKey("b", "c")))
+	require.NoError(t, g.AddEdge(START, "node"))
+	require.NoError(t, g.AddEdge("node", END))
+
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, map[string]any{"a": 1, "b": 2, "d": 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": 1, "c": 2}, out)
+
+	_, err = r.Invoke(ctx, map[string]any{"a": 1})
+	assert.ErrorContains(t, err, "cannot find input key: b")
+
+	// the stream fails at the end if a key never arrives
+	sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]map[string]any{{"a": 1}, {"d": 3}}))
+	if err == nil {
+		_, err = concatStreamReader(sr)
+	}
+	assert.ErrorContains(t, err, "cannot find input key: b")
+}
+
+func TestRenamedOutputKeyCollision(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[map[string]any, map[string]any]()
+	require.NoError(t, g.AddLambdaNode("node", InvokableLambda(func(ctx context.Context, in map[string]any) (map[string]any, error) {
+		return in, nil
+	}), WithRenamedOutputKey("a", "b")))
+	require.NoError(t, g.AddEdge(START, "node"))
+	require.NoError(t, g.AddEdge("node", END))
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, map[string]any{"a": 1, "c": 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"b": 1, "c": 3}, out)
+
+	_, err = r.Invoke(ctx, map[string]any{"a": 1, "b": 2})
+	assert.ErrorContains(t, err, "renamed output key[b] collides, emitted from both key[a] and key[b]")
+
+	// the collision is found across the chunks of a stream as well
+	sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]map[string]any{{"a": 1}, {"b": 2}}))
+	require.NoError(t, err)
+	_, err = concatStreamReader(sr)
+	assert.ErrorContains(t, err, "renamed output key[b] collides")
+}
+
+func TestInputKeysValidation(t *testing.T) {
+	ctx := context.Background()
+	lambda := InvokableLambda(func(ctx context.Context, in *nodeKeysInput) (string, error) {
+		return in.Query, nil
+	})
+
+	t.Run("missing upstream key", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("pre", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		}), WithOutputKey("query")))
+		require.NoError(t, g.AddLambdaNode("node", lambda, WithRenamedInputKey("query", "Query"), WithInputKeys("Count")))
+		require.NoError(t, g.AddEdge(START, "pre"))
+		require.NoError(t, g.AddEdge("pre", "node"))
+		require.NoError(t, g.AddEdge("node", END))
+
+		_, err := g.Compile(ctx)
+		assert.ErrorContains(t, err, "node[node] consumes input key[Count], but none of its predecessors[pre] produces it")
+	})
+
+	t.Run("key renamed away", func(t *testing.T) {
+		g := NewGraph[map[string]any, string]()
+		require.NoError(t, g.AddLambdaNode("pre", InvokableLambda(func(ctx context.Context, in map[string]any) (map[string]any, error) {
+			return in, nil
+		}), WithRenamedOutputKey("query", "q")))
+		require.NoError(t, g.AddLambdaNode("node", lambda, WithRenamedInputKey("query", "Query")))
+		require.NoError(t, g.AddEdge(START, "pre"))
+		require.NoError(t, g.AddEdge("pre", "node"))
+		require.NoError(t, g.AddEdge("node", END))
+
+		_, err := g.Compile(ctx)
+		assert.ErrorContains(t, err, "node[node] consumes input key[query], but none of its predecessors[pre] produces it")
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		g := NewGraph[map[string]any, string]()
+		err := g.AddLambdaNode("node", lambda, WithInputKeys("Missing"))
+		assert.ErrorContains(t, err, "has no field[Missing]")
+	})
+
+	t.Run("both input key and input keys", func(t *testing.T) {
+		g := NewGraph[map[string]any, string]()
+		err := g.AddLambdaNode("node", lambda, WithInputKey("a"), WithInputKeys("Query"))
+		assert.ErrorContains(t, err, "cannot set both input key and input keys")
+	})
+
+	t.Run("duplicate renamed key", func(t *testing.T) {
+		g := NewGraph[map[string]any, string]()
+		err := g.AddLambdaNode("node", lambda, WithInputKeys("Query"), WithRenamedInputKey("q", "Query"))
+		assert.ErrorContains(t, err, "duplicate renamed key: Query")
+	})
+
+	t.Run("renamed output keys of non-map output", func(t *testing.T) {
+		g := NewGraph[map[string]any, string]()
+		err := g.AddLambdaNode("node", lambda, WithInputKeys("Query"), WithRenamedOutputKey("a", "b"))
+		assert.ErrorContains(t, err, "should output map[string]any")
+	})
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+)
+
+// OutputSample is a copy of a node's output collected by WithOutputSampling.
+type OutputSample struct {
+	// NodeKey is the key of the node in the graph it belongs to.
+	NodeKey string
+	// NodeName is the display name of the node, passed from WithNodeName.
+	NodeName string
+	// Address is the full address of the node, including the parent graphs.
+	Address Address
+	// RunID is the ID of the graph run the node belongs to, passed from WithRunID.
+	RunID string
+	// RunInfo is the run info of the node, the same as the one passed to its callbacks,
+	// including the run metadata passed from WithRunMetadata.
+	RunInfo *callbacks.RunInfo
+	// Output is the output of the node, after the state post handler if any.
+	// For stream outputs, the chunks are concatenated into a single value.
+	Output any
+	// Time is the timestamp when the node's output is available.
+	Time time.Time
+}
+
+// OutputSampleSink receives the sampled node outputs.
+// Collect is called asynchronously in a separate goroutine, and should be safe for concurrent use.
+type OutputSampleSink interface {
+	Collect(ctx context.Context, sample *OutputSample)
+}
+
+type outputSampling struct {
+	rate float64
+	sink OutputSampleSink
+}
+
+func (o *outputSampling) hit() bool {
+	return o.rate >= 1 || rand.Float64() < o.rate
+}
+
+// sampleOutput sends a copy of the task's output to the sink if the task is sampled.
+// If the output is a stream, it will be copied and concatenated in a separate goroutine,
+// so that the downstream nodes are not blocked.
+func sampleOutput(ta *task) {
+	if ta.call == nil || ta.call.action == nil || ta.call.action.nodeInfo == nil {
+		return
+	}
+	s := ta.call.action.nodeInfo.outputSampling
+	if s == nil || s.sink == nil || !s.hit() {
+		return
+	}
+
+	info := ta.call.action.nodeInfo
+	ri := &callbacks.RunInfo{
+		Name:  info.name,
+		Group: info.group,
+	}
+	if meta := ta.call.action.meta; meta != nil {
+		ri.Component = meta.component
+		ri.Type = meta.componentImplType
+	}
+	setRunMeta(ta.ctx, ri)
+
+	sample := &OutputSample{
+		NodeKey:  ta.nodeKey,
+		NodeName: info.name,
+		Address:  GetCurrentAddress(ta.ctx),
+		RunID:    ri.RunID,
+		RunInfo:  ri,
+		Time:     time.Now(),
+	}
+
+	var sr streamReader
+	if isr, ok := ta.output.(streamReader); ok {
+		srs := isr.copy(2)
+		ta.output, sr = srs[0], srs[1]
+	} else {
+		sample.Output = ta.output
+	}
+
+	concat := ta.call.action.outputStreamConvertPair.concatStream
+	go func() {
+		defer func() {
+			_ = recover() // a misbehaving sink must not affect the graph run
+		}()
+
+		if sr != nil {
+			out, err := concat(sr)
+			if err != nil {
+				return
+			}
+			sample.Output = out
+		}
+
+		s.sink.Collect(ta.ctx, sample)
+	}()
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type chanSampleSink chan *OutputSample
+
+func (c chanSampleSink) Collect(_ context.Context, sample *OutputSample) {
+	c <- sample
+}
+
+func TestOutputSampling(t *testing.T) {
+	ctx := context.Background()
+	sink := make(chanSampleSink, 10)
+	never := make(chanSampleSink, 10)
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{input, "_1"}), nil
+	}), WithNodeName("node 1"), WithNodeGroup("g"), WithOutputSampling(1, sink)))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "_2", nil
+	}), WithOutputSampling(0, never)))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "in", WithRunID("run-1"), WithRunMetadata(map[string]string{"tenant": "t1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "in_1_2", out)
+	sample := <-sink
+	assert.Equal(t, "1", sample.NodeKey)
+	assert.Equal(t, "node 1", sample.NodeName)
+	assert.Equal(t, "in_1", sample.Output)
+	assert.Equal(t, "1", sample.Address[len(sample.Address)-1].ID)
+	assert.Equal(t, "run-1", sample.RunID)
+	assert.Equal(t, "node 1", sample.RunInfo.Name)
+	assert.Equal(t, "g", sample.RunInfo.Group)
+	assert.Equal(t, ComponentOfLambda, sample.RunInfo.Component)
+	assert.Equal(t, map[string]string{"tenant": "t1"}, sample.RunInfo.Metadata)
+	assert.False(t, sample.Time.IsZero())
+
+	sr, err := r.Stream(ctx, "in")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "in_1_2", out)
+	sample = <-sink
+	assert.Equal(t, "in_1", sample.Output)
+
+	assert.Len(t, never, 0)
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/internal/serialization"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*pipelineRecord]("_eino_pipeline_record")
+}
+
+// PipelineStage is a single stage of a Pipeline, created by NewPipelineStage.
+type PipelineStage struct {
+	name   string
+	invoke func(ctx context.Context, input any) (any, error)
+}
+
+// NewPipelineStage creates a pipeline stage from a compiled Runnable.
+// The input type of a stage must be the output type of the previous stage.
+// opts are passed to every Invoke of the runnable.
+func NewPipelineStage[I, O any](name string, r Runnable[I, O], opts ...Option) *PipelineStage {
+	return &PipelineStage{
+		name: name,
+		invoke: func(ctx context.Context, input any) (any, error) {
+			in, ok := input.(I)
+			if !ok && input != nil {
+				return nil, fmt.Errorf("pipeline stage[%s] expects input type %T, got %T", name, *new(I), input)
+			}
+			return r.Invoke(ctx, in, opts...)
+		},
+	}
+}
+
+// PipelineConfig is the config of a Pipeline.
+type PipelineConfig struct {
+	// Store persists the progress of every run, required.
+	// Stage inputs and outputs are persisted, so custom types must be registered by schema.RegisterName.
+	Store CheckPointStore
+	// Serializer is optional, the internal serializer is used by default.
+	Serializer Serializer
+}
+
+// Pipeline executes a sequence of separately compiled Runnables, the output of each stage being the input of the next.
+// Unlike a Chain, stages are independent runs which may happen hours apart:
+// the output of every stage is persisted in the CheckPointStore, so a run can be resumed
+// from the first unfinished stage after a failure or a restart, e.g. crawl -> index -> evaluate.
+type Pipeline struct {
+	stages     []*PipelineStage
+	store      CheckPointStore
+	serializer Serializer
+}
+
+// PipelineStatus describes the progress of a pipeline run.
+type PipelineStatus struct {
+	RunID string
+	// CompletedStages are the names of the stages that have finished, in order.
+	CompletedStages []string
+	// NextStage is the name of the stage to be executed next, empty if Done.
+	NextStage string
+	Done      bool
+	// LastError is the error message of the last failed stage execution, empty if the last execution succeeded.
+	LastError string
+}
+
+type pipelineRecord struct {
+	Input     any
+	Outputs   []any
+	LastError string
+}
+
+// NewPipeline creates a Pipeline with the given stages, whose names must be unique.
+func NewPipeline(conf *PipelineConfig, stages ...*PipelineStage) (*Pipeline, error) {
+	if conf == nil || conf.Store == nil {
+		return nil, errors.New("pipeline requires a checkpoint store")
+	}
+	if len(stages) == 0 {
+		return nil, errors.New("pipeline requires at least one stage")
+	}
+	names := make(map[string]bool, len(stages))
+	for _, s := range stages {
+		if s == nil {
+			return nil, errors.New("pipeline stage is nil")
+		}
+		if names[s.name] {
+			return nil, fmt.Errorf("duplicated pipeline stage name: %s", s.name)
+		}
+		names[s.name] = true
+	}
+
+	serializer := conf.Serializer
+	if serializer == nil {
+		serializer = &serialization.InternalSerializer{}
+	}
+
+	return &Pipeline{
+		stages:     stages,
+		store:      conf.Store,
+		serializer: serializer,
+	}, nil
+}
+
+// Run executes all the remaining stages of the run identified by runID and returns the output of the last stage.
+// For a new run, input is the input of the first stage; for an existing run, input is ignored and
+// the run resumes from the first unfinished stage. Calling Run on a finished run returns its persisted output.
+func (p *Pipeline) Run(ctx context.Context, runID string, input any) (any, error) {
+	for {
+		status, output, err := p.runNext(ctx, runID, input)
+		if err != nil {
+			return nil, err
+		}
+		if status.Done {
+			return output, nil
+		}
+	}
+}
+
+// RunNext executes only the next unfinished stage of the run identified by runID, and returns the resulting status.
+// It is useful when stages are scheduled separately. input is only used by a new run, like Run.
+func (p *Pipeline) RunNext(ctx context.Context, runID string, input any) (*PipelineStatus, error) {
+	status, _, err := p.runNext(ctx, runID, input)
+	return status, err
+}
+
+// Status returns the progress of the run identified by runID. An error is returned if the run does not exist.
+func (p *Pipeline) Status(ctx context.Context, runID string) (*PipelineStatus, error) {
+	rec, existed, err := p.load(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, fmt.Errorf("pipeline run[%s] not found", runID)
+	}
+	return p.status(runID, rec), nil
+}
+
+// Output returns the persisted output of the given stage of the run identified by runID.
+func (p *Pipeline) Output(ctx context.Context, runID, stage string) (any, error) {
+	rec, existed, err := p.load(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, fmt.Errorf("pipeline run[%s] not found", runID)
+	}
+	for i, s := range p.stages {
+		if s.name != stage {
+			continue
+		}
+		if i >= len(rec.Outputs) {
+			return nil, fmt.Errorf("pipeline stage[%s] of run[%s] has not completed", stage, runID)
+		}
+		return rec.Outputs[i], nil
+	}
+	return nil, fmt.Errorf("unknown pipeline stage: %s", stage)
+}
+
+func (p *Pipeline) runNext(ctx context.Context, runID string, input any) (*PipelineStatus, any, error) {
+	rec, existed, err := p.load(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !existed {
+		rec = &pipelineRecord{Input: input}
+	}
+
+	idx := len(rec.Outputs)
+	if idx >= len(p.stages) {
+		return p.status(runID, rec), rec.Outputs[len(rec.Outputs)-1], nil
+	}
+
+	stageInput := rec.Input
+	if idx > 0 {
+		stageInput = rec.Outputs[idx-1]
+	}
+
+	output, runErr := p.stages[idx].invoke(ctx, stageInput)
+	if runErr != nil {
+		rec.LastError = runErr.Error()
+	} else {
+		rec.Outputs = append(rec.Outputs, output)
+		rec.LastError = ""
+	}
+	if err = p.save(ctx, runID, rec); err != nil {
+		return nil, nil, err
+	}
+	if runErr != nil {
+		return p.status(runID, rec), nil, fmt.Errorf("pipeline stage[%s] of run[%s] failed: %w", p.stages[idx].name, runID, runErr)
+	}
+	return p.status(runID, rec), output, nil
+}
+
+func (p *Pipeline) status(runID string, rec *pipelineRecord) *PipelineStatus {
+	s := &PipelineStatus{
+		RunID:     runID,
+		LastError: rec.LastError,
+	}
+	for i := range rec.Outputs {
+		s.CompletedStages = append(s.CompletedStages, p.stages[i].name)
+	}
+	if len(rec.Outputs) >= len(p.stages) {
+		s.Done = true
+	} else {
+		s.NextStage = p.stages[len(rec.Outputs)].name
+	}
+	return s
+}
+
+func pipelineRecordID(runID string) string {
+	return "_eino_pipeline_" + runID
+}
+
+func (p *Pipeline) load(ctx context.Context, runID string) (*pipelineRecord, bool, error) {
+	data, existed, err := p.store.Get(ctx, pipelineRecordID(runID))
+	if err != nil {
+		return nil, false, fmt.Errorf("get pipeline run[%s] from store fail: %w", runID, err)
+	}
+	if !existed {
+		return nil, false, nil
+	}
+	rec := &pipelineRecord{}
+	if err = p.serializer.Unmarshal(data, rec); err != nil {
+		return nil, false, fmt.Errorf("unmarshal pipeline run[%s] fail: %w", runID, err)
+	}
+	return rec, true, nil
+}
+
+func (p *Pipeline) save(ctx context.Context, runID string, rec *pipelineRecord) error {
+	data, err := p.serializer.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal pipeline run[%s] fail: %w", runID, err)
+	}
+	if err = p.store.Set(ctx, pipelineRecordID(runID), data); err != nil {
+		return fmt.Errorf("set pipeline run[%s] to store fail: %w", runID, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+
+	crawl, err := NewChain[string, []string]().
+		AppendLambda(InvokableLambda(func(ctx context.Context, input string) ([]string, error) {
+			return strings.Split(input, ","), nil
+		})).Compile(ctx)
+	assert.NoError(t, err)
+
+	indexFail := true
+	index, err := NewChain[[]string, int]().
+		AppendLambda(InvokableLambda(func(ctx context.Context, input []string) (int, error) {
+			if indexFail {
+				return 0, errors.New("index unavailable")
+			}
+			return len(input), nil
+		})).Compile(ctx)
+	assert.NoError(t, err)
+
+	evaluate, err := NewChain[int, string]().
+		AppendLambda(InvokableLambda(func(ctx context.Context, input int) (string, error) {
+			if input > 2 {
+				return "good", nil
+			}
+			return "bad", nil
+		})).Compile(ctx)
+	assert.NoError(t, err)
+
+	stages := []*PipelineStage{
+		NewPipelineStage("crawl", crawl),
+		NewPipelineStage("index", index),
+		NewPipelineStage("evaluate", evaluate),
+	}
+
+	_, err = NewPipeline(&PipelineConfig{})
+	assert.Error(t, err)
+	_, err = NewPipeline(&PipelineConfig{Store: newInMemoryStore()}, stages[0], stages[0])
+	assert.ErrorContains(t, err, "duplicated pipeline stage name")
+
+	store := newInMemoryStore()
+	p, err := NewPipeline(&PipelineConfig{Store: store}, stages...)
+	assert.NoError(t, err)
+
+	_, err = p.Status(ctx, "run1")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = p.Run(ctx, "run1", "a,b,c")
+	assert.ErrorContains(t, err, "index unavailable")
+
+	status, err := p.Status(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"crawl"}, status.CompletedStages)
+	assert.Equal(t, "index", status.NextStage)
+	assert.False(t, status.Done)
+	assert.Contains(t, status.LastError, "index unavailable")
+
+	crawled, err := p.Output(ctx, "run1", "crawl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, crawled)
+
+	// a new pipeline instance sharing the store resumes from the failed stage, ignoring the input
+	indexFail = false
+	p2, err := NewPipeline(&PipelineConfig{Store: store}, stages...)
+	assert.NoError(t, err)
+
+	status, err = p2.RunNext(ctx, "run1", "ignored")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"crawl", "index"}, status.CompletedStages)
+	assert.Equal(t, "evaluate", status.NextStage)
+	assert.Empty(t, status.LastError)
+
+	out, err := p2.Run(ctx, "run1", "ignored")
+	assert.NoError(t, err)
+	assert.Equal(t, "good", out)
+
+	status, err = p2.Status(ctx, "run1")
+	assert.NoError(t, err)
+	assert.True(t, status.Done)
+
+	// a finished run returns the persisted output
+	out, err = p2.Run(ctx, "run1", "ignored")
+	assert.NoError(t, err)
+	assert.Equal(t, "good", out)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/einoerr"
+)
+
+// WithPrefetch hints the graph to start the given nodes as soon as the run starts, with the input of the graph,
+// concurrently with the branches of START deciding whether to run them,
+// e.g. a retriever whose query is the raw user input, behind a branch classifying the input with a chat model.
+// If a prefetched node is selected, the result of the prefetch is used as its output,
+// otherwise the prefetch is canceled and its result is discarded.
+// The prefetched nodes must receive data from START only, and must not have state handlers,
+// whose writes to the state can't be undone if the prefetch is discarded,
+// nor be interrupted before by WithInterruptBeforeNodes, which would run the node before the interrupt, and again on resume.
+// For the same reason, the nodes with side effects, e.g. writing to the state by ProcessState or calling external services
+// which change anything, must not be prefetched, as the prefetch may run them even if they are not selected.
+// It's only allowed in Pregel mode, i.e. the AnyPredecessor trigger mode, and takes effect when the run starts
+// from scratch, not when resuming from a checkpoint.
+// Note that the callbacks of a discarded prefetch are triggered as well, usually ending with a context canceled error.
+func WithPrefetch(nodeKeys ...string) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.prefetchNodes = append(o.prefetchNodes, nodeKeys...)
+	}
+}
+
+func (g *graph) validatePrefetchNodes(nodeKeys, interruptBeforeNodes []string, dataPredecessors map[string][]string, dag bool) error {
+	if len(nodeKeys) == 0 {
+		return nil
+	}
+	if dag {
+		return fmt.Errorf("prefetch is only supported in pregel mode")
+	}
+	for _, key := range nodeKeys {
+		node, ok := g.nodes[key]
+		if !ok {
+			return &einoerr.NodeNotFoundError{NodeKey: key, Context: "prefetch node"}
+		}
+		if len(dataPredecessors[key]) == 0 {
+			return fmt.Errorf("cannot prefetch node[%s], which doesn't receive data from START", key)
+		}
+		for _, pre := range dataPredecessors[key] {
+			if pre != START {
+				return fmt.Errorf("cannot prefetch node[%s], which receives data from node[%s] other than START", key, pre)
+			}
+		}
+		if node.nodeInfo != nil && (node.nodeInfo.preProcessor != nil || node.nodeInfo.postProcessor != nil) {
+			return fmt.Errorf("cannot prefetch node[%s], which has state handlers", key)
+		}
+		for _, ib := range interruptBeforeNodes {
+			if ib == key {
+				return fmt.Errorf("cannot prefetch node[%s], which is interrupted before", key)
+			}
+		}
+	}
+	return nil
+}
+
+// prefetchTask is a node started ahead of being selected.
+type prefetchTask struct {
+	ta     *task
+	cancel context.CancelFunc
+	done   chan struct{}
+	taken  bool
+}
+
+// wait waits for the prefetch to finish and takes over its result.
+// The context of the prefetch is canceled once the result is consumed, i.e. at once, or when the output stream ends.
+func (p *prefetchTask) wait(ta *task) {
+	<-p.done
+	ta.output, ta.err, ta.mutations = p.ta.output, p.ta.err, p.ta.mutations
+	if sr, ok := ta.output.(streamReader); ok && ta.err == nil {
+		ta.output = sr.withDoneHook(func(error) { p.cancel() })
+		return
+	}
+	p.cancel()
+}
+
+func (p *prefetchTask) discard() {
+	p.taken = true
+	p.cancel()
+	go func() {
+		<-p.done
+		if sr, ok := p.ta.output.(streamReader); ok {
+			sr.close()
+		}
+	}()
+}
+
+type prefetches map[string]*prefetchTask
+
+// startPrefetches starts the prefetched nodes with copies of the graph input, and returns the input left for START.
+func (r *runner) startPrefetches(ctx context.Context, input any, isStream bool, tm *taskManager, optMap map[string][]any) (any, prefetches) {
+	keys := r.options.prefetchNodes
+	if len(keys) == 0 {
+		return input, nil
+	}
+
+	copies := copyItem(input, len(keys)+1)
+	ret := make(prefetches, len(keys))
+	for i, key := range keys {
+		// resolve the input the same way as the channel of the node does
+		ch := &pregelChannel{Values: map[string]any{START: copies[i+1]}}
+		ch.setMergeConfig(r.mergeConfigs[key])
+		in, _, err := ch.get(isStream, key, r.edgeHandlerManager)
+		if err == nil {
+			in, err = r.preNodeHandlerManager.handle(key, in, isStream)
+		}
+		if err != nil {
+			// leave the error to the normal run of the node
+			if sr, ok := copies[i+1].(streamReader); ok {
+				sr.close()
+			}
+			continue
+		}
+
+		pctx, cancel := context.WithCancel(ctx)
+		tasks, err := r.createTasks(pctx, map[string]any{key: in}, optMap)
+		if err != nil {
+			cancel()
+			continue
+		}
+		p := &prefetchTask{ta: tasks[0], cancel: cancel, done: make(chan struct{})}
+		go func() {
+			defer close(p.done)
+			tm.run(p.ta)
+		}()
+		ret[key] = p
+	}
+	return copies[0], ret
+}
+
+// bind hands the prefetches over to the selected tasks, and discards the others.
+func (ps prefetches) bind(tasks []*task) {
+	for _, t := range tasks {
+		if p, ok := ps[t.nodeKey]; ok && !p.taken {
+			p.taken = true
+			t.prefetch = p
+		}
+	}
+	ps.release()
+}
+
+// release discards the prefetches not taken by any task.
+func (ps prefetches) release() {
+	for _, p := range ps {
+		if !p.taken {
+			p.discard()
+		}
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/einoerr"
+)
+
+func TestPrefetch(t *testing.T) {
+	ctx := context.Background()
+
+	var runs, canceled int32
+	newGraph := func(target string, delay time.Duration) *Graph[string, string] {
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("retriever", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			atomic.AddInt32(&runs, 1)
+			select {
+			case <-time.After(delay):
+				return "docs of " + in, nil
+			case <-ctx.Done():
+				atomic.AddInt32(&canceled, 1)
+				return "", ctx.Err()
+			}
+		})))
+		require.NoError(t, g.AddLambdaNode("chat", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return "chat with " + in, nil
+		})))
+		require.NoError(t, g.AddBranch(START, NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			// a slow classifier
+			time.Sleep(100 * time.Millisecond)
+			return target, nil
+		}, map[string]bool{"retriever": true, "chat": true})))
+		require.NoError(t, g.AddEdge("retriever", END))
+		require.NoError(t, g.AddEdge("chat", END))
+		return g
+	}
+
+	t.Run("selected", func(t *testing.T) {
+		atomic.StoreInt32(&runs, 0)
+		r, err := newGraph("retriever", 100*time.Millisecond).Compile(ctx, WithPrefetch("retriever"))
+		require.NoError(t, err)
+
+		start := time.Now()
+		out, err := r.Invoke(ctx, "query")
+		require.NoError(t, err)
+		assert.Equal(t, "docs of query", out)
+		assert.Less(t, time.Since(start), 180*time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+
+		sr, err := r.Stream(ctx, "query")
+		require.NoError(t, err)
+		chunks, err := concatStreamReader(sr)
+		require.NoError(t, err)
+		assert.Equal(t, "docs of query", chunks)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("discarded", func(t *testing.T) {
+		atomic.StoreInt32(&runs, 0)
+		atomic.StoreInt32(&canceled, 0)
+		r, err := newGraph("chat", time.Minute).Compile(ctx, WithPrefetch("retriever"))
+		require.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "query")
+		require.NoError(t, err)
+		assert.Equal(t, "chat with query", out)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&canceled) == 1
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		_, err := newGraph("chat", time.Minute).Compile(ctx, WithPrefetch("unknown"))
+		assert.ErrorIs(t, err, einoerr.ErrNodeNotFound)
+
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })))
+		require.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", "b"))
+		require.NoError(t, g.AddEdge("b", END))
+		_, err = g.Compile(ctx, WithPrefetch("b"))
+		assert.ErrorContains(t, err, "receives data from node[a]")
+
+		g = NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", END))
+		_, err = g.Compile(ctx, WithPrefetch("a"), WithNodeTriggerMode(AllPredecessor))
+		assert.ErrorContains(t, err, "pregel mode")
+
+		_, err = newGraph("chat", time.Minute).Compile(ctx, WithPrefetch("retriever"), WithInterruptBeforeNodes([]string{"retriever"}))
+		assert.ErrorContains(t, err, "interrupted before")
+
+		// the writes of the state handlers can't be undone if the prefetch is discarded
+		g = NewGraph[string, string](WithGenLocalState(func(ctx context.Context) *[]string { return &[]string{} }))
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil }),
+			WithStatePostHandler(func(ctx context.Context, out string, s *[]string) (string, error) {
+				*s = append(*s, out)
+				return out, nil
+			})))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", END))
+		_, err = g.Compile(ctx, WithPrefetch("a"))
+		assert.ErrorContains(t, err, "has state handlers")
+	})
+
+	t.Run("context released", func(t *testing.T) {
+		ctxs := make(chan context.Context, 2)
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			ctxs <- ctx
+			return in, nil
+		})))
+		require.NoError(t, g.AddEdge(START, "a"))
+		require.NoError(t, g.AddEdge("a", END))
+		r, err := g.Compile(ctx, WithPrefetch("a"))
+		require.NoError(t, err)
+
+		// the parent context is still alive, while the context of the consumed prefetch is canceled
+		_, err = r.Invoke(ctx, "query")
+		require.NoError(t, err)
+		assert.ErrorIs(t, (<-ctxs).Err(), context.Canceled)
+
+		sr, err := r.Stream(ctx, "query")
+		require.NoError(t, err)
+		pctx := <-ctxs
+		_, err = concatStreamReader(sr)
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return pctx.Err() != nil
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -30,6 +30,8 @@ type pregelChannel struct {
 
 func (ch *pregelChannel) setMergeConfig(cfg FanInMergeConfig) {
 	ch.mergeConfig.StreamMergeWithSourceEOF = cfg.StreamMergeWithSourceEOF
+	ch.mergeConfig.strategy = cfg.strategy
+	ch.mergeConfig.mergeFuncs = cfg.mergeFuncs
 }
 
 func (ch *pregelChannel) load(c channel) error {
@@ -71,6 +73,13 @@ func (ch *pregelChannel) get(isStream bool, name string, edgeHandler *edgeHandle
 		i++
 	}
 
+	if ch.mergeConfig.strategy != nil {
+		v, err := ch.mergeConfig.strategy.apply(names, values, isStream)
+		if err != nil {
+			return nil, false, err
+		}
+		return v, true, nil
+	}
 	if len(values) == 1 {
 		return values[0], true, nil
 	}
@@ -79,6 +88,7 @@ func (ch *pregelChannel) get(isStream bool, name string, edgeHandler *edgeHandle
 	mergeOpts := &mergeOptions{
 		streamMergeWithSourceEOF: ch.mergeConfig.StreamMergeWithSourceEOF,
 		names:                    names,
+		mergeFuncs:               ch.mergeConfig.mergeFuncs,
 	}
 	v, err := mergeValues(values, mergeOpts)
 	if err != nil {
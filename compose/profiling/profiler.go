@@ -0,0 +1,295 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package profiling provides a callbacks handler profiling the graph runs, reporting the wall time, the time to the first chunk
+// and the queueing delay of each node, and the critical path through the graph, aggregated over the profiled runs.
+// It helps to decide which nodes to parallelize or cache.
+//
+//	p := profiling.NewProfiler()
+//	out, err := runnable.Invoke(ctx, input, p.Option())
+//	fmt.Println(p.Report())
+package profiling
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// NewProfiler creates a Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{nodes: make(map[string]*NodeProfile)}
+}
+
+// Profiler is a callbacks handler profiling the graph runs, including the nodes of the subgraphs.
+// A run starts with the outermost callback without a profiled run in its context, usually the callback of the root graph.
+// Pass Option() to the runs to be profiled, the same Profiler can be used for many runs, concurrently or not.
+type Profiler struct {
+	mu      sync.Mutex
+	runs    int
+	wall    time.Duration
+	nodes   map[string]*NodeProfile
+	slowest *runProfile
+
+	// pending are the runs waiting for the streams of their nodes to finish
+	pending sync.WaitGroup
+}
+
+// Option returns the option of the runs to be profiled.
+func (p *Profiler) Option() compose.Option {
+	return compose.WithCallbacks(p.Handler())
+}
+
+// Handler returns the callbacks handler profiling the runs.
+func (p *Profiler) Handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			return p.start(ctx)
+		}).
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			input.Close()
+			return p.start(ctx)
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			p.end(ctx, nil)
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			p.endStream(ctx, output)
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			p.end(ctx, err)
+			return ctx
+		}).
+		Build()
+}
+
+// Report returns the profile aggregated over the finished runs, after the streams of them are received.
+func (p *Profiler) Report() *Profile {
+	p.pending.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile := &Profile{Runs: p.runs, WallTime: p.wall}
+	for _, n := range p.nodes {
+		cp := *n
+		profile.Nodes = append(profile.Nodes, &cp)
+	}
+	sort.Slice(profile.Nodes, func(i, j int) bool {
+		a, b := profile.Nodes[i], profile.Nodes[j]
+		if a.CriticalTime != b.CriticalTime {
+			return a.CriticalTime > b.CriticalTime
+		}
+		if a.WallTime != b.WallTime {
+			return a.WallTime > b.WallTime
+		}
+		return a.Path < b.Path
+	})
+	if p.slowest != nil {
+		profile.SlowestRun = p.slowest.wall
+		profile.CriticalPath = append(profile.CriticalPath, p.slowest.criticalPath...)
+	}
+	return profile
+}
+
+// Reset clears the profiled runs.
+func (p *Profiler) Reset() {
+	p.pending.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runs = 0
+	p.wall = 0
+	p.nodes = make(map[string]*NodeProfile)
+	p.slowest = nil
+}
+
+type profileCtxKey struct{}
+
+// profileCtx is stored in the context returned by OnStart.
+// exec is nil for the callbacks which aren't node executions, e.g. the components nested in a node sharing its address.
+type profileCtx struct {
+	run  *runState
+	exec *execution
+	addr string
+	root bool
+}
+
+type runState struct {
+	start time.Time
+	end   time.Time
+	// addr is the address of the root of the run.
+	addr string
+
+	mu    sync.Mutex
+	execs []*execution
+	// streams are the goroutines receiving the output streams of the nodes
+	streams sync.WaitGroup
+}
+
+type execution struct {
+	path string
+	// addr is the address of the node, and parent is the address of the graph the node belongs to.
+	addr, parent string
+
+	start, end time.Time
+	stream     bool
+	firstChunk time.Duration
+	err        error
+}
+
+func (p *Profiler) start(ctx context.Context) context.Context {
+	addr := compose.GetCurrentAddress(ctx)
+	addrStr := addr.String()
+
+	pc, ok := ctx.Value(profileCtxKey{}).(*profileCtx)
+	if !ok {
+		run := &runState{start: time.Now(), addr: addrStr}
+		return context.WithValue(ctx, profileCtxKey{}, &profileCtx{run: run, addr: addrStr, root: true})
+	}
+
+	if len(addr) == 0 || addr[len(addr)-1].Type != compose.AddressSegmentNode || pc.addr == addrStr {
+		// tool calls aren't nodes, and the components nested in a node share its address
+		return context.WithValue(ctx, profileCtxKey{}, &profileCtx{run: pc.run, addr: addrStr})
+	}
+
+	var keys []string
+	for _, seg := range addr {
+		if seg.Type == compose.AddressSegmentNode {
+			keys = append(keys, seg.ID)
+		}
+	}
+	exec := &execution{
+		path:   strings.Join(keys, "/"),
+		addr:   addrStr,
+		parent: addr[:len(addr)-1].String(),
+		start:  time.Now(),
+	}
+
+	pc.run.mu.Lock()
+	pc.run.execs = append(pc.run.execs, exec)
+	pc.run.mu.Unlock()
+
+	return context.WithValue(ctx, profileCtxKey{}, &profileCtx{run: pc.run, exec: exec, addr: addrStr})
+}
+
+func (p *Profiler) end(ctx context.Context, err error) {
+	pc, ok := ctx.Value(profileCtxKey{}).(*profileCtx)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if pc.exec != nil {
+		pc.run.mu.Lock()
+		pc.exec.end = now
+		pc.exec.err = err
+		pc.run.mu.Unlock()
+	}
+	if pc.root {
+		p.finish(pc.run, now)
+	}
+}
+
+func (p *Profiler) endStream(ctx context.Context, output *schema.StreamReader[callbacks.CallbackOutput]) {
+	pc, ok := ctx.Value(profileCtxKey{}).(*profileCtx)
+	if !ok || (pc.exec == nil && !pc.root) {
+		output.Close()
+		return
+	}
+
+	// the root run isn't waiting for its own stream
+	if !pc.root {
+		pc.run.streams.Add(1)
+	}
+	go func() {
+		defer output.Close()
+
+		var (
+			first = true
+			err   error
+		)
+		for {
+			_, e := output.Recv()
+			if errors.Is(e, io.EOF) {
+				break
+			}
+			if e != nil {
+				err = e
+				break
+			}
+			if first && pc.exec != nil {
+				first = false
+				pc.run.mu.Lock()
+				pc.exec.stream = true
+				pc.exec.firstChunk = time.Since(pc.exec.start)
+				pc.run.mu.Unlock()
+			}
+		}
+
+		now := time.Now()
+		if pc.exec != nil {
+			pc.run.mu.Lock()
+			pc.exec.stream = true
+			pc.exec.end = now
+			pc.exec.err = err
+			pc.run.mu.Unlock()
+		}
+		if pc.root {
+			p.finish(pc.run, now)
+			return
+		}
+		pc.run.streams.Done()
+	}()
+}
+
+// finish aggregates the run into the profile once the streams of its nodes are received.
+func (p *Profiler) finish(run *runState, end time.Time) {
+	run.end = end
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		run.streams.Wait()
+
+		rp := analyze(run)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.runs++
+		p.wall += rp.wall
+		for _, e := range rp.execs {
+			n, ok := p.nodes[e.path]
+			if !ok {
+				n = &NodeProfile{Path: e.path}
+				p.nodes[e.path] = n
+			}
+			n.add(e)
+		}
+		if p.slowest == nil || rp.wall > p.slowest.wall {
+			p.slowest = rp
+		}
+	}()
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func sleepLambda(d time.Duration) *compose.Lambda {
+	return compose.InvokableLambda(func(ctx context.Context, in map[string]any) (string, error) {
+		time.Sleep(d)
+		return "ok", nil
+	})
+}
+
+func buildGraph(t *testing.T) compose.Runnable[map[string]any, string] {
+	ctx := context.Background()
+
+	sub := compose.NewGraph[map[string]any, string]()
+	assert.NoError(t, sub.AddLambdaNode("inner", sleepLambda(10*time.Millisecond)))
+	assert.NoError(t, sub.AddEdge(compose.START, "inner"))
+	assert.NoError(t, sub.AddEdge("inner", compose.END))
+
+	g := compose.NewGraph[map[string]any, string]()
+	assert.NoError(t, g.AddGraphNode("fast", sub, compose.WithOutputKey("fast")))
+	assert.NoError(t, g.AddLambdaNode("slow", sleepLambda(60*time.Millisecond), compose.WithOutputKey("slow")))
+	assert.NoError(t, g.AddLambdaNode("answer", compose.StreamableLambda(func(ctx context.Context, in map[string]any) (*schema.StreamReader[string], error) {
+		sr, sw := schema.Pipe[string](0)
+		go func() {
+			defer sw.Close()
+			time.Sleep(5 * time.Millisecond)
+			sw.Send("a", nil)
+			time.Sleep(20 * time.Millisecond)
+			sw.Send("b", nil)
+		}()
+		return sr, nil
+	})))
+	assert.NoError(t, g.AddEdge(compose.START, "fast"))
+	assert.NoError(t, g.AddEdge(compose.START, "slow"))
+	assert.NoError(t, g.AddEdge("fast", "answer"))
+	assert.NoError(t, g.AddEdge("slow", "answer"))
+	assert.NoError(t, g.AddEdge("answer", compose.END))
+
+	r, err := g.Compile(ctx, compose.WithNodeTriggerMode(compose.AllPredecessor))
+	assert.NoError(t, err)
+	return r
+}
+
+func TestProfiler(t *testing.T) {
+	ctx := context.Background()
+	r := buildGraph(t)
+	p := NewProfiler()
+
+	out, err := r.Invoke(ctx, map[string]any{}, p.Option())
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", out)
+
+	sr, err := r.Stream(ctx, map[string]any{}, p.Option())
+	assert.NoError(t, err)
+	for {
+		if _, err = sr.Recv(); err != nil {
+			break
+		}
+	}
+	sr.Close()
+
+	var profile *Profile
+	assert.Eventually(t, func() bool {
+		profile = p.Report()
+		return profile.Runs == 2
+	}, time.Second, 10*time.Millisecond)
+
+	nodes := make(map[string]*NodeProfile)
+	for _, n := range profile.Nodes {
+		nodes[n.Path] = n
+	}
+	assert.Len(t, nodes, 4)
+	for _, path := range []string{"fast", "fast/inner", "slow", "answer"} {
+		assert.Equal(t, 2, nodes[path].Runs, path)
+	}
+
+	slow, answer, fast := nodes["slow"], nodes["answer"], nodes["fast"]
+	assert.True(t, slow.MeanWallTime() >= 60*time.Millisecond)
+	assert.Equal(t, 2, answer.StreamRuns)
+	assert.True(t, answer.MeanFirstChunk() >= 5*time.Millisecond)
+	assert.True(t, answer.MeanFirstChunk() < answer.MeanWallTime())
+	// the answer node waits for the slow node, not the fast one
+	assert.Equal(t, 2, slow.CriticalRuns)
+	assert.Equal(t, 2, answer.CriticalRuns)
+	assert.Equal(t, 0, fast.CriticalRuns)
+	assert.Equal(t, "slow", profile.Nodes[0].Path)
+
+	assert.Len(t, profile.CriticalPath, 2)
+	assert.Equal(t, "slow", profile.CriticalPath[0].Path)
+	assert.Equal(t, "answer", profile.CriticalPath[1].Path)
+	assert.True(t, profile.CriticalPath[1].Start >= 60*time.Millisecond)
+	assert.True(t, profile.CriticalPath[1].FirstChunk > 0)
+
+	assert.Contains(t, profile.String(), "critical path of the slowest run")
+
+	t.Run("error and reset", func(t *testing.T) {
+		p.Reset()
+		assert.Equal(t, 0, p.Report().Runs)
+
+		g := compose.NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("fail", compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return "", errors.New("fail")
+		})))
+		assert.NoError(t, g.AddEdge(compose.START, "fail"))
+		assert.NoError(t, g.AddEdge("fail", compose.END))
+		fr, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		_, err = fr.Invoke(ctx, "", p.Option())
+		assert.Error(t, err)
+
+		profile := p.Report()
+		assert.Equal(t, 1, profile.Runs)
+		assert.Equal(t, 1, profile.Nodes[0].Errors)
+		assert.Equal(t, "fail", profile.CriticalPath[0].Path)
+	})
+}
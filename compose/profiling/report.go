@@ -0,0 +1,247 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile is the profile of the graph runs, reported by Profiler.Report.
+type Profile struct {
+	// Runs is the number of the profiled runs.
+	Runs int
+	// WallTime is the total wall time of the runs.
+	WallTime time.Duration
+	// Nodes are the profiles of the nodes, ordered by the time on the critical path, then by the wall time, descending.
+	Nodes []*NodeProfile
+
+	// SlowestRun is the wall time of the slowest run, and CriticalPath is the critical path of it.
+	SlowestRun   time.Duration
+	CriticalPath []*PathStep
+}
+
+// NodeProfile is the profile of a node, accumulated over its executions in the runs.
+type NodeProfile struct {
+	// Path is the keys of the node and its ancestor nodes from the root graph joined by '/', e.g. "sub_graph/node".
+	Path string
+	// Runs is the number of the executions, and Errors is the number of the failed ones.
+	Runs   int
+	Errors int
+
+	// WallTime is the total wall time of the executions, and MaxWallTime is the longest one.
+	// For streaming output, an execution lasts until the output stream is fully received.
+	WallTime    time.Duration
+	MaxWallTime time.Duration
+
+	// StreamRuns is the number of the executions with streaming output, and FirstChunk is the total time to their first chunks.
+	StreamRuns int
+	FirstChunk time.Duration
+
+	// QueueDelay is the total time the executions waited to start after their predecessors finished,
+	// e.g. the scheduling overhead between the super steps.
+	QueueDelay time.Duration
+
+	// CriticalRuns is the number of the executions on the critical paths of the top-level graph,
+	// and CriticalTime is the total wall time of them.
+	CriticalRuns int
+	CriticalTime time.Duration
+}
+
+// MeanWallTime returns the mean wall time of the executions.
+func (n *NodeProfile) MeanWallTime() time.Duration {
+	if n.Runs == 0 {
+		return 0
+	}
+	return n.WallTime / time.Duration(n.Runs)
+}
+
+// MeanFirstChunk returns the mean time to the first chunk of the executions with streaming output.
+func (n *NodeProfile) MeanFirstChunk() time.Duration {
+	if n.StreamRuns == 0 {
+		return 0
+	}
+	return n.FirstChunk / time.Duration(n.StreamRuns)
+}
+
+// MeanQueueDelay returns the mean queueing delay of the executions.
+func (n *NodeProfile) MeanQueueDelay() time.Duration {
+	if n.Runs == 0 {
+		return 0
+	}
+	return n.QueueDelay / time.Duration(n.Runs)
+}
+
+func (n *NodeProfile) add(e *analyzedExecution) {
+	n.Runs++
+	if e.err != nil {
+		n.Errors++
+	}
+	wall := e.end.Sub(e.start)
+	n.WallTime += wall
+	if wall > n.MaxWallTime {
+		n.MaxWallTime = wall
+	}
+	if e.stream {
+		n.StreamRuns++
+		n.FirstChunk += e.firstChunk
+	}
+	n.QueueDelay += e.queueDelay
+	if e.critical {
+		n.CriticalRuns++
+		n.CriticalTime += wall
+	}
+}
+
+// PathStep is a node execution on the critical path.
+type PathStep struct {
+	Path string
+	// Start is the offset of the start of the execution from the start of the run.
+	Start      time.Duration
+	WallTime   time.Duration
+	QueueDelay time.Duration
+	// FirstChunk is the time to the first chunk if the output is streaming.
+	FirstChunk time.Duration
+}
+
+// String formats the profile as text tables of the nodes and the critical path.
+func (p *Profile) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "runs: %d, wall time: %v, slowest run: %v\n\n", p.Runs, p.WallTime, p.SlowestRun)
+
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tRUNS\tERRORS\tWALL\tMEAN\tMAX\tFIRST CHUNK\tQUEUE\tCRITICAL\t")
+	for _, n := range p.Nodes {
+		firstChunk := "-"
+		if n.StreamRuns > 0 {
+			firstChunk = n.MeanFirstChunk().String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%v\t%v\t%v\t%s\t%v\t%d/%v\t\n", n.Path, n.Runs, n.Errors, n.WallTime, n.MeanWallTime(),
+			n.MaxWallTime, firstChunk, n.MeanQueueDelay(), n.CriticalRuns, n.CriticalTime)
+	}
+	_ = w.Flush()
+
+	if len(p.CriticalPath) > 0 {
+		sb.WriteString("\ncritical path of the slowest run:\n")
+		w = tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NODE\tSTART\tWALL\tFIRST CHUNK\tQUEUE\t")
+		for _, s := range p.CriticalPath {
+			firstChunk := "-"
+			if s.FirstChunk > 0 {
+				firstChunk = s.FirstChunk.String()
+			}
+			fmt.Fprintf(w, "%s\t%v\t%v\t%s\t%v\t\n", s.Path, s.Start, s.WallTime, firstChunk, s.QueueDelay)
+		}
+		_ = w.Flush()
+	}
+	return sb.String()
+}
+
+type analyzedExecution struct {
+	execution
+	queueDelay time.Duration
+	critical   bool
+}
+
+type runProfile struct {
+	wall         time.Duration
+	execs        []*analyzedExecution
+	criticalPath []*PathStep
+}
+
+// analyze finds the predecessor of each execution, to compute the queueing delays and the critical path of the top-level graph.
+// The graph topology isn't known by the callbacks, so the predecessor is taken as the execution in the same graph
+// finishing last before the execution starts. It's exact for the super steps of Pregel mode, which are barriers,
+// and is the execution triggering the node in most cases of DAG mode.
+func analyze(run *runState) *runProfile {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	rp := &runProfile{wall: run.end.Sub(run.start)}
+	byParent := make(map[string][]*analyzedExecution)
+	for _, e := range run.execs {
+		if e.end.IsZero() {
+			// the node never finished, e.g. interrupted
+			continue
+		}
+		ae := &analyzedExecution{execution: *e}
+		rp.execs = append(rp.execs, ae)
+		byParent[e.parent] = append(byParent[e.parent], ae)
+	}
+
+	// the start of the graph a node belongs to, i.e. the latest execution of the subgraph node starting before it
+	graphStart := func(e *analyzedExecution) time.Time {
+		if e.parent == run.addr {
+			return run.start
+		}
+		var start time.Time
+		for _, g := range rp.execs {
+			if g.addr == e.parent && !g.start.After(e.start) && g.start.After(start) {
+				start = g.start
+			}
+		}
+		if start.IsZero() {
+			return e.start
+		}
+		return start
+	}
+
+	preds := make(map[*analyzedExecution]*analyzedExecution)
+	for _, group := range byParent {
+		for _, e := range group {
+			var pred *analyzedExecution
+			for _, c := range group {
+				if c != e && !c.end.After(e.start) && (pred == nil || c.end.After(pred.end)) {
+					pred = c
+				}
+			}
+			if pred != nil {
+				preds[e] = pred
+				e.queueDelay = e.start.Sub(pred.end)
+			} else {
+				e.queueDelay = e.start.Sub(graphStart(e))
+			}
+		}
+	}
+
+	var last *analyzedExecution
+	for _, e := range byParent[run.addr] {
+		if last == nil || e.end.After(last.end) {
+			last = e
+		}
+	}
+	for e := last; e != nil; e = preds[e] {
+		e.critical = true
+		step := &PathStep{
+			Path:       e.path,
+			Start:      e.start.Sub(run.start),
+			WallTime:   e.end.Sub(e.start),
+			QueueDelay: e.queueDelay,
+		}
+		if e.stream {
+			step.FirstChunk = e.firstChunk
+		}
+		rp.criticalPath = append(rp.criticalPath, step)
+	}
+	sort.Slice(rp.criticalPath, func(i, j int) bool {
+		return rp.criticalPath[i].Start < rp.criticalPath[j].Start
+	})
+	return rp
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often the node or the tool calls are executed, e.g. to enforce a quota shared by the nodes calling the same provider.
+// Wait blocks until an execution is allowed, or returns an error if ctx is done first.
+// *rate.Limiter of golang.org/x/time/rate implements it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucketLimiter is a simple token bucket RateLimiter, safe for concurrent use.
+// The bucket holds at most burst tokens, refilled at the rate of tokens per second, and each execution takes one token.
+// Waiters are served in the order they call Wait.
+type TokenBucketLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter creates a TokenBucketLimiter allowing ratePerSecond executions per second on average,
+// and up to burst executions at once. The bucket is full initially.
+// e.g.
+//
+//	limiter, err := compose.NewTokenBucketLimiter(10, 5)
+//	graph.AddChatModelNode("chat_model", chatModel, compose.WithRateLimiter(limiter))
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) (*TokenBucketLimiter, error) {
+	if ratePerSecond <= 0 || math.IsInf(ratePerSecond, 0) || math.IsNaN(ratePerSecond) {
+		return nil, errors.New("rate of token bucket limiter should be a positive number")
+	}
+	if burst < 1 {
+		return nil, errors.New("burst of token bucket limiter should be at least 1")
+	}
+	return &TokenBucketLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// Wait takes a token from the bucket, blocking until the token is available or ctx is done.
+func (l *TokenBucketLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// the token is reserved even if not available yet, so that the later waiters queue behind
+	l.tokens--
+	deficit := -l.tokens
+	l.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give back the reserved token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+type countingLimiter struct {
+	count int32
+	err   error
+}
+
+func (c *countingLimiter) Wait(_ context.Context) error {
+	atomic.AddInt32(&c.count, 1)
+	return c.err
+}
+
+func TestTokenBucketLimiter(t *testing.T) {
+	_, err := NewTokenBucketLimiter(0, 1)
+	assert.Error(t, err)
+	_, err = NewTokenBucketLimiter(1, 0)
+	assert.Error(t, err)
+
+	l, err := NewTokenBucketLimiter(20, 2)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	start := time.Now()
+	assert.NoError(t, l.Wait(ctx))
+	assert.NoError(t, l.Wait(ctx))
+	assert.Less(t, time.Since(start), 25*time.Millisecond)
+
+	// the bucket is empty, the next token is available after 50ms
+	assert.NoError(t, l.Wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	err = l.Wait(cctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	canceled, cancel2 := context.WithCancel(ctx)
+	cancel2()
+	assert.ErrorIs(t, l.Wait(canceled), context.Canceled)
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("node", func(t *testing.T) {
+		limiter := &countingLimiter{}
+		g := NewGraph[string, string]()
+		require.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in + "1", nil
+		}), WithRateLimiter(limiter)))
+		require.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in + "2", nil
+		}), WithRateLimiter(limiter)))
+		require.NoError(t, g.AddEdge(START, "1"))
+		require.NoError(t, g.AddEdge("1", "2"))
+		require.NoError(t, g.AddEdge("2", END))
+		r, err := g.Compile(ctx)
+		require.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "0")
+		assert.NoError(t, err)
+		assert.Equal(t, "012", out)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&limiter.count))
+
+		limiter.err = errors.New("quota exhausted")
+		_, err = r.Invoke(ctx, "0")
+		assert.ErrorContains(t, err, "wait for rate limiter of node[1] fail: quota exhausted")
+	})
+
+	t.Run("tools node", func(t *testing.T) {
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{&mockTool{}}})
+		require.NoError(t, err)
+
+		limiter := &countingLimiter{}
+		g := NewGraph[*schema.Message, []*schema.Message]()
+		require.NoError(t, g.AddToolsNode("tools", tn, WithRateLimiter(limiter)))
+		require.NoError(t, g.AddEdge(START, "tools"))
+		require.NoError(t, g.AddEdge("tools", END))
+		r, err := g.Compile(ctx)
+		require.NoError(t, err)
+
+		input := schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "1", Function: schema.FunctionCall{Name: "mock_tool", Arguments: `{"name":"a"}`}},
+			{ID: "2", Function: schema.FunctionCall{Name: "mock_tool", Arguments: `{"name":"b"}`}},
+		})
+		out, err := r.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Len(t, out, 2)
+		// waited for per tool call rather than per node execution
+		assert.Equal(t, int32(2), atomic.LoadInt32(&limiter.count))
+		// the shared ToolsNode is not affected
+		assert.Nil(t, tn.rateLimiter)
+
+		limiter.err = errors.New("quota exhausted")
+		_, err = r.Invoke(ctx, input)
+		assert.ErrorContains(t, err, "wait for rate limiter of tool[mock_tool] fail: quota exhausted")
+	})
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/internal/safe"
+)
+
+// RunStatus is the lifecycle status of a run started by Start.
+type RunStatus string
+
+const (
+	// RunStatusRunning means the run hasn't finished yet.
+	RunStatusRunning RunStatus = "running"
+	// RunStatusSucceeded means the run finished with an output.
+	RunStatusSucceeded RunStatus = "succeeded"
+	// RunStatusFailed means the run finished with an error.
+	RunStatusFailed RunStatus = "failed"
+	// RunStatusCanceled means the run was stopped by Cancel, CancelRun or the cancellation of the context passed to Start.
+	RunStatusCanceled RunStatus = "canceled"
+	// RunStatusInterrupted means the run was interrupted, use ExtractInterruptInfo on the error of Result to get the details.
+	RunStatusInterrupted RunStatus = "interrupted"
+)
+
+// RunControl is the type-erased view of a RunHandle, kept by the in-process run manager, see GetRun and ListRuns.
+type RunControl interface {
+	ID() string
+	Status() RunStatus
+	Cancel(reason CancelReason) bool
+	// Done is closed when the run finishes.
+	Done() <-chan struct{}
+}
+
+// RunHandle controls a run started asynchronously by Start.
+type RunHandle[O any] struct {
+	id   string
+	ctx  context.Context
+	done chan struct{}
+
+	mu          sync.Mutex
+	status      RunStatus
+	output      O
+	err         error
+	subscribers map[chan *callbacks.GraphEvent]struct{}
+}
+
+// Start runs r with Invoke in a new goroutine and returns immediately with a handle of the run,
+// which can be used to poll the status, cancel the run, wait for the result and subscribe the graph events.
+// The run is registered to the in-process run manager until it finishes, so that it can be found by GetRun with its ID.
+// The run can be canceled by CancelRun with the handle's context as well, as it's created by WithRunCancel.
+// e.g.
+//
+//	h := compose.Start(ctx, runnable, input)
+//	events, unsubscribe := h.Subscribe(16)
+//	defer unsubscribe()
+//	go func() {
+//		for e := range events {
+//			log.Printf("run[%s] event: %s", h.ID(), e.Type)
+//		}
+//	}()
+//	out, err := h.Result(ctx)
+func Start[I, O any](ctx context.Context, r Runnable[I, O], input I, opts ...Option) *RunHandle[O] {
+	ctx = WithRunCancel(ctx)
+	h := &RunHandle[O]{
+		id:          uuid.NewString(),
+		ctx:         ctx,
+		done:        make(chan struct{}),
+		status:      RunStatusRunning,
+		subscribers: make(map[chan *callbacks.GraphEvent]struct{}),
+	}
+
+	handler := callbacks.NewHandlerBuilder().
+		OnGraphEventFn(func(ctx context.Context, info *callbacks.RunInfo, event *callbacks.GraphEvent) {
+			h.publish(event)
+		}).
+		Build()
+	opts = append(opts[:len(opts):len(opts)], WithCallbacks(handler))
+
+	defaultRunManager.add(h)
+	go func() {
+		var (
+			output O
+			err    error
+		)
+		defer func() {
+			if e := recover(); e != nil {
+				err = safe.NewPanicErr(e, debug.Stack())
+			}
+			h.finish(output, err)
+			defaultRunManager.remove(h.id)
+			// release the context created by WithRunCancel
+			ctx.Value(runCancelKey{}).(*runCancel).cancel()
+		}()
+
+		output, err = r.Invoke(ctx, input, opts...)
+	}()
+
+	return h
+}
+
+// ID returns the unique ID of the run.
+func (h *RunHandle[O]) ID() string {
+	return h.id
+}
+
+// Status returns the current status of the run.
+func (h *RunHandle[O]) Status() RunStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// Cancel cancels the run with the reason by CancelRun, the error of the run will match ErrRunCanceled and carry the reason.
+// It returns false if the run has been canceled or finished.
+func (h *RunHandle[O]) Cancel(reason CancelReason) bool {
+	select {
+	case <-h.done:
+		return false
+	default:
+	}
+	return CancelRun(h.ctx, reason)
+}
+
+// Done returns a channel which is closed when the run finishes.
+func (h *RunHandle[O]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Result waits for the run to finish and returns its output and error.
+// It returns the error of ctx if ctx is done before the run finishes, without canceling the run.
+func (h *RunHandle[O]) Result(ctx context.Context) (O, error) {
+	select {
+	case <-h.done:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.output, h.err
+	case <-ctx.Done():
+		var o O
+		return o, ctx.Err()
+	}
+}
+
+// Subscribe subscribes the graph events of the run, including the ones of the subgraphs.
+// The events are delivered to a channel with the given buffer size, and dropped if the channel is full,
+// so that a slow subscriber never blocks the run.
+// The channel is closed when the run finishes or unsubscribe is called.
+func (h *RunHandle[O]) Subscribe(buffer int) (events <-chan *callbacks.GraphEvent, unsubscribe func()) {
+	ch := make(chan *callbacks.GraphEvent, buffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.status != RunStatusRunning {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *RunHandle[O]) publish(event *callbacks.GraphEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (h *RunHandle[O]) finish(output O, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.output, h.err = output, err
+	switch {
+	case err == nil:
+		h.status = RunStatusSucceeded
+	case isInterruptRunError(err):
+		h.status = RunStatusInterrupted
+	case errors.Is(err, ErrRunCanceled) || errors.Is(err, context.Canceled):
+		h.status = RunStatusCanceled
+	default:
+		h.status = RunStatusFailed
+	}
+
+	for ch := range h.subscribers {
+		close(ch)
+	}
+	h.subscribers = nil
+	close(h.done)
+}
+
+func isInterruptRunError(err error) bool {
+	_, ok := ExtractInterruptInfo(err)
+	return ok
+}
+
+type runManager struct {
+	mu   sync.Mutex
+	runs map[string]RunControl
+}
+
+var defaultRunManager = &runManager{runs: make(map[string]RunControl)}
+
+func (m *runManager) add(r RunControl) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[r.ID()] = r
+}
+
+func (m *runManager) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.runs, id)
+}
+
+// GetRun returns the unfinished run started by Start with the given ID.
+func GetRun(id string) (RunControl, bool) {
+	defaultRunManager.mu.Lock()
+	defer defaultRunManager.mu.Unlock()
+	r, ok := defaultRunManager.runs[id]
+	return r, ok
+}
+
+// ListRuns returns all the unfinished runs started by Start, sorted by ID.
+func ListRuns() []RunControl {
+	defaultRunManager.mu.Lock()
+	defer defaultRunManager.mu.Unlock()
+	ret := make([]RunControl, 0, len(defaultRunManager.runs))
+	for _, r := range defaultRunManager.runs {
+		ret = append(ret, r)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID() < ret[j].ID()
+	})
+	return ret
+}
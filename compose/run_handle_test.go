@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/callbacks"
+)
+
+func TestStart(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	g := NewGraph[string, string]()
+	require.NoError(t, g.AddLambdaNode("wait", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		select {
+		case <-release:
+			return in + "_done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})))
+	require.NoError(t, g.AddEdge(START, "wait"))
+	require.NoError(t, g.AddEdge("wait", END))
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+
+	t.Run("succeeded", func(t *testing.T) {
+		h := Start(ctx, r, "x")
+		assert.Equal(t, RunStatusRunning, h.Status())
+		events, unsubscribe := h.Subscribe(10)
+		defer unsubscribe()
+
+		got, ok := GetRun(h.ID())
+		assert.True(t, ok)
+		assert.Equal(t, h.ID(), got.ID())
+		assert.Contains(t, ListRuns(), got)
+
+		release <- struct{}{}
+		out, err := h.Result(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "x_done", out)
+		assert.Equal(t, RunStatusSucceeded, h.Status())
+		assert.False(t, h.Cancel(CancelReasonUserAborted))
+
+		var types []callbacks.GraphEventType
+		for e := range events {
+			types = append(types, e.Type)
+		}
+		assert.Contains(t, types, callbacks.GraphEventRunEnd)
+
+		_, ok = GetRun(h.ID())
+		assert.False(t, ok)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		h := Start(ctx, r, "x")
+		assert.True(t, h.Cancel(CancelReasonUserAborted))
+		<-h.Done()
+		_, err := h.Result(ctx)
+		assert.ErrorIs(t, err, ErrRunCanceled)
+		assert.Equal(t, RunStatusCanceled, h.Status())
+	})
+
+	t.Run("result timeout", func(t *testing.T) {
+		h := Start(ctx, r, "x")
+		tCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := h.Result(tCtx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, RunStatusRunning, h.Status())
+		release <- struct{}{}
+		_, err = h.Result(ctx)
+		assert.NoError(t, err)
+	})
+}
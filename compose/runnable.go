@@ -55,11 +55,17 @@ type composableRunnable struct {
 
 	isPassthrough bool
 
+	// the paradigms implemented by the user, the others are converted from them
+	paradigms runnableParadigm
+
 	meta *executorMeta
 
 	// only available when in Graph node
 	// if composableRunnable not in Graph node, this field would be nil
 	nodeInfo *nodeInfo
+
+	// the configurable fields declared in the graph, only available for the compiled graph
+	configurable map[string][]configurableTarget
 }
 
 func runnableLambda[I, O, TOption any](i Invoke[I, O, TOption], s Stream[I, O, TOption], c Collect[I, O, TOption],
@@ -74,6 +80,32 @@ type runnablePacker[I, O, TOption any] struct {
 	s Stream[I, O, TOption]
 	c Collect[I, O, TOption]
 	t Transform[I, O, TOption]
+
+	paradigms runnableParadigm
+}
+
+// runnableParadigm is a bit set of the paradigms implemented by a runnable.
+type runnableParadigm uint8
+
+const (
+	paradigmInvoke runnableParadigm = 1 << iota
+	paradigmStream
+	paradigmCollect
+	paradigmTransform
+)
+
+// concatsInputWhenStreaming reports whether the input stream is concatenated when running in stream mode,
+// i.e. Transform is converted from Invoke or Stream.
+func (p runnableParadigm) concatsInputWhenStreaming() bool {
+	return p&paradigmTransform == 0 &&
+		(p&paradigmStream != 0 || (p&paradigmCollect == 0 && p&paradigmInvoke != 0))
+}
+
+// concatsOutputWhenInvoking reports whether the output stream is concatenated when running in invoke mode,
+// i.e. Invoke is converted from Stream or Transform.
+func (p runnableParadigm) concatsOutputWhenInvoking() bool {
+	return p&paradigmInvoke == 0 &&
+		(p&paradigmStream != 0 || (p&paradigmCollect == 0 && p&paradigmTransform != 0))
 }
 
 func (rp *runnablePacker[I, O, TOption]) wrapRunnableCtx(ctxWrapper func(ctx context.Context, opts ...TOption) context.Context) {
@@ -106,6 +138,7 @@ func (rp *runnablePacker[I, O, TOption]) toComposableRunnable() *composableRunna
 		inputType:     inputType,
 		outputType:    outputType,
 		optionType:    optionType,
+		paradigms:     rp.paradigms,
 	}
 
 	i := func(ctx context.Context, input any, opts ...any) (output any, err error) {
@@ -337,6 +370,18 @@ func newRunnablePacker[I, O, TOption any](i Invoke[I, O, TOption], s Stream[I, O
 	c Collect[I, O, TOption], t Transform[I, O, TOption], enableCallback bool) *runnablePacker[I, O, TOption] {
 
 	r := &runnablePacker[I, O, TOption]{}
+	if i != nil {
+		r.paradigms |= paradigmInvoke
+	}
+	if s != nil {
+		r.paradigms |= paradigmStream
+	}
+	if c != nil {
+		r.paradigms |= paradigmCollect
+	}
+	if t != nil {
+		r.paradigms |= paradigmTransform
+	}
 
 	if enableCallback {
 		if i != nil {
@@ -510,7 +555,7 @@ func outputKeyedComposableRunnable(key string, r *composableRunnable) *composabl
 
 // composablePassthrough special runnable that passthrough input to output
 func composablePassthrough() *composableRunnable {
-	r := &composableRunnable{isPassthrough: true, nodeInfo: &nodeInfo{}}
+	r := &composableRunnable{isPassthrough: true, nodeInfo: &nodeInfo{}, paradigms: paradigmInvoke | paradigmTransform}
 
 	r.i = func(ctx context.Context, input any, opts ...any) (output any, err error) {
 		return input, nil
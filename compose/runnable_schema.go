@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// InputSchemaOf returns the JSON Schema of the input type I of the Runnable, see reflectJSONSchema,
+// e.g. for the HTTP servers to validate the requests, or for the frontends to generate the forms.
+// e.g.
+//
+//	r, err := graph.Compile(ctx)
+//	inputSchema, err := compose.InputSchemaOf(r)
+func InputSchemaOf[I, O any](r Runnable[I, O]) (*jsonschema.Schema, error) {
+	return reflectJSONSchema(generic.TypeOf[I]())
+}
+
+// OutputSchemaOf returns the JSON Schema of the output type O of the Runnable, reflected in the same way as InputSchemaOf.
+func OutputSchemaOf[I, O any](r Runnable[I, O]) (*jsonschema.Schema, error) {
+	return reflectJSONSchema(generic.TypeOf[O]())
+}
+
+// reflectJSONSchema reflects the JSON Schema of the type, honoring the json and jsonschema struct tags,
+// i.e. the field names and omitempty of json, and the description, enum, etc. of jsonschema.
+// The fields are required unless they are tagged omitempty, and the nested struct types are referenced from $defs,
+// while the struct type itself is expanded at the root.
+func reflectJSONSchema(t reflect.Type) (js *jsonschema.Schema, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			js, err = nil, fmt.Errorf("reflect json schema of type[%s] fail: %v", t, e)
+		}
+	}()
+
+	root := t
+	for root.Kind() == reflect.Ptr {
+		root = root.Elem()
+	}
+	r := &jsonschema.Reflector{
+		Anonymous:      true,
+		ExpandedStruct: root.Kind() == reflect.Struct,
+	}
+	return r.ReflectFromType(t), nil
+}
@@ -208,3 +208,76 @@ func TestRunnableLambda(t *testing.T) {
 		assert.Equal(t, "10+100", out)
 	})
 }
+
+type schemaTestAddress struct {
+	City string `json:"city" jsonschema:"description=the city"`
+}
+
+type schemaTestRequest struct {
+	Query   string             `json:"query" jsonschema:"description=the query"`
+	Mode    string             `json:"mode,omitempty" jsonschema:"enum=fast,enum=deep"`
+	Address *schemaTestAddress `json:"address,omitempty"`
+	Ignored string             `json:"-"`
+}
+
+func TestRunnableSchema(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[*schemaTestRequest, []*schema.Message]()
+	assert.NoError(t, g.AddLambdaNode("node", InvokableLambda(func(ctx context.Context, in *schemaTestRequest) ([]*schema.Message, error) {
+		return []*schema.Message{schema.UserMessage(in.Query)}, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "node"))
+	assert.NoError(t, g.AddEdge("node", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	in, err := InputSchemaOf(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "object", in.Type)
+	assert.Equal(t, []string{"query"}, in.Required)
+	query, ok := in.Properties.Get("query")
+	assert.True(t, ok)
+	assert.Equal(t, "string", query.Type)
+	assert.Equal(t, "the query", query.Description)
+	mode, ok := in.Properties.Get("mode")
+	assert.True(t, ok)
+	assert.Equal(t, []any{"fast", "deep"}, mode.Enum)
+	_, ok = in.Properties.Get("address")
+	assert.True(t, ok)
+	assert.Contains(t, in.Definitions, "schemaTestAddress")
+	_, ok = in.Properties.Get("Ignored")
+	assert.False(t, ok)
+	assert.Equal(t, 3, in.Properties.Len())
+
+	out, err := OutputSchemaOf(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "array", out.Type)
+	assert.NotNil(t, out.Items)
+
+	sr, err := compileSchemaTestGraph[map[string]any, string](ctx)
+	assert.NoError(t, err)
+	in, err = InputSchemaOf(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "object", in.Type)
+	out, err = OutputSchemaOf(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "string", out.Type)
+}
+
+func compileSchemaTestGraph[I, O any](ctx context.Context) (Runnable[I, O], error) {
+	g := NewGraph[I, O]()
+	if err := g.AddLambdaNode("node", InvokableLambda(func(ctx context.Context, in I) (O, error) {
+		var o O
+		return o, nil
+	})); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(START, "node"); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge("node", END); err != nil {
+		return nil, err
+	}
+	return g.Compile(ctx)
+}
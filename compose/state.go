@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
 )
@@ -33,8 +34,53 @@ type stateKey struct{}
 
 type internalState struct {
 	state  any
-	mu     sync.Mutex
+	mu     sync.RWMutex
 	parent *internalState
+
+	fieldMu sync.Map // field pointer -> *sync.RWMutex, locks of the fields accessed by StateField
+	sides   sideLock // excludes the readers of the whole state and the writers of the fields from each other
+}
+
+const (
+	sideStateReaders = iota
+	sideFieldWriters
+)
+
+// sideLock is shared by the holders on the same side, while the two sides exclude each other.
+// It keeps the readers of the whole state, i.e. ProcessStateRead, from observing a state half-written by StateField,
+// while the readers still run concurrently with each other, and so do the writers of different fields.
+type sideLock struct {
+	mu      sync.Mutex
+	side    int
+	holders int
+	idle    chan struct{} // closed once the holders of the current side are all gone
+}
+
+func (l *sideLock) lock(side int) {
+	for {
+		l.mu.Lock()
+		if l.holders == 0 || l.side == side {
+			if l.holders == 0 {
+				l.idle = make(chan struct{})
+			}
+			l.side = side
+			l.holders++
+			l.mu.Unlock()
+			return
+		}
+		idle := l.idle
+		l.mu.Unlock()
+		<-idle
+	}
+}
+
+func (l *sideLock) unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holders--
+	if l.holders == 0 {
+		close(l.idle)
+	}
 }
 
 // StatePreHandler is a function called before the node is executed.
@@ -57,10 +103,13 @@ func convertPreHandler[I, S any](handler StatePreHandler[I, S]) *composableRunna
 		if err != nil {
 			return in, err
 		}
-		pMu.Lock()
-		defer pMu.Unlock()
-
-		return handler(ctx, in, cState)
+		in, err = lockState(pMu, func() (I, error) {
+			return handler(ctx, in, cState)
+		})
+		if err == nil {
+			onStateMutated(ctx)
+		}
+		return in, err
 	}
 
 	return runnableLambda[I, I](rf, nil, nil, nil, false)
@@ -72,10 +121,13 @@ func convertPostHandler[O, S any](handler StatePostHandler[O, S]) *composableRun
 		if err != nil {
 			return out, err
 		}
-		pMu.Lock()
-		defer pMu.Unlock()
-
-		return handler(ctx, out, cState)
+		out, err = lockState(pMu, func() (O, error) {
+			return handler(ctx, out, cState)
+		})
+		if err == nil {
+			onStateMutated(ctx)
+		}
+		return out, err
 	}
 
 	return runnableLambda[O, O](rf, nil, nil, nil, false)
@@ -87,10 +139,13 @@ func streamConvertPreHandler[I, S any](handler StreamStatePreHandler[I, S]) *com
 		if err != nil {
 			return in, err
 		}
-		pMu.Lock()
-		defer pMu.Unlock()
-
-		return handler(ctx, in, cState)
+		in, err = lockState(pMu, func() (*schema.StreamReader[I], error) {
+			return handler(ctx, in, cState)
+		})
+		if err == nil {
+			onStateMutated(ctx)
+		}
+		return in, err
 	}
 
 	return runnableLambda[I, I](nil, nil, nil, rf, false)
@@ -102,10 +157,13 @@ func streamConvertPostHandler[O, S any](handler StreamStatePostHandler[O, S]) *c
 		if err != nil {
 			return out, err
 		}
-		pMu.Lock()
-		defer pMu.Unlock()
-
-		return handler(ctx, out, cState)
+		out, err = lockState(pMu, func() (*schema.StreamReader[O], error) {
+			return handler(ctx, out, cState)
+		})
+		if err == nil {
+			onStateMutated(ctx)
+		}
+		return out, err
 	}
 
 	return runnableLambda[O, O](nil, nil, nil, rf, false)
@@ -167,12 +225,159 @@ func ProcessState[S any](ctx context.Context, handler func(context.Context, S) e
 	if err != nil {
 		return fmt.Errorf("get state from context fail: %w", err)
 	}
-	pMu.Lock()
-	defer pMu.Unlock()
+	_, err = lockState(pMu, func() (struct{}, error) {
+		return struct{}{}, handler(ctx, s)
+	})
+	if err == nil {
+		onStateMutated(ctx)
+	}
+	return err
+}
+
+// ProcessStateRead is the read-only variant of ProcessState.
+// The handler is executed while holding the read lock of the state, so multiple readers can access the state concurrently,
+// e.g. in the parallel nodes of a high fan-out graph. The handler MUST NOT modify the state.
+// State lookup behavior is the same as ProcessState.
+//
+// Example:
+//
+//	var userID string
+//	err := compose.ProcessStateRead[*MyState](ctx, func(ctx context.Context, state *MyState) error {
+//		userID = state.UserID
+//		return nil
+//	})
+func ProcessStateRead[S any](ctx context.Context, handler func(context.Context, S) error) error {
+	s, is, err := getInternalState[S](ctx)
+	if err != nil {
+		return fmt.Errorf("get state from context fail: %w", err)
+	}
+	is.mu.RLock()
+	defer is.mu.RUnlock()
+	is.sides.lock(sideStateReaders)
+	defer is.sides.unlock()
 	return handler(ctx, s)
 }
 
-func getState[S any](ctx context.Context) (S, *sync.Mutex, error) {
+// StateField is a typed accessor of a field in state S.
+// Instead of locking the whole state, each field is guarded by a lock of its own, keyed by the pointer to the field,
+// so that the handlers on different fields run concurrently, while ProcessState still has exclusive access to the whole state.
+// Load is concurrent with ProcessStateRead as well, while Store and Update never interleave with the readers of the whole state,
+// e.g. ProcessStateRead or the state snapshots, which would otherwise observe a half-written state.
+// The accessors of overlapping fields, e.g. a struct and a field of it, are not excluded from each other.
+//
+// Create the accessors once, and use them in any graph with state S:
+//
+//	var messagesField = compose.NewStateField(func(s *MyState) *[]*schema.Message { return &s.Messages })
+//
+//	err := messagesField.Update(ctx, func(msgs []*schema.Message) ([]*schema.Message, error) {
+//		return append(msgs, msg), nil
+//	})
+type StateField[S, F any] struct {
+	get func(S) *F
+}
+
+// NewStateField creates a StateField with a function returning the pointer to the field in state S.
+func NewStateField[S, F any](get func(state S) *F) *StateField[S, F] {
+	return &StateField[S, F]{
+		get: get,
+	}
+}
+
+func (f *StateField[S, F]) access(ctx context.Context, write bool, fn func(*F) error) error {
+	s, is, err := getInternalState[S](ctx)
+	if err != nil {
+		return fmt.Errorf("get state from context fail: %w", err)
+	}
+
+	is.mu.RLock()
+	defer is.mu.RUnlock()
+
+	field := f.get(s)
+	v, _ := is.fieldMu.LoadOrStore(field, &sync.RWMutex{})
+	mu := v.(*sync.RWMutex)
+	if write {
+		is.sides.lock(sideFieldWriters)
+		defer is.sides.unlock()
+		mu.Lock()
+		defer mu.Unlock()
+	} else {
+		mu.RLock()
+		defer mu.RUnlock()
+	}
+
+	return fn(field)
+}
+
+// Load returns the value of the field.
+func (f *StateField[S, F]) Load(ctx context.Context) (F, error) {
+	var ret F
+	err := f.access(ctx, false, func(field *F) error {
+		ret = *field
+		return nil
+	})
+	return ret, err
+}
+
+// Store sets the value of the field.
+func (f *StateField[S, F]) Store(ctx context.Context, value F) error {
+	err := f.access(ctx, true, func(field *F) error {
+		*field = value
+		return nil
+	})
+	if err == nil {
+		onStateMutated(ctx)
+	}
+	return err
+}
+
+// Update sets the value of the field to the result of fn, with exclusive access to the field.
+// The field will be left unchanged if fn returns an error.
+// fn must not access the state by ProcessState, ProcessStateRead or the same StateField, or it will deadlock.
+func (f *StateField[S, F]) Update(ctx context.Context, fn func(F) (F, error)) error {
+	err := f.access(ctx, true, func(field *F) error {
+		nv, err := fn(*field)
+		if err != nil {
+			return err
+		}
+		*field = nv
+		return nil
+	})
+	if err == nil {
+		onStateMutated(ctx)
+	}
+	return err
+}
+
+// lockState runs fn with the write lock of the state held.
+func lockState[T any](mu *sync.RWMutex, fn func() (T, error)) (T, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return fn()
+}
+
+// onStateMutated emits GraphEventStateMutated with the key of the node running, if any.
+// It must be called after the lock of the state is released, so that the event handlers can access the state.
+func onStateMutated(ctx context.Context) {
+	var nodeKey string
+	addr := GetCurrentAddress(ctx)
+	for i := len(addr) - 1; i >= 0; i-- {
+		if addr[i].Type == AddressSegmentNode {
+			nodeKey = addr[i].ID
+			break
+		}
+	}
+	onGraphEvent(ctx, &callbacks.GraphEvent{Type: callbacks.GraphEventStateMutated, NodeKey: nodeKey})
+}
+
+func getState[S any](ctx context.Context) (S, *sync.RWMutex, error) {
+	s, is, err := getInternalState[S](ctx)
+	if err != nil {
+		return s, nil, err
+	}
+	return s, &is.mu, nil
+}
+
+func getInternalState[S any](ctx context.Context) (S, *internalState, error) {
 	state := ctx.Value(stateKey{})
 
 	if state == nil {
@@ -184,7 +389,7 @@ func getState[S any](ctx context.Context) (S, *sync.Mutex, error) {
 
 	for interState != nil {
 		if cState, ok := interState.state.(S); ok {
-			return cState, &interState.mu, nil
+			return cState, interState, nil
 		}
 		interState = interState.parent
 	}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// StateSnapshot is a deep-copied snapshot of the local state, captured at the end of a super step.
+type StateSnapshot[S any] struct {
+	// Step is the index of the super step, starting from 0.
+	Step  int
+	State S
+}
+
+type stateCaptureKey struct{}
+
+type stateCapture struct {
+	mu sync.Mutex
+
+	captured  bool
+	state     any
+	err       error
+	snapshots []*StateSnapshot[any]
+}
+
+// WithStateCapture returns a context which captures the local state of the graph run with it,
+// so that the state can be inspected by GetState and GetStateSnapshots after the run completes, whether it succeeds or not.
+// Only the state of the top level graph is captured, and it's deep copied, so modifying it won't affect the run.
+// Same as checkpoints, the state type should be registered by schema.RegisterName to be deep copied.
+// e.g.
+//
+//	runCtx := compose.WithStateCapture(ctx)
+//	out, err := runnable.Invoke(runCtx, input, compose.WithStateSnapshotPerStep())
+//	state, err := compose.GetState[*MyState](runCtx)
+//	snapshots, err := compose.GetStateSnapshots[*MyState](runCtx)
+func WithStateCapture(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stateCaptureKey{}, &stateCapture{})
+}
+
+// GetState returns the final local state of the graph run with runCtx, which is created by WithStateCapture.
+func GetState[S any](runCtx context.Context) (S, error) {
+	var s S
+	sc, ok := runCtx.Value(stateCaptureKey{}).(*stateCapture)
+	if !ok {
+		return s, errors.New("state capture is not enabled, use WithStateCapture to create the run context")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.err != nil {
+		return s, fmt.Errorf("capture state fail: %w", sc.err)
+	}
+	if !sc.captured {
+		return s, errors.New("state has not been captured, the run may not have completed or the graph has no local state")
+	}
+	s, ok = sc.state.(S)
+	if !ok {
+		return s, fmt.Errorf("unexpected state type. expected: %v, got: %T", generic.TypeOf[S](), sc.state)
+	}
+	return s, nil
+}
+
+// GetStateSnapshots returns the snapshots of local state captured at the end of each super step of the graph run with runCtx,
+// which is created by WithStateCapture. The snapshots are only captured when the run is called with WithStateSnapshotPerStep.
+func GetStateSnapshots[S any](runCtx context.Context) ([]*StateSnapshot[S], error) {
+	sc, ok := runCtx.Value(stateCaptureKey{}).(*stateCapture)
+	if !ok {
+		return nil, errors.New("state capture is not enabled, use WithStateCapture to create the run context")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	ret := make([]*StateSnapshot[S], 0, len(sc.snapshots))
+	for _, snapshot := range sc.snapshots {
+		s, ok := snapshot.State.(S)
+		if !ok {
+			return nil, fmt.Errorf("unexpected state type. expected: %v, got: %T", generic.TypeOf[S](), snapshot.State)
+		}
+		ret = append(ret, &StateSnapshot[S]{Step: snapshot.Step, State: s})
+	}
+	return ret, nil
+}
+
+// WithStateSnapshotPerStep captures a deep-copied snapshot of local state at the end of each super step,
+// which can be retrieved by GetStateSnapshots after the run.
+// It only takes effect when the run context is created by WithStateCapture.
+func WithStateSnapshotPerStep() Option {
+	return Option{
+		stateSnapshotPerStep: true,
+	}
+}
+
+func getStateCapture(ctx context.Context) *stateCapture {
+	if _, isSubGraph := getNodePath(ctx); isSubGraph {
+		return nil
+	}
+	sc, _ := ctx.Value(stateCaptureKey{}).(*stateCapture)
+	return sc
+}
+
+func copyStateFromCtx(ctx context.Context) (any, bool, error) {
+	is, ok := ctx.Value(stateKey{}).(*internalState)
+	if !ok || is == nil {
+		return nil, false, nil
+	}
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	s, err := deepCopyState(is.state)
+	return s, true, err
+}
+
+func (sc *stateCapture) captureFinal(ctx context.Context) {
+	s, ok, err := copyStateFromCtx(ctx)
+	if !ok {
+		return
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.captured, sc.state, sc.err = true, s, err
+}
+
+func (sc *stateCapture) captureStep(ctx context.Context, step int) {
+	s, ok, err := copyStateFromCtx(ctx)
+	if !ok || err != nil {
+		return
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.snapshots = append(sc.snapshots, &StateSnapshot[any]{Step: step, State: s})
+}
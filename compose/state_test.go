@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -763,3 +764,217 @@ func TestNestedGraphStateConcurrency(t *testing.T) {
 	// Note: This test is primarily validated by running with -race flag
 	// If locks don't work correctly, the race detector will catch it
 }
+
+type counterState struct {
+	Count int
+}
+
+func init() {
+	schema.RegisterName[*counterState]("_eino_test_counter_state")
+}
+
+func TestStateCapture(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) *counterState {
+		return &counterState{}
+	}))
+	incr := InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input, ProcessState[*counterState](ctx, func(_ context.Context, s *counterState) error {
+			s.Count++
+			return nil
+		})
+	})
+	assert.NoError(t, g.AddLambdaNode("1", incr))
+	assert.NoError(t, g.AddLambdaNode("2", incr))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	_, err = GetState[*counterState](ctx)
+	assert.Error(t, err)
+
+	runCtx := WithStateCapture(ctx)
+	_, err = r.Invoke(runCtx, "input", WithStateSnapshotPerStep())
+	assert.NoError(t, err)
+
+	s, err := GetState[*counterState](runCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Count)
+
+	_, err = GetState[string](runCtx)
+	assert.Error(t, err)
+
+	snapshots, err := GetStateSnapshots[*counterState](runCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*StateSnapshot[*counterState]{
+		{Step: 0, State: &counterState{Count: 1}},
+		{Step: 1, State: &counterState{Count: 2}},
+	}, snapshots)
+}
+
+type fieldsState struct {
+	Count int
+	Names []string
+}
+
+func TestProcessStateReadAndStateField(t *testing.T) {
+	ctx := context.Background()
+	countField := NewStateField(func(s *fieldsState) *int { return &s.Count })
+	namesField := NewStateField(func(s *fieldsState) *[]string { return &s.Names })
+
+	// both readers hold the read lock until the other one arrives, which would deadlock with an exclusive lock
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	reader := func(key string) *Lambda {
+		return InvokableLambda(func(ctx context.Context, input string) (int, error) {
+			var count int
+			err := ProcessStateRead[*fieldsState](ctx, func(_ context.Context, s *fieldsState) error {
+				count = s.Count
+				barrier.Done()
+				barrier.Wait()
+				return nil
+			})
+			if err != nil {
+				return 0, err
+			}
+			if err = namesField.Update(ctx, func(names []string) ([]string, error) {
+				return append(names, key), nil
+			}); err != nil {
+				return 0, err
+			}
+			return count, countField.Update(ctx, func(c int) (int, error) {
+				return c + 1, nil
+			})
+		})
+	}
+
+	g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) *fieldsState {
+		return &fieldsState{Count: 1}
+	}))
+	assert.NoError(t, g.AddLambdaNode("1", reader("1"), WithOutputKey("1")))
+	assert.NoError(t, g.AddLambdaNode("2", reader("2"), WithOutputKey("2")))
+	assert.NoError(t, g.AddLambdaNode("3", InvokableLambda(func(ctx context.Context, input map[string]any) (string, error) {
+		names, err := namesField.Load(ctx)
+		if err != nil {
+			return "", err
+		}
+		count, err := countField.Load(ctx)
+		if err != nil {
+			return "", err
+		}
+		if err = countField.Store(ctx, 10); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%v %v %d %d", input["1"], input["2"], len(names), count), nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge(START, "2"))
+	assert.NoError(t, g.AddEdge("1", "3"))
+	assert.NoError(t, g.AddEdge("2", "3"))
+	assert.NoError(t, g.AddEdge("3", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "input")
+	assert.NoError(t, err)
+	assert.Equal(t, "1 1 2 3", out)
+
+	_, err = countField.Load(ctx)
+	assert.Error(t, err)
+}
+
+func TestStateFieldWriteExcludesStateReaders(t *testing.T) {
+	ctx := context.Background()
+	countField := NewStateField(func(s *fieldsState) *int { return &s.Count })
+
+	reading := make(chan struct{})
+	g := NewGraph[string, map[string]any](WithGenLocalState(func(ctx context.Context) *fieldsState {
+		return &fieldsState{Count: 1}
+	}))
+	assert.NoError(t, g.AddLambdaNode("reader", InvokableLambda(func(ctx context.Context, input string) (int, error) {
+		var count int
+		err := ProcessStateRead[*fieldsState](ctx, func(_ context.Context, s *fieldsState) error {
+			close(reading)
+			// the writer is blocked until the reader returns, so the state is not changed while reading
+			time.Sleep(50 * time.Millisecond)
+			count = s.Count
+			return nil
+		})
+		return count, err
+	}), WithOutputKey("reader")))
+	assert.NoError(t, g.AddLambdaNode("writer", InvokableLambda(func(ctx context.Context, input string) (int, error) {
+		<-reading
+		return 0, countField.Store(ctx, 5)
+	}), WithOutputKey("writer")))
+	assert.NoError(t, g.AddEdge(START, "reader"))
+	assert.NoError(t, g.AddEdge(START, "writer"))
+	assert.NoError(t, g.AddEdge("reader", END))
+	assert.NoError(t, g.AddEdge("writer", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "input")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, out["reader"])
+}
+
+func TestStateFieldsUpdatedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	countField := NewStateField(func(s *fieldsState) *int { return &s.Count })
+	namesField := NewStateField(func(s *fieldsState) *[]string { return &s.Names })
+
+	// both updates must be running at the same time to pass the barrier
+	var inside sync.WaitGroup
+	inside.Add(2)
+	barrier := func() error {
+		inside.Done()
+		done := make(chan struct{})
+		go func() {
+			inside.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-time.After(time.Second):
+			return errors.New("updates of different fields are serialized")
+		}
+	}
+
+	g := NewGraph[string, map[string]any](WithGenLocalState(func(ctx context.Context) *fieldsState {
+		return &fieldsState{}
+	}))
+	assert.NoError(t, g.AddLambdaNode("count", InvokableLambda(func(ctx context.Context, input string) (int, error) {
+		err := countField.Update(ctx, func(count int) (int, error) {
+			return count + 1, barrier()
+		})
+		return 0, err
+	}), WithOutputKey("count")))
+	assert.NoError(t, g.AddLambdaNode("names", InvokableLambda(func(ctx context.Context, input string) (int, error) {
+		err := namesField.Update(ctx, func(names []string) ([]string, error) {
+			return append(names, input), barrier()
+		})
+		return 0, err
+	}), WithOutputKey("names")))
+	assert.NoError(t, g.AddLambdaNode("sum", InvokableLambda(func(ctx context.Context, input map[string]any) (string, error) {
+		var out string
+		err := ProcessStateRead[*fieldsState](ctx, func(_ context.Context, s *fieldsState) error {
+			out = fmt.Sprintf("%d %v", s.Count, s.Names)
+			return nil
+		})
+		return out, err
+	}), WithOutputKey("sum")))
+	assert.NoError(t, g.AddEdge(START, "count"))
+	assert.NoError(t, g.AddEdge(START, "names"))
+	assert.NoError(t, g.AddEdge("count", "sum"))
+	assert.NoError(t, g.AddEdge("names", "sum"))
+	assert.NoError(t, g.AddEdge("sum", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "input")
+	assert.NoError(t, err)
+	assert.Equal(t, "1 [input]", out["sum"])
+}
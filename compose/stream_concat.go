@@ -17,37 +17,58 @@
 package compose
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 
 	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
 )
 
-// RegisterStreamChunkConcatFunc registers a function to concat stream chunks.
+// RegisterStreamConcatFunc registers a function to concat stream chunks.
 // It's required when you want to concat stream chunks of a specific type.
 // for example you call Invoke() but node only implements Stream().
 // call at process init
 // not thread safe
+// It panics with ErrDuplicateRegistration if a function has been registered for T, unless WithReplaceRegistration is given,
+// while the built-in functions of the basic types such as string can be replaced once.
+// Use WithGraphStreamConcatFunc or WithStreamConcatFunc to concat differently in a specific graph or node instead.
 // eg.
 //
 //	type testStruct struct {
 //		field1 string
 //		field2 int
 //	}
-//	compose.RegisterStreamChunkConcatFunc(func(items []testStruct) (testStruct, error) {
+//	compose.RegisterStreamConcatFunc(func(items []testStruct) (testStruct, error) {
 //		return testStruct{
 //			field1: items[1].field1, // may implement inplace logic by your scenario
 //			field2: items[0].field2 + items[1].field2,
 //		}, nil
 //	})
+func RegisterStreamConcatFunc[T any](fn func([]T) (T, error), opts ...RegisterOption) {
+	if err := internal.RegisterStreamChunkConcatFunc(fn, getRegisterOptions(opts).replace); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterStreamChunkConcatFunc registers a function to concat stream chunks,
+// replacing the function registered for T before, as it always did.
+//
+// Deprecated: use RegisterStreamConcatFunc instead.
 func RegisterStreamChunkConcatFunc[T any](fn func([]T) (T, error)) {
-	internal.RegisterStreamChunkConcatFunc(fn)
+	RegisterStreamConcatFunc(fn, WithReplaceRegistration())
 }
 
 var emptyStreamConcatErr = errors.New("stream reader is empty, concat fail")
 
-func concatStreamReader[T any](sr *schema.StreamReader[T]) (T, error) {
+// ErrStreamChunksExceeded is returned when the input stream of a node has more chunks than the limit set by WithMaxStreamChunks.
+var ErrStreamChunksExceeded = errors.New("stream chunks exceed the limit")
+
+// readStreamChunks reads all chunks of the stream, which must have at least one chunk.
+func readStreamChunks[T any](sr *schema.StreamReader[T]) ([]T, error) {
 	defer sr.Close()
 
 	var items []T
@@ -63,16 +84,23 @@ func concatStreamReader[T any](sr *schema.StreamReader[T]) (T, error) {
 				continue
 			}
 
-			var t T
-			return t, newStreamReadError(err)
+			return nil, newStreamReadError(err)
 		}
 
 		items = append(items, chunk)
 	}
 
 	if len(items) == 0 {
+		return nil, emptyStreamConcatErr
+	}
+	return items, nil
+}
+
+func concatStreamReader[T any](sr *schema.StreamReader[T]) (T, error) {
+	items, err := readStreamChunks(sr)
+	if err != nil {
 		var t T
-		return t, emptyStreamConcatErr
+		return t, err
 	}
 
 	if len(items) == 1 {
@@ -86,3 +114,68 @@ func concatStreamReader[T any](sr *schema.StreamReader[T]) (T, error) {
 	}
 	return res, nil
 }
+
+// newStreamConcatFunc adapts fn to concatenate the stream of type T into a stream of the single concatenated chunk.
+func newStreamConcatFunc[T any](fn func([]T) (T, error)) func(streamReader) (streamReader, error) {
+	return func(isr streamReader) (streamReader, error) {
+		sr, ok := unpackStreamReader[T](isr)
+		if !ok {
+			return nil, fmt.Errorf("unexpected stream chunk type: %v, expected: %v", isr.getChunkType(), generic.TypeOf[T]())
+		}
+		items, err := readStreamChunks(sr)
+		if err != nil {
+			return nil, err
+		}
+		v, err := fn(items)
+		if err != nil {
+			return nil, fmt.Errorf("concat stream chunks fail: %w", err)
+		}
+		return packStreamReader(schema.StreamReaderFromArray([]T{v})), nil
+	}
+}
+
+type streamConcatOptions struct {
+	maxChunks int
+
+	concatType reflect.Type
+	concat     func(streamReader) (streamReader, error)
+}
+
+// streamConcatComposableRunnable applies the options to the input stream of the node before it's concatenated automatically.
+func streamConcatComposableRunnable(sc *streamConcatOptions, r *composableRunnable) (*composableRunnable, error) {
+	if sc.concat != nil && sc.concatType != r.inputType {
+		return nil, fmt.Errorf("stream concat func type[%v] mismatches node input type[%v]", sc.concatType, r.inputType)
+	}
+	if !r.paradigms.concatsInputWhenStreaming() {
+		return r, nil
+	}
+
+	wrapper := *r
+	t := r.t
+	wrapper.t = func(ctx context.Context, input streamReader, opts ...any) (streamReader, error) {
+		if sc.maxChunks > 0 {
+			input = input.withMaxChunks(sc.maxChunks)
+		}
+		if sc.concat != nil {
+			var err error
+			input, err = sc.concat(input)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return t(ctx, input, opts...)
+	}
+	return &wrapper, nil
+}
+
+func limitStreamChunks[T any](sr *schema.StreamReader[T], n int) *schema.StreamReader[T] {
+	count := 0
+	return schema.StreamReaderWithConvert(sr, func(chunk T) (T, error) {
+		count++
+		if count > n {
+			var zero T
+			return zero, fmt.Errorf("%w: %d", ErrStreamChunksExceeded, n)
+		}
+		return chunk, nil
+	})
+}
@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"testing"
@@ -216,3 +217,137 @@ func TestConcatSliceValue(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, testStruct{}, result)
 }
+
+type tNodeConcatEvent struct {
+	seq int
+}
+
+func TestNodeStreamConcatOptions(t *testing.T) {
+	ctx := context.Background()
+	build := func(opts ...GraphAddNodeOpt) (Runnable[int, int], error) {
+		g := NewGraph[int, int]()
+		_ = g.AddLambdaNode("produce", StreamableLambda(func(ctx context.Context, n int) (*schema.StreamReader[*tNodeConcatEvent], error) {
+			events := make([]*tNodeConcatEvent, n)
+			for i := range events {
+				events[i] = &tNodeConcatEvent{seq: i}
+			}
+			return schema.StreamReaderFromArray(events), nil
+		}))
+		_ = g.AddLambdaNode("consume", InvokableLambda(func(ctx context.Context, e *tNodeConcatEvent) (int, error) {
+			return e.seq, nil
+		}), opts...)
+		_ = g.AddEdge(START, "produce")
+		_ = g.AddEdge("produce", "consume")
+		_ = g.AddEdge("consume", END)
+		return g.Compile(ctx)
+	}
+	latest := WithStreamConcatFunc(func(items []*tNodeConcatEvent) (*tNodeConcatEvent, error) {
+		return items[len(items)-1], nil
+	})
+
+	run := func(r Runnable[int, int], n int) (int, error) {
+		// the input stream of consume is concatenated only in stream mode
+		sr, err := r.Stream(ctx, n)
+		if err != nil {
+			return 0, err
+		}
+		return concatStreamReader(sr)
+	}
+
+	// no concat func registered for the type
+	r, err := build()
+	assert.NoError(t, err)
+	_, err = run(r, 3)
+	assert.Error(t, err)
+
+	r, err = build(latest)
+	assert.NoError(t, err)
+	out, err := run(r, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, out)
+
+	r, err = build(latest, WithMaxStreamChunks(3))
+	assert.NoError(t, err)
+	out, err = run(r, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, out)
+	_, err = run(r, 4)
+	assert.ErrorIs(t, err, ErrStreamChunksExceeded)
+
+	_, err = build(WithStreamConcatFunc(func(items []string) (string, error) { return "", nil }))
+	assert.ErrorContains(t, err, "mismatches node input type")
+}
+
+type tDupRegistryForTest struct{ v int }
+
+func TestRegisterStreamConcatFuncConflict(t *testing.T) {
+	newFn := func(last bool) func(items []tDupRegistryForTest) (tDupRegistryForTest, error) {
+		return func(items []tDupRegistryForTest) (tDupRegistryForTest, error) {
+			if last {
+				return items[len(items)-1], nil
+			}
+			return items[0], nil
+		}
+	}
+	RegisterStreamConcatFunc(newFn(true), WithReplaceRegistration())
+	// the closures from the same function literal are different functions, replaced only when opted in
+	assert.NotPanics(t, func() { RegisterStreamConcatFunc(newFn(false), WithReplaceRegistration()) })
+
+	defer func() {
+		err, ok := recover().(error)
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, ErrDuplicateRegistration)
+
+		// the first registration is kept
+		v, err := internal.ConcatItems([]tDupRegistryForTest{{v: 1}, {v: 2}})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v.v)
+	}()
+	RegisterStreamConcatFunc(newFn(true))
+}
+
+func TestGraphStreamConcatFunc(t *testing.T) {
+	ctx := context.Background()
+	run := func(opts ...GraphAddNodeOpt) (int, error) {
+		g := NewGraph[int, int]()
+		_ = g.AddLambdaNode("produce", StreamableLambda(func(ctx context.Context, n int) (*schema.StreamReader[*tNodeConcatEvent], error) {
+			events := make([]*tNodeConcatEvent, n)
+			for i := range events {
+				events[i] = &tNodeConcatEvent{seq: i}
+			}
+			return schema.StreamReaderFromArray(events), nil
+		}))
+		_ = g.AddLambdaNode("consume", InvokableLambda(func(ctx context.Context, e *tNodeConcatEvent) (int, error) {
+			return e.seq, nil
+		}), opts...)
+		_ = g.AddEdge(START, "produce")
+		_ = g.AddEdge("produce", "consume")
+		_ = g.AddEdge("consume", END)
+
+		r, err := g.Compile(ctx, WithGraphStreamConcatFunc(func(items []*tNodeConcatEvent) (*tNodeConcatEvent, error) {
+			return items[0], nil
+		}))
+		if err != nil {
+			return 0, err
+		}
+		sr, err := r.Stream(ctx, 3)
+		if err != nil {
+			return 0, err
+		}
+		return concatStreamReader(sr)
+	}
+
+	out, err := run()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, out)
+
+	// the node's own concat func takes precedence
+	out, err = run(WithStreamConcatFunc(func(items []*tNodeConcatEvent) (*tNodeConcatEvent, error) {
+		return items[len(items)-1], nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, out)
+
+	_, err = run(WithMaxStreamChunks(2))
+	assert.ErrorIs(t, err, ErrStreamChunksExceeded)
+}
@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/cloudwego/eino/internal/generic"
@@ -32,6 +33,11 @@ type streamReader interface {
 	close()
 	toAnyStreamReader() *schema.StreamReader[any]
 	mergeWithNames([]streamReader, []string) streamReader
+	withCancelReason(context.Context, *runCancel) streamReader
+	withChunkMeta(origin string) streamReader
+	withMaxChunks(n int) streamReader
+	withRecvHook(hook func()) streamReader
+	withDoneHook(hook func(err error)) streamReader
 }
 
 type streamReaderPacker[T any] struct {
@@ -102,6 +108,26 @@ func (srp streamReaderPacker[T]) withKey(key string) streamReader {
 	return packStreamReader(ret)
 }
 
+func (srp streamReaderPacker[T]) withCancelReason(ctx context.Context, rc *runCancel) streamReader {
+	return packStreamReader(streamWithCancelReason(ctx, rc, srp.sr))
+}
+
+func (srp streamReaderPacker[T]) withChunkMeta(origin string) streamReader {
+	return packStreamReader(schema.StreamReaderWithChunkMeta(srp.sr, origin))
+}
+
+func (srp streamReaderPacker[T]) withMaxChunks(n int) streamReader {
+	return packStreamReader(limitStreamChunks(srp.sr, n))
+}
+
+func (srp streamReaderPacker[T]) withRecvHook(hook func()) streamReader {
+	return packStreamReader(streamWithRecvHook(srp.sr, hook))
+}
+
+func (srp streamReaderPacker[T]) withDoneHook(hook func(err error)) streamReader {
+	return packStreamReader(streamWithDoneHook(srp.sr, hook))
+}
+
 func (srp streamReaderPacker[T]) toAnyStreamReader() *schema.StreamReader[any] {
 	return schema.StreamReaderWithConvert(srp.sr, func(t T) (any, error) {
 		return t, nil
@@ -17,6 +17,8 @@
 package compose
 
 import (
+	"context"
+	"errors"
 	"io"
 	"reflect"
 	"testing"
@@ -100,3 +102,43 @@ func TestArrayStreamMerge(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamChunkMeta(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, map[string]any]()
+	split := func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{in, in}), nil
+	}
+	assert.NoError(t, g.AddLambdaNode("a", StreamableLambda(split), WithOutputKey("a")))
+	assert.NoError(t, g.AddLambdaNode("b", StreamableLambda(split), WithOutputKey("b")))
+	assert.NoError(t, g.AddEdge(START, "a"))
+	assert.NoError(t, g.AddEdge(START, "b"))
+	assert.NoError(t, g.AddEdge("a", END))
+	assert.NoError(t, g.AddEdge("b", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	sr, err := r.Stream(ctx, "x", WithStreamChunkMeta())
+	assert.NoError(t, err)
+	defer sr.Close()
+	seqs := map[string][]int64{}
+	for {
+		_, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		meta := sr.ChunkMeta()
+		if assert.NotNil(t, meta) {
+			seqs[meta.Origin] = append(seqs[meta.Origin], meta.Seq)
+		}
+	}
+	assert.Equal(t, map[string][]int64{"a": {0, 1}, "b": {0, 1}}, seqs)
+
+	sr, err = r.Stream(ctx, "x")
+	assert.NoError(t, err)
+	_, err = sr.Recv()
+	assert.NoError(t, err)
+	assert.Nil(t, sr.ChunkMeta())
+	sr.Close()
+}
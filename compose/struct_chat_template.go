@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+// templateTagName is the struct tag to name the template variable of a field, "-" to skip the field.
+const templateTagName = "template"
+
+// StructChatTemplate creates a Lambda formatting tpl with the exported fields of the struct T as the variables,
+// so that no lambda is needed to build the map[string]any before a chat template node.
+// The variable name of a field is its name, or the name in the `template` tag, and `template:"-"` skips the field.
+// The fields of the embedded structs without tag are promoted like encoding/json.
+// T can be a struct or a pointer to struct.
+// If tpl implements `TemplateVariables() ([]string, error)` like prompt.DefaultChatTemplate,
+// the variables required by it are checked to be covered by the fields when creating the Lambda.
+// e.g.
+//
+//	type Query struct {
+//		Name     string `template:"name"`
+//		Question string `template:"question"`
+//	}
+//
+//	tpl := prompt.FromMessages(schema.FString, schema.UserMessage("{name} asks: {question}"))
+//	lambda, err := compose.StructChatTemplate[*Query](tpl)
+//	chain := compose.NewChain[*Query, []*schema.Message]().AppendLambda(lambda)
+func StructChatTemplate[T any](tpl prompt.ChatTemplate, opts ...LambdaOpt) (*Lambda, error) {
+	typ := generic.TypeOf[T]()
+	structType := typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("struct chat template input type[%s] is neither a struct nor a pointer to struct", typ)
+	}
+
+	fields := templateFields(structType)
+
+	if lister, ok := tpl.(interface{ TemplateVariables() ([]string, error) }); ok {
+		vars, err := lister.TemplateVariables()
+		if err != nil && !errors.Is(err, schema.ErrTemplateVariablesUnsupported) {
+			return nil, fmt.Errorf("get template variables fail: %w", err)
+		}
+		var missing []string
+		for _, v := range vars {
+			if _, ok := fields[v]; !ok {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("template variables %v are not covered by the fields of input type[%s]", missing, typ)
+		}
+	}
+
+	i := func(ctx context.Context, input T, opts ...prompt.Option) ([]*schema.Message, error) {
+		return tpl.Format(ctx, structToTemplateVariables(reflect.ValueOf(input), fields), opts...)
+	}
+
+	lambdaType := "StructChatTemplate"
+	if t, ok := components.GetType(tpl); ok {
+		lambdaType = t + lambdaType
+	}
+	opts = append([]LambdaOpt{
+		WithLambdaType(lambdaType),
+		WithLambdaCallbackEnable(components.IsCallbacksEnabled(tpl)),
+	}, opts...)
+
+	return InvokableLambdaWithOption(i, opts...), nil
+}
+
+// templateFields returns the index paths of the fields by variable name.
+func templateFields(structType reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(structType) {
+		tag, hasTag := f.Tag.Lookup(templateTagName)
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && !hasTag {
+			// promoted fields are visited separately
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		if old, ok := fields[name]; ok && len(old) <= len(f.Index) {
+			// the shallower field wins
+			continue
+		}
+		fields[name] = f.Index
+	}
+	return fields
+}
+
+func structToTemplateVariables(v reflect.Value, fields map[string][]int) map[string]any {
+	vs := make(map[string]any, len(fields))
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return vs
+		}
+		v = v.Elem()
+	}
+	for name, index := range fields {
+		fv, err := v.FieldByIndexErr(index)
+		if err != nil {
+			// through a nil embedded pointer
+			continue
+		}
+		vs[name] = fv.Interface()
+	}
+	return vs
+}
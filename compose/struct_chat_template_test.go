@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+type structTemplateBase struct {
+	Lang string `template:"lang"`
+}
+
+type structTemplateInput struct {
+	*structTemplateBase
+	Name     string `template:"name"`
+	Question string
+	Secret   string            `template:"-"`
+	History  []*schema.Message `template:"history"`
+	internal string
+}
+
+func TestStructChatTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	tpl := prompt.FromMessages(schema.FString,
+		schema.SystemMessage("answer in {lang}"),
+		schema.MessagesPlaceholder("history", true),
+		schema.UserMessage("{name} asks: {Question}"))
+
+	l, err := StructChatTemplate[*structTemplateInput](tpl)
+	require.NoError(t, err)
+	r, err := NewChain[*structTemplateInput, []*schema.Message]().AppendLambda(l).Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, &structTemplateInput{
+		structTemplateBase: &structTemplateBase{Lang: "English"},
+		Name:               "eino",
+		Question:           "what is a graph?",
+		History:            []*schema.Message{schema.AssistantMessage("hi", nil)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*schema.Message{
+		schema.SystemMessage("answer in English"),
+		schema.AssistantMessage("hi", nil),
+		schema.UserMessage("eino asks: what is a graph?"),
+	}, out)
+
+	_, err = StructChatTemplate[structTemplateInput](prompt.FromMessages(schema.FString,
+		schema.UserMessage("{name} {Secret} {internal}")))
+	assert.ErrorContains(t, err, "[Secret internal]")
+
+	_, err = StructChatTemplate[structTemplateInput](prompt.FromMessages(schema.GoTemplate,
+		schema.UserMessage("{{.name}}{{range .history}}{{.Content}}{{end}}{{if .missing}}x{{end}}")))
+	assert.ErrorContains(t, err, "[missing]")
+
+	_, err = StructChatTemplate[structTemplateInput](prompt.FromMessages(schema.Jinja2,
+		schema.UserMessage("{{missing}}")))
+	assert.NoError(t, err)
+
+	_, err = StructChatTemplate[string](tpl)
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrInvalidToolArguments is the error passed to ArgumentsRepairer, and returned if the repaired arguments are still invalid.
+var ErrInvalidToolArguments = errors.New("invalid tool arguments")
+
+// ArgumentsRepairer repairs the arguments of a tool call generated by the model, when they are not valid JSON,
+// or miss some parameters required by the tool, which is a common cause of agent run failures.
+// cause describes what's wrong with the arguments. The repaired arguments are validated again before the tool is called.
+// An error returned by the repairer fails the tool call as usual.
+type ArgumentsRepairer func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error)
+
+const argumentsRepairSystemPrompt = `You fix the malformed JSON arguments of a tool call.
+Reply with the corrected JSON object only, keeping the values of the original arguments as much as possible.`
+
+// NewChatModelArgumentsRepairer creates an ArgumentsRepairer asking the chat model, usually a small and fast one,
+// to correct the arguments according to the parameters of the tool.
+// e.g.
+//
+//	conf := &compose.ToolsNodeConfig{
+//		Tools:             tools,
+//		ArgumentsRepairer: compose.NewChatModelArgumentsRepairer(smallModel),
+//	}
+func NewChatModelArgumentsRepairer(cm model.BaseChatModel) ArgumentsRepairer {
+	return func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error) {
+		params := "{}"
+		if s, err := info.ParamsOneOf.ToJSONSchema(); err == nil && s != nil {
+			if b, err_ := sonic.MarshalString(s); err_ == nil {
+				params = b
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString("Tool: " + info.Name + "\n")
+		if info.Desc != "" {
+			sb.WriteString("Description: " + info.Desc + "\n")
+		}
+		sb.WriteString("Parameters JSON schema: " + params + "\n")
+		sb.WriteString("Error: " + cause.Error() + "\n")
+		sb.WriteString("Arguments: " + rawArgs)
+
+		out, err := cm.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(argumentsRepairSystemPrompt),
+			schema.UserMessage(sb.String()),
+		})
+		if err != nil {
+			return "", fmt.Errorf("generate repaired arguments fail: %w", err)
+		}
+		return model.TrimCodeFence(out.Content), nil
+	}
+}
+
+// validateToolArguments checks that the arguments are valid JSON, and contain the parameters required by the tool.
+func validateToolArguments(info *schema.ToolInfo, arguments string) error {
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+
+	var v any
+	if err := sonic.UnmarshalString(arguments, &v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolArguments, err)
+	}
+
+	if info == nil || info.ParamsOneOf == nil {
+		return nil
+	}
+	s, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil || s == nil {
+		return nil
+	}
+
+	args, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: expected a JSON object, got: %s", ErrInvalidToolArguments, arguments)
+	}
+	var missing []string
+	for _, name := range s.Required {
+		if _, ok = args[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing required parameters: %s", ErrInvalidToolArguments, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// repairToolArguments validates the arguments, and repairs them by repairer if they are invalid.
+func repairToolArguments(ctx context.Context, repairer ArgumentsRepairer, info *schema.ToolInfo, arguments string) (string, error) {
+	cause := validateToolArguments(info, arguments)
+	if cause == nil {
+		return arguments, nil
+	}
+
+	repaired, err := repairer(ctx, info, arguments, cause)
+	if err != nil {
+		return "", fmt.Errorf("repair arguments fail: %w", err)
+	}
+	if err = validateToolArguments(info, repaired); err != nil {
+		return "", fmt.Errorf("repaired arguments are still invalid: %w", err)
+	}
+	return repaired, nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// HiddenParamResolver resolves the value of a hidden tool parameter at execution time.
+// Hidden parameters (user ID, auth token, workspace, etc.) are not part of the schema shown to the model,
+// so their values never pass through the prompt; ToolsNode fills them into the tool call arguments instead.
+type HiddenParamResolver func(ctx context.Context) (any, error)
+
+// HiddenParamFromContext resolves a hidden parameter from ctx.Value(key).
+// An error is returned if the key is absent from the context.
+func HiddenParamFromContext(key any) HiddenParamResolver {
+	return func(ctx context.Context) (any, error) {
+		v := ctx.Value(key)
+		if v == nil {
+			return nil, fmt.Errorf("hidden param not found in context, key: %v", key)
+		}
+		return v, nil
+	}
+}
+
+// HiddenParamFromState resolves a hidden parameter from the graph state.
+// The state is accessed with a read lock, so get must not modify it.
+// e.g.
+//
+//	HiddenParamFromState(func(s *MyState) (any, error) {
+//		return s.UserID, nil
+//	})
+func HiddenParamFromState[S any](get func(S) (any, error)) HiddenParamResolver {
+	return func(ctx context.Context) (any, error) {
+		var v any
+		err := ProcessStateRead[S](ctx, func(_ context.Context, s S) error {
+			var err error
+			v, err = get(s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// injectHiddenParams resolves the hidden parameters and writes them into the JSON arguments.
+// Values resolved here always override whatever the model has generated under the same name,
+// so a model can not spoof a hidden parameter.
+func injectHiddenParams(ctx context.Context, arguments string, params map[string]HiddenParamResolver) (string, error) {
+	args := map[string]any{}
+	if len(arguments) > 0 {
+		if err := sonic.UnmarshalString(arguments, &args); err != nil {
+			return "", fmt.Errorf("unmarshal arguments fail: %w", err)
+		}
+		if args == nil {
+			args = map[string]any{}
+		}
+	}
+
+	for name, resolve := range params {
+		v, err := resolve(ctx)
+		if err != nil {
+			return "", fmt.Errorf("resolve hidden param[%s] fail: %w", name, err)
+		}
+		args[name] = v
+	}
+
+	return sonic.MarshalString(args)
+}
+
+// withHiddenParams injects the hidden params into the arguments right before the tool runs,
+// i.e. inside the tool call middlewares and callbacks, so that none of them sees the values.
+func withHiddenParams[O any](run Invoke[string, O, tool.Option], params map[string]HiddenParamResolver) Invoke[string, O, tool.Option] {
+	if len(params) == 0 {
+		return run
+	}
+	return func(ctx context.Context, arguments string, opts ...tool.Option) (O, error) {
+		arguments, err := injectHiddenParams(ctx, arguments, params)
+		if err != nil {
+			var o O
+			return o, fmt.Errorf("failed to inject hidden params: %w", err)
+		}
+		return run(ctx, arguments, opts...)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolNamespaceSeparator joins the namespace and the original name of a namespaced tool.
+// It's allowed in the function names of the mainstream model providers.
+const ToolNamespaceSeparator = "__"
+
+// NamespacedTools prefixes the names of the tools with the namespace, to avoid name collisions when combining tools from
+// multiple sources, e.g. MCP servers, OpenAPI specs and local tools.
+// The model sees the prefixed names, e.g. "github__search", and the ToolsNode dispatches the tool calls by them,
+// while the tools run as they are, keeping their original names, types and callbacks.
+// The tools are returned as they are if the namespace is empty.
+// e.g.
+//
+//	conf := &ToolsNodeConfig{
+//		Tools: append(NamespacedTools("github", githubTools...), NamespacedTools("jira", jiraTools...)...),
+//	}
+func NamespacedTools(namespace string, tools ...tool.BaseTool) []tool.BaseTool {
+	if namespace == "" {
+		return tools
+	}
+
+	ret := make([]tool.BaseTool, len(tools))
+	for i, t := range tools {
+		nt := &namespacedTool{namespace: namespace, tool: t}
+		it, isInvokable := t.(tool.InvokableTool)
+		st, isStreamable := t.(tool.StreamableTool)
+		if at, ok := t.(tool.AttachmentTool); ok {
+			// ToolsNode runs attachment tools in the attachment way only
+			ret[i] = &namespacedAttachmentTool{namespacedTool: nt, at: at}
+			continue
+		}
+		switch {
+		case isInvokable && isStreamable:
+			ret[i] = &namespacedInvokableStreamableTool{namespacedTool: nt, it: it, st: st}
+		case isInvokable:
+			ret[i] = &namespacedInvokableTool{namespacedTool: nt, it: it}
+		case isStreamable:
+			ret[i] = &namespacedStreamableTool{namespacedTool: nt, st: st}
+		default:
+			ret[i] = nt
+		}
+	}
+	return ret
+}
+
+// SplitToolName splits the name of a namespaced tool into its namespace and original name.
+// The namespace is empty if the name isn't namespaced.
+func SplitToolName(name string) (namespace, original string) {
+	idx := strings.Index(name, ToolNamespaceSeparator)
+	if idx <= 0 {
+		return "", name
+	}
+	return name[:idx], name[idx+len(ToolNamespaceSeparator):]
+}
+
+type namespacedTool struct {
+	namespace string
+	tool      tool.BaseTool
+}
+
+func (n *namespacedTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	info, err := n.tool.Info(ctx)
+	if err != nil || info == nil {
+		return info, err
+	}
+	cp := *info
+	cp.Name = n.namespace + ToolNamespaceSeparator + info.Name
+	return &cp, nil
+}
+
+func (n *namespacedTool) GetType() string {
+	typ, _ := components.GetType(n.tool)
+	return typ
+}
+
+func (n *namespacedTool) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(n.tool)
+}
+
+type namespacedInvokableTool struct {
+	*namespacedTool
+	it tool.InvokableTool
+}
+
+func (n *namespacedInvokableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return n.it.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+type namespacedAttachmentTool struct {
+	*namespacedTool
+	at tool.AttachmentTool
+}
+
+func (n *namespacedAttachmentTool) InvokableRunWithAttachments(ctx context.Context, argumentsInJSON string,
+	attachments []schema.Attachment, opts ...tool.Option) (*tool.AttachmentResult, error) {
+	return n.at.InvokableRunWithAttachments(ctx, argumentsInJSON, attachments, opts...)
+}
+
+type namespacedStreamableTool struct {
+	*namespacedTool
+	st tool.StreamableTool
+}
+
+func (n *namespacedStreamableTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	return n.st.StreamableRun(ctx, argumentsInJSON, opts...)
+}
+
+type namespacedInvokableStreamableTool struct {
+	*namespacedTool
+	it tool.InvokableTool
+	st tool.StreamableTool
+}
+
+func (n *namespacedInvokableStreamableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return n.it.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+func (n *namespacedInvokableStreamableTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	return n.st.StreamableRun(ctx, argumentsInJSON, opts...)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestNamespacedTools(t *testing.T) {
+	ctx := context.Background()
+
+	search := func(source string) tool.BaseTool {
+		return newTool(&schema.ToolInfo{Name: "search", Desc: "search in " + source}, func(ctx context.Context, in *registryToolInput) (string, error) {
+			return source + ":" + in.V, nil
+		})
+	}
+
+	_, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools: []tool.BaseTool{search("github"), search("jira")},
+	})
+	assert.ErrorContains(t, err, "tool name search collides at idx= 0 and idx= 1")
+
+	tools := append(NamespacedTools("github", search("github")), NamespacedTools("jira", search("jira"))...)
+	tools = append(tools, NamespacedTools("", search("local"))...)
+
+	info, err := tools[0].Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "github__search", info.Name)
+	assert.Equal(t, "search in github", info.Desc)
+	_, ok := tools[0].(tool.InvokableTool)
+	assert.True(t, ok)
+	_, ok = tools[0].(tool.StreamableTool)
+	assert.False(t, ok)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: tools})
+	assert.NoError(t, err)
+
+	out, err := tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "github__search", Arguments: `{"v":"x"}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "jira__search", Arguments: `{"v":"y"}`}},
+		{ID: "3", Function: schema.FunctionCall{Name: "search", Arguments: `{"v":"z"}`}},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, `"github:x"`, out[0].Content)
+	assert.Equal(t, "github__search", out[0].ToolName)
+	assert.Equal(t, `"jira:y"`, out[1].Content)
+	assert.Equal(t, `"local:z"`, out[2].Content)
+
+	ns, name := SplitToolName("github__search")
+	assert.Equal(t, "github", ns)
+	assert.Equal(t, "search", name)
+	ns, name = SplitToolName("search")
+	assert.Equal(t, "", ns)
+	assert.Equal(t, "search", name)
+}
@@ -22,17 +22,21 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/safe"
 	"github.com/cloudwego/eino/schema"
 )
 
 type toolsNodeOptions struct {
-	ToolOptions []tool.Option
-	ToolList    []tool.BaseTool
+	ToolOptions       []tool.Option
+	ToolList          []tool.BaseTool
+	ToolOptionsByName map[string][]tool.Option
+	Attachments       []schema.Attachment
 }
 
 // ToolsNodeOption is the option func type for ToolsNode.
@@ -45,6 +49,31 @@ func WithToolOption(opts ...tool.Option) ToolsNodeOption {
 	}
 }
 
+// WithToolOptionFor adds tool options only for the tool of the name in the ToolsNode, after the ones added by WithToolOption.
+// As the call options of the ToolsNode without designated nodes are passed to the nested graphs as well,
+// it also applies to the tools of the name inside the agents nested in the graph.
+// e.g.
+//
+//	out, err := runnable.Invoke(ctx, input, compose.WithToolsNodeOption(
+//		compose.WithToolOptionFor("search", search.WithTopK(5)),
+//	))
+func WithToolOptionFor(toolName string, opts ...tool.Option) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		if o.ToolOptionsByName == nil {
+			o.ToolOptionsByName = make(map[string][]tool.Option)
+		}
+		o.ToolOptionsByName[toolName] = append(o.ToolOptionsByName[toolName], opts...)
+	}
+}
+
+// WithAttachments passes the attachments to the tools implementing tool.AttachmentTool in the ToolsNode,
+// after the ones carried by the input message.
+func WithAttachments(attachments ...schema.Attachment) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		o.Attachments = append(o.Attachments, attachments...)
+	}
+}
+
 // WithToolList sets the tool list for the ToolsNode.
 func WithToolList(tool ...tool.BaseTool) ToolsNodeOption {
 	return func(o *toolsNodeOptions) {
@@ -65,8 +94,14 @@ type ToolsNode struct {
 	unknownToolHandler        func(ctx context.Context, name, input string) (string, error)
 	executeSequentially       bool
 	toolArgumentsHandler      func(ctx context.Context, name, input string) (string, error)
+	hiddenParams              map[string]map[string]HiddenParamResolver
+	argumentsRepairer         ArgumentsRepairer
+	toolTimeout               time.Duration
+	toolTimeouts              map[string]time.Duration
 	toolCallMiddlewares       []InvokableToolMiddleware
 	streamToolCallMiddlewares []StreamableToolMiddleware
+	rateLimiter               RateLimiter
+	registry                  *registryTuple
 }
 
 // ToolInput represents the input parameters for a tool call execution.
@@ -79,18 +114,24 @@ type ToolInput struct {
 	CallID string
 	// CallOptions contains tool options for the execution.
 	CallOptions []tool.Option
+	// Attachments contains the attachments passed to the tool, only consumed by tool.AttachmentTool.
+	Attachments []schema.Attachment
 }
 
 // ToolOutput represents the result of a non-streaming tool call execution.
 type ToolOutput struct {
 	// Result contains the string output from the tool execution.
 	Result string
+	// Attachments contains the attachments produced by the tool, which are put on the tool message.
+	Attachments []schema.Attachment
 }
 
 // StreamToolOutput represents the result of a streaming tool call execution.
 type StreamToolOutput struct {
 	// Result is a stream reader that provides access to the tool's streaming output.
 	Result *schema.StreamReader[string]
+	// Attachments contains the attachments produced by the tool, which are put on the first chunk of the tool message.
+	Attachments []schema.Attachment
 }
 
 type InvokableToolEndpoint func(ctx context.Context, input *ToolInput) (*ToolOutput, error)
@@ -149,11 +190,43 @@ type ToolsNodeConfig struct {
 	//   - error: Any error that occurred during preprocessing
 	ToolArgumentsHandler func(ctx context.Context, name, arguments string) (string, error)
 
+	// HiddenParams declares parameters that are injected by ToolsNode at execution time, keyed by tool name and then by parameter name.
+	// These parameters should be excluded from the tool's model-facing schema (see utils.WithHiddenParams),
+	// and their values are resolved from context or graph state, e.g. HiddenParamFromContext, HiddenParamFromState.
+	// Hidden params are injected right before the tool runs, i.e. after ToolArgumentsHandler, ArgumentsRepairer,
+	// the tool call middlewares and the callbacks of ToolsNode, none of which sees their values,
+	// and override any value generated by the model under the same name.
+	HiddenParams map[string]map[string]HiddenParamResolver
+
+	// ArgumentsRepairer repairs the arguments of a tool call when they are not valid JSON or miss the required parameters,
+	// before the error is surfaced, e.g. NewChatModelArgumentsRepairer backed by a small model.
+	// This field is optional. When set, the arguments are validated after ToolArgumentsHandler and before HiddenParams are injected.
+	ArgumentsRepairer ArgumentsRepairer
+
+	// ToolTimeout is the timeout of each tool call, and ToolTimeouts overrides it for the tools by name, zero means no timeout.
+	// When a tool call with a timeout exceeds it or the context is canceled, ToolsNode stops waiting for it,
+	// and outputs a tool message telling so instead of failing the other tool calls,
+	// whose status can be checked by GetToolCallStatus, e.g. ToolCallStatusTimeout.
+	// The context of the tool call is canceled on timeout, except that of a streaming tool, whose timeout only bounds the time until its stream is returned.
+	// The tool keeps running after the timeout until it returns, e.g. if it ignores the canceled context, with its result discarded.
+	ToolTimeout  time.Duration
+	ToolTimeouts map[string]time.Duration
+
 	// ToolCallMiddlewares configures middleware for tool calls.
 	// Each element can contain Invokable and/or Streamable middleware.
 	// Invokable middleware only applies to tools implementing InvokableTool interface.
 	// Streamable middleware only applies to tools implementing StreamableTool interface.
 	ToolCallMiddlewares []ToolMiddleware
+
+	// RateLimiter is waited for before each tool call, e.g. NewTokenBucketLimiter, to enforce a quota shared by the tools.
+	// The timeout of the tool call doesn't include the time waiting for the limiter.
+	// This field is optional, and compose.WithRateLimiter sets it when adding the ToolsNode to a graph.
+	RateLimiter RateLimiter
+
+	// ToolRegistry is consulted at each call for the tools that can be called, instead of the fixed Tools,
+	// so that tools can be added, updated or removed at runtime.
+	// This field is optional, and it can't be set together with Tools.
+	ToolRegistry *ToolRegistry
 }
 
 // NewToolNode creates a new ToolsNode.
@@ -175,7 +248,15 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 		}
 	}
 
-	tuple, err := convTools(ctx, conf.Tools, middlewares, streamMiddlewares)
+	var registry *registryTuple
+	if conf.ToolRegistry != nil {
+		if len(conf.Tools) > 0 {
+			return nil, errors.New("tools and tool registry can't be set together")
+		}
+		registry = &registryTuple{registry: conf.ToolRegistry}
+	}
+
+	tuple, err := convTools(ctx, conf.Tools, middlewares, streamMiddlewares, conf.HiddenParams)
 	if err != nil {
 		return nil, err
 	}
@@ -185,8 +266,14 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 		unknownToolHandler:        conf.UnknownToolsHandler,
 		executeSequentially:       conf.ExecuteSequentially,
 		toolArgumentsHandler:      conf.ToolArgumentsHandler,
+		hiddenParams:              conf.HiddenParams,
+		argumentsRepairer:         conf.ArgumentsRepairer,
+		toolTimeout:               conf.ToolTimeout,
+		toolTimeouts:              conf.ToolTimeouts,
 		toolCallMiddlewares:       middlewares,
 		streamToolCallMiddlewares: streamMiddlewares,
+		rateLimiter:               conf.RateLimiter,
+		registry:                  registry,
 	}, nil
 }
 
@@ -203,21 +290,35 @@ func init() {
 }
 
 type toolsInterruptAndRerunState struct {
-	Input         *schema.Message
-	ExecutedTools map[string]string
-	RerunTools    []string
+	Input               *schema.Message
+	ExecutedTools       map[string]string
+	ExecutedAttachments map[string][]schema.Attachment
+	RerunTools          []string
+}
+
+func (s *toolsInterruptAndRerunState) addExecutedAttachments(callID string, attachments []schema.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+	if s.ExecutedAttachments == nil {
+		s.ExecutedAttachments = make(map[string][]schema.Attachment)
+	}
+	s.ExecutedAttachments[callID] = attachments
 }
 
 type toolsTuple struct {
 	indexes         map[string]int
+	infos           []*schema.ToolInfo
 	meta            []*executorMeta
 	endpoints       []InvokableToolEndpoint
 	streamEndpoints []StreamableToolEndpoint
 }
 
-func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware) (*toolsTuple, error) {
+func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware,
+	hiddenParams map[string]map[string]HiddenParamResolver) (*toolsTuple, error) {
 	ret := &toolsTuple{
 		indexes:         make(map[string]int),
+		infos:           make([]*schema.ToolInfo, len(tools)),
 		meta:            make([]*executorMeta, len(tools)),
 		endpoints:       make([]InvokableToolEndpoint, len(tools)),
 		streamEndpoints: make([]StreamableToolEndpoint, len(tools)),
@@ -230,6 +331,7 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 
 		toolName := tl.Name
 		var (
+			at tool.AttachmentTool
 			st tool.StreamableTool
 			it tool.InvokableTool
 
@@ -241,16 +343,22 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 		)
 
 		meta = parseExecutorInfoFromComponent(components.ComponentOfTool, bt)
+		hidden := hiddenParams[toolName]
 
-		if st, ok = bt.(tool.StreamableTool); ok {
-			streamable = wrapStreamToolCall(st, sms, !meta.isComponentCallbackEnabled)
-		}
+		if at, ok = bt.(tool.AttachmentTool); ok {
+			// the attachments are only exchanged in the invokable way, so it takes precedence over the others
+			invokable = wrapAttachmentToolCall(at, ms, !meta.isComponentCallbackEnabled, hidden)
+		} else {
+			if st, ok = bt.(tool.StreamableTool); ok {
+				streamable = wrapStreamToolCall(st, sms, !meta.isComponentCallbackEnabled, hidden)
+			}
 
-		if it, ok = bt.(tool.InvokableTool); ok {
-			invokable = wrapToolCall(it, ms, !meta.isComponentCallbackEnabled)
+			if it, ok = bt.(tool.InvokableTool); ok {
+				invokable = wrapToolCall(it, ms, !meta.isComponentCallbackEnabled, hidden)
+			}
 		}
 
-		if st == nil && it == nil {
+		if at == nil && st == nil && it == nil {
 			return nil, fmt.Errorf("tool %s is not invokable or streamable", toolName)
 		}
 
@@ -261,7 +369,11 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 			invokable = streamableToInvokable(streamable)
 		}
 
+		if prev, ok := ret.indexes[toolName]; ok {
+			return nil, fmt.Errorf("(NewToolNode) tool name %s collides at idx= %d and idx= %d, use NamespacedTools to distinguish tools from different sources", toolName, prev, idx)
+		}
 		ret.indexes[toolName] = idx
+		ret.infos[idx] = tl
 		ret.meta[idx] = meta
 		ret.endpoints[idx] = invokable
 		ret.streamEndpoints[idx] = streamable
@@ -269,18 +381,20 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 	return ret, nil
 }
 
-func wrapToolCall(it tool.InvokableTool, middlewares []InvokableToolMiddleware, needCallback bool) InvokableToolEndpoint {
+func wrapToolCall(it tool.InvokableTool, middlewares []InvokableToolMiddleware, needCallback bool,
+	hidden map[string]HiddenParamResolver) InvokableToolEndpoint {
 	middleware := func(next InvokableToolEndpoint) InvokableToolEndpoint {
 		for i := len(middlewares) - 1; i >= 0; i-- {
 			next = middlewares[i](next)
 		}
 		return next
 	}
+	run := withHiddenParams[string](it.InvokableRun, hidden)
 	if needCallback {
-		it = &invokableToolWithCallback{it: it}
+		run = invokeWithCallbacks(run)
 	}
 	return middleware(func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
-		result, err := it.InvokableRun(ctx, input.Arguments, input.CallOptions...)
+		result, err := run(ctx, input.Arguments, input.CallOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -288,18 +402,49 @@ func wrapToolCall(it tool.InvokableTool, middlewares []InvokableToolMiddleware,
 	})
 }
 
-func wrapStreamToolCall(st tool.StreamableTool, middlewares []StreamableToolMiddleware, needCallback bool) StreamableToolEndpoint {
+func wrapAttachmentToolCall(at tool.AttachmentTool, middlewares []InvokableToolMiddleware, needCallback bool,
+	hidden map[string]HiddenParamResolver) InvokableToolEndpoint {
+	middleware := func(next InvokableToolEndpoint) InvokableToolEndpoint {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+	return middleware(func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+		var attachments []schema.Attachment
+		run := withHiddenParams[string](func(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+			result, err := at.InvokableRunWithAttachments(ctx, argumentsInJSON, input.Attachments, opts...)
+			if err != nil {
+				return "", err
+			}
+			attachments = result.Attachments
+			return result.Content, nil
+		}, hidden)
+		if needCallback {
+			run = invokeWithCallbacks(run)
+		}
+		result, err := run(ctx, input.Arguments, input.CallOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolOutput{Result: result, Attachments: attachments}, nil
+	})
+}
+
+func wrapStreamToolCall(st tool.StreamableTool, middlewares []StreamableToolMiddleware, needCallback bool,
+	hidden map[string]HiddenParamResolver) StreamableToolEndpoint {
 	middleware := func(next StreamableToolEndpoint) StreamableToolEndpoint {
 		for i := len(middlewares) - 1; i >= 0; i-- {
 			next = middlewares[i](next)
 		}
 		return next
 	}
+	run := Stream[string, string, tool.Option](withHiddenParams[*schema.StreamReader[string]](st.StreamableRun, hidden))
 	if needCallback {
-		st = &streamableToolWithCallback{st: st}
+		run = streamWithCallbacks(run)
 	}
 	return middleware(func(ctx context.Context, input *ToolInput) (*StreamToolOutput, error) {
-		result, err := st.StreamableRun(ctx, input.Arguments, input.CallOptions...)
+		result, err := run(ctx, input.Arguments, input.CallOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -307,30 +452,6 @@ func wrapStreamToolCall(st tool.StreamableTool, middlewares []StreamableToolMidd
 	})
 }
 
-type invokableToolWithCallback struct {
-	it tool.InvokableTool
-}
-
-func (i *invokableToolWithCallback) Info(ctx context.Context) (*schema.ToolInfo, error) {
-	return i.it.Info(ctx)
-}
-
-func (i *invokableToolWithCallback) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
-	return invokeWithCallbacks(i.it.InvokableRun)(ctx, argumentsInJSON, opts...)
-}
-
-type streamableToolWithCallback struct {
-	st tool.StreamableTool
-}
-
-func (s *streamableToolWithCallback) Info(ctx context.Context) (*schema.ToolInfo, error) {
-	return s.st.Info(ctx)
-}
-
-func (s *streamableToolWithCallback) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
-	return streamWithCallbacks(s.st.StreamableRun)(ctx, argumentsInJSON, opts...)
-}
-
 func streamableToInvokable(e StreamableToolEndpoint) InvokableToolEndpoint {
 	return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
 		so, err := e(ctx, input)
@@ -341,7 +462,7 @@ func streamableToInvokable(e StreamableToolEndpoint) InvokableToolEndpoint {
 		if err != nil {
 			return nil, fmt.Errorf("failed to concat StreamableTool output message stream: %w", err)
 		}
-		return &ToolOutput{Result: o}, nil
+		return &ToolOutput{Result: o, Attachments: so.Attachments}, nil
 	}
 }
 
@@ -351,7 +472,7 @@ func invokableToStreamable(e InvokableToolEndpoint) StreamableToolEndpoint {
 		if err != nil {
 			return nil, err
 		}
-		return &StreamToolOutput{Result: schema.StreamReaderFromArray([]string{o.Result})}, nil
+		return &StreamToolOutput{Result: schema.StreamReaderFromArray([]string{o.Result}), Attachments: o.Attachments}, nil
 	}
 }
 
@@ -363,16 +484,25 @@ type toolCallTask struct {
 	name           string
 	arg            string
 	callID         string
+	attachments    []schema.Attachment
+
+	opts        []tool.Option // designated by WithToolOptionFor
+	timeout     time.Duration
+	rateLimiter RateLimiter
 
 	// out
+	status   ToolCallStatus
 	executed bool
 	output   string
 	sOutput  *schema.StreamReader[string]
-	err      error
+	// attachments produced by the tool
+	outputAttachments []schema.Attachment
+	err               error
 }
 
 func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
-	input *schema.Message, executedTools map[string]string, isStream bool) ([]toolCallTask, error) {
+	input *schema.Message, executedTools map[string]string, executedAttachments map[string][]schema.Attachment,
+	isStream bool) ([]toolCallTask, error) {
 
 	if input.Role != schema.Assistant {
 		return nil, fmt.Errorf("expected message role is Assistant, got %s", input.Role)
@@ -392,6 +522,7 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			toolCallTasks[i].arg = toolCall.Function.Arguments
 			toolCallTasks[i].callID = toolCall.ID
 			toolCallTasks[i].executed = true
+			toolCallTasks[i].outputAttachments = executedAttachments[toolCall.ID]
 			if isStream {
 				toolCallTasks[i].sOutput = schema.StreamReaderFromArray([]string{result})
 			} else {
@@ -402,7 +533,7 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 		index, ok := tuple.indexes[toolCall.Function.Name]
 		if !ok {
 			if tn.unknownToolHandler == nil {
-				return nil, fmt.Errorf("tool %s not found in toolsNode indexes", toolCall.Function.Name)
+				return nil, &einoerr.ToolNotFoundError{ToolName: toolCall.Function.Name}
 			}
 			toolCallTasks[i] = newUnknownToolTask(toolCall.Function.Name, toolCall.Function.Arguments, toolCall.ID, tn.unknownToolHandler)
 		} else {
@@ -420,6 +551,13 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			} else {
 				toolCallTasks[i].arg = toolCall.Function.Arguments
 			}
+			if tn.argumentsRepairer != nil {
+				arg, err := repairToolArguments(ctx, tn.argumentsRepairer, tuple.infos[index], toolCallTasks[i].arg)
+				if err != nil {
+					return nil, fmt.Errorf("invalid arguments of tool[name:%s arguments:%s]: %w", toolCall.Function.Name, toolCallTasks[i].arg, err)
+				}
+				toolCallTasks[i].arg = arg
+			}
 		}
 	}
 
@@ -454,51 +592,101 @@ func runToolCallTaskByInvoke(ctx context.Context, task *toolCallTask, opts ...to
 	if task.executed {
 		return
 	}
-	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+	ri := &callbacks.RunInfo{
 		Name:      task.name,
 		Type:      task.meta.componentImplType,
 		Component: task.meta.component,
-	})
+	}
+	setRunMeta(ctx, ri)
+	ctx = callbacks.ReuseHandlers(ctx, ri)
 
 	ctx = setToolCallInfo(ctx, &toolCallInfo{toolCallID: task.callID})
 	ctx = appendToolAddressSegment(ctx, task.name, task.callID)
-	output, err := task.endpoint(ctx, &ToolInput{
-		Name:        task.name,
-		Arguments:   task.arg,
-		CallID:      task.callID,
-		CallOptions: opts,
+	if task.rateLimiter != nil {
+		if err := task.rateLimiter.Wait(ctx); err != nil {
+			task.err = fmt.Errorf("wait for rate limiter of tool[%s] fail: %w", task.name, err)
+			return
+		}
+	}
+	output, status, err := runWithToolTimeout(ctx, task.timeout, true, func(ctx context.Context) (*ToolOutput, error) {
+		return task.endpoint(ctx, &ToolInput{
+			Name:        task.name,
+			Arguments:   task.arg,
+			CallID:      task.callID,
+			CallOptions: appendToolCallOptions(opts, task.opts),
+			Attachments: task.attachments,
+		})
 	})
-	if err != nil {
+	if status != "" {
+		task.status = status
+		task.output = toolCallStatusResult(ctx, status, task.timeout)
+		task.executed = true
+	} else if err != nil {
 		task.err = err
 	} else {
 		task.output = output.Result
+		task.outputAttachments = output.Attachments
 		task.executed = true
 	}
 }
 
 func runToolCallTaskByStream(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
-	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+	ri := &callbacks.RunInfo{
 		Name:      task.name,
 		Type:      task.meta.componentImplType,
 		Component: task.meta.component,
-	})
+	}
+	setRunMeta(ctx, ri)
+	ctx = callbacks.ReuseHandlers(ctx, ri)
 
 	ctx = setToolCallInfo(ctx, &toolCallInfo{toolCallID: task.callID})
 	ctx = appendToolAddressSegment(ctx, task.name, task.callID)
-	output, err := task.streamEndpoint(ctx, &ToolInput{
-		Name:        task.name,
-		Arguments:   task.arg,
-		CallID:      task.callID,
-		CallOptions: opts,
+	if task.rateLimiter != nil {
+		if err := task.rateLimiter.Wait(ctx); err != nil {
+			task.err = fmt.Errorf("wait for rate limiter of tool[%s] fail: %w", task.name, err)
+			return
+		}
+	}
+	output, status, err := runWithToolTimeout(ctx, task.timeout, false, func(ctx context.Context) (*StreamToolOutput, error) {
+		return task.streamEndpoint(ctx, &ToolInput{
+			Name:        task.name,
+			Arguments:   task.arg,
+			CallID:      task.callID,
+			CallOptions: appendToolCallOptions(opts, task.opts),
+			Attachments: task.attachments,
+		})
 	})
-	if err != nil {
+	if status != "" {
+		task.status = status
+		task.sOutput = schema.StreamReaderFromArray([]string{toolCallStatusResult(ctx, status, task.timeout)})
+		task.executed = true
+	} else if err != nil {
 		task.err = err
 	} else {
 		task.sOutput = output.Result
+		task.outputAttachments = output.Attachments
 		task.executed = true
 	}
 }
 
+func joinAttachments(fromInput, fromOption []schema.Attachment) []schema.Attachment {
+	if len(fromOption) == 0 {
+		return fromInput
+	}
+	ret := make([]schema.Attachment, 0, len(fromInput)+len(fromOption))
+	ret = append(ret, fromInput...)
+	return append(ret, fromOption...)
+}
+
+func appendToolCallOptions(opts, designated []tool.Option) []tool.Option {
+	if len(designated) == 0 {
+		return opts
+	}
+	ret := make([]tool.Option, 0, len(opts)+len(designated))
+	ret = append(ret, opts...)
+	return append(ret, designated...)
+}
+
 func sequentialRunToolCall(ctx context.Context,
 	run func(ctx2 context.Context, callTask *toolCallTask, opts ...tool.Option),
 	tasks []toolCallTask, opts ...tool.Option) {
@@ -545,33 +733,53 @@ func parallelRunToolCall(ctx context.Context,
 	wg.Wait()
 }
 
+func (tn *ToolsNode) getTuple(ctx context.Context, opt *toolsNodeOptions) (*toolsTuple, error) {
+	if opt.ToolList != nil {
+		tuple, err := convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares, tn.hiddenParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
+		}
+		return tuple, nil
+	}
+	if tn.registry != nil {
+		return tn.registry.get(ctx, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares, tn.hiddenParams)
+	}
+	return tn.tuple, nil
+}
+
 // Invoke calls the tools and collects the results of invokable tools.
 // it's parallel if there are multiple tool calls in the input message.
 func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 	opts ...ToolsNodeOption) ([]*schema.Message, error) {
 
 	opt := getToolsNodeOptions(opts...)
-	tuple := tn.tuple
-	if opt.ToolList != nil {
-		var err error
-		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
-		}
+	tuple, err := tn.getTuple(ctx, opt)
+	if err != nil {
+		return nil, err
 	}
 
-	var executedTools map[string]string
+	var (
+		executedTools       map[string]string
+		executedAttachments map[string][]schema.Attachment
+	)
 	if wasInterrupted, hasState, tnState := GetInterruptState[*toolsInterruptAndRerunState](ctx); wasInterrupted && hasState {
 		input = tnState.Input
 		if tnState.ExecutedTools != nil {
 			executedTools = tnState.ExecutedTools
 		}
+		executedAttachments = tnState.ExecutedAttachments
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, executedTools, false)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, executedTools, executedAttachments, false)
 	if err != nil {
 		return nil, err
 	}
+	for i := range tasks {
+		tasks[i].opts = opt.ToolOptionsByName[tasks[i].name]
+		tasks[i].timeout = tn.getToolTimeout(tasks[i].name)
+		tasks[i].rateLimiter = tn.rateLimiter
+		tasks[i].attachments = joinAttachments(input.Attachments, opt.Attachments)
+	}
 
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
@@ -614,9 +822,12 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 		if tasks[i].executed {
 			rerunExtra.ExecutedTools[tasks[i].callID] = tasks[i].output
 			rerunState.ExecutedTools[tasks[i].callID] = tasks[i].output
+			rerunState.addExecutedAttachments(tasks[i].callID, tasks[i].outputAttachments)
 		}
 		if len(errs) == 0 {
 			output[i] = schema.ToolMessage(tasks[i].output, tasks[i].callID, schema.WithToolName(tasks[i].name))
+			output[i].Attachments = tasks[i].outputAttachments
+			setToolCallStatus(output[i], tasks[i].status)
 		}
 	}
 	if len(errs) > 0 {
@@ -632,27 +843,33 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 	opts ...ToolsNodeOption) (*schema.StreamReader[[]*schema.Message], error) {
 
 	opt := getToolsNodeOptions(opts...)
-	tuple := tn.tuple
-	if opt.ToolList != nil {
-		var err error
-		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
-		}
+	tuple, err := tn.getTuple(ctx, opt)
+	if err != nil {
+		return nil, err
 	}
 
-	var executedTools map[string]string
+	var (
+		executedTools       map[string]string
+		executedAttachments map[string][]schema.Attachment
+	)
 	if wasInterrupted, hasState, tnState := GetInterruptState[*toolsInterruptAndRerunState](ctx); wasInterrupted && hasState {
 		input = tnState.Input
 		if tnState.ExecutedTools != nil {
 			executedTools = tnState.ExecutedTools
 		}
+		executedAttachments = tnState.ExecutedAttachments
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, executedTools, true)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, executedTools, executedAttachments, true)
 	if err != nil {
 		return nil, err
 	}
+	for i := range tasks {
+		tasks[i].opts = opt.ToolOptionsByName[tasks[i].name]
+		tasks[i].timeout = tn.getToolTimeout(tasks[i].name)
+		tasks[i].rateLimiter = tn.rateLimiter
+		tasks[i].attachments = joinAttachments(input.Attachments, opt.Attachments)
+	}
 
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
@@ -702,6 +919,7 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 				}
 				rerunExtra.ExecutedTools[t.callID] = o
 				rerunState.ExecutedTools[t.callID] = o
+				rerunState.addExecutedAttachments(t.callID, t.outputAttachments)
 			}
 		}
 		return nil, CompositeInterrupt(ctx, rerunExtra, rerunState, errs...)
@@ -713,9 +931,14 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 		index := i
 		callID := tasks[i].callID
 		callName := tasks[i].name
+		status := tasks[i].status
+		attachments := tasks[i].outputAttachments
 		cvt := func(s string) ([]*schema.Message, error) {
 			ret := make([]*schema.Message, n)
 			ret[index] = schema.ToolMessage(s, callID, schema.WithToolName(callName))
+			setToolCallStatus(ret[index], status)
+			// the attachments are carried by the first chunk only, as they are appended when concatenating chunks
+			ret[index].Attachments, attachments = attachments, nil
 
 			return ret, nil
 		}
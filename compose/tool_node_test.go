@@ -18,20 +18,27 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/testkit"
 )
 
 const (
@@ -744,6 +751,14 @@ func TestUnknownTool(t *testing.T) {
 		}
 	}
 	assert.Equal(t, expected, result)
+
+	tn, err = NewToolNode(ctx, &ToolsNodeConfig{})
+	assert.NoError(t, err)
+	_, err = tn.Invoke(ctx, input)
+	assert.ErrorIs(t, err, einoerr.ErrToolNotFound)
+	var tnfErr *einoerr.ToolNotFoundError
+	assert.True(t, errors.As(err, &tnfErr))
+	assert.Equal(t, "unknown1", tnfErr.ToolName)
 }
 
 func TestToolRerun(t *testing.T) {
@@ -1019,3 +1034,476 @@ func (f *streamableTool[I, O]) StreamableRun(ctx context.Context, argumentsInJSO
 		return sonic.MarshalString(o)
 	}), nil
 }
+
+func TestToolsNodeCallbacksPropagation(t *testing.T) {
+	ctx := context.Background()
+
+	nested, err := NewChain[string, string]().
+		AppendLambda(InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input, nil
+		}), WithNodeName("nested")).
+		Compile(ctx)
+	assert.NoError(t, err)
+
+	newEchoTool := func(name string) tool.InvokableTool {
+		return newTool(&schema.ToolInfo{Name: name}, func(ctx context.Context, in *string) (string, error) {
+			return nested.Invoke(callbacks.ReuseHandlers(ctx, nil), *in)
+		})
+	}
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools: []tool.BaseTool{newEchoTool("a"), newEchoTool("b"), newEchoTool("c")},
+	})
+	assert.NoError(t, err)
+
+	var mu sync.Mutex
+	started := map[string]int{}
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		mu.Lock()
+		defer mu.Unlock()
+		started[info.Name]++
+		return ctx
+	}).Build()
+
+	r, err := NewChain[*schema.Message, []*schema.Message]().AppendToolsNode(tn).Compile(ctx)
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "a", Arguments: `"1"`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "b", Arguments: `"2"`}},
+		{ID: "3", Function: schema.FunctionCall{Name: "c", Arguments: `"3"`}},
+	}), WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, started["a"])
+	assert.Equal(t, 1, started["b"])
+	assert.Equal(t, 1, started["c"])
+	assert.Equal(t, 3, started["nested"])
+}
+
+type hiddenParamsState struct {
+	UserID string
+}
+
+type hiddenParamsCtxKey struct{}
+
+func TestToolsNodeHiddenParams(t *testing.T) {
+	ctx := context.Background()
+
+	type queryReq struct {
+		Query  string `json:"query"`
+		UserID string `json:"user_id"`
+		Token  string `json:"token"`
+	}
+
+	var got *queryReq
+	tl := newTool(&schema.ToolInfo{Name: "query"}, func(ctx context.Context, in *queryReq) (string, error) {
+		got = in
+		return "ok", nil
+	})
+
+	// the middlewares and callbacks only see the arguments generated by the model
+	var seen []string
+	var seenMu sync.Mutex
+	record := func(args string) {
+		seenMu.Lock()
+		defer seenMu.Unlock()
+		seen = append(seen, args)
+	}
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		if info.Component == components.ComponentOfTool {
+			record(tool.ConvCallbackInput(input).ArgumentsInJSON)
+		}
+		return ctx
+	}).Build()
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools: []tool.BaseTool{tl},
+		ToolCallMiddlewares: []ToolMiddleware{{Invokable: func(next InvokableToolEndpoint) InvokableToolEndpoint {
+			return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+				record(input.Arguments)
+				return next(ctx, input)
+			}
+		}}},
+		HiddenParams: map[string]map[string]HiddenParamResolver{
+			"query": {
+				"user_id": HiddenParamFromState(func(s *hiddenParamsState) (any, error) {
+					return s.UserID, nil
+				}),
+				"token": HiddenParamFromContext(hiddenParamsCtxKey{}),
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	g := NewGraph[*schema.Message, []*schema.Message](WithGenLocalState(func(ctx context.Context) *hiddenParamsState {
+		return &hiddenParamsState{UserID: "u1"}
+	}))
+	assert.NoError(t, g.AddToolsNode("tools", tn))
+	assert.NoError(t, g.AddEdge(START, "tools"))
+	assert.NoError(t, g.AddEdge("tools", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "query", Arguments: `{"query":"q","user_id":"spoofed"}`}},
+	})
+
+	_, err = r.Invoke(context.WithValue(ctx, hiddenParamsCtxKey{}, "secret"), input, WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Equal(t, &queryReq{Query: "q", UserID: "u1", Token: "secret"}, got)
+	assert.Equal(t, []string{`{"query":"q","user_id":"spoofed"}`, `{"query":"q","user_id":"spoofed"}`}, seen)
+
+	// missing context value fails the tool call
+	_, err = r.Invoke(ctx, input)
+	assert.ErrorContains(t, err, "resolve hidden param[token] fail")
+}
+
+func TestToolsNodeArgumentsRepairer(t *testing.T) {
+	ctx := context.Background()
+
+	type queryReq struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+
+	var got *queryReq
+	tl := newTool(&schema.ToolInfo{
+		Name: "query",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"query": {Type: schema.String, Required: true},
+			"limit": {Type: schema.Integer},
+		}),
+	}, func(ctx context.Context, in *queryReq) (string, error) {
+		got = in
+		return "ok", nil
+	})
+
+	var causes []error
+	invoke := func(repairer ArgumentsRepairer, args string) error {
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools: []tool.BaseTool{tl},
+			ArgumentsRepairer: func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error) {
+				assert.Equal(t, "query", info.Name)
+				causes = append(causes, cause)
+				return repairer(ctx, info, rawArgs, cause)
+			},
+		})
+		assert.NoError(t, err)
+		_, err = tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "1", Function: schema.FunctionCall{Name: "query", Arguments: args}},
+		}))
+		return err
+	}
+	fixed := func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error) {
+		return `{"query":"q","limit":3}`, nil
+	}
+
+	// valid arguments are passed through
+	assert.NoError(t, invoke(fixed, `{"query":"q1"}`))
+	assert.Equal(t, &queryReq{Query: "q1"}, got)
+	assert.Len(t, causes, 0)
+
+	// malformed JSON
+	assert.NoError(t, invoke(fixed, `{"query":"q","limit":3,`))
+	assert.Equal(t, &queryReq{Query: "q", Limit: 3}, got)
+	assert.Len(t, causes, 1)
+	assert.ErrorIs(t, causes[0], ErrInvalidToolArguments)
+
+	// missing required parameter
+	assert.NoError(t, invoke(fixed, `{"limit":3}`))
+	assert.ErrorContains(t, causes[1], "missing required parameters: query")
+
+	// the repaired arguments are validated again
+	err := invoke(func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error) {
+		return rawArgs, nil
+	}, `{"limit":3}`)
+	assert.ErrorIs(t, err, ErrInvalidToolArguments)
+
+	err = invoke(func(ctx context.Context, info *schema.ToolInfo, rawArgs string, cause error) (string, error) {
+		return "", fmt.Errorf("repair error")
+	}, `not json`)
+	assert.ErrorContains(t, err, "repair error")
+
+	// backed by a chat model
+	cm := testkit.NewFakeChatModel(&testkit.FakeChatModelConfig{
+		Responses: []*schema.Message{schema.AssistantMessage("```json\n{\"query\":\"from model\"}\n```", nil)},
+	})
+	assert.NoError(t, invoke(NewChatModelArgumentsRepairer(cm), `{"query": from model}`))
+	assert.Equal(t, &queryReq{Query: "from model"}, got)
+	calls := cm.Calls()
+	assert.Len(t, calls, 1)
+	assert.Contains(t, calls[0].Input[1].Content, `{"query": from model}`)
+	assert.Contains(t, calls[0].Input[1].Content, `"required":["query"]`)
+}
+
+type toolOptionForTest struct {
+	tags []string
+}
+
+func withToolTagForTest(tag string) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *toolOptionForTest) {
+		o.tags = append(o.tags, tag)
+	})
+}
+
+type taggedToolForTest struct {
+	name string
+	got  []string
+}
+
+func (tt *taggedToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: tt.name}, nil
+}
+
+func (tt *taggedToolForTest) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	tt.got = tool.GetImplSpecificOptions(&toolOptionForTest{}, opts...).tags
+	return "ok", nil
+}
+
+func TestToolOptionFor(t *testing.T) {
+	ctx := context.Background()
+	a, b := &taggedToolForTest{name: "a"}, &taggedToolForTest{name: "b"}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{a, b}})
+	assert.NoError(t, err)
+
+	sub := NewGraph[*schema.Message, []*schema.Message]()
+	assert.NoError(t, sub.AddToolsNode("tools", tn))
+	assert.NoError(t, sub.AddEdge(START, "tools"))
+	assert.NoError(t, sub.AddEdge("tools", END))
+
+	// the tools node is nested in a sub graph
+	g := NewGraph[*schema.Message, []*schema.Message]()
+	assert.NoError(t, g.AddGraphNode("agent", sub))
+	assert.NoError(t, g.AddEdge(START, "agent"))
+	assert.NoError(t, g.AddEdge("agent", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "a", Arguments: "{}"}},
+		{ID: "2", Function: schema.FunctionCall{Name: "b", Arguments: "{}"}},
+	})
+
+	_, err = r.Invoke(ctx, input, WithToolsNodeOption(
+		WithToolOption(withToolTagForTest("all")),
+		WithToolOptionFor("a", withToolTagForTest("a1")),
+		WithToolOptionFor("a", withToolTagForTest("a2")),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"all", "a1", "a2"}, a.got)
+	assert.Equal(t, []string{"all"}, b.got)
+
+	sr, err := r.Stream(ctx, input, WithToolsNodeOption(WithToolOptionFor("b", withToolTagForTest("b1"))))
+	assert.NoError(t, err)
+	_, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Len(t, a.got, 0)
+	assert.Equal(t, []string{"b1"}, b.got)
+}
+
+func TestToolTimeout(t *testing.T) {
+	ctx := context.Background()
+	hang := make(chan struct{})
+	defer close(hang)
+
+	canceled := make(chan struct{}, 2)
+	slow := newTool(&schema.ToolInfo{Name: "slow"}, func(ctx context.Context, in *string) (string, error) {
+		select {
+		case <-ctx.Done():
+			canceled <- struct{}{}
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+			return "slow", nil
+		}
+	})
+	hanging := newTool(&schema.ToolInfo{Name: "hanging"}, func(ctx context.Context, in *string) (string, error) {
+		<-hang // ignores ctx
+		return "hanging", nil
+	})
+	fast := newTool(&schema.ToolInfo{Name: "fast"}, func(ctx context.Context, in *string) (string, error) {
+		return "fast", nil
+	})
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "slow", Arguments: `""`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "hanging", Arguments: `""`}},
+		{ID: "3", Function: schema.FunctionCall{Name: "fast", Arguments: `""`}},
+	})
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:        []tool.BaseTool{slow, hanging, fast},
+		ToolTimeout:  10 * time.Millisecond,
+		ToolTimeouts: map[string]time.Duration{"fast": 0},
+	})
+	assert.NoError(t, err)
+
+	out, err := tn.Invoke(ctx, input)
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+	for _, i := range []int{0, 1} {
+		status, ok := GetToolCallStatus(out[i])
+		assert.True(t, ok)
+		assert.Equal(t, ToolCallStatusTimeout, status)
+		assert.Contains(t, out[i].Content, "timed out")
+	}
+	// the context of the timed out tool call is canceled
+	<-canceled
+	assert.Equal(t, `"fast"`, out[2].Content)
+	_, ok := GetToolCallStatus(out[2])
+	assert.False(t, ok)
+
+	sr, err := tn.Stream(ctx, input)
+	assert.NoError(t, err)
+	msgs, err := concatStreamReader(sr)
+	assert.NoError(t, err)
+	status, _ := GetToolCallStatus(msgs[1])
+	assert.Equal(t, ToolCallStatusTimeout, status)
+	assert.Equal(t, `"fast"`, msgs[2].Content)
+
+	// the tool calls after the context is canceled are skipped
+	tn, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:               []tool.BaseTool{hanging, fast},
+		ToolTimeout:         time.Second,
+		ExecuteSequentially: true,
+	})
+	assert.NoError(t, err)
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	out, err = tn.Invoke(cctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "hanging", Arguments: `""`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "fast", Arguments: `""`}},
+	}))
+	assert.NoError(t, err)
+	for _, m := range out {
+		status, _ = GetToolCallStatus(m)
+		assert.Equal(t, ToolCallStatusCanceled, status)
+	}
+
+	// the cancellation is reported the same way without timeout
+	tn, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:               []tool.BaseTool{slow, fast},
+		ExecuteSequentially: true,
+	})
+	assert.NoError(t, err)
+	cctx, cancel = context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	out, err = tn.Invoke(cctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "slow", Arguments: `""`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "fast", Arguments: `""`}},
+	}))
+	assert.NoError(t, err)
+	<-canceled
+	for _, m := range out {
+		status, _ = GetToolCallStatus(m)
+		assert.Equal(t, ToolCallStatusCanceled, status)
+		assert.Contains(t, m.Content, "canceled")
+	}
+}
+
+type lateStreamToolForTest struct {
+	release chan struct{}
+	closed  chan bool
+}
+
+func (l *lateStreamToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "late"}, nil
+}
+
+func (l *lateStreamToolForTest) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	<-l.release
+	sr, sw := schema.Pipe[string](0)
+	go func() {
+		defer sw.Close()
+		for {
+			if closed := sw.Send("chunk", nil); closed {
+				l.closed <- true
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
+
+func TestToolTimeoutClosesLateStream(t *testing.T) {
+	ctx := context.Background()
+	late := &lateStreamToolForTest{release: make(chan struct{}), closed: make(chan bool, 1)}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:       []tool.BaseTool{late},
+		ToolTimeout: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	sr, err := tn.Stream(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "late", Arguments: "{}"}},
+	}))
+	assert.NoError(t, err)
+	msgs, err := concatStreamReader(sr)
+	assert.NoError(t, err)
+	status, _ := GetToolCallStatus(msgs[0])
+	assert.Equal(t, ToolCallStatusTimeout, status)
+
+	// the stream returned after the timeout is closed, so its producer doesn't block forever
+	close(late.release)
+	select {
+	case <-late.closed:
+	case <-time.After(time.Second):
+		t.Fatal("the late stream is not closed")
+	}
+}
+
+type attachmentToolForTest struct {
+	got []schema.Attachment
+}
+
+func (at *attachmentToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "render"}, nil
+}
+
+// InvokableRun is shadowed by InvokableRunWithAttachments in ToolsNode.
+func (at *attachmentToolForTest) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return "", errors.New("unexpected invokable run")
+}
+
+func (at *attachmentToolForTest) InvokableRunWithAttachments(ctx context.Context, argumentsInJSON string,
+	attachments []schema.Attachment, opts ...tool.Option) (*tool.AttachmentResult, error) {
+	at.got = attachments
+	return &tool.AttachmentResult{
+		Content:     "rendered " + argumentsInJSON,
+		Attachments: []schema.Attachment{{Name: "out.png", MIMEType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}}},
+	}, nil
+}
+
+func TestToolsNodeAttachments(t *testing.T) {
+	ctx := context.Background()
+	at := &attachmentToolForTest{}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools: append(NamespacedTools("img", at), &taggedToolForTest{name: "plain"}),
+	})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "img__render", Arguments: `{"page":1}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "plain", Arguments: "{}"}},
+	})
+	input.Attachments = []schema.Attachment{{Name: "in.pdf", MIMEType: "application/pdf", Data: []byte("%PDF")}}
+	extra := schema.Attachment{Name: "ref.txt", URI: "https://example.com/ref.txt", Size: 42}
+	outAttachments := []schema.Attachment{{Name: "out.png", MIMEType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}}}
+
+	out, err := tn.Invoke(ctx, input, WithAttachments(extra))
+	assert.NoError(t, err)
+	assert.Equal(t, []schema.Attachment{input.Attachments[0], extra}, at.got)
+	assert.Equal(t, `rendered {"page":1}`, out[0].Content)
+	assert.Equal(t, outAttachments, out[0].Attachments)
+	assert.Equal(t, "ok", out[1].Content)
+	assert.Len(t, out[1].Attachments, 0)
+
+	at.got = nil
+	sr, err := tn.Stream(ctx, input)
+	assert.NoError(t, err)
+	msgs, err := concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, input.Attachments, at.got)
+	assert.Equal(t, `rendered {"page":1}`, msgs[0].Content)
+	assert.Equal(t, outAttachments, msgs[0].Attachments)
+	assert.Len(t, msgs[1].Attachments, 0)
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolRegistry is a set of tools that can change at runtime, e.g. when plugins are loaded or unloaded.
+// A ToolsNode configured with ToolsNodeConfig.ToolRegistry consults the registry at each call instead of a fixed tool list,
+// and a chat model returned by BindChatModel binds the current tool infos before each turn.
+// It's safe for concurrent use.
+type ToolRegistry struct {
+	mu      sync.RWMutex
+	version uint64
+	names   []string
+	tools   map[string]tool.BaseTool
+	infos   map[string]*schema.ToolInfo
+}
+
+// NewToolRegistry creates a ToolRegistry with the given initial tools.
+func NewToolRegistry(ctx context.Context, tools ...tool.BaseTool) (*ToolRegistry, error) {
+	r := &ToolRegistry{
+		tools: make(map[string]tool.BaseTool),
+		infos: make(map[string]*schema.ToolInfo),
+	}
+	if err := r.Register(ctx, tools...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Register adds the tools to the registry, a tool having the same name as a registered one replaces it.
+// The tools must implement InvokableTool or StreamableTool.
+func (r *ToolRegistry) Register(ctx context.Context, tools ...tool.BaseTool) error {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	infos := make([]*schema.ToolInfo, len(tools))
+	for i, t := range tools {
+		if t == nil {
+			return fmt.Errorf("tool at index %d is nil", i)
+		}
+		info, err := t.Info(ctx)
+		if err != nil {
+			return fmt.Errorf("get info of tool at index %d fail: %w", i, err)
+		}
+		if info == nil || info.Name == "" {
+			return fmt.Errorf("tool at index %d has no name", i)
+		}
+		_, ok1 := t.(tool.InvokableTool)
+		_, ok2 := t.(tool.StreamableTool)
+		_, ok3 := t.(tool.AttachmentTool)
+		if !ok1 && !ok2 && !ok3 {
+			return fmt.Errorf("tool %s is neither invokable nor streamable", info.Name)
+		}
+		infos[i] = info
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range tools {
+		name := infos[i].Name
+		if _, ok := r.tools[name]; !ok {
+			r.names = append(r.names, name)
+		}
+		r.tools[name] = t
+		r.infos[name] = infos[i]
+	}
+	r.version++
+
+	return nil
+}
+
+// Unregister removes the tools with the given names, and returns the number of tools removed.
+func (r *ToolRegistry) Unregister(names ...string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for _, name := range names {
+		if _, ok := r.tools[name]; !ok {
+			continue
+		}
+		delete(r.tools, name)
+		delete(r.infos, name)
+		for i, n := range r.names {
+			if n == name {
+				r.names = append(r.names[:i:i], r.names[i+1:]...)
+				break
+			}
+		}
+		removed++
+	}
+	if removed > 0 {
+		r.version++
+	}
+
+	return removed
+}
+
+// Tools returns the registered tools in registration order.
+func (r *ToolRegistry) Tools() []tool.BaseTool {
+	_, tools, _ := r.snapshot()
+	return tools
+}
+
+// ToolInfos returns the infos of the registered tools in registration order.
+func (r *ToolRegistry) ToolInfos() []*schema.ToolInfo {
+	_, _, infos := r.snapshot()
+	return infos
+}
+
+// Version returns a number increased by each change of the registry.
+func (r *ToolRegistry) Version() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+func (r *ToolRegistry) snapshot() (uint64, []tool.BaseTool, []*schema.ToolInfo) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]tool.BaseTool, len(r.names))
+	infos := make([]*schema.ToolInfo, len(r.names))
+	for i, name := range r.names {
+		tools[i] = r.tools[name]
+		infos[i] = r.infos[name]
+	}
+	return r.version, tools, infos
+}
+
+// registryTuple caches the tools tuple converted from a ToolRegistry, until the registry changes.
+type registryTuple struct {
+	registry *ToolRegistry
+
+	mu      sync.Mutex
+	version uint64
+	tuple   *toolsTuple
+}
+
+func (rt *registryTuple) get(ctx context.Context, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware,
+	hiddenParams map[string]map[string]HiddenParamResolver) (*toolsTuple, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.tuple != nil && rt.version == rt.registry.Version() {
+		return rt.tuple, nil
+	}
+
+	version, tools, _ := rt.registry.snapshot()
+	tuple, err := convTools(ctx, tools, ms, sms, hiddenParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert tools of registry: %w", err)
+	}
+	rt.version, rt.tuple = version, tuple
+
+	return tuple, nil
+}
+
+// BindChatModel returns a chat model binding the current tool infos of the registry before each Generate or Stream,
+// so that tools added, updated or removed take effect on the next turn.
+// Calling WithTools on the returned model binds the given tools only, detached from the registry.
+func (r *ToolRegistry) BindChatModel(m model.ToolCallingChatModel) (model.ToolCallingChatModel, error) {
+	if m == nil {
+		return nil, errors.New("chat model is nil")
+	}
+	if err := model.CheckToolCalling(m); err != nil {
+		return nil, err
+	}
+	return &registryBoundChatModel{registry: r, model: m}, nil
+}
+
+type registryBoundChatModel struct {
+	registry *ToolRegistry
+	model    model.ToolCallingChatModel
+
+	mu      sync.Mutex
+	version uint64
+	bound   model.BaseChatModel
+}
+
+func (b *registryBoundChatModel) current() (model.BaseChatModel, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bound != nil && b.version == b.registry.Version() {
+		return b.bound, nil
+	}
+
+	version, _, infos := b.registry.snapshot()
+	if len(infos) == 0 {
+		b.version, b.bound = version, b.model
+		return b.model, nil
+	}
+	bound, err := b.model.WithTools(infos)
+	if err != nil {
+		return nil, fmt.Errorf("bind tools of registry to chat model fail: %w", err)
+	}
+	b.version, b.bound = version, bound
+
+	return bound, nil
+}
+
+func (b *registryBoundChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	return m.Generate(ctx, input, opts...)
+}
+
+func (b *registryBoundChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m, err := b.current()
+	if err != nil {
+		return nil, err
+	}
+	return m.Stream(ctx, input, opts...)
+}
+
+func (b *registryBoundChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return b.model.WithTools(tools)
+}
+
+func (b *registryBoundChatModel) GetType() string {
+	typ, _ := components.GetType(b.model)
+	return typ
+}
+
+func (b *registryBoundChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(b.model)
+}
+
+func (b *registryBoundChatModel) Capabilities() *model.Capabilities {
+	c, _ := model.GetCapabilities(b.model)
+	return c
+}
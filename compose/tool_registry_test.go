@@ -0,0 +1,145 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestToolRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	echo := func(prefix string) func(ctx context.Context, in *registryToolInput) (string, error) {
+		return func(ctx context.Context, in *registryToolInput) (string, error) {
+			return prefix + in.V, nil
+		}
+	}
+
+	r, err := NewToolRegistry(ctx, newTool(&schema.ToolInfo{Name: "a"}, echo("a:")))
+	assert.NoError(t, err)
+
+	_, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:        r.Tools(),
+		ToolRegistry: r,
+	})
+	assert.Error(t, err)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{ToolRegistry: r})
+	assert.NoError(t, err)
+
+	call := func(name string) ([]*schema.Message, error) {
+		return tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "1", Function: schema.FunctionCall{Name: name, Arguments: `{"v":"x"}`}},
+		}))
+	}
+
+	out, err := call("a")
+	assert.NoError(t, err)
+	assert.Equal(t, `"a:x"`, out[0].Content)
+	_, err = call("b")
+	assert.Error(t, err)
+
+	// add
+	version := r.Version()
+	assert.NoError(t, r.Register(ctx, newTool(&schema.ToolInfo{Name: "b"}, echo("b:"))))
+	assert.Greater(t, r.Version(), version)
+	out, err = call("b")
+	assert.NoError(t, err)
+	assert.Equal(t, `"b:x"`, out[0].Content)
+
+	// update
+	assert.NoError(t, r.Register(ctx, newTool(&schema.ToolInfo{Name: "a"}, echo("new a:"))))
+	out, err = call("a")
+	assert.NoError(t, err)
+	assert.Equal(t, `"new a:x"`, out[0].Content)
+	assert.Equal(t, []string{"a", "b"}, toolNames(r.ToolInfos()))
+
+	// remove
+	assert.Equal(t, 1, r.Unregister("a", "c"))
+	_, err = call("a")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"b"}, toolNames(r.ToolInfos()))
+
+	// tool list from the call option takes precedence over the registry
+	out, err = tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "c", Arguments: `{"v":"x"}`}},
+	}), WithToolList(newTool(&schema.ToolInfo{Name: "c"}, echo("c:"))))
+	assert.NoError(t, err)
+	assert.Equal(t, `"c:x"`, out[0].Content)
+
+	assert.Error(t, r.Register(ctx, newTool(&schema.ToolInfo{}, echo(""))))
+}
+
+func TestToolRegistryBindChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+
+	var bound [][]string
+	cm.EXPECT().WithTools(gomock.Any()).DoAndReturn(func(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+		bound = append(bound, toolNames(tools))
+		return cm, nil
+	}).Times(2)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(schema.AssistantMessage("ok", nil), nil).Times(4)
+
+	r, err := NewToolRegistry(ctx, newTool(&schema.ToolInfo{Name: "a"}, func(ctx context.Context, in *registryToolInput) (string, error) {
+		return "", nil
+	}))
+	assert.NoError(t, err)
+
+	m, err := r.BindChatModel(cm)
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("hi")}
+	_, err = m.Generate(ctx, input)
+	assert.NoError(t, err)
+	_, err = m.Generate(ctx, input)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Register(ctx, newTool(&schema.ToolInfo{Name: "b"}, func(ctx context.Context, in *registryToolInput) (string, error) {
+		return "", nil
+	})))
+	_, err = m.Generate(ctx, input)
+	assert.NoError(t, err)
+
+	r.Unregister("a", "b")
+	_, err = m.Generate(ctx, input)
+	assert.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"a"}, {"a", "b"}}, bound)
+}
+
+type registryToolInput struct {
+	V string `json:"v"`
+}
+
+func toolNames(infos []*schema.ToolInfo) []string {
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolCallStatus is the status of a tool call which didn't complete, set to the Extra of its tool message by ToolsNode.
+type ToolCallStatus string
+
+const (
+	// ToolCallStatusTimeout means the tool call exceeded the timeout set by ToolsNodeConfig.ToolTimeout or ToolTimeouts.
+	ToolCallStatusTimeout ToolCallStatus = "timeout"
+	// ToolCallStatusCanceled means the context was canceled before the tool call completed.
+	ToolCallStatusCanceled ToolCallStatus = "canceled"
+)
+
+const toolCallStatusExtraKey = "_eino_tool_call_status"
+
+// GetToolCallStatus returns the status of the tool call of the tool message, if the tool call didn't complete,
+// e.g. to tell the results of the timed out tools from the others in the output of ToolsNode.
+func GetToolCallStatus(msg *schema.Message) (ToolCallStatus, bool) {
+	if msg == nil || msg.Extra == nil {
+		return "", false
+	}
+	s, ok := msg.Extra[toolCallStatusExtraKey].(string)
+	return ToolCallStatus(s), ok
+}
+
+func setToolCallStatus(msg *schema.Message, status ToolCallStatus) {
+	if status == "" {
+		return
+	}
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	// stored as string to survive serialization
+	msg.Extra[toolCallStatusExtraKey] = string(status)
+}
+
+func (tn *ToolsNode) getToolTimeout(name string) time.Duration {
+	if d, ok := tn.toolTimeouts[name]; ok {
+		return d
+	}
+	return tn.toolTimeout
+}
+
+// toolCallStatusResult is the result of the tool call which didn't complete, telling the model what happened.
+func toolCallStatusResult(ctx context.Context, status ToolCallStatus, timeout time.Duration) string {
+	if status == ToolCallStatusTimeout {
+		return fmt.Sprintf("tool call timed out after %v", timeout)
+	}
+	return fmt.Sprintf("tool call canceled: %v", ctx.Err())
+}
+
+// runWithToolTimeout runs the tool call, but stops waiting for it once the timeout is exceeded or ctx is canceled,
+// in which case the status is returned instead.
+// The context of the tool call is canceled on timeout only when cancelOnReturn,
+// i.e. not for a stream which is still read after the call returns.
+// Either way the tool keeps running until it returns, e.g. an invokable tool ignoring its context,
+// whose late result is discarded, and closed if it's a stream, see closeLateToolResult.
+// Without timeout, the tool is called directly, and the call canceled by ctx is reported the same way once it returns.
+func runWithToolTimeout[T any](ctx context.Context, timeout time.Duration, cancelOnReturn bool,
+	call func(ctx context.Context) (T, error)) (T, ToolCallStatus, error) {
+
+	var zero T
+	if ctx.Err() != nil {
+		// canceled before the tool is called, e.g. during the previous tool calls executed sequentially
+		return zero, ToolCallStatusCanceled, nil
+	}
+
+	if timeout <= 0 {
+		ret, err := call(ctx)
+		if ctx.Err() != nil {
+			closeToolResult(ret)
+			return zero, ToolCallStatusCanceled, nil
+		}
+		return ret, "", err
+	}
+
+	callCtx := ctx
+	if cancelOnReturn {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan toolCallResult[T], 1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				done <- toolCallResult[T]{err: safe.NewPanicErr(panicErr, debug.Stack())}
+			}
+		}()
+		r, e := call(callCtx)
+		done <- toolCallResult[T]{ret: r, err: e}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.ret, "", r.err
+	case <-timer.C:
+		go closeLateToolResult(done)
+		return zero, ToolCallStatusTimeout, nil
+	case <-ctx.Done():
+		go closeLateToolResult(done)
+		return zero, ToolCallStatusCanceled, nil
+	}
+}
+
+type toolCallResult[T any] struct {
+	ret T
+	err error
+}
+
+// closeLateToolResult waits for the result of the tool call no longer waited for,
+// and closes it if it's a stream, so that the producer of the stream doesn't block forever.
+func closeLateToolResult[T any](done <-chan toolCallResult[T]) {
+	r := <-done
+	closeToolResult(r.ret)
+}
+
+func closeToolResult[T any](ret T) {
+	if o, ok := any(ret).(*StreamToolOutput); ok && o != nil && o.Result != nil {
+		o.Result.Close()
+	}
+}
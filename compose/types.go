@@ -32,6 +32,7 @@ const (
 	ComponentOfPassthrough component = "Passthrough"
 	ComponentOfToolsNode   component = "ToolsNode"
 	ComponentOfLambda      component = "Lambda"
+	ComponentOfGuardrail   component = "Guardrail"
 )
 
 // NodeTriggerMode controls the triggering mode of graph nodes.
@@ -42,5 +43,8 @@ const (
 	// Ref:https://www.cloudwego.io/docs/eino/core_modules/chain_and_graph_orchestration/orchestration_design_principles/#runtime-engine
 	AnyPredecessor NodeTriggerMode = "any_predecessor"
 	// AllPredecessor means that the current node will only be triggered when all of its predecessor nodes have finished running.
+	// Branches are supported: the nodes not chosen by a branch are marked as skipped, and the skipping is propagated downstream
+	// to the nodes whose predecessors are all skipped, so a join node fires once all of its non-skipped predecessors complete.
+	// If END itself ends up skipped, the run fails with ErrEndNodeSkipped.
 	AllPredecessor NodeTriggerMode = "all_predecessor"
 )
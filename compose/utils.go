@@ -22,6 +22,7 @@ import (
 	"reflect"
 
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/credential"
 	icb "github.com/cloudwego/eino/internal/callbacks"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
@@ -137,6 +138,10 @@ func onGraphError(ctx context.Context, err error) (context.Context, error) {
 	return onError(ctx, err)
 }
 
+func onGraphEvent(ctx context.Context, event *callbacks.GraphEvent) {
+	icb.OnGraphEvent(ctx, event)
+}
+
 func streamWithCallbacks[I, O, TOption any](s Stream[I, O, TOption]) Stream[I, O, TOption] {
 	return runWithCallbacks(s, onStart[I], onEndWithStreamOutput[O], onError)
 }
@@ -158,8 +163,13 @@ func initGraphCallbacks(ctx context.Context, info *nodeInfo, meta *executorMeta,
 
 	if info != nil {
 		ri.Name = info.name
+		ri.Group = info.group
 	}
 
+	ctx = withRunMeta(ctx, opts...)
+	setRunMeta(ctx, ri)
+	ctx = withCredentialProvider(ctx, opts...)
+
 	var cbs []callbacks.Handler
 	for i := range opts {
 		if len(opts[i].handler) != 0 && len(opts[i].paths) == 0 {
@@ -167,6 +177,12 @@ func initGraphCallbacks(ctx context.Context, info *nodeInfo, meta *executorMeta,
 		}
 	}
 
+	var costHandler callbacks.Handler
+	ctx, costHandler = withCostBudget(ctx, opts...)
+	if costHandler != nil {
+		cbs = append(cbs, costHandler)
+	}
+
 	if len(cbs) == 0 {
 		return icb.ReuseHandlers(ctx, ri)
 	}
@@ -183,14 +199,18 @@ func initNodeCallbacks(ctx context.Context, key string, info *nodeInfo, meta *ex
 
 	if info != nil {
 		ri.Name = info.name
+		ri.Group = info.group
 	}
 
+	setRunMeta(ctx, ri)
+
 	var cbs []callbacks.Handler
 	for i := range opts {
 		if len(opts[i].handler) != 0 {
 			if len(opts[i].paths) != 0 {
 				for _, k := range opts[i].paths {
-					if len(k.path) == 1 && k.path[0] == key {
+					if (len(k.path) == 1 && k.group == "" && k.path[0] == key) ||
+						(len(k.path) == 0 && k.group != "" && info != nil && k.group == info.group) {
 						cbs = append(cbs, opts[i].handler...)
 						break
 					}
@@ -235,6 +255,21 @@ func checkAssignable(input, arg reflect.Type) assignableType {
 		return assignableTypeMustNot
 	}
 
+	key := assignableKey{input: input, arg: arg}
+	if at, ok := compileCache.assignable.Load(key); ok {
+		return at.(assignableType)
+	}
+
+	at := resolveAssignable(input, arg)
+	compileCache.assignable.Store(key, at)
+	return at
+}
+
+func resolveAssignable(input, arg reflect.Type) assignableType {
+	if arg == nil || input == nil {
+		return assignableTypeMustNot
+	}
+
 	if arg == input {
 		return assignableTypeMust
 	}
@@ -269,9 +304,47 @@ func extractOption(nodes map[string]*chanCall, opts ...Option) (map[string][]any
 				}
 			}
 		}
+		designate := func(curNodeKey string, curNode *chanCall, path *NodePath) error {
+			if len(opt.options) == 0 && (curNode.action.optionType != nil || (opt.maxRunSteps == 0 && opt.configurable == nil)) {
+				// sub graph common callbacks has been added to ctx in initNodeCallback and won't be passed to subgraph only pass options
+				// node callback also won't be passed
+				return nil
+			}
+			if curNode.action.optionType == nil {
+				nOpt := opt.deepCopy()
+				nOpt.paths = []*NodePath{}
+				optMap[curNodeKey] = append(optMap[curNodeKey], nOpt)
+			} else {
+				// designate to component
+				if curNode.action.optionType != reflect.TypeOf(opt.options[0]) { // assume that types of options are the same
+					return fmt.Errorf("option type[%s] is different from which the designated node[%s] expects[%s]",
+						reflect.TypeOf(opt.options[0]).String(), path, curNode.action.optionType.String())
+				}
+				optMap[curNodeKey] = append(optMap[curNodeKey], opt.options...)
+			}
+			return nil
+		}
+
 		for _, path := range opt.paths {
 			if len(path.path) == 0 {
-				return nil, fmt.Errorf("call option has designated an empty path")
+				if path.group == "" {
+					return nil, fmt.Errorf("call option has designated an empty path")
+				}
+				// designate to the nodes of the group
+				found := false
+				for name, c := range nodes {
+					if c.action.nodeInfo == nil || c.action.nodeInfo.group != path.group {
+						continue
+					}
+					found = true
+					if err := designate(name, c, path); err != nil {
+						return nil, err
+					}
+				}
+				if !found {
+					return nil, fmt.Errorf("option has designated an unknown node group: %s", path.group)
+				}
+				continue
 			}
 
 			var curNode *chanCall
@@ -281,23 +354,9 @@ func extractOption(nodes map[string]*chanCall, opts ...Option) (map[string][]any
 			}
 			curNodeKey := path.path[0]
 
-			if len(path.path) == 1 {
-				if len(opt.options) == 0 {
-					// sub graph common callbacks has been added to ctx in initNodeCallback and won't be passed to subgraph only pass options
-					// node callback also won't be passed
-					continue
-				}
-				if curNode.action.optionType == nil {
-					nOpt := opt.deepCopy()
-					nOpt.paths = []*NodePath{}
-					optMap[curNodeKey] = append(optMap[curNodeKey], nOpt)
-				} else {
-					// designate to component
-					if curNode.action.optionType != reflect.TypeOf(opt.options[0]) { // assume that types of options are the same
-						return nil, fmt.Errorf("option type[%s] is different from which the designated node[%s] expects[%s]",
-							reflect.TypeOf(opt.options[0]).String(), path, curNode.action.optionType.String())
-					}
-					optMap[curNodeKey] = append(optMap[curNodeKey], opt.options...)
+			if len(path.path) == 1 && path.group == "" {
+				if err := designate(curNodeKey, curNode, path); err != nil {
+					return nil, err
 				}
 			} else {
 				if curNode.action.optionType != nil {
@@ -306,7 +365,7 @@ func extractOption(nodes map[string]*chanCall, opts ...Option) (map[string][]any
 				}
 				// designate to sub graph's nodes
 				nOpt := opt.deepCopy()
-				nOpt.paths = []*NodePath{NewNodePath(path.path[1:]...)}
+				nOpt.paths = []*NodePath{{path: path.path[1:], group: path.group}}
 				optMap[curNodeKey] = append(optMap[curNodeKey], nOpt)
 			}
 		}
@@ -322,3 +381,71 @@ func mapToList(m map[string]any) []any {
 	}
 	return ret
 }
+
+type runMetaKey struct{}
+
+type runMeta struct {
+	id       string
+	metadata map[string]string
+}
+
+// withRunMeta stores the run ID and metadata set by WithRunID and WithRunMetadata into ctx,
+// on top of the ones of the parent run, so that they can be set to the RunInfo of the nodes, including the nested ones.
+func withRunMeta(ctx context.Context, opts ...Option) context.Context {
+	var (
+		parent, _ = ctx.Value(runMetaKey{}).(*runMeta)
+		meta      *runMeta
+	)
+	for i := range opts {
+		if len(opts[i].runID) == 0 && len(opts[i].runMetadata) == 0 {
+			continue
+		}
+		if meta == nil {
+			meta = &runMeta{}
+			if parent != nil {
+				meta.id = parent.id
+				meta.metadata = make(map[string]string, len(parent.metadata))
+				for k, v := range parent.metadata {
+					meta.metadata[k] = v
+				}
+			}
+		}
+		if len(opts[i].runID) > 0 {
+			meta.id = opts[i].runID
+		}
+		for k, v := range opts[i].runMetadata {
+			if meta.metadata == nil {
+				meta.metadata = make(map[string]string, len(opts[i].runMetadata))
+			}
+			meta.metadata[k] = v
+		}
+	}
+	if meta == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, runMetaKey{}, meta)
+}
+
+// withCredentialProvider stores the provider set by WithCredentialProvider into ctx, overriding the one of the parent run.
+func withCredentialProvider(ctx context.Context, opts ...Option) context.Context {
+	var p credential.Provider
+	for i := range opts {
+		if opts[i].credentialProvider != nil {
+			p = opts[i].credentialProvider
+		}
+	}
+	if p == nil {
+		return ctx
+	}
+	return credential.WithProvider(ctx, p)
+}
+
+func setRunMeta(ctx context.Context, ri *callbacks.RunInfo) {
+	if meta, ok := ctx.Value(runMetaKey{}).(*runMeta); ok {
+		ri.RunID = meta.id
+		ri.Metadata = meta.metadata
+	}
+	if ev, ok := ctx.Value(experimentVariantsKey{}).(*experimentVariants); ok {
+		ri.Metadata = ev.tag(ri.Metadata)
+	}
+}
@@ -21,18 +21,74 @@ import (
 	"reflect"
 
 	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/internal/generic"
 )
 
+// ErrDuplicateRegistration is the error which RegisterValuesMergeFunc and RegisterStreamConcatFunc panic with,
+// when a function is registered for a type which already has one, without WithReplaceRegistration.
+var ErrDuplicateRegistration = internal.ErrDuplicateRegistration
+
+// RegisterOption is the option of RegisterValuesMergeFunc and RegisterStreamConcatFunc.
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	replace bool
+}
+
+// WithReplaceRegistration replaces the function registered for the type before, instead of panicking with ErrDuplicateRegistration.
+func WithReplaceRegistration() RegisterOption {
+	return func(o *registerOptions) {
+		o.replace = true
+	}
+}
+
+func getRegisterOptions(opts []RegisterOption) *registerOptions {
+	o := &registerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
 // RegisterValuesMergeFunc registers a function to merge outputs from multiple nodes when fan-in.
 // It's used to define how to merge for a specific type.
 // For maps that already have a default merge function, you don't need to register a new one unless you want to customize the merge logic.
-func RegisterValuesMergeFunc[T any](fn func([]T) (T, error)) {
-	internal.RegisterValuesMergeFunc(fn)
+// call at process init, not thread safe.
+// It panics with ErrDuplicateRegistration if a function has been registered for T, unless WithReplaceRegistration is given,
+// use WithGraphValuesMergeFunc to merge differently in a specific graph instead.
+func RegisterValuesMergeFunc[T any](fn func([]T) (T, error), opts ...RegisterOption) {
+	if err := internal.RegisterValuesMergeFunc(fn, getRegisterOptions(opts).replace); err != nil {
+		panic(err)
+	}
 }
 
 type mergeOptions struct {
 	streamMergeWithSourceEOF bool
 	names                    []string
+	mergeFuncs               map[reflect.Type]func([]any) (any, error)
+}
+
+func (o *mergeOptions) getMergeFunc(t reflect.Type) (func([]any) (any, error), bool) {
+	if o == nil {
+		return nil, false
+	}
+	fn, ok := o.mergeFuncs[t]
+	return fn, ok
+}
+
+// newValuesMergeFunc adapts fn to merge the values of type T.
+func newValuesMergeFunc[T any](fn func([]T) (T, error)) func([]any) (any, error) {
+	return func(vs []any) (any, error) {
+		ts := make([]T, len(vs))
+		for i, v := range vs {
+			t, ok := v.(T)
+			if !ok {
+				return nil, fmt.Errorf("(values merge) field type mismatch. expected: '%v', got: '%T'", generic.TypeOf[T](), v)
+			}
+			ts[i] = t
+		}
+		return fn(ts)
+	}
 }
 
 // the caller should ensure len(vs) > 1
@@ -40,6 +96,10 @@ func mergeValues(vs []any, opts *mergeOptions) (any, error) {
 	v0 := reflect.ValueOf(vs[0])
 	t0 := v0.Type()
 
+	if fn, ok := opts.getMergeFunc(t0); ok {
+		return fn(vs)
+	}
+
 	if fn := internal.GetMergeFunc(t0); fn != nil {
 		return fn(vs)
 	}
@@ -47,7 +107,8 @@ func mergeValues(vs []any, opts *mergeOptions) (any, error) {
 	// merge StreamReaders
 	if s, ok := vs[0].(streamReader); ok {
 		t := s.getChunkType()
-		if internal.GetMergeFunc(t) == nil {
+		_, overridden := opts.getMergeFunc(t)
+		if internal.GetMergeFunc(t) == nil && !overridden {
 			return nil, fmt.Errorf("(mergeValues | stream type)"+
 				" unsupported chunk type: %v", t)
 		}
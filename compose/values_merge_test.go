@@ -17,9 +17,11 @@
 package compose
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -252,5 +254,34 @@ func Test_mergeValues(t *testing.T) {
 		_, err := mergeValues([]any{&Unregistered{}}, nil)
 		assert.ErrorContains(t, err, "unsupported type")
 	})
+}
+
+func TestGraphValuesMergeFunc(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	_ = g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in + "a", nil }))
+	_ = g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, in string) (string, error) { return in + "b", nil }))
+	_ = g.AddEdge(START, "a")
+	_ = g.AddEdge(START, "b")
+	_ = g.AddEdge("a", END)
+	_ = g.AddEdge("b", END)
+
+	// strings can't be merged by default
+	r, err := g.Compile(ctx)
+	require.NoError(t, err)
+	_, err = r.Invoke(ctx, "x")
+	assert.ErrorContains(t, err, "unsupported type")
+
+	r, err = g.Compile(ctx, WithGraphValuesMergeFunc(func(vs []string) (string, error) {
+		sort.Strings(vs)
+		return strings.Join(vs, ","), nil
+	}))
+	require.NoError(t, err)
+	out, err := r.Invoke(ctx, "x")
+	require.NoError(t, err)
+	assert.Equal(t, "xa,xb", out)
 
+	// the merge func of a graph doesn't affect the others
+	_, err = mergeValues([]any{"a", "b"}, nil)
+	assert.ErrorContains(t, err, "unsupported type")
 }
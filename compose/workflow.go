@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/cloudwego/eino/components/audio"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
@@ -97,6 +98,11 @@ func (wf *Workflow[I, O]) AddToolsNode(key string, tools *ToolsNode, opts ...Gra
 	return wf.initNode(key)
 }
 
+func (wf *Workflow[I, O]) AddGuardrailNode(key string, guardrail *GuardrailNode, opts ...GraphAddNodeOpt) *WorkflowNode {
+	_ = wf.g.AddGuardrailNode(key, guardrail, opts...)
+	return wf.initNode(key)
+}
+
 func (wf *Workflow[I, O]) AddRetrieverNode(key string, retriever retriever.Retriever, opts ...GraphAddNodeOpt) *WorkflowNode {
 	_ = wf.g.AddRetrieverNode(key, retriever, opts...)
 	return wf.initNode(key)
@@ -122,6 +128,16 @@ func (wf *Workflow[I, O]) AddDocumentTransformerNode(key string, transformer doc
 	return wf.initNode(key)
 }
 
+func (wf *Workflow[I, O]) AddSpeechToTextNode(key string, stt audio.SpeechToText, opts ...GraphAddNodeOpt) *WorkflowNode {
+	_ = wf.g.AddSpeechToTextNode(key, stt, opts...)
+	return wf.initNode(key)
+}
+
+func (wf *Workflow[I, O]) AddTextToSpeechNode(key string, tts audio.TextToSpeech, opts ...GraphAddNodeOpt) *WorkflowNode {
+	_ = wf.g.AddTextToSpeechNode(key, tts, opts...)
+	return wf.initNode(key)
+}
+
 func (wf *Workflow[I, O]) AddGraphNode(key string, graph AnyGraph, opts ...GraphAddNodeOpt) *WorkflowNode {
 	_ = wf.g.AddGraphNode(key, graph, opts...)
 	return wf.initNode(key)
@@ -27,6 +27,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/mock/components/embedding"
 	"github.com/cloudwego/eino/internal/mock/components/indexer"
 	"github.com/cloudwego/eino/internal/mock/components/model"
@@ -731,6 +732,9 @@ func TestWorkflowCompile(t *testing.T) {
 		w.End().AddInput("2")
 		_, err := w.Compile(ctx)
 		assert.ErrorContains(t, err, "edge start node '2' needs to be added to graph first")
+		var nnfErr *einoerr.NodeNotFoundError
+		assert.True(t, errors.As(err, &nnfErr))
+		assert.Equal(t, "2", nnfErr.NodeKey)
 	})
 
 	t.Run("to map with non-string key type", func(t *testing.T) {
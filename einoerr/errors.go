@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package einoerr defines the error categories shared across eino.
+// Each category has a sentinel to check with errors.Is, and most have a typed error
+// carrying structured fields to extract with errors.As, e.g.
+//
+//	var tnf *einoerr.ToolNotFoundError
+//	if errors.As(err, &tnf) {
+//		// tnf.ToolName is the tool the model asked for
+//	}
+package einoerr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrTypeMismatch means a value or a connection does not have the expected type.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrMaxSteps means a graph run exceeds its maximum number of steps.
+	ErrMaxSteps = errors.New("exceeds max steps")
+	// ErrNodeNotFound means a node key referred to does not exist in the graph.
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrStreamClosed means a stream is received from after it has been closed.
+	ErrStreamClosed = errors.New("recv after stream closed")
+	// ErrToolNotFound means a tool call refers to a tool that is not registered.
+	ErrToolNotFound = errors.New("tool not found")
+	// ErrInterrupted means a run is interrupted and can be resumed from a checkpoint.
+	ErrInterrupted = errors.New("interrupted")
+)
+
+// TypeMismatchError is returned when a value or a connection does not have the expected type.
+type TypeMismatchError struct {
+	// Expected is the type required, may be nil if unknown.
+	Expected reflect.Type
+	// Actual is the type got, may be nil if unknown.
+	Actual reflect.Type
+	// Detail describes where and how the mismatch happens, used as the error message if not empty.
+	Detail string
+}
+
+func (e *TypeMismatchError) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	return fmt.Sprintf("unexpected input type. expected: %v, got: %v", e.Expected, e.Actual)
+}
+
+// Is reports whether target is ErrTypeMismatch.
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// MaxStepsError is returned when a graph run exceeds its maximum number of steps.
+type MaxStepsError struct {
+	// MaxSteps is the limit exceeded.
+	MaxSteps int
+}
+
+func (e *MaxStepsError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrMaxSteps.Error(), e.MaxSteps)
+}
+
+// Is reports whether target is ErrMaxSteps.
+func (e *MaxStepsError) Is(target error) bool {
+	return target == ErrMaxSteps
+}
+
+// NodeNotFoundError is returned when a node key referred to does not exist in the graph.
+type NodeNotFoundError struct {
+	// NodeKey is the key not found.
+	NodeKey string
+	// Context describes the reference, e.g. "edge start node".
+	Context string
+}
+
+func (e *NodeNotFoundError) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("%s '%s' needs to be added to graph first", e.Context, e.NodeKey)
+	}
+	return fmt.Sprintf("node '%s' needs to be added to graph first", e.NodeKey)
+}
+
+// Is reports whether target is ErrNodeNotFound.
+func (e *NodeNotFoundError) Is(target error) bool {
+	return target == ErrNodeNotFound
+}
+
+// ToolNotFoundError is returned when a tool call refers to a tool that is not registered.
+type ToolNotFoundError struct {
+	// ToolName is the name in the tool call.
+	ToolName string
+}
+
+func (e *ToolNotFoundError) Error() string {
+	return fmt.Sprintf("tool %s not found in toolsNode indexes", e.ToolName)
+}
+
+// Is reports whether target is ErrToolNotFound.
+func (e *ToolNotFoundError) Is(target error) bool {
+	return target == ErrToolNotFound
+}
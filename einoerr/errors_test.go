@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoerr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedErrors(t *testing.T) {
+	t.Run("type mismatch", func(t *testing.T) {
+		err := fmt.Errorf("run fail: %w", &TypeMismatchError{Expected: reflect.TypeOf(""), Actual: reflect.TypeOf(0)})
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+		assert.ErrorContains(t, err, "unexpected input type. expected: string, got: int")
+		var e *TypeMismatchError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, reflect.TypeOf(0), e.Actual)
+
+		err = &TypeMismatchError{Detail: "edge mismatch"}
+		assert.Equal(t, "edge mismatch", err.Error())
+	})
+
+	t.Run("max steps", func(t *testing.T) {
+		err := fmt.Errorf("run fail: %w", &MaxStepsError{MaxSteps: 3})
+		assert.ErrorIs(t, err, ErrMaxSteps)
+		assert.False(t, errors.Is(err, ErrNodeNotFound))
+		assert.ErrorContains(t, err, "exceeds max steps: 3")
+	})
+
+	t.Run("node not found", func(t *testing.T) {
+		err := &NodeNotFoundError{NodeKey: "a", Context: "edge start node"}
+		assert.ErrorIs(t, err, ErrNodeNotFound)
+		assert.Equal(t, "edge start node 'a' needs to be added to graph first", err.Error())
+		assert.Equal(t, "node 'a' needs to be added to graph first", (&NodeNotFoundError{NodeKey: "a"}).Error())
+	})
+
+	t.Run("tool not found", func(t *testing.T) {
+		var err error = &ToolNotFoundError{ToolName: "search"}
+		assert.ErrorIs(t, err, ErrToolNotFound)
+		var e *ToolNotFoundError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "search", e.ToolName)
+	})
+}
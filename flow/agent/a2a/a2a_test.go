@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package a2a
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/agent/multiagent/host"
+	"github.com/cloudwego/eino/schema"
+)
+
+type echoAgent struct {
+	inputs [][]*schema.Message
+}
+
+func (a *echoAgent) Generate(_ context.Context, input []*schema.Message, _ ...agent.AgentOption) (*schema.Message, error) {
+	a.inputs = append(a.inputs, input)
+	if input[len(input)-1].Content == "fail" {
+		return nil, errors.New("agent failed")
+	}
+	return schema.AssistantMessage("echo: "+input[len(input)-1].Content, nil), nil
+}
+
+func (a *echoAgent) Stream(_ context.Context, input []*schema.Message, _ ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+	a.inputs = append(a.inputs, input)
+	if input[len(input)-1].Content == "fail" {
+		sr, sw := schema.Pipe[*schema.Message](1)
+		sw.Send(nil, errors.New("agent failed"))
+		sw.Close()
+		return sr, nil
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("echo: ", nil),
+		schema.AssistantMessage(input[len(input)-1].Content, nil),
+	}), nil
+}
+
+func TestA2A(t *testing.T) {
+	ctx := context.Background()
+	a := &echoAgent{}
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	handler, err := NewHandler(&ServerConfig{
+		Agent:     a,
+		AgentMeta: host.AgentMeta{Name: "echo", IntendedUse: "echo the last message"},
+		URL:       ts.URL + "/rpc",
+
+		MaxRequestBodySize: 1 << 10,
+	})
+	assert.NoError(t, err)
+	mux.Handle("/", handler)
+
+	specialist, err := NewSpecialist(ctx, &ClientConfig{BaseURL: ts.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", specialist.Name)
+	assert.Equal(t, "echo the last message", specialist.IntendedUse)
+	assert.NotNil(t, specialist.Streamable)
+
+	input := []*schema.Message{
+		schema.SystemMessage("ignored"),
+		schema.UserMessage("hello"),
+		schema.AssistantMessage("hi", nil),
+		schema.UserMessage("how are you"),
+	}
+
+	t.Run("send", func(t *testing.T) {
+		out, err := specialist.Invokable(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: how are you", out.Content)
+
+		last := a.inputs[len(a.inputs)-1]
+		assert.Len(t, last, 3)
+		assert.Equal(t, schema.Assistant, last[1].Role)
+		assert.Equal(t, "hi", last[1].Content)
+
+		_, err = specialist.Invokable(ctx, []*schema.Message{schema.UserMessage("fail")})
+		assert.ErrorContains(t, err, "agent failed")
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		sr, err := specialist.Streamable(ctx, input)
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: how are you", out.Content)
+
+		sr, err = specialist.Streamable(ctx, []*schema.Message{schema.UserMessage("fail")})
+		assert.NoError(t, err)
+		_, err = schema.ConcatMessageStream(sr)
+		assert.ErrorContains(t, err, "agent failed")
+	})
+
+	t.Run("method not found", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/rpc", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tasks/cancel","params":{}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "-32601")
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/rpc", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"message/send","params":{"text":"`+strings.Repeat("a", 2<<10)+`"}}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "-32700")
+		assert.Contains(t, string(body), "too large")
+	})
+}
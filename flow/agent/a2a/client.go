@@ -0,0 +1,270 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package a2a
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/agent/multiagent/host"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ClientConfig is the config of the client delegating to a remote A2A agent.
+type ClientConfig struct {
+	// BaseURL is the base url of the remote agent, whose agent card is fetched from BaseURL + AgentCardPath, required.
+	BaseURL string
+	// HTTPClient is optional, http.DefaultClient is used by default.
+	HTTPClient *http.Client
+	// AgentMeta overrides the name and intended use taken from the agent card, optional.
+	AgentMeta *host.AgentMeta
+}
+
+// Client calls a remote A2A agent.
+type Client struct {
+	card   *AgentCard
+	url    string
+	client *http.Client
+}
+
+// NewClient creates a Client, fetching the agent card of the remote agent.
+func NewClient(ctx context.Context, conf *ClientConfig) (*Client, error) {
+	if conf == nil || len(conf.BaseURL) == 0 {
+		return nil, errors.New("a2a client base url is empty")
+	}
+
+	httpClient := conf.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	baseURL := strings.TrimSuffix(conf.BaseURL, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+AgentCardPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch a2a agent card fail: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch a2a agent card fail, status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read a2a agent card fail: %w", err)
+	}
+	card := &AgentCard{}
+	if err = sonic.Unmarshal(data, card); err != nil {
+		return nil, fmt.Errorf("unmarshal a2a agent card fail: %w", err)
+	}
+
+	if conf.AgentMeta != nil {
+		card.Name = conf.AgentMeta.Name
+		card.Description = conf.AgentMeta.IntendedUse
+	}
+
+	url := card.URL
+	if len(url) == 0 {
+		url = baseURL
+	}
+
+	return &Client{card: card, url: url, client: httpClient}, nil
+}
+
+// AgentCard returns the agent card of the remote agent.
+func (c *Client) AgentCard() *AgentCard {
+	return c.card
+}
+
+// Generate sends the conversation to the remote agent by message/send, and returns the artifacts of the task as the answer.
+func (c *Client) Generate(ctx context.Context, input []*schema.Message, _ ...agent.AgentOption) (*schema.Message, error) {
+	resp, err := c.call(ctx, MethodSendMessage, input)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read a2a response fail: %w", err)
+	}
+
+	ret := &rpcResponse[*Task]{}
+	if err = sonic.Unmarshal(data, ret); err != nil {
+		return nil, fmt.Errorf("unmarshal a2a response fail: %w", err)
+	}
+	if ret.Error != nil {
+		return nil, ret.Error
+	}
+	if ret.Result == nil {
+		return nil, errors.New("a2a response has no result")
+	}
+	if ret.Result.Status.State == TaskStateFailed {
+		return nil, taskFailedError(ret.Result.Status)
+	}
+
+	var content string
+	for _, a := range ret.Result.Artifacts {
+		content += textOf(a.Parts)
+	}
+	return schema.AssistantMessage(content, nil), nil
+}
+
+// Stream sends the conversation to the remote agent by message/stream, and returns the artifact updates as the answer chunks.
+func (c *Client) Stream(ctx context.Context, input []*schema.Message, _ ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := c.call(ctx, MethodStreamMessage, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		ret := &rpcResponse[any]{}
+		if err = sonic.Unmarshal(data, ret); err == nil && ret.Error != nil {
+			return nil, ret.Error
+		}
+		return nil, fmt.Errorf("unexpected a2a stream response: %s", string(data))
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer func() {
+			_ = resp.Body.Close()
+			sw.Close()
+		}()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			event := &rpcResponse[*StreamEvent]{}
+			if err_ := sonic.UnmarshalString(strings.TrimSpace(strings.TrimPrefix(line, "data:")), event); err_ != nil {
+				sw.Send(nil, fmt.Errorf("unmarshal a2a stream event fail: %w", err_))
+				return
+			}
+			if event.Error != nil {
+				sw.Send(nil, event.Error)
+				return
+			}
+			e := event.Result
+			if e == nil {
+				continue
+			}
+
+			if e.Artifact != nil {
+				if closed := sw.Send(schema.AssistantMessage(textOf(e.Artifact.Parts), nil), nil); closed {
+					return
+				}
+			}
+			if e.Status != nil && e.Status.State == TaskStateFailed {
+				sw.Send(nil, taskFailedError(*e.Status))
+				return
+			}
+			if e.Final {
+				return
+			}
+		}
+		if err_ := scanner.Err(); err_ != nil {
+			sw.Send(nil, fmt.Errorf("read a2a stream fail: %w", err_))
+		}
+	}()
+
+	return sr, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, input []*schema.Message) (*http.Response, error) {
+	body, err := sonic.Marshal(&rpcRequest{
+		JSONRPC: "2.0",
+		ID:      uuid.NewString(),
+		Method:  method,
+		Params:  &MessageSendParams{Message: toA2AMessage(input, "user", uuid.NewString())},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal a2a request fail: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if method == MethodStreamMessage {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call a2a agent[%s] fail: %w", c.card.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("call a2a agent[%s] fail, status code: %d", c.card.Name, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// NewSpecialist creates a host.Specialist delegating to the remote A2A agent,
+// whose AgentMeta is taken from the agent card unless ClientConfig.AgentMeta is set.
+// e.g.
+//
+//	specialist, err := a2a.NewSpecialist(ctx, &a2a.ClientConfig{BaseURL: "http://weather-agent:8080"})
+//	hostMA, err := host.NewMultiAgent(ctx, &host.MultiAgentConfig{
+//		Host:        host.Host{ToolCallingModel: chatModel},
+//		Specialists: []*host.Specialist{specialist},
+//	})
+func NewSpecialist(ctx context.Context, conf *ClientConfig) (*host.Specialist, error) {
+	c, err := NewClient(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &host.Specialist{
+		AgentMeta: host.AgentMeta{
+			Name:        c.card.Name,
+			IntendedUse: c.card.Description,
+		},
+		Invokable: c.Generate,
+	}
+	if c.card.Capabilities.Streaming {
+		s.Streamable = c.Stream
+	}
+	return s, nil
+}
+
+func taskFailedError(status TaskStatus) error {
+	if status.Message != nil {
+		return fmt.Errorf("a2a task failed: %s", textOf(status.Message.Parts))
+	}
+	return errors.New("a2a task failed")
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package a2a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/agent/multiagent/host"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ServerConfig is the config of the A2A server.
+type ServerConfig struct {
+	// Agent is the agent to expose, required.
+	Agent Agent
+	// AgentMeta is used to generate the agent card, Name as the card name, IntendedUse as the description and the only skill.
+	AgentMeta host.AgentMeta
+	// URL is the public url of the JSON-RPC endpoint, i.e. where the handler is mounted, required.
+	URL string
+	// Version is the version of the agent, default is "1.0.0".
+	Version string
+	// AgentOptions are passed to every Generate or Stream of the agent.
+	AgentOptions []agent.AgentOption
+	// MaxRequestBodySize is the max size in bytes of the request body, default is 4MB.
+	// The requests exceeding it are answered with the JSON-RPC parse error.
+	MaxRequestBodySize int64
+}
+
+const defaultMaxRequestBodySize = 4 << 20
+
+type server struct {
+	agent       Agent
+	card        *AgentCard
+	options     []agent.AgentOption
+	maxBodySize int64
+}
+
+// NewHandler creates an http.Handler exposing the agent by the A2A protocol.
+// It serves the agent card on GET AgentCardPath, and the JSON-RPC methods message/send and message/stream on POST to any other path.
+// Every message/send or message/stream request creates a task which completes when the agent finishes.
+// e.g.
+//
+//	handler, err := a2a.NewHandler(&a2a.ServerConfig{
+//		Agent:     reactAgent,
+//		AgentMeta: host.AgentMeta{Name: "weather", IntendedUse: "answer questions about weather"},
+//		URL:       "http://localhost:8080",
+//	})
+//	err = http.ListenAndServe(":8080", handler)
+func NewHandler(conf *ServerConfig) (http.Handler, error) {
+	if conf == nil || conf.Agent == nil {
+		return nil, errors.New("a2a server agent is nil")
+	}
+	if len(conf.URL) == 0 {
+		return nil, errors.New("a2a server url is empty")
+	}
+	if len(conf.AgentMeta.Name) == 0 || len(conf.AgentMeta.IntendedUse) == 0 {
+		return nil, errors.New("a2a server agent meta name or intended use is empty")
+	}
+
+	version := conf.Version
+	if len(version) == 0 {
+		version = "1.0.0"
+	}
+	maxBodySize := conf.MaxRequestBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxRequestBodySize
+	}
+
+	return &server{
+		agent: conf.Agent,
+		card: &AgentCard{
+			Name:               conf.AgentMeta.Name,
+			Description:        conf.AgentMeta.IntendedUse,
+			URL:                conf.URL,
+			Version:            version,
+			ProtocolVersion:    ProtocolVersion,
+			Capabilities:       AgentCapabilities{Streaming: true},
+			DefaultInputModes:  []string{"text"},
+			DefaultOutputModes: []string{"text"},
+			Skills: []*AgentSkill{{
+				ID:          conf.AgentMeta.Name,
+				Name:        conf.AgentMeta.Name,
+				Description: conf.AgentMeta.IntendedUse,
+				Tags:        []string{},
+			}},
+		},
+		options:     conf.AgentOptions,
+		maxBodySize: maxBodySize,
+	}, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == AgentCardPath {
+		writeJSON(w, s.card)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodySize))
+	if err != nil {
+		writeJSON(w, errorResponse(nil, codeParseError, err.Error()))
+		return
+	}
+	req := &rpcRequest{}
+	if err = sonic.Unmarshal(body, req); err != nil {
+		writeJSON(w, errorResponse(nil, codeParseError, err.Error()))
+		return
+	}
+	if req.JSONRPC != "2.0" {
+		writeJSON(w, errorResponse(req.ID, codeInvalidRequest, "jsonrpc version must be 2.0"))
+		return
+	}
+	if req.Method != MethodSendMessage && req.Method != MethodStreamMessage {
+		writeJSON(w, errorResponse(req.ID, codeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method)))
+		return
+	}
+	if req.Params == nil || req.Params.Message == nil {
+		writeJSON(w, errorResponse(req.ID, codeInvalidParams, "message is required"))
+		return
+	}
+
+	input := fromA2AMessage(req.Params.Message)
+	taskID := uuid.NewString()
+	contextID := req.Params.Message.ContextID
+	if len(contextID) == 0 {
+		contextID = uuid.NewString()
+	}
+
+	if req.Method == MethodSendMessage {
+		s.send(w, r, req, input, taskID, contextID)
+		return
+	}
+	s.stream(w, r, req, input, taskID, contextID)
+}
+
+func (s *server) send(w http.ResponseWriter, r *http.Request, req *rpcRequest, input []*schema.Message, taskID, contextID string) {
+	out, err := s.agent.Generate(r.Context(), input, s.options...)
+	if err != nil {
+		writeJSON(w, errorResponse(req.ID, codeInternalError, err.Error()))
+		return
+	}
+
+	writeJSON(w, &rpcResponse[*Task]{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: &Task{
+			Kind:      "task",
+			ID:        taskID,
+			ContextID: contextID,
+			Status:    TaskStatus{State: TaskStateCompleted, Timestamp: now()},
+			Artifacts: []*Artifact{{
+				ArtifactID: uuid.NewString(),
+				Parts:      []*Part{{Kind: "text", Text: out.Content}},
+			}},
+		},
+	})
+}
+
+func (s *server) stream(w http.ResponseWriter, r *http.Request, req *rpcRequest, input []*schema.Message, taskID, contextID string) {
+	sr, err := s.agent.Stream(r.Context(), input, s.options...)
+	if err != nil {
+		writeJSON(w, errorResponse(req.ID, codeInternalError, err.Error()))
+		return
+	}
+	defer sr.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(e *StreamEvent) bool {
+		data, err_ := sonic.Marshal(&rpcResponse[*StreamEvent]{JSONRPC: "2.0", ID: req.ID, Result: e})
+		if err_ != nil {
+			return false
+		}
+		if _, err_ = fmt.Fprintf(w, "data: %s\n\n", data); err_ != nil {
+			return false
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return true
+	}
+
+	if !send(&StreamEvent{Kind: "status-update", TaskID: taskID, ContextID: contextID,
+		Status: &TaskStatus{State: TaskStateWorking, Timestamp: now()}}) {
+		return
+	}
+
+	artifactID := uuid.NewString()
+	for {
+		chunk, err_ := sr.Recv()
+		if err_ == io.EOF {
+			break
+		}
+		if err_ != nil {
+			send(&StreamEvent{Kind: "status-update", TaskID: taskID, ContextID: contextID, Final: true,
+				Status: &TaskStatus{
+					State:     TaskStateFailed,
+					Message:   &Message{Kind: "message", MessageID: uuid.NewString(), Role: "agent", Parts: []*Part{{Kind: "text", Text: err_.Error()}}},
+					Timestamp: now(),
+				}})
+			return
+		}
+		if chunk == nil || len(chunk.Content) == 0 {
+			continue
+		}
+		if !send(&StreamEvent{Kind: "artifact-update", TaskID: taskID, ContextID: contextID, Append: true,
+			Artifact: &Artifact{ArtifactID: artifactID, Parts: []*Part{{Kind: "text", Text: chunk.Content}}}}) {
+			return
+		}
+	}
+
+	send(&StreamEvent{Kind: "status-update", TaskID: taskID, ContextID: contextID, Final: true,
+		Status: &TaskStatus{State: TaskStateCompleted, Timestamp: now()}})
+}
+
+func errorResponse(id any, code int, msg string) *rpcResponse[any] {
+	return &rpcResponse[any]{JSONRPC: "2.0", ID: id, Error: &RPCError{Code: code, Message: msg}}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package a2a implements the Agent-to-Agent (A2A) protocol for eino agents:
+// NewHandler exposes an agent as an A2A server, and NewSpecialist delegates to a remote A2A agent
+// as a specialist of the host multi-agent system, so that multi-agent systems can span processes and languages.
+// Only text parts, and the message/send and message/stream methods over JSON-RPC are supported.
+package a2a
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// AgentCardPath is the well-known path of the agent card, relative to the base url of the agent.
+const AgentCardPath = "/.well-known/agent.json"
+
+// ProtocolVersion is the version of the A2A protocol implemented.
+const ProtocolVersion = "0.2.5"
+
+// JSON-RPC methods of the A2A protocol.
+const (
+	MethodSendMessage   = "message/send"
+	MethodStreamMessage = "message/stream"
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Agent is the agent exposed by the A2A server, e.g. react.Agent or host.MultiAgent.
+type Agent interface {
+	Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error)
+	Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error)
+}
+
+// AgentCard describes an A2A agent, served at AgentCardPath.
+type AgentCard struct {
+	Name               string            `json:"name"`
+	Description        string            `json:"description"`
+	URL                string            `json:"url"`
+	Version            string            `json:"version"`
+	ProtocolVersion    string            `json:"protocolVersion,omitempty"`
+	Capabilities       AgentCapabilities `json:"capabilities"`
+	DefaultInputModes  []string          `json:"defaultInputModes"`
+	DefaultOutputModes []string          `json:"defaultOutputModes"`
+	Skills             []*AgentSkill     `json:"skills"`
+}
+
+// AgentCapabilities are the optional capabilities of an A2A agent.
+type AgentCapabilities struct {
+	Streaming bool `json:"streaming"`
+}
+
+// AgentSkill is a capability of an A2A agent.
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// Message is a message of the A2A protocol.
+type Message struct {
+	Kind      string         `json:"kind"` // always "message"
+	MessageID string         `json:"messageId"`
+	Role      string         `json:"role"` // "user" or "agent"
+	Parts     []*Part        `json:"parts"`
+	ContextID string         `json:"contextId,omitempty"`
+	TaskID    string         `json:"taskId,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+}
+
+// Part is a part of a message or an artifact, only text parts are supported.
+type Part struct {
+	Kind     string         `json:"kind"` // "text"
+	Text     string         `json:"text,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// TaskState is the state of an A2A task.
+type TaskState string
+
+const (
+	TaskStateWorking   TaskState = "working"
+	TaskStateCompleted TaskState = "completed"
+	TaskStateFailed    TaskState = "failed"
+)
+
+// TaskStatus is the status of an A2A task.
+type TaskStatus struct {
+	State     TaskState `json:"state"`
+	Message   *Message  `json:"message,omitempty"`
+	Timestamp string    `json:"timestamp,omitempty"`
+}
+
+// Artifact is an output of an A2A task.
+type Artifact struct {
+	ArtifactID string  `json:"artifactId"`
+	Parts      []*Part `json:"parts"`
+}
+
+// Task is the result of message/send.
+type Task struct {
+	Kind      string      `json:"kind"` // always "task"
+	ID        string      `json:"id"`
+	ContextID string      `json:"contextId"`
+	Status    TaskStatus  `json:"status"`
+	Artifacts []*Artifact `json:"artifacts,omitempty"`
+}
+
+// StreamEvent is an event of message/stream, either a "status-update" or an "artifact-update" event.
+type StreamEvent struct {
+	Kind      string `json:"kind"`
+	TaskID    string `json:"taskId"`
+	ContextID string `json:"contextId"`
+
+	// set for status-update events
+	Status *TaskStatus `json:"status,omitempty"`
+	Final  bool        `json:"final,omitempty"`
+
+	// set for artifact-update events
+	Artifact  *Artifact `json:"artifact,omitempty"`
+	Append    bool      `json:"append,omitempty"`
+	LastChunk bool      `json:"lastChunk,omitempty"`
+}
+
+// MessageSendParams is the params of message/send and message/stream.
+type MessageSendParams struct {
+	Message  *Message       `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type rpcRequest struct {
+	JSONRPC string             `json:"jsonrpc"`
+	ID      any                `json:"id"`
+	Method  string             `json:"method"`
+	Params  *MessageSendParams `json:"params"`
+}
+
+type rpcResponse[T any] struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      any       `json:"id"`
+	Result  T         `json:"result,omitempty"`
+	Error   *RPCError `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error returned by an A2A agent.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("a2a rpc error, code: %d, message: %s", e.Code, e.Message)
+}
+
+// metaRole is the metadata key of a text part carrying the role of the eino message it is converted from,
+// which keeps the roles of the conversation when it is sent as a single A2A message.
+const metaRole = "eino_role"
+
+func toA2AMessage(msgs []*schema.Message, role string, id string) *Message {
+	m := &Message{
+		Kind:      "message",
+		MessageID: id,
+		Role:      role,
+	}
+	for _, msg := range msgs {
+		if msg == nil || msg.Role == schema.System {
+			continue
+		}
+		m.Parts = append(m.Parts, &Part{
+			Kind:     "text",
+			Text:     msg.Content,
+			Metadata: map[string]any{metaRole: string(msg.Role)},
+		})
+	}
+	return m
+}
+
+func fromA2AMessage(m *Message) []*schema.Message {
+	defaultRole := schema.User
+	if m.Role == "agent" {
+		defaultRole = schema.Assistant
+	}
+
+	msgs := make([]*schema.Message, 0, len(m.Parts))
+	for _, p := range m.Parts {
+		if p == nil || p.Kind != "text" {
+			continue
+		}
+		role := defaultRole
+		if r, ok := p.Metadata[metaRole].(string); ok && (r == string(schema.User) || r == string(schema.Assistant)) {
+			role = schema.RoleType(r)
+		}
+		msgs = append(msgs, &schema.Message{Role: role, Content: p.Text})
+	}
+	return msgs
+}
+
+func textOf(parts []*Part) string {
+	var s string
+	for _, p := range parts {
+		if p != nil && p.Kind == "text" {
+			s += p.Text
+		}
+	}
+	return s
+}
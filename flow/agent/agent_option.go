@@ -16,7 +16,12 @@
 
 package agent
 
-import "github.com/cloudwego/eino/compose"
+import (
+	"context"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
 
 // AgentOption is the common option type for various agent and multi-agent implementations.
 // For options intended to use with underlying graph or components, use WithComposeOptions to specify.
@@ -24,6 +29,12 @@ import "github.com/cloudwego/eino/compose"
 type AgentOption struct {
 	implSpecificOptFn any
 	composeOptions    []compose.Option
+
+	systemPrompt    *string
+	promptVariables map[string]any
+
+	specialist        string
+	specialistOptions []AgentOption
 }
 
 // GetComposeOptions returns all compose options from the given agent options.
@@ -68,3 +79,108 @@ func GetImplSpecificOptions[T any](base *T, opts ...AgentOption) *T {
 
 	return base
 }
+
+// WithSpecialistOptions returns an agent option that designates the given agent options to the specialist of the name
+// in multi-agent systems, e.g. model options, callbacks and compose options of a single specialist.
+// e.g.
+//
+//	out, err := multiAgent.Generate(ctx, input, agent.WithSpecialistOptions("coder",
+//		agent.WithComposeOptions(compose.WithChatModelOption(model.WithTemperature(0))),
+//		agent.WithComposeOptions(compose.WithCallbacks(coderHandler)),
+//	))
+func WithSpecialistOptions(name string, opts ...AgentOption) AgentOption {
+	return AgentOption{
+		specialist:        name,
+		specialistOptions: opts,
+	}
+}
+
+// GetSpecialistOptions returns the agent options designated to the specialists from the given agent options,
+// keyed by the names of the specialists.
+func GetSpecialistOptions(opts ...AgentOption) map[string][]AgentOption {
+	var result map[string][]AgentOption
+	for _, opt := range opts {
+		if opt.specialist == "" {
+			continue
+		}
+		if result == nil {
+			result = make(map[string][]AgentOption)
+		}
+		result[opt.specialist] = append(result[opt.specialist], opt.specialistOptions...)
+	}
+
+	return result
+}
+
+// WithSystemPrompt returns an agent option that replaces the configured system prompt of the agent for the run.
+// e.g. personalizing the persona per request without rebuilding the agent.
+func WithSystemPrompt(prompt string) AgentOption {
+	return AgentOption{
+		systemPrompt: &prompt,
+	}
+}
+
+// WithPromptVariables returns an agent option that specifies the variables to render the system prompt of the agent,
+// which is treated as an FString template when variables are given, e.g. "You are the assistant of {user_name}.".
+// The variables of multiple options are merged, the latter ones take precedence.
+func WithPromptVariables(vars map[string]any) AgentOption {
+	return AgentOption{
+		promptVariables: vars,
+	}
+}
+
+// RunPrompt is the system prompt and the prompt variables of a run, specified by WithSystemPrompt and WithPromptVariables.
+type RunPrompt struct {
+	// SystemPrompt replaces the configured system prompt if it's not nil.
+	SystemPrompt *string
+	Variables    map[string]any
+}
+
+// GetRunPrompt returns the system prompt and the prompt variables from the given agent options,
+// nil is returned if neither is specified.
+func GetRunPrompt(opts ...AgentOption) *RunPrompt {
+	var p *RunPrompt
+	for _, opt := range opts {
+		if opt.systemPrompt == nil && len(opt.promptVariables) == 0 {
+			continue
+		}
+		if p == nil {
+			p = &RunPrompt{}
+		}
+		if opt.systemPrompt != nil {
+			p.SystemPrompt = opt.systemPrompt
+		}
+		for k, v := range opt.promptVariables {
+			if p.Variables == nil {
+				p.Variables = make(map[string]any, len(opt.promptVariables))
+			}
+			p.Variables[k] = v
+		}
+	}
+
+	return p
+}
+
+// Render returns the system prompt of the run, which is the configured one unless replaced by WithSystemPrompt,
+// rendered with the prompt variables if any.
+// It's safe to call Render on a nil RunPrompt, the configured prompt is returned as is.
+func (p *RunPrompt) Render(ctx context.Context, configured string) (string, error) {
+	if p == nil {
+		return configured, nil
+	}
+
+	prompt := configured
+	if p.SystemPrompt != nil {
+		prompt = *p.SystemPrompt
+	}
+	if len(prompt) == 0 || len(p.Variables) == 0 {
+		return prompt, nil
+	}
+
+	msgs, err := schema.SystemMessage(prompt).Format(ctx, p.Variables, schema.FString)
+	if err != nil {
+		return "", err
+	}
+
+	return msgs[0].Content, nil
+}
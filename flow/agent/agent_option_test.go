@@ -0,0 +1,55 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, GetRunPrompt(WithComposeOptions()))
+
+	var p *RunPrompt
+	prompt, err := p.Render(ctx, "configured {x}")
+	assert.NoError(t, err)
+	assert.Equal(t, "configured {x}", prompt)
+
+	p = GetRunPrompt(
+		WithPromptVariables(map[string]any{"name": "Alice", "lang": "English"}),
+		WithSystemPrompt("ignored"),
+		WithPromptVariables(map[string]any{"lang": "French"}),
+		WithSystemPrompt("{name} speaks {lang}."),
+	)
+	assert.Equal(t, map[string]any{"name": "Alice", "lang": "French"}, p.Variables)
+
+	prompt, err = p.Render(ctx, "configured")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice speaks French.", prompt)
+
+	p = GetRunPrompt(WithPromptVariables(map[string]any{"name": "Bob"}))
+	prompt, err = p.Render(ctx, "Hi {name}.")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi Bob.", prompt)
+
+	_, err = p.Render(ctx, "Hi {missing}.")
+	assert.Error(t, err)
+}
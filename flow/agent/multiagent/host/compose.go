@@ -18,10 +18,12 @@ package host
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/flow/agent"
 	"github.com/cloudwego/eino/schema"
 )
@@ -34,11 +36,17 @@ const (
 	multiIntentSummarizeNodeKey        = "multi_intents_summarize"
 	defaultSummarizerPrompt            = "summarize the answers from the specialists into a single answer."
 	map2ListConverterNodeKey           = "map_to_list"
+	answersFeedbackNodeKey             = "specialist_answers_feedback"
 )
 
 type state struct {
 	msgs              []*schema.Message
 	isMultipleIntents bool
+
+	// used by the supervisor mode only
+	rounds  int
+	handOff *schema.Message
+	answers map[string]*schema.Message
 }
 
 // NewMultiAgent creates a new host multi-agent system.
@@ -51,6 +59,22 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		return nil, err
 	}
 
+	ma := &MultiAgent{config: *config}
+	ma.config.Specialists = append([]*Specialist(nil), config.Specialists...)
+
+	compiled, err := ma.compile(ctx, &ma.config)
+	if err != nil {
+		return nil, err
+	}
+
+	ma.compiled = compiled
+	return ma, nil
+}
+
+// compile builds and compiles the graph of the multi-agent by the config.
+func (ma *MultiAgent) compile(ctx context.Context, config *MultiAgentConfig) (*compiledMultiAgent, error) {
+	compiled := &compiledMultiAgent{specialists: make(map[string]bool, len(config.Specialists))}
+
 	hostKeyName := defaultHostNodeKey
 	if config.HostNodeName != "" {
 		hostKeyName = config.HostNodeName
@@ -97,11 +121,12 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 			}),
 		})
 
-		if err := addSpecialistAgent(specialist, g); err != nil {
+		if err := addSpecialistAgent(specialist, config.MaxRounds > 0, g); err != nil {
 			return nil, err
 		}
 
 		agentMap[specialist.Name] = true
+		compiled.specialists[specialist.Name] = specialist.Invokable != nil || specialist.Streamable != nil
 	}
 
 	chatModel, err := agent.ChatModelWithTools(config.Host.ChatModel, config.Host.ToolCallingModel, agentTools)
@@ -109,7 +134,7 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		return nil, err
 	}
 
-	if err = addHostAgent(chatModel, hostPrompt, g, hostKeyName); err != nil {
+	if err = addHostAgent(ma, chatModel, hostPrompt, g, hostKeyName); err != nil {
 		return nil, err
 	}
 
@@ -126,11 +151,22 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		return nil, err
 	}
 
-	if err = addSingleIntentAnswerNode(g); err != nil {
+	if config.MaxRounds > 0 {
+		if err = addAnswersFeedbackNode(g); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = addSingleIntentAnswerNode(config.MaxRounds, g); err != nil {
 		return nil, err
 	}
 
-	if err = addMultiIntentsSummarizeNode(config.Summarizer, g); err != nil {
+	specialistNames := make([]string, 0, len(config.Specialists))
+	for _, specialist := range config.Specialists {
+		specialistNames = append(specialistNames, specialist.Name)
+	}
+
+	if err = addMultiIntentsSummarizeNode(config.Summarizer, specialistNames, config.MaxRounds, g); err != nil {
 		return nil, err
 	}
 
@@ -139,19 +175,98 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 	}
 
 	compileOpts := []compose.GraphCompileOption{compose.WithNodeTriggerMode(compose.AnyPredecessor), compose.WithGraphName(name)}
+	if config.MaxRounds > 0 {
+		// every round takes at most 7 steps: host, converter, specialists, collector, map_to_list, summarizer and feedback
+		compileOpts = append(compileOpts, compose.WithMaxRunSteps(len(config.Specialists)+10+7*config.MaxRounds))
+	}
 	r, err := g.Compile(ctx, compileOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &MultiAgent{
-		runnable:         r,
-		graph:            g,
-		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
-	}, nil
+	compiled.runnable = r
+	compiled.graph = g
+	compiled.graphAddNodeOpts = []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)}
+	return compiled, nil
+}
+
+// AddSpecialist registers a specialist to the multi-agent after construction.
+// The graph of the multi-agent is recompiled with the specialist, and replaces the current one once compiled successfully,
+// the runs in progress keep running with the graph they started with, so the roster can evolve without downtime.
+// Note: the graphs exported by ExportGraph before are not affected.
+func (ma *MultiAgent) AddSpecialist(ctx context.Context, specialist *Specialist) error {
+	if specialist == nil {
+		return errors.New("specialist is nil")
+	}
+
+	ma.updateMu.Lock()
+	defer ma.updateMu.Unlock()
+
+	for _, s := range ma.config.Specialists {
+		if s.Name == specialist.Name {
+			return fmt.Errorf("specialist %s already exists", specialist.Name)
+		}
+	}
+
+	config := ma.config
+	config.Specialists = append(append(make([]*Specialist, 0, len(ma.config.Specialists)+1), ma.config.Specialists...), specialist)
+
+	return ma.update(ctx, &config)
+}
+
+// RemoveSpecialist unregisters the specialist of the name from the multi-agent, in the same way as AddSpecialist.
+// The last specialist can't be removed.
+func (ma *MultiAgent) RemoveSpecialist(ctx context.Context, name string) error {
+	ma.updateMu.Lock()
+	defer ma.updateMu.Unlock()
+
+	config := ma.config
+	config.Specialists = make([]*Specialist, 0, len(ma.config.Specialists))
+	for _, s := range ma.config.Specialists {
+		if s.Name != name {
+			config.Specialists = append(config.Specialists, s)
+		}
+	}
+	if len(config.Specialists) == len(ma.config.Specialists) {
+		return fmt.Errorf("specialist %s not found in host multi agent: %w", name, einoerr.ErrNodeNotFound)
+	}
+
+	return ma.update(ctx, &config)
+}
+
+func (ma *MultiAgent) update(ctx context.Context, config *MultiAgentConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	compiled, err := ma.compile(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	ma.mu.Lock()
+	ma.compiled = compiled
+	ma.mu.Unlock()
+
+	ma.config = *config
+	return nil
 }
 
-func addSpecialistAgent(specialist *Specialist, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+func addSpecialistAgent(specialist *Specialist, recordAnswer bool, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+	opts := []compose.GraphAddNodeOpt{compose.WithNodeName(specialist.Name), compose.WithOutputKey(specialist.Name)}
+	if recordAnswer {
+		// keep the answer in state, to be fed back to the host in supervisor mode
+		opts = append(opts, compose.WithStatePostHandler(func(_ context.Context, out map[string]any, state *state) (map[string]any, error) {
+			if msg, ok := out[specialist.Name].(*schema.Message); ok {
+				if state.answers == nil {
+					state.answers = make(map[string]*schema.Message)
+				}
+				state.answers[specialist.Name] = msg
+			}
+			return out, nil
+		}))
+	}
+
 	if specialist.Invokable != nil || specialist.Streamable != nil {
 		lambda, err := compose.AnyLambda(specialist.Invokable, specialist.Streamable, nil, nil, compose.WithLambdaType("Specialist"))
 		if err != nil {
@@ -160,8 +275,7 @@ func addSpecialistAgent(specialist *Specialist, g *compose.Graph[[]*schema.Messa
 		preHandler := func(_ context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
 			return state.msgs, nil // replace the tool call message with input msgs stored in state
 		}
-		if err := g.AddLambdaNode(specialist.Name, lambda, compose.WithStatePreHandler(preHandler),
-			compose.WithNodeName(specialist.Name), compose.WithOutputKey(specialist.Name)); err != nil {
+		if err := g.AddLambdaNode(specialist.Name, lambda, append(opts, compose.WithStatePreHandler(preHandler))...); err != nil {
 			return err
 		}
 	} else if specialist.ChatModel != nil {
@@ -176,7 +290,7 @@ func addSpecialistAgent(specialist *Specialist, g *compose.Graph[[]*schema.Messa
 			return state.msgs, nil // replace the tool call message with input msgs stored in state
 		}
 
-		if err := g.AddChatModelNode(specialist.Name, specialist.ChatModel, compose.WithStatePreHandler(preHandler), compose.WithNodeName(specialist.Name), compose.WithOutputKey(specialist.Name)); err != nil {
+		if err := g.AddChatModelNode(specialist.Name, specialist.ChatModel, append(opts, compose.WithStatePreHandler(preHandler))...); err != nil {
 			return err
 		}
 	}
@@ -184,9 +298,13 @@ func addSpecialistAgent(specialist *Specialist, g *compose.Graph[[]*schema.Messa
 	return g.AddEdge(specialist.Name, specialistsAnswersCollectorNodeKey)
 }
 
-func addHostAgent(model model.BaseChatModel, prompt string, g *compose.Graph[[]*schema.Message, *schema.Message], hostNodeName string) error {
-	preHandler := func(_ context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
+func addHostAgent(ma *MultiAgent, model model.BaseChatModel, configuredPrompt string, g *compose.Graph[[]*schema.Message, *schema.Message], hostNodeName string) error {
+	preHandler := func(ctx context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
 		state.msgs = input
+		prompt, err := ma.getRunPrompt(ctx).Render(ctx, configuredPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("render host system prompt fail: %w", err)
+		}
 		if len(prompt) == 0 {
 			return input, nil
 		}
@@ -230,12 +348,13 @@ func addMultiSpecialistsBranch(convertorName string, agentMap map[string]bool, g
 			results[toolCall.Function.Name] = true
 		}
 
-		if len(results) > 1 {
-			_ = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
-				state.isMultipleIntents = true
-				return nil
-			})
-		}
+		_ = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			state.isMultipleIntents = len(results) > 1
+			state.rounds++
+			state.handOff = input[0]
+			state.answers = nil
+			return nil
+		})
 
 		return results, nil
 	}, agentMap)
@@ -243,7 +362,7 @@ func addMultiSpecialistsBranch(convertorName string, agentMap map[string]bool, g
 	return g.AddBranch(convertorName, branch)
 }
 
-func addSingleIntentAnswerNode(g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+func addSingleIntentAnswerNode(maxRounds int, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
 	rc := func(ctx context.Context, input *schema.StreamReader[map[string]any]) (*schema.StreamReader[*schema.Message], error) {
 		return schema.StreamReaderWithConvert(input, func(msgs map[string]any) (*schema.Message, error) {
 			if len(msgs) != 1 {
@@ -257,7 +376,7 @@ func addSingleIntentAnswerNode(g *compose.Graph[[]*schema.Message, *schema.Messa
 	}
 
 	_ = g.AddLambdaNode(singleIntentAnswerNodeKey, compose.TransformableLambda(rc))
-	return g.AddEdge(singleIntentAnswerNodeKey, compose.END)
+	return addAnswerOutput(singleIntentAnswerNodeKey, maxRounds, g)
 }
 
 func addAfterSpecialistsBranch(g *compose.Graph[[]*schema.Message, *schema.Message]) error {
@@ -283,17 +402,37 @@ func addAfterSpecialistsBranch(g *compose.Graph[[]*schema.Message, *schema.Messa
 	return g.AddBranch(specialistsAnswersCollectorNodeKey, b)
 }
 
-func addMultiIntentsSummarizeNode(summarizer *Summarizer, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+func addMultiIntentsSummarizeNode(summarizer *Summarizer, specialistNames []string, maxRounds int, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
 	map2list := func(ctx context.Context, input map[string]any) ([]*schema.Message, error) {
-		var output []*schema.Message
-		for k := range input {
-			output = append(output, input[k].(*schema.Message))
+		// keep the answers in the order of specialists, so that the summarizer input is deterministic
+		output := make([]*schema.Message, 0, len(input))
+		for _, name := range specialistNames {
+			v, ok := input[name]
+			if !ok {
+				continue
+			}
+			msg, ok := v.(*schema.Message)
+			if !ok {
+				return nil, fmt.Errorf("specialist %s output type %T, but expected *schema.Message", name, v)
+			}
+			if msg != nil && msg.Name == "" {
+				named := *msg
+				named.Name = name
+				msg = &named
+			}
+			output = append(output, msg)
 		}
 		return output, nil
 	}
 
 	_ = g.AddLambdaNode(map2ListConverterNodeKey, compose.InvokableLambda(map2list))
 
+	if summarizer != nil && summarizer.Aggregator != nil {
+		_ = g.AddLambdaNode(multiIntentSummarizeNodeKey, compose.InvokableLambda(summarizer.Aggregator))
+		_ = g.AddEdge(map2ListConverterNodeKey, multiIntentSummarizeNodeKey)
+		return addAnswerOutput(multiIntentSummarizeNodeKey, maxRounds, g)
+	}
+
 	if summarizer != nil {
 		_ = g.AddChatModelNode(multiIntentSummarizeNodeKey, summarizer.ChatModel,
 			compose.WithStatePreHandler(func(ctx context.Context, in []*schema.Message, state *state) ([]*schema.Message, error) {
@@ -314,7 +453,7 @@ func addMultiIntentsSummarizeNode(summarizer *Summarizer, g *compose.Graph[[]*sc
 				return out, nil
 			}))
 		_ = g.AddEdge(map2ListConverterNodeKey, multiIntentSummarizeNodeKey)
-		return g.AddEdge(multiIntentSummarizeNodeKey, compose.END)
+		return addAnswerOutput(multiIntentSummarizeNodeKey, maxRounds, g)
 	}
 
 	s := func(ctx context.Context, input []*schema.Message) (*schema.Message, error) {
@@ -331,5 +470,58 @@ func addMultiIntentsSummarizeNode(summarizer *Summarizer, g *compose.Graph[[]*sc
 
 	_ = g.AddLambdaNode(multiIntentSummarizeNodeKey, compose.InvokableLambda(s))
 	_ = g.AddEdge(map2ListConverterNodeKey, multiIntentSummarizeNodeKey)
-	return g.AddEdge(multiIntentSummarizeNodeKey, compose.END)
+	return addAnswerOutput(multiIntentSummarizeNodeKey, maxRounds, g)
+}
+
+// addAnswerOutput ends the run with the answer, or feeds the answer back to the host in supervisor mode.
+func addAnswerOutput(answerNodeKey string, maxRounds int, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+	if maxRounds <= 0 {
+		return g.AddEdge(answerNodeKey, compose.END)
+	}
+
+	b := compose.NewStreamGraphBranch(func(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (string, error) {
+		sr.Close()
+
+		var rounds int
+		_ = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			rounds = state.rounds
+			return nil
+		})
+
+		if rounds >= maxRounds {
+			return compose.END, nil
+		}
+		return answersFeedbackNodeKey, nil
+	}, map[string]bool{
+		compose.END:            true,
+		answersFeedbackNodeKey: true,
+	})
+
+	return g.AddBranch(answerNodeKey, b)
+}
+
+// addAnswersFeedbackNode appends the hand off message of the host and the answers of the specialists as tool results
+// to the conversation, which is the input of the host in the next round.
+func addAnswersFeedbackNode(g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+	feedback := func(ctx context.Context, _ *schema.Message) (output []*schema.Message, err error) {
+		err = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			output = make([]*schema.Message, 0, len(state.msgs)+1+len(state.handOff.ToolCalls))
+			output = append(output, state.msgs...)
+			output = append(output, state.handOff)
+			for _, toolCall := range state.handOff.ToolCalls {
+				var content string
+				if answer := state.answers[toolCall.Function.Name]; answer != nil {
+					content = answer.Content
+				}
+				output = append(output, schema.ToolMessage(content, toolCall.ID, schema.WithToolName(toolCall.Function.Name)))
+			}
+			return nil
+		})
+		return output, err
+	}
+
+	if err := g.AddLambdaNode(answersFeedbackNodeKey, compose.InvokableLambda(feedback)); err != nil {
+		return err
+	}
+	return g.AddEdge(answersFeedbackNodeKey, defaultHostNodeKey)
 }
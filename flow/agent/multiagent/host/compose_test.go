@@ -25,8 +25,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/cloudwego/eino/callbacks"
+	modelcomp "github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/flow/agent"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/internal/mock/components/model"
@@ -669,6 +672,190 @@ func TestHostMultiAgent(t *testing.T) {
 	)
 }
 
+func TestHostMultiAgentWithToolCallingModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	// no expectation is set on the deprecated ChatModel, so calling BindTools on it fails the test
+	deprecatedHostLLM := model.NewMockChatModel(ctrl)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	boundHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockSpecialistLLM := model.NewMockToolCallingChatModel(ctrl)
+
+	ctx := context.Background()
+
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(boundHostLLM, nil).Times(1)
+
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host: Host{
+			ToolCallingModel: mockHostLLM,
+			ChatModel:        deprecatedHostLLM,
+		},
+		Specialists: []*Specialist{
+			{
+				ChatModel: mockSpecialistLLM,
+				AgentMeta: AgentMeta{
+					Name:        "specialist",
+					IntendedUse: "do stuff",
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	boundHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(&schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{
+				Index: generic.PtrOf(0),
+				Function: schema.FunctionCall{
+					Name:      "specialist",
+					Arguments: `{"reason": "specialist is the best"}`,
+				},
+			},
+		},
+	}, nil).Times(1)
+	mockSpecialistLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(&schema.Message{
+		Role:    schema.Assistant,
+		Content: "specialist answer",
+	}, nil).Times(1)
+
+	out, err := hostMA.Generate(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "specialist answer", out.Content)
+}
+
+func TestHostMultiAgentAggregator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(mockHostLLM, nil).AnyTimes()
+
+	newSpecialist := func(name string) *Specialist {
+		return &Specialist{
+			Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+				return &schema.Message{Role: schema.Assistant, Content: name + " answer"}, nil
+			},
+			AgentMeta: AgentMeta{
+				Name:        name,
+				IntendedUse: "answer as " + name,
+			},
+		}
+	}
+
+	ctx := context.Background()
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host: Host{
+			ToolCallingModel: mockHostLLM,
+		},
+		Specialists: []*Specialist{
+			newSpecialist("b"),
+			newSpecialist("a"),
+			newSpecialist("c"),
+		},
+		Summarizer: &Summarizer{
+			Aggregator: func(ctx context.Context, answers []*schema.Message) (*schema.Message, error) {
+				out := &schema.Message{Role: schema.Assistant}
+				for _, answer := range answers {
+					out.Content += answer.Name + ":" + answer.Content + ";"
+				}
+				return out, nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(&schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{Index: generic.PtrOf(0), Function: schema.FunctionCall{Name: "c", Arguments: `{"reason": "c"}`}},
+			{Index: generic.PtrOf(1), Function: schema.FunctionCall{Name: "b", Arguments: `{"reason": "b"}`}},
+		},
+	}, nil).Times(1)
+
+	out, err := hostMA.Generate(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b:b answer;c:c answer;", out.Content)
+
+	_, err = NewMultiAgent(ctx, &MultiAgentConfig{
+		Host:        Host{ToolCallingModel: mockHostLLM},
+		Specialists: []*Specialist{newSpecialist("a")},
+		Summarizer:  &Summarizer{},
+	})
+	assert.Error(t, err)
+}
+
+func TestHostMultiAgentSupervisor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(mockHostLLM, nil).AnyTimes()
+
+	var specialistCalls int
+	specialist := &Specialist{
+		Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+			specialistCalls++
+			return &schema.Message{Role: schema.Assistant, Content: "specialist answer"}, nil
+		},
+		AgentMeta: AgentMeta{
+			Name:        "specialist",
+			IntendedUse: "do stuff",
+		},
+	}
+
+	handOffMsg := &schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{
+				ID:       "call_1",
+				Index:    generic.PtrOf(0),
+				Function: schema.FunctionCall{Name: specialist.Name, Arguments: `{"reason": "specialist"}`},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host:        Host{ToolCallingModel: mockHostLLM},
+		Specialists: []*Specialist{specialist},
+		MaxRounds:   2,
+	})
+	assert.NoError(t, err)
+
+	t.Run("host answers after specialist result", func(t *testing.T) {
+		specialistCalls = 0
+		input := []*schema.Message{schema.UserMessage("hello")}
+
+		mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(handOffMsg, nil).Times(1)
+		mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, msgs []*schema.Message, opts ...any) (*schema.Message, error) {
+				// system prompt, user input, hand off and tool result
+				assert.Len(t, msgs, 4)
+				assert.Equal(t, handOffMsg, msgs[2])
+				assert.Equal(t, schema.Tool, msgs[3].Role)
+				assert.Equal(t, "call_1", msgs[3].ToolCallID)
+				assert.Equal(t, "specialist answer", msgs[3].Content)
+				return &schema.Message{Role: schema.Assistant, Content: "final answer"}, nil
+			}).Times(1)
+
+		out, err := hostMA.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "final answer", out.Content)
+		assert.Equal(t, 1, specialistCalls)
+	})
+
+	t.Run("max rounds reached", func(t *testing.T) {
+		specialistCalls = 0
+		mockHostLLM.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, msgs []*schema.Message, opts ...any) (*schema.StreamReader[*schema.Message], error) {
+				return schema.StreamReaderFromArray([]*schema.Message{handOffMsg}), nil
+			}).Times(2)
+
+		sr, err := hostMA.Stream(ctx, []*schema.Message{schema.UserMessage("hello")})
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "specialist answer", out.Content)
+		assert.Equal(t, 2, specialistCalls)
+	})
+}
+
 type mockAgentCallback struct {
 	infos []*HandOffInfo
 	wg    sync.WaitGroup
@@ -689,3 +876,209 @@ func newMockAgentCallback(expects int) *mockAgentCallback {
 	m.wg.Add(expects)
 	return m
 }
+
+func TestHostMultiAgentWithSystemPrompt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockSpecialistLLM := model.NewMockToolCallingChatModel(ctrl)
+
+	ctx := context.Background()
+
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(mockHostLLM, nil).Times(1)
+
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host: Host{
+			ToolCallingModel: mockHostLLM,
+			SystemPrompt:     "route the task of {user}.",
+		},
+		Specialists: []*Specialist{
+			{
+				ChatModel:    mockSpecialistLLM,
+				SystemPrompt: "specialist prompt",
+				AgentMeta: AgentMeta{
+					Name:        "specialist",
+					IntendedUse: "do stuff",
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	var hostPrompts []string
+	mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...any) (*schema.Message, error) {
+			hostPrompts = append(hostPrompts, input[0].Content)
+			return schema.AssistantMessage("direct answer", nil), nil
+		}).Times(2)
+
+	_, err = hostMA.Generate(ctx, []*schema.Message{schema.UserMessage("hi")},
+		agent.WithPromptVariables(map[string]any{"user": "Alice"}))
+	assert.NoError(t, err)
+	_, err = hostMA.Generate(ctx, []*schema.Message{schema.UserMessage("hi")},
+		agent.WithSystemPrompt("per run prompt"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"route the task of Alice.", "per run prompt"}, hostPrompts)
+}
+
+func TestTextTolerantStreamToolCallChecker(t *testing.T) {
+	ctx := context.Background()
+	toolCall := &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{
+		Index:    generic.PtrOf(0),
+		Function: schema.FunctionCall{Name: "specialist"},
+	}}}
+
+	checker := NewTextTolerantStreamToolCallChecker(20)
+
+	isToolCall, err := checker(ctx, schema.StreamReaderFromArray([]*schema.Message{
+		{ReasoningContent: "thinking about which specialist fits the task best"},
+		schema.AssistantMessage("I'll ask ", nil),
+		schema.AssistantMessage("them.", nil),
+		toolCall,
+	}))
+	assert.NoError(t, err)
+	assert.True(t, isToolCall)
+
+	isToolCall, err = checker(ctx, schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("the answer is ", nil),
+		schema.AssistantMessage("longer than the window", nil),
+		toolCall,
+	}))
+	assert.NoError(t, err)
+	assert.False(t, isToolCall)
+
+	isToolCall, err = checker(ctx, schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("short", nil),
+	}))
+	assert.NoError(t, err)
+	assert.False(t, isToolCall)
+}
+
+func TestHostMultiAgentSpecialistOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(mockHostLLM, nil).AnyTimes()
+	mockCoderLLM := model.NewMockToolCallingChatModel(ctrl)
+
+	var writerOpts []agent.AgentOption
+	ctx := context.Background()
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host: Host{ToolCallingModel: mockHostLLM},
+		Specialists: []*Specialist{
+			{
+				ChatModel: mockCoderLLM,
+				AgentMeta: AgentMeta{Name: "coder", IntendedUse: "write code"},
+			},
+			{
+				Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+					writerOpts = opts
+					return schema.AssistantMessage("writer answer", nil), nil
+				},
+				AgentMeta: AgentMeta{Name: "writer", IntendedUse: "write docs"},
+			},
+		},
+		Summarizer: &Summarizer{
+			Aggregator: func(ctx context.Context, answers []*schema.Message) (*schema.Message, error) {
+				return schema.AssistantMessage("done", nil), nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(&schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{Index: generic.PtrOf(0), Function: schema.FunctionCall{Name: "coder", Arguments: `{"reason": "code"}`}},
+			{Index: generic.PtrOf(1), Function: schema.FunctionCall{Name: "writer", Arguments: `{"reason": "docs"}`}},
+		},
+	}, nil).Times(1)
+	mockCoderLLM.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, msgs []*schema.Message, opts ...modelcomp.Option) (*schema.Message, error) {
+			assert.Equal(t, float32(0.1), *modelcomp.GetCommonOptions(nil, opts...).Temperature)
+			return schema.AssistantMessage("coder answer", nil), nil
+		}).Times(1)
+
+	var coderCallbacks int
+	hostCallback := newMockAgentCallback(2)
+	coderHandler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		coderCallbacks++
+		return ctx
+	}).Build()
+
+	out, err := hostMA.Generate(ctx, nil,
+		WithAgentCallbacks(hostCallback),
+		agent.WithSpecialistOptions("coder",
+			agent.WithComposeOptions(compose.WithChatModelOption(modelcomp.WithTemperature(0.1))),
+			agent.WithComposeOptions(compose.WithCallbacks(coderHandler)),
+		),
+		agent.WithSpecialistOptions("writer", agent.WithSystemPrompt("write briefly")),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out.Content)
+	assert.Equal(t, 1, coderCallbacks)
+	hostCallback.wg.Wait()
+	assert.Len(t, hostCallback.infos, 2)
+	assert.Len(t, writerOpts, 1)
+	assert.Equal(t, "write briefly", *agent.GetRunPrompt(writerOpts...).SystemPrompt)
+
+	_, err = hostMA.Generate(ctx, nil, agent.WithSpecialistOptions("unknown"))
+	assert.ErrorContains(t, err, "specialist unknown not found")
+	assert.ErrorIs(t, err, einoerr.ErrNodeNotFound)
+}
+
+func TestHostMultiAgentDynamicSpecialists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+
+	var handOffTools [][]*schema.ToolInfo
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).DoAndReturn(func(tools []*schema.ToolInfo) (modelcomp.ToolCallingChatModel, error) {
+		handOffTools = append(handOffTools, tools)
+		return mockHostLLM, nil
+	}).AnyTimes()
+
+	newSpecialist := func(name string) *Specialist {
+		return &Specialist{
+			Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+				return schema.AssistantMessage(name+" answer", nil), nil
+			},
+			AgentMeta: AgentMeta{Name: name, IntendedUse: "answer as " + name},
+		}
+	}
+	handOff := func(name string) *schema.Message {
+		return &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{
+			{Index: generic.PtrOf(0), Function: schema.FunctionCall{Name: name, Arguments: `{"reason": "r"}`}},
+		}}
+	}
+
+	ctx := context.Background()
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host:        Host{ToolCallingModel: mockHostLLM},
+		Specialists: []*Specialist{newSpecialist("a")},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, hostMA.AddSpecialist(ctx, newSpecialist("b")))
+	assert.Len(t, handOffTools[len(handOffTools)-1], 2)
+
+	mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(handOff("b"), nil).Times(1)
+	out, err := hostMA.Generate(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b answer", out.Content)
+
+	assert.ErrorContains(t, hostMA.AddSpecialist(ctx, newSpecialist("b")), "specialist b already exists")
+	assert.Error(t, hostMA.AddSpecialist(ctx, &Specialist{AgentMeta: AgentMeta{Name: "c", IntendedUse: "c"}}))
+
+	assert.NoError(t, hostMA.RemoveSpecialist(ctx, "a"))
+	assert.Len(t, handOffTools[len(handOffTools)-1], 1)
+	assert.Equal(t, "b", handOffTools[len(handOffTools)-1][0].Name)
+
+	mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(handOff("b"), nil).Times(1)
+	out, err = hostMA.Generate(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b answer", out.Content)
+
+	assert.ErrorIs(t, hostMA.RemoveSpecialist(ctx, "a"), einoerr.ErrNodeNotFound)
+	assert.ErrorContains(t, hostMA.RemoveSpecialist(ctx, "b"), "specialists are empty")
+	_, err = hostMA.Generate(ctx, nil, agent.WithSpecialistOptions("a"))
+	assert.ErrorContains(t, err, "specialist a not found")
+}
@@ -22,9 +22,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/flow/agent"
 	"github.com/cloudwego/eino/schema"
 )
@@ -33,23 +36,53 @@ import (
 // A host agent is responsible for deciding which specialist to 'hand off' the task to.
 // One or more specialist agents are responsible for completing the task.
 type MultiAgent struct {
+	mu       sync.RWMutex
+	compiled *compiledMultiAgent
+
+	// updateMu serializes the updates of the specialists, config is the config the current graph is compiled with
+	updateMu sync.Mutex
+	config   MultiAgentConfig
+}
+
+type compiledMultiAgent struct {
 	runnable         compose.Runnable[[]*schema.Message, *schema.Message]
 	graph            *compose.Graph[[]*schema.Message, *schema.Message]
 	graphAddNodeOpts []compose.GraphAddNodeOpt
+
+	// specialists maps the names of the specialists to whether they're agents (Invokable / Streamable) instead of chat models
+	specialists map[string]bool
 }
 
-func (ma *MultiAgent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
-	composeOptions := agent.GetComposeOptions(opts...)
+func (ma *MultiAgent) current() *compiledMultiAgent {
+	ma.mu.RLock()
+	defer ma.mu.RUnlock()
+	return ma.compiled
+}
 
-	handler := convertCallbacks(opts...)
-	if handler != nil {
-		composeOptions = append(composeOptions, compose.WithCallbacks(handler).DesignateNode(ma.HostNodeKey()))
+func (ma *MultiAgent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+	compiled := ma.current()
+	composeOptions, err := ma.getComposeOptions(compiled, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return ma.runnable.Invoke(ctx, input, composeOptions...)
+	return compiled.runnable.Invoke(ma.withRunPrompt(ctx, opts...), input, composeOptions...)
 }
 
 func (ma *MultiAgent) Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+	compiled := ma.current()
+	composeOptions, err := ma.getComposeOptions(compiled, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return compiled.runnable.Stream(ma.withRunPrompt(ctx, opts...), input, composeOptions...)
+}
+
+// getComposeOptions converts the agent options to compose options,
+// the options designated to a specialist by agent.WithSpecialistOptions are designated to the node of the specialist:
+// agents receive all of them as their own agent options, while only the compose options apply to chat models.
+func (ma *MultiAgent) getComposeOptions(compiled *compiledMultiAgent, opts ...agent.AgentOption) ([]compose.Option, error) {
 	composeOptions := agent.GetComposeOptions(opts...)
 
 	handler := convertCallbacks(opts...)
@@ -57,18 +90,55 @@ func (ma *MultiAgent) Stream(ctx context.Context, input []*schema.Message, opts
 		composeOptions = append(composeOptions, compose.WithCallbacks(handler).DesignateNode(ma.HostNodeKey()))
 	}
 
-	return ma.runnable.Stream(ctx, input, composeOptions...)
+	for name, specialistOpts := range agent.GetSpecialistOptions(opts...) {
+		isAgent, ok := compiled.specialists[name]
+		if !ok {
+			return nil, fmt.Errorf("specialist %s not found in host multi agent: %w", name, einoerr.ErrNodeNotFound)
+		}
+
+		if isAgent {
+			lambdaOpts := make([]any, len(specialistOpts))
+			for i := range specialistOpts {
+				lambdaOpts[i] = specialistOpts[i]
+			}
+			composeOptions = append(composeOptions, compose.WithLambdaOption(lambdaOpts...).DesignateNode(name))
+			continue
+		}
+
+		composeOptions = append(composeOptions,
+			compose.DesignateSubGraph(compose.NewNodePath(name), agent.GetComposeOptions(specialistOpts...)...)...)
+	}
+
+	return composeOptions, nil
 }
 
 // ExportGraph exports the underlying graph from MultiAgent, along with the []compose.GraphAddNodeOpt to be used when adding this graph to another graph.
 func (ma *MultiAgent) ExportGraph() (compose.AnyGraph, []compose.GraphAddNodeOpt) {
-	return ma.graph, ma.graphAddNodeOpts
+	compiled := ma.current()
+	return compiled.graph, compiled.graphAddNodeOpts
 }
 
 func (ma *MultiAgent) HostNodeKey() string {
 	return defaultHostNodeKey
 }
 
+// runPromptKey is keyed by the multi-agent, so that the run prompt of the host doesn't leak to the nested agents.
+type runPromptKey struct {
+	ma *MultiAgent
+}
+
+func (ma *MultiAgent) withRunPrompt(ctx context.Context, opts ...agent.AgentOption) context.Context {
+	if p := agent.GetRunPrompt(opts...); p != nil {
+		return context.WithValue(ctx, runPromptKey{ma: ma}, p)
+	}
+	return ctx
+}
+
+func (ma *MultiAgent) getRunPrompt(ctx context.Context) *agent.RunPrompt {
+	p, _ := ctx.Value(runPromptKey{ma: ma}).(*agent.RunPrompt)
+	return p
+}
+
 // MultiAgentConfig is the config for host multi-agent system.
 type MultiAgentConfig struct {
 	Host        Host
@@ -88,14 +158,24 @@ type MultiAgentConfig struct {
 	// Note: The handler MUST close the modelOutput stream before returning
 	// Optional. By default, it checks if the first chunk contains tool calls.
 	// Note: The default implementation does not work well with Claude, which typically outputs tool calls after text content.
+	// For such models, use NewTextTolerantStreamToolCallChecker, which keeps looking for the hand off tool call after the text content.
 	// Note: If your ChatModel doesn't output tool calls first, you can try adding prompts to constrain the model from generating extra text during the tool call.
 	StreamToolCallChecker func(ctx context.Context, modelOutput *schema.StreamReader[*schema.Message]) (bool, error)
 
 	// Summarizer is the summarizer agent that will summarize the outputs of all the chosen specialist agents.
-	// Only when the Host agent picks multiple Specialist will this be called.
+	// Only when the Host agent picks multiple Specialist in a single turn, i.e. outputs multiple tool calls, will this be called.
+	// The chosen specialists run concurrently, and their answers are passed to the summarizer in the order of Specialists.
 	// If you do not provide a summarizer, a default summarizer that simply concatenates all the output messages into one message will be used.
 	// Note: the default summarizer do not support streaming.
 	Summarizer *Summarizer
+
+	// MaxRounds enables the supervisor mode when greater than 0.
+	// In supervisor mode, the answers of the specialists are fed back to the Host as the results of its hand off tool calls,
+	// so that the Host can reason further, hand off to other specialists, or answer directly to end the run.
+	// The run also ends with the answer of the last hand off when the Host has handed off MaxRounds times.
+	// Optional. By default, the run ends with the answer of the first hand off.
+	// Note: in supervisor mode, the output of specialists is concatenated before being fed back, even in streaming mode.
+	MaxRounds int
 }
 
 func (conf *MultiAgentConfig) validate() error {
@@ -104,7 +184,7 @@ func (conf *MultiAgentConfig) validate() error {
 	}
 
 	if conf.Host.ChatModel == nil && conf.Host.ToolCallingModel == nil {
-		return errors.New("host multi agent host ChatModel is nil")
+		return errors.New("host multi agent host ToolCallingModel is nil")
 	}
 
 	if len(conf.Specialists) == 0 {
@@ -121,6 +201,19 @@ func (conf *MultiAgentConfig) validate() error {
 		}
 	}
 
+	if conf.MaxRounds < 0 {
+		return fmt.Errorf("host multi agent max rounds is negative: %d", conf.MaxRounds)
+	}
+
+	if conf.Summarizer != nil {
+		if conf.Summarizer.ChatModel == nil && conf.Summarizer.Aggregator == nil {
+			return errors.New("summarizer has no chat model or Aggregator")
+		}
+		if conf.Summarizer.ChatModel != nil && conf.Summarizer.Aggregator != nil {
+			return errors.New("summarizer chat model and Aggregator are mutually exclusive")
+		}
+	}
+
 	return nil
 }
 
@@ -143,17 +236,24 @@ func (am AgentMeta) validate() error {
 }
 
 // Host is the host agent within a multi-agent system.
-// Currently, it can only be a model.ChatModel.
+// Currently, it can only be a chat model.
 type Host struct {
+	// ToolCallingModel is the chat model of the host, the handoff tools are bound by WithTools,
+	// which returns a new instance and leaves ToolCallingModel itself untouched,
+	// so the same model can be shared safely by multiple agents.
+	// It takes precedence over ChatModel if both are set.
 	ToolCallingModel model.ToolCallingChatModel
 	// Deprecated: ChatModel is deprecated, please use ToolCallingModel instead.
 	// This field will be removed in a future release.
-	ChatModel    model.ChatModel
+	ChatModel model.ChatModel
+	// SystemPrompt is the system prompt of the host, a default prompt is used if it's empty.
+	// It's rendered as an FString template if agent.WithPromptVariables is passed to the run,
+	// and can be replaced per run by agent.WithSystemPrompt.
 	SystemPrompt string
 }
 
 // Specialist is a specialist agent within a host multi-agent system.
-// It can be a model.ChatModel or any Invokable and/or Streamable, such as react.Agent.
+// It can be a chat model, e.g. a model.ToolCallingChatModel, or any Invokable and/or Streamable, such as react.Agent.
 // ChatModel and (Invokable / Streamable) are mutually exclusive, only one should be provided.
 // notice: SystemPrompt only effects when ChatModel has been set.
 // If Invokable is provided but not Streamable, then the Specialist will be 'compose.InvokableLambda'.
@@ -162,6 +262,8 @@ type Host struct {
 type Specialist struct {
 	AgentMeta
 
+	// ChatModel is used as is, no tools are bound to it,
+	// so both model.ToolCallingChatModel and the deprecated model.ChatModel are accepted.
 	ChatModel    model.BaseChatModel
 	SystemPrompt string
 
@@ -169,9 +271,19 @@ type Specialist struct {
 	Streamable compose.Stream[[]*schema.Message, *schema.Message, agent.AgentOption]
 }
 
+// Summarizer is the summarizer agent within a host multi-agent system.
+// ChatModel and Aggregator are mutually exclusive, only one should be provided.
+// notice: SystemPrompt only effects when ChatModel has been set.
 type Summarizer struct {
+	// ChatModel is used as is, no tools are bound to it, e.g. a model.ToolCallingChatModel.
+	// It receives the system prompt, the input messages of the multi-agent, and then the answers of the specialists.
 	ChatModel    model.BaseChatModel
 	SystemPrompt string
+
+	// Aggregator merges the answers of the specialists into the final answer without calling a model,
+	// e.g. to pick the best answer or to format them as a list.
+	// The Name of each answer is set to the name of the specialist if it is empty.
+	Aggregator func(ctx context.Context, answers []*schema.Message) (*schema.Message, error)
 }
 
 func firstChunkStreamToolCallChecker(_ context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
@@ -197,3 +309,47 @@ func firstChunkStreamToolCallChecker(_ context.Context, sr *schema.StreamReader[
 		return false, nil
 	}
 }
+
+// defaultTextSniffWindow is the default number of runes of text content sniffed before the hand off tool call.
+const defaultTextSniffWindow = 1024
+
+// NewTextTolerantStreamToolCallChecker returns a StreamToolCallChecker tolerant of the models emitting text before the tool calls,
+// e.g. Claude, which usually explains what it's going to do before handing off.
+// It keeps receiving the stream until a tool call is found,
+// or the text content received exceeds textSniffWindow runes, which means the host is answering directly.
+// The reasoning content isn't counted into the window. textSniffWindow defaults to 1024 if it's not positive.
+// e.g.
+//
+//	config := &host.MultiAgentConfig{
+//		StreamToolCallChecker: host.NewTextTolerantStreamToolCallChecker(512),
+//		// ...
+//	}
+func NewTextTolerantStreamToolCallChecker(textSniffWindow int) func(ctx context.Context, modelOutput *schema.StreamReader[*schema.Message]) (bool, error) {
+	if textSniffWindow <= 0 {
+		textSniffWindow = defaultTextSniffWindow
+	}
+
+	return func(_ context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
+		defer sr.Close()
+
+		sniffed := 0
+		for {
+			msg, err := sr.Recv()
+			if err == io.EOF {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+
+			if len(msg.ToolCalls) > 0 {
+				return true, nil
+			}
+
+			sniffed += utf8.RuneCountInString(msg.Content)
+			if sniffed > textSniffWindow {
+				return false, nil
+			}
+		}
+	}
+}
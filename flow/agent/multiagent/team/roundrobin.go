@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RoundRobinConfig is the config for round-robin multi-agent system.
+type RoundRobinConfig struct {
+	// Members take turns in order, starting over from the first member after the last one.
+	Members []*Member
+
+	Name string // the name of the round-robin multi-agent
+
+	// MaxTurns is the max number of turns taken by all the members in total,
+	// the answer of the last turn is the output of the team.
+	// Optional. By default, every member takes exactly one turn.
+	MaxTurns int
+
+	// ShouldStop is called after every turn to decide whether the chat is over, in which case the answer of the turn is the output of the team.
+	// history is the input of the team followed by the answers of all the turns so far.
+	// Optional. By default, the chat goes on until MaxTurns is reached.
+	ShouldStop func(ctx context.Context, history []*schema.Message) (bool, error)
+
+	// InputBuilder builds the input of each turn.
+	// Optional. By default, a member receives the input of the team followed by the answers of all the previous turns.
+	InputBuilder InputBuilder
+}
+
+// NewRoundRobin creates a round-robin multi-agent system, a.k.a. group chat,
+// in which the members take turns to answer on the shared conversation until ShouldStop returns true or MaxTurns is reached,
+// e.g. a coder and a reviewer iterating until the reviewer approves.
+// Note: the answers of the members are concatenated before being passed on, even in streaming mode.
+func NewRoundRobin(ctx context.Context, config *RoundRobinConfig) (*MultiAgent, error) {
+	if config == nil {
+		return nil, errors.New("round robin multi agent config is nil")
+	}
+	if err := validateMembers(config.Members); err != nil {
+		return nil, err
+	}
+	if config.MaxTurns < 0 {
+		return nil, fmt.Errorf("round robin multi agent max turns is negative: %d", config.MaxTurns)
+	}
+
+	name := config.Name
+	if len(name) == 0 {
+		name = "round robin multi agent"
+	}
+
+	maxTurns := config.MaxTurns
+	if maxTurns == 0 {
+		maxTurns = len(config.Members)
+	}
+
+	builder := config.InputBuilder
+	if builder == nil {
+		builder = historyInputBuilder
+	}
+
+	g := compose.NewGraph[[]*schema.Message, *schema.Message](
+		compose.WithGenLocalState(func(context.Context) *state { return &state{} }))
+
+	for _, m := range config.Members {
+		if err := addMember(g, m, builder, true, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.AddEdge(compose.START, config.Members[0].Name); err != nil {
+		return nil, err
+	}
+
+	for i, m := range config.Members {
+		next := inputNodeKey(config.Members[(i+1)%len(config.Members)].Name)
+		if err := addNextTurnBranch(g, m.Name, next, maxTurns, config.ShouldStop); err != nil {
+			return nil, err
+		}
+	}
+
+	// every turn takes 2 steps: converter and member
+	compileOpts := []compose.GraphCompileOption{compose.WithNodeTriggerMode(compose.AnyPredecessor), compose.WithGraphName(name),
+		compose.WithMaxRunSteps(2*maxTurns + 10)}
+	r, err := g.Compile(ctx, compileOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiAgent{
+		runnable:         r,
+		graph:            g,
+		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
+	}, nil
+}
+
+func addNextTurnBranch(g *compose.Graph[[]*schema.Message, *schema.Message], member, next string, maxTurns int,
+	shouldStop func(ctx context.Context, history []*schema.Message) (bool, error)) error {
+
+	b := compose.NewStreamGraphBranch(func(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (string, error) {
+		sr.Close()
+
+		var (
+			history  []*schema.Message
+			finished bool
+		)
+		err := compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			if len(state.answers) >= maxTurns {
+				finished = true
+				return nil
+			}
+			history = make([]*schema.Message, 0, len(state.input)+len(state.answers))
+			history = append(history, state.input...)
+			history = append(history, state.answers...)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if finished {
+			return compose.END, nil
+		}
+		if shouldStop != nil {
+			stop, err := shouldStop(ctx, history)
+			if err != nil {
+				return "", err
+			}
+			if stop {
+				return compose.END, nil
+			}
+		}
+		return next, nil
+	}, map[string]bool{compose.END: true, next: true})
+
+	return g.AddBranch(member, b)
+}
+
+func historyInputBuilder(_ context.Context, input []*schema.Message, answers []*schema.Message) ([]*schema.Message, error) {
+	msgs := make([]*schema.Message, 0, len(input)+len(answers))
+	msgs = append(msgs, input...)
+	return append(msgs, answers...), nil
+}
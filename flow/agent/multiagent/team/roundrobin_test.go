@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package team
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("one turn each by default", func(t *testing.T) {
+		ma, err := NewRoundRobin(ctx, &RoundRobinConfig{
+			Members: []*Member{newEchoMember("a"), newEchoMember("b")},
+		})
+		assert.NoError(t, err)
+
+		out, err := ma.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "b(:hi,a:a(:hi))", out.Content)
+	})
+
+	t.Run("stop by condition", func(t *testing.T) {
+		var turns int
+		ma, err := NewRoundRobin(ctx, &RoundRobinConfig{
+			Members:  []*Member{newEchoMember("coder"), newEchoMember("reviewer")},
+			MaxTurns: 10,
+			ShouldStop: func(ctx context.Context, history []*schema.Message) (bool, error) {
+				turns = len(history) - 1
+				last := history[len(history)-1]
+				return last.Name == "coder" && strings.Count(last.Content, "reviewer:") >= 1, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		sr, err := ma.Stream(ctx, input)
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, turns)
+		assert.True(t, strings.HasPrefix(out.Content, "coder(:hi,coder:"))
+	})
+
+	t.Run("max turns", func(t *testing.T) {
+		var calls int
+		ma, err := NewRoundRobin(ctx, &RoundRobinConfig{
+			Members:  []*Member{newEchoMember("a"), newEchoMember("b")},
+			MaxTurns: 5,
+			InputBuilder: func(ctx context.Context, input []*schema.Message, answers []*schema.Message) ([]*schema.Message, error) {
+				calls++
+				return input, nil
+			},
+		})
+		assert.NoError(t, err)
+
+		out, err := ma.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, calls)
+		assert.Equal(t, "a(:hi)", out.Content)
+	})
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package team
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// SequentialConfig is the config for sequential multi-agent system.
+type SequentialConfig struct {
+	// Members run one after another in order, the answer of the last member is the output of the team.
+	Members []*Member
+
+	Name string // the name of the sequential multi-agent
+
+	// InputBuilder builds the input of each member.
+	// Optional. By default, a member receives the input of the team followed by the answer of the previous member.
+	InputBuilder InputBuilder
+}
+
+// NewSequential creates a sequential multi-agent system, in which each member receives the prior member's output,
+// e.g. a writer followed by a reviewer followed by a translator.
+// Note: the answers of all the members but the last are concatenated before being passed on, even in streaming mode.
+func NewSequential(ctx context.Context, config *SequentialConfig) (*MultiAgent, error) {
+	if config == nil {
+		return nil, errors.New("sequential multi agent config is nil")
+	}
+	if err := validateMembers(config.Members); err != nil {
+		return nil, err
+	}
+
+	name := config.Name
+	if len(name) == 0 {
+		name = "sequential multi agent"
+	}
+
+	builder := config.InputBuilder
+	if builder == nil {
+		builder = previousAnswerInputBuilder
+	}
+
+	g := compose.NewGraph[[]*schema.Message, *schema.Message](
+		compose.WithGenLocalState(func(context.Context) *state { return &state{} }))
+
+	last := compose.START
+	for i, m := range config.Members {
+		isFirst, isLast := i == 0, i == len(config.Members)-1
+		if err := addMember(g, m, builder, !isFirst, !isLast); err != nil {
+			return nil, err
+		}
+
+		if isFirst {
+			if err := g.AddEdge(compose.START, m.Name); err != nil {
+				return nil, err
+			}
+		} else if err := g.AddEdge(last, inputNodeKey(m.Name)); err != nil {
+			return nil, err
+		}
+		last = m.Name
+	}
+
+	if err := g.AddEdge(last, compose.END); err != nil {
+		return nil, err
+	}
+
+	compileOpts := []compose.GraphCompileOption{compose.WithNodeTriggerMode(compose.AnyPredecessor), compose.WithGraphName(name)}
+	r, err := g.Compile(ctx, compileOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiAgent{
+		runnable:         r,
+		graph:            g,
+		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
+	}, nil
+}
+
+func previousAnswerInputBuilder(_ context.Context, input []*schema.Message, answers []*schema.Message) ([]*schema.Message, error) {
+	if len(answers) == 0 {
+		return input, nil
+	}
+
+	msgs := make([]*schema.Message, 0, len(input)+1)
+	msgs = append(msgs, input...)
+	return append(msgs, answers[len(answers)-1]), nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package team
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// newEchoMember returns a member answering with its name and the contents of its input.
+func newEchoMember(name string) *Member {
+	return &Member{
+		Name: name,
+		Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+			contents := make([]string, 0, len(input))
+			for _, msg := range input {
+				contents = append(contents, msg.Name+":"+msg.Content)
+			}
+			return schema.AssistantMessage(name+"("+strings.Join(contents, ",")+")", nil), nil
+		},
+	}
+}
+
+func TestSequential(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockLLM := model.NewMockChatModel(ctrl)
+
+	ma, err := NewSequential(ctx, &SequentialConfig{
+		Members: []*Member{
+			newEchoMember("writer"),
+			newEchoMember("reviewer"),
+			{Name: "translator", ChatModel: mockLLM, SystemPrompt: "translate"},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockLLM.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...any) (*schema.StreamReader[*schema.Message], error) {
+			assert.Len(t, input, 3)
+			assert.Equal(t, "translate", input[0].Content)
+			assert.Equal(t, "reviewer", input[2].Name)
+			assert.Equal(t, "reviewer(:hi,writer:writer(:hi))", input[2].Content)
+			return schema.StreamReaderFromArray([]*schema.Message{
+				schema.AssistantMessage("trans", nil),
+				schema.AssistantMessage("lated", nil),
+			}), nil
+		}).Times(1)
+
+	sr, err := ma.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+	assert.NoError(t, err)
+	out, err := schema.ConcatMessageStream(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "translated", out.Content)
+
+	ma, err = NewSequential(ctx, &SequentialConfig{
+		Members: []*Member{newEchoMember("a"), newEchoMember("b")},
+		InputBuilder: func(ctx context.Context, input []*schema.Message, answers []*schema.Message) ([]*schema.Message, error) {
+			return answers, nil
+		},
+	})
+	assert.NoError(t, err)
+	msg, err := ma.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	assert.NoError(t, err)
+	assert.Equal(t, "b(a:a())", msg.Content)
+
+	_, err = NewSequential(ctx, &SequentialConfig{Members: []*Member{newEchoMember("a"), newEchoMember("a")}})
+	assert.ErrorContains(t, err, "duplicated")
+}
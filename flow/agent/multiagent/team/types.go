@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package team implements deterministic collaboration patterns for multi-agent system,
+// where the order in which agents take turns is fixed instead of decided by a host agent:
+// a sequential pipeline of agents, and round-robin turn-taking, a.k.a. group chat.
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Member is an agent within a team.
+// It can be a chat model or any Invokable and/or Streamable, such as react.Agent.
+// ChatModel and (Invokable / Streamable) are mutually exclusive, only one should be provided.
+// notice: SystemPrompt only effects when ChatModel has been set.
+type Member struct {
+	// Name is the name of the member, should be unique within the team.
+	// It is set as the Name of the member's answers if they have none, so that other members can tell who said what.
+	Name string
+
+	ChatModel    model.BaseChatModel
+	SystemPrompt string
+
+	Invokable  compose.Invoke[[]*schema.Message, *schema.Message, agent.AgentOption]
+	Streamable compose.Stream[[]*schema.Message, *schema.Message, agent.AgentOption]
+}
+
+// InputBuilder builds the input of a member from the input of the team and the answers of the members so far, in turn order.
+type InputBuilder func(ctx context.Context, input []*schema.Message, answers []*schema.Message) ([]*schema.Message, error)
+
+// MultiAgent is a team multi-agent system, created by NewSequential or NewRoundRobin.
+type MultiAgent struct {
+	runnable         compose.Runnable[[]*schema.Message, *schema.Message]
+	graph            *compose.Graph[[]*schema.Message, *schema.Message]
+	graphAddNodeOpts []compose.GraphAddNodeOpt
+}
+
+func (ma *MultiAgent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+	return ma.runnable.Invoke(ctx, input, agent.GetComposeOptions(opts...)...)
+}
+
+func (ma *MultiAgent) Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+	return ma.runnable.Stream(ctx, input, agent.GetComposeOptions(opts...)...)
+}
+
+// ExportGraph exports the underlying graph from MultiAgent, along with the []compose.GraphAddNodeOpt to be used when adding this graph to another graph.
+func (ma *MultiAgent) ExportGraph() (compose.AnyGraph, []compose.GraphAddNodeOpt) {
+	return ma.graph, ma.graphAddNodeOpts
+}
+
+type state struct {
+	started bool
+	input   []*schema.Message
+	answers []*schema.Message
+}
+
+func validateMembers(members []*Member) error {
+	if len(members) == 0 {
+		return errors.New("team members are empty")
+	}
+
+	names := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m == nil {
+			return errors.New("team member is nil")
+		}
+		if len(m.Name) == 0 {
+			return errors.New("team member name is empty")
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicated team member name: %s", m.Name)
+		}
+		names[m.Name] = true
+
+		if m.ChatModel == nil && m.Invokable == nil && m.Streamable == nil {
+			return fmt.Errorf("team member %s has no chat model or Invokable or Streamable", m.Name)
+		}
+		if m.ChatModel != nil && (m.Invokable != nil || m.Streamable != nil) {
+			return fmt.Errorf("team member %s chat model and Invokable / Streamable are mutually exclusive", m.Name)
+		}
+	}
+
+	return nil
+}
+
+// inputNodeKey is the key of the node converting the answer of the previous member to the input of the member.
+func inputNodeKey(name string) string {
+	return name + "_input"
+}
+
+// addMember adds the member node, and the converter node ahead of it if withInputNode.
+// The member's input is always rebuilt from state by builder, the converter only makes the types of the edges match.
+// Its answer is recorded in state if recordAnswer, which concatenates the answer in streaming mode.
+func addMember(g *compose.Graph[[]*schema.Message, *schema.Message], m *Member, builder InputBuilder,
+	withInputNode, recordAnswer bool) error {
+
+	preHandler := func(ctx context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
+		if !state.started {
+			state.started = true
+			state.input = input
+		}
+
+		msgs, err := builder(ctx, state.input, state.answers)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.ChatModel != nil && len(m.SystemPrompt) > 0 {
+			msgs = append([]*schema.Message{schema.SystemMessage(m.SystemPrompt)}, msgs...)
+		}
+		return msgs, nil
+	}
+
+	opts := []compose.GraphAddNodeOpt{compose.WithNodeName(m.Name), compose.WithStatePreHandler(preHandler)}
+	if recordAnswer {
+		opts = append(opts, compose.WithStatePostHandler(func(_ context.Context, out *schema.Message, state *state) (*schema.Message, error) {
+			answer := out
+			if answer != nil && answer.Name == "" {
+				named := *answer
+				named.Name = m.Name
+				answer = &named
+			}
+			state.answers = append(state.answers, answer)
+			return out, nil
+		}))
+	}
+
+	if m.ChatModel != nil {
+		if err := g.AddChatModelNode(m.Name, m.ChatModel, opts...); err != nil {
+			return err
+		}
+	} else {
+		lambda, err := compose.AnyLambda(m.Invokable, m.Streamable, nil, nil, compose.WithLambdaType("Member"))
+		if err != nil {
+			return err
+		}
+		if err = g.AddLambdaNode(m.Name, lambda, opts...); err != nil {
+			return err
+		}
+	}
+
+	if !withInputNode {
+		return nil
+	}
+
+	if err := g.AddLambdaNode(inputNodeKey(m.Name), compose.ToList[*schema.Message](), compose.WithNodeName("converter")); err != nil {
+		return err
+	}
+	return g.AddEdge(inputNodeKey(m.Name), m.Name)
+}
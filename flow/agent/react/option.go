@@ -34,6 +34,11 @@ func WithToolOptions(opts ...tool.Option) agent.AgentOption {
 	return agent.WithComposeOptions(compose.WithToolsNodeOption(compose.WithToolOption(opts...)))
 }
 
+// WithToolOptionsFor returns an agent option that specifies tool.Option only for the tool of the name in agent.
+func WithToolOptionsFor(toolName string, opts ...tool.Option) agent.AgentOption {
+	return agent.WithComposeOptions(compose.WithToolsNodeOption(compose.WithToolOptionFor(toolName, opts...)))
+}
+
 // WithChatModelOptions returns an agent option that specifies model.Option for the chat model in agent.
 func WithChatModelOptions(opts ...model.Option) agent.AgentOption {
 	return agent.WithComposeOptions(compose.WithChatModelOption(opts...))
@@ -18,17 +18,24 @@ package react
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/memory"
+	"github.com/cloudwego/eino/flow/session"
 	"github.com/cloudwego/eino/schema"
 )
 
 type state struct {
 	Messages                 []*schema.Message
 	ReturnDirectlyToolCallID string
+
+	// speculation is not persisted in checkpoints, the speculative executions don't survive interrupts.
+	speculation *speculation
 }
 
 func init() {
@@ -53,8 +60,16 @@ type AgentConfig struct {
 	Model model.ChatModel
 
 	// ToolsConfig is the config for tools node.
+	// When ToolsConfig.ToolRegistry is set, the ToolCallingModel is bound to the current tools of the registry on each turn.
 	ToolsConfig compose.ToolsNodeConfig
 
+	// SystemPrompt is prepended to the input messages as a system message before the model is called,
+	// it's not stored in state, so it takes no room in the message history.
+	// It's rendered as an FString template if agent.WithPromptVariables is passed to the run,
+	// and can be replaced per run by agent.WithSystemPrompt.
+	// Optional. If both SystemPrompt and MessageModifier are set, MessageModifier is called with the system message prepended.
+	SystemPrompt string
+
 	// MessageModifier.
 	// modify the input messages before the model is called, it's useful when you want to add some system prompt or other messages.
 	MessageModifier MessageModifier
@@ -66,6 +81,13 @@ type AgentConfig struct {
 	// NOTE: if both MessageModifier and MessageRewriter are set, MessageRewriter will be called before MessageModifier.
 	MessageRewriter MessageModifier
 
+	// HistorySummarizer replaces the older turns of the messages in the state with a summary generated by its model,
+	// when the messages exceed its token budget, before the ChatModel is called.
+	// The system prompt isn't stored in state, so it isn't counted in the budget.
+	// NOTE: it's called after MessageRewriter, and before MessageModifier.
+	// Optional.
+	HistorySummarizer *memory.Summarizer
+
 	// MaxStep.
 	// default 12 of steps in pregel (node num + 10).
 	MaxStep int `json:"max_step"`
@@ -98,6 +120,22 @@ type AgentConfig struct {
 	// ToolsNodeName is the node name of the tools node in the ReAct Agent graph.
 	// Optional. Default `Tools`.
 	ToolsNodeName string
+
+	// SpeculativeToolExecution starts executing a tool as soon as the arguments of its call are complete in the streamed model output,
+	// instead of after the model finishes, which cuts the latency when the tools are slow.
+	// The model is always called by streaming in this mode, in Generate as well.
+	// A speculative execution is canceled if the final model output revises or drops the call, so the tools must be safe
+	// to run speculatively, e.g. read only or idempotent.
+	// Only InvokableTool is executed speculatively, without the tool options, the tool call middlewares and the tool callbacks of the run,
+	// and the tools node uses the result in place of calling the tool.
+	// The tools whose arguments are rewritten by the tools node, i.e. by ToolArgumentsHandler, ArgumentsRepairer or HiddenParams,
+	// or which are bounded by ToolTimeout, ToolTimeouts or RateLimiter, are not executed speculatively.
+	// Optional. Default false.
+	SpeculativeToolExecution bool
+
+	// SessionManager manages the sessions of the conversations run by Agent.GenerateInSession.
+	// Optional. Required by GenerateInSession only.
+	SessionManager *session.Manager
 }
 
 // Deprecated: This approach of adding persona involves unnecessary slice copying overhead.
@@ -184,6 +222,7 @@ type Agent struct {
 	runnable         compose.Runnable[[]*schema.Message, *schema.Message]
 	graph            *compose.Graph[[]*schema.Message, *schema.Message]
 	graphAddNodeOpts []compose.GraphAddNodeOpt
+	sessions         *session.Manager
 }
 
 // NewAgent creates a ReAct agent that feeds tool response into next round of Chat Model generation.
@@ -192,6 +231,8 @@ type Agent struct {
 // the default StreamToolCallChecker may not work properly since it only checks the first chunk for tool calls.
 // In such cases, you need to implement a custom StreamToolCallChecker that can properly detect tool calls.
 func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
+	ag := &Agent{sessions: config.SessionManager}
+
 	var (
 		chatModel       model.BaseChatModel
 		toolsNode       *compose.ToolsNode
@@ -219,15 +260,34 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		toolCallChecker = firstChunkStreamToolCallChecker
 	}
 
-	if toolInfos, err = genToolInfos(ctx, config.ToolsConfig); err != nil {
-		return nil, err
+	if registry := config.ToolsConfig.ToolRegistry; registry != nil {
+		if config.ToolCallingModel == nil {
+			return nil, errors.New("tool registry requires ToolCallingModel")
+		}
+		if chatModel, err = registry.BindChatModel(config.ToolCallingModel); err != nil {
+			return nil, err
+		}
+	} else {
+		if toolInfos, err = genToolInfos(ctx, config.ToolsConfig); err != nil {
+			return nil, err
+		}
+
+		if chatModel, err = agent.ChatModelWithTools(config.Model, config.ToolCallingModel, toolInfos); err != nil {
+			return nil, err
+		}
 	}
 
-	if chatModel, err = agent.ChatModelWithTools(config.Model, config.ToolCallingModel, toolInfos); err != nil {
-		return nil, err
+	toolsConfig := config.ToolsConfig
+	if config.SpeculativeToolExecution {
+		resolve, err := speculativeToolResolver(ctx, config.ToolsConfig)
+		if err != nil {
+			return nil, err
+		}
+		chatModel = &speculativeChatModel{BaseChatModel: chatModel, resolve: resolve}
+		toolsConfig.ToolCallMiddlewares = append(append([]compose.ToolMiddleware{}, toolsConfig.ToolCallMiddlewares...), speculativeToolMiddleware())
 	}
 
-	if toolsNode, err = compose.NewToolNode(ctx, &config.ToolsConfig); err != nil {
+	if toolsNode, err = compose.NewToolNode(ctx, &toolsConfig); err != nil {
 		return nil, err
 	}
 
@@ -236,18 +296,41 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 	}))
 
 	modelPreHandle := func(ctx context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
+		c := ag.getStepsCollector(ctx)
+		c.addToolResults(input)
+		c.closeStep()
+
 		state.Messages = append(state.Messages, input...)
 
 		if config.MessageRewriter != nil {
 			state.Messages = config.MessageRewriter(ctx, state.Messages)
 		}
 
-		if messageModifier == nil {
+		if config.HistorySummarizer != nil {
+			msgs, err := config.HistorySummarizer.Summarize(ctx, state.Messages)
+			if err != nil {
+				return nil, err
+			}
+			state.Messages = msgs
+		}
+
+		systemPrompt, err := ag.getRunPrompt(ctx).Render(ctx, config.SystemPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("render system prompt fail: %w", err)
+		}
+
+		if messageModifier == nil && systemPrompt == "" {
 			return state.Messages, nil
 		}
 
-		modifiedInput := make([]*schema.Message, len(state.Messages))
-		copy(modifiedInput, state.Messages)
+		modifiedInput := make([]*schema.Message, 0, len(state.Messages)+1)
+		if systemPrompt != "" {
+			modifiedInput = append(modifiedInput, schema.SystemMessage(systemPrompt))
+		}
+		modifiedInput = append(modifiedInput, state.Messages...)
+		if messageModifier == nil {
+			return modifiedInput, nil
+		}
 		return messageModifier(ctx, modifiedInput), nil
 	}
 
@@ -261,8 +344,12 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 
 	toolsNodePreHandle := func(ctx context.Context, input *schema.Message, state *state) (*schema.Message, error) {
 		if input == nil {
-			return state.Messages[len(state.Messages)-1], nil // used for rerun interrupt resume
+			input = state.Messages[len(state.Messages)-1] // used for rerun interrupt resume
+			ag.getStepsCollector(ctx).addToolCall(input)
+			return input, nil
 		}
+		ag.getStepsCollector(ctx).addToolCall(input)
+		state.speculation.current().reconcile(input.ToolCalls)
 		state.Messages = append(state.Messages, input)
 		state.ReturnDirectlyToolCallID = getReturnDirectlyToolCallID(input, config.ToolReturnDirectly)
 		return input, nil
@@ -277,6 +364,9 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		} else if isToolCall {
 			return nodeKeyTools, nil
 		}
+		if config.SpeculativeToolExecution {
+			getSpeculation(ctx).current().reconcile(nil)
+		}
 		return compose.END, nil
 	}
 
@@ -284,7 +374,7 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		return nil, err
 	}
 
-	if err = buildReturnDirectly(graph); err != nil {
+	if err = buildReturnDirectly(graph, ag); err != nil {
 		return nil, err
 	}
 
@@ -294,16 +384,34 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		return nil, err
 	}
 
-	return &Agent{
-		runnable:         runnable,
-		graph:            graph,
-		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
-	}, nil
+	ag.runnable = runnable
+	ag.graph = graph
+	ag.graphAddNodeOpts = []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)}
+	return ag, nil
+}
+
+// runPromptKey is keyed by the agent, so that the run prompt of an agent doesn't leak to the nested agents.
+type runPromptKey struct {
+	agent *Agent
+}
+
+func (r *Agent) withRunPrompt(ctx context.Context, opts ...agent.AgentOption) context.Context {
+	if p := agent.GetRunPrompt(opts...); p != nil {
+		return context.WithValue(ctx, runPromptKey{agent: r}, p)
+	}
+	return ctx
+}
+
+func (r *Agent) getRunPrompt(ctx context.Context) *agent.RunPrompt {
+	p, _ := ctx.Value(runPromptKey{agent: r}).(*agent.RunPrompt)
+	return p
 }
 
-func buildReturnDirectly(graph *compose.Graph[[]*schema.Message, *schema.Message]) (err error) {
+func buildReturnDirectly(graph *compose.Graph[[]*schema.Message, *schema.Message], ag *Agent) (err error) {
 	directReturn := func(ctx context.Context, msgs *schema.StreamReader[[]*schema.Message]) (*schema.StreamReader[*schema.Message], error) {
+		c := ag.getStepsCollector(ctx)
 		return schema.StreamReaderWithConvert(msgs, func(msgs []*schema.Message) (*schema.Message, error) {
+			c.addToolResults(msgs)
 			var msg *schema.Message
 			err = compose.ProcessState[*state](ctx, func(_ context.Context, state *state) error {
 				for i := range msgs {
@@ -383,12 +491,12 @@ func getReturnDirectlyToolCallID(input *schema.Message, toolReturnDirectly map[s
 
 // Generate generates a response from the agent.
 func (r *Agent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
-	return r.runnable.Invoke(ctx, input, agent.GetComposeOptions(opts...)...)
+	return r.runnable.Invoke(r.withRunPrompt(ctx, opts...), input, agent.GetComposeOptions(opts...)...)
 }
 
 // Stream calls the agent and returns a stream response.
 func (r *Agent) Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (output *schema.StreamReader[*schema.Message], err error) {
-	return r.runnable.Stream(ctx, input, agent.GetComposeOptions(opts...)...)
+	return r.runnable.Stream(r.withRunPrompt(ctx, opts...), input, agent.GetComposeOptions(opts...)...)
 }
 
 // ExportGraph exports the underlying graph from Agent, along with the []compose.GraphAddNodeOpt to be used when adding this graph to another graph.
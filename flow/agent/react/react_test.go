@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +36,8 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/memory"
+	"github.com/cloudwego/eino/flow/session"
 	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
 	"github.com/cloudwego/eino/schema"
 	template "github.com/cloudwego/eino/utils/callbacks"
@@ -190,6 +196,250 @@ func TestReactWithMessageRewriterAndModifier(t *testing.T) {
 	assert.Equal(t, "final response", finalMsg.Content)
 }
 
+func TestReactWithHistorySummarizer(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	sm := mockModel.NewMockToolCallingChatModel(ctrl)
+
+	sm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Contains(t, input[1].Content, "message 1")
+			assert.NotContains(t, input[1].Content, "message 3")
+			return schema.AssistantMessage("earlier", nil), nil
+		}).Times(1)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			// Expected: [system prompt, summary, user: "message 3"]
+			assert.Len(t, input, 3)
+			assert.Equal(t, "system prompt", input[0].Content)
+			assert.True(t, memory.IsSummary(input[1]))
+			assert.True(t, strings.HasSuffix(input[1].Content, "earlier"))
+			assert.Equal(t, "message 3", input[2].Content)
+			return schema.AssistantMessage("final response", nil), nil
+		}).Times(1)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	summarizer, err := memory.NewSummarizer(sm, 20, memory.WithRecentBudget(8))
+	assert.NoError(t, err)
+	ra, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel:  cm,
+		SystemPrompt:      "system prompt",
+		HistorySummarizer: summarizer,
+	})
+	assert.NoError(t, err)
+
+	finalMsg, err := ra.Generate(ctx, []*schema.Message{
+		schema.UserMessage("message 1 " + strings.Repeat("long ", 10)),
+		schema.AssistantMessage("response 1", nil),
+		schema.UserMessage("message 3"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "final response", finalMsg.Content)
+}
+
+func TestReactWithSystemPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+
+	var systemPrompts []string
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Len(t, input, 2)
+			assert.Equal(t, schema.System, input[0].Role)
+			systemPrompts = append(systemPrompts, input[0].Content)
+			return schema.AssistantMessage("ok", nil), nil
+		}).Times(3)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	ra, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		SystemPrompt:     "You are the assistant of {user}.",
+	})
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("hello")}
+	_, err = ra.Generate(ctx, input)
+	assert.NoError(t, err)
+	_, err = ra.Generate(ctx, input, agent.WithPromptVariables(map[string]any{"user": "Alice"}))
+	assert.NoError(t, err)
+	_, err = ra.Generate(ctx, input, agent.WithSystemPrompt("You speak {lang}."), agent.WithPromptVariables(map[string]any{"lang": "French"}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"You are the assistant of {user}.",
+		"You are the assistant of Alice.",
+		"You speak French.",
+	}, systemPrompts)
+
+	_, err = ra.Generate(ctx, input, agent.WithPromptVariables(map[string]any{"other": "x"}))
+	assert.ErrorContains(t, err, "render system prompt fail")
+}
+
+func TestReactWithToolRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	registry, err := compose.NewToolRegistry(ctx)
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+
+	var bound []int
+	cm.EXPECT().WithTools(gomock.Any()).DoAndReturn(func(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+		bound = append(bound, len(tools))
+		return cm, nil
+	}).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			if len(bound) > 0 && input[len(input)-1].Role == schema.User {
+				return schema.AssistantMessage("", []schema.ToolCall{
+					{ID: randStr(), Function: schema.FunctionCall{Name: "greet", Arguments: `{"name": "max"}`}},
+				}), nil
+			}
+			return schema.AssistantMessage("bye", nil), nil
+		}).AnyTimes()
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{ToolRegistry: registry},
+		MaxStep:          40,
+	})
+	assert.NoError(t, err)
+
+	out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", out.Content)
+	assert.Empty(t, bound)
+
+	assert.NoError(t, registry.Register(ctx, &fakeToolGreetForTest{tarCount: 1}))
+
+	out, err = a.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", out.Content)
+	assert.Equal(t, []int{1}, bound)
+
+	_, err = NewAgent(ctx, &AgentConfig{
+		Model:       mockModel.NewMockChatModel(ctrl),
+		ToolsConfig: compose.ToolsNodeConfig{ToolRegistry: registry},
+	})
+	assert.Error(t, err)
+}
+
+func TestReactGenerateWithSteps(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	times := 0
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			times++
+			if times <= 2 {
+				return schema.AssistantMessage("", []schema.ToolCall{
+					{ID: fmt.Sprintf("call_%d", times), Function: schema.FunctionCall{Name: "greet", Arguments: fmt.Sprintf(`{"name": "user%d"}`, times)}},
+				}), nil
+			}
+			return schema.AssistantMessage("bye", nil), nil
+		}).AnyTimes()
+
+	newAgent := func(returnDirectly bool) *Agent {
+		conf := &AgentConfig{
+			ToolCallingModel: cm,
+			ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolGreetForTest{tarCount: 10}}},
+		}
+		if returnDirectly {
+			conf.ToolReturnDirectly = map[string]struct{}{"greet": {}}
+		}
+		a, err := NewAgent(ctx, conf)
+		assert.NoError(t, err)
+		return a
+	}
+
+	out, steps, err := newAgent(false).GenerateWithSteps(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", out.Content)
+	assert.Len(t, steps, 2)
+	for i, step := range steps {
+		callID := fmt.Sprintf("call_%d", i+1)
+		assert.Equal(t, callID, step.ToolCallMessage.ToolCalls[0].ID)
+		assert.Len(t, step.ToolResults, 1)
+		assert.Equal(t, callID, step.ToolResults[0].ToolCallID)
+		assert.Equal(t, fmt.Sprintf(`{"say": "hello user%d"}`, i+1), step.ToolResults[0].Content)
+	}
+
+	times = 0
+	out, steps, err = newAgent(true).GenerateWithSteps(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"say": "hello user1"}`, out.Content)
+	assert.Len(t, steps, 1)
+	assert.Len(t, steps[0].ToolResults, 1)
+	assert.Equal(t, "call_1", steps[0].ToolResults[0].ToolCallID)
+}
+
+func TestReactGenerateInSession(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	var inputs [][]*schema.Message
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			inputs = append(inputs, input)
+			if input[len(input)-1].Role == schema.User && input[len(input)-1].Content == "hello" {
+				return schema.AssistantMessage("", []schema.ToolCall{
+					{ID: "call_1", Function: schema.FunctionCall{Name: "greet", Arguments: `{"name": "user"}`}},
+				}), nil
+			}
+			return schema.AssistantMessage("bye", nil), nil
+		}).AnyTimes()
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolGreetForTest{tarCount: 10}}},
+	})
+	assert.NoError(t, err)
+	_, err = a.GenerateInSession(ctx, "s1", schema.UserMessage("hello"))
+	assert.Error(t, err)
+
+	sessions, err := session.NewManager(session.NewMemoryStore())
+	assert.NoError(t, err)
+	a, err = NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolGreetForTest{tarCount: 10}}},
+		SessionManager:   sessions,
+	})
+	assert.NoError(t, err)
+
+	out, err := a.GenerateInSession(ctx, "s1", schema.UserMessage("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", out.Content)
+
+	s, ok, err := sessions.Get(ctx, "s1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// user message, tool call, tool result, response
+	assert.Len(t, s.Messages, 4)
+	assert.Equal(t, "call_1", s.Messages[2].ToolCallID)
+	assert.Equal(t, "bye", s.Messages[3].Content)
+
+	out, err = a.GenerateInSession(ctx, "s1", schema.UserMessage("again"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", out.Content)
+	// the history is loaded as the input of the next turn
+	assert.Len(t, inputs[len(inputs)-1], 5)
+	s, _, err = sessions.Get(ctx, "s1")
+	assert.NoError(t, err)
+	assert.Len(t, s.Messages, 6)
+}
+
 func TestReactStream(t *testing.T) {
 	ctx := context.Background()
 
@@ -724,6 +974,25 @@ func TestWithTools(t *testing.T) {
 }
 
 // Helper tool for testing error cases
+type noToolCallingModel struct {
+	model.ToolCallingChatModel
+}
+
+func (n *noToolCallingModel) Capabilities() *model.Capabilities {
+	return &model.Capabilities{Vision: true}
+}
+
+func TestModelWithoutToolCalling(t *testing.T) {
+	ctx := context.Background()
+	cm := &noToolCallingModel{mockModel.NewMockToolCallingChatModel(gomock.NewController(t))}
+
+	_, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolGreetForTest{}}},
+	})
+	assert.ErrorContains(t, err, "doesn't support tool calling")
+}
+
 type errorToolForTest struct{}
 
 func (t *errorToolForTest) Info(_ context.Context) (*schema.ToolInfo, error) {
@@ -820,3 +1089,128 @@ func randStr() string {
 }
 
 var callbackForTest = BuildAgentCallback(&template.ModelCallbackHandler{}, &template.ToolCallbackHandler{})
+
+type slowToolForTest struct {
+	mu       sync.Mutex
+	runs     []string
+	canceled int
+	delay    time.Duration
+}
+
+func (t *slowToolForTest) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "slow", Desc: "slow search"}, nil
+}
+
+func (t *slowToolForTest) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	t.mu.Lock()
+	t.runs = append(t.runs, argumentsInJSON)
+	t.mu.Unlock()
+
+	select {
+	case <-time.After(t.delay):
+		return "result of " + argumentsInJSON, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		t.canceled++
+		t.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+func TestReactSpeculativeToolExecution(t *testing.T) {
+	ctx := context.Background()
+	idx := func(i int) *int { return &i }
+
+	newModel := func(t *testing.T) model.ToolCallingChatModel {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				if input[len(input)-1].Role == schema.Tool {
+					return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("done: "+input[len(input)-1].Content, nil)}), nil
+				}
+				sr, sw := schema.Pipe[*schema.Message](0)
+				go func() {
+					defer sw.Close()
+					sw.Send(schema.AssistantMessage("", []schema.ToolCall{{Index: idx(0), ID: "call_1", Function: schema.FunctionCall{Name: "slow", Arguments: `{"q":`}}}), nil)
+					sw.Send(schema.AssistantMessage("", []schema.ToolCall{{Index: idx(0), Function: schema.FunctionCall{Arguments: `"a"}`}}}), nil)
+					// the model keeps generating while the tool runs
+					time.Sleep(100 * time.Millisecond)
+					sw.Send(schema.AssistantMessage("", []schema.ToolCall{{Index: idx(1), ID: "call_2", Function: schema.FunctionCall{Name: "slow", Arguments: `{"q":"b"}`}}}), nil)
+				}()
+				return sr, nil
+			}).AnyTimes()
+		return cm
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		st := &slowToolForTest{delay: 100 * time.Millisecond}
+		a, err := NewAgent(ctx, &AgentConfig{
+			ToolCallingModel:         newModel(t),
+			ToolsConfig:              compose.ToolsNodeConfig{Tools: []tool.BaseTool{st}},
+			SpeculativeToolExecution: true,
+		})
+		assert.NoError(t, err)
+
+		start := time.Now()
+		out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("search")})
+		assert.NoError(t, err)
+		// the first tool call overlaps with the model generation
+		assert.Less(t, time.Since(start), 280*time.Millisecond)
+		assert.True(t, strings.HasPrefix(out.Content, "done: result of "))
+		// every tool call is run exactly once
+		sort.Strings(st.runs)
+		assert.Equal(t, []string{`{"q":"a"}`, `{"q":"b"}`}, st.runs)
+		assert.Equal(t, 0, st.canceled)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		st := &slowToolForTest{delay: time.Minute}
+		a, err := NewAgent(ctx, &AgentConfig{
+			ToolCallingModel: newModel(t),
+			ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{st}},
+			StreamToolCallChecker: func(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
+				// decide to end without calling the tools after the whole output
+				_, err := schema.ConcatMessageStream(sr)
+				return false, err
+			},
+			SpeculativeToolExecution: true,
+		})
+		assert.NoError(t, err)
+
+		sr, err := a.Stream(ctx, []*schema.Message{schema.UserMessage("search")})
+		assert.NoError(t, err)
+		_, err = schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			st.mu.Lock()
+			defer st.mu.Unlock()
+			return st.canceled == 2
+		}, time.Second, 10*time.Millisecond)
+		// both speculative executions are started, then canceled once the agent ends
+		assert.Len(t, st.runs, 2)
+	})
+
+	t.Run("hidden params", func(t *testing.T) {
+		st := &slowToolForTest{delay: 10 * time.Millisecond}
+		a, err := NewAgent(ctx, &AgentConfig{
+			ToolCallingModel: newModel(t),
+			ToolsConfig: compose.ToolsNodeConfig{
+				Tools: []tool.BaseTool{st},
+				HiddenParams: map[string]map[string]compose.HiddenParamResolver{
+					"slow": {"user": func(context.Context) (any, error) { return "u1", nil }},
+				},
+			},
+			SpeculativeToolExecution: true,
+		})
+		assert.NoError(t, err)
+
+		_, err = a.Generate(ctx, []*schema.Message{schema.UserMessage("search")})
+		assert.NoError(t, err)
+		// not executed speculatively, every tool call is run exactly once with the hidden params
+		sort.Strings(st.runs)
+		assert.Equal(t, []string{`{"q":"a","user":"u1"}`, `{"q":"b","user":"u1"}`}, st.runs)
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/session"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GenerateInSession generates a response to the new message in the session managed by AgentConfig.SessionManager.
+// The history of the session is loaded and followed by the message as the input of the agent,
+// and on success, the message, the tool calls and results of the run, and the response are appended to the transcript and saved,
+// all while holding the lock of the session, so the concurrent calls in the same session are run one by one.
+// The session is left intact if the run fails.
+func (r *Agent) GenerateInSession(ctx context.Context, sessionID string, message *schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+	if r.sessions == nil {
+		return nil, errors.New("session manager is not configured")
+	}
+
+	var out *schema.Message
+	err := r.sessions.Run(ctx, sessionID, func(ctx context.Context, s *session.Session) error {
+		input := make([]*schema.Message, 0, len(s.Messages)+1)
+		input = append(input, s.Messages...)
+		input = append(input, message)
+
+		resp, steps, err := r.GenerateWithSteps(ctx, input, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			input = append(input, step.ToolCallMessage)
+			input = append(input, step.ToolResults...)
+		}
+		s.Messages = append(input, resp)
+		out = resp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
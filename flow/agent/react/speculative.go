@@ -0,0 +1,298 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// speculation holds the speculative tool executions of a run, in the state of the run.
+type speculation struct {
+	mu    sync.Mutex
+	round *speculationRound
+}
+
+// speculationRound is the speculative executions started for the output of a model call.
+// Once finalized, i.e. the tool calls of the output are decided, no more executions are started for it.
+type speculationRound struct {
+	mu        sync.Mutex
+	finalized bool
+	execs     map[string]*speculativeExec
+}
+
+type speculativeExec struct {
+	name, args string
+	cancel     context.CancelFunc
+	done       chan struct{}
+	result     string
+	err        error
+}
+
+func speculationKey(callID, name, args string) string {
+	if len(callID) > 0 {
+		return callID
+	}
+	return name + "\x00" + args
+}
+
+func getSpeculation(ctx context.Context) *speculation {
+	var s *speculation
+	_ = compose.ProcessState[*state](ctx, func(_ context.Context, st *state) error {
+		if st.speculation == nil {
+			st.speculation = &speculation{}
+		}
+		s = st.speculation
+		return nil
+	})
+	return s
+}
+
+// newRound starts a round for a model call, the executions left by the previous round are canceled.
+func (s *speculation) newRound() *speculationRound {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.round != nil {
+		s.round.reconcile(nil)
+	}
+	s.round = &speculationRound{execs: make(map[string]*speculativeExec)}
+	return s.round
+}
+
+func (s *speculation) current() *speculationRound {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.round
+}
+
+func (r *speculationRound) start(ctx context.Context, it tool.InvokableTool, tc schema.ToolCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := speculationKey(tc.ID, tc.Function.Name, tc.Function.Arguments)
+	if _, ok := r.execs[key]; ok || r.finalized {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	exec := &speculativeExec{
+		name:   tc.Function.Name,
+		args:   tc.Function.Arguments,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	r.execs[key] = exec
+	go func() {
+		defer close(exec.done)
+		exec.result, exec.err = it.InvokableRun(ctx, exec.args)
+	}()
+}
+
+// reconcile finalizes the round with the tool calls of the model output,
+// canceling the executions which don't match any of the tool calls, i.e. revised or dropped by the final output.
+func (r *speculationRound) reconcile(toolCalls []schema.ToolCall) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finalized = true
+
+	keep := make(map[string]bool, len(toolCalls))
+	for _, tc := range toolCalls {
+		key := speculationKey(tc.ID, tc.Function.Name, tc.Function.Arguments)
+		if exec, ok := r.execs[key]; ok && exec.name == tc.Function.Name && exec.args == tc.Function.Arguments {
+			keep[key] = true
+		}
+	}
+	for key, exec := range r.execs {
+		if !keep[key] {
+			exec.cancel()
+			delete(r.execs, key)
+		}
+	}
+}
+
+// take removes and returns the execution matching the tool call.
+func (r *speculationRound) take(input *compose.ToolInput) *speculativeExec {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := speculationKey(input.CallID, input.Name, input.Arguments)
+	exec, ok := r.execs[key]
+	if !ok || exec.name != input.Name || exec.args != input.Arguments {
+		return nil
+	}
+	delete(r.execs, key)
+	return exec
+}
+
+func (e *speculativeExec) wait(ctx context.Context) (string, error) {
+	select {
+	case <-e.done:
+		e.cancel()
+		return e.result, e.err
+	case <-ctx.Done():
+		e.cancel()
+		return "", ctx.Err()
+	}
+}
+
+// speculativeChatModel starts the tools as soon as the arguments of their calls are complete in the streamed output.
+// Generate is done by streaming as well, to start the tools before the model finishes.
+type speculativeChatModel struct {
+	model.BaseChatModel
+	resolve func(ctx context.Context, name string) tool.InvokableTool
+}
+
+func (m *speculativeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	sr, err := m.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.ConcatMessageStream(sr)
+}
+
+func (m *speculativeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	sr, err := m.BaseChatModel.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := getSpeculation(ctx)
+	if s == nil {
+		return sr, nil
+	}
+
+	round := s.newRound()
+	acc := schema.NewToolCallAccumulator(func(tc schema.ToolCall) {
+		if it := m.resolve(ctx, tc.Function.Name); it != nil {
+			round.start(ctx, it, tc)
+		}
+	})
+	return schema.StreamReaderWithConvert(sr, func(chunk *schema.Message) (*schema.Message, error) {
+		// conflicting chunks fail the concatenation of the output, no need to report here
+		_ = acc.AddMessage(chunk)
+		return chunk, nil
+	}), nil
+}
+
+func (m *speculativeChatModel) GetType() string {
+	typ, _ := components.GetType(m.BaseChatModel)
+	return typ
+}
+
+func (m *speculativeChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.BaseChatModel)
+}
+
+// speculativeToolMiddleware returns the result of the speculative execution matching the tool call, if any.
+// It's the innermost middleware, so that the tool call is matched with the arguments passed to the tool.
+func speculativeToolMiddleware() compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				exec := getSpeculation(ctx).current().take(input)
+				if exec == nil {
+					return next(ctx, input)
+				}
+				result, err := exec.wait(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return &compose.ToolOutput{Result: result}, nil
+			}
+		},
+		Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				exec := getSpeculation(ctx).current().take(input)
+				if exec == nil {
+					return next(ctx, input)
+				}
+				result, err := exec.wait(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{result})}, nil
+			}
+		},
+	}
+}
+
+// speculatable reports whether the calls of the tool can be executed speculatively.
+// A speculative execution calls the tool with the arguments generated by the model, outside the tools node,
+// so it's off for the tools whose arguments are rewritten by the tools node, i.e. by ToolArgumentsHandler, ArgumentsRepairer or HiddenParams,
+// which would not match the speculative one and run the tool twice, and for the tools bounded by the tools node, i.e. by timeout or RateLimiter.
+func speculatable(config compose.ToolsNodeConfig, name string) bool {
+	if config.ToolArgumentsHandler != nil || config.ArgumentsRepairer != nil || config.RateLimiter != nil {
+		return false
+	}
+	if len(config.HiddenParams[name]) > 0 {
+		return false
+	}
+	timeout, ok := config.ToolTimeouts[name]
+	if !ok {
+		timeout = config.ToolTimeout
+	}
+	return timeout <= 0
+}
+
+// speculativeToolResolver finds the invokable tool by name among the tools of the config, nil if it's not speculatable.
+func speculativeToolResolver(ctx context.Context, config compose.ToolsNodeConfig) (func(ctx context.Context, name string) tool.InvokableTool, error) {
+	if registry := config.ToolRegistry; registry != nil {
+		return func(ctx context.Context, name string) tool.InvokableTool {
+			if !speculatable(config, name) {
+				return nil
+			}
+			for _, t := range registry.Tools() {
+				if info, err := t.Info(ctx); err == nil && info.Name == name {
+					it, _ := t.(tool.InvokableTool)
+					return it
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	tools := make(map[string]tool.InvokableTool, len(config.Tools))
+	for _, t := range config.Tools {
+		it, ok := t.(tool.InvokableTool)
+		if !ok {
+			continue
+		}
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tools[info.Name] = it
+	}
+	return func(_ context.Context, name string) tool.InvokableTool {
+		if !speculatable(config, name) {
+			return nil
+		}
+		return tools[name]
+	}, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// AgentStep is an intermediate step of a ReAct run, i.e. the tools called by the model and their results.
+type AgentStep struct {
+	// ToolCallMessage is the assistant message calling the tools.
+	ToolCallMessage *schema.Message
+	// ToolResults are the tool messages carrying the results of the tool calls.
+	ToolResults []*schema.Message
+}
+
+// GenerateWithSteps generates a response from the agent like Generate, and returns the intermediate steps of the run in order as well.
+// The steps taken before an error are returned along with the error.
+func (r *Agent) GenerateWithSteps(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, []AgentStep, error) {
+	c := &stepsCollector{}
+	ctx = context.WithValue(r.withRunPrompt(ctx, opts...), stepsKey{agent: r}, c)
+
+	out, err := r.runnable.Invoke(ctx, input, agent.GetComposeOptions(opts...)...)
+
+	return out, c.get(), err
+}
+
+// stepsKey is keyed by the agent, so that the steps of the nested agents aren't mixed up.
+type stepsKey struct {
+	agent *Agent
+}
+
+type stepsCollector struct {
+	mu    sync.Mutex
+	steps []AgentStep
+	// pending reports whether the tool results of the last step are still expected.
+	pending bool
+}
+
+func (r *Agent) getStepsCollector(ctx context.Context) *stepsCollector {
+	c, _ := ctx.Value(stepsKey{agent: r}).(*stepsCollector)
+	return c
+}
+
+func (c *stepsCollector) addToolCall(msg *schema.Message) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.steps = append(c.steps, AgentStep{ToolCallMessage: msg})
+	c.pending = true
+}
+
+func (c *stepsCollector) addToolResults(msgs []*schema.Message) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.pending {
+		return
+	}
+	last := &c.steps[len(c.steps)-1]
+	last.ToolResults = append(last.ToolResults, msgs...)
+}
+
+func (c *stepsCollector) closeStep() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = false
+}
+
+func (c *stepsCollector) get() []AgentStep {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.steps
+}
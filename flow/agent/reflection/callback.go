@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reflection
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Callback is the callback interface for reflection agent.
+type Callback interface {
+	// OnDraft is called after each draft has been reviewed.
+	OnDraft(ctx context.Context, info *DraftInfo)
+}
+
+// DraftInfo is the info which will be passed to Callback.OnDraft, representing a reviewed draft.
+type DraftInfo struct {
+	Round    int // starts from 1
+	Draft    *schema.Message
+	Critique *schema.Message
+	Approved bool
+}
+
+type options struct {
+	callbacks []Callback
+}
+
+// WithCallbacks registers callbacks receiving the intermediate drafts.
+func WithCallbacks(callbacks ...Callback) agent.AgentOption {
+	return agent.WrapImplSpecificOptFn(func(opts *options) {
+		opts.callbacks = append(opts.callbacks, callbacks...)
+	})
+}
+
+type callbacksKey struct{}
+
+func withCallbacks(ctx context.Context, opts ...agent.AgentOption) context.Context {
+	o := agent.GetImplSpecificOptions(&options{}, opts...)
+	if len(o.callbacks) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, callbacksKey{}, o.callbacks)
+}
+
+func onDraft(ctx context.Context, info *DraftInfo) {
+	cbs, _ := ctx.Value(callbacksKey{}).([]Callback)
+	for _, cb := range cbs {
+		cb.OnDraft(ctx, info)
+	}
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package reflection implements the reflection (self-critique) pattern:
+// a generator produces a draft, a critic reviews it against a rubric, and the generator revises the draft
+// according to the critique, until the critic approves or the max rounds is reached.
+package reflection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	generatorNodeKey      = "generator"
+	draftToListNodeKey    = "draft_to_list"
+	criticNodeKey         = "critic"
+	critiqueToListNodeKey = "critique_to_list"
+	finalDraftNodeKey     = "final_draft"
+	defaultMaxRounds      = 3
+	defaultCriticPrompt   = "You are a strict reviewer. Review the last answer of the assistant against the criteria below.\n"
+)
+
+// ApprovedMarker is what the critic is asked to reply with when the draft meets the rubric, checked by the default IsApproved.
+const ApprovedMarker = "APPROVED"
+
+// Config is the config of the reflection agent.
+// Generator ChatModel and (Invokable / Streamable) are mutually exclusive, only one should be provided.
+type Config struct {
+	// GeneratorModel produces the drafts.
+	GeneratorModel model.BaseChatModel
+	// Invokable / Streamable produce the drafts, e.g. the Generate / Stream of react.Agent.
+	Invokable  compose.Invoke[[]*schema.Message, *schema.Message, agent.AgentOption]
+	Streamable compose.Stream[[]*schema.Message, *schema.Message, agent.AgentOption]
+
+	// Critic reviews the drafts, required.
+	Critic model.BaseChatModel
+	// Rubric is the criteria the critic reviews the drafts against, required.
+	Rubric string
+
+	// MaxRounds is the max number of drafts, the last draft is returned if the critic never approves.
+	// Optional. Default is 3.
+	MaxRounds int
+	// IsApproved decides whether the critique approves the draft.
+	// Optional. By default, the critique is an approval if it starts with ApprovedMarker.
+	IsApproved func(ctx context.Context, critique *schema.Message) (bool, error)
+
+	Name string // the name of the reflection agent
+}
+
+// Agent is the reflection agent.
+type Agent struct {
+	runnable         compose.Runnable[[]*schema.Message, *schema.Message]
+	graph            *compose.Graph[[]*schema.Message, *schema.Message]
+	graphAddNodeOpts []compose.GraphAddNodeOpt
+}
+
+type state struct {
+	input    []*schema.Message
+	rounds   int
+	draft    *schema.Message
+	critique *schema.Message
+}
+
+// NewAgent creates a reflection agent.
+// Note: the drafts are concatenated before being reviewed, so the final draft is returned as a single chunk in streaming mode.
+// e.g.
+//
+//	agent, err := reflection.NewAgent(ctx, &reflection.Config{
+//		GeneratorModel: writerModel,
+//		Critic:         reviewerModel,
+//		Rubric:         "1. the answer cites its sources. 2. the answer is under 200 words.",
+//	})
+//	out, err := agent.Generate(ctx, []*schema.Message{schema.UserMessage("write a summary of ...")},
+//		reflection.WithCallbacks(draftLogger))
+func NewAgent(ctx context.Context, config *Config) (*Agent, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	maxRounds := config.MaxRounds
+	if maxRounds == 0 {
+		maxRounds = defaultMaxRounds
+	}
+	isApproved := config.IsApproved
+	if isApproved == nil {
+		isApproved = startsWithApprovedMarker
+	}
+	name := config.Name
+	if len(name) == 0 {
+		name = "reflection agent"
+	}
+
+	g := compose.NewGraph[[]*schema.Message, *schema.Message](
+		compose.WithGenLocalState(func(context.Context) *state { return &state{} }))
+
+	if err := addGenerator(config, g); err != nil {
+		return nil, err
+	}
+
+	criticPrompt := defaultCriticPrompt + config.Rubric +
+		"\nIf the answer meets all the criteria, reply with \"" + ApprovedMarker + "\" only. " +
+		"Otherwise, point out every problem and how to fix it."
+	if err := g.AddChatModelNode(criticNodeKey, config.Critic, compose.WithNodeName(criticNodeKey),
+		compose.WithStatePreHandler(func(_ context.Context, _ []*schema.Message, state *state) ([]*schema.Message, error) {
+			msgs := make([]*schema.Message, 0, len(state.input)+3)
+			msgs = append(msgs, schema.SystemMessage(criticPrompt))
+			msgs = append(msgs, withoutSystemMessages(state.input)...)
+			msgs = append(msgs, state.draft, schema.UserMessage("Review the last answer against the criteria."))
+			return msgs, nil
+		}),
+		compose.WithStatePostHandler(func(_ context.Context, out *schema.Message, state *state) (*schema.Message, error) {
+			state.critique = out
+			return out, nil
+		})); err != nil {
+		return nil, err
+	}
+
+	if err := g.AddLambdaNode(draftToListNodeKey, compose.ToList[*schema.Message](), compose.WithNodeName("converter")); err != nil {
+		return nil, err
+	}
+	if err := g.AddLambdaNode(critiqueToListNodeKey, compose.ToList[*schema.Message](), compose.WithNodeName("converter")); err != nil {
+		return nil, err
+	}
+	if err := g.AddLambdaNode(finalDraftNodeKey, compose.InvokableLambda(func(ctx context.Context, _ *schema.Message) (draft *schema.Message, err error) {
+		err = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			draft = state.draft
+			return nil
+		})
+		return draft, err
+	}), compose.WithNodeName(finalDraftNodeKey)); err != nil {
+		return nil, err
+	}
+
+	if err := g.AddEdge(compose.START, generatorNodeKey); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(generatorNodeKey, draftToListNodeKey); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(draftToListNodeKey, criticNodeKey); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(critiqueToListNodeKey, generatorNodeKey); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(finalDraftNodeKey, compose.END); err != nil {
+		return nil, err
+	}
+
+	b := compose.NewGraphBranch(func(ctx context.Context, critique *schema.Message) (string, error) {
+		approved, err := isApproved(ctx, critique)
+		if err != nil {
+			return "", err
+		}
+
+		var info *DraftInfo
+		_ = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			info = &DraftInfo{Round: state.rounds, Draft: state.draft, Critique: critique, Approved: approved}
+			return nil
+		})
+		onDraft(ctx, info)
+
+		if approved || info.Round >= maxRounds {
+			return finalDraftNodeKey, nil
+		}
+		return critiqueToListNodeKey, nil
+	}, map[string]bool{finalDraftNodeKey: true, critiqueToListNodeKey: true})
+	if err := g.AddBranch(criticNodeKey, b); err != nil {
+		return nil, err
+	}
+
+	// every round takes 4 steps: generator, converter, critic and converter
+	compileOpts := []compose.GraphCompileOption{compose.WithNodeTriggerMode(compose.AnyPredecessor), compose.WithGraphName(name),
+		compose.WithMaxRunSteps(4*maxRounds + 10)}
+	r, err := g.Compile(ctx, compileOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		runnable:         r,
+		graph:            g,
+		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
+	}, nil
+}
+
+func (c *Config) validate() error {
+	if c == nil {
+		return errors.New("reflection agent config is nil")
+	}
+	if c.GeneratorModel == nil && c.Invokable == nil && c.Streamable == nil {
+		return errors.New("reflection agent has no generator model or Invokable or Streamable")
+	}
+	if c.GeneratorModel != nil && (c.Invokable != nil || c.Streamable != nil) {
+		return errors.New("reflection agent generator model and Invokable / Streamable are mutually exclusive")
+	}
+	if c.Critic == nil {
+		return errors.New("reflection agent critic is nil")
+	}
+	if len(c.Rubric) == 0 {
+		return errors.New("reflection agent rubric is empty")
+	}
+	if c.MaxRounds < 0 {
+		return fmt.Errorf("reflection agent max rounds is negative: %d", c.MaxRounds)
+	}
+	return nil
+}
+
+func addGenerator(config *Config, g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+	preHandler := func(_ context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
+		if state.rounds == 0 {
+			state.input = input
+			return input, nil
+		}
+
+		// revise the last draft according to the critique
+		msgs := make([]*schema.Message, 0, len(state.input)+2)
+		msgs = append(msgs, state.input...)
+		return append(msgs, state.draft,
+			schema.UserMessage("Revise your last answer according to the review below, reply with the revised answer only.\n"+
+				state.critique.Content)), nil
+	}
+	postHandler := func(_ context.Context, out *schema.Message, state *state) (*schema.Message, error) {
+		state.rounds++
+		state.draft = out
+		return out, nil
+	}
+
+	opts := []compose.GraphAddNodeOpt{compose.WithNodeName(generatorNodeKey),
+		compose.WithStatePreHandler(preHandler), compose.WithStatePostHandler(postHandler)}
+	if config.GeneratorModel != nil {
+		return g.AddChatModelNode(generatorNodeKey, config.GeneratorModel, opts...)
+	}
+
+	lambda, err := compose.AnyLambda(config.Invokable, config.Streamable, nil, nil, compose.WithLambdaType("Generator"))
+	if err != nil {
+		return err
+	}
+	return g.AddLambdaNode(generatorNodeKey, lambda, opts...)
+}
+
+func (a *Agent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+	return a.runnable.Invoke(withCallbacks(ctx, opts...), input, agent.GetComposeOptions(opts...)...)
+}
+
+func (a *Agent) Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.StreamReader[*schema.Message], error) {
+	return a.runnable.Stream(withCallbacks(ctx, opts...), input, agent.GetComposeOptions(opts...)...)
+}
+
+// ExportGraph exports the underlying graph from Agent, along with the []compose.GraphAddNodeOpt to be used when adding this graph to another graph.
+func (a *Agent) ExportGraph() (compose.AnyGraph, []compose.GraphAddNodeOpt) {
+	return a.graph, a.graphAddNodeOpts
+}
+
+func startsWithApprovedMarker(_ context.Context, critique *schema.Message) (bool, error) {
+	return strings.HasPrefix(strings.TrimSpace(critique.Content), ApprovedMarker), nil
+}
+
+func withoutSystemMessages(msgs []*schema.Message) []*schema.Message {
+	ret := make([]*schema.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.Role != schema.System {
+			ret = append(ret, msg)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reflection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/flow/agent"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type draftRecorder struct {
+	infos []*DraftInfo
+}
+
+func (r *draftRecorder) OnDraft(_ context.Context, info *DraftInfo) {
+	r.infos = append(r.infos, info)
+}
+
+func TestReflectionAgent(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	critic := mockModel.NewMockChatModel(ctrl)
+
+	var generatorInputs [][]*schema.Message
+	generate := func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+		generatorInputs = append(generatorInputs, input)
+		return schema.AssistantMessage(fmt.Sprintf("draft %d", len(generatorInputs)), nil), nil
+	}
+
+	a, err := NewAgent(ctx, &Config{
+		Invokable: generate,
+		Critic:    critic,
+		Rubric:    "be concise",
+		MaxRounds: 3,
+	})
+	assert.NoError(t, err)
+
+	t.Run("approved after revision", func(t *testing.T) {
+		generatorInputs = nil
+		critic.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				assert.Equal(t, schema.System, input[0].Role)
+				assert.True(t, strings.Contains(input[0].Content, "be concise"))
+				assert.Equal(t, "draft 1", input[len(input)-2].Content)
+				return schema.AssistantMessage("too long", nil), nil
+			}).Times(1)
+		critic.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(schema.AssistantMessage(ApprovedMarker, nil), nil).Times(1)
+
+		recorder := &draftRecorder{}
+		out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("write")}, WithCallbacks(recorder))
+		assert.NoError(t, err)
+		assert.Equal(t, "draft 2", out.Content)
+
+		assert.Len(t, generatorInputs, 2)
+		revision := generatorInputs[1]
+		assert.Equal(t, "draft 1", revision[1].Content)
+		assert.True(t, strings.Contains(revision[2].Content, "too long"))
+
+		assert.Len(t, recorder.infos, 2)
+		assert.Equal(t, 1, recorder.infos[0].Round)
+		assert.False(t, recorder.infos[0].Approved)
+		assert.Equal(t, "too long", recorder.infos[0].Critique.Content)
+		assert.True(t, recorder.infos[1].Approved)
+	})
+
+	t.Run("max rounds reached", func(t *testing.T) {
+		generatorInputs = nil
+		critic.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("not good", nil)}), nil
+			}).Times(3)
+
+		sr, err := a.Stream(ctx, []*schema.Message{schema.UserMessage("write")})
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "draft 3", out.Content)
+		assert.Len(t, generatorInputs, 3)
+	})
+
+	_, err = NewAgent(ctx, &Config{Invokable: generate, Critic: critic})
+	assert.ErrorContains(t, err, "rubric")
+}
@@ -30,6 +30,9 @@ func ChatModelWithTools(model_ model.ChatModel, toolCallingModel model.ToolCalli
 		if len(toolInfos) == 0 {
 			return toolCallingModel, nil
 		}
+		if err := model.CheckToolCalling(toolCallingModel); err != nil {
+			return nil, err
+		}
 		return toolCallingModel.WithTools(toolInfos)
 	}
 
@@ -37,6 +40,9 @@ func ChatModelWithTools(model_ model.ChatModel, toolCallingModel model.ToolCalli
 		if len(toolInfos) == 0 {
 			return model_, nil
 		}
+		if err := model.CheckToolCalling(model_); err != nil {
+			return nil, err
+		}
 		err := model_.BindTools(toolInfos)
 		if err != nil {
 			return nil, err
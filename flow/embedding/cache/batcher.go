@@ -0,0 +1,127 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// batcher gathers the texts of concurrent calls within a time window, and embeds the distinct ones together.
+type batcher struct {
+	embedder     embedding.Embedder
+	window       time.Duration
+	maxBatchSize int
+
+	mu      sync.Mutex
+	pending []*batchCall
+	size    int
+	timer   *time.Timer
+}
+
+type batchCall struct {
+	ctx        context.Context
+	texts      []string
+	embeddings [][]float64
+	err        error
+	done       chan struct{}
+}
+
+func newBatcher(embedder embedding.Embedder, window time.Duration, maxBatchSize int) *batcher {
+	return &batcher{embedder: embedder, window: window, maxBatchSize: maxBatchSize}
+}
+
+// submit adds the texts to the pending batch, and waits for their embeddings.
+func (b *batcher) submit(ctx context.Context, texts []string) ([][]float64, error) {
+	call := &batchCall{ctx: ctx, texts: texts, done: make(chan struct{})}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	b.size += len(texts)
+	if b.maxBatchSize > 0 && b.size >= b.maxBatchSize {
+		calls := b.take()
+		b.mu.Unlock()
+		go b.flush(calls)
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, func() {
+				b.mu.Lock()
+				calls := b.take()
+				b.mu.Unlock()
+				b.flush(calls)
+			})
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.embeddings, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take takes the pending calls, must be called with the lock held.
+func (b *batcher) take() []*batchCall {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	calls := b.pending
+	b.pending, b.size = nil, 0
+	return calls
+}
+
+func (b *batcher) flush(calls []*batchCall) {
+	if len(calls) == 0 {
+		return
+	}
+
+	var (
+		texts []string
+		index = make(map[string]int)
+	)
+	for _, call := range calls {
+		for _, text := range call.texts {
+			if _, ok := index[text]; !ok {
+				index[text] = len(texts)
+				texts = append(texts, text)
+			}
+		}
+	}
+
+	// the batch outlives the cancellation of any single call, but is reported under the trace of the first one
+	ctx := callbacks.PropagateContext(context.Background(), calls[0].ctx)
+	result, err := embedInBatches(ctx, b.embedder, texts, b.maxBatchSize)
+	for _, call := range calls {
+		if err != nil {
+			call.err = err
+		} else {
+			call.embeddings = make([][]float64, len(call.texts))
+			for i, text := range call.texts {
+				// the calls sharing a text get their own copies, so that one mutating its result doesn't affect the others
+				call.embeddings[i] = copyEmbedding(result[index[text]])
+			}
+		}
+		close(call.done)
+	}
+}
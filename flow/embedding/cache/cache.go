@@ -0,0 +1,245 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cache provides an embedding.Embedder decorator, which deduplicates the texts,
+// caches the embeddings, and batches the texts of concurrent calls into fewer requests.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// CacheStatsExtraKey is the key of the *CacheStats in the Extra of embedding.CallbackOutput.
+const CacheStatsExtraKey = "_eino_embedding_cache_stats"
+
+// CacheStats is the cache statistics of an EmbedStrings call, reported by callbacks.
+type CacheStats struct {
+	// Hits is the number of distinct texts served from the cache.
+	Hits int
+	// Misses is the number of distinct texts embedded by the underlying embedder.
+	Misses int
+	// Duplicates is the number of texts identical to a previous one in the same call.
+	Duplicates int
+}
+
+// Config is the config of the cached embedder.
+type Config struct {
+	// Embedder is the underlying embedder, required.
+	Embedder embedding.Embedder
+	// Store stores the embeddings. Optional. Default is an unlimited in-memory store.
+	Store Store
+	// TTL is the time to live of the cached embeddings. Optional. 0 means never expire.
+	TTL time.Duration
+	// Namespace separates the embeddings of different embedders sharing a Store, e.g. the model name.
+	// The model of embedding.WithModel is part of the key as well.
+	Namespace string
+
+	// BatchWindow is the time window to gather the texts of concurrent calls into one request.
+	// Optional. 0 means no batching across calls. Calls with options are never batched with others.
+	BatchWindow time.Duration
+	// MaxBatchSize is the max number of texts of a request to the underlying embedder,
+	// a batch is sent immediately once it's full. Optional. 0 means unlimited.
+	MaxBatchSize int
+}
+
+// Embedder is the cached embedder.
+type Embedder struct {
+	embedder     embedding.Embedder
+	store        Store
+	ttl          time.Duration
+	namespace    string
+	maxBatchSize int
+	batcher      *batcher
+}
+
+// NewEmbedder creates a cached embedder.
+// e.g.
+//
+//	emb, err := cache.NewEmbedder(ctx, &cache.Config{
+//		Embedder:    arkEmbedder,
+//		TTL:         24 * time.Hour,
+//		BatchWindow: 10 * time.Millisecond,
+//	})
+//	indexer, err := redis.NewIndexer(ctx, &redis.IndexerConfig{Embedding: emb})
+func NewEmbedder(ctx context.Context, config *Config) (*Embedder, error) {
+	if config == nil || config.Embedder == nil {
+		return nil, errors.New("cache embedder has no underlying embedder")
+	}
+	if config.TTL < 0 || config.BatchWindow < 0 || config.MaxBatchSize < 0 {
+		return nil, errors.New("cache embedder ttl, batch window and max batch size must not be negative")
+	}
+
+	store := config.Store
+	if store == nil {
+		store = NewMemoryStore(0)
+	}
+
+	e := &Embedder{
+		embedder:     config.Embedder,
+		store:        store,
+		ttl:          config.TTL,
+		namespace:    config.Namespace,
+		maxBatchSize: config.MaxBatchSize,
+	}
+	if config.BatchWindow > 0 {
+		e.batcher = newBatcher(config.Embedder, config.BatchWindow, config.MaxBatchSize)
+	}
+	return e, nil
+}
+
+// EmbedStrings returns the embeddings of the texts, embedding only the distinct texts missing in the cache.
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) (embeddings [][]float64, err error) {
+	ctx = callbacks.EnsureRunInfo(ctx, e.GetType(), components.ComponentOfEmbedding)
+	ctx = callbacks.OnStart(ctx, &embedding.CallbackInput{Texts: texts})
+	defer func() {
+		if err != nil {
+			_ = callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var model string
+	if m := embedding.GetCommonOptions(nil, opts...).Model; m != nil {
+		model = *m
+	}
+
+	var (
+		stats   = &CacheStats{}
+		vectors = make(map[string][]float64, len(texts))
+		keys    = make(map[string]string, len(texts))
+		misses  []string
+	)
+	for _, text := range texts {
+		if _, ok := keys[text]; ok {
+			stats.Duplicates++
+			continue
+		}
+		key := e.key(model, text)
+		keys[text] = key
+
+		vector, ok, err_ := e.store.Get(ctx, key)
+		if err_ != nil {
+			return nil, fmt.Errorf("get embedding from cache fail: %w", err_)
+		}
+		if ok {
+			stats.Hits++
+			vectors[text] = vector
+			continue
+		}
+		stats.Misses++
+		misses = append(misses, text)
+	}
+
+	if len(misses) > 0 {
+		var result [][]float64
+		if e.batcher != nil && len(opts) == 0 {
+			result, err = e.batcher.submit(ctx, misses)
+		} else {
+			result, err = embedInBatches(ctx, e.embedder, misses, e.maxBatchSize, opts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i, text := range misses {
+			vectors[text] = result[i]
+			if err = e.store.Set(ctx, keys[text], result[i], e.ttl); err != nil {
+				return nil, fmt.Errorf("set embedding to cache fail: %w", err)
+			}
+		}
+	}
+
+	embeddings = make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i] = vectors[text]
+	}
+
+	_ = callbacks.OnEnd(ctx, &embedding.CallbackOutput{
+		Embeddings: embeddings,
+		Extra:      map[string]any{CacheStatsExtraKey: stats},
+	})
+
+	return embeddings, nil
+}
+
+func (e *Embedder) key(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(e.namespace))
+	h.Write([]byte{0})
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GetType returns the type of the embedder, i.e. Cached + the type of the underlying embedder.
+func (e *Embedder) GetType() string {
+	typ, _ := components.GetType(e.embedder)
+	return "Cached" + typ
+}
+
+// IsCallbacksEnabled checks if the callbacks are enabled for the embedder.
+func (e *Embedder) IsCallbacksEnabled() bool {
+	return true
+}
+
+// GetCacheStats returns the cache statistics from the embedding callback output of the cached embedder, nil if there is none.
+func GetCacheStats(output *embedding.CallbackOutput) *CacheStats {
+	if output == nil {
+		return nil
+	}
+	stats, _ := output.Extra[CacheStatsExtraKey].(*CacheStats)
+	return stats
+}
+
+// embedInBatches embeds the texts by requests of at most maxBatchSize texts.
+func embedInBatches(ctx context.Context, embedder embedding.Embedder, texts []string, maxBatchSize int,
+	opts ...embedding.Option) ([][]float64, error) {
+
+	if maxBatchSize <= 0 {
+		maxBatchSize = len(texts)
+	}
+
+	runInfo := &callbacks.RunInfo{Component: components.ComponentOfEmbedding}
+	runInfo.Type, _ = components.GetType(embedder)
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+	ctx = callbacks.ReuseHandlers(ctx, runInfo)
+
+	ret := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		result, err := embedder.EmbedStrings(ctx, texts[start:end], opts...)
+		if err != nil {
+			return nil, err
+		}
+		if len(result) != end-start {
+			return nil, fmt.Errorf("embedder returns %d embeddings for %d texts", len(result), end-start)
+		}
+		ret = append(ret, result...)
+	}
+	return ret, nil
+}
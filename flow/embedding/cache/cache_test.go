@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+type countingEmbedder struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (c *countingEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, texts)
+
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = []float64{float64(len(text))}
+	}
+	return ret, nil
+}
+
+func (c *countingEmbedder) getCalls() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func TestEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dedupe and cache", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner})
+		assert.NoError(t, err)
+
+		var stats []*CacheStats
+		handler := callbacks.NewHandlerBuilder().
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				stats = append(stats, GetCacheStats(embedding.ConvCallbackOutput(output)))
+				return ctx
+			}).Build()
+		cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+		out, err := e.EmbedStrings(cbCtx, []string{"a", "bb", "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}, {2}, {1}}, out)
+
+		out, err = e.EmbedStrings(cbCtx, []string{"bb", "ccc"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{2}, {3}}, out)
+
+		// the model is part of the key
+		_, err = e.EmbedStrings(cbCtx, []string{"a"}, embedding.WithModel("another"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, [][]string{{"a", "bb"}, {"ccc"}, {"a"}}, inner.getCalls())
+		assert.Equal(t, []*CacheStats{
+			{Hits: 0, Misses: 2, Duplicates: 1},
+			{Hits: 1, Misses: 1},
+			{Misses: 1},
+		}, stats)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, TTL: 20 * time.Millisecond})
+		assert.NoError(t, err)
+
+		_, err = e.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+		_, err = e.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+		assert.Len(t, inner.getCalls(), 1)
+
+		time.Sleep(30 * time.Millisecond)
+		_, err = e.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+		assert.Len(t, inner.getCalls(), 2)
+	})
+
+	t.Run("batch across calls", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, BatchWindow: 50 * time.Millisecond})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		results := make([][][]float64, 3)
+		for i, texts := range [][]string{{"a", "bb"}, {"bb", "ccc"}, {"dddd"}} {
+			wg.Add(1)
+			go func(i int, texts []string) {
+				defer wg.Done()
+				out, err_ := e.EmbedStrings(ctx, texts)
+				assert.NoError(t, err_)
+				results[i] = out
+			}(i, texts)
+		}
+		wg.Wait()
+
+		assert.Equal(t, [][][]float64{{{1}, {2}}, {{2}, {3}}, {{4}}}, results)
+		// the embedding of "bb" is not shared by the calls
+		results[0][1][0] = 0
+		assert.Equal(t, []float64{2}, results[1][0])
+		calls := inner.getCalls()
+		assert.Len(t, calls, 1)
+		assert.ElementsMatch(t, []string{"a", "bb", "ccc", "dddd"}, calls[0])
+	})
+
+	t.Run("max batch size", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		e, err := NewEmbedder(ctx, &Config{Embedder: inner, MaxBatchSize: 2})
+		assert.NoError(t, err)
+
+		out, err := e.EmbedStrings(ctx, []string{"a", "bb", "ccc", "dddd", "eeeee"})
+		assert.NoError(t, err)
+		assert.Len(t, out, 5)
+		assert.Equal(t, [][]string{{"a", "bb"}, {"ccc", "dddd"}, {"eeeee"}}, inner.getCalls())
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewEmbedder(ctx, &Config{})
+		assert.Error(t, err)
+		_, err = NewEmbedder(ctx, &Config{Embedder: &countingEmbedder{}, TTL: -1})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+
+	assert.NoError(t, s.Set(ctx, "a", []float64{1}, time.Hour))
+	assert.NoError(t, s.Set(ctx, "b", []float64{2}, time.Minute))
+	assert.NoError(t, s.Set(ctx, "c", []float64{3}, 0))
+
+	// b expires the earliest, so it's evicted
+	_, ok, err := s.Get(ctx, "b")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	v, ok, err := s.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []float64{1}, v)
+
+	// the returned embedding is a copy
+	v[0] = 100
+	v, _, _ = s.Get(ctx, "a")
+	assert.Equal(t, []float64{1}, v)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store stores the embeddings by key, e.g. redis.
+type Store interface {
+	// Get returns the embedding of the key, false if it doesn't exist or has expired.
+	Get(ctx context.Context, key string) ([]float64, bool, error)
+	// Set stores the embedding of the key, which expires after ttl. ttl 0 means never expire.
+	Set(ctx context.Context, key string, embedding []float64, ttl time.Duration) error
+}
+
+// NewMemoryStore creates a Store in memory, expired entries are removed lazily.
+// maxEntries limits the number of entries, the entry expiring the earliest is evicted when the store is full.
+// 0 means unlimited.
+func NewMemoryStore(maxEntries int) Store {
+	return &memoryStore{maxEntries: maxEntries, entries: make(map[string]*memoryEntry)}
+}
+
+type memoryEntry struct {
+	embedding []float64
+	expireAt  time.Time // zero means never expire
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*memoryEntry
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) ([]float64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if e.expired(time.Now()) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return copyEmbedding(e.embedding), true, nil
+}
+
+func (m *memoryStore) Set(_ context.Context, key string, embedding []float64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := &memoryEntry{embedding: copyEmbedding(embedding)}
+	if ttl > 0 {
+		e.expireAt = time.Now().Add(ttl)
+	}
+
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		m.evict()
+	}
+	m.entries[key] = e
+	return nil
+}
+
+// evict removes the expired entries, or the entry expiring the earliest if none has expired.
+func (m *memoryStore) evict() {
+	now := time.Now()
+	var (
+		victim string
+		first  *memoryEntry
+	)
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+			continue
+		}
+		if first == nil || expireBefore(e, first) {
+			victim, first = k, e
+		}
+	}
+	if len(m.entries) >= m.maxEntries && first != nil {
+		delete(m.entries, victim)
+	}
+}
+
+func expireBefore(a, b *memoryEntry) bool {
+	if a.expireAt.IsZero() {
+		return false
+	}
+	return b.expireAt.IsZero() || a.expireAt.Before(b.expireAt)
+}
+
+func copyEmbedding(embedding []float64) []float64 {
+	ret := make([]float64, len(embedding))
+	copy(ret, embedding)
+	return ret
+}
@@ -0,0 +1,344 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// Example is a single case of the dataset.
+type Example[I, O any] struct {
+	ID       string         `json:"id,omitempty"`
+	Input    I              `json:"input"`
+	Expected O              `json:"expected"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// LoadJSONL loads the examples from the JSON Lines reader, one example per line, e.g.
+//
+//	{"id": "1", "input": "what's 1+1?", "expected": "2"}
+//
+// Empty lines are skipped, and the line number is used as ID if it's absent.
+func LoadJSONL[I, O any](r io.Reader) ([]*Example[I, O], error) {
+	var ret []*Example[I, O]
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		ex := &Example[I, O]{}
+		if err := sonic.UnmarshalString(text, ex); err != nil {
+			return nil, fmt.Errorf("unmarshal example at line %d fail: %w", line, err)
+		}
+		if ex.ID == "" {
+			ex.ID = fmt.Sprintf("%d", line)
+		}
+		ret = append(ret, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read dataset fail: %w", err)
+	}
+	return ret, nil
+}
+
+// Mapping maps the columns of the flat records, i.e. the CSV rows or the top-level fields of the JSON objects, to the examples.
+// A value is converted to the type of the target, a string is parsed as JSON unless the target is a string,
+// e.g. "42" for an int field, or `["a","b"]` for a []string field, and an empty string leaves the target zero.
+type Mapping struct {
+	// ID is the column of the example ID, "id" by default, and the row number is used if it's absent or empty.
+	ID string
+	// Input is the column of the input, "input" by default.
+	// If the record has no such column and the input type is a struct, its fields are filled from the columns
+	// named by the `eval` tag of the fields, or the json tag, or the field name.
+	Input string
+	// Expected is the column of the expected output, "expected" by default, filled like Input.
+	// The expected output is left zero if it's absent.
+	Expected string
+	// Metadata are the columns copied to the metadata of the examples, all the columns not mapped by default.
+	Metadata []string
+}
+
+// LoadCSV loads the examples from the CSV reader by the mapping, whose first row is the header naming the columns.
+// e.g.
+//
+//	id,question,answer
+//	1,what's 1+1?,2
+//
+//	type QA struct {
+//		Question string `eval:"question"`
+//	}
+//	dataset, err := eval.LoadCSV[*QA, string](f, &eval.Mapping{Expected: "answer"})
+func LoadCSV[I, O any](r io.Reader, mapping *Mapping) ([]*Example[I, O], error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read csv header fail: %w", err)
+	}
+
+	var records []map[string]any
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row %d fail: %w", len(records)+1, err)
+		}
+		record := make(map[string]any, len(header))
+		for i, col := range header {
+			if i < len(row) {
+				record[col] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return FromRecords[I, O](records, mapping)
+}
+
+// LoadJSONLWithMapping loads the examples from the JSON Lines reader by the mapping, one flat JSON object per line, e.g.
+//
+//	{"id": "1", "question": "what's 1+1?", "answer": 2}
+//
+// Empty lines are skipped.
+func LoadJSONLWithMapping[I, O any](r io.Reader, mapping *Mapping) ([]*Example[I, O], error) {
+	var records []map[string]any
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		record := map[string]any{}
+		if err := sonic.UnmarshalString(text, &record); err != nil {
+			return nil, fmt.Errorf("unmarshal record at line %d fail: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read dataset fail: %w", err)
+	}
+	return FromRecords[I, O](records, mapping)
+}
+
+// FromRecords converts the flat records to the examples by the mapping, e.g. the rows read from a database.
+func FromRecords[I, O any](records []map[string]any, mapping *Mapping) ([]*Example[I, O], error) {
+	m := Mapping{ID: "id", Input: "input", Expected: "expected"}
+	if mapping != nil {
+		if mapping.ID != "" {
+			m.ID = mapping.ID
+		}
+		if mapping.Input != "" {
+			m.Input = mapping.Input
+		}
+		if mapping.Expected != "" {
+			m.Expected = mapping.Expected
+		}
+		m.Metadata = mapping.Metadata
+	}
+
+	ret := make([]*Example[I, O], 0, len(records))
+	for i, record := range records {
+		ex := &Example[I, O]{}
+		used := map[string]bool{m.ID: true}
+
+		if id, ok := record[m.ID]; ok && id != nil {
+			ex.ID = fmt.Sprint(id)
+		}
+		if ex.ID == "" {
+			ex.ID = fmt.Sprintf("%d", i+1)
+		}
+		if err := mapValue(record, m.Input, &ex.Input, used, true); err != nil {
+			return nil, fmt.Errorf("map input of record %d fail: %w", i+1, err)
+		}
+		if err := mapValue(record, m.Expected, &ex.Expected, used, false); err != nil {
+			return nil, fmt.Errorf("map expected of record %d fail: %w", i+1, err)
+		}
+
+		if m.Metadata != nil {
+			for _, col := range m.Metadata {
+				if v, ok := record[col]; ok {
+					if ex.Metadata == nil {
+						ex.Metadata = make(map[string]any)
+					}
+					ex.Metadata[col] = v
+				}
+			}
+		} else {
+			for col, v := range record {
+				if used[col] {
+					continue
+				}
+				if ex.Metadata == nil {
+					ex.Metadata = make(map[string]any)
+				}
+				ex.Metadata[col] = v
+			}
+		}
+		ret = append(ret, ex)
+	}
+	return ret, nil
+}
+
+// mapValue sets the column of the record to target, or fills the fields of the struct target from the columns.
+func mapValue[T any](record map[string]any, col string, target *T, used map[string]bool, required bool) error {
+	if v, ok := record[col]; ok {
+		used[col] = true
+		return convertValue(v, reflect.ValueOf(target).Elem())
+	}
+
+	typ := generic.TypeOf[T]()
+	structTyp := typ
+	if structTyp.Kind() == reflect.Ptr {
+		structTyp = structTyp.Elem()
+	}
+	if structTyp.Kind() != reflect.Struct {
+		if required {
+			return fmt.Errorf("column %s not found", col)
+		}
+		return nil
+	}
+
+	sv := reflect.New(structTyp).Elem()
+	for i := 0; i < structTyp.NumField(); i++ {
+		f := structTyp.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := columnOfField(f)
+		if name == "-" {
+			continue
+		}
+		v, ok := record[name]
+		if !ok {
+			continue
+		}
+		used[name] = true
+		if err := convertValue(v, sv.Field(i)); err != nil {
+			return fmt.Errorf("convert column %s to field %s fail: %w", name, f.Name, err)
+		}
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		reflect.ValueOf(target).Elem().Set(sv.Addr())
+	} else {
+		reflect.ValueOf(target).Elem().Set(sv)
+	}
+	return nil
+}
+
+func columnOfField(f reflect.StructField) string {
+	if tag := f.Tag.Get("eval"); tag != "" {
+		return tag
+	}
+	if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+// convertValue converts v to the type of dst and sets it.
+func convertValue(v any, dst reflect.Value) error {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return nil
+	}
+
+	var data string
+	if s, ok := v.(string); ok {
+		if dst.Kind() == reflect.String {
+			dst.SetString(s)
+			return nil
+		}
+		if s == "" { // e.g. an empty cell of CSV
+			return nil
+		}
+		data = s
+	} else {
+		b, err := sonic.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+
+	ptr := reflect.New(dst.Type())
+	if err := sonic.UnmarshalString(data, ptr.Interface()); err != nil {
+		return err
+	}
+	dst.Set(ptr.Elem())
+	return nil
+}
+
+// Sample returns n examples of the dataset chosen by the seed, in the order of the dataset,
+// so that the same seed always chooses the same examples. The whole dataset is returned if n isn't less than its size.
+func Sample[I, O any](dataset []*Example[I, O], n int, seed int64) []*Example[I, O] {
+	if n >= len(dataset) {
+		return dataset
+	}
+	if n <= 0 {
+		return nil
+	}
+	indexes := rand.New(rand.NewSource(seed)).Perm(len(dataset))[:n]
+	sort.Ints(indexes)
+	ret := make([]*Example[I, O], n)
+	for i, idx := range indexes {
+		ret[i] = dataset[idx]
+	}
+	return ret
+}
+
+// Split splits the dataset into two parts by the ratio of the first part, e.g. 0.8 for a train/test split, keeping the order.
+// An example is assigned by the hash of its ID and the seed, so it stays in the same part when the dataset grows.
+func Split[I, O any](dataset []*Example[I, O], ratio float64, seed int64) (first, second []*Example[I, O]) {
+	for _, ex := range dataset {
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%d:%s", seed, ex.ID)
+		if float64(h.Sum64()%10000)/10000 < ratio {
+			first = append(first, ex)
+		} else {
+			second = append(second, ex)
+		}
+	}
+	return first, second
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type qaInput struct {
+	Question string   `eval:"question"`
+	Tags     []string `json:"tags"`
+	Level    int
+	ignored  string
+}
+
+func TestLoadCSV(t *testing.T) {
+	dataset, err := LoadCSV[*qaInput, int](strings.NewReader(`id,question,tags,Level,answer,source
+q1,"what's 1+1?","[""math""]",1,2,manual
+,what's 2+2?,,2,4,generated
+`), &Mapping{Expected: "answer"})
+	assert.NoError(t, err)
+	assert.Len(t, dataset, 2)
+
+	assert.Equal(t, "q1", dataset[0].ID)
+	assert.Equal(t, &qaInput{Question: "what's 1+1?", Tags: []string{"math"}, Level: 1}, dataset[0].Input)
+	assert.Equal(t, 2, dataset[0].Expected)
+	assert.Equal(t, map[string]any{"source": "manual"}, dataset[0].Metadata)
+
+	assert.Equal(t, "2", dataset[1].ID)
+	assert.Nil(t, dataset[1].Input.Tags)
+
+	dataset2, err := LoadCSV[string, string](strings.NewReader("question,answer,source\nhi,hello,a\n"),
+		&Mapping{Input: "question", Expected: "answer", Metadata: []string{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dataset2[0].ID)
+	assert.Equal(t, "hi", dataset2[0].Input)
+	assert.Equal(t, "hello", dataset2[0].Expected)
+	assert.Nil(t, dataset2[0].Metadata)
+
+	_, err = LoadCSV[string, string](strings.NewReader("question\nhi\n"), nil)
+	assert.ErrorContains(t, err, "column input not found")
+	_, err = LoadCSV[*qaInput, int](strings.NewReader("question,Level\nhi,high\n"), nil)
+	assert.ErrorContains(t, err, "field Level")
+}
+
+func TestLoadJSONLWithMapping(t *testing.T) {
+	dataset, err := LoadJSONLWithMapping[qaInput, []string](strings.NewReader(`{"id": 7, "question": "colors?", "tags": ["art"], "Level": "3", "expected": ["red", "blue"]}
+
+{"question": "shapes?", "expected": "[\"circle\"]", "source": "x"}
+`), &Mapping{Metadata: []string{"source"}})
+	assert.NoError(t, err)
+	assert.Len(t, dataset, 2)
+	assert.Equal(t, "7", dataset[0].ID)
+	assert.Equal(t, qaInput{Question: "colors?", Tags: []string{"art"}, Level: 3}, dataset[0].Input)
+	assert.Equal(t, []string{"red", "blue"}, dataset[0].Expected)
+	assert.Nil(t, dataset[0].Metadata)
+	assert.Equal(t, "2", dataset[1].ID)
+	assert.Equal(t, []string{"circle"}, dataset[1].Expected)
+	assert.Equal(t, map[string]any{"source": "x"}, dataset[1].Metadata)
+}
+
+func TestSampleAndSplit(t *testing.T) {
+	var dataset []*Example[string, string]
+	for i := 0; i < 1000; i++ {
+		dataset = append(dataset, &Example[string, string]{ID: fmt.Sprintf("%d", i)})
+	}
+
+	s1 := Sample(dataset, 10, 42)
+	assert.Len(t, s1, 10)
+	assert.Equal(t, s1, Sample(dataset, 10, 42))
+	assert.NotEqual(t, s1, Sample(dataset, 10, 43))
+	assert.Len(t, Sample(dataset, 2000, 42), 1000)
+	assert.Empty(t, Sample(dataset, 0, 42))
+
+	train, test := Split(dataset, 0.8, 1)
+	assert.Equal(t, 1000, len(train)+len(test))
+	assert.InDelta(t, 800, len(train), 60)
+
+	// stable when the dataset grows
+	train2, _ := Split(append(dataset, &Example[string, string]{ID: "new"}), 0.8, 1)
+	assert.Equal(t, train, train2[:len(train)])
+}
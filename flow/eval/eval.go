@@ -0,0 +1,217 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eval runs a Runnable over a dataset, scores the outputs and aggregates the metrics.
+//
+//	dataset, err := eval.LoadJSONL[string, string](f)
+//	report, err := eval.Run(ctx, runnable, dataset, &eval.Config[string, string]{
+//		Scorers: []eval.Scorer[string, string]{eval.NewExactMatch[string, string](nil)},
+//	})
+//	fmt.Println(report.Metrics["exact_match"].PassRate)
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/compose"
+)
+
+// ComponentOfEvalExample is the component of the callbacks reported for every example,
+// whose input is the *Example and output is the *Result.
+// The runnable and scorers are called with the context of the example, so their callbacks can be correlated to it.
+const ComponentOfEvalExample components.Component = "EvalExample"
+
+// Config is the config of the evaluation.
+type Config[I, O any] struct {
+	// Scorers score the output of every example.
+	Scorers []Scorer[I, O]
+	// Concurrency is the number of examples evaluated concurrently, 1 by default.
+	Concurrency int
+	// Options are passed to every call of the runnable.
+	Options []compose.Option
+	// Callbacks receive the per-example traces, in addition to the global handlers.
+	Callbacks []callbacks.Handler
+}
+
+// Result is the evaluation result of an example.
+type Result[I, O any] struct {
+	Example *Example[I, O]
+	Output  O
+	// Err is the error returned by the runnable, the example isn't scored if it's not nil.
+	Err     error
+	Scores  []*Score
+	Latency time.Duration
+}
+
+// Metric is the aggregated scores of a scorer.
+type Metric struct {
+	// Count is the number of scored examples.
+	Count int
+	// Errors is the number of examples the scorer failed on.
+	Errors int
+	// Mean is the mean score of the scored examples.
+	Mean float64
+	// PassRate is the ratio of the passed examples among the scored ones.
+	PassRate float64
+}
+
+// Report is the result of the evaluation.
+type Report[I, O any] struct {
+	// Results are in the order of the dataset.
+	Results []*Result[I, O]
+	// Metrics are keyed by the scorer name.
+	Metrics map[string]*Metric
+	// Failed is the number of examples the runnable failed on.
+	Failed int
+	// MeanLatency is the mean latency of the runnable calls.
+	MeanLatency time.Duration
+}
+
+// Run evaluates the runnable over the dataset.
+// Failures of the runnable or scorers are recorded in the report, only the cancellation of ctx aborts the evaluation.
+func Run[I, O any](ctx context.Context, r compose.Runnable[I, O], dataset []*Example[I, O], config *Config[I, O]) (*Report[I, O], error) {
+	if r == nil {
+		return nil, errors.New("runnable is required")
+	}
+	if config == nil {
+		config = &Config[I, O]{}
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]*Result[I, O], len(dataset))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ex := range dataset {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, ex *Example[I, O]) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = runExample(ctx, r, ex, config)
+		}(i, ex)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return newReport(results, config.Scorers), nil
+}
+
+func runExample[I, O any](ctx context.Context, r compose.Runnable[I, O], ex *Example[I, O], config *Config[I, O]) (res *Result[I, O]) {
+	info := &callbacks.RunInfo{Name: ex.ID, Component: ComponentOfEvalExample}
+	if len(config.Callbacks) > 0 {
+		ctx = callbacks.InitCallbacks(ctx, info, config.Callbacks...)
+	} else {
+		ctx = callbacks.ReuseHandlers(ctx, info)
+	}
+	ctx = callbacks.OnStart(ctx, ex)
+	res = &Result[I, O]{Example: ex}
+	defer func() {
+		_ = callbacks.OnEnd(ctx, res)
+	}()
+
+	start := time.Now()
+	out, err := safeInvoke(ctx, r, ex.Input, config.Options...)
+	res.Latency = time.Since(start)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Output = out
+
+	for _, s := range config.Scorers {
+		score, err := s.Score(ctx, ex, out)
+		if err != nil {
+			score = &Score{Err: err}
+		} else if score == nil {
+			score = &Score{Err: errors.New("nil score")}
+		}
+		score.Name = s.Name()
+		res.Scores = append(res.Scores, score)
+	}
+	return res
+}
+
+func safeInvoke[I, O any](ctx context.Context, r compose.Runnable[I, O], input I, opts ...compose.Option) (out O, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("invoke panic: %v", e)
+		}
+	}()
+	return r.Invoke(ctx, input, opts...)
+}
+
+func newReport[I, O any](results []*Result[I, O], scorers []Scorer[I, O]) *Report[I, O] {
+	report := &Report[I, O]{
+		Results: results,
+		Metrics: make(map[string]*Metric, len(scorers)),
+	}
+	for _, s := range scorers {
+		report.Metrics[s.Name()] = &Metric{}
+	}
+
+	var latency time.Duration
+	passed := make(map[string]int, len(scorers))
+	for _, res := range results {
+		latency += res.Latency
+		if res.Err != nil {
+			report.Failed++
+			continue
+		}
+		for _, score := range res.Scores {
+			m := report.Metrics[score.Name]
+			if m == nil {
+				m = &Metric{}
+				report.Metrics[score.Name] = m
+			}
+			if score.Err != nil {
+				m.Errors++
+				continue
+			}
+			m.Count++
+			m.Mean += score.Value
+			if score.Pass {
+				passed[score.Name]++
+			}
+		}
+	}
+
+	for name, m := range report.Metrics {
+		if m.Count > 0 {
+			m.Mean /= float64(m.Count)
+			m.PassRate = float64(passed[name]) / float64(m.Count)
+		}
+	}
+	if len(results) > 0 {
+		report.MeanLatency = latency / time.Duration(len(results))
+	}
+	return report
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type judgeModel struct{}
+
+func (judgeModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	if strings.Contains(input[1].Content, "Actual output:\nHELLO") {
+		return schema.AssistantMessage("```json\n{\"score\": 0.9, \"reason\": \"same meaning\"}\n```", nil), nil
+	}
+	return schema.AssistantMessage(`{"score": 0.2, "reason": "different"}`, nil), nil
+}
+
+func (judgeModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = []float64{float64(len(text)), 1}
+	}
+	return ret, nil
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	dataset, err := LoadJSONL[string, string](strings.NewReader(`{"id": "a", "input": "hello", "expected": "HELLO"}
+
+{"input": "world", "expected": "earth"}
+{"id": "c", "input": "fail", "expected": "FAIL"}
+`))
+	assert.NoError(t, err)
+	assert.Len(t, dataset, 3)
+	assert.Equal(t, "3", dataset[1].ID)
+
+	r, err := compose.NewChain[string, string]().
+		AppendLambda(compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			if in == "fail" {
+				return "", errors.New("boom")
+			}
+			return strings.ToUpper(in), nil
+		})).
+		Compile(ctx)
+	assert.NoError(t, err)
+
+	judge, err := NewLLMJudge[string, string](&LLMJudgeConfig{Model: judgeModel{}})
+	assert.NoError(t, err)
+	sim, err := NewEmbeddingSimilarity[string, string](&EmbeddingSimilarityConfig{Embedder: lengthEmbedder{}, Threshold: 0.99})
+	assert.NoError(t, err)
+	broken := NewScorer("broken", func(context.Context, *Example[string, string], string) (*Score, error) {
+		return nil, errors.New("broken scorer")
+	})
+
+	var mu sync.Mutex
+	var traced []string
+	var lambdas int
+	handler := callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			mu.Lock()
+			defer mu.Unlock()
+			if info.Component == ComponentOfEvalExample {
+				res := output.(*Result[string, string])
+				traced = append(traced, info.Name+":"+res.Example.ID)
+			}
+			if info.Component == compose.ComponentOfLambda {
+				lambdas++
+			}
+			return ctx
+		}).Build()
+
+	report, err := Run(ctx, r, dataset, &Config[string, string]{
+		Scorers:     []Scorer[string, string]{NewExactMatch[string, string](nil), sim, judge, broken},
+		Concurrency: 2,
+		Callbacks:   []callbacks.Handler{handler},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Results, 3)
+	assert.Equal(t, "HELLO", report.Results[0].Output)
+	assert.Equal(t, "WORLD", report.Results[1].Output)
+	assert.EqualError(t, report.Results[2].Err, "[NodeRunError] boom\n------------------------\nnode path: [node_0]")
+	assert.Equal(t, 1, report.Failed)
+
+	assert.Equal(t, &Metric{Count: 2, Mean: 0.5, PassRate: 0.5}, report.Metrics["exact_match"])
+	assert.Equal(t, 2, report.Metrics["embedding_similarity"].Count)
+	assert.Equal(t, 1.0, report.Metrics["embedding_similarity"].PassRate)
+	assert.InDelta(t, 0.55, report.Metrics["llm_judge"].Mean, 1e-9)
+	assert.Equal(t, 0.5, report.Metrics["llm_judge"].PassRate)
+	assert.Equal(t, "same meaning", report.Results[0].Scores[2].Reason)
+	assert.Equal(t, &Metric{Errors: 2}, report.Metrics["broken"])
+
+	assert.ElementsMatch(t, []string{"a:a", "3:3", "c:c"}, traced)
+	// the failed lambda reports OnError instead
+	assert.Equal(t, 2, lambdas)
+}
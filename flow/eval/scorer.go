@@ -0,0 +1,251 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Score is the result of a scorer on an example.
+type Score struct {
+	// Name is the name of the scorer.
+	Name string
+	// Value is the score in [0, 1].
+	Value float64
+	// Pass reports whether the score reaches the threshold of the scorer.
+	Pass bool
+	// Reason explains the score, if the scorer gives one.
+	Reason string
+	// Err is the error met when scoring, the score is excluded from the metrics if it's not nil.
+	Err error
+}
+
+// Scorer scores the actual output of an example.
+type Scorer[I, O any] interface {
+	Name() string
+	Score(ctx context.Context, example *Example[I, O], actual O) (*Score, error)
+}
+
+// NewScorer creates a Scorer from the function.
+func NewScorer[I, O any](name string, fn func(ctx context.Context, example *Example[I, O], actual O) (*Score, error)) Scorer[I, O] {
+	return &scorerFunc[I, O]{name: name, fn: fn}
+}
+
+type scorerFunc[I, O any] struct {
+	name string
+	fn   func(ctx context.Context, example *Example[I, O], actual O) (*Score, error)
+}
+
+func (s *scorerFunc[I, O]) Name() string {
+	return s.name
+}
+
+func (s *scorerFunc[I, O]) Score(ctx context.Context, example *Example[I, O], actual O) (*Score, error) {
+	return s.fn(ctx, example, actual)
+}
+
+// ExactMatchConfig is the config of the exact match scorer.
+type ExactMatchConfig struct {
+	// Name of the scorer, "exact_match" by default.
+	Name string
+	// IgnoreCase compares the texts case-insensitively.
+	IgnoreCase bool
+	// TrimSpace trims the leading and trailing spaces of the texts before comparing.
+	TrimSpace bool
+}
+
+// NewExactMatch creates a scorer giving 1 if the text of the actual output equals to the expected one, otherwise 0.
+// The text of a string is itself, of a *schema.Message is its content, and other values are marshalled to JSON.
+func NewExactMatch[I, O any](config *ExactMatchConfig) Scorer[I, O] {
+	if config == nil {
+		config = &ExactMatchConfig{}
+	}
+	name := config.Name
+	if name == "" {
+		name = "exact_match"
+	}
+	normalize := func(s string) string {
+		if config.TrimSpace {
+			s = strings.TrimSpace(s)
+		}
+		if config.IgnoreCase {
+			s = strings.ToLower(s)
+		}
+		return s
+	}
+
+	return NewScorer(name, func(_ context.Context, example *Example[I, O], actual O) (*Score, error) {
+		if normalize(textOf(example.Expected)) == normalize(textOf(actual)) {
+			return &Score{Value: 1, Pass: true}, nil
+		}
+		return &Score{Value: 0}, nil
+	})
+}
+
+// DefaultSimilarityThreshold is the default threshold of the embedding similarity scorer.
+const DefaultSimilarityThreshold = 0.8
+
+// EmbeddingSimilarityConfig is the config of the embedding similarity scorer.
+type EmbeddingSimilarityConfig struct {
+	// Embedder embeds the expected and actual texts, required.
+	Embedder embedding.Embedder
+	// Name of the scorer, "embedding_similarity" by default.
+	Name string
+	// Threshold of the cosine similarity to pass, DefaultSimilarityThreshold by default.
+	Threshold float64
+}
+
+// NewEmbeddingSimilarity creates a scorer giving the cosine similarity between the embeddings of the expected and actual texts,
+// negative similarities are clamped to 0.
+func NewEmbeddingSimilarity[I, O any](config *EmbeddingSimilarityConfig) (Scorer[I, O], error) {
+	if config == nil || config.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	name := config.Name
+	if name == "" {
+		name = "embedding_similarity"
+	}
+	threshold := config.Threshold
+	if threshold <= 0 {
+		threshold = DefaultSimilarityThreshold
+	}
+
+	return NewScorer(name, func(ctx context.Context, example *Example[I, O], actual O) (*Score, error) {
+		vectors, err := config.Embedder.EmbedStrings(ctx, []string{textOf(example.Expected), textOf(actual)})
+		if err != nil {
+			return nil, fmt.Errorf("embed texts fail: %w", err)
+		}
+		if len(vectors) != 2 {
+			return nil, fmt.Errorf("unexpected embedding count: %d", len(vectors))
+		}
+		sim := math.Max(cosineSimilarity(vectors[0], vectors[1]), 0)
+		return &Score{Value: sim, Pass: sim >= threshold}, nil
+	}), nil
+}
+
+// DefaultJudgeThreshold is the default threshold of the LLM judge scorer.
+const DefaultJudgeThreshold = 0.5
+
+const defaultJudgeInstruction = `You are an impartial judge evaluating the output of an AI system.
+Compare the actual output with the expected output for the given input, according to the criteria.
+Criteria: %s
+Respond with only a JSON object: {"score": <number between 0 and 1>, "reason": "<short explanation>"}`
+
+// LLMJudgeConfig is the config of the LLM-as-judge scorer.
+type LLMJudgeConfig struct {
+	// Model is the judge model, required.
+	Model model.BaseChatModel
+	// Name of the scorer, "llm_judge" by default.
+	Name string
+	// Criteria describes how the output should be judged, "correctness and completeness compared to the expected output" by default.
+	Criteria string
+	// Threshold of the score to pass, DefaultJudgeThreshold by default.
+	Threshold float64
+	// Options are passed to every call of the judge model.
+	Options []model.Option
+}
+
+type judgeResult struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// NewLLMJudge creates a scorer asking the judge model to score the actual output in [0, 1] with a reason.
+func NewLLMJudge[I, O any](config *LLMJudgeConfig) (Scorer[I, O], error) {
+	if config == nil || config.Model == nil {
+		return nil, errors.New("judge model is required")
+	}
+	name := config.Name
+	if name == "" {
+		name = "llm_judge"
+	}
+	criteria := config.Criteria
+	if criteria == "" {
+		criteria = "correctness and completeness compared to the expected output"
+	}
+	threshold := config.Threshold
+	if threshold <= 0 {
+		threshold = DefaultJudgeThreshold
+	}
+	instruction := fmt.Sprintf(defaultJudgeInstruction, criteria)
+
+	return NewScorer(name, func(ctx context.Context, example *Example[I, O], actual O) (*Score, error) {
+		msg, err := config.Model.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(instruction),
+			schema.UserMessage(fmt.Sprintf("Input:\n%s\n\nExpected output:\n%s\n\nActual output:\n%s",
+				textOf(example.Input), textOf(example.Expected), textOf(actual))),
+		}, config.Options...)
+		if err != nil {
+			return nil, fmt.Errorf("generate judgement fail: %w", err)
+		}
+
+		var res judgeResult
+		if err = sonic.UnmarshalString(model.TrimCodeFence(msg.Content), &res); err != nil {
+			return nil, fmt.Errorf("unmarshal judgement fail: %w, content: %s", err, msg.Content)
+		}
+		value := math.Min(math.Max(res.Score, 0), 1)
+		return &Score{Value: value, Pass: value >= threshold, Reason: res.Reason}, nil
+	}), nil
+}
+
+func textOf(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case *schema.Message:
+		if t == nil {
+			return ""
+		}
+		return t.Content
+	case []byte:
+		return string(t)
+	case fmt.Stringer:
+		return t.String()
+	}
+	s, err := sonic.MarshalString(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return s
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var dot, na, nb float64
+	for i := 0; i < n; i++ {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package simulation runs multi-turn conversations between an agent under test and a simulated user,
+// e.g. to regression-test the multi-turn behavior of the agent.
+//
+//	user, err := simulation.NewSimulatedUser(ctx, &simulation.UserConfig{
+//		Model:   userModel,
+//		Persona: &simulation.Persona{Description: "a customer who lost the password", Goal: "reset the password"},
+//	})
+//	res, err := simulation.Run(ctx, &simulation.Config{Agent: agentRunnable, User: user, MaxTurns: 8})
+//	fmt.Println(res.Termination, len(res.Transcript.Messages))
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultMaxTurns is the default max turns of a simulation.
+const DefaultMaxTurns = 10
+
+// DefaultStopToken is the default token the simulated user replies with to end the conversation.
+const DefaultStopToken = "[END]"
+
+// Termination is the reason why a simulation stops.
+type Termination string
+
+const (
+	// TerminationMaxTurns means the conversation reached the max turns.
+	TerminationMaxTurns Termination = "max_turns"
+	// TerminationUserStopped means the simulated user ended the conversation with the stop token, e.g. when the goal is achieved.
+	TerminationUserStopped Termination = "user_stopped"
+	// TerminationStopCondition means Config.StopCondition ended the conversation.
+	TerminationStopCondition Termination = "stop_condition"
+	// TerminationAgentError means the agent under test failed.
+	TerminationAgentError Termination = "agent_error"
+	// TerminationUserError means the simulated user failed.
+	TerminationUserError Termination = "user_error"
+	// TerminationCanceled means the context was canceled.
+	TerminationCanceled Termination = "canceled"
+)
+
+// Persona describes the simulated user.
+type Persona struct {
+	// Name of the persona, e.g. "impatient customer", recorded in the metadata of the transcript.
+	Name string
+	// Description is the background and the behavior of the user.
+	Description string
+	// Goal is what the user wants to achieve in the conversation.
+	Goal string
+}
+
+// UserConfig is the config of NewSimulatedUser.
+type UserConfig struct {
+	// Model plays the user, required.
+	Model model.BaseChatModel
+	// Persona is the user played by the model, required.
+	Persona *Persona
+	// StopToken is the token the user replies with to end the conversation, DefaultStopToken by default.
+	StopToken string
+	// Instruction overrides the system prompt built from the persona, which is formatted in FString with
+	// the variables "description", "goal" and "stop_token".
+	Instruction string
+}
+
+const defaultUserInstruction = `You are role-playing a user talking to an AI assistant. Stay in character and never reveal you are simulated.
+Who you are: {description}
+Your goal: {goal}
+Write only the next message of the user, short and natural.
+When your goal is achieved, or the conversation can't make progress any more, reply with only {stop_token}.`
+
+// NewSimulatedUser creates a runnable playing the persona by the chat model.
+// It takes the conversation so far, in which the user messages are its own, and returns the next user message.
+func NewSimulatedUser(ctx context.Context, config *UserConfig) (compose.Runnable[[]*schema.Message, *schema.Message], error) {
+	if config == nil || config.Model == nil {
+		return nil, errors.New("model of simulated user is required")
+	}
+	if config.Persona == nil {
+		return nil, errors.New("persona of simulated user is required")
+	}
+	stopToken := config.StopToken
+	if stopToken == "" {
+		stopToken = DefaultStopToken
+	}
+	instruction := config.Instruction
+	if instruction == "" {
+		instruction = defaultUserInstruction
+	}
+	system, err := schema.SystemMessage(instruction).Format(ctx, map[string]any{
+		"description": config.Persona.Description,
+		"goal":        config.Persona.Goal,
+		"stop_token":  stopToken,
+	}, schema.FString)
+	if err != nil {
+		return nil, fmt.Errorf("format instruction of simulated user fail: %w", err)
+	}
+
+	// the model plays the user, so the roles are swapped from its point of view
+	swapRoles := func(ctx context.Context, history []*schema.Message) ([]*schema.Message, error) {
+		msgs := make([]*schema.Message, 0, len(history)+2)
+		msgs = append(msgs, system...)
+		for _, m := range history {
+			switch m.Role {
+			case schema.User:
+				msgs = append(msgs, schema.AssistantMessage(m.Content, nil))
+			case schema.Assistant:
+				msgs = append(msgs, schema.UserMessage(m.Content))
+			}
+		}
+		if len(history) == 0 {
+			msgs = append(msgs, schema.UserMessage("(the conversation starts, write your first message)"))
+		}
+		return msgs, nil
+	}
+	asUser := func(ctx context.Context, m *schema.Message) (*schema.Message, error) {
+		return schema.UserMessage(strings.TrimSpace(m.Content)), nil
+	}
+
+	return compose.NewChain[[]*schema.Message, *schema.Message]().
+		AppendLambda(compose.InvokableLambda(swapRoles)).
+		AppendChatModel(config.Model).
+		AppendLambda(compose.InvokableLambda(asUser)).
+		Compile(ctx, compose.WithGraphName("SimulatedUser"))
+}
+
+// Config is the config of a simulation.
+type Config struct {
+	// Agent is the agent under test, taking the conversation so far and returning its reply, required.
+	Agent compose.Runnable[[]*schema.Message, *schema.Message]
+	// User is the simulated user, taking the conversation so far and returning the next user message, required.
+	// See NewSimulatedUser.
+	User compose.Runnable[[]*schema.Message, *schema.Message]
+	// Persona is recorded in the metadata of the transcript, optional.
+	Persona *Persona
+	// MaxTurns is the max number of turns, each of which is a user message and the reply of the agent, DefaultMaxTurns by default.
+	MaxTurns int
+	// FirstMessage opens the conversation instead of the simulated user, optional.
+	FirstMessage string
+	// StopToken ends the conversation when the user message contains it, DefaultStopToken by default.
+	StopToken string
+	// StopCondition is checked after each turn, and ends the conversation when it returns true, optional.
+	StopCondition func(ctx context.Context, messages []*schema.Message) bool
+	// AgentOptions are passed to every call of the agent.
+	AgentOptions []compose.Option
+	// UserOptions are passed to every call of the simulated user.
+	UserOptions []compose.Option
+}
+
+// Result is the result of a simulation.
+type Result struct {
+	// Transcript records the conversation, with the messages of the user in the user role and the replies of the agent in the assistant role.
+	Transcript *schema.Transcript
+	// Turns is the number of completed turns.
+	Turns int
+	// Termination is the reason why the simulation stops.
+	Termination Termination
+	// Err is the error of the agent or the simulated user, nil unless the termination is an error.
+	Err error
+}
+
+// Run runs the conversation between the agent and the simulated user until it terminates.
+// The failures of the agent or the user are recorded in the result, only an invalid config returns an error.
+func Run(ctx context.Context, config *Config) (*Result, error) {
+	if config == nil || config.Agent == nil || config.User == nil {
+		return nil, errors.New("agent and simulated user are required")
+	}
+	maxTurns := config.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = DefaultMaxTurns
+	}
+	stopToken := config.StopToken
+	if stopToken == "" {
+		stopToken = DefaultStopToken
+	}
+
+	res := &Result{Transcript: &schema.Transcript{CreatedAt: time.Now()}}
+	if p := config.Persona; p != nil {
+		res.Transcript.Metadata = map[string]any{"persona": p.Name, "goal": p.Goal}
+	}
+
+	var history []*schema.Message
+	defer func() {
+		res.Transcript.Messages = history
+	}()
+
+	for res.Turns < maxTurns {
+		if err := ctx.Err(); err != nil {
+			res.Termination, res.Err = TerminationCanceled, err
+			return res, nil
+		}
+
+		var userMsg *schema.Message
+		if res.Turns == 0 && config.FirstMessage != "" {
+			userMsg = schema.UserMessage(config.FirstMessage)
+		} else {
+			msg, err := config.User.Invoke(ctx, history, config.UserOptions...)
+			if err != nil {
+				res.Termination, res.Err = TerminationUserError, err
+				return res, nil
+			}
+			if msg == nil {
+				res.Termination, res.Err = TerminationUserError, errors.New("simulated user returned nil message")
+				return res, nil
+			}
+			if strings.Contains(msg.Content, stopToken) {
+				res.Termination = TerminationUserStopped
+				return res, nil
+			}
+			userMsg = msg
+			if userMsg.Role != schema.User {
+				userMsg = schema.UserMessage(msg.Content)
+			}
+		}
+		history = append(history, userMsg)
+
+		reply, err := config.Agent.Invoke(ctx, history, config.AgentOptions...)
+		if err != nil {
+			res.Termination, res.Err = TerminationAgentError, err
+			return res, nil
+		}
+		history = append(history, reply)
+		res.Turns++
+
+		if config.StopCondition != nil && config.StopCondition(ctx, history) {
+			res.Termination = TerminationStopCondition
+			return res, nil
+		}
+	}
+
+	res.Termination = TerminationMaxTurns
+	return res, nil
+}
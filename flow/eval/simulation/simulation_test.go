@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type scriptedModel struct {
+	replies []string
+	inputs  [][]*schema.Message
+}
+
+func (m *scriptedModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.inputs = append(m.inputs, input)
+	reply := m.replies[0]
+	m.replies = m.replies[1:]
+	return schema.AssistantMessage(reply, nil), nil
+}
+
+func (m *scriptedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func newAgent(t *testing.T, fail int) compose.Runnable[[]*schema.Message, *schema.Message] {
+	r, err := compose.NewChain[[]*schema.Message, *schema.Message]().
+		AppendLambda(compose.InvokableLambda(func(ctx context.Context, history []*schema.Message) (*schema.Message, error) {
+			turn := (len(history) + 1) / 2
+			if turn == fail {
+				return nil, errors.New("agent fails")
+			}
+			return schema.AssistantMessage(fmt.Sprintf("reply %d to %s", turn, history[len(history)-1].Content), nil), nil
+		})).
+		Compile(context.Background())
+	assert.NoError(t, err)
+	return r
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	persona := &Persona{Name: "lost password", Description: "a customer who lost the password", Goal: "reset the password"}
+
+	t.Run("user stopped", func(t *testing.T) {
+		m := &scriptedModel{replies: []string{" I lost my password ", "thanks", "[END]"}}
+		user, err := NewSimulatedUser(ctx, &UserConfig{Model: m, Persona: persona})
+		assert.NoError(t, err)
+
+		res, err := Run(ctx, &Config{Agent: newAgent(t, 0), User: user, Persona: persona})
+		assert.NoError(t, err)
+		assert.Equal(t, TerminationUserStopped, res.Termination)
+		assert.Equal(t, 2, res.Turns)
+		assert.NoError(t, res.Err)
+		assert.Equal(t, "lost password", res.Transcript.Metadata["persona"])
+
+		msgs := res.Transcript.Messages
+		assert.Len(t, msgs, 4)
+		assert.Equal(t, schema.User, msgs[0].Role)
+		assert.Equal(t, "I lost my password", msgs[0].Content)
+		assert.Equal(t, schema.Assistant, msgs[1].Role)
+		assert.Equal(t, "reply 1 to I lost my password", msgs[1].Content)
+
+		// the roles are swapped for the model playing the user
+		assert.Contains(t, m.inputs[0][0].Content, "reset the password")
+		assert.Contains(t, m.inputs[0][0].Content, DefaultStopToken)
+		last := m.inputs[2]
+		assert.Equal(t, schema.Assistant, last[1].Role)
+		assert.Equal(t, "I lost my password", last[1].Content)
+		assert.Equal(t, schema.User, last[2].Role)
+		assert.True(t, strings.HasPrefix(last[2].Content, "reply 1"))
+	})
+
+	t.Run("max turns", func(t *testing.T) {
+		m := &scriptedModel{replies: []string{"more", "more", "more"}}
+		user, err := NewSimulatedUser(ctx, &UserConfig{Model: m, Persona: persona})
+		assert.NoError(t, err)
+
+		res, err := Run(ctx, &Config{Agent: newAgent(t, 0), User: user, MaxTurns: 3, FirstMessage: "hi"})
+		assert.NoError(t, err)
+		assert.Equal(t, TerminationMaxTurns, res.Termination)
+		assert.Equal(t, 3, res.Turns)
+		assert.Equal(t, "hi", res.Transcript.Messages[0].Content)
+		assert.Len(t, m.inputs, 2)
+	})
+
+	t.Run("stop condition and errors", func(t *testing.T) {
+		m := &scriptedModel{replies: []string{"a", "b", "c"}}
+		user, err := NewSimulatedUser(ctx, &UserConfig{Model: m, Persona: persona})
+		assert.NoError(t, err)
+		res, err := Run(ctx, &Config{Agent: newAgent(t, 0), User: user, StopCondition: func(ctx context.Context, messages []*schema.Message) bool {
+			return strings.Contains(messages[len(messages)-1].Content, "to b")
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, TerminationStopCondition, res.Termination)
+		assert.Equal(t, 2, res.Turns)
+
+		m = &scriptedModel{replies: []string{"a", "b", "c"}}
+		user, err = NewSimulatedUser(ctx, &UserConfig{Model: m, Persona: persona})
+		assert.NoError(t, err)
+		res, err = Run(ctx, &Config{Agent: newAgent(t, 2), User: user})
+		assert.NoError(t, err)
+		assert.Equal(t, TerminationAgentError, res.Termination)
+		assert.ErrorContains(t, res.Err, "agent fails")
+		assert.Len(t, res.Transcript.Messages, 3)
+
+		_, err = Run(ctx, &Config{User: user})
+		assert.Error(t, err)
+		_, err = NewSimulatedUser(ctx, &UserConfig{Model: m})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package incremental provides a helper syncing a document source to an indexer incrementally,
+// only the added and changed documents are stored (and thus embedded) again.
+package incremental
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// MetaKeyContentHash is the metadata key storing the content hash of an indexed document.
+const MetaKeyContentHash = "_content_hash"
+
+// Config is the config of the Syncer.
+type Config struct {
+	// Indexer stores the documents, required.
+	// It must support indexer.WithUpsert, and indexer.WithDeleteIDs if DeleteMissing is set.
+	Indexer indexer.Indexer
+	// ListIndexed lists the indexed documents of the source, by the map from document ID to the content hash
+	// stored in the metadata by MetaKeyContentHash, required.
+	ListIndexed func(ctx context.Context) (map[string]string, error)
+	// HashFunc computes the content hash of a document. Optional. By default, the sha256 of the content.
+	HashFunc func(doc *schema.Document) string
+	// DeleteMissing deletes the indexed documents which are no longer in the source. Optional.
+	DeleteMissing bool
+}
+
+// Result is the result of a sync, by document IDs.
+type Result struct {
+	Added     []string
+	Updated   []string
+	Unchanged []string
+	Deleted   []string
+}
+
+// Syncer syncs a document source to an indexer incrementally.
+// e.g.
+//
+//	syncer, err := incremental.NewSyncer(ctx, &incremental.Config{
+//		Indexer: milvusIndexer,
+//		ListIndexed: func(ctx context.Context) (map[string]string, error) {
+//			return listContentHashes(ctx, "wiki") // read the _content_hash of the documents indexed from wiki
+//		},
+//		DeleteMissing: true,
+//	})
+//	result, err := syncer.Sync(ctx, wikiDocs)
+type Syncer struct {
+	indexer       indexer.Indexer
+	listIndexed   func(ctx context.Context) (map[string]string, error)
+	hash          func(doc *schema.Document) string
+	deleteMissing bool
+}
+
+// NewSyncer creates a Syncer.
+func NewSyncer(ctx context.Context, config *Config) (*Syncer, error) {
+	if config == nil || config.Indexer == nil {
+		return nil, errors.New("incremental syncer indexer is nil")
+	}
+	if config.ListIndexed == nil {
+		return nil, errors.New("incremental syncer ListIndexed is nil")
+	}
+
+	hash := config.HashFunc
+	if hash == nil {
+		hash = ContentHash
+	}
+
+	return &Syncer{
+		indexer:       config.Indexer,
+		listIndexed:   config.ListIndexed,
+		hash:          hash,
+		deleteMissing: config.DeleteMissing,
+	}, nil
+}
+
+// Sync stores the documents which are added or changed since the last sync, with their content hashes in the metadata,
+// and deletes the indexed documents missing in docs if DeleteMissing is set.
+// docs are the whole source, each with a unique ID. opts are passed to the indexer.
+func (s *Syncer) Sync(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (*Result, error) {
+	indexed, err := s.listIndexed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexed documents fail: %w", err)
+	}
+
+	var (
+		result = &Result{}
+		seen   = make(map[string]bool, len(docs))
+		toSave []*schema.Document
+	)
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if len(doc.ID) == 0 {
+			return nil, errors.New("incremental sync document has no id")
+		}
+		if seen[doc.ID] {
+			return nil, fmt.Errorf("incremental sync document id is duplicated: %s", doc.ID)
+		}
+		seen[doc.ID] = true
+
+		hash := s.hash(doc)
+		oldHash, ok := indexed[doc.ID]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, doc.ID)
+		case oldHash != hash:
+			result.Updated = append(result.Updated, doc.ID)
+		default:
+			result.Unchanged = append(result.Unchanged, doc.ID)
+			continue
+		}
+
+		meta := make(map[string]any, len(doc.MetaData)+1)
+		for k, v := range doc.MetaData {
+			meta[k] = v
+		}
+		meta[MetaKeyContentHash] = hash
+		toSave = append(toSave, &schema.Document{ID: doc.ID, Content: doc.Content, MetaData: meta})
+	}
+
+	if s.deleteMissing {
+		for id := range indexed {
+			if !seen[id] {
+				result.Deleted = append(result.Deleted, id)
+			}
+		}
+		sort.Strings(result.Deleted)
+	}
+
+	if len(toSave) == 0 && len(result.Deleted) == 0 {
+		return result, nil
+	}
+
+	opts = append(opts, indexer.WithUpsert())
+	if len(result.Deleted) > 0 {
+		opts = append(opts, indexer.WithDeleteIDs(result.Deleted...))
+	}
+	if _, err = s.indexer.Store(ctx, toSave, opts...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ContentHash returns the hex encoded sha256 of the document content.
+func ContentHash(doc *schema.Document) string {
+	sum := sha256.Sum256([]byte(doc.Content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// memIndexer stores the documents in memory, supporting upsert and deletion by IDs.
+type memIndexer struct {
+	docs   map[string]*schema.Document
+	stored []string
+}
+
+func (m *memIndexer) Store(_ context.Context, docs []*schema.Document, opts ...indexer.Option) ([]string, error) {
+	o := indexer.GetCommonOptions(nil, opts...)
+	for _, id := range o.DeleteIDs {
+		delete(m.docs, id)
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		m.docs[doc.ID] = doc
+		m.stored = append(m.stored, doc.ID)
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+func (m *memIndexer) listIndexed(context.Context) (map[string]string, error) {
+	ret := make(map[string]string, len(m.docs))
+	for id, doc := range m.docs {
+		ret[id], _ = doc.MetaData[MetaKeyContentHash].(string)
+	}
+	return ret, nil
+}
+
+func TestSyncer(t *testing.T) {
+	ctx := context.Background()
+	idx := &memIndexer{docs: map[string]*schema.Document{}}
+
+	s, err := NewSyncer(ctx, &Config{Indexer: idx, ListIndexed: idx.listIndexed, DeleteMissing: true})
+	assert.NoError(t, err)
+
+	src := []*schema.Document{
+		{ID: "1", Content: "one"},
+		{ID: "2", Content: "two", MetaData: map[string]any{"source": "wiki"}},
+	}
+	result, err := s.Sync(ctx, src)
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Added: []string{"1", "2"}}, result)
+	assert.Equal(t, ContentHash(src[1]), idx.docs["2"].MetaData[MetaKeyContentHash])
+	assert.Equal(t, "wiki", idx.docs["2"].MetaData["source"])
+	assert.Nil(t, src[0].MetaData)
+
+	// nothing changed, nothing stored
+	idx.stored = nil
+	result, err = s.Sync(ctx, src)
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Unchanged: []string{"1", "2"}}, result)
+	assert.Empty(t, idx.stored)
+
+	src = []*schema.Document{
+		{ID: "2", Content: "two v2"},
+		{ID: "3", Content: "three"},
+	}
+	result, err = s.Sync(ctx, src)
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Added: []string{"3"}, Updated: []string{"2"}, Deleted: []string{"1"}}, result)
+	assert.Equal(t, []string{"2", "3"}, idx.stored)
+	assert.Len(t, idx.docs, 2)
+	assert.Equal(t, "two v2", idx.docs["2"].Content)
+
+	_, err = s.Sync(ctx, []*schema.Document{{ID: "1"}, {ID: "1"}})
+	assert.Error(t, err)
+	_, err = s.Sync(ctx, []*schema.Document{{Content: "no id"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package memory manages the chat history of agents, e.g. summarizing the older turns to fit the history in a token budget.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+// ExtraKeySummary marks the summary message generated by Summarizer in the Extra of the message,
+// so that it's summarized again together with the older turns, instead of being pinned as a system prompt.
+const ExtraKeySummary = "_eino_history_summary"
+
+const defaultSummaryInstruction = `Summarize the conversation below between a user and an AI assistant, ` +
+	`so that the assistant can continue the conversation with the summary in place of it. ` +
+	`Keep the user's goals, constraints and preferences, the decisions made, the important facts and tool results, and the open questions. ` +
+	`Reply with the summary only.`
+
+const defaultSummaryPrefix = "Summary of the earlier conversation:\n"
+
+type summarizerOptions struct {
+	tokenizer    tokenizer.Tokenizer
+	recentBudget int
+	instruction  string
+	prefix       string
+}
+
+// SummarizerOption is the option of Summarizer.
+type SummarizerOption func(*summarizerOptions)
+
+// WithTokenizer sets the tokenizer counting the tokens of the messages. By default, tokenizer.Approximate.
+func WithTokenizer(t tokenizer.Tokenizer) SummarizerOption {
+	return func(o *summarizerOptions) {
+		o.tokenizer = t
+	}
+}
+
+// WithRecentBudget sets the token budget of the recent turns kept as is. By default, half of the budget.
+// The last turn is always kept, even if it exceeds the recent budget.
+func WithRecentBudget(tokens int) SummarizerOption {
+	return func(o *summarizerOptions) {
+		o.recentBudget = tokens
+	}
+}
+
+// WithSummaryInstruction replaces the system prompt instructing the model to summarize the conversation,
+// which is given to the model followed by a user message of the transcript of the older turns.
+func WithSummaryInstruction(instruction string) SummarizerOption {
+	return func(o *summarizerOptions) {
+		o.instruction = instruction
+	}
+}
+
+// WithSummaryPrefix replaces the text prepended to the summary in the summary message.
+func WithSummaryPrefix(prefix string) SummarizerOption {
+	return func(o *summarizerOptions) {
+		o.prefix = prefix
+	}
+}
+
+// Summarizer replaces the older turns of the chat history with a summary generated by the model,
+// when the history exceeds the token budget.
+// The leading system messages, i.e. the system prompt, and the recent turns are pinned,
+// and the summary message is a system message placed after the system prompt, marked by ExtraKeySummary.
+// The history is cut at a message other than the tool message, so that the tool calls are kept together with their results.
+type Summarizer struct {
+	model  model.BaseChatModel
+	budget int
+	opts   summarizerOptions
+}
+
+// NewSummarizer creates a Summarizer keeping the history within budget tokens.
+func NewSummarizer(m model.BaseChatModel, budget int, opts ...SummarizerOption) (*Summarizer, error) {
+	if m == nil {
+		return nil, errors.New("summarizer model is required")
+	}
+	if budget < 1 {
+		return nil, fmt.Errorf("summarizer budget must be at least 1, got %d", budget)
+	}
+
+	o := summarizerOptions{
+		tokenizer:    tokenizer.Approximate,
+		recentBudget: budget / 2,
+		instruction:  defaultSummaryInstruction,
+		prefix:       defaultSummaryPrefix,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.tokenizer == nil {
+		o.tokenizer = tokenizer.Approximate
+	}
+
+	return &Summarizer{model: m, budget: budget, opts: o}, nil
+}
+
+// SummarizerNode creates a lambda node of Summarizer, which takes and returns the chat history, e.g.
+//
+//	node, err := memory.SummarizerNode(cm, 8000)
+//	err = graph.AddLambdaNode("summarize", node)
+func SummarizerNode(m model.BaseChatModel, budget int, opts ...SummarizerOption) (*compose.Lambda, error) {
+	s, err := NewSummarizer(m, budget, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return compose.InvokableLambda(s.Summarize), nil
+}
+
+// Summarize returns the messages as is if they are within the budget,
+// otherwise the messages with the older turns replaced by a summary message.
+// The messages are returned as is as well if there are no older turns to summarize,
+// i.e. the history consists of the system prompt and the recent turns only.
+func (s *Summarizer) Summarize(ctx context.Context, msgs []*schema.Message) ([]*schema.Message, error) {
+	total, err := tokenizer.CountMessageTokens(ctx, s.opts.tokenizer, msgs)
+	if err != nil {
+		return nil, fmt.Errorf("count history tokens fail: %w", err)
+	}
+	if total <= s.budget {
+		return msgs, nil
+	}
+
+	pinned := 0
+	for pinned < len(msgs) && msgs[pinned].Role == schema.System && !IsSummary(msgs[pinned]) {
+		pinned++
+	}
+
+	cut, err := s.recentStart(ctx, msgs[pinned:])
+	if err != nil {
+		return nil, err
+	}
+	if cut == 0 {
+		return msgs, nil
+	}
+	older, recent := msgs[pinned:pinned+cut], msgs[pinned+cut:]
+
+	summary, err := s.model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(s.opts.instruction),
+		schema.UserMessage(transcript(older)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate history summary fail: %w", err)
+	}
+
+	summaryMsg := schema.SystemMessage(s.opts.prefix + summary.Content)
+	summaryMsg.Extra = map[string]any{ExtraKeySummary: true}
+
+	ret := make([]*schema.Message, 0, pinned+1+len(recent))
+	ret = append(ret, msgs[:pinned]...)
+	ret = append(ret, summaryMsg)
+	ret = append(ret, recent...)
+	return ret, nil
+}
+
+// recentStart returns the index where the recent turns start, i.e. the earliest cut point which keeps the suffix within the recent budget,
+// or the latest cut point if none does.
+func (s *Summarizer) recentStart(ctx context.Context, msgs []*schema.Message) (int, error) {
+	latest := -1
+	tokens := 0
+	for i := len(msgs) - 1; i >= 0; i-- {
+		n, err := tokenizer.CountMessageTokens(ctx, s.opts.tokenizer, msgs[i:i+1])
+		if err != nil {
+			return 0, fmt.Errorf("count history tokens fail: %w", err)
+		}
+		tokens += n
+		if msgs[i].Role == schema.Tool {
+			continue
+		}
+		if tokens > s.opts.recentBudget {
+			if latest < 0 {
+				return i, nil
+			}
+			break
+		}
+		latest = i
+	}
+	if latest < 0 {
+		return 0, nil
+	}
+	return latest, nil
+}
+
+// IsSummary reports whether the message is a summary generated by Summarizer.
+func IsSummary(msg *schema.Message) bool {
+	if msg == nil || msg.Extra == nil {
+		return false
+	}
+	v, _ := msg.Extra[ExtraKeySummary].(bool)
+	return v
+}
+
+func transcript(msgs []*schema.Message) string {
+	sb := strings.Builder{}
+	for _, msg := range msgs {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		switch {
+		case IsSummary(msg):
+			sb.WriteString("[earlier summary]: ")
+		case msg.Role == schema.Tool && msg.ToolName != "":
+			sb.WriteString(fmt.Sprintf("[tool %s]: ", msg.ToolName))
+		default:
+			sb.WriteString(fmt.Sprintf("[%s]: ", msg.Role))
+		}
+		sb.WriteString(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			sb.WriteString(fmt.Sprintf("\n(calls tool %s with %s)", tc.Function.Name, tc.Function.Arguments))
+		}
+	}
+	return sb.String()
+}
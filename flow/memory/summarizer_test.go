@@ -0,0 +1,154 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+type summaryModel struct {
+	inputs [][]*schema.Message
+	err    error
+}
+
+func (m *summaryModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage("the summary", nil), nil
+}
+
+func (m *summaryModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+// wordTokenizer counts a word as a token.
+var wordTokenizer = tokenizer.Func(func(_ context.Context, text string) (int, error) {
+	return len(strings.Fields(text)), nil
+})
+
+func TestSummarizer(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewSummarizer(nil, 10)
+	assert.Error(t, err)
+	_, err = NewSummarizer(&summaryModel{}, 0)
+	assert.Error(t, err)
+
+	history := []*schema.Message{
+		schema.SystemMessage("be helpful"),
+		schema.UserMessage("one two three four"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: "{}"}}}),
+		schema.ToolMessage("five six seven eight", "1", schema.WithToolName("search")),
+		schema.AssistantMessage("nine ten", nil),
+		schema.UserMessage("eleven twelve"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "search", Arguments: "{}"}}}),
+		schema.ToolMessage("thirteen fourteen", "2", schema.WithToolName("search")),
+	}
+
+	t.Run("within budget", func(t *testing.T) {
+		m := &summaryModel{}
+		s, err := NewSummarizer(m, 1000, WithTokenizer(wordTokenizer))
+		require.NoError(t, err)
+		out, err := s.Summarize(ctx, history)
+		require.NoError(t, err)
+		assert.Equal(t, history, out)
+		assert.Empty(t, m.inputs)
+	})
+
+	t.Run("summarize", func(t *testing.T) {
+		m := &summaryModel{}
+		// each message takes its words plus 4 tokens of overhead, the last two messages take 14 tokens
+		s, err := NewSummarizer(m, 40, WithTokenizer(wordTokenizer), WithRecentBudget(14))
+		require.NoError(t, err)
+		out, err := s.Summarize(ctx, history)
+		require.NoError(t, err)
+
+		// the tool message is kept together with its tool call
+		require.Len(t, out, 4)
+		assert.Equal(t, history[0], out[0])
+		assert.True(t, IsSummary(out[1]))
+		assert.Equal(t, schema.System, out[1].Role)
+		assert.Equal(t, defaultSummaryPrefix+"the summary", out[1].Content)
+		assert.Equal(t, history[6:], out[2:])
+
+		require.Len(t, m.inputs, 1)
+		prompt := m.inputs[0][1].Content
+		assert.Contains(t, prompt, "[user]: one two three four")
+		assert.Contains(t, prompt, "(calls tool search with {})")
+		assert.Contains(t, prompt, "[tool search]: five six seven eight")
+		assert.Contains(t, prompt, "[user]: eleven twelve")
+		assert.NotContains(t, prompt, "be helpful")
+
+		// the summary is summarized again, instead of being pinned
+		out = append(out, schema.AssistantMessage("fifteen sixteen seventeen eighteen nineteen twenty", nil),
+			schema.UserMessage("a b c d e f g h i j k l m n o p q r s t u v w x y z"))
+		out, err = s.Summarize(ctx, out)
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+		assert.True(t, IsSummary(out[1]))
+		assert.Contains(t, m.inputs[1][1].Content, "[earlier summary]: "+defaultSummaryPrefix+"the summary")
+	})
+
+	t.Run("nothing to summarize", func(t *testing.T) {
+		m := &summaryModel{}
+		s, err := NewSummarizer(m, 10, WithTokenizer(wordTokenizer))
+		require.NoError(t, err)
+		msgs := []*schema.Message{schema.SystemMessage("be helpful"), schema.UserMessage("a b c d e f g h i j")}
+		out, err := s.Summarize(ctx, msgs)
+		require.NoError(t, err)
+		assert.Equal(t, msgs, out)
+		assert.Empty(t, m.inputs)
+	})
+
+	t.Run("model error", func(t *testing.T) {
+		s, err := NewSummarizer(&summaryModel{err: errors.New("boom")}, 20, WithTokenizer(wordTokenizer))
+		require.NoError(t, err)
+		_, err = s.Summarize(ctx, history)
+		assert.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("node", func(t *testing.T) {
+		node, err := SummarizerNode(&summaryModel{}, 40, WithTokenizer(wordTokenizer), WithRecentBudget(14))
+		require.NoError(t, err)
+		g := compose.NewGraph[[]*schema.Message, []*schema.Message]()
+		require.NoError(t, g.AddLambdaNode("summarize", node))
+		require.NoError(t, g.AddEdge(compose.START, "summarize"))
+		require.NoError(t, g.AddEdge("summarize", compose.END))
+		r, err := g.Compile(ctx)
+		require.NoError(t, err)
+		out, err := r.Invoke(ctx, history)
+		require.NoError(t, err)
+		assert.Len(t, out, 4)
+	})
+}
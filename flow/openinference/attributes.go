@@ -0,0 +1,335 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openinference
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func (t *Tracer) inputAttrs(component components.Component, input callbacks.CallbackInput) map[string]any {
+	attrs := make(map[string]any)
+	switch component {
+	case components.ComponentOfChatModel:
+		if in := model.ConvCallbackInput(input); in != nil {
+			t.modelInputAttrs(attrs, in.Messages, in.Tools, in.Config)
+			return attrs
+		}
+	case components.ComponentOfTool:
+		if in := tool.ConvCallbackInput(input); in != nil {
+			t.valueAttrs(attrs, AttrInputValue, AttrInputMimeType, in.ArgumentsInJSON, mimeTypeJSON)
+			return attrs
+		}
+	case components.ComponentOfRetriever:
+		if in := retriever.ConvCallbackInput(input); in != nil {
+			t.valueAttrs(attrs, AttrInputValue, AttrInputMimeType, in.Query, mimeTypeText)
+			return attrs
+		}
+	case components.ComponentOfEmbedding:
+		if in := embedding.ConvCallbackInput(input); in != nil {
+			if in.Config != nil && len(in.Config.Model) > 0 {
+				attrs[AttrEmbeddingModelName] = in.Config.Model
+			}
+			if !t.noContent {
+				for i, text := range in.Texts {
+					attrs[fmt.Sprintf("%s.%d.%s", AttrEmbeddingEmbeddings, i, AttrEmbeddingText)] = text
+				}
+			}
+			return attrs
+		}
+	}
+	t.anyAttrs(attrs, AttrInputValue, AttrInputMimeType, input)
+	return attrs
+}
+
+func (t *Tracer) streamInputAttrs(component components.Component, chunks []callbacks.CallbackInput) map[string]any {
+	if component == components.ComponentOfChatModel {
+		var (
+			messages []*schema.Message
+			tools    []*schema.ToolInfo
+			config   *model.Config
+		)
+		for _, c := range chunks {
+			if in := model.ConvCallbackInput(c); in != nil {
+				messages = append(messages, in.Messages...)
+				tools = append(tools, in.Tools...)
+				if in.Config != nil {
+					config = in.Config
+				}
+			}
+		}
+		attrs := make(map[string]any)
+		t.modelInputAttrs(attrs, messages, tools, config)
+		return attrs
+	}
+
+	attrs := make(map[string]any)
+	t.anyAttrs(attrs, AttrInputValue, AttrInputMimeType, chunks)
+	return attrs
+}
+
+func (t *Tracer) outputAttrs(component components.Component, output callbacks.CallbackOutput) map[string]any {
+	attrs := make(map[string]any)
+	switch component {
+	case components.ComponentOfChatModel:
+		if out := model.ConvCallbackOutput(output); out != nil {
+			t.modelOutputAttrs(attrs, out.Message, out.Config, out.TokenUsage)
+			return attrs
+		}
+	case components.ComponentOfTool:
+		if out := tool.ConvCallbackOutput(output); out != nil {
+			t.valueAttrs(attrs, AttrOutputValue, AttrOutputMimeType, out.Response, mimeTypeText)
+			return attrs
+		}
+	case components.ComponentOfRetriever:
+		if out := retriever.ConvCallbackOutput(output); out != nil {
+			t.documentAttrs(attrs, out.Docs)
+			return attrs
+		}
+	case components.ComponentOfEmbedding:
+		if out := embedding.ConvCallbackOutput(output); out != nil {
+			if out.Config != nil && len(out.Config.Model) > 0 {
+				attrs[AttrEmbeddingModelName] = out.Config.Model
+			}
+			if out.TokenUsage != nil {
+				attrs[AttrLLMTokenCountPrompt] = out.TokenUsage.PromptTokens
+				attrs[AttrLLMTokenCountTotal] = out.TokenUsage.TotalTokens
+			}
+			return attrs
+		}
+	}
+	t.anyAttrs(attrs, AttrOutputValue, AttrOutputMimeType, output)
+	return attrs
+}
+
+func (t *Tracer) streamOutputAttrs(component components.Component, chunks []callbacks.CallbackOutput) map[string]any {
+	attrs := make(map[string]any)
+	switch component {
+	case components.ComponentOfChatModel:
+		var (
+			messages []*schema.Message
+			config   *model.Config
+			usage    *model.TokenUsage
+		)
+		for _, c := range chunks {
+			out := model.ConvCallbackOutput(c)
+			if out == nil {
+				continue
+			}
+			if out.Message != nil {
+				messages = append(messages, out.Message)
+			}
+			if out.Config != nil {
+				config = out.Config
+			}
+			if out.TokenUsage != nil {
+				usage = out.TokenUsage
+			}
+		}
+		var msg *schema.Message
+		if len(messages) > 0 {
+			var err error
+			if msg, err = schema.ConcatMessages(messages); err != nil {
+				msg = nil
+			}
+		}
+		t.modelOutputAttrs(attrs, msg, config, usage)
+		return attrs
+	case components.ComponentOfTool:
+		var sb strings.Builder
+		for _, c := range chunks {
+			if out := tool.ConvCallbackOutput(c); out != nil {
+				sb.WriteString(out.Response)
+			}
+		}
+		t.valueAttrs(attrs, AttrOutputValue, AttrOutputMimeType, sb.String(), mimeTypeText)
+		return attrs
+	}
+	t.anyAttrs(attrs, AttrOutputValue, AttrOutputMimeType, chunks)
+	return attrs
+}
+
+func (t *Tracer) modelInputAttrs(attrs map[string]any, messages []*schema.Message, tools []*schema.ToolInfo, config *model.Config) {
+	if config != nil {
+		if len(config.Model) > 0 {
+			attrs[AttrLLMModelName] = config.Model
+		}
+		attrs[AttrLLMInvocationParameters] = invocationParameters(config)
+	}
+	if t.noContent {
+		return
+	}
+
+	t.anyAttrs(attrs, AttrInputValue, AttrInputMimeType, messages)
+	for i, m := range messages {
+		messageAttrs(attrs, fmt.Sprintf("%s.%d.", AttrLLMInputMessages, i), m)
+	}
+	for i, ti := range tools {
+		attrs[fmt.Sprintf("%s.%d.%s", AttrLLMTools, i, AttrToolJSONSchema)] = toolSchema(ti)
+	}
+}
+
+func (t *Tracer) modelOutputAttrs(attrs map[string]any, msg *schema.Message, config *model.Config, usage *model.TokenUsage) {
+	if config != nil && len(config.Model) > 0 {
+		attrs[AttrLLMModelName] = config.Model
+	}
+	if msg != nil && msg.ResponseMeta != nil {
+		if len(msg.ResponseMeta.Model) > 0 {
+			attrs[AttrLLMModelName] = msg.ResponseMeta.Model
+		}
+		if usage == nil && msg.ResponseMeta.Usage != nil {
+			u := msg.ResponseMeta.Usage
+			usage = &model.TokenUsage{
+				PromptTokens:       u.PromptTokens,
+				PromptTokenDetails: model.PromptTokenDetails{CachedTokens: u.PromptTokenDetails.CachedTokens},
+				CompletionTokens:   u.CompletionTokens,
+				TotalTokens:        u.TotalTokens,
+			}
+		}
+	}
+	if usage != nil {
+		attrs[AttrLLMTokenCountPrompt] = usage.PromptTokens
+		attrs[AttrLLMTokenCountCompletion] = usage.CompletionTokens
+		attrs[AttrLLMTokenCountTotal] = usage.TotalTokens
+		if usage.PromptTokenDetails.CachedTokens > 0 {
+			attrs[AttrLLMTokenCountPromptCacheRead] = usage.PromptTokenDetails.CachedTokens
+		}
+	}
+	if t.noContent || msg == nil {
+		return
+	}
+
+	t.anyAttrs(attrs, AttrOutputValue, AttrOutputMimeType, msg)
+	messageAttrs(attrs, AttrLLMOutputMessages+".0.", msg)
+}
+
+func messageAttrs(attrs map[string]any, prefix string, m *schema.Message) {
+	if m == nil {
+		return
+	}
+	attrs[prefix+AttrMessageRole] = string(m.Role)
+	if len(m.Content) > 0 {
+		attrs[prefix+AttrMessageContent] = m.Content
+	}
+	if len(m.Name) > 0 {
+		attrs[prefix+AttrMessageName] = m.Name
+	}
+	if len(m.ToolCallID) > 0 {
+		attrs[prefix+AttrMessageToolCallID] = m.ToolCallID
+	}
+	for j, tc := range m.ToolCalls {
+		tcPrefix := fmt.Sprintf("%s%s.%d.", prefix, AttrMessageToolCalls, j)
+		if len(tc.ID) > 0 {
+			attrs[tcPrefix+AttrToolCallID] = tc.ID
+		}
+		attrs[tcPrefix+AttrToolCallName] = tc.Function.Name
+		attrs[tcPrefix+AttrToolCallArguments] = tc.Function.Arguments
+	}
+}
+
+func (t *Tracer) documentAttrs(attrs map[string]any, docs []*schema.Document) {
+	if t.noContent {
+		return
+	}
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		prefix := fmt.Sprintf("%s.%d.", AttrRetrievalDocuments, i)
+		if len(doc.ID) > 0 {
+			attrs[prefix+AttrDocumentID] = doc.ID
+		}
+		attrs[prefix+AttrDocumentContent] = doc.Content
+		attrs[prefix+AttrDocumentScore] = doc.Score()
+		if len(doc.MetaData) > 0 {
+			attrs[prefix+AttrDocumentMetadata] = toJSON(doc.MetaData)
+		}
+	}
+}
+
+func (t *Tracer) valueAttrs(attrs map[string]any, valueKey, mimeKey, value, mimeType string) {
+	if t.noContent {
+		return
+	}
+	attrs[valueKey] = value
+	attrs[mimeKey] = mimeType
+}
+
+func (t *Tracer) anyAttrs(attrs map[string]any, valueKey, mimeKey string, value any) {
+	if s, ok := value.(string); ok {
+		t.valueAttrs(attrs, valueKey, mimeKey, s, mimeTypeText)
+		return
+	}
+	if value == nil {
+		return
+	}
+	t.valueAttrs(attrs, valueKey, mimeKey, toJSON(value), mimeTypeJSON)
+}
+
+func invocationParameters(config *model.Config) string {
+	params := make(map[string]any)
+	if config.MaxTokens > 0 {
+		params["max_tokens"] = config.MaxTokens
+	}
+	if config.Temperature != 0 {
+		params["temperature"] = config.Temperature
+	}
+	if config.TopP != 0 {
+		params["top_p"] = config.TopP
+	}
+	if len(config.Stop) > 0 {
+		params["stop"] = config.Stop
+	}
+	return toJSON(params)
+}
+
+func toolSchema(info *schema.ToolInfo) string {
+	if info == nil {
+		return "{}"
+	}
+	function := map[string]any{
+		"name":        info.Name,
+		"description": info.Desc,
+	}
+	if info.ParamsOneOf != nil {
+		if params, err := info.ParamsOneOf.ToJSONSchema(); err == nil && params != nil {
+			function["parameters"] = params
+		}
+	}
+	return toJSON(map[string]any{
+		"type":     "function",
+		"function": function,
+	})
+}
+
+func toJSON(v any) string {
+	s, err := sonic.MarshalString(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return s
+}
@@ -0,0 +1,396 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openinference
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	defaultOTLPBatchSize    = 64
+	defaultOTLPMaxQueueSize = 2048
+)
+
+// OTLPConfig is the config of the OTLPExporter.
+type OTLPConfig struct {
+	// Endpoint is the OTLP/HTTP traces endpoint, required.
+	// e.g. http://localhost:6006/v1/traces for Phoenix, https://cloud.langfuse.com/api/public/otel/v1/traces for Langfuse.
+	Endpoint string
+	// Headers are set to every request, e.g. the Authorization header required by Langfuse.
+	Headers map[string]string
+	// ServiceName is reported as the service.name resource attribute, optional, "eino" by default.
+	ServiceName string
+	// BatchSize is the number of spans sent in one request, optional, 64 by default.
+	BatchSize int
+	// MaxQueueSize is the max number of spans buffered, optional, 2048 by default.
+	// The spans are kept in the buffer until they are sent, so the buffer grows while the endpoint is failing,
+	// and the spans exported to the full buffer are dropped, with Export returning an error.
+	MaxQueueSize int
+	// HTTPClient is optional, http.DefaultClient is used by default.
+	HTTPClient *http.Client
+}
+
+// NewOTLPExporter creates an OTLPExporter.
+func NewOTLPExporter(conf *OTLPConfig) (*OTLPExporter, error) {
+	if conf == nil || len(conf.Endpoint) == 0 {
+		return nil, errors.New("otlp exporter endpoint is empty")
+	}
+
+	e := &OTLPExporter{
+		endpoint:     conf.Endpoint,
+		headers:      conf.Headers,
+		serviceName:  conf.ServiceName,
+		batchSize:    conf.BatchSize,
+		maxQueueSize: conf.MaxQueueSize,
+		client:       conf.HTTPClient,
+		notify:       make(chan struct{}, 1),
+		flushes:      make(chan *otlpFlush),
+		stop:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	if len(e.serviceName) == 0 {
+		e.serviceName = "eino"
+	}
+	if e.batchSize <= 0 {
+		e.batchSize = defaultOTLPBatchSize
+	}
+	if e.maxQueueSize <= 0 {
+		e.maxQueueSize = defaultOTLPMaxQueueSize
+	}
+	if e.maxQueueSize < e.batchSize {
+		e.maxQueueSize = e.batchSize
+	}
+	if e.client == nil {
+		e.client = http.DefaultClient
+	}
+
+	go e.run()
+	return e, nil
+}
+
+// OTLPExporter sends the spans in batches to an OTLP/HTTP endpoint in the JSON encoding.
+// The spans are buffered and sent by a background goroutine once the batch is full, so Export never blocks the runs.
+// A batch failed to send is kept in the buffer and sent again along with the next batch or by Flush.
+// Call Flush after the runs to send the rest of the spans, and Shutdown to stop the exporter.
+type OTLPExporter struct {
+	endpoint     string
+	headers      map[string]string
+	serviceName  string
+	batchSize    int
+	maxQueueSize int
+	client       *http.Client
+
+	mu     sync.Mutex
+	spans  []*Span // only the background goroutine removes the spans, once they are sent
+	closed bool
+
+	notify   chan struct{}
+	flushes  chan *otlpFlush
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+type otlpFlush struct {
+	ctx  context.Context
+	done chan error
+}
+
+// Export implements Exporter.
+// The span is only buffered, the ctx of the run is not used to send it, which may be canceled once the run returns.
+func (e *OTLPExporter) Export(_ context.Context, span *Span) error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return errors.New("otlp exporter is shut down")
+	}
+	if len(e.spans) >= e.maxQueueSize {
+		e.mu.Unlock()
+		return fmt.Errorf("otlp exporter queue is full, span[%s] dropped", span.Name)
+	}
+	e.spans = append(e.spans, span)
+	full := len(e.spans) >= e.batchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.notify <- struct{}{}:
+		default: // already notified
+		}
+	}
+	return nil
+}
+
+// Flush sends all the buffered spans, and returns the error if any batch fails to send,
+// in which case the batch and the ones after it are kept in the buffer.
+func (e *OTLPExporter) Flush(ctx context.Context) error {
+	f := &otlpFlush{ctx: ctx, done: make(chan error, 1)}
+	select {
+	case e.flushes <- f:
+	case <-e.stopped:
+		return errors.New("otlp exporter is shut down")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-f.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting spans, flushes the buffered ones and stops the background goroutine.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+
+	var err error
+	select {
+	case <-e.stopped:
+	default:
+		err = e.Flush(ctx)
+	}
+	e.stopOnce.Do(func() { close(e.stop) })
+
+	select {
+	case <-e.stopped:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+	for {
+		select {
+		case <-e.notify:
+			// the spans failed to send are retried by the next notification or Flush
+			_ = e.sendBatches(context.Background(), false)
+		case f := <-e.flushes:
+			f.done <- e.sendBatches(f.ctx, true)
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// sendBatches sends the full batches in the buffer, and the last partial one as well if all.
+func (e *OTLPExporter) sendBatches(ctx context.Context, all bool) error {
+	for {
+		e.mu.Lock()
+		n := len(e.spans)
+		if n == 0 || (!all && n < e.batchSize) {
+			e.mu.Unlock()
+			return nil
+		}
+		if n > e.batchSize {
+			n = e.batchSize
+		}
+		batch := e.spans[:n:n]
+		e.mu.Unlock()
+
+		if err := e.send(ctx, batch); err != nil {
+			return err
+		}
+
+		e.mu.Lock()
+		e.spans = e.spans[n:]
+		e.mu.Unlock()
+	}
+}
+
+func (e *OTLPExporter) send(ctx context.Context, spans []*Span) error {
+	body, err := sonic.Marshal(toOTLPRequest(e.serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("marshal otlp request fail: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send otlp spans fail: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("send otlp spans fail, status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// the JSON encoding of OTLP ExportTraceServiceRequest, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type otlpRequest struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   *otlpResource     `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope *otlpScope  `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []*otlpKeyValue `json:"attributes"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string     `json:"key"`
+	Value *otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []*otlpValue `json:"values"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+func toOTLPRequest(serviceName string, spans []*Span) *otlpRequest {
+	otlpSpans := make([]*otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		out := &otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        toOTLPAttributes(s.Attributes),
+			Status:            &otlpStatus{Code: otlpStatusOK},
+		}
+		if len(s.Error) > 0 {
+			out.Status = &otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		otlpSpans = append(otlpSpans, out)
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []*otlpResourceSpans{{
+			Resource: &otlpResource{
+				Attributes: toOTLPAttributes(map[string]any{"service.name": serviceName}),
+			},
+			ScopeSpans: []*otlpScopeSpans{{
+				Scope: &otlpScope{Name: "github.com/cloudwego/eino/flow/openinference"},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func toOTLPAttributes(attrs map[string]any) []*otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]*otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, &otlpKeyValue{Key: k, Value: toOTLPValue(attrs[k])})
+	}
+	return kvs
+}
+
+func toOTLPValue(v any) *otlpValue {
+	switch t := v.(type) {
+	case string:
+		return &otlpValue{StringValue: &t}
+	case bool:
+		return &otlpValue{BoolValue: &t}
+	case int:
+		s := strconv.Itoa(t)
+		return &otlpValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(t, 10)
+		return &otlpValue{IntValue: &s}
+	case float64:
+		return &otlpValue{DoubleValue: &t}
+	case float32:
+		f := float64(t)
+		return &otlpValue{DoubleValue: &f}
+	case []string:
+		values := make([]*otlpValue, 0, len(t))
+		for i := range t {
+			values = append(values, &otlpValue{StringValue: &t[i]})
+		}
+		return &otlpValue{ArrayValue: &otlpArrayValue{Values: values}}
+	default:
+		s := toJSON(v)
+		return &otlpValue{StringValue: &s}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openinference
+
+// Span kinds of the OpenInference semantic conventions.
+const (
+	SpanKindLLM       = "LLM"
+	SpanKindChain     = "CHAIN"
+	SpanKindTool      = "TOOL"
+	SpanKindRetriever = "RETRIEVER"
+	SpanKindEmbedding = "EMBEDDING"
+	SpanKindAgent     = "AGENT"
+)
+
+// Attribute keys of the OpenInference semantic conventions, see https://github.com/Arize-ai/openinference.
+const (
+	AttrSpanKind = "openinference.span.kind"
+
+	AttrInputValue     = "input.value"
+	AttrInputMimeType  = "input.mime_type"
+	AttrOutputValue    = "output.value"
+	AttrOutputMimeType = "output.mime_type"
+
+	AttrSessionID = "session.id"
+	AttrUserID    = "user.id"
+	AttrMetadata  = "metadata"
+	AttrTagTags   = "tag.tags"
+
+	AttrLLMModelName            = "llm.model_name"
+	AttrLLMInvocationParameters = "llm.invocation_parameters"
+	AttrLLMInputMessages        = "llm.input_messages"
+	AttrLLMOutputMessages       = "llm.output_messages"
+	AttrLLMTools                = "llm.tools"
+
+	AttrLLMTokenCountPrompt          = "llm.token_count.prompt"
+	AttrLLMTokenCountCompletion      = "llm.token_count.completion"
+	AttrLLMTokenCountTotal           = "llm.token_count.total"
+	AttrLLMTokenCountPromptCacheRead = "llm.token_count.prompt_details.cache_read"
+
+	AttrMessageRole         = "message.role"
+	AttrMessageContent      = "message.content"
+	AttrMessageName         = "message.name"
+	AttrMessageToolCallID   = "message.tool_call_id"
+	AttrMessageToolCalls    = "message.tool_calls"
+	AttrToolCallID          = "tool_call.id"
+	AttrToolCallName        = "tool_call.function.name"
+	AttrToolCallArguments   = "tool_call.function.arguments"
+	AttrToolJSONSchema      = "tool.json_schema"
+	AttrToolName            = "tool.name"
+	AttrToolDescription     = "tool.description"
+	AttrToolParameters      = "tool.parameters"
+	AttrRetrievalDocuments  = "retrieval.documents"
+	AttrDocumentID          = "document.id"
+	AttrDocumentContent     = "document.content"
+	AttrDocumentScore       = "document.score"
+	AttrDocumentMetadata    = "document.metadata"
+	AttrEmbeddingModelName  = "embedding.model_name"
+	AttrEmbeddingEmbeddings = "embedding.embeddings"
+	AttrEmbeddingText       = "embedding.text"
+
+	// AttrEinoComponent and AttrEinoType are the component kind and implementation type reported by eino.
+	AttrEinoComponent = "eino.component"
+	AttrEinoType      = "eino.type"
+	AttrEinoNodeGroup = "eino.node_group"
+)
+
+const (
+	mimeTypeJSON = "application/json"
+	mimeTypeText = "text/plain"
+)
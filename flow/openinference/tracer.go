@@ -0,0 +1,320 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openinference provides a callbacks handler reporting the runs of graphs, nodes and components as spans
+// following the OpenInference semantic conventions, which are understood by the popular LLM observability backends,
+// e.g. Arize Phoenix and Langfuse. Chat models are reported as LLM spans with the messages, invocation parameters and token usage,
+// tools as TOOL spans, retrievers as RETRIEVER spans, and the others as CHAIN spans.
+//
+// The spans are handed to an Exporter, e.g. the OTLPExporter sending them to an OTLP/HTTP endpoint:
+//
+//	exporter, err := openinference.NewOTLPExporter(&openinference.OTLPConfig{
+//		Endpoint: "http://localhost:6006/v1/traces",
+//	})
+//	defer exporter.Shutdown(ctx)
+//	tracer := openinference.NewTracer(exporter)
+//	out, err := runnable.Invoke(ctx, input, compose.WithCallbacks(tracer.Handler()))
+package openinference
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Span is a finished span.
+type Span struct {
+	// TraceID is the 16 bytes trace id in hex, shared by all the spans of an outermost run.
+	TraceID string
+	// SpanID is the 8 bytes span id in hex.
+	SpanID string
+	// ParentSpanID is empty for the root span.
+	ParentSpanID string
+
+	Name      string
+	Kind      string
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Attributes are keyed by the OpenInference attribute keys, the values are string, bool, int, int64, float64 or []string.
+	Attributes map[string]any
+
+	// Error is the error message if the run failed.
+	Error string
+}
+
+// Exporter exports the finished spans.
+// Export is called when each span finishes, children before their parents, and may be called concurrently.
+type Exporter interface {
+	Export(ctx context.Context, span *Span) error
+}
+
+// TracerOption is the option for NewTracer.
+type TracerOption func(*Tracer)
+
+// WithSpanKind overrides the span kind decided by the component, e.g. to report a graph as an AGENT span.
+// An empty return value keeps the default kind.
+func WithSpanKind(fn func(info *callbacks.RunInfo) string) TracerOption {
+	return func(t *Tracer) {
+		t.spanKind = fn
+	}
+}
+
+// WithoutContent omits the inputs, outputs, messages and documents from the spans, keeping the names, kinds, timings,
+// model names and token usage only, for the cases where the contents are sensitive.
+func WithoutContent() TracerOption {
+	return func(t *Tracer) {
+		t.noContent = true
+	}
+}
+
+type attributesKey struct{}
+
+// WithAttributes adds the attributes to all the spans started within the returned context, e.g. AttrSessionID and AttrUserID,
+// which are used by the backends to group the traces.
+func WithAttributes(ctx context.Context, attrs map[string]any) context.Context {
+	merged := make(map[string]any)
+	if parent, ok := ctx.Value(attributesKey{}).(map[string]any); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, attributesKey{}, merged)
+}
+
+// NewTracer creates a Tracer exporting the spans to the exporter.
+func NewTracer(exporter Exporter, opts ...TracerOption) *Tracer {
+	t := &Tracer{exporter: exporter}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Tracer reports the runs as spans through callbacks.
+type Tracer struct {
+	exporter  Exporter
+	spanKind  func(info *callbacks.RunInfo) string
+	noContent bool
+
+	mu  sync.Mutex
+	err error
+}
+
+type spanKey struct{}
+
+type activeSpan struct {
+	span      *Span
+	component components.Component
+
+	mu sync.Mutex
+
+	streamingInput bool
+	// input is done when the streaming input is read.
+	input sync.WaitGroup
+}
+
+func (s *activeSpan) setAttrs(attrs map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range attrs {
+		s.span.Attributes[k] = v
+	}
+}
+
+func (s *activeSpan) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.span.Error = err.Error()
+}
+
+// Handler returns the callbacks handler reporting the spans, pass it with compose.WithCallbacks.
+func (t *Tracer) Handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			ctx, s := t.start(ctx, info)
+			s.setAttrs(t.inputAttrs(s.component, input))
+			return ctx
+		}).
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			ctx, s := t.start(ctx, info)
+			s.streamingInput = true
+			s.input.Add(1)
+			go func() {
+				defer s.input.Done()
+				chunks, _ := readAll(input)
+				s.setAttrs(t.streamInputAttrs(s.component, chunks))
+			}()
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if s, ok := ctx.Value(spanKey{}).(*activeSpan); ok {
+				s.setAttrs(t.outputAttrs(s.component, output))
+				t.finish(ctx, s)
+			}
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			s, ok := ctx.Value(spanKey{}).(*activeSpan)
+			if !ok {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				chunks, err := readAll(output)
+				s.setAttrs(t.streamOutputAttrs(s.component, chunks))
+				if err != nil {
+					s.setError(err)
+				}
+				t.finish(ctx, s)
+			}()
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if s, ok := ctx.Value(spanKey{}).(*activeSpan); ok {
+				s.setError(err)
+				t.finish(ctx, s)
+			}
+			return ctx
+		}).
+		Build()
+}
+
+// Err returns the first error returned by the exporter.
+func (t *Tracer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Tracer) start(ctx context.Context, info *callbacks.RunInfo) (context.Context, *activeSpan) {
+	span := &Span{
+		SpanID:     newID(8),
+		StartTime:  time.Now(),
+		Attributes: make(map[string]any),
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*activeSpan); ok {
+		span.TraceID = parent.span.TraceID
+		span.ParentSpanID = parent.span.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+	if attrs, ok := ctx.Value(attributesKey{}).(map[string]any); ok {
+		for k, v := range attrs {
+			span.Attributes[k] = v
+		}
+	}
+
+	s := &activeSpan{span: span}
+	if info != nil {
+		s.component = info.Component
+		span.Name = info.Name
+		if len(span.Name) == 0 {
+			span.Name = info.Type + string(info.Component)
+		}
+		span.Attributes[AttrEinoComponent] = string(info.Component)
+		if len(info.Type) > 0 {
+			span.Attributes[AttrEinoType] = info.Type
+		}
+		if len(info.Group) > 0 {
+			span.Attributes[AttrEinoNodeGroup] = info.Group
+		}
+	}
+	span.Kind = spanKindOf(s.component)
+	if t.spanKind != nil && info != nil {
+		if kind := t.spanKind(info); len(kind) > 0 {
+			span.Kind = kind
+		}
+	}
+	span.Attributes[AttrSpanKind] = span.Kind
+	if span.Kind == SpanKindTool {
+		span.Attributes[AttrToolName] = span.Name
+	}
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *Tracer) finish(ctx context.Context, s *activeSpan) {
+	export := func() {
+		s.mu.Lock()
+		s.span.EndTime = time.Now()
+		s.mu.Unlock()
+		if err := t.exporter.Export(ctx, s.span); err != nil {
+			t.mu.Lock()
+			if t.err == nil {
+				t.err = err
+			}
+			t.mu.Unlock()
+		}
+	}
+
+	if s.streamingInput {
+		// the input may still be streaming, don't block the callback
+		go func() {
+			s.input.Wait()
+			export()
+		}()
+		return
+	}
+	export()
+}
+
+func spanKindOf(component components.Component) string {
+	switch component {
+	case components.ComponentOfChatModel:
+		return SpanKindLLM
+	case components.ComponentOfTool:
+		return SpanKindTool
+	case components.ComponentOfRetriever:
+		return SpanKindRetriever
+	case components.ComponentOfEmbedding:
+		return SpanKindEmbedding
+	default:
+		return SpanKindChain
+	}
+}
+
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func readAll[T any](sr *schema.StreamReader[T]) ([]T, error) {
+	defer sr.Close()
+	var chunks []T
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, chunk)
+	}
+}
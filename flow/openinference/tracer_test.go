@@ -0,0 +1,301 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openinference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type toolCallingModel struct{}
+
+func (toolCallingModel) Generate(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+	msg := schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "call_1",
+		Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"bj"}`},
+	}})
+	msg.ResponseMeta = &schema.ResponseMeta{
+		Model: "gpt-x",
+		Usage: &schema.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
+	return msg, nil
+}
+
+func (m toolCallingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, _ := m.Generate(ctx, input, opts...)
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (toolCallingModel) BindTools([]*schema.ToolInfo) error { return nil }
+
+type weatherTool struct {
+	err error
+}
+
+func (w *weatherTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "weather", Desc: "get weather"}, nil
+}
+
+func (w *weatherTool) InvokableRun(context.Context, string, ...tool.Option) (string, error) {
+	if w.err != nil {
+		return "", w.err
+	}
+	return "sunny", nil
+}
+
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (m *memoryExporter) Export(_ context.Context, span *Span) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = append(m.spans, span)
+	return nil
+}
+
+func (m *memoryExporter) byKind() map[string]*Span {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make(map[string]*Span)
+	for _, s := range m.spans {
+		ret[s.Kind] = s
+	}
+	return ret
+}
+
+func buildChain(t *testing.T, wt *weatherTool) compose.Runnable[[]*schema.Message, []*schema.Message] {
+	ctx := context.Background()
+	tn, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: []tool.BaseTool{wt}})
+	assert.NoError(t, err)
+	r, err := compose.NewChain[[]*schema.Message, []*schema.Message]().
+		AppendChatModel(toolCallingModel{}).
+		AppendToolsNode(tn).
+		Compile(ctx)
+	assert.NoError(t, err)
+	return r
+}
+
+func TestTracer(t *testing.T) {
+	input := []*schema.Message{schema.SystemMessage("be brief"), schema.UserMessage("weather in bj?")}
+
+	t.Run("spans", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter)
+		ctx := WithAttributes(context.Background(), map[string]any{AttrSessionID: "s1"})
+
+		_, err := buildChain(t, &weatherTool{}).Invoke(ctx, input, compose.WithCallbacks(tracer.Handler()))
+		assert.NoError(t, err)
+		assert.NoError(t, tracer.Err())
+
+		spans := exporter.byKind()
+		llm, tl, chain := spans[SpanKindLLM], spans[SpanKindTool], spans[SpanKindChain]
+		assert.NotNil(t, llm)
+		assert.NotNil(t, tl)
+		assert.NotNil(t, chain)
+
+		// the chain is the root of all the spans
+		var root *Span
+		for _, s := range exporter.spans {
+			assert.Equal(t, llm.TraceID, s.TraceID)
+			assert.Equal(t, "s1", s.Attributes[AttrSessionID])
+			if len(s.ParentSpanID) == 0 {
+				assert.Nil(t, root)
+				root = s
+			}
+		}
+		assert.Equal(t, root.SpanID, llm.ParentSpanID)
+		assert.Len(t, root.TraceID, 32)
+		assert.Len(t, root.SpanID, 16)
+
+		assert.Equal(t, "system", llm.Attributes["llm.input_messages.0.message.role"])
+		assert.Equal(t, "be brief", llm.Attributes["llm.input_messages.0.message.content"])
+		assert.Equal(t, "weather in bj?", llm.Attributes["llm.input_messages.1.message.content"])
+		assert.Equal(t, "weather", llm.Attributes["llm.output_messages.0.message.tool_calls.0.tool_call.function.name"])
+		assert.Equal(t, `{"city":"bj"}`, llm.Attributes["llm.output_messages.0.message.tool_calls.0.tool_call.function.arguments"])
+		assert.Equal(t, "gpt-x", llm.Attributes[AttrLLMModelName])
+		assert.Equal(t, 10, llm.Attributes[AttrLLMTokenCountPrompt])
+		assert.Equal(t, 5, llm.Attributes[AttrLLMTokenCountCompletion])
+		assert.Equal(t, 15, llm.Attributes[AttrLLMTokenCountTotal])
+		assert.Equal(t, SpanKindLLM, llm.Attributes[AttrSpanKind])
+
+		assert.Equal(t, "weather", tl.Attributes[AttrToolName])
+		assert.Equal(t, `{"city":"bj"}`, tl.Attributes[AttrInputValue])
+		assert.Equal(t, "sunny", tl.Attributes[AttrOutputValue])
+		assert.Empty(t, tl.Error)
+	})
+
+	t.Run("stream and options", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter, WithoutContent(), WithSpanKind(func(info *callbacks.RunInfo) string {
+			if info.Component == compose.ComponentOfChain {
+				return SpanKindAgent
+			}
+			return ""
+		}))
+
+		sr, err := buildChain(t, &weatherTool{}).Stream(context.Background(), input, compose.WithCallbacks(tracer.Handler()))
+		assert.NoError(t, err)
+		for {
+			if _, err = sr.Recv(); err != nil {
+				break
+			}
+		}
+		sr.Close()
+
+		assert.Eventually(t, func() bool { return len(exporter.byKind()) == 4 }, time.Second, 10*time.Millisecond)
+		spans := exporter.byKind()
+		llm := spans[SpanKindLLM]
+		assert.Equal(t, 15, llm.Attributes[AttrLLMTokenCountTotal])
+		assert.Nil(t, llm.Attributes[AttrInputValue])
+		assert.Nil(t, llm.Attributes["llm.input_messages.0.message.content"])
+		assert.NotNil(t, spans[SpanKindAgent])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exporter := &memoryExporter{}
+		tracer := NewTracer(exporter)
+		_, err := buildChain(t, &weatherTool{err: errors.New("no weather")}).Invoke(context.Background(), input, compose.WithCallbacks(tracer.Handler()))
+		assert.Error(t, err)
+		assert.Contains(t, exporter.byKind()[SpanKindTool].Error, "no weather")
+		assert.Contains(t, exporter.byKind()[SpanKindChain].Error, "no weather")
+	})
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+		headers  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := map[string]any{}
+		_ = json.Unmarshal(body, &req)
+		mu.Lock()
+		requests = append(requests, req)
+		headers = append(headers, r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	_, err := NewOTLPExporter(&OTLPConfig{})
+	assert.Error(t, err)
+
+	exporter, err := NewOTLPExporter(&OTLPConfig{
+		Endpoint:  server.URL,
+		Headers:   map[string]string{"Authorization": "Basic xxx"},
+		BatchSize: 3,
+	})
+	assert.NoError(t, err)
+	tracer := NewTracer(exporter)
+
+	_, err = buildChain(t, &weatherTool{}).Invoke(context.Background(), []*schema.Message{schema.UserMessage("hi")},
+		compose.WithCallbacks(tracer.Handler()))
+	assert.NoError(t, err)
+	assert.NoError(t, tracer.Err())
+	// the model, tool and tools node spans are sent in a batch of 3 in background, and the chain span by Flush
+	assert.NoError(t, exporter.Flush(context.Background()))
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+	assert.Error(t, exporter.Export(context.Background(), &Span{}))
+	assert.Len(t, requests, 2)
+	assert.Equal(t, []string{"Basic xxx", "Basic xxx"}, headers)
+
+	rs := requests[1]["resourceSpans"].([]any)[0].(map[string]any)
+	resourceAttrs := rs["resource"].(map[string]any)["attributes"].([]any)
+	assert.Equal(t, map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "eino"}}, resourceAttrs[0])
+	spans := rs["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	assert.Len(t, spans, 1)
+	rs = requests[0]["resourceSpans"].([]any)[0].(map[string]any)
+	spans = rs["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	assert.Len(t, spans, 3)
+	for _, s := range spans {
+		span := s.(map[string]any)
+		assert.Len(t, span["traceId"], 32)
+		assert.Equal(t, float64(1), span["status"].(map[string]any)["code"])
+		for _, a := range span["attributes"].([]any) {
+			kv := a.(map[string]any)
+			if kv["key"] == AttrLLMTokenCountTotal {
+				assert.Equal(t, map[string]any{"intValue": "15"}, kv["value"])
+			}
+		}
+	}
+}
+
+func TestOTLPExporterRetry(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fail    = true
+		batches []int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := &otlpRequest{}
+		_ = json.Unmarshal(body, req)
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		batches = append(batches, len(req.ResourceSpans[0].ScopeSpans[0].Spans))
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(&OTLPConfig{
+		Endpoint:     server.URL,
+		BatchSize:    2,
+		MaxQueueSize: 3,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		_ = exporter.Shutdown(context.Background())
+	}()
+
+	// the run is done before the spans are sent, so its canceled context doesn't matter
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, exporter.Export(ctx, &Span{Name: strconv.Itoa(i)}))
+	}
+	assert.Error(t, exporter.Export(ctx, &Span{Name: "3"}))
+
+	// the spans failed to send are kept
+	assert.Error(t, exporter.Flush(context.Background()))
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	assert.NoError(t, exporter.Flush(context.Background()))
+	assert.Equal(t, []int{2, 1}, batches)
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"github.com/cloudwego/eino/schema"
+)
+
+// CitationsExtraKey is the key of the citations in the Extra of the answer.
+const CitationsExtraKey = "_eino_rag_citations"
+
+// Citation describes a document packed into the context of the answer.
+type Citation struct {
+	// Index is the index of the document in the context, starting from 1, i.e. the n of the [n] citing it.
+	Index      int
+	DocumentID string
+	// Start and End are the byte offsets of the formatted document in the packed context.
+	Start, End int
+	// Score is the score of the document given by the retriever or the reranker.
+	Score    float64
+	MetaData map[string]any
+	// Cited reports whether the answer references the document by [Index].
+	Cited bool
+	// Truncated reports whether the content of the document is truncated to fit in the context, set by PackContext and the RAG flow.
+	Truncated bool
+}
+
+// GetCitations returns the citations of the answer generated by the RAG flow, nil if there is none.
+func GetCitations(msg *schema.Message) []*Citation {
+	if msg == nil {
+		return nil
+	}
+	citations, _ := msg.Extra[CitationsExtraKey].([]*Citation)
+	return citations
+}
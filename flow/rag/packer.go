@@ -0,0 +1,289 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+// MetaKeyCitations is the key of the []*Citation in the MetaData of the document output by ContextPacker.
+const MetaKeyCitations = "_eino_rag_citations"
+
+// PackConfig is the config of PackContext and ContextPacker.
+type PackConfig struct {
+	// MaxTokens is the token budget of the packed context, including the headers and separators, required.
+	MaxTokens int
+	// Tokenizer counts the tokens. Optional. By default, tokenizer.Approximate.
+	Tokenizer tokenizer.Tokenizer
+	// Header formats the header of a packed document, index starting from 1, which is also the citation marker.
+	// Optional. By default, "[index]", followed by " (source: ...)" if the document has a "source" in its MetaData.
+	Header func(index int, doc *schema.Document) string
+	// Separator separates the packed documents. Optional. By default, a blank line.
+	Separator string
+	// DisableTruncation skips the documents which don't fit in the rest of the budget,
+	// instead of truncating them at sentence boundaries.
+	DisableTruncation bool
+}
+
+// PackedContext is the result of PackContext.
+type PackedContext struct {
+	// Text is the packed context, i.e. the formatted documents joined by the separator.
+	Text string
+	// Documents are the packed documents in the order of the context, whose content may be truncated.
+	Documents []*schema.Document
+	// Citations describe the packed documents, in the same order as Documents.
+	Citations []*Citation
+	// Tokens is the number of tokens of Text.
+	Tokens int
+}
+
+// PackContext packs the documents into a context block of at most config.MaxTokens tokens.
+// The documents are chosen greedily by their scores in descending order, keeping the retrieval order for the equal scores.
+// Each packed document is formatted as the header followed by its content on the next line.
+// A document which doesn't fit in the rest of the budget is truncated at the last sentence boundary which fits,
+// or skipped if not even its first sentence fits, and then the smaller documents are tried.
+// e.g.
+//
+//	packed, err := rag.PackContext(ctx, docs, &rag.PackConfig{MaxTokens: 4000})
+//	messages, err := tpl.Format(ctx, map[string]any{"context": packed.Text, "query": query})
+func PackContext(ctx context.Context, docs []*schema.Document, config *PackConfig) (*PackedContext, error) {
+	p, err := newPacker(config)
+	if err != nil {
+		return nil, err
+	}
+	return p.pack(ctx, docs)
+}
+
+// ContextPacker is a document.Transformer packing the documents into a single document, whose content is the packed context,
+// with the citations in its MetaData by MetaKeyCitations, e.g. to be added after the retriever or the reranker in a graph.
+type ContextPacker struct {
+	p *packer
+}
+
+// NewContextPacker creates a ContextPacker.
+func NewContextPacker(_ context.Context, config *PackConfig) (*ContextPacker, error) {
+	p, err := newPacker(config)
+	if err != nil {
+		return nil, err
+	}
+	return &ContextPacker{p: p}, nil
+}
+
+// Transform packs the documents into a single document, see PackContext.
+func (c *ContextPacker) Transform(ctx context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	packed, err := c.p.pack(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return []*schema.Document{{
+		Content:  packed.Text,
+		MetaData: map[string]any{MetaKeyCitations: packed.Citations},
+	}}, nil
+}
+
+// GetType returns the type of the transformer (ContextPacker).
+func (c *ContextPacker) GetType() string {
+	return "ContextPacker"
+}
+
+type packer struct {
+	maxTokens  int
+	tokenizer  tokenizer.Tokenizer
+	header     func(index int, doc *schema.Document) string
+	sep        string
+	noTruncate bool
+}
+
+func newPacker(config *PackConfig) (*packer, error) {
+	if config == nil {
+		return nil, errors.New("pack config is nil")
+	}
+	if config.MaxTokens <= 0 {
+		return nil, errors.New("max tokens of pack config should be positive")
+	}
+	p := &packer{
+		maxTokens:  config.MaxTokens,
+		tokenizer:  config.Tokenizer,
+		header:     config.Header,
+		sep:        config.Separator,
+		noTruncate: config.DisableTruncation,
+	}
+	if p.tokenizer == nil {
+		p.tokenizer = tokenizer.Approximate
+	}
+	if p.header == nil {
+		p.header = defaultHeader
+	}
+	if p.sep == "" {
+		p.sep = "\n\n"
+	}
+	return p, nil
+}
+
+func defaultHeader(index int, doc *schema.Document) string {
+	if source, ok := doc.MetaData["source"].(string); ok && source != "" {
+		return fmt.Sprintf("[%d] (source: %s)", index, source)
+	}
+	return fmt.Sprintf("[%d]", index)
+}
+
+func (p *packer) pack(ctx context.Context, docs []*schema.Document) (*PackedContext, error) {
+	candidates := make([]*schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if doc != nil {
+			candidates = append(candidates, doc)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score() > candidates[j].Score()
+	})
+
+	sepTokens, err := p.tokenizer.CountTokens(ctx, p.sep)
+	if err != nil {
+		return nil, fmt.Errorf("count tokens fail: %w", err)
+	}
+
+	var (
+		sb  strings.Builder
+		ret = &PackedContext{}
+	)
+	for _, doc := range candidates {
+		budget := p.maxTokens - ret.Tokens
+		if len(ret.Documents) > 0 {
+			budget -= sepTokens
+		}
+		if budget <= 0 {
+			break
+		}
+
+		index := len(ret.Documents) + 1
+		header, content := p.header(index, doc)+"\n", doc.Content
+		text, tokens, truncated, err := p.fit(ctx, header, content, budget)
+		if err != nil {
+			return nil, err
+		}
+		if text == "" {
+			continue
+		}
+
+		if len(ret.Documents) > 0 {
+			sb.WriteString(p.sep)
+			ret.Tokens += sepTokens
+		}
+		start := sb.Len()
+		sb.WriteString(text)
+		ret.Tokens += tokens
+
+		packed := doc
+		if truncated {
+			cp := *doc
+			cp.Content = text[len(header):]
+			packed = &cp
+		}
+		ret.Documents = append(ret.Documents, packed)
+		ret.Citations = append(ret.Citations, &Citation{
+			Index:      index,
+			DocumentID: doc.ID,
+			Start:      start,
+			End:        sb.Len(),
+			Score:      doc.Score(),
+			MetaData:   doc.MetaData,
+			Truncated:  truncated,
+		})
+	}
+	ret.Text = sb.String()
+	return ret, nil
+}
+
+// fit returns the formatted document if it fits in the budget, otherwise the one truncated at the longest sentence prefix which fits.
+// The text is empty if nothing fits.
+func (p *packer) fit(ctx context.Context, header, content string, budget int) (text string, tokens int, truncated bool, err error) {
+	count := func(s string) (int, error) {
+		n, err := p.tokenizer.CountTokens(ctx, s)
+		if err != nil {
+			return 0, fmt.Errorf("count tokens fail: %w", err)
+		}
+		return n, nil
+	}
+
+	text = header + content
+	if tokens, err = count(text); err != nil || tokens <= budget {
+		return text, tokens, false, err
+	}
+	if p.noTruncate {
+		return "", 0, false, nil
+	}
+
+	// binary search the most sentences fitting in the budget, as the tokens grow with the sentences
+	ends := sentenceEnds(content)
+	lo, hi := 0, len(ends)-1 // the whole content doesn't fit
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		n, err := count(header + strings.TrimRightFunc(content[:ends[mid-1]], unicode.IsSpace))
+		if err != nil {
+			return "", 0, false, err
+		}
+		if n <= budget {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 {
+		return "", 0, false, nil
+	}
+
+	text = header + strings.TrimRightFunc(content[:ends[lo-1]], unicode.IsSpace)
+	if tokens, err = count(text); err != nil {
+		return "", 0, false, err
+	}
+	return text, tokens, true, nil
+}
+
+const sentenceTerminators = ".!?。！？；;\n"
+
+// sentenceEnds returns the byte offsets where the sentences of the text end, the last one is always len(text).
+// A sentence ends at a terminator followed by a white space or the end of the text, or at a CJK terminator.
+func sentenceEnds(text string) []int {
+	var ends []int
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !strings.ContainsRune(sentenceTerminators, r) {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(text[i:]); i < len(text) && r < utf8.RuneSelf && !unicode.IsSpace(r) && !unicode.IsSpace(next) {
+			// e.g. 3.14, e.g.
+			continue
+		}
+		ends = append(ends, i)
+	}
+	if len(ends) == 0 || ends[len(ends)-1] != len(text) {
+		ends = append(ends, len(text))
+	}
+	return ends
+}
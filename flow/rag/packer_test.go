@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/eino/schema/tokenizer"
+)
+
+var wordTokenizer = tokenizer.Func(func(_ context.Context, text string) (int, error) {
+	return len(strings.Fields(text)), nil
+})
+
+func TestPackContext(t *testing.T) {
+	ctx := context.Background()
+	docs := []*schema.Document{
+		(&schema.Document{ID: "a", Content: "alpha one. alpha two."}).WithScore(0.5),
+		(&schema.Document{ID: "b", Content: "beta one. beta two. beta three."}).WithScore(0.9),
+		nil,
+		(&schema.Document{ID: "c", Content: "gamma one. gamma two. gamma three. gamma 3.14 four. gamma five."}).WithScore(0.7),
+	}
+
+	_, err := PackContext(ctx, docs, &PackConfig{})
+	assert.Error(t, err)
+
+	packed, err := PackContext(ctx, docs, &PackConfig{MaxTokens: 12, Tokenizer: wordTokenizer})
+	require.NoError(t, err)
+	assert.Equal(t, "[1]\nbeta one. beta two. beta three.\n\n[2]\ngamma one. gamma two.", packed.Text)
+	assert.Equal(t, 12, packed.Tokens)
+	require.Len(t, packed.Documents, 2)
+	assert.Equal(t, "gamma one. gamma two.", packed.Documents[1].Content)
+	// the input is not modified
+	assert.Equal(t, "gamma one. gamma two. gamma three. gamma 3.14 four. gamma five.", docs[3].Content)
+	require.Len(t, packed.Citations, 2)
+	assert.Equal(t, "b", packed.Citations[0].DocumentID)
+	assert.False(t, packed.Citations[0].Truncated)
+	assert.Equal(t, "c", packed.Citations[1].DocumentID)
+	assert.True(t, packed.Citations[1].Truncated)
+	assert.Equal(t, "[2]\ngamma one. gamma two.", packed.Text[packed.Citations[1].Start:packed.Citations[1].End])
+
+	// a smaller document is tried after a larger one doesn't fit
+	packed, err = PackContext(ctx, docs, &PackConfig{MaxTokens: 12, Tokenizer: wordTokenizer, DisableTruncation: true})
+	require.NoError(t, err)
+	assert.Equal(t, "[1]\nbeta one. beta two. beta three.\n\n[2]\nalpha one. alpha two.", packed.Text)
+
+	// the sentence with 3.14 is not broken
+	packed, err = PackContext(ctx, docs[3:], &PackConfig{MaxTokens: 9, Tokenizer: wordTokenizer,
+		Header: func(index int, doc *schema.Document) string { return "Doc " + doc.ID + ":" }})
+	require.NoError(t, err)
+	assert.Equal(t, "Doc c:\ngamma one. gamma two. gamma three.", packed.Text)
+}
+
+func TestContextPacker(t *testing.T) {
+	ctx := context.Background()
+	packer, err := NewContextPacker(ctx, &PackConfig{MaxTokens: 100})
+	require.NoError(t, err)
+
+	r, err := compose.NewChain[[]*schema.Document, []*schema.Document]().
+		AppendDocumentTransformer(packer).
+		Compile(ctx)
+	require.NoError(t, err)
+
+	out, err := r.Invoke(ctx, []*schema.Document{
+		{ID: "1", Content: "first", MetaData: map[string]any{"source": "a.md"}},
+		{ID: "2", Content: "second"},
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, "[1] (source: a.md)\nfirst\n\n[2]\nsecond", out[0].Content)
+	citations, ok := out[0].MetaData[MetaKeyCitations].([]*Citation)
+	require.True(t, ok)
+	assert.Len(t, citations, 2)
+}
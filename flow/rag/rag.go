@@ -0,0 +1,282 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rag implements the retrieval-augmented generation flow:
+// retriever -> optional reranker -> context packing -> chat template -> chat model,
+// with the final message annotated by the citations of the documents packed into the context.
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	retrieverNodeKey = "retriever"
+	rerankerNodeKey  = "reranker"
+	packerNodeKey    = "context_packer"
+	templateNodeKey  = "chat_template"
+	modelNodeKey     = "chat_model"
+	citationNodeKey  = "citation"
+)
+
+// Variables of the chat template.
+const (
+	// VariableContext is the packed context, i.e. the formatted documents joined by blank lines.
+	VariableContext = "context"
+	// VariableQuery is the query, i.e. the input of the flow.
+	VariableQuery = "query"
+	// VariableDocuments is the []*schema.Document packed into the context.
+	VariableDocuments = "documents"
+)
+
+const defaultSystemPrompt = "Answer the question based on the documents below. " +
+	"Cite the documents you use by their index, e.g. [1]. " +
+	"If the documents do not contain the answer, say you don't know.\n\n{context}"
+
+// Config is the config of the RAG flow.
+type Config struct {
+	// Retriever retrieves the documents for the query, required.
+	Retriever retriever.Retriever
+	// Reranker reorders or filters the retrieved documents, optional.
+	Reranker document.Transformer
+
+	// Pack configures how the documents are packed into the context, see PackContext.
+	// Optional. By default, the documents are packed with no limit.
+	Pack *PackConfig
+
+	// ChatTemplate formats the messages with the variables VariableContext, VariableQuery and VariableDocuments.
+	// Optional. By default, a system message asking to answer by the context with citations, followed by the query as user message.
+	ChatTemplate prompt.ChatTemplate
+	// ChatModel generates the answer, required.
+	ChatModel model.BaseChatModel
+}
+
+type state struct {
+	query     string
+	citations []*Citation
+}
+
+// NewGraph creates the graph of the RAG flow, whose input is the query and output is the answer.
+// The answer carries the citations in its Extra, read them by GetCitations.
+// e.g.
+//
+//	g, err := rag.NewGraph(ctx, &rag.Config{
+//		Retriever: vikingRetriever,
+//		Pack:      &rag.PackConfig{MaxTokens: 4000},
+//		ChatModel: chatModel,
+//	})
+//	r, err := g.Compile(ctx)
+//	answer, err := r.Invoke(ctx, "how to build agent with eino")
+//	for _, c := range rag.GetCitations(answer) {
+//		if c.Cited {
+//			fmt.Println(c.DocumentID)
+//		}
+//	}
+func NewGraph(ctx context.Context, config *Config) (*compose.Graph[string, *schema.Message], error) {
+	if config == nil {
+		return nil, errors.New("rag config is nil")
+	}
+	if config.Retriever == nil {
+		return nil, errors.New("rag retriever is nil")
+	}
+	if config.ChatModel == nil {
+		return nil, errors.New("rag chat model is nil")
+	}
+
+	tpl := config.ChatTemplate
+	if tpl == nil {
+		tpl = prompt.FromMessages(schema.FString,
+			schema.SystemMessage(defaultSystemPrompt),
+			schema.UserMessage("{query}"))
+	}
+
+	p, err := newPacker(packConfig(config))
+	if err != nil {
+		return nil, err
+	}
+
+	g := compose.NewGraph[string, *schema.Message](
+		compose.WithGenLocalState(func(context.Context) *state { return &state{} }))
+
+	if err = g.AddRetrieverNode(retrieverNodeKey, config.Retriever,
+		compose.WithStatePreHandler(func(_ context.Context, query string, state *state) (string, error) {
+			state.query = query
+			return query, nil
+		})); err != nil {
+		return nil, err
+	}
+
+	last := retrieverNodeKey
+	if config.Reranker != nil {
+		if err := g.AddDocumentTransformerNode(rerankerNodeKey, config.Reranker); err != nil {
+			return nil, err
+		}
+		if err := g.AddEdge(last, rerankerNodeKey); err != nil {
+			return nil, err
+		}
+		last = rerankerNodeKey
+	}
+
+	if err := g.AddLambdaNode(packerNodeKey, compose.InvokableLambda(packVariables(p))); err != nil {
+		return nil, err
+	}
+	if err := g.AddChatTemplateNode(templateNodeKey, tpl); err != nil {
+		return nil, err
+	}
+	if err := g.AddChatModelNode(modelNodeKey, config.ChatModel); err != nil {
+		return nil, err
+	}
+	citation, err := compose.AnyLambda(annotateCitations, nil, nil, streamAnnotateCitations)
+	if err != nil {
+		return nil, err
+	}
+	if err = g.AddLambdaNode(citationNodeKey, citation); err != nil {
+		return nil, err
+	}
+
+	for _, e := range [][2]string{
+		{compose.START, retrieverNodeKey},
+		{last, packerNodeKey},
+		{packerNodeKey, templateNodeKey},
+		{templateNodeKey, modelNodeKey},
+		{modelNodeKey, citationNodeKey},
+		{citationNodeKey, compose.END},
+	} {
+		if err = g.AddEdge(e[0], e[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// packConfig returns config.Pack, or the one packing with no limit if it's nil.
+func packConfig(config *Config) *PackConfig {
+	if config.Pack != nil {
+		return config.Pack
+	}
+	return &PackConfig{MaxTokens: math.MaxInt}
+}
+
+// packVariables packs the documents into the variables of the chat template, and keeps the citations in the state.
+func packVariables(p *packer) func(ctx context.Context, docs []*schema.Document) (map[string]any, error) {
+	return func(ctx context.Context, docs []*schema.Document) (map[string]any, error) {
+		packed, err := p.pack(ctx, docs)
+		if err != nil {
+			return nil, err
+		}
+
+		var query string
+		err = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			query = state.query
+			state.citations = packed.Citations
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{
+			VariableContext:   packed.Text,
+			VariableQuery:     query,
+			VariableDocuments: packed.Documents,
+		}, nil
+	}
+}
+
+func annotateCitations(ctx context.Context, msg *schema.Message, _ ...any) (*schema.Message, error) {
+	citations, err := getStateCitations(ctx, msg.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := *msg
+	ret.Extra = make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		ret.Extra[k] = v
+	}
+	ret.Extra[CitationsExtraKey] = citations
+	return &ret, nil
+}
+
+// streamAnnotateCitations passes the chunks through, and appends a chunk carrying the citations at the end.
+func streamAnnotateCitations(ctx context.Context, sr *schema.StreamReader[*schema.Message], _ ...any) (*schema.StreamReader[*schema.Message], error) {
+	out, sw := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer func() {
+			sr.Close()
+			sw.Close()
+		}()
+
+		var (
+			content strings.Builder
+			role    schema.RoleType = schema.Assistant
+			name    string
+		)
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if chunk != nil {
+				content.WriteString(chunk.Content)
+				role, name = chunk.Role, chunk.Name
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+
+		citations, err := getStateCitations(ctx, content.String())
+		if err != nil {
+			sw.Send(nil, err)
+			return
+		}
+		sw.Send(&schema.Message{Role: role, Name: name, Extra: map[string]any{CitationsExtraKey: citations}}, nil)
+	}()
+
+	return out, nil
+}
+
+func getStateCitations(ctx context.Context, answer string) ([]*Citation, error) {
+	var citations []*Citation
+	err := compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+		citations = make([]*Citation, 0, len(state.citations))
+		for _, c := range state.citations {
+			cc := *c
+			cc.Cited = strings.Contains(answer, fmt.Sprintf("[%d]", c.Index))
+			citations = append(citations, &cc)
+		}
+		return nil
+	})
+	return citations, err
+}
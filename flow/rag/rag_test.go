@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeRetriever struct {
+	docs []*schema.Document
+}
+
+func (f *fakeRetriever) Retrieve(_ context.Context, _ string, _ ...retriever.Option) ([]*schema.Document, error) {
+	return f.docs, nil
+}
+
+type reverseReranker struct{}
+
+func (reverseReranker) Transform(_ context.Context, src []*schema.Document, _ ...document.TransformerOption) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, 0, len(src))
+	for i := len(src) - 1; i >= 0; i-- {
+		ret = append(ret, src[i])
+	}
+	return ret, nil
+}
+
+func TestRAG(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockChatModel(ctrl)
+
+	r := &fakeRetriever{docs: []*schema.Document{
+		{ID: "b", Content: strings.Repeat("eino supports streaming. ", 10)},
+		{ID: "a", Content: "eino is a framework"},
+		{ID: "c", Content: "eino is written in go", MetaData: map[string]any{"source": "readme"}},
+	}}
+
+	g, err := NewGraph(ctx, &Config{
+		Retriever: r,
+		Reranker:  reverseReranker{},
+		Pack:      &PackConfig{MaxTokens: 30},
+		ChatModel: cm,
+	})
+	assert.NoError(t, err)
+	run, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	checkInput := func(input []*schema.Message) {
+		assert.Len(t, input, 2)
+		// the last document is truncated to the first sentence to fit in the budget
+		assert.True(t, strings.HasSuffix(input[0].Content,
+			"[1] (source: readme)\neino is written in go\n\n[2]\neino is a framework\n\n[3]\neino supports streaming."))
+		assert.Equal(t, "what is eino", input[1].Content)
+	}
+
+	checkCitations := func(citations []*Citation) {
+		assert.Len(t, citations, 3)
+		assert.Equal(t, "c", citations[0].DocumentID)
+		assert.Equal(t, 1, citations[0].Index)
+		assert.Equal(t, "readme", citations[0].MetaData["source"])
+		assert.False(t, citations[0].Cited)
+		assert.Equal(t, "a", citations[1].DocumentID)
+		assert.True(t, citations[1].Cited)
+		assert.False(t, citations[1].Truncated)
+		assert.Equal(t, "b", citations[2].DocumentID)
+		assert.True(t, citations[2].Truncated)
+	}
+
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			checkInput(input)
+			return schema.AssistantMessage("eino is a framework [2]", nil), nil
+		}).Times(1)
+
+	out, err := run.Invoke(ctx, "what is eino")
+	assert.NoError(t, err)
+	assert.Equal(t, "eino is a framework [2]", out.Content)
+	checkCitations(GetCitations(out))
+
+	cm.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+			checkInput(input)
+			return schema.StreamReaderFromArray([]*schema.Message{
+				schema.AssistantMessage("eino is a framework ", nil),
+				schema.AssistantMessage("[2]", nil),
+			}), nil
+		}).Times(1)
+
+	sr, err := run.Stream(ctx, "what is eino")
+	assert.NoError(t, err)
+	out, err = schema.ConcatMessageStream(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "eino is a framework [2]", out.Content)
+	checkCitations(GetCitations(out))
+}
+
+func TestPackConfig(t *testing.T) {
+	pack := &PackConfig{MaxTokens: 10}
+	assert.Same(t, pack, packConfig(&Config{Pack: pack}))
+
+	pc := packConfig(&Config{})
+	assert.Equal(t, math.MaxInt, pc.MaxTokens)
+	assert.Nil(t, pc.Tokenizer)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrNotRecorded is returned by the replayed calls having no recorded entry.
+var ErrNotRecorded = errors.New("call not recorded")
+
+// NewPlayer creates a Player replaying the entries loaded from the store.
+func NewPlayer(ctx context.Context, store Store) (*Player, error) {
+	entries, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load recorded entries fail: %w", err)
+	}
+
+	p := &Player{
+		entries: make(map[string]map[int]*Entry),
+		seqs:    make(map[string]int),
+	}
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		key := entryKey(e.Address, e.Component)
+		if p.entries[key] == nil {
+			p.entries[key] = make(map[int]*Entry)
+		}
+		p.entries[key][e.Seq] = e
+	}
+	return p, nil
+}
+
+// Player replays the recorded outputs of chat models and tools instead of calling them.
+// A call is matched with the entry recorded at the same address, with the same component and sequence number,
+// so the replayed graph should be built the same as the recorded one.
+// Replaying a model recorded by Generate with Stream gets a single chunk stream, and vice versa the chunks are concatenated.
+type Player struct {
+	entries map[string]map[int]*Entry
+
+	mu   sync.Mutex
+	seqs map[string]int
+}
+
+// ChatModelMiddleware returns the middleware replaying the recorded chat model outputs, use it with model.WrapChatModel.
+func (p *Player) ChatModelMiddleware() model.Middleware {
+	return model.Middleware{
+		Generate: func(_ model.GenerateEndpoint) model.GenerateEndpoint {
+			return func(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+				e, err := p.next(ctx, components.ComponentOfChatModel)
+				if err != nil {
+					return nil, err
+				}
+				return e.Message, nil
+			}
+		},
+		Stream: func(_ model.StreamEndpoint) model.StreamEndpoint {
+			return func(ctx context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				e, err := p.next(ctx, components.ComponentOfChatModel)
+				if err != nil {
+					return nil, err
+				}
+				if len(e.Chunks) > 0 {
+					return schema.StreamReaderFromArray(e.Chunks), nil
+				}
+				return schema.StreamReaderFromArray([]*schema.Message{e.Message}), nil
+			}
+		},
+	}
+}
+
+// ToolMiddleware returns the middleware replaying the recorded tool results, use it with compose.ToolsNodeConfig.ToolCallMiddlewares.
+func (p *Player) ToolMiddleware() compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(_ compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, _ *compose.ToolInput) (*compose.ToolOutput, error) {
+				e, err := p.next(ctx, components.ComponentOfTool)
+				if err != nil {
+					return nil, err
+				}
+				return &compose.ToolOutput{Result: e.Result}, nil
+			}
+		},
+		Streamable: func(_ compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			return func(ctx context.Context, _ *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				e, err := p.next(ctx, components.ComponentOfTool)
+				if err != nil {
+					return nil, err
+				}
+				if len(e.ResultChunks) > 0 {
+					return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray(e.ResultChunks)}, nil
+				}
+				return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{e.Result})}, nil
+			}
+		},
+	}
+}
+
+// Reset restarts the replay from the first recorded calls, so the player can be reused for another run.
+func (p *Player) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seqs = make(map[string]int)
+}
+
+func (p *Player) next(ctx context.Context, component components.Component) (*Entry, error) {
+	addr := compose.GetCurrentAddress(ctx).String()
+	key := entryKey(addr, component)
+
+	p.mu.Lock()
+	seq := p.seqs[key]
+	p.seqs[key]++
+	p.mu.Unlock()
+
+	e, ok := p.entries[key][seq]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s[address:%s seq:%d]", ErrNotRecorded, component, addr, seq)
+	}
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	return e, nil
+}
@@ -0,0 +1,284 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// NewRecorder creates a Recorder saving the entries to the store.
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{
+		save: func(ctx context.Context, rec *recording) error {
+			return store.Save(ctx, rec.entry)
+		},
+		seqs: make(map[string]int),
+	}
+}
+
+// Recorder records the inputs and outputs of every component call reported through callbacks, including graphs and their nodes.
+// Streams are read from the copies given to the handler, and the entry is saved after the streams are finished.
+type Recorder struct {
+	// started is called when a call starts, before its input is known if streaming, optional.
+	started func(ctx context.Context, rec *recording)
+	save    func(ctx context.Context, rec *recording) error
+
+	mu   sync.Mutex
+	seqs map[string]int
+	err  error
+}
+
+type recordingKey struct{}
+
+type recording struct {
+	entry *Entry
+
+	runID   string
+	startAt time.Time
+
+	// chunks and firstChunk summarize the streaming output.
+	chunks     int
+	firstChunk time.Duration
+
+	streamingInput bool
+	input          sync.WaitGroup
+}
+
+// Handler returns the callbacks handler doing the recording, pass it with compose.WithCallbacks.
+func (r *Recorder) Handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			ctx, rec := r.start(ctx, info)
+			setInput(rec.entry, input)
+			if r.started != nil {
+				r.started(ctx, rec)
+			}
+			return ctx
+		}).
+		OnStartWithStreamInputFn(func(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+			ctx, rec := r.start(ctx, info)
+			rec.streamingInput = true
+			if r.started != nil {
+				r.started(ctx, rec)
+			}
+			rec.input.Add(1)
+			go func() {
+				defer rec.input.Done()
+				chunks, _ := readAll(input, nil)
+				if rec.entry.Component == components.ComponentOfChatModel {
+					for _, c := range chunks {
+						setInput(rec.entry, c)
+					}
+					return
+				}
+				setInput(rec.entry, chunks)
+			}()
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if rec, ok := ctx.Value(recordingKey{}).(*recording); ok {
+				setOutput(rec.entry, output)
+				r.finish(ctx, rec)
+			}
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			rec, ok := ctx.Value(recordingKey{}).(*recording)
+			if !ok {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				chunks, err := readAll(output, func(int) {
+					if rec.chunks == 0 {
+						rec.firstChunk = time.Since(rec.startAt)
+					}
+					rec.chunks++
+				})
+				setStreamOutput(rec.entry, chunks)
+				if err != nil && rec.entry.Error == "" {
+					rec.entry.Error = err.Error()
+				}
+				r.finish(ctx, rec)
+			}()
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if rec, ok := ctx.Value(recordingKey{}).(*recording); ok {
+				rec.entry.Error = err.Error()
+				r.finish(ctx, rec)
+			}
+			return ctx
+		}).
+		Build()
+}
+
+// Err returns the first error met when saving the entries.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) start(ctx context.Context, info *callbacks.RunInfo) (context.Context, *recording) {
+	entry := &Entry{
+		Address: compose.GetCurrentAddress(ctx).String(),
+	}
+	if info != nil {
+		entry.Component = info.Component
+		entry.Type = info.Type
+		entry.Name = info.Name
+	}
+
+	key := entryKey(entry.Address, entry.Component)
+	r.mu.Lock()
+	entry.Seq = r.seqs[key]
+	r.seqs[key]++
+	r.mu.Unlock()
+
+	rec := &recording{entry: entry, startAt: time.Now()}
+	if parent, ok := ctx.Value(recordingKey{}).(*recording); ok {
+		rec.runID = parent.runID
+	} else {
+		rec.runID = uuid.NewString()
+	}
+	return context.WithValue(ctx, recordingKey{}, rec), rec
+}
+
+func (r *Recorder) finish(ctx context.Context, rec *recording) {
+	save := func() {
+		if err := r.save(ctx, rec); err != nil {
+			r.mu.Lock()
+			if r.err == nil {
+				r.err = err
+			}
+			r.mu.Unlock()
+		}
+	}
+
+	if rec.streamingInput {
+		// the input may still be streaming, don't block the callback
+		go func() {
+			rec.input.Wait()
+			save()
+		}()
+		return
+	}
+	save()
+}
+
+func setInput(e *Entry, input callbacks.CallbackInput) {
+	switch e.Component {
+	case components.ComponentOfChatModel:
+		if in := model.ConvCallbackInput(input); in != nil {
+			e.Messages = append(e.Messages, in.Messages...)
+			return
+		}
+	case components.ComponentOfTool:
+		if in := tool.ConvCallbackInput(input); in != nil {
+			e.Arguments += in.ArgumentsInJSON
+			return
+		}
+	}
+	e.Input = input
+}
+
+func setOutput(e *Entry, output callbacks.CallbackOutput) {
+	switch e.Component {
+	case components.ComponentOfChatModel:
+		if out := model.ConvCallbackOutput(output); out != nil {
+			e.Message = out.Message
+			return
+		}
+	case components.ComponentOfTool:
+		if out := tool.ConvCallbackOutput(output); out != nil {
+			e.Result = out.Response
+			return
+		}
+	}
+	e.Output = output
+}
+
+func setStreamOutput(e *Entry, chunks []callbacks.CallbackOutput) {
+	e.IsStream = true
+	switch e.Component {
+	case components.ComponentOfChatModel:
+		for _, c := range chunks {
+			if out := model.ConvCallbackOutput(c); out != nil && out.Message != nil {
+				e.Chunks = append(e.Chunks, out.Message)
+			}
+		}
+		if len(e.Chunks) > 0 {
+			msg, err := schema.ConcatMessages(e.Chunks)
+			if err != nil {
+				e.Error = err.Error()
+				return
+			}
+			e.Message = msg
+		}
+		return
+	case components.ComponentOfTool:
+		var sb strings.Builder
+		for _, c := range chunks {
+			if out := tool.ConvCallbackOutput(c); out != nil {
+				e.ResultChunks = append(e.ResultChunks, out.Response)
+				sb.WriteString(out.Response)
+			}
+		}
+		e.Result = sb.String()
+		return
+	}
+
+	out := make([]any, 0, len(chunks))
+	for _, c := range chunks {
+		out = append(out, c)
+	}
+	e.Output = out
+}
+
+// readAll reads the stream to the end, calling onChunk if not nil for every chunk received.
+func readAll[T any](sr *schema.StreamReader[T], onChunk func(idx int)) ([]T, error) {
+	defer sr.Close()
+	var chunks []T
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		if onChunk != nil {
+			onChunk(len(chunks))
+		}
+		chunks = append(chunks, chunk)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type scriptedModel struct {
+	calls int
+}
+
+func (m *scriptedModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.calls++
+	if input[len(input)-1].Role == schema.Tool {
+		return schema.AssistantMessage("weather: "+input[len(input)-1].Content, nil), nil
+	}
+	return schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "call_1",
+		Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"bj"}`},
+	}}), nil
+}
+
+func (m *scriptedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *scriptedModel) BindTools([]*schema.ToolInfo) error { return nil }
+
+type failingModel struct{}
+
+func (failingModel) Generate(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+	return nil, errors.New("real model called")
+}
+
+func (failingModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("real model called")
+}
+
+func (failingModel) BindTools([]*schema.ToolInfo) error { return nil }
+
+type weatherTool struct {
+	result string
+	calls  int
+}
+
+func (w *weatherTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "weather", Desc: "get weather"}, nil
+}
+
+func (w *weatherTool) InvokableRun(context.Context, string, ...tool.Option) (string, error) {
+	w.calls++
+	return w.result, nil
+}
+
+func buildAgent(t *testing.T, cm model.BaseChatModel, tl tool.BaseTool, mws ...compose.ToolMiddleware) compose.Runnable[[]*schema.Message, *schema.Message] {
+	ctx := context.Background()
+	tn, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{
+		Tools:               []tool.BaseTool{tl},
+		ToolCallMiddlewares: mws,
+	})
+	assert.NoError(t, err)
+
+	g := compose.NewGraph[[]*schema.Message, *schema.Message](compose.WithGenLocalState(func(context.Context) *[]*schema.Message {
+		return &[]*schema.Message{}
+	}))
+	appendHistory := func(ctx context.Context, in []*schema.Message, state *[]*schema.Message) ([]*schema.Message, error) {
+		*state = append(*state, in...)
+		return *state, nil
+	}
+	assert.NoError(t, g.AddChatModelNode("model", cm, compose.WithStatePreHandler(appendHistory),
+		compose.WithStatePostHandler(func(ctx context.Context, out *schema.Message, state *[]*schema.Message) (*schema.Message, error) {
+			*state = append(*state, out)
+			return out, nil
+		})))
+	assert.NoError(t, g.AddToolsNode("tools", tn))
+	assert.NoError(t, g.AddEdge(compose.START, "model"))
+	assert.NoError(t, g.AddBranch("model", compose.NewGraphBranch(func(ctx context.Context, msg *schema.Message) (string, error) {
+		if len(msg.ToolCalls) > 0 {
+			return "tools", nil
+		}
+		return compose.END, nil
+	}, map[string]bool{"tools": true, compose.END: true})))
+	assert.NoError(t, g.AddEdge("tools", "model"))
+
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	return r
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("weather in bj?")}
+
+	cm := &scriptedModel{}
+	wt := &weatherTool{result: "sunny"}
+	store := NewMemoryStore()
+	rec := NewRecorder(store)
+
+	out, err := buildAgent(t, cm, wt).Invoke(ctx, input, compose.WithCallbacks(rec.Handler()))
+	assert.NoError(t, err)
+	assert.NoError(t, rec.Err())
+	assert.Equal(t, "weather: sunny", out.Content)
+	assert.Equal(t, 2, cm.calls)
+	assert.Equal(t, 1, wt.calls)
+
+	var models, tools int
+	for _, e := range store.Entries() {
+		switch e.Component {
+		case "ChatModel":
+			assert.Equal(t, models, e.Seq)
+			models++
+		case "Tool":
+			assert.Equal(t, `{"city":"bj"}`, e.Arguments)
+			assert.Equal(t, "sunny", e.Result)
+			tools++
+		}
+	}
+	assert.Equal(t, 2, models)
+	assert.Equal(t, 1, tools)
+
+	// persist and load the entries
+	data, err := json.Marshal(store.Entries())
+	assert.NoError(t, err)
+	var loaded []*Entry
+	assert.NoError(t, json.Unmarshal(data, &loaded))
+
+	t.Run("invoke", func(t *testing.T) {
+		p, err := NewPlayer(ctx, NewMemoryStore(loaded...))
+		assert.NoError(t, err)
+		wt2 := &weatherTool{result: "rainy"}
+		r := buildAgent(t, model.WrapChatModel(failingModel{}, p.ChatModelMiddleware()), wt2, p.ToolMiddleware())
+
+		out, err := r.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "weather: sunny", out.Content)
+		assert.Equal(t, 0, wt2.calls)
+
+		// calls beyond the recording fail
+		_, err = r.Invoke(ctx, input)
+		assert.ErrorIs(t, err, ErrNotRecorded)
+
+		p.Reset()
+		out, err = r.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "weather: sunny", out.Content)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		p, err := NewPlayer(ctx, NewMemoryStore(loaded...))
+		assert.NoError(t, err)
+		r := buildAgent(t, model.WrapChatModel(failingModel{}, p.ChatModelMiddleware()), &weatherTool{}, p.ToolMiddleware())
+
+		sr, err := r.Stream(ctx, input)
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "weather: sunny", out.Content)
+	})
+}
+
+func TestRecordStreamAndError(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	rec := NewRecorder(store)
+
+	g := compose.NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("upper", compose.StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{in, "!"}), nil
+	})))
+	assert.NoError(t, g.AddEdge(compose.START, "upper"))
+	assert.NoError(t, g.AddEdge("upper", compose.END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	sr, err := r.Stream(ctx, "hi", compose.WithCallbacks(rec.Handler()))
+	assert.NoError(t, err)
+	for {
+		if _, err = sr.Recv(); err != nil {
+			break
+		}
+	}
+	sr.Close()
+
+	assert.Eventually(t, func() bool { return len(store.Entries()) == 2 }, time.Second, 10*time.Millisecond)
+	for _, e := range store.Entries() {
+		if e.Component == compose.ComponentOfLambda {
+			assert.True(t, e.IsStream)
+			assert.Equal(t, []any{"hi", "!"}, e.Output)
+			assert.Equal(t, "hi", e.Input)
+		}
+	}
+
+	p, err := NewPlayer(ctx, NewMemoryStore(&Entry{Component: "ChatModel", Error: "rate limited"}))
+	assert.NoError(t, err)
+	_, err = model.WrapChatModel(failingModel{}, p.ChatModelMiddleware()).Generate(ctx, nil)
+	assert.EqualError(t, err, "rate limited")
+}
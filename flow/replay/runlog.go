@@ -0,0 +1,215 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+)
+
+// Kinds of the run log events.
+const (
+	RunLogEventStart = "start"
+	RunLogEventEnd   = "end"
+	RunLogEventError = "error"
+)
+
+// RunLogEvent is one line of the run log written by RunLogger.
+// The end and error events carry the complete Entry of the call, so the log can be loaded back by LoadRunLog and replayed.
+type RunLogEvent struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	// RunID identifies the outermost run the call belongs to.
+	RunID string `json:"run_id"`
+
+	// DurationMS is the duration of the call in milliseconds, set on end and error events.
+	// For streaming output, it lasts until the stream is finished.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// ChunkCount is the number of the output chunks if streaming.
+	ChunkCount int `json:"chunk_count,omitempty"`
+	// FirstChunkMS is the latency in milliseconds of the first output chunk if streaming.
+	FirstChunkMS int64 `json:"first_chunk_ms,omitempty"`
+
+	*Entry
+}
+
+// RunLoggerOption is the option for NewRunLogger.
+type RunLoggerOption func(*RunLogger)
+
+// WithRotation rotates the writer when writing the next line would exceed maxBytes in the current writer.
+// rotate is given the current writer, it should close the writer if needed and return the new one.
+func WithRotation(maxBytes int64, rotate func(current io.Writer) (io.Writer, error)) RunLoggerOption {
+	return func(l *RunLogger) {
+		l.maxBytes = maxBytes
+		l.rotate = rotate
+	}
+}
+
+// WithoutStreamChunks drops the individual chunks of streaming output from the log, keeping only the concatenated output
+// and the chunk summary. The log can still be replayed, with the streams replayed as single chunks.
+func WithoutStreamChunks() RunLoggerOption {
+	return func(l *RunLogger) {
+		l.dropChunks = true
+	}
+}
+
+// NewRunLogger creates a RunLogger writing to w.
+func NewRunLogger(w io.Writer, opts ...RunLoggerOption) *RunLogger {
+	l := &RunLogger{w: w}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.rec = &Recorder{
+		started: l.started,
+		save:    l.finished,
+		seqs:    make(map[string]int),
+	}
+	return l
+}
+
+// RunLogger writes one JSON line per component call event to an io.Writer, see RunLogEvent.
+// Calls are identified in the same way as Recorder, so the log can feed the Player through LoadRunLog.
+type RunLogger struct {
+	rec *Recorder
+
+	maxBytes   int64
+	rotate     func(io.Writer) (io.Writer, error)
+	dropChunks bool
+
+	mu      sync.Mutex
+	w       io.Writer
+	written int64
+}
+
+// Handler returns the callbacks handler writing the log, pass it with compose.WithCallbacks.
+func (l *RunLogger) Handler() callbacks.Handler {
+	return l.rec.Handler()
+}
+
+// Err returns the first error met when writing the log.
+func (l *RunLogger) Err() error {
+	return l.rec.Err()
+}
+
+func (l *RunLogger) started(_ context.Context, rec *recording) {
+	entry := *rec.entry
+	err := l.write(&RunLogEvent{
+		Event: RunLogEventStart,
+		Time:  rec.startAt,
+		RunID: rec.runID,
+		Entry: &entry,
+	})
+	if err != nil {
+		l.rec.mu.Lock()
+		if l.rec.err == nil {
+			l.rec.err = err
+		}
+		l.rec.mu.Unlock()
+	}
+}
+
+func (l *RunLogger) finished(_ context.Context, rec *recording) error {
+	entry := *rec.entry
+	if l.dropChunks {
+		entry.Chunks = nil
+		entry.ResultChunks = nil
+	}
+
+	event := &RunLogEvent{
+		Event:      RunLogEventEnd,
+		Time:       time.Now(),
+		RunID:      rec.runID,
+		DurationMS: time.Since(rec.startAt).Milliseconds(),
+		ChunkCount: rec.chunks,
+		Entry:      &entry,
+	}
+	if rec.chunks > 0 {
+		event.FirstChunkMS = rec.firstChunk.Milliseconds()
+	}
+	if entry.Error != "" {
+		event.Event = RunLogEventError
+	}
+	return l.write(event)
+}
+
+func (l *RunLogger) write(event *RunLogEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		// the informational input and output may be not marshallable, keep their string form instead
+		entry := *event.Entry
+		if entry.Input != nil {
+			entry.Input = fmt.Sprintf("%+v", entry.Input)
+		}
+		if entry.Output != nil {
+			entry.Output = fmt.Sprintf("%+v", entry.Output)
+		}
+		event.Entry = &entry
+		if line, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("marshal run log event fail: %w", err)
+		}
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rotate != nil && l.written > 0 && l.written+int64(len(line)) > l.maxBytes {
+		w, err := l.rotate(l.w)
+		if err != nil {
+			return fmt.Errorf("rotate run log fail: %w", err)
+		}
+		l.w = w
+		l.written = 0
+	}
+	n, err := l.w.Write(line)
+	l.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("write run log fail: %w", err)
+	}
+	return nil
+}
+
+// LoadRunLog reads the run log written by RunLogger and returns the entries of the finished calls in a MemoryStore,
+// which can be given to NewPlayer. Logs split by rotation can be loaded together with io.MultiReader.
+func LoadRunLog(r io.Reader) (*MemoryStore, error) {
+	store := NewMemoryStore()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		event := &RunLogEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			return nil, fmt.Errorf("unmarshal run log line %d fail: %w", line, err)
+		}
+		if event.Event == RunLogEventStart || event.Entry == nil {
+			continue
+		}
+		_ = store.Save(context.Background(), event.Entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read run log fail: %w", err)
+	}
+	return store, nil
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte{}, b.buf.Bytes()...)
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func readRunLog(t *testing.T, data []byte) []*RunLogEvent {
+	var events []*RunLogEvent
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		e := &RunLogEvent{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestRunLogger(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("weather in bj?")}
+
+	t.Run("record and replay", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		l := NewRunLogger(buf)
+
+		out, err := buildAgent(t, &scriptedModel{}, &weatherTool{result: "sunny"}).Invoke(ctx, input, compose.WithCallbacks(l.Handler()))
+		assert.NoError(t, err)
+		assert.NoError(t, l.Err())
+		assert.Equal(t, "weather: sunny", out.Content)
+
+		events := readRunLog(t, buf.Bytes())
+		var starts, ends int
+		runID := events[0].RunID
+		for _, e := range events {
+			assert.Equal(t, runID, e.RunID)
+			switch e.Event {
+			case RunLogEventStart:
+				starts++
+			case RunLogEventEnd:
+				ends++
+			}
+		}
+		// graph, 2 model calls, tools node and 1 tool call
+		assert.Equal(t, 5, starts)
+		assert.Equal(t, 5, ends)
+
+		store, err := LoadRunLog(bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+		assert.Len(t, store.Entries(), 5)
+
+		p, err := NewPlayer(ctx, store)
+		assert.NoError(t, err)
+		wt := &weatherTool{result: "rainy"}
+		out, err = buildAgent(t, model.WrapChatModel(failingModel{}, p.ChatModelMiddleware()), wt, p.ToolMiddleware()).Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "weather: sunny", out.Content)
+		assert.Equal(t, 0, wt.calls)
+	})
+
+	t.Run("stream summary and error", func(t *testing.T) {
+		g := compose.NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("upper", compose.StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+			if in == "" {
+				return nil, assert.AnError
+			}
+			return schema.StreamReaderFromArray([]string{in, "!"}), nil
+		})))
+		assert.NoError(t, g.AddEdge(compose.START, "upper"))
+		assert.NoError(t, g.AddEdge("upper", compose.END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		buf := &syncBuffer{}
+		l := NewRunLogger(buf, WithoutStreamChunks())
+		sr, err := r.Stream(ctx, "hi", compose.WithCallbacks(l.Handler()))
+		assert.NoError(t, err)
+		for {
+			if _, err = sr.Recv(); err != nil {
+				break
+			}
+		}
+		sr.Close()
+
+		var lambda *RunLogEvent
+		assert.Eventually(t, func() bool {
+			for _, e := range readRunLog(t, buf.Bytes()) {
+				if e.Event == RunLogEventEnd && e.Component == compose.ComponentOfLambda {
+					lambda = e
+				}
+			}
+			return lambda != nil
+		}, time.Second, 10*time.Millisecond)
+		assert.True(t, lambda.IsStream)
+		assert.Equal(t, 2, lambda.ChunkCount)
+
+		buf.Reset()
+		_, err = r.Invoke(ctx, "", compose.WithCallbacks(l.Handler()))
+		assert.Error(t, err)
+		var errs int
+		for _, e := range readRunLog(t, buf.Bytes()) {
+			if e.Event == RunLogEventError {
+				assert.Contains(t, e.Error, assert.AnError.Error())
+				errs++
+			}
+		}
+		assert.Equal(t, 2, errs)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		var files []*bytes.Buffer
+		first := &bytes.Buffer{}
+		files = append(files, first)
+		l := NewRunLogger(first, WithRotation(1, func(io.Writer) (io.Writer, error) {
+			b := &bytes.Buffer{}
+			files = append(files, b)
+			return b, nil
+		}))
+
+		_, err := buildAgent(t, &scriptedModel{}, &weatherTool{result: "sunny"}).Invoke(ctx, input, compose.WithCallbacks(l.Handler()))
+		assert.NoError(t, err)
+		assert.NoError(t, l.Err())
+		// every line goes to its own writer
+		assert.Len(t, files, 10)
+
+		readers := make([]io.Reader, 0, len(files))
+		for _, f := range files {
+			readers = append(readers, f)
+		}
+		store, err := LoadRunLog(io.MultiReader(readers...))
+		assert.NoError(t, err)
+		assert.Len(t, store.Entries(), 5)
+	})
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package replay records the inputs and outputs of the components during a graph run,
+// and replays the recorded outputs of chat models and tools in later runs,
+// so that the graph logic can be regression tested without calling the real models and tools.
+//
+// Record a run with the callbacks handler of a Recorder:
+//
+//	store := replay.NewMemoryStore()
+//	rec := replay.NewRecorder(store)
+//	out, err := runnable.Invoke(ctx, input, compose.WithCallbacks(rec.Handler()))
+//
+// Then build the same graph with the chat models and tools node wrapped by a Player:
+//
+//	p, err := replay.NewPlayer(ctx, store)
+//	cm := model.WrapChatModel(realModel, p.ChatModelMiddleware())
+//	toolsNode, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{
+//		Tools:               tools,
+//		ToolCallMiddlewares: []compose.ToolMiddleware{p.ToolMiddleware()},
+//	})
+//
+// A RunLogger writes the calls as JSON lines to an io.Writer instead, for offline analysis,
+// and the log can be loaded back for replay with LoadRunLog.
+package replay
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Entry is the record of one component call.
+// Calls are identified by the address of the execution point, see compose.GetCurrentAddress,
+// the component kind and the sequence number of the calls with the same address and component.
+type Entry struct {
+	// Address is the string form of the address where the call happened.
+	Address string `json:"address"`
+	// Seq is the sequence number of the call among the calls with the same Address and Component, starting from 0.
+	Seq int `json:"seq"`
+
+	Component components.Component `json:"component"`
+	Type      string               `json:"type,omitempty"`
+	Name      string               `json:"name,omitempty"`
+
+	// IsStream reports whether the output is a stream.
+	IsStream bool `json:"is_stream,omitempty"`
+
+	// Messages is the input messages of chat models.
+	Messages []*schema.Message `json:"messages,omitempty"`
+	// Message is the output message of chat models, concatenated if streaming.
+	Message *schema.Message `json:"message,omitempty"`
+	// Chunks is the streaming output of chat models.
+	Chunks []*schema.Message `json:"chunks,omitempty"`
+
+	// Arguments is the input arguments in JSON of tools.
+	Arguments string `json:"arguments,omitempty"`
+	// Result is the output of tools, concatenated if streaming.
+	Result string `json:"result,omitempty"`
+	// ResultChunks is the streaming output of tools.
+	ResultChunks []string `json:"result_chunks,omitempty"`
+
+	// Input and Output are the callback input and output of other components, the chunks are kept in slice if streaming.
+	// They are informational only and are not used by replay.
+	Input  any `json:"input,omitempty"`
+	Output any `json:"output,omitempty"`
+
+	// Error is the error message if the call failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Store saves and loads the recorded entries.
+type Store interface {
+	Save(ctx context.Context, entry *Entry) error
+	Load(ctx context.Context) ([]*Entry, error)
+}
+
+// NewMemoryStore creates a Store keeping the entries in memory, optionally with the entries loaded elsewhere,
+// e.g. unmarshalled from a JSON file.
+func NewMemoryStore(entries ...*Entry) *MemoryStore {
+	return &MemoryStore{entries: entries}
+}
+
+// MemoryStore is a Store keeping the entries in memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+// Save implements Store.
+func (m *MemoryStore) Save(_ context.Context, entry *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// Load implements Store.
+func (m *MemoryStore) Load(_ context.Context) ([]*Entry, error) {
+	return m.Entries(), nil
+}
+
+// Entries returns the saved entries ordered by address, component and sequence number.
+func (m *MemoryStore) Entries() []*Entry {
+	m.mu.Lock()
+	ret := make([]*Entry, len(m.entries))
+	copy(ret, m.entries)
+	m.mu.Unlock()
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Address != ret[j].Address {
+			return ret[i].Address < ret[j].Address
+		}
+		if ret[i].Component != ret[j].Component {
+			return ret[i].Component < ret[j].Component
+		}
+		return ret[i].Seq < ret[j].Seq
+	})
+	return ret
+}
+
+func entryKey(address string, component components.Component) string {
+	return address + "|" + string(component)
+}
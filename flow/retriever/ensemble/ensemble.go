@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/retriever/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config is the config for ensemble retriever.
+type Config struct {
+	// Retrievers is the retrievers to be ensembled, e.g. a vector retriever and a full-text retriever, required.
+	Retrievers []retriever.Retriever
+	// Weights is the weight of each retriever in the fusion, in the same order as Retrievers.
+	// Optional. Each retriever weighs 1 by default.
+	Weights []float64
+	// RRFConstant is the constant k of reciprocal rank fusion, 60 by default.
+	RRFConstant int
+	// TopK limits the number of fused documents, 0 means no limit.
+	TopK int
+}
+
+// NewRetriever creates an ensemble retriever.
+// ensemble retriever retrieves documents from all the retrievers with the same query concurrently,
+// and merges the results by weighted reciprocal rank fusion.
+// eg.
+//
+//	ensembleRetriever, err := ensemble.NewRetriever(ctx, &ensemble.Config{
+//		Retrievers: []retriever.Retriever{vectorRetriever, esRetriever},
+//		Weights:    []float64{0.7, 0.3},
+//	})
+//	docs, err := ensembleRetriever.Retrieve(ctx, "how to build agent with eino")
+func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, error) {
+	if config == nil || len(config.Retrievers) == 0 {
+		return nil, fmt.Errorf("retrievers is empty")
+	}
+	for i, r := range config.Retrievers {
+		if r == nil {
+			return nil, fmt.Errorf("retriever[%d] is nil", i)
+		}
+	}
+	if len(config.Weights) > 0 && len(config.Weights) != len(config.Retrievers) {
+		return nil, fmt.Errorf("weights length[%d] mismatches retrievers length[%d]", len(config.Weights), len(config.Retrievers))
+	}
+	for i, w := range config.Weights {
+		if w < 0 {
+			return nil, fmt.Errorf("weight[%d] is negative: %f", i, w)
+		}
+	}
+	if config.TopK < 0 {
+		return nil, fmt.Errorf("top k is negative: %d", config.TopK)
+	}
+
+	return &ensembleRetriever{
+		retrievers:  config.Retrievers,
+		weights:     config.Weights,
+		rrfConstant: config.RRFConstant,
+		topK:        config.TopK,
+	}, nil
+}
+
+type ensembleRetriever struct {
+	retrievers  []retriever.Retriever
+	weights     []float64
+	rrfConstant int
+	topK        int
+}
+
+// Retrieve retrieves documents from the ensemble retriever.
+func (e *ensembleRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	tasks := make([]*utils.RetrieveTask, len(e.retrievers))
+	for i := range e.retrievers {
+		tasks[i] = &utils.RetrieveTask{
+			Name:            fmt.Sprintf("%d", i),
+			Retriever:       e.retrievers[i],
+			Query:           query,
+			RetrieveOptions: opts,
+		}
+	}
+	utils.ConcurrentRetrieveWithCallback(ctx, tasks)
+	result := make([][]*schema.Document, len(tasks))
+	for i := range tasks {
+		if tasks[i].Err != nil {
+			return nil, tasks[i].Err
+		}
+		result[i] = tasks[i].Result
+	}
+
+	// fusion
+	fusionCtx := ctxWithFusionRunInfo(ctx)
+	fusionCtx = callbacks.OnStart(fusionCtx, result)
+	fusionDocs := utils.ReciprocalRankFusion(result, e.weights, e.rrfConstant)
+	if e.topK > 0 && len(fusionDocs) > e.topK {
+		fusionDocs = fusionDocs[:e.topK]
+	}
+	callbacks.OnEnd(fusionCtx, fusionDocs)
+	return fusionDocs, nil
+}
+
+// GetType returns the type of the retriever (Ensemble).
+func (e *ensembleRetriever) GetType() string { return "Ensemble" }
+
+func ctxWithFusionRunInfo(ctx context.Context) context.Context {
+	runInfo := &callbacks.RunInfo{
+		Component: compose.ComponentOfLambda,
+		Type:      "FusionFunc",
+	}
+
+	runInfo.Name = runInfo.Type + string(runInfo.Component)
+
+	return callbacks.ReuseHandlers(ctx, runInfo)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ensemble
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockRetriever []string
+
+func (m mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, 0, len(m))
+	for _, id := range m {
+		ret = append(ret, &schema.Document{ID: id})
+	}
+	return ret, nil
+}
+
+func ids(docs []*schema.Document) []string {
+	ret := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ret = append(ret, doc.ID)
+	}
+	return ret
+}
+
+func TestEnsembleRetriever(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rrf", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{
+			Retrievers: []retriever.Retriever{mockRetriever{"a", "b", "c"}, mockRetriever{"c", "b", "d"}},
+		})
+		assert.NoError(t, err)
+		cr, err := compose.NewChain[string, []*schema.Document]().AppendRetriever(r).Compile(ctx)
+		assert.NoError(t, err)
+		docs, err := cr.Invoke(ctx, "query")
+		assert.NoError(t, err)
+		// b and c are recalled by both retrievers, b ranks 2 in both while c ranks 3 and 1
+		assert.Equal(t, []string{"c", "b", "a", "d"}, ids(docs))
+	})
+
+	t.Run("weights and top k", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{
+			Retrievers: []retriever.Retriever{mockRetriever{"a", "b", "c"}, mockRetriever{"c", "b", "d"}},
+			Weights:    []float64{0, 1},
+			TopK:       2,
+		})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c", "b"}, ids(docs))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRetriever(ctx, &Config{})
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{
+			Retrievers: []retriever.Retriever{mockRetriever{"a"}},
+			Weights:    []float64{1, 2},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -54,6 +54,12 @@ var deduplicateFusion = func(ctx context.Context, docs [][]*schema.Document) ([]
 	return ret, nil
 }
 
+// RRFFusion is a FusionFunc ranking the documents recalled by the queries with reciprocal rank fusion,
+// so that documents recalled by more queries and at higher ranks come first.
+func RRFFusion(ctx context.Context, docs [][]*schema.Document) ([]*schema.Document, error) {
+	return utils.ReciprocalRankFusion(docs, nil, utils.DefaultRRFConstant), nil
+}
+
 // NewRetriever creates a multi-query retriever.
 // multi-query retriever is useful when you want to retrieve documents from multiple retrievers with different queries.
 // eg.
@@ -146,7 +152,8 @@ type Config struct {
 	// Origin Retriever
 	OrigRetriever retriever.Retriever
 
-	// fusion docs recalled from multi retrievers, remove dup based on document id by default
+	// fusion docs recalled from multi retrievers, remove dup based on document id by default.
+	// use RRFFusion to rank the docs by reciprocal rank fusion.
 	FusionFunc func(ctx context.Context, docs [][]*schema.Document) ([]*schema.Document, error)
 }
 
@@ -171,7 +178,7 @@ func (m *multiQueryRetriever) Retrieve(ctx context.Context, query string, opts .
 	// retrieve
 	tasks := make([]*utils.RetrieveTask, len(queries))
 	for i := range queries {
-		tasks[i] = &utils.RetrieveTask{Retriever: m.origRetriever, Query: queries[i]}
+		tasks[i] = &utils.RetrieveTask{Retriever: m.origRetriever, Query: queries[i], RetrieveOptions: opts}
 	}
 	utils.ConcurrentRetrieveWithCallback(ctx, tasks)
 	result := make([][]*schema.Document, len(queries))
@@ -115,4 +115,23 @@ func TestMultiQueryRetriever(t *testing.T) {
 	if len(result) != 3 {
 		t.Fatal("default llm retrieve result is unexpected")
 	}
+
+	// use rrf fusion
+	mqr, err = NewRetriever(ctx, &Config{
+		RewriteHandler: func(ctx context.Context, query string) ([]string, error) {
+			return []string{"12", "23", "234"}, nil
+		},
+		OrigRetriever: &mockRetriever{},
+		FusionFunc:    RRFFusion,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = mqr.Retrieve(ctx, "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 4 || result[0].ID != "2" || result[1].ID != "3" {
+		t.Fatal("rrf fusion retrieve result is unexpected")
+	}
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+// Config is the config for parent retriever.
 type Config struct {
 	// Retriever specifies the original retriever used to retrieve documents.
 	// For example: a vector database retriever like Milvus, or a full-text search retriever like Elasticsearch.
@@ -70,6 +71,9 @@ func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, err
 	if config.Retriever == nil {
 		return nil, fmt.Errorf("retriever is required")
 	}
+	if len(config.ParentIDKey) == 0 {
+		return nil, fmt.Errorf("parent id key is required")
+	}
 	if config.OrigDocGetter == nil {
 		return nil, fmt.Errorf("orig doc getter is required")
 	}
@@ -102,6 +106,9 @@ func (p *parentRetriever) Retrieve(ctx context.Context, query string, opts ...re
 	return p.origDocGetter(ctx, ids)
 }
 
+// GetType returns the type of the retriever (Parent).
+func (p *parentRetriever) GetType() string { return "Parent" }
+
 func inList(elem string, list []string) bool {
 	for _, v := range list {
 		if v == elem {
@@ -19,6 +19,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/cloudwego/eino/callbacks"
@@ -81,3 +82,39 @@ func ctxWithRetrieverRunInfo(ctx context.Context, r retriever.Retriever) context
 
 	return callbacks.ReuseHandlers(ctx, runInfo)
 }
+
+// DefaultRRFConstant is the default constant k of ReciprocalRankFusion, as suggested by the original paper.
+const DefaultRRFConstant = 60
+
+// ReciprocalRankFusion merges the ranked document lists by weighted reciprocal rank fusion,
+// i.e. a document scores sum(weights[i] / (k + rank_i)) over the lists containing it, where rank starts from 1.
+// Documents are identified by ID, the first occurrence is kept. weights can be nil, meaning 1 for every list.
+// Documents with the same score keep the order they first appear.
+func ReciprocalRankFusion(lists [][]*schema.Document, weights []float64, k int) []*schema.Document {
+	if k <= 0 {
+		k = DefaultRRFConstant
+	}
+
+	scores := make(map[string]float64)
+	var ret []*schema.Document
+	for i, docs := range lists {
+		weight := 1.0
+		if i < len(weights) {
+			weight = weights[i]
+		}
+		for rank, doc := range docs {
+			if doc == nil {
+				continue
+			}
+			if _, ok := scores[doc.ID]; !ok {
+				ret = append(ret, doc)
+			}
+			scores[doc.ID] += weight / float64(k+rank+1)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return scores[ret[i].ID] > scores[ret[j].ID]
+	})
+	return ret
+}
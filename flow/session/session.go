@@ -0,0 +1,184 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package session manages the sessions of multi-turn conversations, i.e. the transcripts and the custom states keyed by the session ID,
+// which are loaded, updated and saved atomically per session by Manager.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type options struct {
+	ttl time.Duration
+	now func() time.Time
+}
+
+// Option is the option of Manager.
+type Option func(*options)
+
+// WithTTL expires the sessions not updated for the ttl, which are started over on the next access.
+// By default, the sessions never expire.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// Manager runs the updates of the sessions in the store, one at a time per session.
+type Manager struct {
+	store Store
+	opts  options
+	locks keyedMutex
+}
+
+// NewManager creates a Manager of the sessions in the store.
+func NewManager(store Store, opts ...Option) (*Manager, error) {
+	if store == nil {
+		return nil, errors.New("session store is required")
+	}
+
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ttl < 0 {
+		return nil, fmt.Errorf("session ttl must not be negative, got %s", o.ttl)
+	}
+
+	return &Manager{store: store, opts: o, locks: keyedMutex{locks: make(map[string]*keyLock)}}, nil
+}
+
+// Run loads the session, calls fn with it, and saves it if fn succeeds, while holding the lock of the session,
+// so that the concurrent runs of the same session are serialized, and the runs of different sessions are concurrent.
+// A new session is passed to fn if the session doesn't exist or has expired.
+// If fn fails, the session isn't saved, so fn can modify it freely and leave the store intact by failing.
+// Waiting for the lock is canceled along with ctx.
+func (m *Manager) Run(ctx context.Context, sessionID string, fn func(ctx context.Context, s *Session) error) error {
+	if sessionID == "" {
+		return errors.New("session id is required")
+	}
+
+	unlock, err := m.locks.lock(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("lock session[%s] fail: %w", sessionID, err)
+	}
+	defer unlock()
+
+	s, err := m.load(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(ctx, s); err != nil {
+		return err
+	}
+
+	s.ID = sessionID
+	s.UpdatedAt = m.opts.now()
+	if err = m.store.Set(ctx, sessionID, s); err != nil {
+		return fmt.Errorf("save session[%s] fail: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Get returns the session, or false if it doesn't exist or has expired.
+func (m *Manager) Get(ctx context.Context, sessionID string) (*Session, bool, error) {
+	s, ok, err := m.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, false, fmt.Errorf("load session[%s] fail: %w", sessionID, err)
+	}
+	if !ok || m.expired(s) {
+		return nil, false, nil
+	}
+	return s, true, nil
+}
+
+// Delete deletes the session, waiting for the running update of it if any.
+func (m *Manager) Delete(ctx context.Context, sessionID string) error {
+	unlock, err := m.locks.lock(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("lock session[%s] fail: %w", sessionID, err)
+	}
+	defer unlock()
+
+	if err = m.store.Delete(ctx, sessionID); err != nil {
+		return fmt.Errorf("delete session[%s] fail: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (m *Manager) load(ctx context.Context, sessionID string) (*Session, error) {
+	s, ok, err := m.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("load session[%s] fail: %w", sessionID, err)
+	}
+	if ok && !m.expired(s) {
+		return s, nil
+	}
+	return &Session{ID: sessionID, CreatedAt: m.opts.now()}, nil
+}
+
+func (m *Manager) expired(s *Session) bool {
+	return m.opts.ttl > 0 && m.opts.now().Sub(s.UpdatedAt) > m.opts.ttl
+}
+
+// keyedMutex is a set of mutexes keyed by string, whose entries are removed once unlocked by all the holders and waiters.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+func (k *keyedMutex) lock(ctx context.Context, key string) (unlock func(), err error) {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{ch: make(chan struct{}, 1)}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	release := func() {
+		k.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+
+	select {
+	case l.ch <- struct{}{}:
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-l.ch
+		release()
+	}, nil
+}
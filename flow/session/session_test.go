@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewManager(nil)
+	assert.Error(t, err)
+	_, err = NewManager(NewMemoryStore(), WithTTL(-time.Second))
+	assert.Error(t, err)
+
+	t.Run("run", func(t *testing.T) {
+		m, err := NewManager(NewMemoryStore())
+		require.NoError(t, err)
+
+		assert.Error(t, m.Run(ctx, "", func(ctx context.Context, s *Session) error { return nil }))
+
+		err = m.Run(ctx, "s1", func(ctx context.Context, s *Session) error {
+			assert.Empty(t, s.Messages)
+			s.Messages = append(s.Messages, schema.UserMessage("hi"))
+			s.Values = map[string]any{"turns": 1}
+			return nil
+		})
+		require.NoError(t, err)
+
+		// failed runs leave the session intact
+		err = m.Run(ctx, "s1", func(ctx context.Context, s *Session) error {
+			s.Messages = append(s.Messages, schema.UserMessage("lost"))
+			s.Values["turns"] = 2
+			return errors.New("boom")
+		})
+		assert.EqualError(t, err, "boom")
+
+		s, ok, err := m.Get(ctx, "s1")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "s1", s.ID)
+		assert.Len(t, s.Messages, 1)
+		assert.Equal(t, 1, s.Values["turns"])
+		assert.False(t, s.UpdatedAt.IsZero())
+
+		require.NoError(t, m.Delete(ctx, "s1"))
+		_, ok, err = m.Get(ctx, "s1")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("serialized", func(t *testing.T) {
+		m, err := NewManager(NewMemoryStore())
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, m.Run(ctx, fmt.Sprintf("s%d", i%2), func(ctx context.Context, s *Session) error {
+					time.Sleep(time.Millisecond)
+					s.Messages = append(s.Messages, schema.UserMessage("hi"))
+					return nil
+				}))
+			}(i)
+		}
+		wg.Wait()
+
+		for _, id := range []string{"s0", "s1"} {
+			s, ok, err := m.Get(ctx, id)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Len(t, s.Messages, 10)
+		}
+		assert.Empty(t, m.locks.locks)
+	})
+
+	t.Run("lock canceled", func(t *testing.T) {
+		m, err := NewManager(NewMemoryStore())
+		require.NoError(t, err)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			_ = m.Run(ctx, "s1", func(ctx context.Context, s *Session) error {
+				close(entered)
+				<-release
+				return nil
+			})
+		}()
+		<-entered
+
+		cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		err = m.Run(cctx, "s1", func(ctx context.Context, s *Session) error { return nil })
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(release)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		store := NewMemoryStore()
+		m, err := NewManager(store, WithTTL(time.Minute))
+		require.NoError(t, err)
+		now := time.Now()
+		m.opts.now = func() time.Time { return now }
+
+		require.NoError(t, m.Run(ctx, "s1", func(ctx context.Context, s *Session) error {
+			s.Messages = append(s.Messages, schema.UserMessage("hi"))
+			return nil
+		}))
+
+		now = now.Add(2 * time.Minute)
+		_, ok, err := m.Get(ctx, "s1")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		require.NoError(t, m.Run(ctx, "s1", func(ctx context.Context, s *Session) error {
+			assert.Empty(t, s.Messages)
+			assert.Equal(t, now, s.CreatedAt)
+			return nil
+		}))
+
+		assert.Equal(t, 0, store.Sweep(now.Add(-time.Minute)))
+		assert.Equal(t, 1, store.Sweep(now.Add(time.Second)))
+	})
+}
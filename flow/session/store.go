@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Session is the persisted state of a conversation.
+type Session struct {
+	// ID is the session ID.
+	ID string `json:"id"`
+	// Messages is the transcript of the conversation.
+	Messages []*schema.Message `json:"messages,omitempty"`
+	// Values are the custom states of the session, which must be serializable if the store persists the sessions.
+	Values map[string]any `json:"values,omitempty"`
+	// CreatedAt is the time when the session is created.
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the time when the session is saved last time, from which the TTL of Manager is counted.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists the sessions keyed by the session ID.
+// It needn't lock the sessions, which is done by Manager, but must be safe for concurrent use with different IDs.
+type Store interface {
+	Get(ctx context.Context, sessionID string) (*Session, bool, error)
+	Set(ctx context.Context, sessionID string, s *Session) error
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// MemoryStore is a Store keeping the sessions in memory, e.g. for tests or a single process service.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewMemoryStore creates a MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*Session)}
+}
+
+// Get returns a copy of the session.
+func (m *MemoryStore) Get(_ context.Context, sessionID string) (*Session, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, false, nil
+	}
+	return s.clone(), true, nil
+}
+
+// Set saves a copy of the session.
+func (m *MemoryStore) Set(_ context.Context, sessionID string, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[sessionID] = s.clone()
+	return nil
+}
+
+// Delete deletes the session, it's a no-op if the session doesn't exist.
+func (m *MemoryStore) Delete(_ context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+// Sweep deletes the sessions not updated since the given time, and returns the number of the deleted sessions.
+// Manager ignores the expired sessions on access, Sweep is to reclaim the memory of the ones never accessed again, e.g.
+//
+//	store.Sweep(time.Now().Add(-ttl))
+func (m *MemoryStore) Sweep(before time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for id, s := range m.sessions {
+		if s.UpdatedAt.Before(before) {
+			delete(m.sessions, id)
+			n++
+		}
+	}
+	return n
+}
+
+// clone copies the session, the messages and the values are shallow copied.
+func (s *Session) clone() *Session {
+	c := *s
+	if s.Messages != nil {
+		c.Messages = make([]*schema.Message, len(s.Messages))
+		copy(c.Messages, s.Messages)
+	}
+	if s.Values != nil {
+		c.Values = make(map[string]any, len(s.Values))
+		for k, v := range s.Values {
+			c.Values[k] = v
+		}
+	}
+	return &c
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package structured generates go structs with chat models.
+package structured
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/cloudwego/eino/components/model"
+	toolutils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config is the config for Generator.
+type Config struct {
+	// Model is the chat model generating the output.
+	// Models not reporting native JSON mode support are wrapped with model.ResponseFormatFallback.
+	Model model.BaseChatModel
+	// Name is the name of the output schema, default is the name of the go type.
+	Name string
+	// Description describes the expected output, optional.
+	Description string
+	// Strict requires the output to conform to the schema exactly, if supported by the model.
+	Strict bool
+	// SchemaOptions customizes the schema inferred from the go type, e.g. toolutils.WithSchemaModifier.
+	SchemaOptions []toolutils.Option
+}
+
+// Generator generates T with the chat model, constraining the output by the JSON schema inferred from T.
+// It can be used as a lambda node, e.g.
+//
+//	gen, err := structured.NewGenerator[*Weather](ctx, &structured.Config{Model: cm})
+//	chain.AppendLambda(compose.InvokableLambdaWithOption(gen.Generate))
+type Generator[T any] struct {
+	model  model.BaseChatModel
+	format *model.ResponseFormat
+	parser schema.MessageParser[T]
+}
+
+// NewGenerator creates a Generator of T.
+func NewGenerator[T any](_ context.Context, config *Config) (*Generator[T], error) {
+	if config == nil || config.Model == nil {
+		return nil, errors.New("chat model is required")
+	}
+
+	params, err := toolutils.GoStruct2ParamsOneOf[T](config.SchemaOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("infer output schema fail: %w", err)
+	}
+	js, err := params.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("infer output schema fail: %w", err)
+	}
+
+	name := config.Name
+	if name == "" {
+		name = typeName[T]()
+	}
+
+	return &Generator[T]{
+		model: model.WithResponseFormatFallback(config.Model),
+		format: &model.ResponseFormat{
+			Type:        model.ResponseFormatJSONSchema,
+			Name:        name,
+			Description: config.Description,
+			JSONSchema:  js,
+			Strict:      config.Strict,
+		},
+		parser: schema.NewMessageJSONParser[T](nil),
+	}, nil
+}
+
+// Generate calls the chat model with the response format set, and parses the output into T.
+// The response format can be overridden by passing model.WithResponseFormat in opts.
+func (g *Generator[T]) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (T, error) {
+	var zero T
+
+	opts = append([]model.Option{model.WithResponseFormat(g.format)}, opts...)
+	out, err := g.model.Generate(ctx, input, opts...)
+	if err != nil {
+		return zero, err
+	}
+
+	ret, err := g.parser.Parse(ctx, out)
+	if err != nil {
+		return zero, fmt.Errorf("parse structured output fail: %w", err)
+	}
+	return ret, nil
+}
+
+// ResponseFormat returns the response format inferred from T.
+func (g *Generator[T]) ResponseFormat() *model.ResponseFormat {
+	return g.format
+}
+
+func typeName[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return "output"
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structured
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type weather struct {
+	City        string  `json:"city" jsonschema:"required"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+type mockModel struct {
+	options *model.Options
+	input   []*schema.Message
+	jsonMod bool
+}
+
+func (m *mockModel) Generate(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.input = input
+	m.options = model.GetCommonOptions(nil, opts...)
+	return schema.AssistantMessage("```json\n{\"city\":\"Paris\",\"temperature\":21.5}\n```", nil), nil
+}
+
+func (m *mockModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	panic("implement me")
+}
+
+func (m *mockModel) Capabilities() *model.Capabilities {
+	return &model.Capabilities{JSONMode: m.jsonMod}
+}
+
+func TestGenerator(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewGenerator[*weather](ctx, &Config{})
+	assert.Error(t, err)
+
+	cm := &mockModel{}
+	gen, err := NewGenerator[*weather](ctx, &Config{Model: cm, Description: "the weather"})
+	assert.NoError(t, err)
+	assert.Equal(t, "weather", gen.ResponseFormat().Name)
+	assert.Equal(t, []string{"city"}, gen.ResponseFormat().JSONSchema.Required)
+
+	out, err := gen.Generate(ctx, []*schema.Message{schema.UserMessage("weather of Paris")})
+	assert.NoError(t, err)
+	assert.Equal(t, &weather{City: "Paris", Temperature: 21.5}, out)
+	assert.Equal(t, gen.ResponseFormat(), cm.options.ResponseFormat)
+	// the fallback instruction is added for models without json mode
+	assert.Len(t, cm.input, 2)
+
+	cm = &mockModel{jsonMod: true}
+	gen, err = NewGenerator[*weather](ctx, &Config{Model: cm})
+	assert.NoError(t, err)
+	_, err = gen.Generate(ctx, []*schema.Message{schema.UserMessage("weather of Paris")})
+	// native json mode is trusted to output plain json
+	assert.Error(t, err)
+	assert.Len(t, cm.input, 1)
+}
@@ -107,8 +107,9 @@ func On[T any](ctx context.Context, inOut T, handle Handle[T], timing CallbackTi
 func OnStartHandle[T any](ctx context.Context, input T,
 	runInfo *RunInfo, handlers []Handler) (context.Context, T) {
 
+	in := redact(runInfo, input, handlers)
 	for i := len(handlers) - 1; i >= 0; i-- {
-		ctx = handlers[i].OnStart(ctx, runInfo, input)
+		ctx = handlers[i].OnStart(ctx, runInfo, in)
 	}
 
 	return ctx, input
@@ -117,8 +118,9 @@ func OnStartHandle[T any](ctx context.Context, input T,
 func OnEndHandle[T any](ctx context.Context, output T,
 	runInfo *RunInfo, handlers []Handler) (context.Context, T) {
 
+	out := redact(runInfo, output, handlers)
 	for _, handler := range handlers {
-		ctx = handler.OnEnd(ctx, runInfo, output)
+		ctx = handler.OnEnd(ctx, runInfo, out)
 	}
 
 	return ctx, output
@@ -153,7 +155,7 @@ func OnStartWithStreamInputHandle[T any](ctx context.Context, input *schema.Stre
 
 	handle := func(ctx context.Context, handler Handler, in *schema.StreamReader[T]) context.Context {
 		in_ := schema.StreamReaderWithConvert(in, func(i T) (CallbackInput, error) {
-			return i, nil
+			return redact(runInfo, i, handlers), nil
 		})
 		return handler.OnStartWithStreamInput(ctx, runInfo, in_)
 	}
@@ -168,7 +170,7 @@ func OnEndWithStreamOutputHandle[T any](ctx context.Context, output *schema.Stre
 
 	handle := func(ctx context.Context, handler Handler, out *schema.StreamReader[T]) context.Context {
 		out_ := schema.StreamReaderWithConvert(out, func(i T) (CallbackOutput, error) {
-			return i, nil
+			return redact(runInfo, i, handlers), nil
 		})
 		return handler.OnEndWithStreamOutput(ctx, runInfo, out_)
 	}
@@ -179,9 +181,49 @@ func OnEndWithStreamOutputHandle[T any](ctx context.Context, output *schema.Stre
 func OnErrorHandle(ctx context.Context, err error,
 	runInfo *RunInfo, handlers []Handler) (context.Context, error) {
 
+	e := err
+	if r, ok := redact(runInfo, err, handlers).(error); ok {
+		e = r
+	}
 	for _, handler := range handlers {
-		ctx = handler.OnError(ctx, runInfo, err)
+		ctx = handler.OnError(ctx, runInfo, e)
 	}
 
 	return ctx, err
 }
+
+func OnGraphEvent(ctx context.Context, event *GraphEvent) {
+	mgr, ok := managerFromCtx(ctx)
+	if !ok {
+		return
+	}
+
+	info := mgr.runInfo
+	if info == nil {
+		info, _ = ctx.Value(CtxRunInfoKey{}).(*RunInfo)
+	}
+
+	for _, hs := range [][]Handler{mgr.handlers, mgr.globalHandlers} {
+		for _, handler := range hs {
+			if gh, ok_ := handler.(GraphEventHandler); ok_ {
+				gh.OnGraphEvent(ctx, info, event)
+			}
+		}
+	}
+}
+
+// redact applies the global redactors to the data before it's handed to the handlers.
+func redact(runInfo *RunInfo, data any, handlers []Handler) any {
+	if len(GlobalRedactors) == 0 || len(handlers) == 0 {
+		return data
+	}
+
+	var comp components.Component
+	if runInfo != nil {
+		comp = runInfo.Component
+	}
+	for _, r := range GlobalRedactors {
+		data = r(comp, data)
+	}
+	return data
+}
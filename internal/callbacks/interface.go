@@ -29,12 +29,24 @@ type RunInfo struct {
 	Name      string
 	Type      string
 	Component components.Component
+	// Group is the logical group of the graph node, e.g. "retrieval".
+	// Passed from compose.WithNodeGroup().
+	Group string
+
+	// RunID is the ID of the graph run the component belongs to.
+	// Passed from compose.WithRunID().
+	RunID string
+	// Metadata is the metadata of the graph run the component belongs to, should be treated as read-only.
+	// Passed from compose.WithRunMetadata().
+	Metadata map[string]string
 }
 
 type CallbackInput any
 
 type CallbackOutput any
 
+type Redactor func(component components.Component, data any) any
+
 type Handler interface {
 	OnStart(ctx context.Context, info *RunInfo, input CallbackInput) context.Context
 	OnEnd(ctx context.Context, info *RunInfo, output CallbackOutput) context.Context
@@ -47,6 +59,21 @@ type Handler interface {
 		output *schema.StreamReader[CallbackOutput]) context.Context
 }
 
+type GraphEventType string
+
+type GraphEvent struct {
+	Type         GraphEventType
+	NodeKey      string
+	Targets      []string
+	Step         int
+	CheckPointID string
+	Err          error
+}
+
+type GraphEventHandler interface {
+	OnGraphEvent(ctx context.Context, info *RunInfo, event *GraphEvent)
+}
+
 type CallbackTiming uint8
 
 type TimingChecker interface {
@@ -29,6 +29,8 @@ type manager struct {
 
 var GlobalHandlers []Handler
 
+var GlobalRedactors []Redactor
+
 func newManager(runInfo *RunInfo, handlers ...Handler) (*manager, bool) {
 	if len(handlers)+len(GlobalHandlers) == 0 {
 		return nil, false
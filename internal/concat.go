@@ -44,6 +44,7 @@ var (
 		generic.TypeOf[time.Time]():     useLast[time.Time],
 		generic.TypeOf[time.Duration](): useLast[time.Duration],
 	}
+	concatFuncsSource = map[reflect.Type]bool{}
 )
 
 func useLast[T any](s []T) (T, error) {
@@ -68,8 +69,15 @@ func concatStrings(ss []string) (string, error) {
 	return b.String(), nil
 }
 
-func RegisterStreamChunkConcatFunc[T any](fn func([]T) (T, error)) {
-	concatFuncs[generic.TypeOf[T]()] = fn
+// RegisterStreamChunkConcatFunc registers fn for T, an error is returned if a function has been registered for T, unless replace.
+// The built-in functions of the basic types can be replaced once.
+func RegisterStreamChunkConcatFunc[T any](fn func([]T) (T, error), replace bool) error {
+	typ := generic.TypeOf[T]()
+	if err := checkRegistration(concatFuncsSource, typ, replace); err != nil {
+		return err
+	}
+	concatFuncs[typ] = fn
+	return nil
 }
 
 func GetConcatFunc(typ reflect.Type) func(reflect.Value) (reflect.Value, error) {
@@ -23,10 +23,19 @@ import (
 	"github.com/cloudwego/eino/internal/generic"
 )
 
-var mergeFuncs = map[reflect.Type]any{}
+var (
+	mergeFuncs       = map[reflect.Type]any{}
+	mergeFuncsSource = map[reflect.Type]bool{}
+)
 
-func RegisterValuesMergeFunc[T any](fn func([]T) (T, error)) {
-	mergeFuncs[generic.TypeOf[T]()] = fn
+// RegisterValuesMergeFunc registers fn for T, an error is returned if a function has been registered for T, unless replace.
+func RegisterValuesMergeFunc[T any](fn func([]T) (T, error), replace bool) error {
+	typ := generic.TypeOf[T]()
+	if err := checkRegistration(mergeFuncsSource, typ, replace); err != nil {
+		return err
+	}
+	mergeFuncs[typ] = fn
+	return nil
 }
 
 func GetMergeFunc(typ reflect.Type) func([]any) (any, error) {
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrDuplicateRegistration is returned when a function is registered for a type which already has one.
+var ErrDuplicateRegistration = errors.New("duplicate registration")
+
+// checkRegistration records typ as registered in registered,
+// a second registration for typ is rejected unless replace, as the functions can't be told apart reliably,
+// e.g. the closures created from the same function literal share the code pointer.
+func checkRegistration(registered map[reflect.Type]bool, typ reflect.Type, replace bool) error {
+	if registered[typ] && !replace {
+		return fmt.Errorf("%w: a function has been registered for type[%v]", ErrDuplicateRegistration, typ)
+	}
+	registered[typ] = true
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Attachment is a file carried by a message alongside its content, e.g. the document a tool reads or the image it renders.
+// The file is either referenced by URI or inlined as raw bytes in Data, so binary files need not be base64-encoded
+// into the content or the tool call arguments.
+//
+// When streaming, an attachment may be split across chunks sharing the same Index:
+// the Data of the chunks is concatenated in order, and the other fields are taken from whichever chunk carries them.
+type Attachment struct {
+	// Index identifies the attachment among the chunks of a message stream, nil if the attachment is not split.
+	Index *int `json:"index,omitempty"`
+	// Name is the file name of the attachment, e.g. "report.pdf".
+	Name string `json:"name,omitempty"`
+	// MIMEType is the MIME type of the attachment, e.g. "application/pdf".
+	MIMEType string `json:"mime_type,omitempty"`
+	// URI references the attachment when it is not inlined, e.g. an http(s) or object storage URL.
+	URI string `json:"uri,omitempty"`
+	// Data is the raw content of the attachment when it is inlined.
+	Data []byte `json:"data,omitempty"`
+	// Size is the size of the attachment in bytes, which may be set for attachments referenced by URI.
+	Size int64 `json:"size,omitempty"`
+
+	Extra map[string]any `json:"extra,omitempty"`
+}
+
+// GetSize returns the size of the attachment in bytes, i.e. Size if set, otherwise the length of Data.
+func (a *Attachment) GetSize() int64 {
+	if a.Size > 0 {
+		return a.Size
+	}
+	return int64(len(a.Data))
+}
+
+func concatAttachments(chunks []Attachment) ([]Attachment, error) {
+	var (
+		merged  []Attachment
+		indexes []int
+	)
+	m := make(map[int][]int)
+	for i := range chunks {
+		index := chunks[i].Index
+		if index == nil {
+			merged = append(merged, chunks[i])
+			continue
+		}
+		if _, ok := m[*index]; !ok {
+			indexes = append(indexes, *index)
+		}
+		m[*index] = append(m[*index], i)
+	}
+	sort.Ints(indexes)
+
+	for _, index := range indexes {
+		idx := index
+		ret := Attachment{Index: &idx}
+		var (
+			dataLen   int
+			extraList []map[string]any
+		)
+		for _, n := range m[index] {
+			dataLen += len(chunks[n].Data)
+		}
+		if dataLen > 0 {
+			ret.Data = make([]byte, 0, dataLen)
+		}
+
+		for _, n := range m[index] {
+			chunk := chunks[n]
+			if err := concatAttachmentField(&ret.Name, chunk.Name, "name"); err != nil {
+				return nil, err
+			}
+			if err := concatAttachmentField(&ret.MIMEType, chunk.MIMEType, "mime type"); err != nil {
+				return nil, err
+			}
+			if err := concatAttachmentField(&ret.URI, chunk.URI, "uri"); err != nil {
+				return nil, err
+			}
+			ret.Data = append(ret.Data, chunk.Data...)
+			if chunk.Size > ret.Size {
+				ret.Size = chunk.Size
+			}
+			if len(chunk.Extra) > 0 {
+				extraList = append(extraList, chunk.Extra)
+			}
+		}
+
+		if len(extraList) > 0 {
+			extra, err := concatExtra(extraList)
+			if err != nil {
+				return nil, fmt.Errorf("failed to concat extra of attachment[index:%d]: %w", index, err)
+			}
+			ret.Extra = extra
+		}
+		merged = append(merged, ret)
+	}
+
+	return merged, nil
+}
+
+func concatAttachmentField(dst *string, src, field string) error {
+	if src == "" {
+		return nil
+	}
+	if *dst == "" {
+		*dst = src
+		return nil
+	}
+	if *dst != src {
+		return fmt.Errorf("cannot concat attachments with different %s: '%s' '%s'", field, *dst, src)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcatAttachments(t *testing.T) {
+	idx0, idx1 := 0, 1
+	msgs := []*Message{
+		{Role: Tool, Attachments: []Attachment{{Index: &idx1, Name: "b.txt", MIMEType: "text/plain", Data: []byte("hel")}}},
+		{Role: Tool, Content: "done", Attachments: []Attachment{
+			{Index: &idx1, Data: []byte("lo"), Extra: map[string]any{"k": "v"}},
+			{Index: &idx0, Name: "a.png", URI: "https://example.com/a.png", Size: 1024},
+		}},
+		{Role: Tool, Attachments: []Attachment{{Name: "c.bin", Data: []byte{1, 2}}}},
+	}
+
+	msg, err := ConcatMessages(msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", msg.Content)
+	assert.Equal(t, []Attachment{
+		{Name: "c.bin", Data: []byte{1, 2}},
+		{Index: &idx0, Name: "a.png", URI: "https://example.com/a.png", Size: 1024},
+		{Index: &idx1, Name: "b.txt", MIMEType: "text/plain", Data: []byte("hello"), Extra: map[string]any{"k": "v"}},
+	}, msg.Attachments)
+	assert.Equal(t, int64(1024), msg.Attachments[1].GetSize())
+	assert.Equal(t, int64(5), msg.Attachments[2].GetSize())
+	assert.Contains(t, msg.String(), "b.txt(text/plain, 5 bytes)")
+
+	// the chunks of an attachment are not mutated
+	assert.Equal(t, []byte("hel"), msgs[0].Attachments[0].Data)
+
+	_, err = ConcatMessages([]*Message{
+		{Role: Tool, Attachments: []Attachment{{Index: &idx0, Name: "a.png"}}},
+		{Role: Tool, Attachments: []Attachment{{Index: &idx0, Name: "b.png"}}},
+	})
+	assert.ErrorContains(t, err, "different name")
+}
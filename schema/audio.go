@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino/internal"
+)
+
+func init() {
+	internal.RegisterStreamChunkConcatFunc(ConcatAudioChunks, false)
+}
+
+// AudioChunk is a chunk of the audio stream exchanged with the speech components,
+// i.e. the input of components/audio.SpeechToText and the output of components/audio.TextToSpeech.
+type AudioChunk struct {
+	// Data is the raw audio bytes of the chunk, encoded as MIMEType.
+	Data []byte `json:"data,omitempty"`
+	// MIMEType is the encoding of the audio, e.g. "audio/pcm", "audio/mpeg" or "audio/opus".
+	MIMEType string `json:"mime_type,omitempty"`
+	// SampleRate is the sample rate of the audio in Hz, e.g. 16000.
+	SampleRate int `json:"sample_rate,omitempty"`
+	// Channels is the number of the audio channels, e.g. 1 for mono.
+	Channels int `json:"channels,omitempty"`
+
+	Extra map[string]any `json:"extra,omitempty"`
+}
+
+// ConcatAudioChunks concatenates the chunks of an audio stream into one chunk, joining their data in order.
+// The format fields are taken from whichever chunk carries them, and must agree among the chunks.
+// It is registered as the concat function of *AudioChunk, e.g. to invoke a TextToSpeech node of a graph,
+// which is only meaningful for the encodings which can be split at any byte, e.g. raw PCM or MP3.
+func ConcatAudioChunks(chunks []*AudioChunk) (*AudioChunk, error) {
+	var (
+		ret       = &AudioChunk{}
+		dataLen   int
+		extraList []map[string]any
+	)
+	for idx, c := range chunks {
+		if c == nil {
+			return nil, fmt.Errorf("unexpected nil chunk in audio stream, index: %d", idx)
+		}
+		dataLen += len(c.Data)
+	}
+	if dataLen > 0 {
+		ret.Data = make([]byte, 0, dataLen)
+	}
+
+	for _, c := range chunks {
+		if c.MIMEType != "" {
+			if ret.MIMEType == "" {
+				ret.MIMEType = c.MIMEType
+			} else if ret.MIMEType != c.MIMEType {
+				return nil, fmt.Errorf("cannot concat audio chunks with different mime types: '%s' '%s'", ret.MIMEType, c.MIMEType)
+			}
+		}
+		if c.SampleRate != 0 {
+			if ret.SampleRate == 0 {
+				ret.SampleRate = c.SampleRate
+			} else if ret.SampleRate != c.SampleRate {
+				return nil, fmt.Errorf("cannot concat audio chunks with different sample rates: %d %d", ret.SampleRate, c.SampleRate)
+			}
+		}
+		if c.Channels != 0 {
+			if ret.Channels == 0 {
+				ret.Channels = c.Channels
+			} else if ret.Channels != c.Channels {
+				return nil, fmt.Errorf("cannot concat audio chunks with different channels: %d %d", ret.Channels, c.Channels)
+			}
+		}
+		ret.Data = append(ret.Data, c.Data...)
+		if len(c.Extra) > 0 {
+			extraList = append(extraList, c.Extra)
+		}
+	}
+
+	if len(extraList) > 0 {
+		extra, err := concatExtra(extraList)
+		if err != nil {
+			return nil, fmt.Errorf("failed to concat audio chunk's extra: %w", err)
+		}
+		ret.Extra = extra
+	}
+
+	return ret, nil
+}
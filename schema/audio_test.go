@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/internal"
+)
+
+func TestConcatAudioChunks(t *testing.T) {
+	chunks := []*AudioChunk{
+		{Data: []byte{1, 2}, MIMEType: "audio/pcm", SampleRate: 16000, Channels: 1},
+		{Data: []byte{3}, Extra: map[string]any{"seq": 1}},
+		{Data: []byte{4}, SampleRate: 16000},
+	}
+	c, err := ConcatAudioChunks(chunks)
+	assert.NoError(t, err)
+	assert.Equal(t, &AudioChunk{Data: []byte{1, 2, 3, 4}, MIMEType: "audio/pcm", SampleRate: 16000, Channels: 1,
+		Extra: map[string]any{"seq": 1}}, c)
+	assert.Equal(t, []byte{1, 2}, chunks[0].Data)
+
+	// registered as the concat function of the audio stream
+	c, err = internal.ConcatItems(chunks)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, c.Data)
+
+	_, err = ConcatAudioChunks([]*AudioChunk{{SampleRate: 16000}, {SampleRate: 24000}})
+	assert.ErrorContains(t, err, "different sample rates")
+	_, err = ConcatAudioChunks([]*AudioChunk{{MIMEType: "audio/pcm"}, nil})
+	assert.ErrorContains(t, err, "nil chunk")
+}
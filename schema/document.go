@@ -16,6 +16,27 @@
 
 package schema
 
+import (
+	"github.com/cloudwego/eino/internal"
+)
+
+func init() {
+	internal.RegisterStreamChunkConcatFunc(concatDocumentChunks, false)
+}
+
+// concatDocumentChunks concatenates the chunks of a document stream, each being a batch of documents.
+func concatDocumentChunks(chunks [][]*Document) ([]*Document, error) {
+	var n int
+	for _, c := range chunks {
+		n += len(c)
+	}
+	ret := make([]*Document, 0, n)
+	for _, c := range chunks {
+		ret = append(ret, c...)
+	}
+	return ret, nil
+}
+
 const (
 	docMetaDataKeySubIndexes   = "_sub_indexes"
 	docMetaDataKeyScore        = "_score"
@@ -94,6 +115,12 @@ func (d *Document) Score() float64 {
 	return 0
 }
 
+// HasScore reports whether the score of the document is set, e.g. by doc.WithScore().
+func (d *Document) HasScore() bool {
+	_, ok := d.MetaData[docMetaDataKeyScore].(float64)
+	return ok
+}
+
 // WithExtraInfo sets the extra info of the document.
 // can use doc.ExtraInfo() to get the extra info.
 func (d *Document) WithExtraInfo(extraInfo string) *Document {
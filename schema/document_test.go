@@ -38,6 +38,8 @@ func TestDocument(t *testing.T) {
 			MetaData: nil,
 		}
 
+		convey.So(d.HasScore(), convey.ShouldBeFalse)
+
 		d.WithSubIndexes(subIndexes).
 			WithDenseVector(vector).
 			WithScore(score).
@@ -46,6 +48,7 @@ func TestDocument(t *testing.T) {
 
 		convey.So(d.SubIndexes(), convey.ShouldEqual, subIndexes)
 		convey.So(d.Score(), convey.ShouldEqual, score)
+		convey.So(d.HasScore(), convey.ShouldBeTrue)
 		convey.So(d.ExtraInfo(), convey.ShouldEqual, extraInfo)
 		convey.So(d.DSLInfo(), convey.ShouldEqual, dslInfo)
 		convey.So(d.DenseVector(), convey.ShouldEqual, vector)
@@ -0,0 +1,284 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FilterOp is the operator of a Filter.
+type FilterOp string
+
+const (
+	// FilterOpEq matches documents whose field equals the value.
+	FilterOpEq FilterOp = "eq"
+	// FilterOpNe matches documents whose field doesn't equal the value, including those without the field.
+	FilterOpNe FilterOp = "ne"
+	// FilterOpIn matches documents whose field equals any of the values.
+	FilterOpIn FilterOp = "in"
+	// FilterOpGt, FilterOpGte, FilterOpLt and FilterOpLte compare the field with the value, both numbers or both strings.
+	FilterOpGt  FilterOp = "gt"
+	FilterOpGte FilterOp = "gte"
+	FilterOpLt  FilterOp = "lt"
+	FilterOpLte FilterOp = "lte"
+	// FilterOpAnd matches documents matching all the children.
+	FilterOpAnd FilterOp = "and"
+	// FilterOpOr matches documents matching any of the children.
+	FilterOpOr FilterOp = "or"
+)
+
+// Filter is a provider-agnostic filter on the document metadata.
+// Graphs express filters once by Filter, and each retriever or indexer translates them to the syntax of its store,
+// by switching on Op and walking Children recursively.
+// e.g.
+//
+//	filter := schema.And(
+//		schema.Eq("lang", "go"),
+//		schema.Or(schema.Gte("year", 2024), schema.In("tag", "eino", "agent")),
+//	)
+//	docs, err := r.Retrieve(ctx, query, retriever.WithFilter(filter))
+type Filter struct {
+	Op FilterOp `json:"op"`
+	// Field is the metadata key compared by the comparison operators.
+	Field string `json:"field,omitempty"`
+	// Value is the operand of Eq, Ne, Gt, Gte, Lt and Lte.
+	Value any `json:"value,omitempty"`
+	// Values is the operand of In.
+	Values []any `json:"values,omitempty"`
+	// Children are the operands of And and Or.
+	Children []*Filter `json:"children,omitempty"`
+}
+
+// Eq creates a filter matching documents whose field equals value.
+func Eq(field string, value any) *Filter {
+	return &Filter{Op: FilterOpEq, Field: field, Value: value}
+}
+
+// Ne creates a filter matching documents whose field doesn't equal value.
+func Ne(field string, value any) *Filter {
+	return &Filter{Op: FilterOpNe, Field: field, Value: value}
+}
+
+// In creates a filter matching documents whose field equals any of values.
+func In(field string, values ...any) *Filter {
+	return &Filter{Op: FilterOpIn, Field: field, Values: values}
+}
+
+// Gt creates a filter matching documents whose field is greater than value.
+func Gt(field string, value any) *Filter {
+	return &Filter{Op: FilterOpGt, Field: field, Value: value}
+}
+
+// Gte creates a filter matching documents whose field is greater than or equal to value.
+func Gte(field string, value any) *Filter {
+	return &Filter{Op: FilterOpGte, Field: field, Value: value}
+}
+
+// Lt creates a filter matching documents whose field is less than value.
+func Lt(field string, value any) *Filter {
+	return &Filter{Op: FilterOpLt, Field: field, Value: value}
+}
+
+// Lte creates a filter matching documents whose field is less than or equal to value.
+func Lte(field string, value any) *Filter {
+	return &Filter{Op: FilterOpLte, Field: field, Value: value}
+}
+
+// And creates a filter matching documents matching all the filters.
+func And(filters ...*Filter) *Filter {
+	return &Filter{Op: FilterOpAnd, Children: filters}
+}
+
+// Or creates a filter matching documents matching any of the filters.
+func Or(filters ...*Filter) *Filter {
+	return &Filter{Op: FilterOpOr, Children: filters}
+}
+
+// Validate checks the filter is well-formed, so that adapters can translate it without further checks.
+func (f *Filter) Validate() error {
+	if f == nil {
+		return fmt.Errorf("filter is nil")
+	}
+
+	switch f.Op {
+	case FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if len(f.Field) == 0 {
+			return fmt.Errorf("filter[%s] has no field", f.Op)
+		}
+		if f.Value == nil {
+			return fmt.Errorf("filter[%s] on field[%s] has no value", f.Op, f.Field)
+		}
+	case FilterOpIn:
+		if len(f.Field) == 0 {
+			return fmt.Errorf("filter[%s] has no field", f.Op)
+		}
+		if len(f.Values) == 0 {
+			return fmt.Errorf("filter[%s] on field[%s] has no values", f.Op, f.Field)
+		}
+	case FilterOpAnd, FilterOpOr:
+		if len(f.Children) == 0 {
+			return fmt.Errorf("filter[%s] has no children", f.Op)
+		}
+		for _, c := range f.Children {
+			if err := c.Validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown filter op: %s", f.Op)
+	}
+
+	return nil
+}
+
+// Match evaluates the filter on the metadata, e.g. to enforce the filter on documents returned by a store ignoring it.
+// Numbers of different types are compared by their values. A document without the field only matches Ne.
+func (f *Filter) Match(metaData map[string]any) bool {
+	if f == nil {
+		return true
+	}
+
+	switch f.Op {
+	case FilterOpAnd:
+		for _, c := range f.Children {
+			if !c.Match(metaData) {
+				return false
+			}
+		}
+		return true
+	case FilterOpOr:
+		for _, c := range f.Children {
+			if c.Match(metaData) {
+				return true
+			}
+		}
+		return false
+	}
+
+	v, ok := metaData[f.Field]
+	switch f.Op {
+	case FilterOpEq:
+		return ok && filterValueEqual(v, f.Value)
+	case FilterOpNe:
+		return !ok || !filterValueEqual(v, f.Value)
+	case FilterOpIn:
+		if !ok {
+			return false
+		}
+		for _, value := range f.Values {
+			if filterValueEqual(v, value) {
+				return true
+			}
+		}
+		return false
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if !ok {
+			return false
+		}
+		c, comparable := filterValueCompare(v, f.Value)
+		if !comparable {
+			return false
+		}
+		switch f.Op {
+		case FilterOpGt:
+			return c > 0
+		case FilterOpGte:
+			return c >= 0
+		case FilterOpLt:
+			return c < 0
+		default:
+			return c <= 0
+		}
+	default:
+		return false
+	}
+}
+
+// String returns the filter in a readable form, e.g. (lang == go AND year >= 2024).
+func (f *Filter) String() string {
+	if f == nil {
+		return "<nil>"
+	}
+
+	switch f.Op {
+	case FilterOpAnd, FilterOpOr:
+		children := make([]string, 0, len(f.Children))
+		for _, c := range f.Children {
+			children = append(children, c.String())
+		}
+		return "(" + strings.Join(children, " "+strings.ToUpper(string(f.Op))+" ") + ")"
+	case FilterOpIn:
+		return fmt.Sprintf("%s IN %v", f.Field, f.Values)
+	default:
+		symbols := map[FilterOp]string{FilterOpEq: "==", FilterOpNe: "!=", FilterOpGt: ">", FilterOpGte: ">=", FilterOpLt: "<", FilterOpLte: "<="}
+		if s, ok := symbols[f.Op]; ok {
+			return fmt.Sprintf("%s %s %v", f.Field, s, f.Value)
+		}
+		return fmt.Sprintf("%s %s %v", f.Field, f.Op, f.Value)
+	}
+}
+
+func filterValueEqual(a, b any) bool {
+	if c, ok := filterValueCompare(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// filterValueCompare compares two numbers or two strings, returns false if they are not comparable.
+func filterValueCompare(a, b any) (int, bool) {
+	if fa, ok := toFloat64(a); ok {
+		fb, okk := toFloat64(b)
+		if !okk {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	sa, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	sb, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(sa, sb), true
+}
+
+func toFloat64(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	meta := map[string]any{
+		"lang":  "go",
+		"year":  int64(2025),
+		"score": 0.8,
+		"tag":   "eino",
+	}
+
+	t.Run("match", func(t *testing.T) {
+		cases := []struct {
+			filter *Filter
+			want   bool
+		}{
+			{Eq("lang", "go"), true},
+			{Eq("year", 2025), true},
+			{Eq("missing", "x"), false},
+			{Ne("lang", "go"), false},
+			{Ne("missing", "x"), true},
+			{In("tag", "agent", "eino"), true},
+			{In("tag", "agent"), false},
+			{Gt("year", 2024), true},
+			{Gte("score", 0.8), true},
+			{Lt("score", 0.5), false},
+			{Lte("lang", "go"), true},
+			{Gt("lang", 1), false},
+			{And(Eq("lang", "go"), Gte("year", 2024)), true},
+			{And(Eq("lang", "go"), Lt("year", 2024)), false},
+			{Or(Eq("lang", "python"), In("tag", "eino")), true},
+			{Or(Eq("lang", "python"), Eq("tag", "agent")), false},
+			{nil, true},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.want, c.filter.Match(meta), c.filter.String())
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, And(Eq("lang", "go"), Or(In("tag", "eino"), Gt("year", 2024))).Validate())
+		assert.Error(t, (*Filter)(nil).Validate())
+		assert.Error(t, Eq("", "go").Validate())
+		assert.Error(t, Eq("lang", nil).Validate())
+		assert.Error(t, In("tag").Validate())
+		assert.Error(t, And().Validate())
+		assert.Error(t, Or(Eq("lang", "go"), &Filter{Op: "like"}).Validate())
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, "(lang == go AND (tag IN [eino agent] OR year >= 2024))",
+			And(Eq("lang", "go"), Or(In("tag", "eino", "agent"), Gte("year", 2024))).String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		f := And(Eq("lang", "go"), In("tag", "eino"))
+		data, err := json.Marshal(f)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"op":"and","children":[{"op":"eq","field":"lang","value":"go"},{"op":"in","field":"tag","values":["eino"]}]}`, string(data))
+
+		var got Filter
+		assert.NoError(t, json.Unmarshal(data, &got))
+		assert.True(t, got.Match(map[string]any{"lang": "go", "tag": "eino"}))
+	})
+}
@@ -0,0 +1,641 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package jsonstream incrementally parses JSON text arriving in chunks, e.g. the structured output streamed by a chat model,
+// into partial values and events, so that the output can be rendered progressively instead of waiting for the end of the stream.
+//
+//	updates := jsonstream.ParseMessages(msgStream)
+//	defer updates.Close()
+//	for {
+//		u, err := updates.Recv()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		render(u.Value) // the partial value parsed so far
+//		for _, e := range u.Events {
+//			if e.Type == jsonstream.EventComplete && e.Path.String() == "$.rows[0]" {
+//				// the first row is complete
+//			}
+//		}
+//	}
+package jsonstream
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// EventStart is reported when a value starts, e.g. when '{' of an object or '"' of a string is parsed.
+	EventStart EventType = "start"
+	// EventDelta is reported when characters are appended to a string value, Event.Delta is the appended text.
+	EventDelta EventType = "delta"
+	// EventComplete is reported when a value is complete, Event.Value is the complete value.
+	EventComplete EventType = "complete"
+)
+
+// Event is reported while parsing, for a value at Path.
+type Event struct {
+	Type EventType
+	Path Path
+	// Value is the complete value for EventComplete, in the types of encoding/json decoding into any,
+	// i.e. map[string]any, []any, string, float64, bool or nil.
+	Value any
+	// Delta is the appended text for EventDelta.
+	Delta string
+}
+
+// Path locates a value from the root, whose elements are the string keys of objects and the int indexes of arrays.
+type Path []any
+
+// String formats the path in the JSONPath style, e.g. $.rows[0].name.
+func (p Path) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("$")
+	for _, e := range p {
+		switch t := e.(type) {
+		case int:
+			sb.WriteString("[" + strconv.Itoa(t) + "]")
+		case string:
+			if isIdentifier(t) {
+				sb.WriteString("." + t)
+			} else {
+				sb.WriteString("[" + strconv.Quote(t) + "]")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func isIdentifier(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// SyntaxError is returned when the text isn't valid JSON.
+type SyntaxError struct {
+	// Offset is the offset in bytes of the invalid character in the whole text.
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("json syntax error at offset %d: %s", e.Offset, e.Msg)
+}
+
+type nodeKind int
+
+const (
+	kindObject nodeKind = iota
+	kindArray
+	kindString
+	kindNumber
+	kindLiteral
+)
+
+// node is a value being parsed.
+type node struct {
+	kind nodeKind
+	path Path
+
+	keys   []string
+	fields map[string]*node
+	items  []*node
+
+	// text is the content of strings, and the raw text of numbers and literals
+	text strings.Builder
+	done bool
+	// value is the value of the complete scalars
+	value any
+}
+
+// visible reports whether the value is in the snapshots, the incomplete numbers and literals are not.
+func (n *node) visible() bool {
+	return n.done || (n.kind != kindNumber && n.kind != kindLiteral)
+}
+
+func (n *node) snapshot() any {
+	switch n.kind {
+	case kindObject:
+		m := make(map[string]any, len(n.keys))
+		for _, k := range n.keys {
+			if v := n.fields[k]; v.visible() {
+				m[k] = v.snapshot()
+			}
+		}
+		return m
+	case kindArray:
+		s := make([]any, 0, len(n.items))
+		for _, v := range n.items {
+			if v.visible() {
+				s = append(s, v.snapshot())
+			}
+		}
+		return s
+	case kindString:
+		return n.text.String()
+	default:
+		return n.value
+	}
+}
+
+type state int
+
+const (
+	stateValue       state = iota // expecting a value
+	stateArrayFirst               // after '[', expecting an element or ']'
+	stateObjectFirst              // after '{', expecting a key or '}'
+	stateObjectKey                // after ',' in an object, expecting a key
+	stateKey                      // in a key string
+	stateColon                    // after a key, expecting ':'
+	stateObjectNext               // after a field value, expecting ',' or '}'
+	stateArrayNext                // after an element, expecting ',' or ']'
+	stateString                   // in a string value
+	stateScalar                   // in a number or literal
+	stateDone                     // the root value is complete
+)
+
+// NewParser creates a Parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parser incrementally parses JSON text written in chunks, not safe for concurrent use.
+// Whitespaces and the opening line of a markdown code fence, e.g. ```json, before the value are skipped,
+// and so is any text after the value.
+type Parser struct {
+	root  *node
+	stack []*node
+	state state
+
+	// fence is the skipped opening line of the code fence, inFence reports whether the line is not finished
+	fence   strings.Builder
+	inFence bool
+
+	key       strings.Builder
+	escape    []byte // the pending escape sequence in a string, including the backslash
+	surrogate rune   // the pending high surrogate of \u escapes
+	utf8Buf   []byte // the incomplete UTF-8 sequence at the end of a chunk
+
+	offset int
+	err    error
+
+	events []Event
+	delta  strings.Builder
+}
+
+// Write parses the chunk and returns the events reported for it.
+// After an error is returned, the parser stays in the error state.
+func (p *Parser) Write(chunk string) ([]Event, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.events = nil
+
+	data := chunk
+	if len(p.utf8Buf) > 0 {
+		data = string(p.utf8Buf) + chunk
+		p.utf8Buf = nil
+	}
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRuneInString(data[i:])
+		if r == utf8.RuneError && !utf8.FullRuneInString(data[i:]) {
+			// wait for the rest of the rune in the next chunk
+			p.utf8Buf = []byte(data[i:])
+			break
+		}
+		if err := p.step(r); err != nil {
+			p.err = err
+			return p.events, err
+		}
+		i += size
+		p.offset += size
+	}
+	p.flushDelta()
+	return p.events, nil
+}
+
+// Close finishes the parsing, returning an error wrapping io.ErrUnexpectedEOF if the root value is incomplete.
+// A number at the root, which has no closing character, is completed by Close.
+func (p *Parser) Close() ([]Event, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.events = nil
+	if p.state == stateScalar && len(p.stack) == 1 {
+		if err := p.completeScalar(); err != nil {
+			p.err = err
+			return p.events, err
+		}
+	}
+	if p.state != stateDone {
+		p.err = fmt.Errorf("json stream ends before the value is complete: %w", io.ErrUnexpectedEOF)
+		return p.events, p.err
+	}
+	return p.events, nil
+}
+
+// Value returns a snapshot of the value parsed so far, e.g. an object with the complete fields and the partial strings, nil if no value is started.
+// Incomplete numbers and literals are left out, and so are the keys without values yet.
+func (p *Parser) Value() any {
+	if p.root == nil {
+		return nil
+	}
+	return p.root.snapshot()
+}
+
+// Done reports whether the root value is complete.
+func (p *Parser) Done() bool {
+	return p.state == stateDone
+}
+
+func (p *Parser) syntaxError(format string, args ...any) error {
+	return &SyntaxError{Offset: p.offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func (p *Parser) step(r rune) error {
+	switch p.state {
+	case stateValue, stateArrayFirst:
+		if p.root == nil && (r == '`' || p.inFence) {
+			return p.skipFence(r)
+		}
+		if isSpace(r) {
+			return nil
+		}
+		if r == ']' && p.state == stateArrayFirst {
+			return p.closeContainer()
+		}
+		return p.startValue(r)
+	case stateObjectFirst, stateObjectKey:
+		if isSpace(r) {
+			return nil
+		}
+		if r == '}' && p.state == stateObjectFirst {
+			return p.closeContainer()
+		}
+		if r != '"' {
+			return p.syntaxError("invalid character %q looking for beginning of object key string", r)
+		}
+		p.key.Reset()
+		p.state = stateKey
+		return nil
+	case stateKey:
+		if r == '"' && len(p.escape) == 0 {
+			p.endString(&p.key)
+			p.state = stateColon
+			return nil
+		}
+		return p.stringChar(r, &p.key)
+	case stateColon:
+		if isSpace(r) {
+			return nil
+		}
+		if r != ':' {
+			return p.syntaxError("invalid character %q after object key", r)
+		}
+		p.state = stateValue
+		return nil
+	case stateObjectNext:
+		if isSpace(r) {
+			return nil
+		}
+		switch r {
+		case ',':
+			p.state = stateObjectKey
+			return nil
+		case '}':
+			return p.closeContainer()
+		}
+		return p.syntaxError("invalid character %q after object field", r)
+	case stateArrayNext:
+		if isSpace(r) {
+			return nil
+		}
+		switch r {
+		case ',':
+			p.state = stateValue
+			return nil
+		case ']':
+			return p.closeContainer()
+		}
+		return p.syntaxError("invalid character %q after array element", r)
+	case stateString:
+		top := p.stack[len(p.stack)-1]
+		if r == '"' && len(p.escape) == 0 {
+			p.endString(&top.text)
+			p.flushDelta()
+			p.complete(top)
+			return nil
+		}
+		before := top.text.Len()
+		if err := p.stringChar(r, &top.text); err != nil {
+			return err
+		}
+		if top.text.Len() > before {
+			p.delta.WriteString(top.text.String()[before:])
+		}
+		return nil
+	case stateScalar:
+		top := p.stack[len(p.stack)-1]
+		if (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || r == '-' || r == '+' || r == '.' || r == 'E' {
+			top.text.WriteRune(r)
+			return nil
+		}
+		if err := p.completeScalar(); err != nil {
+			return err
+		}
+		return p.step(r)
+	default: // stateDone
+		return nil
+	}
+}
+
+// skipFence skips the opening line of a markdown code fence before the root value.
+func (p *Parser) skipFence(r rune) error {
+	p.inFence = r != '\n'
+	if !p.inFence {
+		return nil
+	}
+	p.fence.WriteRune(r)
+	if f := p.fence.String(); !strings.HasPrefix(f, "```") && !strings.HasPrefix("```", f) {
+		return p.syntaxError("invalid character %q looking for beginning of value", r)
+	}
+	return nil
+}
+
+func (p *Parser) startValue(r rune) error {
+	n := &node{}
+	switch {
+	case r == '{':
+		n.kind = kindObject
+		n.fields = make(map[string]*node)
+	case r == '[':
+		n.kind = kindArray
+	case r == '"':
+		n.kind = kindString
+	case r == '-' || (r >= '0' && r <= '9'):
+		n.kind = kindNumber
+		n.text.WriteRune(r)
+	case r == 't' || r == 'f' || r == 'n':
+		n.kind = kindLiteral
+		n.text.WriteRune(r)
+	default:
+		return p.syntaxError("invalid character %q looking for beginning of value", r)
+	}
+
+	if len(p.stack) == 0 {
+		p.root = n
+	} else {
+		parent := p.stack[len(p.stack)-1]
+		n.path = make(Path, len(parent.path), len(parent.path)+1)
+		copy(n.path, parent.path)
+		if parent.kind == kindObject {
+			key := p.key.String()
+			n.path = append(n.path, key)
+			if _, ok := parent.fields[key]; !ok {
+				parent.keys = append(parent.keys, key)
+			}
+			parent.fields[key] = n
+		} else {
+			n.path = append(n.path, len(parent.items))
+			parent.items = append(parent.items, n)
+		}
+	}
+	p.stack = append(p.stack, n)
+
+	switch n.kind {
+	case kindObject:
+		p.state = stateObjectFirst
+	case kindArray:
+		p.state = stateArrayFirst
+	case kindString:
+		p.state = stateString
+	default:
+		p.state = stateScalar
+	}
+	p.events = append(p.events, Event{Type: EventStart, Path: n.path})
+	return nil
+}
+
+// endString writes the unpaired high surrogate left at the end of a string.
+func (p *Parser) endString(sb *strings.Builder) {
+	if p.surrogate != 0 {
+		p.surrogate = 0
+		sb.WriteRune(utf8.RuneError)
+	}
+}
+
+func (p *Parser) stringChar(r rune, sb *strings.Builder) error {
+	if len(p.escape) == 0 {
+		if r == '\\' {
+			p.escape = append(p.escape, '\\')
+			return nil
+		}
+		if r < 0x20 {
+			return p.syntaxError("invalid control character %q in string literal", r)
+		}
+		p.writeRune(sb, r)
+		return nil
+	}
+
+	p.escape = append(p.escape, string(r)...)
+	if len(p.escape) == 2 {
+		var c rune
+		switch r {
+		case '"', '\\', '/':
+			c = r
+		case 'b':
+			c = '\b'
+		case 'f':
+			c = '\f'
+		case 'n':
+			c = '\n'
+		case 'r':
+			c = '\r'
+		case 't':
+			c = '\t'
+		case 'u':
+			return nil
+		default:
+			return p.syntaxError("invalid character %q in string escape code", r)
+		}
+		p.escape = p.escape[:0]
+		p.writeRune(sb, c)
+		return nil
+	}
+	if len(p.escape) < 6 {
+		return nil
+	}
+
+	code, err := strconv.ParseUint(string(p.escape[2:]), 16, 16)
+	if err != nil {
+		return p.syntaxError("invalid unicode escape %s", p.escape)
+	}
+	p.escape = p.escape[:0]
+	p.writeRune(sb, rune(code))
+	return nil
+}
+
+// writeRune writes the rune, combining the surrogate pairs of \u escapes.
+func (p *Parser) writeRune(sb *strings.Builder, r rune) {
+	if p.surrogate != 0 {
+		high := p.surrogate
+		p.surrogate = 0
+		if utf16.IsSurrogate(r) {
+			if c := utf16.DecodeRune(high, r); c != utf8.RuneError {
+				sb.WriteRune(c)
+				return
+			}
+		}
+		sb.WriteRune(utf8.RuneError)
+	}
+	if r >= 0xD800 && r < 0xDC00 {
+		p.surrogate = r
+		return
+	}
+	if utf16.IsSurrogate(r) {
+		r = utf8.RuneError
+	}
+	sb.WriteRune(r)
+}
+
+func (p *Parser) completeScalar() error {
+	top := p.stack[len(p.stack)-1]
+	text := top.text.String()
+	if top.kind == kindNumber {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil || !validNumber(text) {
+			return p.syntaxError("invalid number %s", text)
+		}
+		top.value = f
+	} else {
+		switch text {
+		case "true":
+			top.value = true
+		case "false":
+			top.value = false
+		case "null":
+			top.value = nil
+		default:
+			return p.syntaxError("invalid literal %s", text)
+		}
+	}
+	p.complete(top)
+	return nil
+}
+
+// validNumber checks the number grammar of JSON, which is stricter than strconv, e.g. no leading zeros or '+'.
+func validNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	if i >= len(s) {
+		return false
+	}
+	if s[i] == '0' {
+		i++
+	} else if s[i] >= '1' && s[i] <= '9' {
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	} else {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+func (p *Parser) closeContainer() error {
+	p.complete(p.stack[len(p.stack)-1])
+	return nil
+}
+
+// complete pops the complete value at the top of the stack and reports it.
+func (p *Parser) complete(n *node) {
+	n.done = true
+	p.stack = p.stack[:len(p.stack)-1]
+	p.events = append(p.events, Event{Type: EventComplete, Path: n.path, Value: n.snapshot()})
+
+	if len(p.stack) == 0 {
+		p.state = stateDone
+		return
+	}
+	if p.stack[len(p.stack)-1].kind == kindObject {
+		p.state = stateObjectNext
+	} else {
+		p.state = stateArrayNext
+	}
+}
+
+func (p *Parser) flushDelta() {
+	if p.delta.Len() == 0 {
+		return
+	}
+	var path Path
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].kind == kindString {
+			path = p.stack[i].path
+			break
+		}
+	}
+	p.events = append(p.events, Event{Type: EventDelta, Path: path, Delta: p.delta.String()})
+	p.delta.Reset()
+}
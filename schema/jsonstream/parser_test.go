@@ -0,0 +1,174 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonstream
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func parseByRunes(t *testing.T, text string) (any, []Event) {
+	p := NewParser()
+	var events []Event
+	for _, r := range text {
+		es, err := p.Write(string(r))
+		assert.NoError(t, err)
+		events = append(events, es...)
+	}
+	es, err := p.Close()
+	assert.NoError(t, err)
+	return p.Value(), append(events, es...)
+}
+
+func TestParser(t *testing.T) {
+	t.Run("same as encoding/json", func(t *testing.T) {
+		for _, text := range []string{
+			`{"a":1,"b":[true,false,null],"c":{"d":"e\n\"\\\/你😀"},"e":[],"f":{},"g":-1.5e3}`,
+			`[1, [2, [3, []]], {"x": [{}]}]`,
+			` "plain" `,
+			`0`,
+			`-12.25`,
+			`{"unicode":"你好, 世界 😀"}`,
+			`["\u4f60\ud83d\ude00", "\ud83d", "\u00e9x"]`,
+		} {
+			value, _ := parseByRunes(t, text)
+			var expected any
+			assert.NoError(t, json.Unmarshal([]byte(text), &expected))
+			assert.Equal(t, expected, value, text)
+
+			// in one chunk
+			p := NewParser()
+			_, err := p.Write(text)
+			assert.NoError(t, err)
+			_, err = p.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, p.Value(), text)
+		}
+	})
+
+	t.Run("partial values and events", func(t *testing.T) {
+		p := NewParser()
+		events, err := p.Write(`{"title":"Wea`)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"title": "Wea"}, p.Value())
+		assert.Equal(t, []Event{
+			{Type: EventStart, Path: nil},
+			{Type: EventStart, Path: Path{"title"}},
+			{Type: EventDelta, Path: Path{"title"}, Delta: "Wea"},
+		}, events)
+
+		events, err = p.Write(`ther","rows":[{"city":"bj","temp":2`)
+		assert.NoError(t, err)
+		// the incomplete number is left out
+		assert.Equal(t, map[string]any{"title": "Weather", "rows": []any{map[string]any{"city": "bj"}}}, p.Value())
+		assert.Contains(t, events, Event{Type: EventComplete, Path: Path{"title"}, Value: "Weather"})
+		assert.Contains(t, events, Event{Type: EventComplete, Path: Path{"rows", 0, "city"}, Value: "bj"})
+		assert.False(t, p.Done())
+
+		events, err = p.Write(`5}]}`)
+		assert.NoError(t, err)
+		assert.Equal(t, Event{Type: EventComplete, Path: Path{"rows", 0, "temp"}, Value: float64(25)}, events[0])
+		assert.Equal(t, Event{Type: EventComplete, Path: Path{"rows", 0}, Value: map[string]any{"city": "bj", "temp": float64(25)}}, events[1])
+		assert.Equal(t, Path{"rows"}, events[2].Path)
+		assert.Nil(t, events[3].Path)
+		assert.True(t, p.Done())
+
+		// the text after the value is ignored
+		_, err = p.Write("\n```")
+		assert.NoError(t, err)
+		_, err = p.Close()
+		assert.NoError(t, err)
+	})
+
+	t.Run("code fence and split runes", func(t *testing.T) {
+		text := "```json\n{\"a\":\"世界\"}\n```"
+		p := NewParser()
+		data := []byte(text)
+		for i := 0; i < len(data); i++ {
+			_, err := p.Write(string(data[i : i+1]))
+			assert.NoError(t, err)
+		}
+		_, err := p.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "世界"}, p.Value())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, text := range []string{`{"a" 1}`, `[1,,2]`, `{"a":tru}`, `[01]`, `hello`, `{"a":"\x"}`, `[1}`} {
+			p := NewParser()
+			_, err := p.Write(text)
+			var se *SyntaxError
+			assert.True(t, errors.As(err, &se), text)
+			// the parser stays in the error state
+			_, err2 := p.Write("1")
+			assert.Equal(t, err, err2)
+		}
+
+		p := NewParser()
+		_, err := p.Write(`{"a":[1,2`)
+		assert.NoError(t, err)
+		_, err = p.Close()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		assert.Equal(t, "$", Path(nil).String())
+		assert.Equal(t, `$.rows[0]["first name"]`, Path{"rows", 0, "first name"}.String())
+	})
+}
+
+func TestParseMessages(t *testing.T) {
+	sr := schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage(`{"items":[`, nil),
+		schema.AssistantMessage("", nil),
+		schema.AssistantMessage(`"a",`, nil),
+		schema.AssistantMessage(`"b"]}`, nil),
+	})
+	updates := ParseMessages(sr)
+	defer updates.Close()
+
+	var values []any
+	for {
+		u, err := updates.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		values = append(values, u.Value)
+		if u.Done {
+			assert.Equal(t, map[string]any{"items": []any{"a", "b"}}, u.Value)
+		}
+	}
+	assert.Equal(t, []any{
+		map[string]any{"items": []any{}},
+		map[string]any{"items": []any{"a"}},
+		map[string]any{"items": []any{"a", "b"}},
+	}, values)
+
+	updates = Parse(schema.StreamReaderFromArray([]string{`{"a":`, `1`}))
+	var err error
+	for err == nil {
+		_, err = updates.Recv()
+	}
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonstream
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Update is the result of parsing a chunk of the stream.
+type Update struct {
+	// Events are the events reported for the chunk.
+	Events []Event
+	// Value is the snapshot of the value parsed so far, see Parser.Value.
+	Value any
+	// Done reports whether the value is complete.
+	Done bool
+}
+
+// Parse parses the stream of JSON text chunks, returning the stream of the updates, one for each chunk reporting events.
+// The returned stream ends with the error of the source stream, a *SyntaxError, or an error wrapping io.ErrUnexpectedEOF
+// if the source stream ends before the value is complete. The source stream is closed when the returned stream ends or is closed.
+func Parse(sr *schema.StreamReader[string]) *schema.StreamReader[*Update] {
+	out, sw := schema.Pipe[*Update](0)
+	go func() {
+		defer func() {
+			sr.Close()
+			sw.Close()
+		}()
+
+		p := NewParser()
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				events, err := p.Close()
+				if len(events) > 0 {
+					if closed := sw.Send(&Update{Events: events, Value: p.Value(), Done: p.Done()}, nil); closed {
+						return
+					}
+				}
+				if err != nil {
+					sw.Send(nil, err)
+				}
+				return
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+
+			events, err := p.Write(chunk)
+			if len(events) > 0 {
+				if closed := sw.Send(&Update{Events: events, Value: p.Value(), Done: p.Done()}, nil); closed {
+					return
+				}
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ParseMessages parses the contents of the message stream, e.g. the structured output streamed by a chat model, see Parse.
+func ParseMessages(sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*Update] {
+	return Parse(schema.StreamReaderWithConvert(sr, func(m *schema.Message) (string, error) {
+		if m == nil || len(m.Content) == 0 {
+			return "", schema.ErrNoValue
+		}
+		return m.Content, nil
+	}))
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/nikolalohinski/gonja"
 	"github.com/nikolalohinski/gonja/config"
@@ -37,8 +38,8 @@ import (
 )
 
 func init() {
-	internal.RegisterStreamChunkConcatFunc(ConcatMessages)
-	internal.RegisterStreamChunkConcatFunc(ConcatMessageArray)
+	internal.RegisterStreamChunkConcatFunc(ConcatMessages, false)
+	internal.RegisterStreamChunkConcatFunc(ConcatMessageArray, false)
 }
 
 func ConcatMessageArray(mas [][]*Message) ([]*Message, error) {
@@ -398,6 +399,12 @@ type ResponseMeta struct {
 	Usage *TokenUsage `json:"usage,omitempty"`
 	// LogProbs is Log probability information.
 	LogProbs *LogProbs `json:"logprobs,omitempty"`
+	// Model is the name of the model which actually served the request, as reported by the provider.
+	Model string `json:"model,omitempty"`
+	// RequestID is the provider's id of the request, useful for troubleshooting with the provider.
+	RequestID string `json:"request_id,omitempty"`
+	// Latency is the time from sending the request to receiving the complete response.
+	Latency time.Duration `json:"latency,omitempty"`
 }
 
 // Message denotes the data structure for model input and output, originating from either user input or model return.
@@ -472,6 +479,9 @@ type Message struct {
 	// ReasoningContent is the thinking process of the model, which will be included when the model returns reasoning content.
 	ReasoningContent string `json:"reasoning_content,omitempty"`
 
+	// Attachments are the files carried by the message, e.g. the files a tool reads or produces.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// customized information for model implementation
 	Extra map[string]any `json:"extra,omitempty"`
 }
@@ -515,6 +525,9 @@ type messagesPlaceholder struct {
 }
 
 // MessagesPlaceholder can render a placeholder to a list of messages in params.
+// If optional is false, the key is required, and formatting fails if it's missing or nil in params.
+// Formatting also fails if the value isn't []*Message or contains nil messages.
+// A template can have multiple placeholders, e.g. one for the long-term memory and one for the recent history.
 // e.g.
 //
 //	placeholder := MessagesPlaceholder("history", false)
@@ -546,7 +559,7 @@ func MessagesPlaceholder(key string, optional bool) MessagesTemplate {
 //	msgs, err := placeholder.Format(ctx, params) // <= this will return the value of "history" in params
 func (p *messagesPlaceholder) Format(_ context.Context, vs map[string]any, _ FormatType) ([]*Message, error) {
 	v, ok := vs[p.key]
-	if !ok {
+	if !ok || v == nil {
 		if p.optional {
 			return []*Message{}, nil
 		}
@@ -558,10 +571,57 @@ func (p *messagesPlaceholder) Format(_ context.Context, vs map[string]any, _ For
 	if !ok {
 		return nil, fmt.Errorf("only messages can be used to format message placeholder, key: %v, actual type: %v", p.key, reflect.TypeOf(v))
 	}
+	for i, msg := range msgs {
+		if msg == nil {
+			return nil, fmt.Errorf("message placeholder format: %s[%d] is nil", p.key, i)
+		}
+	}
 
 	return msgs, nil
 }
 
+type messagePlaceholder struct {
+	key      string
+	optional bool
+}
+
+// MessagePlaceholder can render a placeholder to a single message in params, e.g. the current user input.
+// If optional is false, the key is required, and formatting fails if it's missing or nil in params.
+// Formatting also fails if the value isn't *Message.
+// e.g.
+//
+//	chatTemplate := prompt.FromMessages(schema.FString,
+//		schema.SystemMessage("you are eino helper"),
+//		schema.MessagesPlaceholder("history", true),
+//		schema.MessagePlaceholder("input", false), // <= this will use the message of "input" in params
+//	)
+//	msgs, err := chatTemplate.Format(ctx, map[string]any{"input": schema.UserMessage("how to use eino?")})
+func MessagePlaceholder(key string, optional bool) MessagesTemplate {
+	return &messagePlaceholder{
+		key:      key,
+		optional: optional,
+	}
+}
+
+// Format returns the message of the specified key as a list of one message, or an empty list if it's optional and missing.
+func (p *messagePlaceholder) Format(_ context.Context, vs map[string]any, _ FormatType) ([]*Message, error) {
+	v, ok := vs[p.key]
+	if ok && v != nil {
+		msg, ok := v.(*Message)
+		if !ok {
+			return nil, fmt.Errorf("only message can be used to format single message placeholder, key: %v, actual type: %v", p.key, reflect.TypeOf(v))
+		}
+		if msg != nil {
+			return []*Message{msg}, nil
+		}
+	}
+
+	if p.optional {
+		return []*Message{}, nil
+	}
+	return nil, fmt.Errorf("message placeholder format: %s not found", p.key)
+}
+
 func formatContent(content string, vs map[string]any, formatType FormatType) (string, error) {
 	switch formatType {
 	case FString:
@@ -804,6 +864,12 @@ func (m *Message) String() string {
 			sb.WriteString(fmt.Sprintf("%+v\n", tc))
 		}
 	}
+	if len(m.Attachments) > 0 {
+		sb.WriteString("\nattachments:")
+		for _, a := range m.Attachments {
+			sb.WriteString(fmt.Sprintf("\n%s(%s, %d bytes)", a.Name, a.MIMEType, a.GetSize()))
+		}
+	}
 	if m.ToolCallID != "" {
 		sb.WriteString(fmt.Sprintf("\ntool_call_id: %s", m.ToolCallID))
 	}
@@ -1095,6 +1161,7 @@ func ConcatMessages(msgs []*Message) (*Message, error) {
 		reasoningContents             []string
 		reasoningContentLen           int
 		toolCalls                     []ToolCall
+		attachments                   []Attachment
 		multiContentParts             []ChatMessagePart
 		assistantGenMultiContentParts []MessageOutputPart
 		ret                           = Message{}
@@ -1154,6 +1221,10 @@ func ConcatMessages(msgs []*Message) (*Message, error) {
 			toolCalls = append(toolCalls, msg.ToolCalls...)
 		}
 
+		if len(msg.Attachments) > 0 {
+			attachments = append(attachments, msg.Attachments...)
+		}
+
 		if len(msg.Extra) > 0 {
 			extraList = append(extraList, msg.Extra)
 		}
@@ -1198,6 +1269,17 @@ func ConcatMessages(msgs []*Message) (*Message, error) {
 				}
 			}
 
+			// keep the last Model and RequestID with a valid value, and the longest Latency.
+			if msg.ResponseMeta.Model != "" {
+				ret.ResponseMeta.Model = msg.ResponseMeta.Model
+			}
+			if msg.ResponseMeta.RequestID != "" {
+				ret.ResponseMeta.RequestID = msg.ResponseMeta.RequestID
+			}
+			if msg.ResponseMeta.Latency > ret.ResponseMeta.Latency {
+				ret.ResponseMeta.Latency = msg.ResponseMeta.Latency
+			}
+
 			if msg.ResponseMeta.LogProbs != nil {
 				if ret.ResponseMeta.LogProbs == nil {
 					ret.ResponseMeta.LogProbs = &LogProbs{}
@@ -1243,6 +1325,15 @@ func ConcatMessages(msgs []*Message) (*Message, error) {
 		ret.ToolCalls = merged
 	}
 
+	if len(attachments) > 0 {
+		merged, err := concatAttachments(attachments)
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Attachments = merged
+	}
+
 	if len(extraList) > 0 {
 		extra, err := concatExtra(extraList)
 		if err != nil {
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// MessageSource is a message history from one agent or session, to be merged by MergeMessages.
+type MessageSource struct {
+	// Name identifies the source, e.g. the name of the agent, which is passed to the restamp function of WithRestamp.
+	Name string
+	// Messages is the history of the source in order.
+	Messages []*Message
+}
+
+// MergeOption is the option of MergeMessages.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	restamp     func(source string, m *Message) *Message
+	keepOrphans bool
+}
+
+// WithRestamp sets the function re-stamping each merged message, e.g. to turn the answers of the other agents into
+// user messages for a supervisor model, see AssistantAsUser.
+// The function receives a shallow copy of the message, so it can change the fields without affecting the source,
+// and returning nil drops the message.
+func WithRestamp(fn func(source string, m *Message) *Message) MergeOption {
+	return func(o *mergeOptions) {
+		o.restamp = fn
+	}
+}
+
+// WithOrphanToolMessages keeps the tool messages not following an assistant message calling them, which are dropped by default,
+// since most models reject the tool messages without a matching tool call.
+func WithOrphanToolMessages() MergeOption {
+	return func(o *mergeOptions) {
+		o.keepOrphans = true
+	}
+}
+
+// AssistantAsUser is a restamp function for WithRestamp turning the assistant messages without tool calls into user messages
+// named after the source, so that a supervisor model sees the answers of the agents as inputs instead of its own outputs.
+// The other messages are kept as they are.
+func AssistantAsUser(source string, m *Message) *Message {
+	if m.Role == Assistant && len(m.ToolCalls) == 0 {
+		m.Role = User
+		m.Name = source
+	}
+	return m
+}
+
+// MergeMessages merges the message histories of the sources into one transcript, source by source in order.
+// The messages having the same content hash as a merged one are dropped, see MessageHash, e.g. the shared system prompt
+// and user input, while an assistant message calling tools and the tool messages answering it are deduplicated as a whole,
+// so that a tool call is never separated from its results.
+// The sources are never modified.
+func MergeMessages(sources []*MessageSource, opts ...MergeOption) []*Message {
+	o := &mergeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var (
+		ret  []*Message
+		seen = make(map[string]bool)
+	)
+	for _, src := range sources {
+		if src == nil {
+			continue
+		}
+		for _, group := range groupToolExchanges(src.Messages, o.keepOrphans) {
+			h := groupHash(group)
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+
+			for _, m := range group {
+				if o.restamp != nil {
+					cp := *m
+					if m = o.restamp(src.Name, &cp); m == nil {
+						continue
+					}
+				}
+				ret = append(ret, m)
+			}
+		}
+	}
+
+	return ret
+}
+
+// groupToolExchanges splits the messages into groups deduplicated as a whole,
+// which is an assistant message calling tools along with the tool messages answering it, or a single message otherwise.
+func groupToolExchanges(msgs []*Message, keepOrphans bool) [][]*Message {
+	var (
+		groups  [][]*Message
+		pending map[string]bool // ids of the tool calls of the last group
+	)
+	for _, m := range msgs {
+		if m == nil {
+			continue
+		}
+		if m.Role == Tool {
+			if pending[m.ToolCallID] {
+				delete(pending, m.ToolCallID)
+				groups[len(groups)-1] = append(groups[len(groups)-1], m)
+			} else if keepOrphans {
+				groups = append(groups, []*Message{m})
+			}
+			continue
+		}
+
+		pending = nil
+		if m.Role == Assistant && len(m.ToolCalls) > 0 {
+			pending = make(map[string]bool, len(m.ToolCalls))
+			for _, tc := range m.ToolCalls {
+				pending[tc.ID] = true
+			}
+		}
+		groups = append(groups, []*Message{m})
+	}
+	return groups
+}
+
+func groupHash(group []*Message) string {
+	if len(group) == 1 {
+		return MessageHash(group[0])
+	}
+	h := sha256.New()
+	for _, m := range group {
+		h.Write([]byte(MessageHash(m)))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type hashedToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type hashedMessage struct {
+	Role                     RoleType            `json:"role"`
+	Name                     string              `json:"name,omitempty"`
+	Content                  string              `json:"content"`
+	MultiContent             []ChatMessagePart   `json:"multi_content,omitempty"`
+	UserInputMultiContent    []MessageInputPart  `json:"user_input_multi_content,omitempty"`
+	AssistantGenMultiContent []MessageOutputPart `json:"assistant_output_multi_content,omitempty"`
+	ToolCalls                []hashedToolCall    `json:"tool_calls,omitempty"`
+	Attachments              []Attachment        `json:"attachments,omitempty"`
+	ToolCallID               string              `json:"tool_call_id,omitempty"`
+	ToolName                 string              `json:"tool_name,omitempty"`
+}
+
+// MessageHash returns the hex encoded SHA-256 hash of the content of the message, i.e. the role, name, contents,
+// tool calls, tool call id and attachments, ignoring the response meta, the reasoning content and the extra.
+func MessageHash(m *Message) string {
+	if m == nil {
+		return ""
+	}
+	hm := &hashedMessage{
+		Role:                     m.Role,
+		Name:                     m.Name,
+		Content:                  m.Content,
+		MultiContent:             m.MultiContent,
+		UserInputMultiContent:    m.UserInputMultiContent,
+		AssistantGenMultiContent: m.AssistantGenMultiContent,
+		ToolCallID:               m.ToolCallID,
+		ToolName:                 m.ToolName,
+		Attachments:              m.Attachments,
+	}
+	for _, tc := range m.ToolCalls {
+		hm.ToolCalls = append(hm.ToolCalls, hashedToolCall{ID: tc.ID, Name: tc.Function.Name, Arguments: tc.Function.Arguments})
+	}
+
+	b, err := json.Marshal(hm)
+	if err != nil {
+		// the extra of the multimodal parts may hold values not marshalable, fall back to the readable form
+		b = []byte(m.String())
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
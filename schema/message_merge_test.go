@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeMessages(t *testing.T) {
+	sys := SystemMessage("you are helpful")
+	question := UserMessage("what's the weather?")
+	call := AssistantMessage("", []ToolCall{{ID: "1", Function: FunctionCall{Name: "weather", Arguments: `{"city":"beijing"}`}}})
+	result := ToolMessage("sunny", "1")
+
+	a := []*Message{sys, question, call, result, AssistantMessage("it's sunny", nil)}
+	b := []*Message{
+		SystemMessage("you are helpful"),
+		UserMessage("what's the weather?"),
+		// the same tool call answered differently is kept with its own result
+		AssistantMessage("", []ToolCall{{ID: "1", Function: FunctionCall{Name: "weather", Arguments: `{"city":"beijing"}`}}}),
+		ToolMessage("rainy", "1"),
+		ToolMessage("orphan", "2"),
+		AssistantMessage("it's rainy", nil),
+	}
+
+	merged := MergeMessages([]*MessageSource{{Name: "a", Messages: a}, nil, {Name: "b", Messages: b}})
+	assert.Equal(t, []*Message{sys, question, call, result, a[4], b[2], b[3], b[5]}, merged)
+
+	merged = MergeMessages([]*MessageSource{{Name: "a", Messages: a}, {Name: "b", Messages: b}}, WithOrphanToolMessages())
+	assert.Len(t, merged, 9)
+	assert.Equal(t, "orphan", merged[7].Content)
+
+	// the whole exchange is deduplicated
+	merged = MergeMessages([]*MessageSource{{Name: "a", Messages: a}, {Name: "a2", Messages: a}})
+	assert.Equal(t, a, merged)
+
+	merged = MergeMessages([]*MessageSource{{Name: "a", Messages: a}, {Name: "b", Messages: b}}, WithRestamp(func(source string, m *Message) *Message {
+		if m.Role == System && source != "a" {
+			return nil
+		}
+		return AssistantAsUser(source, m)
+	}))
+	assert.Len(t, merged, 8)
+	assert.Equal(t, User, merged[4].Role)
+	assert.Equal(t, "a", merged[4].Name)
+	assert.Equal(t, "it's sunny", merged[4].Content)
+	assert.Equal(t, Assistant, merged[2].Role)
+	assert.Equal(t, User, merged[7].Role)
+	assert.Equal(t, "b", merged[7].Name)
+	// the sources aren't modified
+	assert.Equal(t, Assistant, a[4].Role)
+	assert.Empty(t, a[4].Name)
+}
+
+func TestMessageHash(t *testing.T) {
+	m := AssistantMessage("hi", nil)
+	m2 := AssistantMessage("hi", nil)
+	m2.ResponseMeta = &ResponseMeta{FinishReason: "stop"}
+	m2.Extra = map[string]any{"k": "v"}
+	assert.Equal(t, MessageHash(m), MessageHash(m2))
+	assert.NotEqual(t, MessageHash(m), MessageHash(UserMessage("hi")))
+	assert.NotEqual(t, MessageHash(ToolMessage("x", "1")), MessageHash(ToolMessage("x", "2")))
+	assert.Len(t, MessageHash(m), 64)
+	assert.Empty(t, MessageHash(nil))
+}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -588,6 +589,23 @@ func TestConcatMessage(t *testing.T) {
 
 		assert.Equal(t, expectedMultiContent, mergedMsg.MultiContent)
 	})
+
+	t.Run("concat response meta model, request id and latency", func(t *testing.T) {
+		msgs := []*Message{
+			{Role: Assistant, Content: "a", ResponseMeta: &ResponseMeta{Model: "m-1", RequestID: "req-1", Latency: time.Second}},
+			{Role: Assistant, Content: "b", ResponseMeta: &ResponseMeta{Latency: 3 * time.Second}},
+			{Role: Assistant, Content: "c", ResponseMeta: &ResponseMeta{FinishReason: "stop", Model: "m-1-0801", Latency: 2 * time.Second}},
+		}
+
+		mergedMsg, err := ConcatMessages(msgs)
+		assert.NoError(t, err)
+		assert.Equal(t, &ResponseMeta{
+			FinishReason: "stop",
+			Model:        "m-1-0801",
+			RequestID:    "req-1",
+			Latency:      3 * time.Second,
+		}, mergedMsg.ResponseMeta)
+	})
 }
 
 func TestConcatToolCalls(t *testing.T) {
@@ -1008,3 +1026,60 @@ func TestFormatUserInputMultiContent(t *testing.T) {
 		}
 	})
 }
+
+func TestMessageTemplateVariables(t *testing.T) {
+	vars, err := UserMessage("{name} asks {{escaped}}: {question.text} {items[0]:>10}").TemplateVariables(FString)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"items", "name", "question"}, vars)
+
+	vars, err = UserMessage("{{.name}}{{with .user}}{{.id}}{{end}}{{range $i, $v := .list}}{{$.lang}}{{end}}").TemplateVariables(GoTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lang", "list", "name", "user"}, vars)
+
+	_, err = UserMessage("{{name}}").TemplateVariables(Jinja2)
+	assert.ErrorIs(t, err, ErrTemplateVariablesUnsupported)
+
+	_, err = UserMessage("{name").TemplateVariables(FString)
+	assert.Error(t, err)
+}
+
+func TestMessagePlaceholders(t *testing.T) {
+	ctx := context.Background()
+	m1 := UserMessage("how are you?")
+	m2 := AssistantMessage("I'm good. how about you?", nil)
+
+	_, err := MessagesPlaceholder("history", false).Format(ctx, map[string]any{"history": nil}, FString)
+	assert.ErrorContains(t, err, "history not found")
+	ms, err := MessagesPlaceholder("history", true).Format(ctx, map[string]any{"history": nil}, FString)
+	assert.NoError(t, err)
+	assert.Empty(t, ms)
+	_, err = MessagesPlaceholder("history", false).Format(ctx, map[string]any{"history": []*Message{m1, nil}}, FString)
+	assert.ErrorContains(t, err, "history[1] is nil")
+
+	ms, err = MessagePlaceholder("input", false).Format(ctx, map[string]any{"input": m1}, FString)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Message{m1}, ms)
+	_, err = MessagePlaceholder("input", false).Format(ctx, map[string]any{"input": (*Message)(nil)}, FString)
+	assert.ErrorContains(t, err, "input not found")
+	_, err = MessagePlaceholder("input", false).Format(ctx, map[string]any{"input": []*Message{m1}}, FString)
+	assert.Error(t, err)
+	ms, err = MessagePlaceholder("input", true).Format(ctx, map[string]any{}, FString)
+	assert.NoError(t, err)
+	assert.Empty(t, ms)
+
+	vars, err := MessagePlaceholder("input", false).(TemplateVariablesLister).TemplateVariables(FString)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"input"}, vars)
+
+	var all []*Message
+	for _, tpl := range []MessagesTemplate{
+		MessagesPlaceholder("memory", true),
+		MessagesPlaceholder("history", false),
+		MessagePlaceholder("input", false),
+	} {
+		ms, err = tpl.Format(ctx, map[string]any{"history": []*Message{m1, m2}, "input": m1}, FString)
+		assert.NoError(t, err)
+		all = append(all, ms...)
+	}
+	assert.Equal(t, []*Message{m1, m2, m1}, all)
+}
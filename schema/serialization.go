@@ -52,6 +52,8 @@ func init() {
 	RegisterName[MessagePartCommon]("_eino_message_part_common")
 	RegisterName[ImageURLDetail]("_eino_image_url_detail")
 	RegisterName[PromptTokenDetails]("_eino_prompt_token_details")
+	RegisterName[Attachment]("_eino_attachment")
+	RegisterName[AudioChunk]("_eino_audio_chunk")
 }
 
 // RegisterName registers a type with a specific name for serialization. This is
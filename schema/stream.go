@@ -26,6 +26,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/cloudwego/eino/einoerr"
 	"github.com/cloudwego/eino/internal/safe"
 )
 
@@ -49,7 +50,7 @@ var ErrNoValue = errors.New("no value")
 
 // ErrRecvAfterClosed indicates that StreamReader.Recv was unexpectedly called after StreamReader.Close.
 // This error should not occur during normal use of StreamReader.Recv. If it does, please check your application code.
-var ErrRecvAfterClosed = errors.New("recv after stream closed")
+var ErrRecvAfterClosed = einoerr.ErrStreamClosed
 
 // SourceEOF represents an EOF error from a specific source stream.
 // It is only returned by the method Recv() of StreamReader created
@@ -137,6 +138,23 @@ func (sw *StreamWriter[T]) Close() {
 	sw.stm.closeSend()
 }
 
+// ClosedError returns the error passed to StreamReader.CloseWithError by the receiver,
+// nil if the receiver hasn't closed the stream or closed it by StreamReader.Close.
+// It's useful to learn why the stream is closed after Send reports closed, e.g.
+//
+//	if closed := sw.Send(chunk, nil); closed {
+//		log.Printf("stream aborted: %v", sw.ClosedError())
+//		return
+//	}
+func (sw *StreamWriter[T]) ClosedError() error {
+	select {
+	case <-sw.stm.closed:
+		return sw.stm.closeErr
+	default:
+		return nil
+	}
+}
+
 // StreamReader the receiver of a stream.
 // created by Pipe function.
 // eg.
@@ -225,6 +243,30 @@ func (sr *StreamReader[T]) Close() {
 	}
 }
 
+// CloseWithError closes the StreamReader like Close, with err as the reason,
+// which can be retrieved by StreamWriter.ClosedError on the sender side, including the senders of the source streams
+// of a converted, merged or copied StreamReader. For a copied StreamReader,
+// the first error of the copies is passed to the source once all the copies are closed.
+// e.g.
+//
+//	sr.CloseWithError(errors.New("stop generating"))
+func (sr *StreamReader[T]) CloseWithError(err error) {
+	switch sr.typ {
+	case readerTypeStream:
+		sr.st.closeRecvWithError(err)
+	case readerTypeArray:
+
+	case readerTypeMultiStream:
+		sr.msr.closeWithError(err)
+	case readerTypeWithConvert:
+		sr.srw.sr.CloseWithError(err)
+	case readerTypeChild:
+		sr.csr.parent.closeWithError(sr.csr.index, err)
+	default:
+		panic("impossible")
+	}
+}
+
 // Copy creates a slice of new StreamReader.
 // The number of copies, indicated by the parameter n, should be a non-zero positive integer.
 // The original StreamReader will become unusable after Copy.
@@ -346,8 +388,10 @@ const (
 
 type iStreamReader interface {
 	recvAny() (any, error)
+	ChunkMeta() *ChunkMeta
 	copyAny(int) []iStreamReader
 	Close()
+	CloseWithError(error)
 	SetAutomaticClose()
 }
 
@@ -361,11 +405,17 @@ type stream[T any] struct {
 
 	automaticClose bool
 	closedFlag     *uint32 // 0 = not closed, 1 = closed, only used when automaticClose is set
+
+	stamper  *chunkStamper // used by the sender only
+	lastMeta *ChunkMeta    // used by the receiver only
+
+	closeErr error // set by the receiver before closing, read by the sender after closed
 }
 
 type streamItem[T any] struct {
 	chunk T
 	err   error
+	meta  *ChunkMeta
 }
 
 func newStream[T any](cap int) *stream[T] {
@@ -385,11 +435,20 @@ func (s *stream[T]) recv() (chunk T, err error) {
 	if !ok {
 		item.err = io.EOF
 	}
+	s.lastMeta = item.meta
 
 	return item.chunk, item.err
 }
 
 func (s *stream[T]) send(chunk T, err error) (closed bool) {
+	var meta *ChunkMeta
+	if s.stamper != nil {
+		meta = s.stamper.next()
+	}
+	return s.sendWithMeta(chunk, err, meta)
+}
+
+func (s *stream[T]) sendWithMeta(chunk T, err error, meta *ChunkMeta) (closed bool) {
 	// if the stream is closed, return immediately
 	select {
 	case <-s.closed:
@@ -397,7 +456,7 @@ func (s *stream[T]) send(chunk T, err error) (closed bool) {
 	default:
 	}
 
-	item := streamItem[T]{chunk, err}
+	item := streamItem[T]{chunk, err, meta}
 
 	select {
 	case <-s.closed:
@@ -411,6 +470,16 @@ func (s *stream[T]) closeSend() {
 	close(s.items)
 }
 
+func (s *stream[T]) closeRecvWithError(err error) {
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+	s.closeErr = err
+	s.closeRecv()
+}
+
 func (s *stream[T]) closeRecv() {
 	if s.automaticClose {
 		if atomic.CompareAndSwapUint32(s.closedFlag, 0, 1) {
@@ -484,6 +553,8 @@ type multiStreamReader[T any] struct {
 	nonClosed []int
 
 	sourceReaderNames []string
+
+	lastMeta *ChunkMeta
 }
 
 func newMultiStreamReader[T any](sts []*stream[T]) *multiStreamReader[T] {
@@ -511,6 +582,7 @@ func newMultiStreamReader[T any](sts []*stream[T]) *multiStreamReader[T] {
 }
 
 func (msr *multiStreamReader[T]) recv() (T, error) {
+	msr.lastMeta = nil
 	for len(msr.nonClosed) > 0 {
 		var chosen int
 		var ok bool
@@ -519,6 +591,7 @@ func (msr *multiStreamReader[T]) recv() (T, error) {
 			chosen, recv, ok = reflect.Select(msr.itemsCases)
 			if ok {
 				item := recv.Interface().(streamItem[T])
+				msr.lastMeta = item.meta
 				return item.chunk, item.err
 			}
 			msr.itemsCases[chosen].Chan = reflect.Value{}
@@ -526,6 +599,7 @@ func (msr *multiStreamReader[T]) recv() (T, error) {
 			var item *streamItem[T]
 			chosen, item, ok = receiveN(msr.nonClosed, msr.sts)
 			if ok {
+				msr.lastMeta = item.meta
 				return item.chunk, item.err
 			}
 		}
@@ -564,6 +638,16 @@ func (msr *multiStreamReader[T]) close() {
 	}
 }
 
+func (msr *multiStreamReader[T]) closeWithError(err error) {
+	for _, s := range msr.sts {
+		s.closeRecvWithError(err)
+	}
+}
+
+func (msr *multiStreamReader[T]) chunkMeta() *ChunkMeta {
+	return msr.lastMeta
+}
+
 func (msr *multiStreamReader[T]) toStream() *stream[T] {
 	return toStream[T, *multiStreamReader[T]](msr)
 }
@@ -572,6 +656,9 @@ type streamReaderWithConvert[T any] struct {
 	sr iStreamReader
 
 	convert func(any) (T, error)
+
+	stamper  *chunkStamper
+	lastMeta *ChunkMeta
 }
 
 func newStreamReaderWithConvert[T any](origin iStreamReader, convert func(any) (T, error)) *StreamReader[T] {
@@ -609,6 +696,11 @@ func StreamReaderWithConvert[T, D any](sr *StreamReader[T], convert func(T) (D,
 func (srw *streamReaderWithConvert[T]) recv() (T, error) {
 	for {
 		out, err := srw.sr.recvAny()
+		if srw.stamper != nil && err == nil {
+			srw.lastMeta = srw.stamper.next()
+		} else {
+			srw.lastMeta = srw.sr.ChunkMeta()
+		}
 
 		if err != nil {
 			var t T
@@ -630,9 +722,19 @@ func (srw *streamReaderWithConvert[T]) close() {
 	srw.sr.Close()
 }
 
+func (srw *streamReaderWithConvert[T]) closeWithError(err error) {
+	srw.sr.CloseWithError(err)
+}
+
+func (srw *streamReaderWithConvert[T]) chunkMeta() *ChunkMeta {
+	return srw.lastMeta
+}
+
 type reader[T any] interface {
 	recv() (T, error)
+	chunkMeta() *ChunkMeta
 	close()
+	closeWithError(error)
 }
 
 func toStream[T any, Reader reader[T]](r Reader) *stream[T] {
@@ -649,6 +751,14 @@ func toStream[T any, Reader reader[T]](r Reader) *stream[T] {
 			}
 
 			ret.closeSend()
+			select {
+			case <-ret.closed:
+				if ret.closeErr != nil {
+					r.closeWithError(ret.closeErr)
+					return
+				}
+			default:
+			}
 			r.close()
 		}()
 
@@ -658,7 +768,7 @@ func toStream[T any, Reader reader[T]](r Reader) *stream[T] {
 				break
 			}
 
-			closed := ret.send(out, err)
+			closed := ret.sendWithMeta(out, err, r.chunkMeta())
 			if closed {
 				break
 			}
@@ -721,15 +831,19 @@ type parentStreamReader[T any] struct {
 
 	// closedNum is the count of closed children.
 	closedNum uint32
+
+	// closeErr is the first error passed to CloseWithError of the children.
+	closeErr     error
+	closeErrOnce sync.Once
 }
 
 // peek is not safe for concurrent use with the same idx but is safe for different idx.
 // Ensure that each child StreamReader uses a for-loop in a single goroutine.
-func (p *parentStreamReader[T]) peek(idx int) (t T, err error) {
+func (p *parentStreamReader[T]) peek(idx int) (t T, meta *ChunkMeta, err error) {
 	elem := p.subStreamList[idx]
 	if elem == nil {
 		// Unexpected call to receive after the child has been closed.
-		return t, ErrRecvAfterClosed
+		return t, nil, ErrRecvAfterClosed
 	}
 
 	// The sync.Once here is used to:
@@ -738,7 +852,7 @@ func (p *parentStreamReader[T]) peek(idx int) (t T, err error) {
 	//    similar to the initialization in copyStreamReaders.
 	elem.once.Do(func() {
 		t, err = p.sr.Recv()
-		elem.item = streamItem[T]{chunk: t, err: err}
+		elem.item = streamItem[T]{chunk: t, err: err, meta: p.sr.ChunkMeta()}
 		if err != io.EOF {
 			elem.next = &cpStreamElement[T]{}
 			p.subStreamList[idx] = elem.next
@@ -753,7 +867,7 @@ func (p *parentStreamReader[T]) peek(idx int) (t T, err error) {
 		p.subStreamList[idx] = elem.next
 	}
 
-	return t, err
+	return t, elem.item.meta, err
 }
 
 func (p *parentStreamReader[T]) close(idx int) {
@@ -767,17 +881,45 @@ func (p *parentStreamReader[T]) close(idx int) {
 
 	allClosed := int(curClosedNum) == len(p.subStreamList)
 	if allClosed {
+		if p.closeErr != nil {
+			p.sr.CloseWithError(p.closeErr)
+			return
+		}
 		p.sr.Close()
 	}
 }
 
+func (p *parentStreamReader[T]) closeWithError(idx int, err error) {
+	if p.subStreamList[idx] == nil {
+		return // avoid close multiple times
+	}
+	if err != nil {
+		p.closeErrOnce.Do(func() {
+			p.closeErr = err
+		})
+	}
+	p.close(idx)
+}
+
 type childStreamReader[T any] struct {
 	parent *parentStreamReader[T]
 	index  int
+
+	lastMeta *ChunkMeta
 }
 
 func (csr *childStreamReader[T]) recv() (T, error) {
-	return csr.parent.peek(csr.index)
+	t, meta, err := csr.parent.peek(csr.index)
+	csr.lastMeta = meta
+	return t, err
+}
+
+func (csr *childStreamReader[T]) closeWithError(err error) {
+	csr.parent.closeWithError(csr.index, err)
+}
+
+func (csr *childStreamReader[T]) chunkMeta() *ChunkMeta {
+	return csr.lastMeta
 }
 
 func (csr *childStreamReader[T]) toStream() *stream[T] {
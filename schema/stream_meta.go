@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"time"
+)
+
+// ChunkMeta is the metadata of a stream chunk, attached only when it's enabled, see StreamWriter.EnableChunkMeta and StreamReaderWithChunkMeta.
+// The metadata travels along with the chunk through Copy, StreamReaderWithConvert and the merging of the StreamReaders,
+// so the consumers can measure the inter-chunk latency and reconstruct the order of the chunks.
+type ChunkMeta struct {
+	// Seq is the sequence number of the chunk at its origin, starting from 0.
+	Seq int64
+	// Time is when the chunk is produced.
+	Time time.Time
+	// Origin names where the chunk is produced, e.g. the graph node key.
+	Origin string
+}
+
+type chunkStamper struct {
+	origin string
+	seq    int64
+}
+
+func (c *chunkStamper) next() *ChunkMeta {
+	meta := &ChunkMeta{Seq: c.seq, Time: time.Now(), Origin: c.origin}
+	c.seq++
+	return meta
+}
+
+// EnableChunkMeta attaches the ChunkMeta with the origin to every item sent afterward.
+// It should be called before sending, in the goroutine sending the chunks.
+// e.g.
+//
+//	sr, sw := schema.Pipe[string](3)
+//	sw.EnableChunkMeta("producer")
+//	sw.Send("hello", nil)
+//
+//	chunk, _ := sr.Recv()
+//	meta := sr.ChunkMeta() // &ChunkMeta{Seq: 0, Time: ..., Origin: "producer"}
+func (sw *StreamWriter[T]) EnableChunkMeta(origin string) {
+	sw.stm.stamper = &chunkStamper{origin: origin}
+}
+
+// ChunkMeta returns the metadata of the chunk last received by Recv, nil if it has none.
+// Not concurrency safe, call it in the goroutine calling Recv.
+func (sr *StreamReader[T]) ChunkMeta() *ChunkMeta {
+	switch sr.typ {
+	case readerTypeStream:
+		return sr.st.lastMeta
+	case readerTypeMultiStream:
+		return sr.msr.chunkMeta()
+	case readerTypeWithConvert:
+		return sr.srw.chunkMeta()
+	case readerTypeChild:
+		return sr.csr.chunkMeta()
+	default:
+		return nil
+	}
+}
+
+// StreamReaderWithChunkMeta returns a StreamReader attaching the ChunkMeta with the origin to every chunk received,
+// replacing the metadata attached by the upstream, if any.
+// The time of the metadata is when the chunk is received from sr.
+func StreamReaderWithChunkMeta[T any](sr *StreamReader[T], origin string) *StreamReader[T] {
+	ret := newStreamReaderWithConvert(sr, func(a any) (T, error) {
+		return a.(T), nil
+	})
+	ret.srw.stamper = &chunkStamper{origin: origin}
+	return ret
+}
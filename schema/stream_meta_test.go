@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkMeta(t *testing.T) {
+	newStream := func(origin string, vals ...int) *StreamReader[int] {
+		sr, sw := Pipe[int](len(vals))
+		sw.EnableChunkMeta(origin)
+		for _, v := range vals {
+			sw.Send(v, nil)
+		}
+		sw.Close()
+		return sr
+	}
+	recvAll := func(sr *StreamReader[int]) (vals []int, metas []*ChunkMeta) {
+		defer sr.Close()
+		for {
+			v, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			assert.NoError(t, err)
+			vals = append(vals, v)
+			metas = append(metas, sr.ChunkMeta())
+		}
+	}
+
+	t.Run("pipe", func(t *testing.T) {
+		vals, metas := recvAll(newStream("a", 1, 2))
+		assert.Equal(t, []int{1, 2}, vals)
+		assert.Equal(t, int64(0), metas[0].Seq)
+		assert.Equal(t, int64(1), metas[1].Seq)
+		assert.Equal(t, "a", metas[1].Origin)
+		assert.False(t, metas[1].Time.Before(metas[0].Time))
+
+		sr, sw := Pipe[int](1)
+		sw.Send(1, nil)
+		sw.Close()
+		_, metas = recvAll(sr)
+		assert.Nil(t, metas[0])
+	})
+
+	t.Run("copy and convert", func(t *testing.T) {
+		srs := newStream("a", 1, 2).Copy(2)
+		_, metas0 := recvAll(srs[0])
+		_, metas1 := recvAll(StreamReaderWithConvert(srs[1], func(i int) (int, error) {
+			if i == 1 {
+				return 0, ErrNoValue
+			}
+			return i * 10, nil
+		}))
+		assert.Len(t, metas0, 2)
+		assert.Equal(t, []*ChunkMeta{metas0[1]}, metas1)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		conv := StreamReaderWithConvert(newStream("b", 3), func(i int) (int, error) { return i, nil })
+		vals, metas := recvAll(MergeStreamReaders([]*StreamReader[int]{newStream("a", 1, 2), conv}))
+		assert.Len(t, vals, 3)
+		for i, v := range vals {
+			if v == 3 {
+				assert.Equal(t, "b", metas[i].Origin)
+			} else {
+				assert.Equal(t, "a", metas[i].Origin)
+				assert.Equal(t, int64(v-1), metas[i].Seq)
+			}
+		}
+
+		vals, metas = recvAll(MergeStreamReadersOrdered([]*StreamReader[int]{newStream("a", 1), newStream("b", 2)}))
+		assert.Equal(t, []int{1, 2}, vals)
+		assert.Equal(t, "b", metas[1].Origin)
+	})
+
+	t.Run("stamp", func(t *testing.T) {
+		vals, metas := recvAll(StreamReaderWithChunkMeta(StreamReaderFromArray([]int{1, 2}), "node"))
+		assert.Equal(t, []int{1, 2}, vals)
+		assert.Equal(t, "node", metas[0].Origin)
+		assert.Equal(t, int64(1), metas[1].Seq)
+	})
+}
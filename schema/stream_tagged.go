@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"errors"
+	"io"
+	"sort"
+)
+
+// TaggedChunk is a chunk tagged with the key of the source stream producing it.
+type TaggedChunk[T any] struct {
+	Source string
+	Chunk  T
+}
+
+// MergeStreamReadersTagged merges the StreamReaders into one, tagging every chunk with the key of its source,
+// e.g. to tell which parallel branch produced each chunk.
+// Chunks are received in the order they become available, and chunks of the same source keep their order.
+// Errors of the sources are returned as they are.
+// e.g.
+//
+//	sr := schema.MergeStreamReadersTagged(map[string]*schema.StreamReader[string]{"a": sr1, "b": sr2})
+//	defer sr.Close()
+//	for {
+//		tc, err := sr.Recv()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		fmt.Println(tc.Source, tc.Chunk)
+//	}
+func MergeStreamReadersTagged[T any](srs map[string]*StreamReader[T]) *StreamReader[TaggedChunk[T]] {
+	if len(srs) < 1 {
+		return nil
+	}
+
+	keys := sortedKeys(srs)
+	tagged := make([]*StreamReader[TaggedChunk[T]], 0, len(keys))
+	for _, key := range keys {
+		tagged = append(tagged, tagStreamReader(key, srs[key]))
+	}
+	return MergeStreamReaders(tagged)
+}
+
+// MergeStreamReadersOrdered merges the StreamReaders into one by draining them sequentially,
+// i.e. all chunks of srs[0] are received before those of srs[1], and so on.
+// The sources later in the order are buffered by their producers in the meantime.
+// An error of a source is returned and the following chunks of it are still received, the same as the StreamReader itself.
+// Closing the merged StreamReader closes all the sources. The ChunkMeta of the chunks, if any, is kept.
+func MergeStreamReadersOrdered[T any](srs []*StreamReader[T]) *StreamReader[T] {
+	if len(srs) < 1 {
+		return nil
+	}
+	if len(srs) < 2 {
+		return srs[0]
+	}
+
+	sr, sw := Pipe[T](0)
+	go func() {
+		defer func() {
+			for _, s := range srs {
+				s.Close()
+			}
+			sw.Close()
+		}()
+
+		for _, s := range srs {
+			for {
+				chunk, err := s.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if closed := sw.stm.sendWithMeta(chunk, err, s.ChunkMeta()); closed {
+					return
+				}
+			}
+		}
+	}()
+	return sr
+}
+
+// MergeStreamReadersTaggedOrdered is the ordered variant of MergeStreamReadersTagged,
+// the sources are drained sequentially in the order of keys, followed by the sources not in keys sorted by their keys.
+func MergeStreamReadersTaggedOrdered[T any](keys []string, srs map[string]*StreamReader[T]) *StreamReader[TaggedChunk[T]] {
+	if len(srs) < 1 {
+		return nil
+	}
+
+	listed := make(map[string]bool, len(keys))
+	tagged := make([]*StreamReader[TaggedChunk[T]], 0, len(srs))
+	for _, key := range keys {
+		sr, ok := srs[key]
+		if !ok || listed[key] {
+			continue
+		}
+		listed[key] = true
+		tagged = append(tagged, tagStreamReader(key, sr))
+	}
+	for _, key := range sortedKeys(srs) {
+		if !listed[key] {
+			tagged = append(tagged, tagStreamReader(key, srs[key]))
+		}
+	}
+	return MergeStreamReadersOrdered(tagged)
+}
+
+func tagStreamReader[T any](key string, sr *StreamReader[T]) *StreamReader[TaggedChunk[T]] {
+	return StreamReaderWithConvert(sr, func(chunk T) (TaggedChunk[T], error) {
+		return TaggedChunk[T]{Source: key, Chunk: chunk}, nil
+	})
+}
+
+func sortedKeys[T any](srs map[string]*StreamReader[T]) []string {
+	keys := make([]string, 0, len(srs))
+	for key := range srs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectTagged[T any](t *testing.T, sr *StreamReader[TaggedChunk[T]]) []TaggedChunk[T] {
+	defer sr.Close()
+	var ret []TaggedChunk[T]
+	for {
+		tc, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return ret
+		}
+		assert.NoError(t, err)
+		ret = append(ret, tc)
+	}
+}
+
+func TestMergeStreamReadersTagged(t *testing.T) {
+	sr1, sw1 := Pipe[int](3)
+	go func() {
+		defer sw1.Close()
+		for i := 0; i < 3; i++ {
+			sw1.Send(i, nil)
+		}
+	}()
+	sr2 := StreamReaderFromArray([]int{10, 11})
+
+	chunks := collectTagged(t, MergeStreamReadersTagged(map[string]*StreamReader[int]{"a": sr1, "b": sr2}))
+	assert.Len(t, chunks, 5)
+	bySource := map[string][]int{}
+	for _, c := range chunks {
+		bySource[c.Source] = append(bySource[c.Source], c.Chunk)
+	}
+	assert.Equal(t, map[string][]int{"a": {0, 1, 2}, "b": {10, 11}}, bySource)
+
+	assert.Nil(t, MergeStreamReadersTagged[int](nil))
+}
+
+func TestMergeStreamReadersOrdered(t *testing.T) {
+	newPipe := func(vals ...int) *StreamReader[int] {
+		sr, sw := Pipe[int](len(vals))
+		go func() {
+			defer sw.Close()
+			for _, v := range vals {
+				sw.Send(v, nil)
+			}
+		}()
+		return sr
+	}
+
+	t.Run("untagged", func(t *testing.T) {
+		sr := MergeStreamReadersOrdered([]*StreamReader[int]{newPipe(1, 2), StreamReaderFromArray([]int{3}), newPipe(4, 5)})
+		var got []int
+		for {
+			v, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			got = append(got, v)
+		}
+		sr.Close()
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	})
+
+	t.Run("tagged", func(t *testing.T) {
+		chunks := collectTagged(t, MergeStreamReadersTaggedOrdered([]string{"z", "missing", "a"}, map[string]*StreamReader[int]{
+			"a": newPipe(1),
+			"m": newPipe(2),
+			"z": newPipe(3, 4),
+		}))
+		assert.Equal(t, []TaggedChunk[int]{{"z", 3}, {"z", 4}, {"a", 1}, {"m", 2}}, chunks)
+	})
+
+	t.Run("error and close", func(t *testing.T) {
+		boom := errors.New("boom")
+		src, sw := Pipe[int](2)
+		sw.Send(1, boom)
+		sw.Send(2, nil)
+		sw.Close()
+
+		sr := MergeStreamReadersOrdered([]*StreamReader[int]{src, newPipe(3)})
+		_, err := sr.Recv()
+		assert.ErrorIs(t, err, boom)
+		v, err := sr.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, v)
+		sr.Close()
+	})
+}
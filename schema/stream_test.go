@@ -855,3 +855,43 @@ func TestMergeNamedStreamReaders(t *testing.T) {
 		}
 	})
 }
+
+func TestCloseWithError(t *testing.T) {
+	stop := errors.New("stop")
+
+	t.Run("pipe", func(t *testing.T) {
+		sr, sw := Pipe[int](0)
+		assert.NoError(t, sw.ClosedError())
+		sr.CloseWithError(stop)
+		assert.True(t, sw.Send(1, nil))
+		assert.Equal(t, stop, sw.ClosedError())
+
+		sr, sw = Pipe[int](0)
+		sr.Close()
+		assert.True(t, sw.Send(1, nil))
+		assert.NoError(t, sw.ClosedError())
+	})
+
+	t.Run("convert and merge", func(t *testing.T) {
+		sr1, sw1 := Pipe[int](0)
+		sr2, sw2 := Pipe[int](0)
+		conv := StreamReaderWithConvert(sr1, func(i int) (string, error) { return "", nil })
+		sr3 := StreamReaderWithConvert(sr2, func(i int) (string, error) { return "", nil })
+		MergeStreamReaders([]*StreamReader[string]{conv, sr3}).CloseWithError(stop)
+		// the converted streams are forwarded by goroutines, which find the closing on the next chunk
+		for _, sw := range []*StreamWriter[int]{sw1, sw2} {
+			for !sw.Send(1, nil) {
+			}
+			assert.Equal(t, stop, sw.ClosedError())
+		}
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		sr, sw := Pipe[int](0)
+		srs := sr.Copy(2)
+		srs[0].CloseWithError(stop)
+		assert.NoError(t, sw.ClosedError())
+		srs[1].Close()
+		assert.Equal(t, stop, sw.ClosedError())
+	})
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// ErrTemplateVariablesUnsupported is returned by TemplateVariables when the variables of the format type can't be analyzed statically, e.g. Jinja2.
+var ErrTemplateVariablesUnsupported = errors.New("template variables analysis unsupported")
+
+// TemplateVariablesLister is implemented by the MessagesTemplate which can tell the top-level variables
+// required to format it, so that the missing variables can be found before formatting.
+type TemplateVariablesLister interface {
+	TemplateVariables(formatType FormatType) ([]string, error)
+}
+
+// TemplateVariables returns the sorted top-level variables referenced by the templated texts of the message,
+// i.e. Content, the texts and the URLs of MultiContent and UserInputMultiContent.
+// e.g.
+//
+//	vars, err := schema.UserMessage("{name} asks: {question.text}").TemplateVariables(schema.FString)
+//	// vars is ["name", "question"]
+func (m *Message) TemplateVariables(formatType FormatType) ([]string, error) {
+	texts := []string{m.Content}
+	for _, part := range m.MultiContent {
+		switch {
+		case part.Type == ChatMessagePartTypeText:
+			texts = append(texts, part.Text)
+		case part.ImageURL != nil:
+			texts = append(texts, part.ImageURL.URL)
+		case part.AudioURL != nil:
+			texts = append(texts, part.AudioURL.URL)
+		case part.VideoURL != nil:
+			texts = append(texts, part.VideoURL.URL)
+		case part.FileURL != nil:
+			texts = append(texts, part.FileURL.URL)
+		}
+	}
+	for _, part := range m.UserInputMultiContent {
+		var url *string
+		switch {
+		case part.Type == ChatMessagePartTypeText:
+			texts = append(texts, part.Text)
+		case part.Image != nil:
+			url = part.Image.URL
+		case part.Audio != nil:
+			url = part.Audio.URL
+		case part.Video != nil:
+			url = part.Video.URL
+		case part.File != nil:
+			url = part.File.URL
+		}
+		if url != nil {
+			texts = append(texts, *url)
+		}
+	}
+
+	vars := make(map[string]bool)
+	for _, text := range texts {
+		if len(text) == 0 {
+			continue
+		}
+		if err := collectTemplateVariables(text, formatType, vars); err != nil {
+			return nil, err
+		}
+	}
+	return sortedVariables(vars), nil
+}
+
+// TemplateVariables returns the key of the placeholder, or nothing if it's optional.
+func (p *messagesPlaceholder) TemplateVariables(_ FormatType) ([]string, error) {
+	if p.optional {
+		return nil, nil
+	}
+	return []string{p.key}, nil
+}
+
+// TemplateVariables returns the key of the placeholder, or nothing if it's optional.
+func (p *messagePlaceholder) TemplateVariables(_ FormatType) ([]string, error) {
+	if p.optional {
+		return nil, nil
+	}
+	return []string{p.key}, nil
+}
+
+func collectTemplateVariables(text string, formatType FormatType, vars map[string]bool) error {
+	switch formatType {
+	case FString:
+		return collectFStringVariables(text, vars)
+	case GoTemplate:
+		tpl, err := template.New("template").Parse(text)
+		if err != nil {
+			return err
+		}
+		if tpl.Tree != nil {
+			collectGoTemplateVariables(tpl.Tree.Root, false, vars)
+		}
+		return nil
+	case Jinja2:
+		return fmt.Errorf("%w: jinja2", ErrTemplateVariablesUnsupported)
+	default:
+		return fmt.Errorf("unknown format type: %v", formatType)
+	}
+}
+
+// collectFStringVariables collects the field names of the replacement fields, e.g. name of {name}, {name.attr}, {name[0]} and {name:>10}.
+func collectFStringVariables(text string, vars map[string]bool) error {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			if i+1 < len(text) && text[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return fmt.Errorf("unclosed replacement field in: %s", text)
+			}
+			field := text[i+1 : i+end]
+			if idx := strings.IndexAny(field, ".[:!"); idx >= 0 {
+				field = field[:idx]
+			}
+			if len(field) > 0 {
+				vars[field] = true
+			}
+			i += end
+		case '}':
+			if i+1 < len(text) && text[i+1] == '}' {
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// collectGoTemplateVariables collects the top-level fields referenced with the root dot or $,
+// the fields of dot are skipped in the bodies of range and with, where dot is changed.
+func collectGoTemplateVariables(node parse.Node, dotChanged bool, vars map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			collectGoTemplateVariables(c, dotChanged, vars)
+		}
+	case *parse.ActionNode:
+		collectGoTemplateVariables(n.Pipe, dotChanged, vars)
+	case *parse.IfNode:
+		collectGoTemplateVariables(n.Pipe, dotChanged, vars)
+		collectGoTemplateVariables(n.List, dotChanged, vars)
+		collectGoTemplateVariables(n.ElseList, dotChanged, vars)
+	case *parse.RangeNode:
+		collectGoTemplateVariables(n.Pipe, dotChanged, vars)
+		collectGoTemplateVariables(n.List, true, vars)
+		collectGoTemplateVariables(n.ElseList, dotChanged, vars)
+	case *parse.WithNode:
+		collectGoTemplateVariables(n.Pipe, dotChanged, vars)
+		collectGoTemplateVariables(n.List, true, vars)
+		collectGoTemplateVariables(n.ElseList, dotChanged, vars)
+	case *parse.TemplateNode:
+		collectGoTemplateVariables(n.Pipe, dotChanged, vars)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectGoTemplateVariables(cmd, dotChanged, vars)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectGoTemplateVariables(arg, dotChanged, vars)
+		}
+	case *parse.ChainNode:
+		collectGoTemplateVariables(n.Node, dotChanged, vars)
+	case *parse.FieldNode:
+		if !dotChanged {
+			vars[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			vars[n.Ident[1]] = true
+		}
+	}
+}
+
+func sortedVariables(m map[string]bool) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
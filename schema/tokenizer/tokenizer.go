@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tokenizer counts the tokens of texts and messages, e.g. for memory trimming, context packing and usage estimation.
+package tokenizer
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Tokenizer counts the tokens of a text, e.g. by the tokenizer of the model.
+type Tokenizer interface {
+	CountTokens(ctx context.Context, text string) (int, error)
+}
+
+// Func is an adapter to use a function as Tokenizer.
+type Func func(ctx context.Context, text string) (int, error)
+
+// CountTokens calls f(ctx, text).
+func (f Func) CountTokens(ctx context.Context, text string) (int, error) {
+	return f(ctx, text)
+}
+
+// Approximate estimates the tokens without a vocabulary: a CJK character counts as a token,
+// and roughly every 4 other characters count as a token, which works reasonably well for most BPE tokenizers.
+var Approximate Tokenizer = Func(func(_ context.Context, text string) (int, error) {
+	return approximate(text), nil
+})
+
+func approximate(text string) int {
+	var cjk, others int
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			others++
+		}
+	}
+	return cjk + (others+3)/4
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Tokenizer{}
+)
+
+// Register registers the tokenizer of the model. The name is matched as a prefix of the model names,
+// e.g. the tokenizer registered as "gpt-4o" is used for "gpt-4o-mini" as well, unless a longer name is registered.
+func Register(model string, t Tokenizer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if t == nil {
+		delete(registry, model)
+		return
+	}
+	registry[model] = t
+}
+
+// Lookup returns the tokenizer registered for the model, matched by the longest registered prefix.
+func Lookup(model string) (Tokenizer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if t, ok := registry[model]; ok {
+		return t, true
+	}
+
+	var (
+		ret     Tokenizer
+		longest = -1
+	)
+	for name, t := range registry {
+		if len(name) > longest && strings.HasPrefix(model, name) {
+			ret, longest = t, len(name)
+		}
+	}
+	return ret, ret != nil
+}
+
+// ForModel returns the tokenizer registered for the model, Approximate if none.
+func ForModel(model string) Tokenizer {
+	if t, ok := Lookup(model); ok {
+		return t
+	}
+	return Approximate
+}
+
+// MessageOverheadTokens is the estimated tokens taken by the role and separators of a message.
+const MessageOverheadTokens = 4
+
+// CountMessageTokens counts the tokens of the messages by the tokenizer, Approximate if t is nil.
+// The text contents, reasoning contents and tool calls are counted, plus MessageOverheadTokens for each message.
+// Non-text parts, e.g. images, are not counted.
+func CountMessageTokens(ctx context.Context, t Tokenizer, msgs []*schema.Message) (int, error) {
+	if t == nil {
+		t = Approximate
+	}
+
+	var total int
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		n, err := t.CountTokens(ctx, messageText(msg))
+		if err != nil {
+			return 0, err
+		}
+		total += n + MessageOverheadTokens
+	}
+	return total, nil
+}
+
+func messageText(msg *schema.Message) string {
+	sb := strings.Builder{}
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(s)
+	}
+
+	write(msg.Name)
+	write(msg.Content)
+	write(msg.ReasoningContent)
+	for _, part := range msg.UserInputMultiContent {
+		write(part.Text)
+	}
+	for _, part := range msg.AssistantGenMultiContent {
+		write(part.Text)
+	}
+	for _, part := range msg.MultiContent {
+		write(part.Text)
+	}
+	for _, tc := range msg.ToolCalls {
+		write(tc.Function.Name)
+		write(tc.Function.Arguments)
+	}
+	return sb.String()
+}
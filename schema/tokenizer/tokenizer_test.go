@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestApproximate(t *testing.T) {
+	ctx := context.Background()
+
+	for text, expected := range map[string]int{
+		"":             0,
+		"hi":           1,
+		"hello world!": 3,
+		"你好世界":         4,
+		"hello 世界":     4,
+	} {
+		n, err := Approximate.CountTokens(ctx, text)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, n, text)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	words := Func(func(_ context.Context, text string) (int, error) {
+		return len(text), nil
+	})
+	Register("gpt-4", Approximate)
+	Register("gpt-4o", words)
+	defer Register("gpt-4", nil)
+	defer Register("gpt-4o", nil)
+
+	tk, ok := Lookup("gpt-4o-mini")
+	assert.True(t, ok)
+	n, _ := tk.CountTokens(context.Background(), "abcd")
+	assert.Equal(t, 4, n)
+
+	tk, ok = Lookup("gpt-4-turbo")
+	assert.True(t, ok)
+	n, _ = tk.CountTokens(context.Background(), "abcd")
+	assert.Equal(t, 1, n)
+
+	_, ok = Lookup("claude")
+	assert.False(t, ok)
+	assert.NotNil(t, ForModel("claude"))
+}
+
+func TestCountMessageTokens(t *testing.T) {
+	ctx := context.Background()
+
+	n, err := CountMessageTokens(ctx, nil, []*schema.Message{
+		schema.SystemMessage("abcd"),
+		nil,
+		schema.AssistantMessage("", []schema.ToolCall{{Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"x"}`}}}),
+	})
+	assert.NoError(t, err)
+	// "abcd" + overhead, "search\n{\"q\":\"x\"}" + overhead
+	assert.Equal(t, 1+MessageOverheadTokens+4+MessageOverheadTokens, n)
+
+	_, err = CountMessageTokens(ctx, Func(func(context.Context, string) (int, error) {
+		return 0, errors.New("remote counter unavailable")
+	}), []*schema.Message{schema.UserMessage("hi")})
+	assert.Error(t, err)
+}
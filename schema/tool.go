@@ -68,6 +68,11 @@ type ToolInfo struct {
 	//  - use jsonschema: schema.NewParamsOneOfByJSONSchema(jsonschema)
 	// If is nil, signals that the tool does not need any input parameter
 	*ParamsOneOf
+
+	// ReturnsOneOf describes the result of the tool, in the same way as ParamsOneOf,
+	// which isn't sent to the model, but can be used by planners and validators to understand the tool result.
+	// If is nil, the result of the tool is undeclared.
+	ReturnsOneOf *ParamsOneOf
 }
 
 // ParameterInfo is the information of a parameter.
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NewToolCallAccumulator creates a ToolCallAccumulator.
+// onReady is optional, it's called once for each tool call as soon as its name is known and its arguments become valid JSON,
+// so that the tool can be started before the stream ends. It's called synchronously within Add and Finish.
+func NewToolCallAccumulator(onReady func(toolCall ToolCall)) *ToolCallAccumulator {
+	return &ToolCallAccumulator{
+		onReady: onReady,
+		indexed: make(map[int]*accumulatingToolCall),
+	}
+}
+
+// ToolCallAccumulator accumulates the streamed tool call chunks, i.e. the fragments of the names and arguments identified by ToolCall.Index,
+// into the complete tool calls, in the same way as ConcatMessages. It's safe for concurrent use.
+// e.g.
+//
+//	acc := schema.NewToolCallAccumulator(func(tc schema.ToolCall) {
+//		go runTool(ctx, tc) // start the tool before the stream ends
+//	})
+//	for {
+//		chunk, err := sr.Recv()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		...
+//		if err = acc.AddMessage(chunk); err != nil {
+//			return err
+//		}
+//	}
+//	toolCalls := acc.Finish()
+type ToolCallAccumulator struct {
+	onReady func(toolCall ToolCall)
+
+	mu sync.Mutex
+	// unindexed are the tool calls without Index, each chunk of which is a complete tool call
+	unindexed []*accumulatingToolCall
+	indexed   map[int]*accumulatingToolCall
+}
+
+type accumulatingToolCall struct {
+	toolCall ToolCall
+	args     strings.Builder
+	ready    bool
+}
+
+// AddMessage adds the tool call chunks of the message chunk.
+func (a *ToolCallAccumulator) AddMessage(msg *Message) error {
+	if msg == nil {
+		return nil
+	}
+	return a.Add(msg.ToolCalls...)
+}
+
+// Add adds the tool call chunks. It fails if the chunks with the same index have different ids, types or names.
+func (a *ToolCallAccumulator) Add(chunks ...ToolCall) error {
+	var ready []ToolCall
+	err := func() error {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		for _, chunk := range chunks {
+			if chunk.Index == nil {
+				c := &accumulatingToolCall{toolCall: chunk}
+				c.args.WriteString(chunk.Function.Arguments)
+				a.unindexed = append(a.unindexed, c)
+				if tc, ok := c.checkReady(); ok {
+					ready = append(ready, tc)
+				}
+				continue
+			}
+
+			c, ok := a.indexed[*chunk.Index]
+			if !ok {
+				index := *chunk.Index
+				c = &accumulatingToolCall{toolCall: chunk}
+				c.toolCall.Index = &index
+				c.toolCall.Function.Arguments = ""
+				a.indexed[index] = c
+			} else if err := c.merge(chunk); err != nil {
+				return err
+			}
+			c.args.WriteString(chunk.Function.Arguments)
+			if tc, ok := c.checkReady(); ok {
+				ready = append(ready, tc)
+			}
+		}
+		return nil
+	}()
+
+	// call the hook without the lock, in case it accesses the accumulator
+	if a.onReady != nil {
+		for _, tc := range ready {
+			a.onReady(tc)
+		}
+	}
+	return err
+}
+
+func (c *accumulatingToolCall) merge(chunk ToolCall) error {
+	tc := &c.toolCall
+	if len(chunk.ID) > 0 {
+		if len(tc.ID) == 0 {
+			tc.ID = chunk.ID
+		} else if tc.ID != chunk.ID {
+			return fmt.Errorf("cannot concat ToolCalls with different tool id: '%s' '%s'", tc.ID, chunk.ID)
+		}
+	}
+	if len(chunk.Type) > 0 {
+		if len(tc.Type) == 0 {
+			tc.Type = chunk.Type
+		} else if tc.Type != chunk.Type {
+			return fmt.Errorf("cannot concat ToolCalls with different tool type: '%s' '%s'", tc.Type, chunk.Type)
+		}
+	}
+	if len(chunk.Function.Name) > 0 {
+		if len(tc.Function.Name) == 0 {
+			tc.Function.Name = chunk.Function.Name
+		} else if tc.Function.Name != chunk.Function.Name {
+			return fmt.Errorf("cannot concat ToolCalls with different tool name: '%s' '%s'", tc.Function.Name, chunk.Function.Name)
+		}
+	}
+	return nil
+}
+
+// checkReady marks the tool call ready and returns it if its name is known and its arguments are valid JSON for the first time.
+func (c *accumulatingToolCall) checkReady() (ToolCall, bool) {
+	if c.ready || len(c.toolCall.Function.Name) == 0 || c.args.Len() == 0 || !json.Valid([]byte(c.args.String())) {
+		return ToolCall{}, false
+	}
+	c.ready = true
+	return c.get(), true
+}
+
+func (c *accumulatingToolCall) get() ToolCall {
+	tc := c.toolCall
+	tc.Function.Arguments = c.args.String()
+	return tc
+}
+
+// ToolCalls returns the tool calls accumulated so far, some of which may be incomplete,
+// ordered in the same way as ConcatMessages, i.e. the ones without Index first, then by Index.
+func (a *ToolCallAccumulator) ToolCalls() []ToolCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ret := make([]ToolCall, 0, len(a.unindexed)+len(a.indexed))
+	for _, c := range a.unindexed {
+		ret = append(ret, c.get())
+	}
+	for _, index := range a.sortedIndexes() {
+		ret = append(ret, a.indexed[index].get())
+	}
+	return ret
+}
+
+func (a *ToolCallAccumulator) sortedIndexes() []int {
+	indexes := make([]int, 0, len(a.indexed))
+	for index := range a.indexed {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Ready reports whether the tool call at the index is ready, i.e. its arguments are valid JSON.
+func (a *ToolCallAccumulator) Ready(index int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.indexed[index]
+	return ok && c.ready
+}
+
+// Finish is called when the stream ends, it returns the accumulated tool calls, see ToolCalls.
+// The tool calls with empty arguments, e.g. of the tools without parameters, are ready at the end and reported to onReady.
+// The ones with invalid arguments are returned but never reported.
+func (a *ToolCallAccumulator) Finish() []ToolCall {
+	var ready []ToolCall
+	a.mu.Lock()
+	for _, c := range a.unindexed {
+		if !c.ready && c.args.Len() == 0 && len(c.toolCall.Function.Name) > 0 {
+			c.ready = true
+			ready = append(ready, c.get())
+		}
+	}
+	for _, index := range a.sortedIndexes() {
+		if c := a.indexed[index]; !c.ready && c.args.Len() == 0 && len(c.toolCall.Function.Name) > 0 {
+			c.ready = true
+			ready = append(ready, c.get())
+		}
+	}
+	a.mu.Unlock()
+
+	if a.onReady != nil {
+		for _, tc := range ready {
+			a.onReady(tc)
+		}
+	}
+	return a.ToolCalls()
+}
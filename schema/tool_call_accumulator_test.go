@@ -0,0 +1,93 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolCallAccumulator(t *testing.T) {
+	idx := func(i int) *int { return &i }
+	chunks := []*Message{
+		{Role: Assistant, ToolCalls: []ToolCall{{Index: idx(0), ID: "call_1", Type: "function", Function: FunctionCall{Name: "weather"}}}},
+		{Role: Assistant, ToolCalls: []ToolCall{{Index: idx(0), Function: FunctionCall{Arguments: `{"city":`}}}},
+		{Role: Assistant, ToolCalls: []ToolCall{
+			{Index: idx(0), Function: FunctionCall{Arguments: `"bj"}`}},
+			{Index: idx(1), ID: "call_2", Function: FunctionCall{Name: "time", Arguments: `{"tz":"U`}},
+		}},
+		{Role: Assistant, ToolCalls: []ToolCall{{Index: idx(2), ID: "call_3", Function: FunctionCall{Name: "now"}}}},
+		{Role: Assistant, ToolCalls: []ToolCall{{Index: idx(1), Function: FunctionCall{Arguments: `TC"}`}}}},
+	}
+
+	var (
+		mu    sync.Mutex
+		ready []ToolCall
+	)
+	acc := NewToolCallAccumulator(func(tc ToolCall) {
+		mu.Lock()
+		defer mu.Unlock()
+		ready = append(ready, tc)
+	})
+
+	assert.NoError(t, acc.AddMessage(chunks[0]))
+	assert.NoError(t, acc.AddMessage(chunks[1]))
+	assert.Empty(t, ready)
+	assert.False(t, acc.Ready(0))
+	assert.Equal(t, `{"city":`, acc.ToolCalls()[0].Function.Arguments)
+
+	assert.NoError(t, acc.AddMessage(chunks[2]))
+	// the first tool call is ready before the stream ends
+	assert.Len(t, ready, 1)
+	assert.True(t, acc.Ready(0))
+	assert.Equal(t, "call_1", ready[0].ID)
+	assert.Equal(t, `{"city":"bj"}`, ready[0].Function.Arguments)
+
+	assert.NoError(t, acc.AddMessage(chunks[3]))
+	assert.NoError(t, acc.AddMessage(chunks[4]))
+	assert.Len(t, ready, 2)
+	assert.Equal(t, "time", ready[1].Function.Name)
+
+	toolCalls := acc.Finish()
+	// the tool call without arguments is ready at the end
+	assert.Len(t, ready, 3)
+	assert.Equal(t, "call_3", ready[2].ID)
+
+	concatenated, err := ConcatMessages(chunks)
+	assert.NoError(t, err)
+	assert.Equal(t, concatenated.ToolCalls, toolCalls)
+
+	t.Run("conflict", func(t *testing.T) {
+		acc := NewToolCallAccumulator(nil)
+		assert.NoError(t, acc.Add(ToolCall{Index: idx(0), ID: "a"}))
+		assert.Error(t, acc.Add(ToolCall{Index: idx(0), ID: "b"}))
+	})
+
+	t.Run("without index", func(t *testing.T) {
+		var ready []string
+		acc := NewToolCallAccumulator(func(tc ToolCall) { ready = append(ready, tc.ID) })
+		assert.NoError(t, acc.Add(
+			ToolCall{ID: "a", Function: FunctionCall{Name: "x", Arguments: `{}`}},
+			ToolCall{ID: "b", Function: FunctionCall{Name: "y", Arguments: `{`}},
+		))
+		assert.Equal(t, []string{"a"}, ready)
+		assert.Len(t, acc.Finish(), 2)
+		assert.Equal(t, []string{"a"}, ready)
+	})
+}
@@ -0,0 +1,346 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudwego/eino/internal/serialization"
+)
+
+// TranscriptVersion is the version of the transcript format written by WriteTranscript,
+// which is bumped when the format changes incompatibly.
+const TranscriptVersion = 1
+
+// ErrUnsupportedTranscriptVersion is returned when reading a transcript of a version newer than TranscriptVersion.
+var ErrUnsupportedTranscriptVersion = errors.New("unsupported transcript version")
+
+// TranscriptFormat is the encoding of a transcript.
+type TranscriptFormat string
+
+const (
+	// TranscriptFormatJSON encodes the transcript as a single JSON object.
+	TranscriptFormatJSON TranscriptFormat = "json"
+	// TranscriptFormatJSONL encodes the header of the transcript in the first line, followed by one message per line,
+	// so that the messages can be appended to the transcript by AppendTranscriptJSONL as the conversation goes on.
+	TranscriptFormatJSONL TranscriptFormat = "jsonl"
+)
+
+// Transcript is the full history of a conversation, which can be persisted by WriteTranscript and loaded back by ReadTranscript,
+// e.g. to resume a session after the process restarts, or to inspect it offline.
+// The tool calls and the token usages are kept in the messages.
+type Transcript struct {
+	// Version is the version of the format, set by WriteTranscript.
+	Version int `json:"version"`
+	// ID is the id of the conversation.
+	ID string `json:"id,omitempty"`
+	// CreatedAt is when the conversation started.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Metadata is the customized information of the conversation.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// Messages is the messages of the conversation in order.
+	Messages []*Message `json:"messages"`
+}
+
+// Usage returns the total token usage of the messages in the transcript.
+func (t *Transcript) Usage() *TokenUsage {
+	usage := &TokenUsage{}
+	for _, m := range t.Messages {
+		if m == nil || m.ResponseMeta == nil || m.ResponseMeta.Usage == nil {
+			continue
+		}
+		usage.PromptTokens += m.ResponseMeta.Usage.PromptTokens
+		usage.PromptTokenDetails.CachedTokens += m.ResponseMeta.Usage.PromptTokenDetails.CachedTokens
+		usage.CompletionTokens += m.ResponseMeta.Usage.CompletionTokens
+		usage.TotalTokens += m.ResponseMeta.Usage.TotalTokens
+	}
+	return usage
+}
+
+// transcriptMessage is the persisted form of a message.
+// Besides the plain JSON for reading, the Extra of the message and its tool calls are also encoded with their types kept,
+// so that e.g. an int isn't loaded back as a float64, which requires the types of the values to be registered by Register or RegisterName,
+// otherwise only the plain JSON is kept. The Extra of the multimodal parts is kept as plain JSON only.
+type transcriptMessage struct {
+	*Message
+	ExtraTyped          json.RawMessage   `json:"extra_typed,omitempty"`
+	ToolCallExtrasTyped []json.RawMessage `json:"tool_call_extras_typed,omitempty"`
+}
+
+type transcriptJSON struct {
+	Version   int                  `json:"version"`
+	ID        string               `json:"id,omitempty"`
+	CreatedAt time.Time            `json:"created_at,omitempty"`
+	Metadata  map[string]any       `json:"metadata,omitempty"`
+	Messages  []*transcriptMessage `json:"messages,omitempty"`
+}
+
+var transcriptSerializer = &serialization.InternalSerializer{}
+
+func marshalTypedExtra(extra map[string]any) json.RawMessage {
+	if len(extra) == 0 {
+		return nil
+	}
+	typed, err := transcriptSerializer.Marshal(extra)
+	if err != nil {
+		return nil
+	}
+	return typed
+}
+
+func unmarshalTypedExtra(typed json.RawMessage) (map[string]any, error) {
+	var extra map[string]any
+	if err := transcriptSerializer.Unmarshal(typed, &extra); err != nil {
+		return nil, err
+	}
+	return extra, nil
+}
+
+func toTranscriptMessage(m *Message) *transcriptMessage {
+	tm := &transcriptMessage{Message: m}
+	if m == nil {
+		return tm
+	}
+	tm.ExtraTyped = marshalTypedExtra(m.Extra)
+	for i, tc := range m.ToolCalls {
+		if typed := marshalTypedExtra(tc.Extra); typed != nil {
+			if tm.ToolCallExtrasTyped == nil {
+				tm.ToolCallExtrasTyped = make([]json.RawMessage, len(m.ToolCalls))
+			}
+			tm.ToolCallExtrasTyped[i] = typed
+		}
+	}
+	return tm
+}
+
+func fromTranscriptMessage(tm *transcriptMessage) (*Message, error) {
+	if tm.Message == nil {
+		return nil, errors.New("empty message in transcript")
+	}
+	if len(tm.ExtraTyped) > 0 {
+		extra, err := unmarshalTypedExtra(tm.ExtraTyped)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal extra of message fail: %w", err)
+		}
+		tm.Message.Extra = extra
+	}
+	for i, typed := range tm.ToolCallExtrasTyped {
+		if len(typed) == 0 || string(typed) == "null" || i >= len(tm.Message.ToolCalls) {
+			continue
+		}
+		extra, err := unmarshalTypedExtra(typed)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal extra of tool call[%d] fail: %w", i, err)
+		}
+		tm.Message.ToolCalls[i].Extra = extra
+	}
+	return tm.Message, nil
+}
+
+func checkTranscriptVersion(version int) error {
+	if version <= 0 {
+		return errors.New("transcript version is missing")
+	}
+	if version > TranscriptVersion {
+		return fmt.Errorf("%w: %d, the latest supported is %d", ErrUnsupportedTranscriptVersion, version, TranscriptVersion)
+	}
+	return nil
+}
+
+// WriteTranscript writes the transcript to w in the format, with Version set to TranscriptVersion.
+func WriteTranscript(w io.Writer, t *Transcript, format TranscriptFormat) error {
+	tj := &transcriptJSON{
+		Version:   TranscriptVersion,
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		Metadata:  t.Metadata,
+	}
+
+	switch format {
+	case TranscriptFormatJSON:
+		tj.Messages = make([]*transcriptMessage, len(t.Messages))
+		for i, m := range t.Messages {
+			tj.Messages[i] = toTranscriptMessage(m)
+		}
+		if err := json.NewEncoder(w).Encode(tj); err != nil {
+			return fmt.Errorf("write transcript fail: %w", err)
+		}
+		return nil
+	case TranscriptFormatJSONL:
+		if err := json.NewEncoder(w).Encode(tj); err != nil {
+			return fmt.Errorf("write transcript header fail: %w", err)
+		}
+		return AppendTranscriptJSONL(w, t.Messages...)
+	default:
+		return fmt.Errorf("unknown transcript format: %s", format)
+	}
+}
+
+// AppendTranscriptJSONL appends the messages to a transcript written in TranscriptFormatJSONL, one message per line.
+// e.g.
+//
+//	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+//	defer f.Close()
+//	err := schema.AppendTranscriptJSONL(f, userMsg, assistantMsg)
+func AppendTranscriptJSONL(w io.Writer, msgs ...*Message) error {
+	enc := json.NewEncoder(w)
+	for _, m := range msgs {
+		if err := enc.Encode(toTranscriptMessage(m)); err != nil {
+			return fmt.Errorf("write transcript message fail: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadTranscript reads a transcript written by WriteTranscript in the format from r.
+// ErrUnsupportedTranscriptVersion is returned if it's written in a newer version.
+func ReadTranscript(r io.Reader, format TranscriptFormat) (*Transcript, error) {
+	switch format {
+	case TranscriptFormatJSON:
+		tj := &transcriptJSON{}
+		if err := json.NewDecoder(r).Decode(tj); err != nil {
+			return nil, fmt.Errorf("read transcript fail: %w", err)
+		}
+		if err := checkTranscriptVersion(tj.Version); err != nil {
+			return nil, err
+		}
+		t := newTranscript(tj)
+		for _, tm := range tj.Messages {
+			m, err := fromTranscriptMessage(tm)
+			if err != nil {
+				return nil, err
+			}
+			t.Messages = append(t.Messages, m)
+		}
+		return t, nil
+	case TranscriptFormatJSONL:
+		dec := json.NewDecoder(bufio.NewReader(r))
+		tj := &transcriptJSON{}
+		if err := dec.Decode(tj); err != nil {
+			return nil, fmt.Errorf("read transcript header fail: %w", err)
+		}
+		if err := checkTranscriptVersion(tj.Version); err != nil {
+			return nil, err
+		}
+		t := newTranscript(tj)
+		for {
+			tm := &transcriptMessage{}
+			err := dec.Decode(tm)
+			if err == io.EOF {
+				return t, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read transcript message[%d] fail: %w", len(t.Messages), err)
+			}
+			m, err := fromTranscriptMessage(tm)
+			if err != nil {
+				return nil, err
+			}
+			t.Messages = append(t.Messages, m)
+		}
+	default:
+		return nil, fmt.Errorf("unknown transcript format: %s", format)
+	}
+}
+
+func newTranscript(tj *transcriptJSON) *Transcript {
+	return &Transcript{
+		Version:   tj.Version,
+		ID:        tj.ID,
+		CreatedAt: tj.CreatedAt,
+		Metadata:  tj.Metadata,
+		Messages:  []*Message{},
+	}
+}
+
+// TranscriptStore persists the transcripts by their ids, e.g. to resume a session after the process restarts.
+type TranscriptStore interface {
+	Get(ctx context.Context, id string) (*Transcript, bool, error)
+	Set(ctx context.Context, t *Transcript) error
+}
+
+// NewFileTranscriptStore creates a TranscriptStore saving each transcript as a file named by its id in dir,
+// in TranscriptFormatJSONL so that the files can be inspected or appended to by AppendTranscriptJSONL directly.
+func NewFileTranscriptStore(dir string) TranscriptStore {
+	return &fileTranscriptStore{dir: dir}
+}
+
+type fileTranscriptStore struct {
+	dir string
+}
+
+func (s *fileTranscriptStore) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid transcript id: %q", id)
+	}
+	return filepath.Join(s.dir, id+".jsonl"), nil
+}
+
+func (s *fileTranscriptStore) Get(_ context.Context, id string) (*Transcript, bool, error) {
+	p, err := s.path(id)
+	if err != nil {
+		return nil, false, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	t, err := ReadTranscript(f, TranscriptFormatJSONL)
+	if err != nil {
+		return nil, false, err
+	}
+	return t, true, nil
+}
+
+func (s *fileTranscriptStore) Set(_ context.Context, t *Transcript) error {
+	p, err := s.path(t.ID)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	// write to a temp file first so that a crash doesn't leave a truncated transcript
+	tmp, err := os.CreateTemp(s.dir, t.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = WriteTranscript(tmp, t, TranscriptFormatJSONL); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTranscriptForTest() *Transcript {
+	url := "https://example.com/cat.png"
+	data := "aGVsbG8="
+	idx := 0
+	return &Transcript{
+		ID:        "session-1",
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata:  map[string]any{"user": "u1"},
+		Messages: []*Message{
+			SystemMessage("you are a helpful assistant"),
+			{
+				Role:    User,
+				Content: "what's in the image",
+				Name:    "alice",
+				MultiContent: []ChatMessagePart{
+					{Type: ChatMessagePartTypeText, Text: "legacy"},
+					{Type: ChatMessagePartTypeImageURL, ImageURL: &ChatMessageImageURL{URL: url, Detail: ImageURLDetailHigh, MIMEType: "image/png"}},
+					{Type: ChatMessagePartTypeFileURL, FileURL: &ChatMessageFileURL{URI: "s3://bucket/a.pdf", Name: "a.pdf"}},
+				},
+				UserInputMultiContent: []MessageInputPart{
+					{Type: ChatMessagePartTypeText, Text: "describe it"},
+					{Type: ChatMessagePartTypeImageURL, Image: &MessageInputImage{
+						MessagePartCommon: MessagePartCommon{URL: &url, MIMEType: "image/png"},
+						Detail:            ImageURLDetailLow,
+					}},
+					{Type: ChatMessagePartTypeFileURL, File: &MessageInputFile{
+						MessagePartCommon: MessagePartCommon{Base64Data: &data},
+						Name:              "a.txt",
+					}},
+				},
+				Extra: map[string]any{
+					"int":    1,
+					"int64":  int64(2),
+					"float":  1.5,
+					"bool":   true,
+					"string": "s",
+					"slice":  []string{"a", "b"},
+					"nested": map[string]any{"k": 3},
+				},
+			},
+			{
+				Role:             Assistant,
+				ReasoningContent: "let me call the tool",
+				ToolCalls: []ToolCall{
+					{Index: &idx, ID: "call-1", Type: "function", Function: FunctionCall{Name: "describe", Arguments: `{"url":"x"}`}, Extra: map[string]any{"n": 7}},
+					{ID: "call-2", Type: "function", Function: FunctionCall{Name: "noop", Arguments: `{}`}},
+				},
+				AssistantGenMultiContent: []MessageOutputPart{
+					{Type: ChatMessagePartTypeText, Text: "partial"},
+					{Type: ChatMessagePartTypeImageURL, Image: &MessageOutputImage{MessagePartCommon: MessagePartCommon{Base64Data: &data, MIMEType: "image/jpeg"}}},
+				},
+				ResponseMeta: &ResponseMeta{
+					FinishReason: "tool_calls",
+					Usage:        &TokenUsage{PromptTokens: 10, PromptTokenDetails: PromptTokenDetails{CachedTokens: 4}, CompletionTokens: 5, TotalTokens: 15},
+					LogProbs: &LogProbs{Content: []LogProb{{
+						Token: "a", LogProb: -0.5, Bytes: []int64{97},
+						TopLogProbs: []TopLogProb{{Token: "b", LogProb: -1, Bytes: []int64{98}}},
+					}}},
+					Model:     "m1",
+					RequestID: "req-1",
+					Latency:   time.Second,
+				},
+			},
+			ToolMessage("a cat", "call-1", WithToolName("describe")),
+			{
+				Role:    Assistant,
+				Content: "it's a cat",
+				ResponseMeta: &ResponseMeta{
+					FinishReason: "stop",
+					Usage:        &TokenUsage{PromptTokens: 20, CompletionTokens: 3, TotalTokens: 23},
+				},
+			},
+		},
+	}
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	for _, format := range []TranscriptFormat{TranscriptFormatJSON, TranscriptFormatJSONL} {
+		t.Run(string(format), func(t *testing.T) {
+			orig := newTranscriptForTest()
+			var buf bytes.Buffer
+			require.NoError(t, WriteTranscript(&buf, orig, format))
+
+			got, err := ReadTranscript(&buf, format)
+			require.NoError(t, err)
+
+			assert.Equal(t, TranscriptVersion, got.Version)
+			assert.Equal(t, orig.ID, got.ID)
+			assert.True(t, orig.CreatedAt.Equal(got.CreatedAt))
+			assert.Equal(t, orig.Metadata, got.Metadata)
+			// the types of the values in Extra are kept, e.g. int isn't loaded back as float64
+			assert.Equal(t, orig.Messages, got.Messages)
+			assert.Equal(t, &TokenUsage{PromptTokens: 30, PromptTokenDetails: PromptTokenDetails{CachedTokens: 4}, CompletionTokens: 8, TotalTokens: 38}, got.Usage())
+		})
+	}
+}
+
+func TestTranscriptJSONL(t *testing.T) {
+	orig := newTranscriptForTest()
+	var buf bytes.Buffer
+	require.NoError(t, WriteTranscript(&buf, &Transcript{ID: orig.ID}, TranscriptFormatJSONL))
+	for _, m := range orig.Messages {
+		require.NoError(t, AppendTranscriptJSONL(&buf, m))
+	}
+	assert.Equal(t, len(orig.Messages)+1, strings.Count(buf.String(), "\n"))
+
+	got, err := ReadTranscript(&buf, TranscriptFormatJSONL)
+	require.NoError(t, err)
+	assert.Equal(t, orig.Messages, got.Messages)
+
+	// the Extra of an unregistered type is kept as plain JSON
+	type unregistered struct{ A int }
+	buf.Reset()
+	require.NoError(t, WriteTranscript(&buf, &Transcript{Messages: []*Message{{Role: User, Extra: map[string]any{"u": unregistered{A: 1}}}}}, TranscriptFormatJSONL))
+	got, err = ReadTranscript(&buf, TranscriptFormatJSONL)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"u": map[string]any{"A": float64(1)}}, got.Messages[0].Extra)
+}
+
+func TestTranscriptVersion(t *testing.T) {
+	_, err := ReadTranscript(strings.NewReader(`{"version":2,"messages":[]}`), TranscriptFormatJSON)
+	assert.ErrorIs(t, err, ErrUnsupportedTranscriptVersion)
+
+	_, err = ReadTranscript(strings.NewReader(`{"version":2}`+"\n"), TranscriptFormatJSONL)
+	assert.ErrorIs(t, err, ErrUnsupportedTranscriptVersion)
+
+	_, err = ReadTranscript(strings.NewReader(`{"messages":[]}`), TranscriptFormatJSON)
+	assert.ErrorContains(t, err, "version is missing")
+
+	_, err = ReadTranscript(strings.NewReader(`{}`), "yaml")
+	assert.ErrorContains(t, err, "unknown transcript format")
+}
+
+func TestFileTranscriptStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileTranscriptStore(dir)
+
+	_, ok, err := store.Get(ctx, "session-1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	orig := newTranscriptForTest()
+	require.NoError(t, store.Set(ctx, orig))
+
+	// the messages can be appended to the file directly
+	f, err := os.OpenFile(filepath.Join(dir, "session-1.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	require.NoError(t, AppendTranscriptJSONL(f, UserMessage("thanks")))
+	require.NoError(t, f.Close())
+
+	got, ok, err := store.Get(ctx, "session-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, append(orig.Messages, UserMessage("thanks")), got.Messages)
+
+	assert.Error(t, store.Set(ctx, &Transcript{ID: "../escape"}))
+	_, _, err = store.Get(ctx, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// FakeChatModelConfig is the config of FakeChatModel.
+type FakeChatModelConfig struct {
+	// Responses are queued initially, more can be queued by Push and PushError.
+	Responses []*schema.Message
+	// Fallback generates the response when the queue is empty, ErrNoResponse is returned if it's nil.
+	Fallback func(ctx context.Context, input []*schema.Message) (*schema.Message, error)
+	// Latency is waited before responding, or before the first chunk when streaming.
+	Latency time.Duration
+	// ChunkSize is the number of runes of the content per chunk when streaming, 0 means the whole content in one chunk.
+	// The tool calls and response meta are sent in the last chunk.
+	ChunkSize int
+	// ChunkLatency is waited before every chunk after the first one when streaming.
+	ChunkLatency time.Duration
+}
+
+// ChatModelCall is a recorded call of FakeChatModel.
+type ChatModelCall struct {
+	Input   []*schema.Message
+	Tools   []*schema.ToolInfo
+	Options *model.Options
+	Stream  bool
+}
+
+type chatResponse struct {
+	message *schema.Message
+	err     error
+}
+
+// NewFakeChatModel creates a FakeChatModel.
+func NewFakeChatModel(config *FakeChatModelConfig) *FakeChatModel {
+	if config == nil {
+		config = &FakeChatModelConfig{}
+	}
+	m := &FakeChatModel{
+		state:  &chatModelState{},
+		config: config,
+	}
+	for _, msg := range config.Responses {
+		m.state.responses.push(&chatResponse{message: msg})
+	}
+	return m
+}
+
+// FakeChatModel is a chat model returning the queued messages or errors in order, and recording its calls.
+// The models returned by WithTools share the queue and records with the original one.
+type FakeChatModel struct {
+	state  *chatModelState
+	config *FakeChatModelConfig
+	tools  []*schema.ToolInfo
+}
+
+type chatModelState struct {
+	responses queue[*chatResponse]
+
+	mu    sync.Mutex
+	calls []*ChatModelCall
+	tools []*schema.ToolInfo
+}
+
+// Push queues the messages to respond.
+func (m *FakeChatModel) Push(msgs ...*schema.Message) {
+	for _, msg := range msgs {
+		m.state.responses.push(&chatResponse{message: msg})
+	}
+}
+
+// PushError queues an error to return.
+func (m *FakeChatModel) PushError(err error) {
+	m.state.responses.push(&chatResponse{err: err})
+}
+
+// Remaining returns the number of the queued responses.
+func (m *FakeChatModel) Remaining() int {
+	return m.state.responses.len()
+}
+
+// Calls returns the recorded calls.
+func (m *FakeChatModel) Calls() []*ChatModelCall {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	ret := make([]*ChatModelCall, len(m.state.calls))
+	copy(ret, m.state.calls)
+	return ret
+}
+
+// Generate implements model.BaseChatModel.
+func (m *FakeChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msg, err := m.respond(ctx, input, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = sleep(ctx, m.config.Latency); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Stream implements model.BaseChatModel, the message is split into chunks by ChunkSize.
+func (m *FakeChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.respond(ctx, input, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := splitMessage(msg, m.config.ChunkSize)
+	sr, sw := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer sw.Close()
+		for i, chunk := range chunks {
+			latency := m.config.ChunkLatency
+			if i == 0 {
+				latency = m.config.Latency
+			}
+			if err := sleep(ctx, latency); err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
+
+// BindTools implements model.ChatModel, the tools are bound to the shared state.
+func (m *FakeChatModel) BindTools(tools []*schema.ToolInfo) error {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+	m.state.tools = tools
+	return nil
+}
+
+// WithTools implements model.ToolCallingChatModel.
+func (m *FakeChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &FakeChatModel{
+		state:  m.state,
+		config: m.config,
+		tools:  tools,
+	}, nil
+}
+
+// GetType returns the type of the fake.
+func (m *FakeChatModel) GetType() string {
+	return "Fake"
+}
+
+func (m *FakeChatModel) respond(ctx context.Context, input []*schema.Message, stream bool, opts ...model.Option) (*schema.Message, error) {
+	options := model.GetCommonOptions(&model.Options{}, opts...)
+	call := &ChatModelCall{Input: input, Tools: m.tools, Options: options, Stream: stream}
+
+	m.state.mu.Lock()
+	if call.Tools == nil {
+		call.Tools = m.state.tools
+	}
+	if options.Tools != nil {
+		call.Tools = options.Tools
+	}
+	m.state.calls = append(m.state.calls, call)
+	m.state.mu.Unlock()
+
+	resp, ok := m.state.responses.pop()
+	if !ok {
+		if m.config.Fallback == nil {
+			return nil, ErrNoResponse
+		}
+		return m.config.Fallback(ctx, input)
+	}
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	return resp.message, nil
+}
+
+func splitMessage(msg *schema.Message, size int) []*schema.Message {
+	content := []rune(msg.Content)
+	if size <= 0 || len(content) <= size {
+		return []*schema.Message{msg}
+	}
+
+	var chunks []*schema.Message
+	for start := 0; start < len(content); start += size {
+		end := start + size
+		if end > len(content) {
+			end = len(content)
+		}
+		chunks = append(chunks, &schema.Message{
+			Role:    msg.Role,
+			Content: string(content[start:end]),
+		})
+	}
+	last := chunks[len(chunks)-1]
+	last.Name = msg.Name
+	last.ToolCalls = msg.ToolCalls
+	last.ResponseMeta = msg.ResponseMeta
+	last.ReasoningContent = msg.ReasoningContent
+	last.Extra = msg.Extra
+	return chunks
+}
+
+// ToolCall creates a schema.ToolCall, useful to queue the tool calling messages.
+func ToolCall(id, name, arguments string) schema.ToolCall {
+	return schema.ToolCall{
+		ID:       id,
+		Type:     "function",
+		Function: schema.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+// FakeRetrieverConfig is the config of FakeRetriever.
+type FakeRetrieverConfig struct {
+	// Documents are returned for the queries not in ByQuery.
+	Documents []*schema.Document
+	// ByQuery maps the queries to the documents returned.
+	ByQuery map[string][]*schema.Document
+	// Err is returned by every call if it's not nil.
+	Err error
+	// Latency is waited before returning.
+	Latency time.Duration
+}
+
+// NewFakeRetriever creates a FakeRetriever.
+func NewFakeRetriever(config *FakeRetrieverConfig) *FakeRetriever {
+	if config == nil {
+		config = &FakeRetrieverConfig{}
+	}
+	return &FakeRetriever{config: config}
+}
+
+// FakeRetriever is a retriever returning the canned documents, truncated by the TopK option, and recording the queries.
+type FakeRetriever struct {
+	config *FakeRetrieverConfig
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// Retrieve implements retriever.Retriever.
+func (r *FakeRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	r.mu.Lock()
+	r.queries = append(r.queries, query)
+	r.mu.Unlock()
+
+	if err := sleep(ctx, r.config.Latency); err != nil {
+		return nil, err
+	}
+	if r.config.Err != nil {
+		return nil, r.config.Err
+	}
+
+	docs, ok := r.config.ByQuery[query]
+	if !ok {
+		docs = r.config.Documents
+	}
+	options := retriever.GetCommonOptions(&retriever.Options{}, opts...)
+	if options.TopK != nil && *options.TopK >= 0 && *options.TopK < len(docs) {
+		docs = docs[:*options.TopK]
+	}
+	return docs, nil
+}
+
+// Queries returns the recorded queries.
+func (r *FakeRetriever) Queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := make([]string, len(r.queries))
+	copy(ret, r.queries)
+	return ret
+}
+
+// GetType returns the type of the fake.
+func (r *FakeRetriever) GetType() string {
+	return "Fake"
+}
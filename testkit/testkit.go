@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package testkit provides scriptable fakes of the components for behavioral tests,
+// e.g. simulating a multi-turn agent with a chat model returning queued messages and tool calls.
+//
+//	cm := testkit.NewFakeChatModel(&testkit.FakeChatModelConfig{ChunkSize: 4})
+//	cm.Push(
+//		schema.AssistantMessage("", []schema.ToolCall{testkit.ToolCall("call_1", "search", `{"q":"eino"}`)}),
+//		schema.AssistantMessage("eino is a framework", nil),
+//	)
+//	search := testkit.NewFakeTool(&testkit.FakeToolConfig{Name: "search", Result: "eino is ..."})
+//
+// The fakes record their calls, which can be inspected after running.
+package testkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoResponse is returned by the fakes having no queued response and no fallback.
+var ErrNoResponse = errors.New("no response queued")
+
+// sleep waits for d, returns the error of ctx if it's done earlier.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type queue[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (q *queue[T]) push(items ...T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, items...)
+}
+
+func (q *queue[T]) pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false
+	}
+	item := q.items[0]
+	q.items[0] = zero
+	q.items = q.items[1:]
+	return item, true
+}
+
+func (q *queue[T]) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
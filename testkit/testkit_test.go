@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestFakeChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("multi-turn agent", func(t *testing.T) {
+		cm := NewFakeChatModel(&FakeChatModelConfig{
+			Responses: []*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{ToolCall("call_1", "search", `{"q":"eino"}`)}),
+			},
+		})
+		cm.Push(schema.AssistantMessage("eino is a framework", nil))
+		search := NewFakeTool(&FakeToolConfig{Name: "search", Results: []string{"eino is an LLM framework"}})
+
+		agent, err := react.NewAgent(ctx, &react.AgentConfig{
+			ToolCallingModel: cm,
+			ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{search}},
+		})
+		assert.NoError(t, err)
+
+		out, err := agent.Generate(ctx, []*schema.Message{schema.UserMessage("what's eino?")})
+		assert.NoError(t, err)
+		assert.Equal(t, "eino is a framework", out.Content)
+		assert.Equal(t, 0, cm.Remaining())
+
+		calls := cm.Calls()
+		assert.Len(t, calls, 2)
+		assert.Equal(t, "search", calls[0].Tools[0].Name)
+		assert.Equal(t, schema.Tool, calls[1].Input[len(calls[1].Input)-1].Role)
+		assert.Equal(t, []*ToolInvocation{{Arguments: `{"q":"eino"}`, Result: "eino is an LLM framework"}}, search.Calls())
+	})
+
+	t.Run("stream chunks", func(t *testing.T) {
+		cm := NewFakeChatModel(&FakeChatModelConfig{ChunkSize: 3, ChunkLatency: time.Millisecond})
+		cm.Push(schema.AssistantMessage("hello world", []schema.ToolCall{ToolCall("1", "a", "{}")}))
+
+		sr, err := cm.Stream(ctx, nil)
+		assert.NoError(t, err)
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		assert.Len(t, chunks, 4)
+		assert.Equal(t, "hel", chunks[0].Content)
+		assert.Empty(t, chunks[0].ToolCalls)
+		assert.Len(t, chunks[3].ToolCalls, 1)
+
+		msg, err := schema.ConcatMessages(chunks)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", msg.Content)
+		assert.True(t, cm.Calls()[0].Stream)
+	})
+
+	t.Run("errors and fallback", func(t *testing.T) {
+		cm := NewFakeChatModel(nil)
+		boom := errors.New("boom")
+		cm.PushError(boom)
+		_, err := cm.Generate(ctx, nil)
+		assert.ErrorIs(t, err, boom)
+		_, err = cm.Generate(ctx, nil)
+		assert.ErrorIs(t, err, ErrNoResponse)
+
+		cm = NewFakeChatModel(&FakeChatModelConfig{
+			Fallback: func(ctx context.Context, input []*schema.Message) (*schema.Message, error) {
+				return schema.AssistantMessage("echo: "+input[0].Content, nil), nil
+			},
+		})
+		out, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", out.Content)
+	})
+
+	t.Run("latency respects ctx", func(t *testing.T) {
+		cm := NewFakeChatModel(&FakeChatModelConfig{Latency: time.Hour})
+		cm.Push(schema.AssistantMessage("late", nil))
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := cm.Generate(cctx, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestFakeRetrieverAndTool(t *testing.T) {
+	ctx := context.Background()
+
+	r := NewFakeRetriever(&FakeRetrieverConfig{
+		Documents: []*schema.Document{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+		ByQuery:   map[string][]*schema.Document{"special": {{ID: "s"}}},
+	})
+	docs, err := r.Retrieve(ctx, "any", retriever.WithTopK(2))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 2)
+	docs, err = r.Retrieve(ctx, "special")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", docs[0].ID)
+	assert.Equal(t, []string{"any", "special"}, r.Queries())
+
+	tl := NewFakeTool(&FakeToolConfig{Name: "t", Results: []string{"first"}, Result: "default"})
+	res, err := tl.InvokableRun(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", res)
+	res, err = tl.InvokableRun(ctx, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", res)
+	assert.Len(t, tl.Calls(), 2)
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// FakeToolConfig is the config of FakeTool.
+type FakeToolConfig struct {
+	// Name of the tool, required unless Info is set.
+	Name string
+	// Desc of the tool.
+	Desc string
+	// Info overrides Name and Desc if it's set.
+	Info *schema.ToolInfo
+	// Results are returned in order, Result is returned after they run out.
+	Results []string
+	// Result is the default result.
+	Result string
+	// Handler computes the result if it's set, taking precedence over Results and Result.
+	Handler func(ctx context.Context, arguments string) (string, error)
+	// Err is returned by every call if it's not nil.
+	Err error
+	// Latency is waited before returning.
+	Latency time.Duration
+}
+
+// ToolInvocation is a recorded invocation of FakeTool.
+type ToolInvocation struct {
+	Arguments string
+	Result    string
+	Err       error
+}
+
+// NewFakeTool creates a FakeTool.
+func NewFakeTool(config *FakeToolConfig) *FakeTool {
+	if config == nil {
+		config = &FakeToolConfig{}
+	}
+	t := &FakeTool{config: config}
+	t.results.push(config.Results...)
+	return t
+}
+
+// FakeTool is an invokable tool returning the scripted results and recording its invocations.
+type FakeTool struct {
+	config  *FakeToolConfig
+	results queue[string]
+
+	mu    sync.Mutex
+	calls []*ToolInvocation
+}
+
+// Info implements tool.BaseTool.
+func (t *FakeTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	if t.config.Info != nil {
+		return t.config.Info, nil
+	}
+	return &schema.ToolInfo{Name: t.config.Name, Desc: t.config.Desc}, nil
+}
+
+// InvokableRun implements tool.InvokableTool.
+func (t *FakeTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	result, err := t.run(ctx, argumentsInJSON)
+
+	t.mu.Lock()
+	t.calls = append(t.calls, &ToolInvocation{Arguments: argumentsInJSON, Result: result, Err: err})
+	t.mu.Unlock()
+
+	return result, err
+}
+
+func (t *FakeTool) run(ctx context.Context, arguments string) (string, error) {
+	if err := sleep(ctx, t.config.Latency); err != nil {
+		return "", err
+	}
+	if t.config.Err != nil {
+		return "", t.config.Err
+	}
+	if t.config.Handler != nil {
+		return t.config.Handler(ctx, arguments)
+	}
+	if result, ok := t.results.pop(); ok {
+		return result, nil
+	}
+	return t.config.Result, nil
+}
+
+// Calls returns the recorded invocations.
+func (t *FakeTool) Calls() []*ToolInvocation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ret := make([]*ToolInvocation, len(t.calls))
+	copy(ret, t.calls)
+	return ret
+}
+
+// GetType returns the type of the fake.
+func (t *FakeTool) GetType() string {
+	return "Fake"
+}
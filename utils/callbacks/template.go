@@ -35,7 +35,7 @@ import (
 // NewHandlerHelper creates a new component template handler builder.
 // This builder can be used to configure and build a component template handler,
 // which can handle callback events for different components with its own struct definition,
-// and fallbackTemplate can be used to handle scenarios where none of the cases are hit as a fallback.
+// and the fallback handler set by Fallback can be used to handle scenarios where none of the cases are hit.
 func NewHandlerHelper() *HandlerHelper {
 	return &HandlerHelper{
 		composeTemplates: map[components.Component]callbacks.Handler{},
@@ -63,6 +63,7 @@ type HandlerHelper struct {
 	toolHandler        *ToolCallbackHandler
 	toolsNodeHandler   *ToolsNodeCallbackHandlers
 	composeTemplates   map[components.Component]callbacks.Handler
+	fallbackHandler    callbacks.Handler
 }
 
 // Handler returns the callbacks.Handler created by HandlerHelper.
@@ -142,6 +143,51 @@ func (c *HandlerHelper) Lambda(handler callbacks.Handler) *HandlerHelper {
 	return c
 }
 
+// Workflow sets the workflow handler for the handler helper, which will be called when the workflow is executed.
+func (c *HandlerHelper) Workflow(handler callbacks.Handler) *HandlerHelper {
+	c.composeTemplates[compose.ComponentOfWorkflow] = handler
+	return c
+}
+
+// Fallback sets the fallback handler for the handler helper, which will be called with the untyped input and output
+// when a component without handler is executed, including the components unknown to the handler helper,
+// e.g. the custom components, so that a comprehensive handler doesn't need a manual type switch.
+func (c *HandlerHelper) Fallback(handler callbacks.Handler) *HandlerHelper {
+	c.fallbackHandler = handler
+	return c
+}
+
+// hasHandler reports whether a specific handler is set for the component.
+func (c *HandlerHelper) hasHandler(component components.Component) bool {
+	switch component {
+	case components.ComponentOfPrompt:
+		return c.promptHandler != nil
+	case components.ComponentOfChatModel:
+		return c.chatModelHandler != nil
+	case components.ComponentOfEmbedding:
+		return c.embeddingHandler != nil
+	case components.ComponentOfIndexer:
+		return c.indexerHandler != nil
+	case components.ComponentOfRetriever:
+		return c.retrieverHandler != nil
+	case components.ComponentOfLoader:
+		return c.loaderHandler != nil
+	case components.ComponentOfTransformer:
+		return c.transformerHandler != nil
+	case components.ComponentOfTool:
+		return c.toolHandler != nil
+	case compose.ComponentOfToolsNode:
+		return c.toolsNodeHandler != nil
+	case compose.ComponentOfGraph,
+		compose.ComponentOfChain,
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
+		return c.composeTemplates[component] != nil
+	default:
+		return false
+	}
+}
+
 type handlerTemplate struct {
 	*HandlerHelper
 }
@@ -149,6 +195,13 @@ type handlerTemplate struct {
 // OnStart is the callback function for the start event of a component.
 // implement the callbacks Handler interface.
 func (c *handlerTemplate) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler != nil {
+			return c.fallbackHandler.OnStart(ctx, info, input)
+		}
+		return ctx
+	}
+
 	switch info.Component {
 	case components.ComponentOfPrompt:
 		return c.promptHandler.OnStart(ctx, info, prompt.ConvCallbackInput(input))
@@ -170,7 +223,8 @@ func (c *handlerTemplate) OnStart(ctx context.Context, info *callbacks.RunInfo,
 		return c.toolsNodeHandler.OnStart(ctx, info, convToolsNodeCallbackInput(input))
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		return c.composeTemplates[info.Component].OnStart(ctx, info, input)
 	default:
 		return ctx
@@ -180,6 +234,13 @@ func (c *handlerTemplate) OnStart(ctx context.Context, info *callbacks.RunInfo,
 // OnEnd is the callback function for the end event of a component.
 // implement the callbacks Handler interface.
 func (c *handlerTemplate) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler != nil {
+			return c.fallbackHandler.OnEnd(ctx, info, output)
+		}
+		return ctx
+	}
+
 	switch info.Component {
 	case components.ComponentOfPrompt:
 		return c.promptHandler.OnEnd(ctx, info, prompt.ConvCallbackOutput(output))
@@ -201,7 +262,8 @@ func (c *handlerTemplate) OnEnd(ctx context.Context, info *callbacks.RunInfo, ou
 		return c.toolsNodeHandler.OnEnd(ctx, info, convToolsNodeCallbackOutput(output))
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		return c.composeTemplates[info.Component].OnEnd(ctx, info, output)
 	default:
 		return ctx
@@ -211,6 +273,13 @@ func (c *handlerTemplate) OnEnd(ctx context.Context, info *callbacks.RunInfo, ou
 // OnError is the callback function for the error event of a component.
 // implement the callbacks Handler interface.
 func (c *handlerTemplate) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler != nil {
+			return c.fallbackHandler.OnError(ctx, info, err)
+		}
+		return ctx
+	}
+
 	switch info.Component {
 	case components.ComponentOfPrompt:
 		return c.promptHandler.OnError(ctx, info, err)
@@ -232,7 +301,8 @@ func (c *handlerTemplate) OnError(ctx context.Context, info *callbacks.RunInfo,
 		return c.toolsNodeHandler.OnError(ctx, info, err)
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		return c.composeTemplates[info.Component].OnError(ctx, info, err)
 	default:
 		return ctx
@@ -242,11 +312,19 @@ func (c *handlerTemplate) OnError(ctx context.Context, info *callbacks.RunInfo,
 // OnStartWithStreamInput is the callback function for the start event of a component with stream input.
 // implement the callbacks Handler interface.
 func (c *handlerTemplate) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo, input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler != nil {
+			return c.fallbackHandler.OnStartWithStreamInput(ctx, info, input)
+		}
+		return ctx
+	}
+
 	switch info.Component {
 	// currently no components.Component receive stream as input
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		return c.composeTemplates[info.Component].OnStartWithStreamInput(ctx, info, input)
 	default:
 		return ctx
@@ -256,6 +334,13 @@ func (c *handlerTemplate) OnStartWithStreamInput(ctx context.Context, info *call
 // OnEndWithStreamOutput is the callback function for the end event of a component with stream output.
 // implement the callbacks Handler interface.
 func (c *handlerTemplate) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler != nil {
+			return c.fallbackHandler.OnEndWithStreamOutput(ctx, info, output)
+		}
+		return ctx
+	}
+
 	switch info.Component {
 	case components.ComponentOfChatModel:
 		return c.chatModelHandler.OnEndWithStreamOutput(ctx, info,
@@ -274,7 +359,8 @@ func (c *handlerTemplate) OnEndWithStreamOutput(ctx context.Context, info *callb
 			}))
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		return c.composeTemplates[info.Component].OnEndWithStreamOutput(ctx, info, output)
 	default:
 		return ctx
@@ -287,6 +373,14 @@ func (c *handlerTemplate) Needed(ctx context.Context, info *callbacks.RunInfo, t
 		return false
 	}
 
+	if !c.hasHandler(info.Component) {
+		if c.fallbackHandler == nil {
+			return false
+		}
+		checker, ok := c.fallbackHandler.(callbacks.TimingChecker)
+		return !ok || checker.Needed(ctx, info, timing)
+	}
+
 	switch info.Component {
 	case components.ComponentOfChatModel:
 		if c.chatModelHandler != nil && c.chatModelHandler.Needed(ctx, info, timing) {
@@ -326,7 +420,8 @@ func (c *handlerTemplate) Needed(ctx context.Context, info *callbacks.RunInfo, t
 		}
 	case compose.ComponentOfGraph,
 		compose.ComponentOfChain,
-		compose.ComponentOfLambda:
+		compose.ComponentOfLambda,
+		compose.ComponentOfWorkflow:
 		handler := c.composeTemplates[info.Component]
 		if handler != nil {
 			checker, ok := handler.(callbacks.TimingChecker)
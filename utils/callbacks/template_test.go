@@ -288,3 +288,38 @@ func TestNewComponentTemplate(t *testing.T) {
 		assert.Equal(t, 30, cnt)
 	})
 }
+
+func TestHandlerHelperFallback(t *testing.T) {
+	ctx := context.Background()
+
+	var fallbackComponents, workflowComponents []components.Component
+	handler := NewHandlerHelper().
+		Prompt(&PromptCallbackHandler{
+			OnStart: func(ctx context.Context, runInfo *callbacks.RunInfo, input *prompt.CallbackInput) context.Context {
+				return ctx
+			},
+		}).
+		Workflow(callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			workflowComponents = append(workflowComponents, info.Component)
+			return ctx
+		}).Build()).
+		Fallback(callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			fallbackComponents = append(fallbackComponents, info.Component)
+			return ctx
+		}).Build()).
+		Handler()
+
+	checker := handler.(callbacks.TimingChecker)
+	for _, c := range []components.Component{components.ComponentOfChatModel, "Custom", compose.ComponentOfWorkflow, components.ComponentOfPrompt} {
+		info := &callbacks.RunInfo{Component: c}
+		assert.True(t, checker.Needed(ctx, info, callbacks.TimingOnStart), c)
+		handler.OnStart(ctx, info, nil)
+	}
+	assert.Equal(t, []components.Component{components.ComponentOfChatModel, "Custom"}, fallbackComponents)
+	assert.Equal(t, []components.Component{compose.ComponentOfWorkflow}, workflowComponents)
+	assert.False(t, checker.Needed(ctx, &callbacks.RunInfo{Component: "Custom"}, callbacks.TimingOnEnd))
+
+	handler = NewHandlerHelper().Handler()
+	assert.False(t, handler.(callbacks.TimingChecker).Needed(ctx, &callbacks.RunInfo{Component: "Custom"}, callbacks.TimingOnStart))
+	assert.Equal(t, ctx, handler.OnStart(ctx, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, nil))
+}